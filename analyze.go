@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"ndsemu/memdump"
+	"os"
+	"strconv"
+)
+
+// runAnalyze implements the "analyze" subcommand, which scans memory dumps
+// for strings and pointer tables (see package memdump):
+//
+//	ndsemu analyze [-base ADDR] [-json] ram.dump...
+//
+// The same analysis can be run on the live main RAM through the control
+// socket (see ControlServer).
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	base := fs.String("base", "", "address at which the dump was taken (default: guessed from the size)")
+	minStr := fs.Int("min-string", memdump.DefaultOptions.MinStringLen, "minimum length of strings, in characters")
+	minTable := fs.Int("min-table", memdump.DefaultOptions.MinTableCount, "minimum number of entries of pointer tables")
+	asJSON := fs.Bool("json", false, "output the results as JSON")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: ndsemu analyze [options] DUMP...")
+	}
+
+	opts := memdump.Options{MinStringLen: *minStr, MinTableCount: *minTable}
+	for _, fn := range fs.Args() {
+		data, err := ioutil.ReadFile(fn)
+		if err != nil {
+			return err
+		}
+
+		addr, ok := memdump.DefaultBase(len(data))
+		if *base != "" {
+			v, err := strconv.ParseUint(*base, 0, 32)
+			if err != nil {
+				return fmt.Errorf("invalid base address: %v", err)
+			}
+			addr, ok = uint32(v), true
+		}
+		if !ok {
+			return fmt.Errorf("%s: cannot guess the base address from the size (%d bytes), use -base", fn, len(data))
+		}
+
+		rep := memdump.Analyze(data, addr, opts)
+		if *asJSON {
+			if err := json.NewEncoder(os.Stdout).Encode(rep); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Printf("== %s (base %08x, %d bytes)\n", fn, addr, len(data))
+		fmt.Printf("strings: %d\n", len(rep.Strings))
+		for _, s := range rep.Strings {
+			kind := "ascii"
+			if s.SJIS {
+				kind = "sjis"
+			}
+			fmt.Printf("  %08x %-5s %q\n", s.Addr, kind, s.Text)
+		}
+		fmt.Printf("pointer tables: %d\n", len(rep.Tables))
+		for _, t := range rep.Tables {
+			fmt.Printf("  %08x %4d entries -> %-5s", t.Addr, t.Count, t.Region)
+			if t.StringRefs > 0 {
+				fmt.Printf(" (%d to strings)", t.StringRefs)
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
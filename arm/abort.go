@@ -0,0 +1,50 @@
+package arm
+
+import (
+	"ndsemu/emu"
+	log "ndsemu/emu/logger"
+)
+
+// BusError is called by the bus when it rejects an access (see
+// hwio.Table.Abort): if the access was performed by the CPU, this raises a
+// data abort. Accesses performed by other masters of the bus (eg: DMA) are
+// ignored, both between the CPU accesses and during them (eg: a DMA started
+// by a CPU write to its control register).
+func (cpu *Cpu) BusError(addr uint32) {
+	if cpu.busActive && addr == cpu.busAddr {
+		cpu.dataAbort(addr)
+	}
+}
+
+// Signal a data abort for the memory access being performed by the current
+// opcode. Loads and stores don't update the destination and base registers
+// after an aborted access (like the ARM9 "base restored" abort model; LDM
+// still overwrites the registers loaded so far), and the data abort
+// exception is taken right after the opcode, with LR pointing 8 bytes after
+// it (so that the handler can retry it with SUBS PC, LR, #8).
+func (cpu *Cpu) dataAbort(addr uint32) {
+	if cpu.abort {
+		return
+	}
+	log.ModCpu.WithFields(log.Fields{
+		"pc":   cpu.GetPC(),
+		"addr": emu.Hex32(addr),
+	}).Warn("data abort")
+	cpu.abort = true
+	cpu.abortPc = cpu.pc
+	cpu.tightExit = true
+}
+
+func (cpu *Cpu) takeDataAbort() {
+	cpu.abort = false
+	// Discard a jump done by the aborted opcode (eg: LDM with PC)
+	cpu.pc = cpu.abortPc
+	cpu.Exception(ExceptionDataAbort)
+}
+
+// BKPT raises a prefetch abort, as if the opcode itself could not be
+// fetched: LR points 4 bytes after it.
+func (cpu *Cpu) bkpt() {
+	cpu.pc = cpu.GetPC()
+	cpu.Exception(ExceptionPrefetchAbort)
+}
@@ -0,0 +1,144 @@
+package arm
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// Test bus that rejects accesses above limit
+type abortTestBus struct {
+	testBus
+	cpu   *Cpu
+	limit uint32
+}
+
+func (b *abortTestBus) Read32(addr uint32) uint32 {
+	if addr >= b.limit {
+		b.cpu.BusError(addr)
+		return 0
+	}
+	return b.testBus.Read32(addr)
+}
+
+func (b *abortTestBus) Read16(addr uint32) uint16 {
+	if addr >= b.limit {
+		b.cpu.BusError(addr)
+		return 0
+	}
+	return b.testBus.Read16(addr)
+}
+
+func (b *abortTestBus) Write32(addr uint32, val uint32) {
+	if addr >= b.limit {
+		b.cpu.BusError(addr)
+		return
+	}
+	b.testBus.Write32(addr, val)
+}
+
+func TestDataAbort(t *testing.T) {
+	bus := &abortTestBus{testBus: *newTestBus([]uint32{
+		0xE3A01B06, // 00: mov r1, #0x1800
+		0xE5910000, // 04: ldr r0, [r1]
+		0xEAFFFFFE, // 08: b   08
+		0xEAFFFFFE, // 0C: b   0C (prefetch abort)
+		0xEAFFFFFE, // 10: b   10 (data abort)
+	}, nil, 0), limit: 0x1800}
+	cpu := NewCpu(ARMv5, bus)
+	bus.cpu = cpu
+	cpu.SetPC(0)
+	cpu.Run(100)
+
+	if cpu.Cpsr.GetMode() != CpuModeAbort || cpu.pc != 0x10 {
+		t.Fatalf("data abort not taken: mode=%v pc=%v", cpu.Cpsr.GetMode(), cpu.pc)
+	}
+	if lr := cpu.Regs[14]; lr != 0xC {
+		t.Errorf("lr=%v, want 0xC", lr)
+	}
+	if spsr := *cpu.RegSpsr(); CpuMode(spsr&0x1F) != CpuModeSupervisor {
+		t.Errorf("spsr=%v", spsr)
+	}
+
+	// Bus errors for accesses performed by other masters are ignored
+	cpu = NewCpu(ARMv5, bus)
+	cpu.BusError(0x1800)
+	if cpu.abort {
+		t.Errorf("abort raised by an access not performed by the CPU")
+	}
+	cpu.Read32(0x100)
+	cpu.BusError(0x100)
+	if cpu.abort {
+		t.Errorf("abort raised by an access to the last address accessed by the CPU")
+	}
+}
+
+func TestBkpt(t *testing.T) {
+	// ARM: bkpt 0x1234
+	cpu := NewCpu(ARMv5, newTestBus(nil, nil, 0))
+	execArm(cpu, 0xE1212374)
+	if cpu.Cpsr.GetMode() != CpuModeAbort || cpu.pc != 0xC || cpu.Regs[14] != 0x104 {
+		t.Errorf("bkpt: mode=%v pc=%v lr=%v", cpu.Cpsr.GetMode(), cpu.pc, cpu.Regs[14])
+	}
+
+	// Thumb: bkpt 0x12
+	cpu = NewCpu(ARMv5, newTestBus(nil, nil, 0))
+	execThumb(cpu, 0xBE12)
+	if cpu.Cpsr.GetMode() != CpuModeAbort || cpu.Cpsr.T() || cpu.pc != 0xC || cpu.Regs[14] != 0x104 {
+		t.Errorf("thumb bkpt: mode=%v T=%v pc=%v lr=%v", cpu.Cpsr.GetMode(), cpu.Cpsr.T(), cpu.pc, cpu.Regs[14])
+	}
+
+	// Undefined on ARMv4
+	cpu = NewCpu(ARMv4, newTestBus(nil, nil, 0))
+	execThumb(cpu, 0xBE12)
+	if cpu.Cpsr.GetMode() != CpuModeUndefined {
+		t.Errorf("thumb bkpt on ARMv4: mode=%v", cpu.Cpsr.GetMode())
+	}
+}
+
+func TestDataAbortRegisters(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		op    uint32
+		thumb bool
+		sp    uint32
+		block bool // load multiple: only the base is preserved
+	}{
+		{"ldr", 0xE5B10004, false, 0, false},   // ldr   r0, [r1, #4]!
+		{"ldrh", 0xE0D100B4, false, 0, false},  // ldrh  r0, [r1], #4
+		{"ldrd", 0xE1E100D0, false, 0, false},  // ldrd  r0, [r1]!
+		{"str", 0xE5A10004, false, 0, false},   // str   r0, [r1, #4]!
+		{"ldm", 0xE8B10003, false, 0, true},    // ldmia r1!, {r0, r1}
+		{"stm", 0xE8A10001, false, 0, false},   // stmia r1!, {r0}
+		{"tldr", 0x6808, true, 0, false},       // ldr   r0, [r1]
+		{"tldm", 0xC903, true, 0, true},        // ldmia r1!, {r0, r1}
+		{"tpop", 0xBD01, true, 0x1800, true},   // pop   {r0, pc}
+		{"tpush", 0xB401, true, 0x1804, false}, // push  {r0}
+	} {
+		bus := &abortTestBus{testBus: *newTestBus(nil, nil, 0), limit: 0x1800}
+		binary.LittleEndian.PutUint32(bus.mem[0x10:], 0xEAFFFFFE) // b 10 (data abort)
+		if tt.thumb {
+			binary.LittleEndian.PutUint16(bus.mem[0x100:], uint16(tt.op))
+		} else {
+			binary.LittleEndian.PutUint32(bus.mem[0x100:], tt.op)
+		}
+		cpu := NewCpu(ARMv5, bus)
+		bus.cpu = cpu
+		cpu.Regs[0] = 0x1234
+		cpu.Regs[1] = 0x1800
+		cpu.Regs[13] = reg(tt.sp)
+		cpu.Cpsr.SetT(tt.thumb)
+		cpu.SetPC(0x100)
+		cpu.Run(10)
+
+		// The data abort handler runs in abort mode: check the registers
+		// of the interrupted mode
+		if cpu.Cpsr.GetMode() != CpuModeAbort {
+			t.Errorf("%s: data abort not taken", tt.name)
+			continue
+		}
+		cpu.Cpsr.SetMode(CpuModeSupervisor, cpu)
+		if (cpu.Regs[0] != 0x1234 && !tt.block) || cpu.Regs[1] != 0x1800 || cpu.Regs[13] != reg(tt.sp) {
+			t.Errorf("%s: registers modified: r0=%v r1=%v sp=%v", tt.name, cpu.Regs[0], cpu.Regs[1], cpu.Regs[13])
+		}
+	}
+}
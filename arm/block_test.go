@@ -0,0 +1,172 @@
+package arm
+
+import "testing"
+
+// Execute a single thumb opcode at address 0x100
+func execThumb(cpu *Cpu, op uint16) {
+	cpu.Cpsr.SetT(true)
+	cpu.SetPC(0x100)
+	cpu.Regs[15] = cpu.pc + 4
+	cpu.pc += 2
+	opThumbTable[op>>8](cpu, op)
+}
+
+func TestBlockTransferBaseInList(t *testing.T) {
+	tests := []struct {
+		name      string
+		thumb     bool
+		op        uint32
+		arch      Arch
+		r0, r1    reg    // values after the opcode
+		mem, mem4 uint32 // values at 0x800 and 0x804
+	}{
+		// ldmia r0!, {r0, r1}: base first in list
+		{"ldm-first", false, 0xE8B00003, ARMv4, 0x11, 0x22, 0x11, 0x22},
+		{"ldm-first", false, 0xE8B00003, ARMv5, 0x808, 0x22, 0x11, 0x22},
+		// ldmia r1!, {r0, r1}: base last in list
+		{"ldm-last", false, 0xE8B10003, ARMv4, 0x11, 0x22, 0x11, 0x22},
+		{"ldm-last", false, 0xE8B10003, ARMv5, 0x11, 0x22, 0x11, 0x22},
+		// ldmia r0!, {r0}: base only register
+		{"ldm-only", false, 0xE8B00001, ARMv4, 0x11, 0x800, 0x11, 0x22},
+		{"ldm-only", false, 0xE8B00001, ARMv5, 0x804, 0x800, 0x11, 0x22},
+		// stmia r1!, {r0, r1}: base not first
+		{"stm-notfirst", false, 0xE8A10003, ARMv4, 0x800, 0x808, 0x800, 0x808},
+		{"stm-notfirst", false, 0xE8A10003, ARMv5, 0x800, 0x808, 0x800, 0x800},
+		// stmia r0!, {r0, r1}: base first
+		{"stm-first", false, 0xE8A00003, ARMv4, 0x808, 0x800, 0x800, 0x800},
+		{"stm-first", false, 0xE8A00003, ARMv5, 0x808, 0x800, 0x800, 0x800},
+
+		// Thumb: ldmia r0!, {r0, r1}
+		{"thumb-ldm-first", true, 0xC803, ARMv4, 0x11, 0x22, 0x11, 0x22},
+		{"thumb-ldm-first", true, 0xC803, ARMv5, 0x808, 0x22, 0x11, 0x22},
+		// Thumb: ldmia r1!, {r0, r1}
+		{"thumb-ldm-last", true, 0xC903, ARMv5, 0x11, 0x22, 0x11, 0x22},
+		// Thumb: stmia r1!, {r0, r1}
+		{"thumb-stm-notfirst", true, 0xC103, ARMv4, 0x800, 0x808, 0x800, 0x808},
+		{"thumb-stm-notfirst", true, 0xC103, ARMv5, 0x800, 0x808, 0x800, 0x800},
+	}
+
+	for _, tt := range tests {
+		bus := newTestBus(nil, nil, 0)
+		bus.Write32(0x800, 0x11)
+		bus.Write32(0x804, 0x22)
+		cpu := NewCpu(tt.arch, bus)
+		cpu.Regs[0], cpu.Regs[1] = 0x800, 0x800
+		if tt.thumb {
+			execThumb(cpu, uint16(tt.op))
+		} else {
+			execArm(cpu, tt.op)
+		}
+		if cpu.Regs[0] != tt.r0 || cpu.Regs[1] != tt.r1 {
+			t.Errorf("%s/%v: r0=%v r1=%v, want r0=%v r1=%v", tt.name, tt.arch, cpu.Regs[0], cpu.Regs[1], tt.r0, tt.r1)
+		}
+		if m, m4 := bus.Read32(0x800), bus.Read32(0x804); m != tt.mem || m4 != tt.mem4 {
+			t.Errorf("%s/%v: mem=%08x,%08x, want %08x,%08x", tt.name, tt.arch, m, m4, tt.mem, tt.mem4)
+		}
+	}
+}
+
+func TestBlockTransferEmptyList(t *testing.T) {
+	for _, arch := range []Arch{ARMv4, ARMv5} {
+		// ldmia r0!, {}
+		bus := newTestBus(nil, nil, 0)
+		bus.Write32(0x800, 0x400)
+		cpu := NewCpu(arch, bus)
+		cpu.Regs[0] = 0x800
+		execArm(cpu, 0xE8B00000)
+		if cpu.Regs[0] != 0x840 {
+			t.Errorf("ldm/%v: r0=%v, want 0x840", arch, cpu.Regs[0])
+		}
+		if wantpc := map[Arch]reg{ARMv4: 0x400, ARMv5: 0x104}[arch]; cpu.pc != wantpc {
+			t.Errorf("ldm/%v: pc=%v, want %v", arch, cpu.pc, wantpc)
+		}
+
+		// stmdb r0!, {}
+		bus = newTestBus(nil, nil, 0)
+		cpu = NewCpu(arch, bus)
+		cpu.Regs[0] = 0x840
+		execArm(cpu, 0xE9200000)
+		if cpu.Regs[0] != 0x800 {
+			t.Errorf("stm/%v: r0=%v, want 0x800", arch, cpu.Regs[0])
+		}
+		if want := map[Arch]uint32{ARMv4: 0x10C, ARMv5: 0}[arch]; bus.Read32(0x800) != want {
+			t.Errorf("stm/%v: stored %08x, want %08x", arch, bus.Read32(0x800), want)
+		}
+
+		// Thumb: ldmia r0!, {}
+		bus = newTestBus(nil, nil, 0)
+		bus.Write32(0x800, 0x401)
+		cpu = NewCpu(arch, bus)
+		cpu.Regs[0] = 0x800
+		execThumb(cpu, 0xC800)
+		if cpu.Regs[0] != 0x840 {
+			t.Errorf("thumb-ldm/%v: r0=%v, want 0x840", arch, cpu.Regs[0])
+		}
+		if wantpc := map[Arch]reg{ARMv4: 0x400, ARMv5: 0x102}[arch]; cpu.pc != wantpc {
+			t.Errorf("thumb-ldm/%v: pc=%v, want %v", arch, cpu.pc, wantpc)
+		}
+
+		// Thumb: stmia r0!, {}
+		bus = newTestBus(nil, nil, 0)
+		cpu = NewCpu(arch, bus)
+		cpu.Regs[0] = 0x800
+		execThumb(cpu, 0xC000)
+		if cpu.Regs[0] != 0x840 {
+			t.Errorf("thumb-stm/%v: r0=%v, want 0x840", arch, cpu.Regs[0])
+		}
+		if want := map[Arch]uint32{ARMv4: 0x106, ARMv5: 0}[arch]; bus.Read32(0x800) != want {
+			t.Errorf("thumb-stm/%v: stored %08x, want %08x", arch, bus.Read32(0x800), want)
+		}
+	}
+}
+
+func TestBlockTransferUserBank(t *testing.T) {
+	bus := newTestBus(nil, nil, 0)
+	cpu := NewCpu(ARMv5, bus)
+	cpu.Cpsr.SetMode(CpuModeSystem, cpu)
+	cpu.Regs[13] = 0x1234
+	cpu.Cpsr.SetMode(CpuModeIrq, cpu)
+	cpu.Regs[13] = 0x1000
+	*cpu.RegSpsr() = 0x1F // system mode
+	cpu.Regs[0] = 0x800
+
+	// stmia r0, {sp}^
+	execArm(cpu, 0xE8C02000)
+	if v := bus.Read32(0x800); v != 0x1234 {
+		t.Errorf("stm^: stored %08x, want user sp", v)
+	}
+
+	// ldmia r0, {sp}^
+	bus.Write32(0x800, 0x5678)
+	execArm(cpu, 0xE8D02000)
+	if cpu.Regs[13] != 0x1000 || cpu.UsrBank[0] != 0x5678 {
+		t.Errorf("ldm^: sp=%v user sp=%v", cpu.Regs[13], cpu.UsrBank[0])
+	}
+	if cpu.Cpsr.GetMode() != CpuModeIrq {
+		t.Errorf("ldm^: mode=%v, want irq", cpu.Cpsr.GetMode())
+	}
+
+	// ldmia sp!, {pc}^: the writeback goes to the IRQ bank
+	bus.Write32(0x1000, 0x400)
+	execArm(cpu, 0xE8FD8000)
+	if cpu.Cpsr.GetMode() != CpuModeSystem || cpu.pc != 0x400 {
+		t.Errorf("ldm^ pc: mode=%v pc=%v", cpu.Cpsr.GetMode(), cpu.pc)
+	}
+	if cpu.Regs[13] != 0x5678 || cpu.IrqBank[0] != 0x1004 {
+		t.Errorf("ldm^ pc: sp=%v irq sp=%v", cpu.Regs[13], cpu.IrqBank[0])
+	}
+}
+
+func TestLdmInterworking(t *testing.T) {
+	// ldmia r0, {pc}: only ARMv5 switches to thumb
+	for _, arch := range []Arch{ARMv4, ARMv5} {
+		bus := newTestBus(nil, nil, 0)
+		bus.Write32(0x800, 0x401)
+		cpu := NewCpu(arch, bus)
+		cpu.Regs[0] = 0x800
+		execArm(cpu, 0xE8908000)
+		if cpu.Cpsr.T() != (arch == ARMv5) || cpu.pc != 0x400 {
+			t.Errorf("%v: T=%v pc=%v", arch, cpu.Cpsr.T(), cpu.pc)
+		}
+	}
+}
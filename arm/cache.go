@@ -0,0 +1,217 @@
+package arm
+
+// Emulation of the ARM946E-S caches and write buffer. Only the timings are
+// emulated: cache contents are not stored, so reads always return the
+// current contents of memory (even if a DMA or the other CPU modified it
+// behind the cache), and writes always reach memory. What is tracked is
+// which lines are present in each cache (and dirty, for the data cache), so
+// that hits, line fills and write-backs can be accounted for.
+//
+// Whether an access is cached or buffered depends on the protection unit
+// regions (CP15 C6) and their attributes (C2: cacheable, C3: bufferable).
+// Caches and write buffer are only active when the protection unit is
+// enabled.
+
+const (
+	cacheLineSize  = 32
+	cacheLineWords = cacheLineSize / 4
+	cacheWays      = 4
+)
+
+// cache tracks the lines present in a 4-way set-associative cache
+type cache struct {
+	sets   int
+	tags   [][cacheWays]uint32 // line address | cacheValid | cacheDirty
+	next   []uint8             // next way to replace (round-robin)
+	rand   uint32              // state of the pseudo-random replacement
+	rrobin bool
+}
+
+const (
+	cacheValid = 1 << 0
+	cacheDirty = 1 << 1
+)
+
+func newCache(size int) *cache {
+	sets := size / cacheLineSize / cacheWays
+	return &cache{
+		sets: sets,
+		tags: make([][cacheWays]uint32, sets),
+		next: make([]uint8, sets),
+		rand: 1,
+	}
+}
+
+func (c *cache) set(addr uint32) int {
+	return int(addr/cacheLineSize) % c.sets
+}
+
+// Return the way of the set containing the line, or -1
+func (c *cache) lookup(addr uint32) (set int, way int) {
+	set = c.set(addr)
+	line := addr &^ (cacheLineSize - 1)
+	for w := range c.tags[set] {
+		if t := c.tags[set][w]; t&cacheValid != 0 && t&^(cacheLineSize-1) == line {
+			return set, w
+		}
+	}
+	return set, -1
+}
+
+// Allocate the line containing addr, evicting one of the lines in its set.
+// Returns true if the evicted line was dirty.
+func (c *cache) fill(addr uint32) (way int, dirty bool) {
+	set := c.set(addr)
+	way = -1
+	for w := range c.tags[set] {
+		if c.tags[set][w]&cacheValid == 0 {
+			way = w
+			break
+		}
+	}
+	if way < 0 {
+		if c.rrobin {
+			way = int(c.next[set])
+			c.next[set] = uint8((way + 1) % cacheWays)
+		} else {
+			// xorshift, as a stand-in for the hardware random generator
+			c.rand ^= c.rand << 13
+			c.rand ^= c.rand >> 17
+			c.rand ^= c.rand << 5
+			way = int(c.rand % cacheWays)
+		}
+	}
+	dirty = c.tags[set][way]&(cacheValid|cacheDirty) == cacheValid|cacheDirty
+	c.tags[set][way] = addr&^(cacheLineSize-1) | cacheValid
+	return way, dirty
+}
+
+// Invalidate all lines
+func (c *cache) invalidateAll() {
+	for i := range c.tags {
+		c.tags[i] = [cacheWays]uint32{}
+	}
+}
+
+// Invalidate a line (if present); returns true if it was dirty. If clean is
+// true, the line is only cleaned (that is, written back) but stays valid.
+func (c *cache) invalidateLine(addr uint32, clean bool) (dirty bool) {
+	set, way := c.lookup(addr)
+	if way < 0 {
+		return false
+	}
+	return c.invalidateWay(set, way, clean)
+}
+
+func (c *cache) invalidateWay(set, way int, clean bool) (dirty bool) {
+	t := &c.tags[set][way]
+	dirty = *t&(cacheValid|cacheDirty) == cacheValid|cacheDirty
+	if clean {
+		*t &^= cacheDirty
+	} else {
+		*t = 0
+	}
+	return dirty
+}
+
+// Cycles to transfer a whole cache line to/from the bus
+func (c *Cp15) lineCycles(addr uint32) int64 {
+	if c.cpu.timings == nil {
+		return c.cpu.memCycles * cacheLineWords
+	}
+	t := &c.cpu.timings[addr>>24]
+	return int64(t.N32 + t.S32*(cacheLineWords-1))
+}
+
+// Return the cycles taken by a data access through the data cache or the
+// write buffer, or false if the access goes directly to the bus.
+func (c *Cp15) dataAccess(addr uint32, write bool) (int64, bool) {
+	if c.dcache == nil {
+		return 0, false
+	}
+	attr := c.pageAttrs(addr)
+	if attr&pageDCache != 0 && c.regControl.Bit(2) {
+		set, way := c.dcache.lookup(addr)
+		switch {
+		case way >= 0 && write && attr&pageBuffer != 0:
+			// Write-back: write hits only update the cache
+			c.dcache.tags[set][way] |= cacheDirty
+			return 1, true
+		case way >= 0 && !write:
+			return 1, true
+		case !write:
+			// Read miss: fill the line (writing back the evicted one)
+			cycles := c.lineCycles(addr) + 1
+			if _, dirty := c.dcache.fill(addr); dirty {
+				cycles += c.lineCycles(addr)
+			}
+			return cycles, true
+		}
+		// Write misses (no write-allocate) and write-through writes go
+		// through the write buffer, if bufferable, or to the bus.
+	}
+	if write && attr&pageBuffer != 0 {
+		// Write buffer: the CPU doesn't wait for the write to complete
+		// (the buffer is assumed to never be full)
+		return 1, true
+	}
+	return 0, false
+}
+
+// Return whether opcodes at the specified address are fetched through the
+// instruction cache.
+func (c *Cp15) icacheActive(addr uint32) bool {
+	return c.icache != nil && c.regControl.Bit(12) && c.pageAttrs(addr)&pageICache != 0
+}
+
+// Return the extra cycles taken to fetch the cache line containing the
+// opcode at addr: zero on a hit, the line fill on a miss.
+func (c *Cp15) icacheFetch(addr uint32) int64 {
+	if _, way := c.icache.lookup(addr); way >= 0 {
+		return 0
+	}
+	c.icache.fill(addr)
+	return c.lineCycles(addr)
+}
+
+// Cache maintenance operations (CP15 C7). Cleaning a dirty line charges
+// the cycles to write it back.
+func (c *Cp15) cacheOp(cm, cp uint32, value uint32) {
+	if c.icache == nil || c.dcache == nil {
+		return
+	}
+	var dirty bool
+	switch {
+	case cm == 5 && cp == 0:
+		c.icache.invalidateAll()
+	case cm == 5 && cp == 1:
+		c.icache.invalidateLine(value, false)
+	case cm == 13 && cp == 1:
+		// Prefetch instruction cache line
+		if _, way := c.icache.lookup(value); way < 0 {
+			c.icache.fill(value)
+			c.cpu.Clock += c.lineCycles(value)
+		}
+	case cm == 6 && cp == 0:
+		c.dcache.invalidateAll()
+	case cm == 6 && cp == 1:
+		c.dcache.invalidateLine(value, false)
+	case cm == 10 && cp == 1:
+		dirty = c.dcache.invalidateLine(value, true)
+	case cm == 14 && cp == 1:
+		dirty = c.dcache.invalidateLine(value, false)
+	case (cm == 10 || cm == 14) && cp == 2:
+		// Index format: set in bits 5+, way in bits 30-31
+		set := int(value/cacheLineSize) % c.dcache.sets
+		way := int(value >> 30)
+		value = c.dcache.tags[set][way] &^ (cacheLineSize - 1)
+		dirty = c.dcache.invalidateWay(set, way, cm == 10)
+	case cm == 10 && cp == 4:
+		// Drain write buffer: nothing to do, as it never holds data
+	default:
+		modCp15.WithField("pc", c.cpu.GetPC()).Warnf("unhandled cache operation C7,C%d,%d", cm, cp)
+	}
+	if dirty {
+		c.cpu.Clock += c.lineCycles(value)
+	}
+}
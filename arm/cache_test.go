@@ -0,0 +1,106 @@
+package arm
+
+import (
+	"ndsemu/emu"
+	"testing"
+)
+
+func newCacheTestCpu() (*Cpu, *Cp15) {
+	bus := &timedTestBus{testBus: *newTestBus(nil, nil, 0)}
+	for i := range bus.timings {
+		bus.timings[i] = emu.AccessTimings{N16: 1, S16: 1, N32: 1, S32: 1}
+	}
+	bus.timings[2] = emu.AccessTimings{N16: 8, S16: 1, N32: 9, S32: 2}
+	cpu := NewCpu(ARMv5, bus)
+	cp15 := cpu.EnableCp15()
+	cp15.ConfigureCaches(8*1024, 4*1024)
+
+	// Region 0: whole address space, cacheable, bufferable and fully accessible
+	cp15.Write(0, 6, 0, 0, 0x1F<<1|1)
+	cp15.Write(0, 2, 0, 0, 1)
+	cp15.Write(0, 2, 0, 1, 1)
+	cp15.Write(0, 3, 0, 0, 1)
+	cp15.Write(0, 5, 0, 2, 3)
+	cp15.Write(0, 5, 0, 3, 3)
+	// Enable protection unit, data cache and instruction cache
+	cp15.Write(0, 1, 0, 0, 0x2078|1<<0|1<<2|1<<12)
+	return cpu, cp15
+}
+
+func TestDataCache(t *testing.T) {
+	cpu, cp15 := newCacheTestCpu()
+	const fill = 9 + 7*2 // N + 7*S of the region
+
+	tests := []struct {
+		name   string
+		access func()
+		want   int64
+	}{
+		{"miss", func() { cpu.Read32(0x02000004) }, fill + 1},
+		{"hit", func() { cpu.Read32(0x02000000) }, 1},
+		{"hit-same-line", func() { cpu.Read16(0x0200001E) }, 1},
+		{"write-hit", func() { cpu.Write32(0x02000008, 0) }, 1},
+		{"write-miss", func() { cpu.Write32(0x02000100, 0) }, 1}, // write buffer
+		{"clean-dirty", func() { cp15.Write(0, 7, 10, 1, 0x02000000) }, fill},
+		{"clean-clean", func() { cp15.Write(0, 7, 10, 1, 0x02000000) }, 0},
+		{"still-hit", func() { cpu.Read32(0x02000000) }, 1},
+		{"invalidate", func() { cp15.Write(0, 7, 6, 0, 0) }, 0},
+		{"miss-again", func() { cpu.Read32(0x02000000) }, fill + 1},
+		{"disable", func() { cp15.Write(0, 1, 0, 0, 0x2078|1<<0|1<<12) }, 0},
+		{"uncached-read", func() { cpu.Read32(0x02000000) }, 9},
+		{"buffered-write", func() { cpu.Write32(0x02000000, 0) }, 1},
+		{"disable-pu", func() { cp15.Write(0, 1, 0, 0, 0x2078) }, 0},
+		{"unbuffered-write", func() { cpu.Write32(0x02000000, 0) }, 9},
+	}
+	for _, test := range tests {
+		start := cpu.Clock
+		test.access()
+		if got := cpu.Clock - start; got != test.want {
+			t.Errorf("%s: %d cycles, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDataCacheEviction(t *testing.T) {
+	cpu, _ := newCacheTestCpu()
+	const fill = 9 + 7*2
+
+	// 4KB cache, 4 ways: addresses 1KB apart share the same set. Make all
+	// lines of the set dirty, then load a fifth line: a dirty line must be
+	// written back.
+	for i := uint32(0); i < 4; i++ {
+		cpu.Read32(0x02000000 + i*1024)
+		cpu.Write32(0x02000000+i*1024, 0)
+	}
+	start := cpu.Clock
+	cpu.Read32(0x02000000 + 4*1024)
+	if got := cpu.Clock - start; got != 2*fill+1 {
+		t.Errorf("eviction: %d cycles, want %d", got, 2*fill+1)
+	}
+}
+
+func TestInstructionCache(t *testing.T) {
+	cpu, cp15 := newCacheTestCpu()
+	const fill = 9 + 7*2
+
+	cpu.SetPC(0x02000000)
+	if fetch, icache := cpu.fetchCycles(); fetch != 1 || !icache {
+		t.Errorf("cached code: fetch=%d icache=%v", fetch, icache)
+	}
+	if got := cp15.icacheFetch(0x02000000); got != fill {
+		t.Errorf("miss: %d cycles, want %d", got, fill)
+	}
+	if got := cp15.icacheFetch(0x0200001C); got != 0 {
+		t.Errorf("hit: %d cycles, want 0", got)
+	}
+	cp15.Write(0, 7, 5, 1, 0x02000000)
+	if got := cp15.icacheFetch(0x02000000); got != fill {
+		t.Errorf("miss after invalidate: %d cycles, want %d", got, fill)
+	}
+
+	// With the instruction cache disabled, code is fetched from the bus
+	cp15.Write(0, 1, 0, 0, 0x2078|1<<0|1<<2)
+	if fetch, icache := cpu.fetchCycles(); fetch != 2 || icache {
+		t.Errorf("uncached code: fetch=%d icache=%v", fetch, icache)
+	}
+}
@@ -0,0 +1,55 @@
+package arm
+
+import "testing"
+
+func TestCoprocessorMissing(t *testing.T) {
+	for _, op := range []uint32{
+		0xEE100F10, // mrc p15, 0, r0, c0, c0, 0
+		0xEE010F10, // mcr p15, 0, r0, c1, c0, 0
+		0xEE000500, // cdp p5, 0, c0, c0, c0, 0
+		0xED900500, // ldc p5, c0, [r0]
+	} {
+		cpu := NewCpu(ARMv4, newTestBus(nil, nil, 0))
+		cpu.Regs[0] = 0x1234
+		execArm(cpu, op)
+		if cpu.Cpsr.GetMode() != CpuModeUndefined {
+			t.Errorf("%08X: mode=%v, want undefined", op, cpu.Cpsr.GetMode())
+		}
+		if lr := cpu.UndBank[1]; lr != 0x104 {
+			t.Errorf("%08X: lr=%v, want 0x104", op, lr)
+		}
+		if cpu.Regs[0] != 0x1234 {
+			t.Errorf("%08X: r0 modified", op)
+		}
+	}
+}
+
+func TestCp14(t *testing.T) {
+	cpu := NewCpu(ARMv5, newTestBus(nil, nil, 0))
+	cpu.EnableCp15()
+	cpu.EnableCp14()
+
+	// mrc p14, 0, r0, c0, c0, 0 (DCC control: empty channel)
+	execArm(cpu, 0xEE100E10)
+	if cpu.Regs[0] != 3<<28 {
+		t.Errorf("DCC control: %v", cpu.Regs[0])
+	}
+
+	// mcr p14, 0, r0, c1, c0, 0 (DCC data write)
+	execArm(cpu, 0xEE010E10)
+	if cpu.Cpsr.GetMode() != CpuModeSupervisor {
+		t.Errorf("DCC write raised an exception")
+	}
+
+	// mrc p15, 0, r0, c0, c0, 0 (main ID)
+	execArm(cpu, 0xEE100F10)
+	if cpu.Regs[0] != 0x41059461 {
+		t.Errorf("CP15 main ID: %v", cpu.Regs[0])
+	}
+
+	// cdp p14, 0, c0, c0, c0, 0
+	execArm(cpu, 0xEE000E00)
+	if cpu.Cpsr.GetMode() != CpuModeUndefined {
+		t.Errorf("CDP on CP14 didn't raise the undefined exception")
+	}
+}
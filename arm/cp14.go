@@ -0,0 +1,61 @@
+package arm
+
+import (
+	"ndsemu/emu"
+	log "ndsemu/emu/logger"
+)
+
+var modCp14 = log.NewModule("cp14")
+
+// Cp14 is the debug coprocessor, that gives access to the debug
+// communications channel (DCC) of the EmbeddedICE unit: a pair of registers
+// used to exchange words with a JTAG debugger. No debugger is ever attached,
+// so the channel is always empty in read, and words written by the program
+// are just logged (some debug builds use it to print messages).
+type Cp14 struct {
+	cpu     *Cpu
+	version uint32
+}
+
+func newCp14(cpu *Cpu) *Cp14 {
+	c := &Cp14{cpu: cpu}
+	// EmbeddedICE version, reported in the DCC control register
+	if cpu.arch >= ARMv5 {
+		c.version = 3
+	} else {
+		c.version = 1
+	}
+	return c
+}
+
+func (c *Cp14) Read(op uint32, cn, cm, cp uint32) uint32 {
+	switch {
+	case op == 0 && cn == 0 && cm == 0 && cp == 0:
+		// DCC control: bit 0 is set when the read register is full (never),
+		// bit 1 when the write register is full (never, as the debugger
+		// would immediately drain it).
+		return c.version << 28
+	case op == 0 && cn == 1 && cm == 0 && cp == 0:
+		// DCC data read
+		modCp14.WithField("pc", c.cpu.GetPC()).Warn("read from empty DCC")
+		return 0
+	default:
+		modCp14.WithField("pc", c.cpu.GetPC()).Warnf("unhandled read C%d,C%d,%d (op=%d)", cn, cm, cp, op)
+		return 0
+	}
+}
+
+func (c *Cp14) Write(op uint32, cn, cm, cp uint32, value uint32) {
+	switch {
+	case op == 0 && cn == 1 && cm == 0 && cp == 0:
+		// DCC data write
+		modCp14.WithField("val", emu.Hex32(value)).WithField("pc", c.cpu.GetPC()).Info("DCC write")
+	default:
+		modCp14.WithField("pc", c.cpu.GetPC()).Warnf("unhandled write C%d,C%d,%d (op=%d) = %08x", cn, cm, cp, op, value)
+	}
+}
+
+// CDP is not supported by the debug coprocessor
+func (c *Cp14) Exec(op uint32, cn, cm, cp, cd uint32) {
+	c.cpu.Exception(ExceptionUndefined)
+}
@@ -24,6 +24,31 @@ type Cp15 struct {
 	itcmEnd   uint32
 	dtcmBegin uint32
 	dtcmEnd   uint32
+
+	// Protection unit regions and their cache/write buffer attributes
+	regRegion     [8]reg
+	regDCacheable reg
+	regICacheable reg
+	regBufferable reg
+
+	// Access permissions of the regions, in the extended format (4 bits
+	// per region)
+	regDataPerm  reg
+	regInstrPerm reg
+
+	// Caches (nil if not configured, see ConfigureCaches)
+	icache *cache
+	dcache *cache
+
+	// Protection unit: region covering each 4KB page, and attributes of
+	// each region (see updatePageAttrs)
+	puEnabled   bool
+	pages       [1 << (32 - puPageShift)]uint8
+	regionAttrs [puNoRegion + 1]uint16
+	attrsDirty  bool
+
+	// Called on a wait for interrupt (see SetHaltHook)
+	haltHook func()
 }
 
 // updateTcmConfig() recalculates the variables xtcmBegin/xtcmEnd, used by
@@ -87,9 +112,31 @@ func (c *Cp15) Read(op uint32, cn, cm, cp uint32) uint32 {
 	}
 
 	switch {
+	case cn == 0 && cm == 0 && cp == 0:
+		// Main ID: ARM946E-S
+		return 0x41059461
+	case cn == 0 && cm == 0 && cp == 1:
+		// Cache type: 8KB icache, 4KB dcache (4-way, 32-byte lines)
+		return 0x0F0D2112
 	case cn == 1 && cm == 0 && cp == 0:
 		// modCp15.WithField("val", c.regControl).WithField("pc", c.cpu.GetPC()).Info("read control reg")
 		return uint32(c.regControl)
+	case cn == 2 && cm == 0 && cp == 0:
+		return uint32(c.regDCacheable)
+	case cn == 2 && cm == 0 && cp == 1:
+		return uint32(c.regICacheable)
+	case cn == 3 && cm == 0 && cp == 0:
+		return uint32(c.regBufferable)
+	case cn == 5 && cm == 0 && cp == 0:
+		return permToLegacy(uint32(c.regDataPerm))
+	case cn == 5 && cm == 0 && cp == 1:
+		return permToLegacy(uint32(c.regInstrPerm))
+	case cn == 5 && cm == 0 && cp == 2:
+		return uint32(c.regDataPerm)
+	case cn == 5 && cm == 0 && cp == 3:
+		return uint32(c.regInstrPerm)
+	case cn == 6 && cp == 0:
+		return uint32(c.regRegion[cm&7])
 	case cn == 9 && cm == 1 && cp == 0:
 		// modCp15.WithField("val", c.regDtcmVsize).WithField("pc", c.cpu.GetPC()).Info("read DTCM size")
 		return uint32(c.regDtcmVsize)
@@ -137,6 +184,33 @@ func (c *Cp15) Write(op uint32, cn, cm, cp uint32, value uint32) {
 			modCp15.Info("Disabled DTCM")
 		}
 		c.updateTcmConfig()
+		c.puEnabled = c.regControl.Bit(0)
+		c.puChanged()
+		if c.icache != nil {
+			c.icache.rrobin = c.regControl.Bit(14)
+			c.dcache.rrobin = c.regControl.Bit(14)
+		}
+	case cn == 2 && cm == 0 && cp == 0:
+		c.regDCacheable = reg(value & 0xFF)
+		c.puChanged()
+	case cn == 2 && cm == 0 && cp == 1:
+		c.regICacheable = reg(value & 0xFF)
+		c.puChanged()
+	case cn == 3 && cm == 0 && cp == 0:
+		c.regBufferable = reg(value & 0xFF)
+		c.puChanged()
+	case cn == 5 && cm == 0 && cp == 0:
+		c.regDataPerm = reg(permFromLegacy(value))
+		c.puChanged()
+	case cn == 5 && cm == 0 && cp == 1:
+		c.regInstrPerm = reg(permFromLegacy(value))
+		c.puChanged()
+	case cn == 5 && cm == 0 && cp == 2:
+		c.regDataPerm = reg(value)
+		c.puChanged()
+	case cn == 5 && cm == 0 && cp == 3:
+		c.regInstrPerm = reg(value)
+		c.puChanged()
 	case cn == 9 && cm == 1 && cp == 0:
 		c.regDtcmVsize = reg(value)
 		c.updateTcmConfig()
@@ -146,7 +220,9 @@ func (c *Cp15) Write(op uint32, cn, cm, cp uint32, value uint32) {
 		c.updateTcmConfig()
 		modCp15.WithField("val", c.regItcmVsize).WithField("pc", c.cpu.GetPC()).Info("write ITCM size")
 
-	case cn == 6:
+	case cn == 6 && cp == 0:
+		c.regRegion[cm&7] = reg(value)
+		c.puChanged()
 		modCp15.WithFields(log.Fields{
 			"pc":     c.cpu.GetPC(),
 			"region": cm,
@@ -159,9 +235,14 @@ func (c *Cp15) Write(op uint32, cn, cm, cp uint32, value uint32) {
 		if (cm == 0 && cp == 4) || (cm == 8 && cp == 2) {
 			// Halt processor (wait for interrupt
 			modCp15.WithField("pc", c.cpu.GetPC()).Info("halt cpu")
-			c.cpu.SetLine(LineHalt, true)
+			if c.haltHook != nil {
+				c.haltHook()
+			} else {
+				c.cpu.SetLine(LineHalt, true)
+			}
+			return
 		}
-		// anything else is a cache command, ignore
+		c.cacheOp(cm, cp, value)
 
 	default:
 		modCp15.WithField("pc", c.cpu.GetPC()).Warnf("unhandled write C%d,C%d,%d = %08x", cn, cm, cp, value)
@@ -169,6 +250,14 @@ func (c *Cp15) Write(op uint32, cn, cm, cp uint32, value uint32) {
 	}
 }
 
+// Install a function that is called to halt the CPU on a wait for interrupt,
+// in place of setting the halt line directly. This lets the interrupt
+// controller decide whether the CPU halts at all (eg: not if an enabled
+// interrupt is already pending).
+func (c *Cp15) SetHaltHook(hook func()) {
+	c.haltHook = hook
+}
+
 func (c *Cp15) Exec(op uint32, cn, cm, cp, cd uint32) {
 	modCp15.WithField("op", op).WithField("pc", c.cpu.GetPC()).Error("invalid op in exec")
 	return
@@ -196,17 +285,27 @@ func (c *Cp15) ConfigureTcm(itcmSize int, dtcmSize int) {
 	c.dtcmSizeMask = uint32(dtcmSize - 1)
 }
 
+// ConfigureCaches activates emulation of the instruction and data caches,
+// with the specified sizes (in bytes). Only their timings are emulated (see
+// cache).
+func (c *Cp15) ConfigureCaches(icacheSize int, dcacheSize int) {
+	c.icache = newCache(icacheSize)
+	c.dcache = newCache(dcacheSize)
+}
+
 // Configure the CP15 Control Register. Value is the initial value of the register,
 // while rwmask specifies which bits can be modified at runtime, and which bits
 // are fixed.
 func (c *Cp15) ConfigureControlReg(value uint32, rwmask uint32) {
 	c.regControl = reg(value)
 	c.regControlRwMask = rwmask
+	c.puEnabled = c.regControl.Bit(0)
 }
 
 func newCp15(cpu *Cpu) *Cp15 {
 	return &Cp15{
 		cpu:              cpu,
 		regControlRwMask: 0xFFFFFFFF,
+		attrsDirty:       true,
 	}
 }
@@ -45,9 +45,15 @@ type Cpu struct {
 	cops  [16]Coprocessor
 	lines Line
 
+	// Cache of decoded opcodes (nil with DispatchInterp)
+	dcache *decodeCache
+
 	// Optional HLE implementation of SWIs
 	swiHle [256]func(cpu *Cpu) int64
 
+	// Optional hook called when an IRQ is delivered (see SetIrqHook)
+	irqHook func(pc uint32)
+
 	// Store the previous PC, used for debugging (eg: jumping into nowhere)
 	prevpc reg
 
@@ -56,6 +62,25 @@ type Cpu struct {
 	targetCycles int64
 	tightExit    bool
 
+	// Timings of the accesses to the external bus, if the bus provides them
+	// (see emu.TimedBus); otherwise, all accesses take memCycles.
+	timings *[256]emu.AccessTimings
+
+	// Address and time at which the next data access would be sequential
+	// (see busCycles), and whether the pipeline must be refilled after a
+	// branch (see fetchCycles)
+	seqAddr  uint32
+	seqClock int64
+	refill   bool
+
+	// Address of the last access to the external bus, and whether it is
+	// still in flight; data abort signaled by the current opcode (see
+	// dataAbort)
+	busAddr   uint32
+	busActive bool
+	abort     bool
+	abortPc   reg
+
 	// manual tracing support
 	DebugTrace int
 	dbg        debugger.CpuDebugger
@@ -65,6 +90,9 @@ func NewCpu(arch Arch, bus emu.Bus) *Cpu {
 	cpu := &Cpu{bus: bus, arch: arch}
 	cpu.Cpsr.r = 0x13 // mode supervisor
 	cpu.memCycles = int64(bus.WaitStates() + 1)
+	if tb, ok := bus.(emu.TimedBus); ok {
+		cpu.timings = tb.RegionTimings()
+	}
 	return cpu
 }
 
@@ -122,6 +150,12 @@ func (cpu *Cpu) MapCoprocessor(copnum int, cop Coprocessor) {
 	cpu.cops[copnum] = cop
 }
 
+func (cpu *Cpu) EnableCp14() *Cp14 {
+	cp14 := newCp14(cpu)
+	cpu.cops[14] = cp14
+	return cp14
+}
+
 func (cpu *Cpu) EnableCp15() *Cp15 {
 	cpu.cp15 = newCp15(cpu)
 	cpu.cops[15] = cpu.cp15
@@ -153,11 +187,15 @@ var excMode = [8]CpuMode{
 	CpuModeFiq,
 }
 
+// Offset added to pc to compute the return address (LR) of an exception.
+// Undefined, SWI and data abort are raised while executing the opcode, when
+// pc already points to the next one; prefetch abort and interrupts are
+// raised before executing the opcode pc points to.
 var excPcOffsetArm = [8]uint32{
-	0, 4, 0, 4, 8, 4, 4, 4,
+	0, 0, 0, 4, 4, 4, 4, 4,
 }
 var excPcOffsetThumb = [8]uint32{
-	0, 2, 0, 4, 6, 2, 4, 4,
+	0, 0, 0, 4, 6, 2, 4, 4,
 }
 
 func (cpu *Cpu) Exception(exc Exception) {
@@ -186,6 +224,9 @@ func (cpu *Cpu) Exception(exc Exception) {
 	} else {
 		log.ModCpu.Infof("Exception: exc=%v, LR=%v, arch=%v", exc, pc, cpu.arch)
 	}
+	if exc == ExceptionIrq && cpu.irqHook != nil {
+		cpu.irqHook(uint32(cpu.pc))
+	}
 
 	*cpu.RegSpsrForMode(newmode) = cpu.Cpsr.r
 	*cpu.RegF14ForMode(newmode) = pc
@@ -228,6 +269,37 @@ func (cpu *Cpu) SetSwiHle(swi uint8, hle func(cpu *Cpu) int64) {
 	cpu.swiHle[swi] = hle
 }
 
+// Execute again the SWI being emulated, after the HLE function returns.
+// This can be used by HLE functions that wait for an event: they halt
+// the CPU and restart the SWI, so that the condition is checked again
+// when the CPU is woken up (and after the IRQ has been serviced).
+func (cpu *Cpu) RestartSwi() {
+	cpu.branch(cpu.GetPC(), BranchJump)
+}
+
+// Install a function that is called every time an IRQ is delivered (that
+// is, the CPU is about to jump to the IRQ vector), with the address of the
+// instruction that was interrupted. This can be used to trace interrupts.
+func (cpu *Cpu) SetIrqHook(hook func(pc uint32)) {
+	cpu.irqHook = hook
+}
+
+// Return the value left on the data bus by the last opcode prefetch, that
+// is the opcode at R15 (PC+8 in ARM state, PC+4 in Thumb state, where the
+// halfword is duplicated). This is what reads from unmapped memory return
+// on CPUs without bus pull-downs.
+func (cpu *Cpu) OpenBus() uint32 {
+	mem := cpu.opFetchPointer(uint32(cpu.Regs[15]))
+	if len(mem) < 4 {
+		return 0
+	}
+	if cpu.Cpsr.T() {
+		op := uint32(emu.Read16LE(mem))
+		return op | op<<16
+	}
+	return emu.Read32LE(mem)
+}
+
 // Set the status of the external (virtual) lines. This is modeled
 // to resemble the physical lines of the CPU core, but without the
 // need of full fidelity to high/low signals or clocking.
@@ -237,6 +309,14 @@ func (cpu *Cpu) SetSwiHle(swi uint8, hle func(cpu *Cpu) int64) {
 // high/low signal required by the core).
 func (cpu *Cpu) SetLine(line Line, val bool) {
 	if val {
+		// HALT is a no-op if an interrupt is already pending, as the
+		// wakeup condition is immediately satisfied
+		if line&LineHalt != 0 && cpu.lines&(LineFiq|LineIrq) != 0 {
+			line &^= LineHalt
+			if line == 0 {
+				return
+			}
+		}
 		// Any activation of new lines must be checked immediately,
 		// so we need to exit from the tight loop where the lines are ignored.
 		if cpu.lines^line != 0 {
@@ -253,6 +333,11 @@ func (cpu *Cpu) SetLine(line Line, val bool) {
 	}
 }
 
+// Halted returns true if the CPU is halted, waiting for an interrupt.
+func (cpu *Cpu) Halted() bool {
+	return cpu.lines&LineHalt != 0
+}
+
 func (cpu *Cpu) Reset() {
 	cpu.Exception(ExceptionReset)
 }
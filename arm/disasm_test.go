@@ -0,0 +1,69 @@
+package arm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisasmArm(t *testing.T) {
+	var cpu Cpu
+	cpu.Cpsr.r = reg(CpuModeSupervisor)
+
+	const pc = 0x02000000
+	tests := []struct {
+		op   uint32
+		want string
+	}{
+		// ALU
+		{0xE0810002, "add r0, r1, r2"},
+		{0xE1B00102, "movs r0, r2 lsl #2"},
+		{0xE3A004FF, "mov r0, #0xff000000"},
+
+		// Multiply, including ARMv5TE DSP
+		{0xE0210293, "mla r1, r3, r2, r0"},
+		{0xE0E21293, "smlal r1, r2, r3, r2"},
+		{0xE10010A0, "smlatb r0, r0, r0, r1"},
+		{0xE12010E0, "smulwt r0, r0, r0"},
+		{0xE1421384, "smlalbb r1, r2, r4, r3"},
+		{0xE1021053, "qadd r1, r3, r2"},
+		{0xE1621053, "qdsub r1, r3, r2"},
+
+		// Misc ARMv5
+		{0xE16F1F12, "clz r1, r2"},
+		{0xE12FFF31, "blx r1"},
+		{0xE1201273, "bkpt #0x123"},
+		{0xFB000010, "blx 200004a"},
+
+		// PSR transfer
+		{0xE14F0000, "mrs r0, spsr_svc"},
+		{0xE328F20F, "msr cpsr_f, #0xf0000000"},
+
+		// Memory
+		{0xE5B10004, "ldr r0, [r1, #0x4]!"},
+		{0xE5110004, "ldr r0, [r1, #-0x4]"},
+		{0xE4110004, "ldr r0, [r1], #-0x4"},
+		{0xE7110102, "ldr r0, [r1, -r2 lsl #2]"},
+		{0xE4F10004, "ldrbt r0, [r1], #0x4"},
+		{0xE1C100F8, "strd r0, [r1, #0x8]"},
+		{0xF5D1F020, "pld [r1, #0x20]"},
+		{0xE8BD8010, "ldm sp!, {r4, pc}"},
+		{0xE9F18000, "ldmib r1!, {pc}^"},
+
+		// Coprocessor
+		{0xEE110F10, "mrc p15, #0, r0, c1, c0, #0"},
+		{0xFE010F10, "mcr2 p15, #0, r0, c1, c0, #0"},
+		{0xED310502, "ldc p5, c0, [r1, #-0x8]!"},
+		{0xFCD10502, "ldc2l p5, c0, [r1], #8"},
+		{0xEC510500, "mrrc p5, #0, r0, r1, c0"},
+
+		// Undefined
+		{0xE7F000F0, "dw e7f000f0"},
+	}
+
+	for _, test := range tests {
+		got := disasmArmTable[((test.op>>16)&0xFF0)|((test.op>>4)&0xF)](&cpu, test.op, pc)
+		if got = strings.Join(strings.Fields(got), " "); got != test.want {
+			t.Errorf("%08x: got %q, want %q", test.op, got, test.want)
+		}
+	}
+}
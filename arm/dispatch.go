@@ -0,0 +1,216 @@
+package arm
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// A Dispatch selects how the CPU dispatches opcodes to their handlers.
+// Both give the same results, down to the cycle count; they only differ in
+// speed.
+type Dispatch int
+
+const (
+	// DispatchInterp decodes each opcode every time it is executed.
+	DispatchInterp Dispatch = iota
+
+	// DispatchCached keeps a cache of decoded opcodes (see decodeCache), so
+	// that code that is executed repeatedly (loops, hot functions) is
+	// decoded only once.
+	DispatchCached
+)
+
+// SetDispatch selects the opcode dispatch method (DispatchInterp by
+// default). It must be called while the CPU is not running.
+func (cpu *Cpu) SetDispatch(d Dispatch) {
+	switch d {
+	case DispatchInterp:
+		cpu.dcache = nil
+	case DispatchCached:
+		if cpu.dcache == nil {
+			cpu.dcache = newDecodeCache()
+		}
+	default:
+		panic("invalid dispatch method")
+	}
+}
+
+// Size in bytes of the memory covered by a block of decoded opcodes
+const decodeBlockSize = 256
+
+type armDecoded struct {
+	op uint32
+	fn func(*Cpu, uint32)
+}
+
+type thumbDecoded struct {
+	op uint16
+	fn func(*Cpu, uint16)
+}
+
+type armBlock [decodeBlockSize / 4]armDecoded
+type thumbBlock [decodeBlockSize / 2]thumbDecoded
+
+// New blocks are filled with the decoding of opcode 0, rather than left
+// empty: this way, an entry is valid as soon as its opcode matches the one
+// in memory, and the tight loop doesn't need to check for missing handlers.
+func newArmBlock() *armBlock {
+	blk := new(armBlock)
+	for i := range blk {
+		blk[i].fn = opArmTable[0]
+	}
+	return blk
+}
+
+func newThumbBlock() *thumbBlock {
+	blk := new(thumbBlock)
+	for i := range blk {
+		blk[i].fn = opThumbTable[0]
+	}
+	return blk
+}
+
+// decodeCache holds the decoded opcodes, grouped in blocks. Blocks are keyed
+// by the host address of the memory the opcodes are fetched from (that is,
+// the physical memory), so that the cache is not affected by changes to the
+// memory map (eg: VRAM/WRAM banks, or TCM being moved).
+//
+// Each entry stores the opcode it was decoded from, which is checked against
+// memory before executing it. This means that stale entries are never
+// executed, whoever modified the code (the CPU itself, the other CPU or a
+// DMA), without having to track writes on all the memory paths.
+//
+// Looking up blocks in the maps is too slow to be done on every branch, so
+// the most recently used blocks are also kept in small direct-mapped tables.
+type decodeCache struct {
+	arm   map[uintptr]*armBlock
+	thumb map[uintptr]*thumbBlock
+
+	armRecent [decodeRecentSize]struct {
+		addr uintptr
+		blk  *armBlock
+	}
+	thumbRecent [decodeRecentSize]struct {
+		addr uintptr
+		blk  *thumbBlock
+	}
+}
+
+const decodeRecentSize = 64
+
+func newDecodeCache() *decodeCache {
+	return &decodeCache{
+		arm:   make(map[uintptr]*armBlock),
+		thumb: make(map[uintptr]*thumbBlock),
+	}
+}
+
+func (dc *decodeCache) armBlock(addr uintptr) *armBlock {
+	r := &dc.armRecent[(addr/decodeBlockSize)%decodeRecentSize]
+	if r.addr == addr && r.blk != nil {
+		return r.blk
+	}
+	blk := dc.arm[addr]
+	if blk == nil {
+		blk = newArmBlock()
+		dc.arm[addr] = blk
+	}
+	r.addr, r.blk = addr, blk
+	return blk
+}
+
+func (dc *decodeCache) thumbBlock(addr uintptr) *thumbBlock {
+	r := &dc.thumbRecent[(addr/decodeBlockSize)%decodeRecentSize]
+	if r.addr == addr && r.blk != nil {
+		return r.blk
+	}
+	blk := dc.thumb[addr]
+	if blk == nil {
+		blk = newThumbBlock()
+		dc.thumb[addr] = blk
+	}
+	r.addr, r.blk = addr, blk
+	return blk
+}
+
+// Tight loop of the cached dispatcher: this is the equivalent of the tight
+// loop in Run(), with the table lookup replaced by the decode cache. The
+// exit conditions are the same.
+func (cpu *Cpu) runCached(mem []uint8, trace func(uint32), fetch int64, icache bool) {
+	base := uintptr(unsafe.Pointer(&mem[0]))
+	iline := ^reg(0)
+
+	if !cpu.Cpsr.T() {
+		var blk *armBlock
+		blkaddr := ^uintptr(0)
+		for i := 0; i < len(mem)-3; i += 4 {
+			cpu.Regs[15] = cpu.pc + 8 // simulate pipeline with prefetch
+			cpu.pc += 4
+
+			if trace != nil {
+				trace(uint32(cpu.pc - 4))
+			}
+
+			op := binary.LittleEndian.Uint32(mem[i:])
+			cpu.Clock += fetch
+			if icache && (cpu.pc-4)&^(cacheLineSize-1) != iline {
+				iline = (cpu.pc - 4) &^ (cacheLineSize - 1)
+				cpu.Clock += cpu.cp15.icacheFetch(uint32(iline))
+			}
+
+			addr := base + uintptr(i)
+			if addr&^(decodeBlockSize-1) != blkaddr {
+				blkaddr = addr &^ (decodeBlockSize - 1)
+				blk = cpu.dcache.armBlock(blkaddr)
+			}
+			d := &blk[(addr/4)%uintptr(len(blk))]
+			if d.op != op {
+				d.op = op
+				d.fn = opArmTable[(((op>>16)&0xFF0)|((op>>4)&0xF))&0xFFF]
+			}
+
+			if op >= 0xE0000000 || cpu.opArmCond(uint(op>>28)) {
+				d.fn(cpu, op)
+			}
+
+			if cpu.Clock >= cpu.targetCycles || cpu.tightExit {
+				break
+			}
+		}
+	} else {
+		var blk *thumbBlock
+		blkaddr := ^uintptr(0)
+		for i := 0; i < len(mem)-1; i += 2 {
+			cpu.Regs[15] = cpu.pc + 4 // simulate pipeline with prefetch
+			cpu.pc += 2
+
+			if trace != nil {
+				trace(uint32(cpu.pc - 2))
+			}
+
+			op := binary.LittleEndian.Uint16(mem[i:])
+			cpu.Clock += fetch
+			if icache && (cpu.pc-2)&^(cacheLineSize-1) != iline {
+				iline = (cpu.pc - 2) &^ (cacheLineSize - 1)
+				cpu.Clock += cpu.cp15.icacheFetch(uint32(iline))
+			}
+
+			addr := base + uintptr(i)
+			if addr&^(decodeBlockSize-1) != blkaddr {
+				blkaddr = addr &^ (decodeBlockSize - 1)
+				blk = cpu.dcache.thumbBlock(blkaddr)
+			}
+			d := &blk[(addr/2)%uintptr(len(blk))]
+			if d.op != op {
+				d.op = op
+				d.fn = opThumbTable[op>>8]
+			}
+
+			d.fn(cpu, op)
+
+			if cpu.Clock >= cpu.targetCycles || cpu.tightExit {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package arm
+
+import "testing"
+
+// Execute a single ARM opcode at address 0x100
+func execArm(cpu *Cpu, op uint32) {
+	cpu.SetPC(0x100)
+	cpu.Regs[15] = cpu.pc + 8
+	cpu.pc += 4
+	if op >= 0xE0000000 || cpu.opArmCond(uint(op>>28)) {
+		opArmTable[(((op>>16)&0xFF0)|((op>>4)&0xF))&0xFFF](cpu, op)
+	}
+}
+
+func TestDsp(t *testing.T) {
+	tests := []struct {
+		op     uint32
+		rn, rm uint32 // values of r1 and r2
+		ra     uint32 // value of r3 (accumulator)
+		want   uint32 // value of r0
+		q      bool
+	}{
+		// qadd r0, r2, r1
+		{0xE1010052, 1, 2, 0, 3, false},
+		{0xE1010052, 0x7FFFFFFF, 1, 0, 0x7FFFFFFF, true},
+		{0xE1010052, 0x80000000, 0xFFFFFFFF, 0, 0x80000000, true},
+		// qsub r0, r2, r1
+		{0xE1210052, 1, 3, 0, 2, false},
+		{0xE1210052, 1, 0x80000000, 0, 0x80000000, true},
+		{0xE1210052, 0x80000000, 0, 0, 0x7FFFFFFF, true},
+		// qdadd r0, r2, r1
+		{0xE1410052, 3, 1, 0, 7, false},
+		{0xE1410052, 0x40000000, 0, 0, 0x7FFFFFFF, true}, // saturates while doubling
+		{0xE1410052, 0x40000000, 0xFFFFFFFF, 0, 0x7FFFFFFE, true},
+		// qdsub r0, r2, r1
+		{0xE1610052, 3, 1, 0, 0xFFFFFFFB, false},
+		{0xE1610052, 0xC0000000, 1, 0, 0x7FFFFFFF, true},
+		// smulbt r0, r2, r1
+		{0xE16001C2, 0xFFFE0000, 0x00001234, 0, 0xFFFFDB98, false},
+		// smlatb r0, r2, r1, r3
+		{0xE10031A2, 0x00000003, 0x00020000, 1, 7, false},
+		{0xE10031A2, 0x00007FFF, 0x7FFF0000, 0x7FFFFFFF, 0xBFFF0000, true}, // wraps, but sets Q
+		// smlawb r0, r2, r1, r3
+		{0xE1203182, 0x00008000, 0x00020000, 5, 0xFFFF0005, false},
+		{0xE1203182, 0x00000001, 0x7FFF0000, 0x7FFFFFFF, 0x80007FFE, true},
+		// smulwt r0, r2, r1
+		{0xE12001E2, 0x00020000, 0x00010000, 0, 2, false},
+	}
+
+	for _, tt := range tests {
+		cpu := NewCpu(ARMv5, newTestBus(nil, nil, 0))
+		cpu.Regs[1], cpu.Regs[2], cpu.Regs[3] = reg(tt.rn), reg(tt.rm), reg(tt.ra)
+		execArm(cpu, tt.op)
+		if uint32(cpu.Regs[0]) != tt.want || cpu.Cpsr.Q() != tt.q {
+			t.Errorf("%08X (r1=%08X r2=%08X r3=%08X): r0=%08X q=%v, want r0=%08X q=%v",
+				tt.op, tt.rn, tt.rm, tt.ra, uint32(cpu.Regs[0]), cpu.Cpsr.Q(), tt.want, tt.q)
+		}
+	}
+
+	// The Q flag is sticky
+	cpu := NewCpu(ARMv5, newTestBus(nil, nil, 0))
+	cpu.Cpsr.SetQ(true)
+	execArm(cpu, 0xE1010052)
+	if !cpu.Cpsr.Q() {
+		t.Errorf("Q flag cleared by a non-saturating qadd")
+	}
+}
+
+func TestSmlalxy(t *testing.T) {
+	// smlalbb r0, r1, r2, r3
+	cpu := NewCpu(ARMv5, newTestBus(nil, nil, 0))
+	cpu.Regs[0], cpu.Regs[1] = 0xFFFFFFFF, 0x00000001
+	cpu.Regs[2], cpu.Regs[3] = 0x0000FFFF, 0x00000002 // -1 * 2
+	execArm(cpu, 0xE1410382)
+	if cpu.Regs[0] != 0xFFFFFFFD || cpu.Regs[1] != 1 {
+		t.Errorf("smlalbb: r0=%v r1=%v, want r0=FFFFFFFD r1=1", cpu.Regs[0], cpu.Regs[1])
+	}
+}
+
+func TestDspUndefinedOnArmv4(t *testing.T) {
+	for _, op := range []uint32{
+		0xE1010052, // qadd r0, r2, r1
+		0xE16001C2, // smulbt r0, r2, r1
+		0xE1410382, // smlalbb r0, r1, r2, r3
+		0xE16F0F12, // clz r0, r2
+		0xE1C100D0, // ldrd r0, [r1]
+		0xE1C100F0, // strd r0, [r1]
+		0xF5D1F020, // pld [r1, #0x20]
+	} {
+		cpu := NewCpu(ARMv4, newTestBus(nil, nil, 0))
+		cpu.Regs[1] = 0x1000
+		execArm(cpu, op)
+		if cpu.Cpsr.GetMode() != CpuModeUndefined || cpu.Regs[15] != 0x4 {
+			t.Errorf("%08X: mode=%v pc=%v, want undefined exception", op, cpu.Cpsr.GetMode(), cpu.Regs[15])
+		}
+		if cpu.Regs[1] != 0x1000 {
+			t.Errorf("%08X: r1 modified", op)
+		}
+	}
+
+	// On ARMv5, PLD is a hint with no effects
+	cpu := NewCpu(ARMv5, newTestBus(nil, nil, 0))
+	cpu.Regs[1] = 0x1000
+	execArm(cpu, 0xF5D1F020)
+	if cpu.pc != 0x104 || cpu.Regs[1] != 0x1000 {
+		t.Errorf("pld: pc=%v r1=%v", cpu.pc, cpu.Regs[1])
+	}
+}
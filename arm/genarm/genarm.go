@@ -23,6 +23,23 @@ func (g *Generator) WriteDisasm(opname string, args ...string) {
 	g.Generator.WriteDisasm(opname, args...)
 }
 
+// ARMv5 opcodes are undefined on ARMv4 (NDS7): they trigger the undefined
+// instruction exception, like on the real hardware.
+func (g *Generator) writeExitIfArmv4() {
+	fmt.Fprintf(g, "if cpu.arch < ARMv5 {\n")
+	fmt.Fprintf(g, "cpu.Exception(ExceptionUndefined)\n")
+	fmt.Fprintf(g, "return\n}\n")
+}
+
+// Exit if the memory access just performed was aborted (see Cpu.dataAbort):
+// the destination and base registers are left unchanged, as on the ARM9
+// ("base restored" abort model).
+func (g *Generator) writeExitIfAborted() {
+	fmt.Fprintf(g, "if cpu.abort {\n")
+	g.writeCycles(1)
+	fmt.Fprintf(g, "return\n}\n")
+}
+
 func (g *Generator) writeBranch(target string, reason string) {
 	fmt.Fprintf(g, "cpu.branch(%s, %s)\n", target, reason)
 }
@@ -50,12 +67,15 @@ func (g *Generator) writeOpSwp(op uint32) {
 	fmt.Fprintf(g, "rdx := (op >> 12) & 0xF\n")
 
 	if byt {
-		fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read8(rn))\n")
+		fmt.Fprintf(g, "res := reg(cpu.Read8(rn))\n")
+		g.writeExitIfAborted()
+		fmt.Fprintf(g, "cpu.Regs[rdx] = res\n")
 		fmt.Fprintf(g, "cpu.Write8(rn, uint8(rm))\n")
 	} else {
 		fmt.Fprintf(g, "res := reg(cpu.Read32(rn))\n")
 		// LDR and SWP only do a bitwise rotation in case of misaligned address
 		fmt.Fprintf(g, "if rn&3!=0 { rot := (rn&3)*8; res = (res>>rot) | (res << (32-rot)) }\n")
+		g.writeExitIfAborted()
 		fmt.Fprintf(g, "cpu.Regs[rdx] = res\n")
 		fmt.Fprintf(g, "cpu.Write32(rn, rm)\n")
 	}
@@ -66,7 +86,7 @@ func (g *Generator) writeOpSwp(op uint32) {
 
 var mulNames = [16]string{
 	"mul", "mla", "?", "?", "umull", "umlal", "smull", "smlal",
-	"smlaXY", "smlawY", "?", "smulXY", "?", "?", "?", "?",
+	"smlaXY", "smlawY", "smlalXY", "smulXY", "?", "?", "?", "?",
 }
 
 func (g *Generator) writeOpMul(op uint32) {
@@ -113,7 +133,7 @@ func (g *Generator) writeOpMul(op uint32) {
 	fmt.Fprintf(g, "// %s\n", name)
 
 	if halfwidth {
-		g.WriteExitIfOpInvalid("cpu.arch < ARMv5", "half-width mul not available on ARMv4 or before")
+		g.writeExitIfArmv4()
 	}
 
 	fmt.Fprintf(g, "rsx := (op >> 8) & 0xF\n")
@@ -124,7 +144,14 @@ func (g *Generator) writeOpMul(op uint32) {
 
 	fmt.Fprintf(g, "rdx := (op >> 16) & 0xF\n")
 
-	if !halfwidth { // cycle count for full word multiplies
+	if halfwidth {
+		// ARM9E: single cycle, except SMLALxy which needs two
+		if code == 0xA {
+			g.writeCycles(2)
+		} else {
+			g.writeCycles(1)
+		}
+	} else { // cycle count for full word multiplies
 		fmt.Fprintf(g, "if rs&0xFFFFFF00 == 0 || ^rs&0xFFFFFF00 == 0 {")
 		g.writeCycles(1 + int(code&1)) // add 1 if *MLA
 		fmt.Fprintf(g, "} else if rs&0xFFFF0000 == 0 || ^rs&0xFFFF0000 == 0 {")
@@ -191,9 +218,27 @@ func (g *Generator) writeOpMul(op uint32) {
 			fmt.Fprintf(g, "hrs := int16(rs&0xFFFF)\n")
 		}
 		fmt.Fprintf(g, "res := reg(int32(hrm)*int32(hrs))\n")
-		fmt.Fprintf(g, "res += reg(rn)\n")
+		g.writeAccumulateQ()
 		g.WriteDisasm(name, "r:(op >> 16) & 0xF", "r:(op >> 0) & 0xF", "r:(op >> 8) & 0xF", "r:(op >> 12) & 0xF")
 
+	case 0xA: // SMLALxy
+		if htopx {
+			fmt.Fprintf(g, "hrm := int16(rm>>16)\n")
+		} else {
+			fmt.Fprintf(g, "hrm := int16(rm&0xFFFF)\n")
+		}
+		if htopy {
+			fmt.Fprintf(g, "hrs := int16(rs>>16)\n")
+		} else {
+			fmt.Fprintf(g, "hrs := int16(rs&0xFFFF)\n")
+		}
+		fmt.Fprintf(g, "rnx := (op >> 12) & 0xF\n")
+		fmt.Fprintf(g, "res64 := int64(int32(hrm)*int32(hrs))\n")
+		fmt.Fprintf(g, "res64 += int64(uint64(cpu.Regs[rnx]) + uint64(cpu.Regs[rdx]) << 32)\n")
+		fmt.Fprintf(g, "cpu.Regs[rnx] = reg(res64)\n")
+		fmt.Fprintf(g, "res := uint32(res64 >> 32)\n")
+		g.WriteDisasm(name, "r:(op >> 12) & 0xF", "r:(op >> 16) & 0xF", "r:(op >> 0) & 0xF", "r:(op >> 8) & 0xF")
+
 	case 0x9:
 		if !htopx {
 			// SMLAWy
@@ -205,7 +250,7 @@ func (g *Generator) writeOpMul(op uint32) {
 				fmt.Fprintf(g, "hrs := int16(rs&0xFFFF)\n")
 			}
 			fmt.Fprintf(g, "res := reg((int64(int32(rm))*int64(hrs))>>16)\n")
-			fmt.Fprintf(g, "res += reg(rn)\n")
+			g.writeAccumulateQ()
 			g.WriteDisasm(name, "r:(op >> 16) & 0xF", "r:(op >> 0) & 0xF", "r:(op >> 8) & 0xF", "r:(op >> 12) & 0xF")
 		} else {
 			// SMULWy
@@ -238,6 +283,14 @@ func (g *Generator) writeOpMul(op uint32) {
 	fmt.Fprintf(g, "cpu.Regs[rdx] = reg(res)\n")
 }
 
+// Accumulate rn into res (SMLAxy, SMLAWy): the addition doesn't saturate,
+// but an overflow sets the sticky Q flag.
+func (g *Generator) writeAccumulateQ() {
+	fmt.Fprintf(g, "acc := res + reg(rn)\n")
+	fmt.Fprintf(g, "if (^(uint32(res)^rn) & (uint32(acc)^rn))>>31 != 0 { cpu.Cpsr.SetQ(true) }\n")
+	fmt.Fprintf(g, "res = acc\n")
+}
+
 func (g *Generator) writeOpBx(op uint32) {
 	link := op&0x20 != 0
 
@@ -361,7 +414,7 @@ func (g *Generator) writeDecodeAluOp2Reg(op uint32, setcarry bool) {
 		case 3: // ror -> rrx #1
 			fmt.Fprintf(g, "if shift == 0 { // becomes RRX #1\n")
 			if setcarry {
-				fmt.Fprintf(g, "cpu.Cpsr.SetC(op2&1 != 0)\n")
+				fmt.Fprintf(g, "cpu.Cpsr.SetCBit(op2)\n")
 			}
 			fmt.Fprintf(g, "  op2 = (op2 >> 1) | (cf << 31)\n")
 			fmt.Fprintf(g, "  goto op2end\n")
@@ -377,7 +430,7 @@ func (g *Generator) writeDecodeAluOp2Reg(op uint32, setcarry bool) {
 	case 0: // lsl
 		if setcarry {
 			fmt.Fprintf(g, "op2 <<= shift-1\n")
-			fmt.Fprintf(g, "cpu.Cpsr.SetC(op2>>31 != 0)\n")
+			fmt.Fprintf(g, "cpu.Cpsr.SetCBit(op2>>31)\n")
 			fmt.Fprintf(g, "op2 <<= 1\n")
 		} else {
 			fmt.Fprintf(g, "op2 <<= shift\n")
@@ -385,7 +438,7 @@ func (g *Generator) writeDecodeAluOp2Reg(op uint32, setcarry bool) {
 	case 1: // lsr
 		if setcarry {
 			fmt.Fprintf(g, "op2 >>= shift-1\n")
-			fmt.Fprintf(g, "cpu.Cpsr.SetC(op2&1 != 0)\n")
+			fmt.Fprintf(g, "cpu.Cpsr.SetCBit(op2)\n")
 			fmt.Fprintf(g, "op2 >>= 1\n")
 		} else {
 			fmt.Fprintf(g, "op2 >>= shift\n")
@@ -393,7 +446,7 @@ func (g *Generator) writeDecodeAluOp2Reg(op uint32, setcarry bool) {
 	case 2: // asr
 		if setcarry {
 			fmt.Fprintf(g, "op2 = uint32(int32(op2)>>(shift-1))\n")
-			fmt.Fprintf(g, "cpu.Cpsr.SetC(op2&1 != 0)\n")
+			fmt.Fprintf(g, "cpu.Cpsr.SetCBit(op2)\n")
 			fmt.Fprintf(g, "op2 = uint32(int32(op2)>>1)\n")
 		} else {
 			fmt.Fprintf(g, "op2 = uint32(int32(op2) >> shift)\n")
@@ -402,7 +455,7 @@ func (g *Generator) writeDecodeAluOp2Reg(op uint32, setcarry bool) {
 		fmt.Fprintf(g, "shift &= 31\n")
 		fmt.Fprintf(g, "op2 = (op2 >> shift) | (op2 << (32 - shift))\n")
 		if setcarry {
-			fmt.Fprintf(g, "cpu.Cpsr.SetC(op2>>31 != 0)\n")
+			fmt.Fprintf(g, "cpu.Cpsr.SetCBit(op2>>31)\n")
 		}
 	}
 
@@ -445,7 +498,7 @@ func (g *Generator) writeOpAlu(op uint32) {
 		fmt.Fprintf(g, "rot := uint((op>>7)&0x1E)\n")
 		fmt.Fprintf(g, "op2 := ((op&0xFF)>>rot) | ((op&0xFF)<<(32-rot))\n")
 		if setflags {
-			fmt.Fprintf(g, "if rot!=0 { cpu.Cpsr.SetC(op2>>31 != 0) }\n")
+			fmt.Fprintf(g, "if rot!=0 { cpu.Cpsr.SetCBit(op2>>31) }\n")
 		}
 		disop2 = "x:((op&0xFF)>>((op>>7)&0x1E)) | ((op&0xFF)<<(32-((op>>7)&0x1E)))"
 	} else {
@@ -499,11 +552,7 @@ func (g *Generator) writeOpAlu(op uint32) {
 		fmt.Fprintf(g, "res := rn + op2\n")
 		fmt.Fprintf(g, "res += cf\n")
 		if setflags {
-			fmt.Fprintf(g, "if cf == 0 {\n")
-			fmt.Fprintf(g, "  cpu.Cpsr.SetC(rn>res)\n")
-			fmt.Fprintf(g, "} else {\n")
-			fmt.Fprintf(g, "  cpu.Cpsr.SetC(rn>=res)\n")
-			fmt.Fprintf(g, "}\n")
+			fmt.Fprintf(g, "cpu.Cpsr.SetCAdd(rn,op2,cf)\n")
 			fmt.Fprintf(g, "cpu.Cpsr.SetVAdd(rn,op2,res)\n")
 		}
 	case 7: // RSC
@@ -513,11 +562,7 @@ func (g *Generator) writeOpAlu(op uint32) {
 		fmt.Fprintf(g, "res := rn - op2\n")
 		fmt.Fprintf(g, "res += cf - 1\n")
 		if setflags {
-			fmt.Fprintf(g, "if cf == 0 {\n")
-			fmt.Fprintf(g, "  cpu.Cpsr.SetC(rn>op2)\n")
-			fmt.Fprintf(g, "} else {\n")
-			fmt.Fprintf(g, "  cpu.Cpsr.SetC(rn>=op2)\n")
-			fmt.Fprintf(g, "}\n")
+			fmt.Fprintf(g, "cpu.Cpsr.SetCSub(rn,op2,cf)\n")
 			fmt.Fprintf(g, "cpu.Cpsr.SetVSub(rn,op2,res)\n")
 		}
 	case 12: // ORR
@@ -588,7 +633,11 @@ func (g *Generator) writeOpBranch(op uint32) {
 	fmt.Fprintf(g, "}\n")
 
 	fmt.Fprintf(&g.Disasm, "if op>>28 == 0xF {\n")
-	g.WriteDisasm("@blx", "o:int32(op<<8)>>6")
+	if link {
+		g.WriteDisasm("@blx", "o:int32(op<<8)>>6+2")
+	} else {
+		g.WriteDisasm("@blx", "o:int32(op<<8)>>6")
+	}
 	fmt.Fprintf(&g.Disasm, "}\n")
 
 	if link {
@@ -620,18 +669,27 @@ func (g *Generator) writeOpCoprocessor(op uint32) {
 		fmt.Fprintf(g, "// MCR\n")
 	}
 
+	fmt.Fprintf(g, "copnum:= (op>>8)&0xF\n")
+	fmt.Fprintf(g, "if cpu.opCopMissing(copnum) { return }\n")
 	fmt.Fprintf(g, "opc   := (op>>21)&0x7\n")
 	fmt.Fprintf(g, "cn    := (op>>16)&0xF\n")
 	fmt.Fprintf(g, "rdx   := (op>>12)&0xF\n")
-	fmt.Fprintf(g, "copnum:= (op>>8)&0xF\n")
 	fmt.Fprintf(g, "cp    := (op>>5)&0x7\n")
 	fmt.Fprintf(g, "cm    := (op>>0)&0xF\n")
 
+	// Opcodes with condition NV are the ARMv5 CDP2/MRC2/MCR2 variants
+	disasm := func(name string, args ...string) {
+		fmt.Fprintf(&g.Disasm, "if op>>28 == 0xF {\n")
+		g.WriteDisasm("@"+name+"2", args...)
+		fmt.Fprintf(&g.Disasm, "}\n")
+		g.WriteDisasm(name, args...)
+	}
+
 	if cdp {
 		fmt.Fprintf(g, "cpu.opCopExec(copnum, opc, cn, cm, cp, rdx)\n")
-		g.WriteDisasm("cdp",
+		disasm("cdp",
 			"s:\"p\"+strconv.FormatInt(int64(op>>8)&0xF,10)",
-			"d:(op>>21)&0x7",
+			"d:(op>>20)&0xF",
 			"s:\"c\"+strconv.FormatInt(int64(op>>12)&0xF,10)",
 			"s:\"c\"+strconv.FormatInt(int64(op>>16)&0xF,10)",
 			"s:\"c\"+strconv.FormatInt(int64(op>>0)&0xF,10)",
@@ -642,7 +700,7 @@ func (g *Generator) writeOpCoprocessor(op uint32) {
 		fmt.Fprintf(g, "res := cpu.opCopRead(copnum, opc, cn, cm, cp)\n")
 		fmt.Fprintf(g, "if rdx==15 { cpu.Cpsr.SetWithMask(res, 0xF0000000, cpu) }")
 		fmt.Fprintf(g, "else { cpu.Regs[rdx] = reg(res) }\n")
-		g.WriteDisasm("mrc",
+		disasm("mrc",
 			"s:\"p\"+strconv.FormatInt(int64(op>>8)&0xF,10)",
 			"d:(op>>21)&0x7",
 			"r:(op>>12)&0xF",
@@ -654,7 +712,7 @@ func (g *Generator) writeOpCoprocessor(op uint32) {
 		fmt.Fprintf(g, "cpu.Regs[15]+=4\n")
 		fmt.Fprintf(g, "rd := cpu.Regs[rdx]\n")
 		fmt.Fprintf(g, "cpu.opCopWrite(copnum, opc, cn, cm, cp, uint32(rd))\n")
-		g.WriteDisasm("mcr",
+		disasm("mcr",
 			"s:\"p\"+strconv.FormatInt(int64(op>>8)&0xF,10)",
 			"d:(op>>21)&0x7",
 			"r:(op>>12)&0xF",
@@ -674,7 +732,18 @@ func (g *Generator) writeOpMemory(op uint32) {
 	wb := (op>>21)&1 != 0
 	load := (op>>20)&1 != 0
 
-	g.WriteExitIfOpInvalid("(op>>28)==0xF", "PLD not supported")
+	if pre && byt && load && !wb {
+		// PLD (ARMv5), in the NV space of LDRB with pre-indexing. There is
+		// nothing to preload on the ARM946 (it has no L2 cache), so it's a
+		// hint that costs a single cycle.
+		fmt.Fprintf(g, "if (op>>28)==0xF {\n")
+		g.writeExitIfArmv4()
+		g.WriteExitIfOpInvalid("op&0x0000F000 != 0x0000F000", "invalid opcode decoded as PLD")
+		g.writeCycles(1)
+		fmt.Fprintf(g, "return\n}\n")
+	} else {
+		g.WriteExitIfOpInvalid("(op>>28)==0xF", "invalid NV opcode in LDR/STR space")
+	}
 
 	fmt.Fprintf(g, "rnx := (op>>16)&0xF\n")
 	fmt.Fprintf(g, "rdx := (op>>12)&0xF\n")
@@ -709,6 +778,7 @@ func (g *Generator) writeOpMemory(op uint32) {
 			fmt.Fprintf(g, "if rn&3!=0 { rot := (rn&3)*8; res = (res>>rot) | (res << (32-rot)) }\n")
 			name = "ldr"
 		}
+		g.writeExitIfAborted()
 		fmt.Fprintf(g, "cpu.Regs[rdx] = reg(res)\n")
 		fmt.Fprintf(g, "if rdx == 15 {\n")
 		fmt.Fprintf(g, "cpu.Cpsr.SetT((res&1)!=0)\n")
@@ -723,10 +793,24 @@ func (g *Generator) writeOpMemory(op uint32) {
 			fmt.Fprintf(g, "cpu.Write32(rn, uint32(rd))\n")
 			name = "str"
 		}
+		if wb || !pre {
+			g.writeExitIfAborted()
+		}
 	}
 
 	fmt.Fprintf(g, "// %s\n", name) // better late than never
 
+	// PLD (ARMv5), in the NV space of LDRB with pre-indexing
+	if pre && byt && load && !wb {
+		fmt.Fprintf(&g.Disasm, "if op>>28 == 0xF {\n")
+		g.writeDisasmMemory("@pld", pre, up, shreg, false)
+		fmt.Fprintf(&g.Disasm, "}\n")
+	}
+	if !pre && wb {
+		// post-indexing with writeback bit: LDRT/STRT/LDRBT/STRBT
+		name += "t"
+	}
+
 	if !pre {
 		if up {
 			fmt.Fprintf(g, "rn += off\n")
@@ -746,7 +830,16 @@ func (g *Generator) writeOpMemory(op uint32) {
 	}
 	g.writeCycles(1)
 
-	// disasm
+	g.writeDisasmMemory(name, pre, up, shreg, wb)
+}
+
+// Write the disassembly of LDR/STR (or PLD, if name is "@pld": in that case,
+// the destination register is omitted).
+func (g *Generator) writeDisasmMemory(name string, pre, up, shreg, wb bool) {
+	var args []string
+	if name != "@pld" {
+		args = append(args, "r:(op>>12)&0xF")
+	}
 	if pre {
 		var off string
 		if shreg {
@@ -765,7 +858,7 @@ func (g *Generator) writeOpMemory(op uint32) {
 		if wb {
 			off += ":!"
 		}
-		g.WriteDisasm(name, "r:(op>>12)&0xF", off)
+		args = append(args, off)
 	} else {
 		var off string
 		if shreg {
@@ -778,11 +871,12 @@ func (g *Generator) writeOpMemory(op uint32) {
 			if up {
 				off = "x:op&0xFFF"
 			} else {
-				off = "x:-int64(op&0xFFF)"
+				off = "s:\"#-0x\"+strconv.FormatInt(int64(op&0xFFF), 16)"
 			}
 		}
-		g.WriteDisasm(name, "r:(op>>12)&0xF", "l:(op>>16)&0xF", off)
+		args = append(args, "l:(op>>16)&0xF", off)
 	}
+	g.WriteDisasm(name, args...)
 }
 
 func (g *Generator) writeOpHalfWord(op uint32) {
@@ -835,6 +929,7 @@ func (g *Generator) writeOpHalfWord(op uint32) {
 			fmt.Fprintf(g, "res := cpu.Read16(rn)\n")
 			// On ARMv4, LDRH byteswaps while reading unaligned addresses
 			fmt.Fprintf(g, "if rn&1!=0 && cpu.arch < ARMv5 { res = (res>>8)|(res<<8) }\n")
+			g.writeExitIfAborted()
 			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(res)\n")
 
 			g.WriteExitIfOpInvalid("rdx==15", "LDRH PC not implemented")
@@ -848,13 +943,18 @@ func (g *Generator) writeOpHalfWord(op uint32) {
 			fmt.Fprintf(g, "// LDRSB\n")
 			name = "ldrsb"
 			fmt.Fprintf(g, "data := int32(int8(cpu.Read8(rn)))\n")
+			g.writeExitIfAborted()
 			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(data)\n")
 			g.WriteExitIfOpInvalid("rdx==15", "LDRSB PC not implemented")
 		} else {
 			fmt.Fprintf(g, "// LDRD\n")
 			name = "ldrd"
-			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read32(rn))\n")
-			fmt.Fprintf(g, "cpu.Regs[rdx+1] = reg(cpu.Read32(rn+4))\n")
+			g.writeExitIfArmv4()
+			fmt.Fprintf(g, "lo := cpu.Read32(rn)\n")
+			fmt.Fprintf(g, "hi := cpu.Read32(rn+4)\n")
+			g.writeExitIfAborted()
+			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(lo)\n")
+			fmt.Fprintf(g, "cpu.Regs[rdx+1] = reg(hi)\n")
 			g.WriteExitIfOpInvalid("rdx==14", "LDRD PC not implemented")
 		}
 	case 3:
@@ -864,15 +964,20 @@ func (g *Generator) writeOpHalfWord(op uint32) {
 			fmt.Fprintf(g, "data := int32(int16(cpu.Read16(rn)))\n")
 			// On ARMv4, LDRSH basically ignores the lower byte and sign extends the higher
 			fmt.Fprintf(g, "if rn&1!=0 && cpu.arch < ARMv5 { data >>= 8 }\n")
+			g.writeExitIfAborted()
 			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(data)\n")
 			g.WriteExitIfOpInvalid("rdx==15", "LDRSH PC not implemented")
 		} else {
 			fmt.Fprintf(g, "// STRD\n")
 			name = "strd"
+			g.writeExitIfArmv4()
 			fmt.Fprintf(g, "cpu.Write32(rn, uint32(cpu.Regs[rdx]))\n")
 			fmt.Fprintf(g, "cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))\n")
 		}
 	}
+	if !load && wb {
+		g.writeExitIfAborted()
+	}
 
 	if !pre {
 		if up {
@@ -968,18 +1073,46 @@ func (g *Generator) writeOpBlock(op uint32) {
 	fmt.Fprintf(g, "rnx := (op>>16)&0xF\n")
 	g.WriteExitIfOpInvalid("rnx==15", "invalid use of PC in LDM/STM")
 	fmt.Fprintf(g, "rn := uint32(cpu.Regs[rnx])\n")
+	if load {
+		fmt.Fprintf(g, "base := cpu.Regs[rnx]\n")
+	}
 	fmt.Fprintf(g, "mask := uint16(op&0xFFFF)\n")
-	if !up {
-		fmt.Fprintf(g, "rn -= uint32(4*popcount16(mask))\n")
+
+	// Empty register list: the base is moved as if all 16 registers were
+	// transferred, but only PC is transferred (ARMv4), or nothing (ARMv5).
+	if up && !wb {
+		// The size is not needed
+		fmt.Fprintf(g, "if mask == 0 && cpu.arch < ARMv5 { mask = 0x8000 }\n")
+	} else {
+		fmt.Fprintf(g, "size := uint32(4*popcount16(mask))\n")
+		fmt.Fprintf(g, "if mask == 0 {\n")
+		fmt.Fprintf(g, "  size = 0x40\n")
+		fmt.Fprintf(g, "  if cpu.arch < ARMv5 { mask = 0x8000 }\n")
+		fmt.Fprintf(g, "}\n")
+	}
+	if up {
+		if wb {
+			fmt.Fprintf(g, "wbrn := rn + size\n")
+		}
+	} else {
+		fmt.Fprintf(g, "rn -= size\n")
 		if wb {
-			fmt.Fprintf(g, "orn := rn\n")
+			fmt.Fprintf(g, "wbrn := rn\n")
 		}
 		pre = !pre
 	}
 	if !load {
 		fmt.Fprintf(g, "cpu.Regs[15] += 4\n") // simulate prefetching
+		if wb {
+			// When the base is in the list, ARMv4 stores the new base unless
+			// it's the first register being stored; ARMv5 always stores the
+			// old base.
+			fmt.Fprintf(g, "newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0\n")
+		}
 	}
 	if psr {
+		// S bit: transfer the user bank registers, unless it's a LDM
+		// including PC (which instead restores CPSR from SPSR)
 		if load {
 			fmt.Fprintf(g, "usrbnk := (mask&0x8000)==0\n")
 		} else {
@@ -988,41 +1121,66 @@ func (g *Generator) writeOpBlock(op uint32) {
 		fmt.Fprintf(g, "oldmode := cpu.Cpsr.GetMode()\n")
 		fmt.Fprintf(g, "if usrbnk { cpu.Cpsr.SetMode(CpuModeUser, cpu) }\n")
 	}
-	fmt.Fprintf(g, "for i:=0; mask != 0; i++ {\n")
-	fmt.Fprintf(g, "  if mask&1 != 0 {\n")
+	fmt.Fprintf(g, "m := mask\n")
+	fmt.Fprintf(g, "for i:=0; m != 0; i++ {\n")
+	fmt.Fprintf(g, "  if m&1 != 0 {\n")
 	if pre {
 		fmt.Fprintf(g, "rn += 4\n")
 	}
 	if load {
-		fmt.Fprintf(g, "val := reg(cpu.Read32(rn))\n")
-		fmt.Fprintf(g, "cpu.Regs[i] = val\n")
-		fmt.Fprintf(g, "if i==15 {\n")
-		if psr {
-			fmt.Fprintf(g, "cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)\n")
-		}
-		fmt.Fprintf(g, "  if cpu.Regs[15]&1 != 0 {cpu.Cpsr.SetT(true); cpu.Regs[15] &^= 1} else {cpu.Regs[15] &^= 3}\n")
-		g.writeBranch("   cpu.Regs[15]", "BranchJump")
-		fmt.Fprintf(g, "}\n")
+		fmt.Fprintf(g, "cpu.Regs[i] = reg(cpu.Read32(rn))\n")
 	} else {
-		fmt.Fprintf(g, "var val uint32\n")
-		fmt.Fprintf(g, "val = uint32(cpu.Regs[i])\n")
+		fmt.Fprintf(g, "val := uint32(cpu.Regs[i])\n")
+		if wb {
+			fmt.Fprintf(g, "if i == int(rnx) && newbase { val = wbrn }\n")
+		}
 		fmt.Fprintf(g, "cpu.Write32(rn, val)\n")
 	}
 	if !pre {
 		fmt.Fprintf(g, "rn += 4\n")
 	}
 	fmt.Fprintf(g, "  }\n")
-	fmt.Fprintf(g, "  mask >>= 1\n")
+	fmt.Fprintf(g, "  m >>= 1\n")
 	fmt.Fprintf(g, "}\n")
+	if psr {
+		fmt.Fprintf(g, "if usrbnk { cpu.Cpsr.SetMode(oldmode, cpu) }\n")
+	}
+	// On abort, the registers loaded so far are overwritten, but the base
+	// is restored (even if it was in the list), and PC and CPSR are not
+	// changed
+	if load {
+		fmt.Fprintf(g, "if cpu.abort {\n")
+		fmt.Fprintf(g, "cpu.Regs[rnx] = base\n")
+		g.writeCycles(1)
+		fmt.Fprintf(g, "return\n}\n")
+	} else if wb {
+		g.writeExitIfAborted()
+	}
 	if wb {
-		if up {
-			fmt.Fprintf(g, "cpu.Regs[rnx] = reg(rn)\n")
+		if load {
+			// When the base is in the list, the loaded value wins on ARMv4;
+			// ARMv5 writes back the new base, unless the base is the last
+			// register of the list (and not the only one).
+			fmt.Fprintf(g, "if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {\n")
+			fmt.Fprintf(g, "  cpu.Regs[rnx] = reg(wbrn)\n")
+			fmt.Fprintf(g, "}\n")
 		} else {
-			fmt.Fprintf(g, "cpu.Regs[rnx] = reg(orn)\n")
+			fmt.Fprintf(g, "cpu.Regs[rnx] = reg(wbrn)\n")
 		}
 	}
-	if psr {
-		fmt.Fprintf(g, "if usrbnk { cpu.Cpsr.SetMode(oldmode, cpu) }\n")
+	if load {
+		// Jump after the writeback, as a mode change (when restoring CPSR)
+		// must not affect the bank of the base register
+		fmt.Fprintf(g, "if mask&0x8000 != 0 {\n")
+		if psr {
+			fmt.Fprintf(g, "cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)\n")
+			fmt.Fprintf(g, "if cpu.Cpsr.T() { cpu.Regs[15] &^= 1 } else { cpu.Regs[15] &^= 3 }\n")
+		} else {
+			// Only ARMv5 switches to thumb depending on bit 0
+			fmt.Fprintf(g, "if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {cpu.Cpsr.SetT(true); cpu.Regs[15] &^= 1} else {cpu.Regs[15] &^= 3}\n")
+		}
+		g.writeBranch("cpu.Regs[15]", "BranchJump")
+		fmt.Fprintf(g, "}\n")
 	}
 	g.writeCycles(1)
 
@@ -1030,14 +1188,124 @@ func (g *Generator) writeOpBlock(op uint32) {
 	if wb {
 		sreg += ":!"
 	}
-	g.WriteDisasm(name, sreg, "k:uint16(op&0xFFFF)")
+	regs := "k:uint16(op&0xFFFF)"
+	if psr {
+		regs += ":^"
+	}
+	g.WriteDisasm(name, sreg, regs)
+}
+
+// QADD/QSUB/QDADD/QDSUB (ARMv5TE saturated arithmetic)
+func (g *Generator) writeOpQAlu(op uint32) {
+	names := [4]string{"qadd", "qsub", "qdadd", "qdsub"}
+	name := names[(op>>21)&3]
+	double := (op>>22)&1 != 0
+	sub := (op>>21)&1 != 0
+	fmt.Fprintf(g, "// %s\n", name)
+	g.WriteExitIfOpInvalid("op&0x0F900FF0 != 0x01000050", "invalid opcode decoded as QADD/QSUB")
+	g.writeExitIfArmv4()
+
+	fmt.Fprintf(g, "rm := int64(int32(cpu.Regs[op&0xF]))\n")
+	fmt.Fprintf(g, "rn := int64(int32(cpu.Regs[(op>>16)&0xF]))\n")
+	if double {
+		// QDADD/QDSUB: rn is doubled (with saturation) before the operation
+		fmt.Fprintf(g, "dbl, sat1 := saturate32(rn*2)\n")
+		fmt.Fprintf(g, "if sat1 { cpu.Cpsr.SetQ(true) }\n")
+		fmt.Fprintf(g, "rn = int64(dbl)\n")
+	}
+	if sub {
+		fmt.Fprintf(g, "res, sat := saturate32(rm-rn)\n")
+	} else {
+		fmt.Fprintf(g, "res, sat := saturate32(rm+rn)\n")
+	}
+	fmt.Fprintf(g, "if sat { cpu.Cpsr.SetQ(true) }\n")
+	fmt.Fprintf(g, "cpu.Regs[(op>>12)&0xF] = reg(res)\n")
+	g.writeCycles(1)
+	g.WriteDisasm(name, "r:(op>>12)&0xF", "r:op&0xF", "r:(op>>16)&0xF")
+}
+
+// BKPT (ARMv5): unconditional, the comment field is split around bits 4-7
+func (g *Generator) writeOpBkpt(op uint32) {
+	fmt.Fprintf(g, "// bkpt\n")
+	g.WriteExitIfOpInvalid("op&0xFFF000F0 != 0xE1200070", "invalid opcode decoded as BKPT")
+	g.writeExitIfArmv4()
+	fmt.Fprintf(g, "cpu.bkpt()\n")
+	g.WriteDisasm("@bkpt", "x:(op>>4)&0xFFF0|op&0xF")
+}
+
+// LDC/STC, and the ARMv5TE MCRR/MRRC (in the LDC/STC space with
+// pre-indexing, no up and no writeback). There are no coprocessors with
+// registers transferable to/from memory on NDS, so they're only
+// disassembled (and raise the undefined exception if the coprocessor is
+// missing).
+func (g *Generator) writeOpCopMemory(op uint32) {
+	if (op>>21)&0xF == 0x2 {
+		name := "mcrr"
+		if (op>>20)&1 != 0 {
+			name = "mrrc"
+		}
+		fmt.Fprintf(g, "// %s\n", name)
+		fmt.Fprintf(g, "if cpu.opCopMissing((op>>8)&0xF) { return }\n")
+		g.WriteOpInvalid(name + " not implemented")
+		g.WriteDisasm(name,
+			"s:\"p\"+strconv.FormatInt(int64(op>>8)&0xF,10)",
+			"d:(op>>4)&0xF",
+			"r:(op>>12)&0xF",
+			"r:(op>>16)&0xF",
+			"s:\"c\"+strconv.FormatInt(int64(op>>0)&0xF,10)",
+		)
+		return
+	}
+
+	pre := (op>>24)&1 != 0
+	up := (op>>23)&1 != 0
+	long := (op>>22)&1 != 0
+	wb := (op>>21)&1 != 0
+	load := (op>>20)&1 != 0
+
+	name := "stc"
+	if load {
+		name = "ldc"
+	}
+	suffix := ""
+	if long {
+		suffix = "l"
+	}
+	fmt.Fprintf(g, "// %s%s\n", name, suffix)
+	fmt.Fprintf(g, "if cpu.opCopMissing((op>>8)&0xF) { return }\n")
+	g.WriteOpInvalid(name + suffix + " not implemented")
+
+	off := "int32(op&0xFF)*4"
+	if !up {
+		off = "-" + off
+	}
+	var mem string
+	if pre {
+		mem = "n:(op>>16)&0xF:" + off
+		if wb {
+			mem += ":!"
+		}
+	} else {
+		mem = "s:\"[\"+RegNames[(op>>16)&0xF]+\"], #\"+strconv.FormatInt(int64(" + off + "),10)"
+	}
+	args := []string{
+		"s:\"p\"+strconv.FormatInt(int64(op>>8)&0xF,10)",
+		"s:\"c\"+strconv.FormatInt(int64(op>>12)&0xF,10)",
+		mem,
+	}
+
+	// Opcodes with condition NV are the ARMv5 LDC2/STC2 variants
+	fmt.Fprintf(&g.Disasm, "if op>>28 == 0xF {\n")
+	g.WriteDisasm("@"+name+"2"+suffix, args...)
+	fmt.Fprintf(&g.Disasm, "}\n")
+	g.WriteDisasm(name+suffix, args...)
 }
 
 func (g *Generator) writeOpClz(op uint32) {
 
 	fmt.Fprintf(g, "// clz\n")
 	g.WriteExitIfOpInvalid("op&0x0FFF0FF0 != 0x016F0F10", "invalid opcode decoded as CLZ")
-	g.WriteExitIfOpInvalid("cpu.arch < ARMv5", "invalid CLZ opcode on pre-ARMv5 CPU")
+	g.writeExitIfArmv4()
 
 	fmt.Fprintf(g, "rdx := (op>>12)&0xF\n")
 	fmt.Fprintf(g, "rm := cpu.Regs[op&0xF]\n")
@@ -1056,57 +1324,33 @@ func (g *Generator) writeOpUndefined(op uint32) {
 	fmt.Fprintf(g, "cpu.Exception(ExceptionUndefined)\n")
 }
 
-func (g *Generator) WriteOp(op uint32) {
-	high := (op >> 20) & 0xFF
-	low := (op >> 4) & 0xF
-
-	g.WriteOpHeader(int(high<<4 | low))
-
-	// E1C410B0
-
-	switch {
-	case high == 0x12 && low&0xD == 0x1:
-		g.writeOpBx(op)
-	case high == 0x16 && low == 0x1:
-		g.writeOpClz(op)
-	case (high & 0xFB) == 0x32:
-		g.writeOpPsrTransfer(op)
-	case (high&0xF9) == 0x10 && low == 0:
-		g.writeOpPsrTransfer(op)
-	case (high&0xF9) == 0x10 && low&0x9 == 0x8:
-		g.writeOpMul(op) // half-word mul
-	case (high&0xFC) == 0 && low&0xF == 0x9:
-		g.writeOpMul(op)
-	case (high&0xF8) == 8 && low&0xF == 0x9:
-		g.writeOpMul(op)
-	case (high&0xFB) == 0x10 && low&0xF == 0x9:
-		g.writeOpSwp(op)
-	case (high>>5) == 0 && low&0x9 == 9: // TransReg10 / TransImm10
-		g.writeOpHalfWord(op)
-	case (high>>5) == 0 && low&0x1 == 0:
-		g.writeOpAlu(op)
-	case (high>>5) == 0 && low&0x9 == 1:
-		g.writeOpAlu(op)
-	case (high >> 5) == 1:
-		g.writeOpAlu(op)
-	case (high>>5) == 3 && low&0x1 == 1:
-		g.writeOpUndefined(op)
-	case (high>>5) == 2 || (high>>5) == 3: // TransImm9 / TransReg9
-		g.writeOpMemory(op)
-	case (high >> 5) == 4:
-		g.writeOpBlock(op)
-	case (high >> 5) == 5:
-		g.writeOpBranch(op)
-	case (high>>5) == 7 && (high>>4)&1 == 0:
-		g.writeOpCoprocessor(op)
-	case (high>>5) == 7 && (high>>4)&1 == 1:
-		g.writeOpSwi(op)
-	default:
-		g.WriteOpInvalid("unimplemented")
-		// panic("unreachable")
+// Opcodes are dispatched through a table indexed by bits 20-27 and 4-7 of
+// the opcode (that is: (op>>20)&0xFF<<4 | (op>>4)&0xF). Masks and values
+// below refer to that 12-bit index. Order is important: the first match wins.
+func (g *Generator) ops() []cpugen.OpDesc {
+	return []cpugen.OpDesc{
+		{Name: "bx/blx reg", Mask: 0xFFD, Value: 0x121, Gen: g.writeOpBx},
+		{Name: "clz", Mask: 0xFFF, Value: 0x161, Gen: g.writeOpClz},
+		{Name: "msr imm", Mask: 0xFB0, Value: 0x320, Gen: g.writeOpPsrTransfer},
+		{Name: "mrs/msr reg", Mask: 0xF9F, Value: 0x100, Gen: g.writeOpPsrTransfer},
+		{Name: "qadd/qsub", Mask: 0xF9F, Value: 0x105, Gen: g.writeOpQAlu},
+		{Name: "bkpt", Mask: 0xFFF, Value: 0x127, Gen: g.writeOpBkpt},
+		{Name: "mul halfword", Mask: 0xF99, Value: 0x108, Gen: g.writeOpMul},
+		{Name: "mul", Mask: 0xFCF, Value: 0x009, Gen: g.writeOpMul},
+		{Name: "mul long", Mask: 0xF8F, Value: 0x089, Gen: g.writeOpMul},
+		{Name: "swp", Mask: 0xFBF, Value: 0x109, Gen: g.writeOpSwp},
+		{Name: "ldr/str halfword", Mask: 0xE09, Value: 0x009, Gen: g.writeOpHalfWord},
+		{Name: "alu shift imm", Mask: 0xE01, Value: 0x000, Gen: g.writeOpAlu},
+		{Name: "alu shift reg", Mask: 0xE09, Value: 0x001, Gen: g.writeOpAlu},
+		{Name: "alu imm", Mask: 0xE00, Value: 0x200, Gen: g.writeOpAlu},
+		{Name: "undefined", Mask: 0xE01, Value: 0x601, Gen: g.writeOpUndefined},
+		{Name: "ldr/str", Mask: 0xC00, Value: 0x400, Gen: g.writeOpMemory},
+		{Name: "ldm/stm", Mask: 0xE00, Value: 0x800, Gen: g.writeOpBlock},
+		{Name: "b/bl/blx", Mask: 0xE00, Value: 0xA00, Gen: g.writeOpBranch},
+		{Name: "ldc/stc", Mask: 0xE00, Value: 0xC00, Gen: g.writeOpCopMemory},
+		{Name: "cdp/mrc/mcr", Mask: 0xF00, Value: 0xE00, Gen: g.writeOpCoprocessor},
+		{Name: "swi", Mask: 0xF00, Value: 0xF00, Gen: g.writeOpSwi},
 	}
-
-	g.WriteOpFooter(int(high<<4 | low))
 }
 
 func main() {
@@ -1118,9 +1362,10 @@ func main() {
 		out.PcRelOff = 8
 		out.TableBits = 12
 		out.WriteHeader()
+		ops := out.ops()
 		for op := 0; op < 0x100; op++ {
 			for op2 := 0; op2 < 0x10; op2++ {
-				out.WriteOp(uint32(op<<20) | uint32(op2<<4))
+				out.WriteOpFromTable(ops, op<<4|op2, uint32(op<<20)|uint32(op2<<4))
 			}
 		}
 		out.WriteFooter()
@@ -14,6 +14,22 @@ func (g *Generator) writeCycles(cycles int) {
 	fmt.Fprintf(g, "cpu.Clock += %d\n", cycles)
 }
 
+// Exit if the memory access just performed was aborted (see Cpu.dataAbort):
+// the destination and base registers are left unchanged, as on the ARM9
+// ("base restored" abort model).
+func (g *Generator) writeExitIfAborted() {
+	fmt.Fprintf(g, "if cpu.abort {\n")
+	g.writeCycles(1)
+	fmt.Fprintf(g, "return\n}\n")
+}
+
+// Load a register, unless the access is aborted
+func (g *Generator) writeLoad(rd string, val string) {
+	fmt.Fprintf(g, "val := reg(%s)\n", val)
+	g.writeExitIfAborted()
+	fmt.Fprintf(g, "cpu.Regs[%s] = val\n", rd)
+}
+
 func (g *Generator) writeBranch(target string, reason string) {
 	fmt.Fprintf(g, "cpu.branch(%s, %s)\n", target, reason)
 }
@@ -84,15 +100,15 @@ func (g *Generator) writeOpF1Shift(op uint16) {
 
 	switch opcode {
 	case 0: // LSL
-		fmt.Fprintf(g, "if offset != 0 { cpu.Cpsr.SetC(rs & (1<<(32-offset)) != 0) }\n")
+		fmt.Fprintf(g, "if offset != 0 { cpu.Cpsr.SetCBit(rs >> (32-offset)) }\n")
 		fmt.Fprintf(g, "res := rs << offset\n")
 	case 1: // LSR
 		fmt.Fprintf(g, "if offset == 0 { offset = 32 }\n")
-		fmt.Fprintf(g, "cpu.Cpsr.SetC(rs & (1<<(offset-1)) != 0)\n")
+		fmt.Fprintf(g, "cpu.Cpsr.SetCBit(rs >> (offset-1))\n")
 		fmt.Fprintf(g, "res := rs >> offset\n")
 	case 2: // ASR
 		fmt.Fprintf(g, "if offset == 0 { offset = 32 }\n")
-		fmt.Fprintf(g, "cpu.Cpsr.SetC(rs & (1<<(offset-1)) != 0)\n")
+		fmt.Fprintf(g, "cpu.Cpsr.SetCBit(rs >> (offset-1))\n")
 		fmt.Fprintf(g, "res := uint32(int32(rs) >> offset)\n")
 	default:
 		panic("unreachable")
@@ -237,7 +253,7 @@ func (g *Generator) writeOpF6LdrPc(op uint16) {
 	fmt.Fprintf(g, "// ldr pc\n")
 	fmt.Fprintf(g, "pc := uint32(cpu.Regs[15]) &^ 2\n")
 	fmt.Fprintf(g, "pc += uint32((op & 0xFF)*4)\n")
-	fmt.Fprintf(g, "cpu.Regs[%d] = reg(cpu.Read32(pc))\n", rdx)
+	g.writeLoad(fmt.Sprint(rdx), "cpu.Read32(pc)")
 	g.writeCycles(1)
 	g.WriteDisasm("ldr", "r:(op>>8)&7", "P:(op & 0xFF)*4")
 }
@@ -268,9 +284,9 @@ func (g *Generator) writeOpF7F8LdrStr(op uint16) {
 		case 1: // STRB
 			fmt.Fprintf(g, "cpu.Write8(addr, uint8(cpu.Regs[rdx]))\n")
 		case 2: // LDR
-			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read32(addr))\n")
+			g.writeLoad("rdx", "cpu.Read32(addr)")
 		case 3: // LDRB
-			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read8(addr))\n")
+			g.writeLoad("rdx", "cpu.Read8(addr)")
 		default:
 			panic("unreachable")
 		}
@@ -279,11 +295,11 @@ func (g *Generator) writeOpF7F8LdrStr(op uint16) {
 		case 0: // STRH
 			fmt.Fprintf(g, "cpu.Write16(addr, uint16(cpu.Regs[rdx]))\n")
 		case 1: // LDSB
-			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(int8(cpu.Read8(addr)))\n")
+			g.writeLoad("rdx", "int8(cpu.Read8(addr))")
 		case 2: // LDRH
-			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read16(addr))\n")
+			g.writeLoad("rdx", "cpu.Read16(addr)")
 		case 3: // LDSH
-			fmt.Fprintf(g, "cpu.Regs[rdx] = reg(int16(cpu.Read16(addr)))\n")
+			g.writeLoad("rdx", "int16(cpu.Read16(addr))")
 		default:
 			panic("unreachable")
 		}
@@ -307,12 +323,12 @@ func (g *Generator) writeOpF9Strb(op uint16) {
 		fmt.Fprintf(g, "cpu.Write32(rb+offset, rd)\n")
 	case 1: // LDR
 		fmt.Fprintf(g, "offset *= 4\n")
-		fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read32(rb+offset))\n")
+		g.writeLoad("rdx", "cpu.Read32(rb+offset)")
 	case 2: // STRB
 		fmt.Fprintf(g, "rd := uint8(cpu.Regs[rdx])\n")
 		fmt.Fprintf(g, "cpu.Write8(rb+offset, rd)\n")
 	case 3: // LDRB
-		fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read8(rb+offset))\n")
+		g.writeLoad("rdx", "cpu.Read8(rb+offset)")
 	default:
 		panic("unreachable")
 	}
@@ -342,7 +358,7 @@ func (g *Generator) writeOpF10Strh(op uint16) {
 		fmt.Fprintf(g, "cpu.Write16(rb+offset, rd)\n")
 	case 1: // LDRH
 		fmt.Fprintf(g, "offset *= 2\n")
-		fmt.Fprintf(g, "cpu.Regs[rdx] = reg(cpu.Read16(rb+offset))\n")
+		g.writeLoad("rdx", "cpu.Read16(rb+offset)")
 	default:
 		panic("unreachable")
 	}
@@ -363,7 +379,7 @@ func (g *Generator) writeOpF11Strsp(op uint16) {
 	case 0: // STR
 		fmt.Fprintf(g, "cpu.Write32(sp+uint32(offset), uint32(cpu.Regs[%d]))\n", rdx)
 	case 1: // LDR
-		fmt.Fprintf(g, "cpu.Regs[%d] = reg(cpu.Read32(sp+uint32(offset)))\n", rdx)
+		g.writeLoad(fmt.Sprint(rdx), "cpu.Read32(sp+uint32(offset))")
 	default:
 		panic("unreachable")
 	}
@@ -421,7 +437,6 @@ func (g *Generator) writeOpF14PushPop(op uint16) {
 	fmt.Fprintf(g, "sp := uint32(cpu.Regs[13])\n")
 	if !pop {
 		fmt.Fprintf(g, "sp -= uint32(count*4)\n")
-		fmt.Fprintf(g, "cpu.Regs[13] = reg(sp)\n")
 	}
 
 	for i := 0; i < 9; i++ {
@@ -440,10 +455,12 @@ func (g *Generator) writeOpF14PushPop(op uint16) {
 
 				g.writeCaseArchSwitch("ARMv4")
 				fmt.Fprintf(g, "  pc := reg(cpu.Read32(sp) &^ 1)\n")
+				g.writeExitIfAborted()
 				g.writeBranch("pc", "BranchReturn")
 
 				g.writeCaseArchSwitch("ARMv5")
 				fmt.Fprintf(g, "  pc := reg(cpu.Read32(sp))\n")
+				g.writeExitIfAborted()
 				fmt.Fprintf(g, "  if pc&1 == 0 { cpu.Cpsr.SetT(false); pc = pc&^3 } else { pc = pc&^1 }\n")
 				g.writeBranch("pc", "BranchReturn")
 
@@ -460,8 +477,13 @@ func (g *Generator) writeOpF14PushPop(op uint16) {
 		fmt.Fprintf(g, "}\n")
 	}
 
+	// On abort, the registers loaded so far are overwritten, but the stack
+	// pointer is not updated
+	g.writeExitIfAborted()
 	if pop {
 		fmt.Fprintf(g, "cpu.Regs[13] = reg(sp)\n")
+	} else {
+		fmt.Fprintf(g, "cpu.Regs[13] -= reg(count*4)\n")
 	}
 	g.writeCycles(1)
 
@@ -480,6 +502,17 @@ func (g *Generator) writeOpF14PushPop(op uint16) {
 	}
 }
 
+// BKPT (ARMv5): raises a prefetch abort
+func (g *Generator) writeOpF17Bkpt(op uint16) {
+	fmt.Fprintf(g, "// bkpt\n")
+	fmt.Fprintf(g, "if cpu.arch < ARMv5 {\n")
+	fmt.Fprintf(g, "  cpu.Exception(ExceptionUndefined)\n")
+	fmt.Fprintf(g, "  return\n")
+	fmt.Fprintf(g, "}\n")
+	fmt.Fprintf(g, "cpu.bkpt()\n")
+	g.WriteDisasm("bkpt", "x:op&0xFF")
+}
+
 func (g *Generator) writeOpF15LdmStm(op uint16) {
 	load := (op>>11)&1 != 0
 	rbx := (op >> 8) & 0x7
@@ -488,30 +521,43 @@ func (g *Generator) writeOpF15LdmStm(op uint16) {
 		fmt.Fprintf(g, "// ldm\n")
 	} else {
 		fmt.Fprintf(g, "// stm\n")
-		g.WriteExitIfOpInvalid(fmt.Sprintf("op&(1<<%d) != 0", rbx), "unimplemented: base reg in register list in STM")
 	}
 
 	fmt.Fprintf(g, "ptr := uint32(cpu.Regs[%d])\n", rbx)
+	if load {
+		fmt.Fprintf(g, "base := cpu.Regs[%d]\n", rbx)
+	}
 
+	// Empty register list: the base is incremented by 0x40, as if all 16
+	// registers were transferred, but only PC is transferred (ARMv4), or
+	// nothing (ARMv5).
 	fmt.Fprintf(g, "if op&0xFF==0 {\n")
 	g.writeBeginArchSwitch()
 
 	g.writeCaseArchSwitch("ARMv4")
 	if load {
-		fmt.Fprintf(g, "  cpu.Regs[15] = reg(cpu.Read32(ptr))\n")
+		fmt.Fprintf(g, "  pc := reg(cpu.Read32(ptr)) &^ 1\n")
+		g.writeExitIfAborted()
+		fmt.Fprintf(g, "  cpu.Regs[%d] = reg(ptr+0x40)\n", rbx)
+		g.writeBranch("pc", "BranchJump")
 	} else {
-		fmt.Fprintf(g, "  cpu.Write32(ptr, uint32(cpu.Regs[15]))\n")
+		// PC is stored as the address of the opcode + 6
+		fmt.Fprintf(g, "  cpu.Write32(ptr, uint32(cpu.Regs[15]+2))\n")
+		g.writeExitIfAborted()
+		fmt.Fprintf(g, "  cpu.Regs[%d] = reg(ptr+0x40)\n", rbx)
 	}
-	fmt.Fprintf(g, "ptr+=0x40\n")
 
 	g.writeCaseArchSwitch("ARMv5")
-	fmt.Fprintf(g, "ptr+=0x40\n")
+	fmt.Fprintf(g, "  cpu.Regs[%d] = reg(ptr+0x40)\n", rbx)
 
 	g.writeEndArchSwitch()
-	fmt.Fprintf(g, "cpu.Regs[%d] = reg(ptr)\n", rbx)
+	g.writeCycles(1)
 	fmt.Fprintf(g, "return\n")
 	fmt.Fprintf(g, "}\n")
 
+	if !load && rbx != 0 {
+		fmt.Fprintf(g, "wbptr := ptr + uint32(4*popcount16(op&0xFF))\n")
+	}
 	fmt.Fprintf(g, "wb := true\n")
 	for i := 0; i < 8; i++ {
 		fmt.Fprintf(g, "if op & (1<<%d) != 0 {\n", i)
@@ -519,8 +565,20 @@ func (g *Generator) writeOpF15LdmStm(op uint16) {
 		if load {
 			fmt.Fprintf(g, "  cpu.Regs[%d] = reg(cpu.Read32(ptr))\n", regnum)
 			if regnum == int(rbx) {
-				fmt.Fprintf(g, "wb = false\n")
+				// Base in the list: the loaded value wins on ARMv4; ARMv5
+				// writes back the new base, unless the base is the last
+				// register of the list (and not the only one).
+				fmt.Fprintf(g, "wb = cpu.arch >= ARMv5 && (op&0xFF == %#x || op&%#x != 0)\n",
+					1<<rbx, 0xFF&^(2<<rbx-1))
 			}
+		} else if regnum == int(rbx) {
+			// Base in the list: ARMv4 stores the new base, unless it's the
+			// first register stored; ARMv5 always stores the old base.
+			fmt.Fprintf(g, "  val := uint32(cpu.Regs[%d])\n", regnum)
+			if rbx != 0 {
+				fmt.Fprintf(g, "  if cpu.arch < ARMv5 && op&%#x != 0 { val = wbptr }\n", 1<<rbx-1)
+			}
+			fmt.Fprintf(g, "  cpu.Write32(ptr, val)\n")
 		} else {
 			fmt.Fprintf(g, "  cpu.Write32(ptr, uint32(cpu.Regs[%d]))\n", regnum)
 		}
@@ -528,6 +586,15 @@ func (g *Generator) writeOpF15LdmStm(op uint16) {
 		fmt.Fprintf(g, "}\n")
 	}
 
+	// On abort, the base is restored (even if it was in the list)
+	if load {
+		fmt.Fprintf(g, "if cpu.abort {\n")
+		fmt.Fprintf(g, "cpu.Regs[%d] = base\n", rbx)
+		g.writeCycles(1)
+		fmt.Fprintf(g, "return\n}\n")
+	} else {
+		g.writeExitIfAborted()
+	}
 	fmt.Fprintf(g, "if wb { cpu.Regs[%d] = reg(ptr) }\n", rbx)
 	g.writeCycles(1)
 	if load {
@@ -655,44 +722,36 @@ func (g *Generator) WriteAluOp(op uint16) {
 		fmt.Fprintf(g, "res := rd ^ rs\n")
 	case 2: // LSL
 		fmt.Fprintf(g, "shift := (rs&0xFF)\n")
-		fmt.Fprintf(g, "if shift != 0 { cpu.Cpsr.SetC((rd << (shift-1)) & 0x80000000 != 0) }\n")
+		fmt.Fprintf(g, "if shift != 0 { cpu.Cpsr.SetCBit((rd << (shift-1)) >> 31) }\n")
 		fmt.Fprintf(g, "res := rd << shift\n")
 	case 3: // LSR
 		fmt.Fprintf(g, "shift := (rs&0xFF)\n")
-		fmt.Fprintf(g, "if shift != 0 { cpu.Cpsr.SetC((rd >> (shift-1)) & 1 != 0) }\n")
+		fmt.Fprintf(g, "if shift != 0 { cpu.Cpsr.SetCBit(rd >> (shift-1)) }\n")
 		fmt.Fprintf(g, "res := rd >> shift\n")
 	case 4: // ASR
 		fmt.Fprintf(g, "shift := (rs&0xFF)\n")
-		fmt.Fprintf(g, "if shift != 0 { cpu.Cpsr.SetC((int32(rd) >> (shift-1)) & 1 != 0) }\n")
+		fmt.Fprintf(g, "if shift != 0 { cpu.Cpsr.SetCBit(uint32(int32(rd) >> (shift-1))) }\n")
 		fmt.Fprintf(g, "res := uint32(int32(rd) >> shift)\n")
 	case 5: // ADC
 		fmt.Fprintf(g, "cf := cpu.Cpsr.CB()\n")
 		fmt.Fprintf(g, "res := rd + rs\n")
 		fmt.Fprintf(g, "res += cf\n")
-		fmt.Fprintf(g, "if cf == 0 {\n")
-		fmt.Fprintf(g, "  cpu.Cpsr.SetC(res<rd)\n")
-		fmt.Fprintf(g, "} else {\n")
-		fmt.Fprintf(g, "  cpu.Cpsr.SetC(res<=rd)\n")
-		fmt.Fprintf(g, "}\n")
+		fmt.Fprintf(g, "cpu.Cpsr.SetCAdd(rd, rs, cf)\n")
 		fmt.Fprintf(g, "cpu.Cpsr.SetVAdd(rd, rs, res)\n")
 	case 6: // SBC
 		fmt.Fprintf(g, "cf := cpu.Cpsr.CB()\n")
 		fmt.Fprintf(g, "res := rd - rs\n")
 		fmt.Fprintf(g, "res += cf-1\n")
-		fmt.Fprintf(g, "if cf == 0 {\n")
-		fmt.Fprintf(g, "  cpu.Cpsr.SetC(rd>rs)\n")
-		fmt.Fprintf(g, "} else {\n")
-		fmt.Fprintf(g, "  cpu.Cpsr.SetC(rd>=rs)\n")
-		fmt.Fprintf(g, "}\n")
+		fmt.Fprintf(g, "cpu.Cpsr.SetCSub(rd, rs, cf)\n")
 		fmt.Fprintf(g, "cpu.Cpsr.SetVSub(rd, rs, res)\n")
 	case 7: // ROR
 		fmt.Fprintf(g, "rot := (rs&0xFF)\n")
-		fmt.Fprintf(g, "if rot != 0 { cpu.Cpsr.SetC((rd << (rot-1)) & 0x80000000 != 0) }\n")
+		fmt.Fprintf(g, "if rot != 0 { cpu.Cpsr.SetCBit(rd >> ((rot-1)&31)) }\n")
 		fmt.Fprintf(g, "rot = (rs&0x1F)\n")
 		fmt.Fprintf(g, "res := (rd >> rot) | (rd << (32-rot))\n")
 	case 9: // NEG
 		fmt.Fprintf(g, "res := 0 - rs\n")
-		fmt.Fprintf(g, "cpu.Cpsr.SetC(false)\n")
+		fmt.Fprintf(g, "cpu.Cpsr.SetC(rs == 0)\n")
 		fmt.Fprintf(g, "cpu.Cpsr.SetVSub(0, rs, res)\n")
 	case 10: // CMP
 		test = true
@@ -724,70 +783,41 @@ func (g *Generator) WriteAluOp(op uint16) {
 	g.writeOpAluFooter(op)
 }
 
-func (g *Generator) WriteOp(op uint16) {
-	g.WriteOpHeader(int((op >> 8) & 0xFF))
-
-	oph := op >> 8
-	switch {
-	case oph>>5 == 0x0 && (oph>>3)&3 != 3: // F1
-		g.writeOpF1Shift(op)
-
-	case oph>>5 == 0x0 && (oph>>3)&3 == 3: // F2
-		g.writeOpF2Add(op)
-
-	case oph>>5 == 0x1: // F3
-		g.writeOpF3AluImm(op)
-
-	case oph>>2 == 0x10: // F4
-		g.writeOpF4Alu(op)
-
-	case oph>>2 == 0x11: // F5
-		g.writeOpF5HiReg(op)
-
-	case oph>>3 == 0x9: // F6
-		g.writeOpF6LdrPc(op)
-
-	case oph>>4 == 0x5: // F7 & F8
-		g.writeOpF7F8LdrStr(op)
-
-	case oph>>5 == 0x3: // F9
-		g.writeOpF9Strb(op)
-
-	case oph>>4 == 0x8: // F10
-		g.writeOpF10Strh(op)
-
-	case oph>>4 == 0x9: // F11
-		g.writeOpF11Strsp(op)
-
-	case oph>>4 == 0xA: // F12
-		g.writeOpF12AddPc(op)
-
-	case oph>>4 == 0xB && oph&0xF == 0: // F13
-		g.writeOpF13AddSp(op)
-
-	case oph>>4 == 0xB && oph&6 == 4: // F14
-		g.writeOpF14PushPop(op)
-
-	case oph>>4 == 0xC: // F15
-		g.writeOpF15LdmStm(op)
-
-	case oph>>4 == 0xD: // F16
-		g.writeOpF16BranchCond(op)
-
-	case oph>>3 == 0x1C: // F18
-		g.writeOpF18Branch(op)
-
-	case oph>>3 == 0x1E: // F19
-		g.writeOpF19LongBranch1(op)
-	case oph>>3 == 0x1F || oph>>3 == 0x1D: // F19
-		g.writeOpF19LongBranch2(op)
-
-	default:
-		g.WriteOpInvalid("not implemented")
-		g.WriteDisasmInvalid()
+func thumbGen(gen func(op uint16)) func(op uint32) {
+	return func(op uint32) { gen(uint16(op)) }
+}
+
+// Opcodes are dispatched through a table indexed by the high byte of the
+// opcode; masks and values below refer to that 8-bit index. Order is important:
+// the first match wins.
+//
+// The prefixes 11101, 11110 and 11111 are the halves of BL/BLX, which are
+// decoded here as two separate 16-bit opcodes. Later architectures (Thumb2)
+// reuse these prefixes for all 32-bit encodings, so they are kept as distinct
+// entries instead of being folded into the surrounding groups.
+func (g *Generator) ops() []cpugen.OpDesc {
+	return []cpugen.OpDesc{
+		{Name: "F2 add/sub", Mask: 0xF8, Value: 0x18, Gen: thumbGen(g.writeOpF2Add)},
+		{Name: "F1 shift", Mask: 0xE0, Value: 0x00, Gen: thumbGen(g.writeOpF1Shift)},
+		{Name: "F3 alu imm", Mask: 0xE0, Value: 0x20, Gen: thumbGen(g.writeOpF3AluImm)},
+		{Name: "F4 alu", Mask: 0xFC, Value: 0x40, Gen: thumbGen(g.writeOpF4Alu)},
+		{Name: "F5 hireg/bx", Mask: 0xFC, Value: 0x44, Gen: thumbGen(g.writeOpF5HiReg)},
+		{Name: "F6 ldr pc", Mask: 0xF8, Value: 0x48, Gen: thumbGen(g.writeOpF6LdrPc)},
+		{Name: "F7/F8 ldr/str reg", Mask: 0xF0, Value: 0x50, Gen: thumbGen(g.writeOpF7F8LdrStr)},
+		{Name: "F9 ldr/str imm", Mask: 0xE0, Value: 0x60, Gen: thumbGen(g.writeOpF9Strb)},
+		{Name: "F10 ldrh/strh imm", Mask: 0xF0, Value: 0x80, Gen: thumbGen(g.writeOpF10Strh)},
+		{Name: "F11 ldr/str sp", Mask: 0xF0, Value: 0x90, Gen: thumbGen(g.writeOpF11Strsp)},
+		{Name: "F12 add pc/sp", Mask: 0xF0, Value: 0xA0, Gen: thumbGen(g.writeOpF12AddPc)},
+		{Name: "F13 add sp", Mask: 0xFF, Value: 0xB0, Gen: thumbGen(g.writeOpF13AddSp)},
+		{Name: "F14 push/pop", Mask: 0xF6, Value: 0xB4, Gen: thumbGen(g.writeOpF14PushPop)},
+		{Name: "F15 ldm/stm", Mask: 0xF0, Value: 0xC0, Gen: thumbGen(g.writeOpF15LdmStm)},
+		{Name: "F17 bkpt", Mask: 0xFF, Value: 0xBE, Gen: thumbGen(g.writeOpF17Bkpt)},
+		{Name: "F16 b cond/swi", Mask: 0xF0, Value: 0xD0, Gen: thumbGen(g.writeOpF16BranchCond)},
+		{Name: "F18 b", Mask: 0xF8, Value: 0xE0, Gen: thumbGen(g.writeOpF18Branch)},
+		{Name: "F19 bl/blx (1st half)", Mask: 0xF8, Value: 0xF0, Gen: thumbGen(g.writeOpF19LongBranch1)},
+		{Name: "F19 bl (2nd half)", Mask: 0xF8, Value: 0xF8, Gen: thumbGen(g.writeOpF19LongBranch2)},
+		{Name: "F19 blx (2nd half)", Mask: 0xF8, Value: 0xE8, Gen: thumbGen(g.writeOpF19LongBranch2)},
 	}
-
-	g.WriteOpFooter(int((op >> 8) & 0xFF))
 }
 
 func main() {
@@ -799,8 +829,9 @@ func main() {
 		out.PcRelOff = 4
 		out.TableBits = 8
 		out.WriteHeader()
+		ops := out.ops()
 		for op := 0; op < 0x100; op++ {
-			out.WriteOp(uint16(op << 8))
+			out.WriteOpFromTable(ops, op, uint32(op<<8))
 		}
 		for op := 0; op < 0x10; op++ {
 			out.WriteAluOp(uint16(op<<6) | uint16(0x10<<10))
@@ -0,0 +1,65 @@
+package arm
+
+import "testing"
+
+var testHaltCode = []uint32{
+	0xEE070F90, // 00: mcr p15, 0, r0, c7, c0, 4 (wait for interrupt)
+	0xE2811001, // 04: add r1, r1, #1
+	0xEAFFFFFE, // 08: b   08
+}
+
+func TestHaltWaitForInterrupt(t *testing.T) {
+	cpu := NewCpu(ARMv5, newTestBus(testHaltCode, nil, 0))
+	cpu.EnableCp15()
+	cpu.Cpsr.SetI(true)
+	cpu.SetPC(0)
+
+	// The halted CPU skips to the target without executing opcodes
+	cpu.Run(1000)
+	if !cpu.Halted() || cpu.Clock != 1000 || cpu.Regs[1] != 0 {
+		t.Fatalf("halt: halted=%v clock=%v r1=%v", cpu.Halted(), cpu.Clock, cpu.Regs[1])
+	}
+	cpu.Run(2000)
+	if cpu.Clock != 2000 || cpu.Regs[1] != 0 {
+		t.Fatalf("halt: clock=%v r1=%v", cpu.Clock, cpu.Regs[1])
+	}
+
+	// An IRQ releases the halt even if masked in CPSR, and execution
+	// resumes after the MCR
+	cpu.SetLine(LineIrq, true)
+	if cpu.Halted() {
+		t.Fatalf("IRQ didn't release the halt")
+	}
+	cpu.Run(3000)
+	if cpu.Regs[1] != 1 || cpu.Cpsr.GetMode() != CpuModeSupervisor {
+		t.Errorf("resume: r1=%v mode=%v", cpu.Regs[1], cpu.Cpsr.GetMode())
+	}
+}
+
+func TestHaltWithPendingIrq(t *testing.T) {
+	cpu := NewCpu(ARMv5, newTestBus(testHaltCode, nil, 0))
+	cpu.EnableCp15()
+	cpu.Cpsr.SetI(true)
+	cpu.SetLine(LineIrq, true)
+	cpu.SetPC(0)
+
+	cpu.Run(1000)
+	if cpu.Halted() || cpu.Regs[1] != 1 {
+		t.Errorf("halted with a pending IRQ: halted=%v r1=%v", cpu.Halted(), cpu.Regs[1])
+	}
+}
+
+func TestHaltHook(t *testing.T) {
+	cpu := NewCpu(ARMv5, newTestBus(testHaltCode, nil, 0))
+	cp15 := cpu.EnableCp15()
+	halts := 0
+	cp15.SetHaltHook(func() { halts++ })
+	cpu.Cpsr.SetI(true)
+	cpu.SetPC(0)
+
+	// The hook decides whether to halt: here it doesn't
+	cpu.Run(1000)
+	if halts != 1 || cpu.Halted() || cpu.Regs[1] != 1 {
+		t.Errorf("hook: calls=%v halted=%v r1=%v", halts, cpu.Halted(), cpu.Regs[1])
+	}
+}
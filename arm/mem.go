@@ -7,8 +7,8 @@ import "ndsemu/emu"
 //
 // 	1) Check if there is a debugger installed, and call the wathcpoint
 // 	2) Check if the address is misaligned, and handle it the way the CPU does
-// 	3) Check if the address falls within DTCM or ITCM (if there is a CP15 and
-// 	they are active).
+// 	3) If there is a CP15, check if the protection unit allows the access,
+// 	and if the address falls within DTCM or ITCM (if they are active).
 //
 // 	The code isn't pretty because it is manually optimized.
 // 	DO NOT REFACTOR WITHOUT RUNNING MICRO-BENCHMARKS
@@ -22,6 +22,77 @@ func (cpu *Cpu) opFetchPointer(addr uint32) []uint8 {
 	return cpu.bus.FetchPointer(addr)
 }
 
+// Account for the cycles of an access to the external bus. Accesses are
+// sequential if they immediately follow an access to the previous address,
+// without any other cycle in between (that is: within the same LDM/STM).
+// On the ARM946, the access might instead be served by the data cache or
+// the write buffer.
+//
+// It also marks the access as in flight, until the caller clears busActive
+// after the bus returns (see BusError).
+func (cpu *Cpu) busAccess(addr uint32, width uint32, write bool) {
+	cpu.busAddr, cpu.busActive = addr, true
+	if cpu.cp15 != nil {
+		if cycles, ok := cpu.cp15.dataAccess(addr, write); ok {
+			cpu.Clock += cycles
+			return
+		}
+	}
+	if cpu.timings == nil {
+		cpu.Clock += cpu.memCycles
+		return
+	}
+	t := &cpu.timings[addr>>24]
+	seq := addr == cpu.seqAddr && cpu.Clock == cpu.seqClock
+	var cycles int
+	switch {
+	case width == 4 && seq:
+		cycles = t.S32
+	case width == 4:
+		cycles = t.N32
+	case seq:
+		cycles = t.S16
+	default:
+		cycles = t.N16
+	}
+	cpu.Clock += int64(cycles)
+	cpu.seqAddr, cpu.seqClock = addr+width, cpu.Clock
+}
+
+// Return the cycles taken to fetch each opcode (sequentially) at the current
+// PC, and account for the refill of the pipeline if a branch was taken (one
+// non-sequential and one sequential fetch, of which branch() already
+// accounted one cycle each). This is called before entering the tight loop.
+//
+// On the ARM946, code in ITCM is fetched in 1 cycle. If it's fetched
+// through the instruction cache, fetches take 1 cycle as well, and icache
+// is true: the tight loop must then call Cp15.icacheFetch on each new cache
+// line, to account for line fills.
+func (cpu *Cpu) fetchCycles() (fetch int64, icache bool) {
+	refill := cpu.refill
+	cpu.refill = false
+	if cpu.cp15 != nil {
+		if cpu.cp15.CheckITcm(uint32(cpu.pc)) != nil {
+			return 1, false
+		}
+		if cpu.cp15.icacheActive(uint32(cpu.pc)) {
+			return 1, true
+		}
+	}
+	if cpu.timings == nil {
+		return 1, false
+	}
+	t := &cpu.timings[cpu.pc>>24]
+	n, s := t.N32, t.S32
+	if cpu.Cpsr.T() {
+		n, s = t.N16, t.S16
+	}
+	if refill {
+		cpu.Clock += int64(n + s - 2)
+	}
+	return int64(s), false
+}
+
 func (cpu *Cpu) Read32(addr uint32) uint32 {
 	if cpu.dbg != nil {
 		cpu.dbg.WatchRead(addr)
@@ -33,6 +104,10 @@ func (cpu *Cpu) Read32(addr uint32) uint32 {
 	addr &^= 3
 
 	if cpu.cp15 != nil {
+		if !cpu.cp15.dataAllowed(addr, false) {
+			cpu.dataAbort(addr)
+			return 0
+		}
 		ptr := cpu.cp15.CheckITcm(addr)
 		if ptr == nil {
 			ptr = cpu.cp15.CheckDTcm(addr)
@@ -46,8 +121,10 @@ func (cpu *Cpu) Read32(addr uint32) uint32 {
 	}
 
 nodtcm:
-	cpu.Clock += cpu.memCycles
-	return cpu.bus.Read32(addr)
+	cpu.busAccess(addr, 4, false)
+	val := cpu.bus.Read32(addr)
+	cpu.busActive = false
+	return val
 }
 
 func (cpu *Cpu) Write32(addr uint32, val uint32) {
@@ -59,6 +136,10 @@ func (cpu *Cpu) Write32(addr uint32, val uint32) {
 	addr &^= 3
 
 	if cpu.cp15 != nil {
+		if !cpu.cp15.dataAllowed(addr, true) {
+			cpu.dataAbort(addr)
+			return
+		}
 		ptr := cpu.cp15.CheckITcm(addr)
 		if ptr == nil {
 			ptr = cpu.cp15.CheckDTcm(addr)
@@ -73,8 +154,9 @@ func (cpu *Cpu) Write32(addr uint32, val uint32) {
 	}
 
 nodtcm:
-	cpu.Clock += cpu.memCycles
+	cpu.busAccess(addr, 4, true)
 	cpu.bus.Write32(addr, val)
+	cpu.busActive = false
 }
 
 func (cpu *Cpu) Read16(addr uint32) uint16 {
@@ -88,6 +170,10 @@ func (cpu *Cpu) Read16(addr uint32) uint16 {
 	addr &^= 1
 
 	if cpu.cp15 != nil {
+		if !cpu.cp15.dataAllowed(addr, false) {
+			cpu.dataAbort(addr)
+			return 0
+		}
 		ptr := cpu.cp15.CheckITcm(addr)
 		if ptr == nil {
 			ptr = cpu.cp15.CheckDTcm(addr)
@@ -100,8 +186,10 @@ func (cpu *Cpu) Read16(addr uint32) uint16 {
 	}
 
 nodtcm:
-	cpu.Clock += cpu.memCycles
-	return cpu.bus.Read16(addr)
+	cpu.busAccess(addr, 2, false)
+	val := cpu.bus.Read16(addr)
+	cpu.busActive = false
+	return val
 }
 
 func (cpu *Cpu) Write16(addr uint32, val uint16) {
@@ -113,6 +201,10 @@ func (cpu *Cpu) Write16(addr uint32, val uint16) {
 	addr &^= 1
 
 	if cpu.cp15 != nil {
+		if !cpu.cp15.dataAllowed(addr, true) {
+			cpu.dataAbort(addr)
+			return
+		}
 		ptr := cpu.cp15.CheckITcm(addr)
 		if ptr == nil {
 			ptr = cpu.cp15.CheckDTcm(addr)
@@ -125,8 +217,9 @@ func (cpu *Cpu) Write16(addr uint32, val uint16) {
 		return
 	}
 nodtcm:
-	cpu.Clock += cpu.memCycles
+	cpu.busAccess(addr, 2, true)
 	cpu.bus.Write16(addr, val)
+	cpu.busActive = false
 }
 
 func (cpu *Cpu) Read8(addr uint32) uint8 {
@@ -135,6 +228,10 @@ func (cpu *Cpu) Read8(addr uint32) uint8 {
 	}
 	cpu.Clock += 1
 	if cpu.cp15 != nil {
+		if !cpu.cp15.dataAllowed(addr, false) {
+			cpu.dataAbort(addr)
+			return 0
+		}
 		ptr := cpu.cp15.CheckITcm(addr)
 		if ptr == nil {
 			ptr = cpu.cp15.CheckDTcm(addr)
@@ -146,8 +243,10 @@ func (cpu *Cpu) Read8(addr uint32) uint8 {
 		return ptr[0]
 	}
 nodtcm:
-	cpu.Clock += cpu.memCycles
-	return cpu.bus.Read8(addr)
+	cpu.busAccess(addr, 1, false)
+	val := cpu.bus.Read8(addr)
+	cpu.busActive = false
+	return val
 }
 
 func (cpu *Cpu) Write8(addr uint32, val uint8) {
@@ -156,6 +255,10 @@ func (cpu *Cpu) Write8(addr uint32, val uint8) {
 	}
 	cpu.Clock += 1
 	if cpu.cp15 != nil {
+		if !cpu.cp15.dataAllowed(addr, true) {
+			cpu.dataAbort(addr)
+			return
+		}
 		ptr := cpu.cp15.CheckITcm(addr)
 		if ptr == nil {
 			ptr = cpu.cp15.CheckDTcm(addr)
@@ -168,6 +271,7 @@ func (cpu *Cpu) Write8(addr uint32, val uint8) {
 		return
 	}
 nodtcm:
-	cpu.Clock += cpu.memCycles
+	cpu.busAccess(addr, 1, true)
 	cpu.bus.Write8(addr, val)
+	cpu.busActive = false
 }
@@ -0,0 +1,89 @@
+package arm
+
+import (
+	"ndsemu/emu"
+	"testing"
+)
+
+// Test bus with different timings for each region
+type timedTestBus struct {
+	testBus
+	timings [256]emu.AccessTimings
+}
+
+func (b *timedTestBus) RegionTimings() *[256]emu.AccessTimings { return &b.timings }
+
+func TestBusTimings(t *testing.T) {
+	bus := &timedTestBus{testBus: *newTestBus(nil, nil, 0)}
+	for i := range bus.timings {
+		bus.timings[i] = emu.AccessTimings{N16: 1, S16: 1, N32: 1, S32: 1}
+	}
+	bus.timings[2] = emu.AccessTimings{N16: 8, S16: 1, N32: 9, S32: 2}
+	cpu := NewCpu(ARMv4, bus)
+
+	tests := []struct {
+		name   string
+		access func()
+		want   int64
+	}{
+		{"fast", func() { cpu.Read32(0x1000) }, 1},
+		{"read32", func() { cpu.Read32(0x02000000) }, 9},
+		{"write32", func() { cpu.Write32(0x02000000, 0) }, 9},
+		{"ldm", func() {
+			for i := uint32(0); i < 4; i++ {
+				cpu.Read32(0x02000000 + i*4)
+			}
+		}, 9 + 3*2},
+		{"interleaved", func() {
+			cpu.Read32(0x02000000)
+			cpu.Clock++
+			cpu.Read32(0x02000004)
+		}, 9 + 1 + 9},
+		{"nonseq", func() {
+			cpu.Read32(0x02000000)
+			cpu.Read32(0x02000010)
+		}, 9 + 9},
+	}
+	for _, test := range tests {
+		start := cpu.Clock
+		test.access()
+		if got := cpu.Clock - start; got != test.want {
+			t.Errorf("%s: %d cycles, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFetchTimings(t *testing.T) {
+	// Same loop (3 iterations) in a fast and in a slow region
+	loop := []uint32{
+		0xE3A01003, // 0: mov  r1, #3
+		0xE2511001, // 4: subs r1, r1, #1
+		0x1AFFFFFD, // 8: bne  4
+		0xEAFFFFFE, // C: b    .
+	}
+	run := func(base uint32) int64 {
+		bus := &timedTestBus{testBus: *newTestBus(nil, nil, 0)}
+		for i, op := range loop {
+			bus.Write32(base+uint32(i)*4, op)
+		}
+		for i := range bus.timings {
+			bus.timings[i] = emu.AccessTimings{N16: 1, S16: 1, N32: 1, S32: 1}
+		}
+		bus.timings[2] = emu.AccessTimings{N16: 8, S16: 1, N32: 9, S32: 2}
+		cpu := NewCpu(ARMv4, bus)
+		cpu.SetPC(base)
+		// Run one opcode at a time, until the loop exits
+		for cpu.Regs[1] != 0 || cpu.pc != reg(base+0xC) {
+			cpu.Run(cpu.Clock + 1)
+		}
+		return cpu.Clock
+	}
+
+	fast, slow := run(0x1000), run(0x02000000)
+	// 7 opcodes are fetched sequentially (1 extra cycle each), and the
+	// pipeline is refilled twice after the backward branch (N+S: 9 extra
+	// cycles each time)
+	if want := fast + 7*1 + 2*9; slow != want {
+		t.Errorf("slow loop: %d cycles, want %d (fast: %d)", slow, want, fast)
+	}
+}
@@ -0,0 +1,155 @@
+package arm
+
+// Emulation of the protection unit of the ARM946E-S. When enabled, each
+// access is checked against the 8 regions configured in CP15 C6 (regions
+// with higher numbers have priority where they overlap). The region
+// decides the access permissions (C5), and whether the access is cached
+// (C2) and buffered (C3). Accesses not covered by any region, or not
+// allowed by its permissions, abort.
+//
+// To make lookups fast, the region covering each 4KB page is stored in a
+// table, which is rebuilt lazily after the configuration changes.
+
+// Attributes of a region
+const (
+	pageICache    = 1 << iota // instruction cacheable
+	pageDCache                // data cacheable
+	pageBuffer                // bufferable (data write-back if also cacheable)
+	pagePrivRead              // data read in privileged modes
+	pagePrivWrite             // data write in privileged modes
+	pageUserRead              // data read in user mode
+	pageUserWrite             // data write in user mode
+	pagePrivExec              // instruction fetch in privileged modes
+	pageUserExec              // instruction fetch in user mode
+)
+
+const (
+	puPageShift = 12
+	puPageSize  = 1 << puPageShift
+	puNoRegion  = 8 // pages not covered by any region
+)
+
+// Permissions for each value of the access permission fields (privileged
+// read/write, user read/write); reserved values grant no access.
+var puPermissions = [16]uint16{
+	0: 0,
+	1: pagePrivRead | pagePrivWrite,
+	2: pagePrivRead | pagePrivWrite | pageUserRead,
+	3: pagePrivRead | pagePrivWrite | pageUserRead | pageUserWrite,
+	5: pagePrivRead,
+	6: pagePrivRead | pageUserRead,
+}
+
+// Convert access permissions between the extended format (4 bits per
+// region, C5 opcode 2/3) and the legacy one (2 bits per region, opcode 0/1)
+func permToLegacy(val uint32) uint32 {
+	var res uint32
+	for r := uint(0); r < 8; r++ {
+		res |= ((val >> (r * 4)) & 3) << (r * 2)
+	}
+	return res
+}
+
+func permFromLegacy(val uint32) uint32 {
+	var res uint32
+	for r := uint(0); r < 8; r++ {
+		res |= ((val >> (r * 2)) & 3) << (r * 4)
+	}
+	return res
+}
+
+// The protection unit configuration changed: the attributes must be
+// recomputed, and the CPU must exit the tight loop as the code being
+// executed might not be accessible anymore.
+func (c *Cp15) puChanged() {
+	c.attrsDirty = true
+	c.cpu.tightExit = true
+}
+
+// Recompute the attributes of the regions and the pages they cover
+func (c *Cp15) updatePageAttrs() {
+	c.attrsDirty = false
+	for i := range c.pages {
+		c.pages[i] = puNoRegion
+	}
+	c.regionAttrs[puNoRegion] = 0
+	for r := 0; r < 8; r++ {
+		var attr uint16
+		if (c.regICacheable>>uint(r))&1 != 0 {
+			attr |= pageICache
+		}
+		if (c.regDCacheable>>uint(r))&1 != 0 {
+			attr |= pageDCache
+		}
+		if (c.regBufferable>>uint(r))&1 != 0 {
+			attr |= pageBuffer
+		}
+		dperm := puPermissions[(c.regDataPerm>>uint(r*4))&0xF]
+		iperm := puPermissions[(c.regInstrPerm>>uint(r*4))&0xF]
+		attr |= dperm
+		if iperm&pagePrivRead != 0 {
+			attr |= pagePrivExec
+		}
+		if iperm&pageUserRead != 0 {
+			attr |= pageUserExec
+		}
+		c.regionAttrs[r] = attr
+
+		val := uint32(c.regRegion[r])
+		if val&1 == 0 {
+			continue
+		}
+		size := uint64(2) << ((val >> 1) & 0x1F)
+		if size < puPageSize {
+			size = puPageSize
+		}
+		// The base must be aligned to the size
+		base := uint64(val&0xFFFFF000) &^ (size - 1)
+		for p := base >> puPageShift; p < (base+size)>>puPageShift && p < uint64(len(c.pages)); p++ {
+			c.pages[p] = uint8(r)
+		}
+	}
+}
+
+// Return the attributes of the page containing addr (zero if the
+// protection unit is disabled)
+func (c *Cp15) pageAttrs(addr uint32) uint16 {
+	if !c.puEnabled {
+		return 0
+	}
+	if c.attrsDirty {
+		c.updatePageAttrs()
+	}
+	return c.regionAttrs[c.pages[addr>>puPageShift]]
+}
+
+// Check whether a data access is allowed by the protection unit
+func (c *Cp15) dataAllowed(addr uint32, write bool) bool {
+	if !c.puEnabled {
+		return true
+	}
+	return c.checkData(addr, write)
+}
+
+func (c *Cp15) checkData(addr uint32, write bool) bool {
+	need := uint16(pagePrivRead)
+	if write {
+		need = pagePrivWrite
+	}
+	if c.cpu.Cpsr.GetMode() == CpuModeUser {
+		need <<= 2 // user permissions
+	}
+	return c.pageAttrs(addr)&need != 0
+}
+
+// Check whether opcodes can be fetched from the specified address
+func (c *Cp15) fetchAllowed(addr uint32) bool {
+	if !c.puEnabled {
+		return true
+	}
+	need := uint16(pagePrivExec)
+	if c.cpu.Cpsr.GetMode() == CpuModeUser {
+		need = pageUserExec
+	}
+	return c.pageAttrs(addr)&need != 0
+}
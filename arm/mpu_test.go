@@ -0,0 +1,109 @@
+package arm
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+var testMpuCode = []uint32{
+	0xE5910000, // 00: ldr r0, [r1]
+	0xE1A0F001, // 04: mov pc, r1
+	0xEAFFFFFE, // 08: b   08
+	0xEAFFFFFE, // 0C: b   0C (prefetch abort)
+	0xEAFFFFFE, // 10: b   10 (data abort)
+}
+
+func newMpuTestCpu() (*Cpu, *Cp15) {
+	cpu := NewCpu(ARMv5, newTestBus(testMpuCode, nil, 0))
+	cp15 := cpu.EnableCp15()
+
+	// Region 0: 0x0000-0x1FFF, data privileged RW / user RO, code always
+	// executable
+	cp15.Write(0, 6, 0, 0, 12<<1|1)
+	// Region 1: 0x1000-0x1FFF, no access
+	cp15.Write(0, 6, 1, 0, 0x1000|11<<1|1)
+	cp15.Write(0, 5, 0, 2, 0x02)
+	cp15.Write(0, 5, 0, 3, 0x03)
+	cp15.Write(0, 1, 0, 0, 0x78|1) // low vectors
+
+	cpu.Regs[1] = 0x1000
+	return cpu, cp15
+}
+
+func TestMpuPermissions(t *testing.T) {
+	cpu, cp15 := newMpuTestCpu()
+
+	tests := []struct {
+		addr  uint32
+		write bool
+		user  bool
+		want  bool
+	}{
+		{0x0800, false, false, true},
+		{0x0800, true, false, true},
+		{0x0800, false, true, true},
+		{0x0800, true, true, false},
+		{0x1000, false, false, false},     // region 1 has priority
+		{0x02000000, false, false, false}, // no region
+	}
+	for _, tt := range tests {
+		if tt.user {
+			cpu.Cpsr.SetMode(CpuModeUser, cpu)
+		} else {
+			cpu.Cpsr.SetMode(CpuModeSupervisor, cpu)
+		}
+		if got := cp15.dataAllowed(tt.addr, tt.write); got != tt.want {
+			t.Errorf("addr=%08x write=%v user=%v: allowed=%v, want %v", tt.addr, tt.write, tt.user, got, tt.want)
+		}
+	}
+
+	// Legacy view of the permissions
+	if v := cp15.Read(0, 5, 0, 0); v != 0x2 {
+		t.Errorf("legacy data permissions: %x", v)
+	}
+	cp15.Write(0, 5, 0, 1, 0xE)
+	if v := cp15.Read(0, 5, 0, 3); v != 0x32 {
+		t.Errorf("extended instruction permissions: %x", v)
+	}
+
+	// Disabling the protection unit allows everything
+	cp15.Write(0, 1, 0, 0, 0x78)
+	if !cp15.dataAllowed(0x1000, true) {
+		t.Errorf("access not allowed with the protection unit disabled")
+	}
+}
+
+func TestMpuAborts(t *testing.T) {
+	// ldr r0, [r1] in a region with no access
+	cpu, _ := newMpuTestCpu()
+	cpu.SetPC(0)
+	cpu.Run(100)
+	if cpu.Cpsr.GetMode() != CpuModeAbort || cpu.pc != 0x10 || cpu.Regs[14] != 0x8 {
+		t.Errorf("data abort: mode=%v pc=%v lr=%v", cpu.Cpsr.GetMode(), cpu.pc, cpu.Regs[14])
+	}
+
+	// mov pc, r1 into a region which is not executable
+	cpu, _ = newMpuTestCpu()
+	cpu.SetPC(4)
+	cpu.Run(100)
+	if cpu.Cpsr.GetMode() != CpuModeAbort || cpu.pc != 0xC || cpu.Regs[14] != 0x1004 {
+		t.Errorf("prefetch abort: mode=%v pc=%v lr=%v", cpu.Cpsr.GetMode(), cpu.pc, cpu.Regs[14])
+	}
+}
+
+func TestMpuThumbPageEnd(t *testing.T) {
+	// Thumb opcode in the last halfword of an executable page, followed by
+	// a page which is not executable: the fetch pointer is truncated to two
+	// bytes, and the zero opcode must not be mistaken for a jump to the
+	// zero area.
+	cpu, _ := newMpuTestCpu()
+	mem := cpu.bus.(*testBus).mem
+	binary.LittleEndian.PutUint16(mem[0xFFE:], 0x0000)  // movs r0, r0
+	binary.LittleEndian.PutUint16(mem[0x1000:], 0x46C0) // nop
+	cpu.Cpsr.SetT(true)
+	cpu.SetPC(0xFFE)
+	cpu.Run(100)
+	if cpu.Cpsr.GetMode() != CpuModeAbort || cpu.pc != 0xC || cpu.Regs[14] != 0x1004 {
+		t.Errorf("prefetch abort: mode=%v pc=%v lr=%v", cpu.Cpsr.GetMode(), cpu.pc, cpu.Regs[14])
+	}
+}
@@ -35,6 +35,18 @@ func popcount16(val uint16) uint {
 	return popcount8(uint8(val&0xFF)) + popcount8(uint8(val>>8))
 }
 
+// Saturate a 64-bit result to the signed 32-bit range (QADD and friends),
+// returning whether it saturated.
+func saturate32(val int64) (int32, bool) {
+	if val > 0x7FFFFFFF {
+		return 0x7FFFFFFF, true
+	}
+	if val < -0x80000000 {
+		return -0x80000000, true
+	}
+	return int32(val), false
+}
+
 // func init() {
 // 	for i := 0; i < 256; i++ {
 // 		cnt := 0
@@ -92,43 +104,30 @@ func (cpu *Cpu) opArmCond(cond uint) bool {
 	panic("unreachable")
 }
 
-func (cpu *Cpu) opCopRead(copnum uint32, op uint32, cn, cm, cp uint32) uint32 {
-	cop := cpu.cops[copnum]
-	if cop == nil {
-		log.WithFields(log.Fields{
-			"pc":  cpu.pc,
-			"cop": copnum,
-		}).Error("invalid coprocessor access")
-		return 0xFFFFFFFF
+// Check whether a coprocessor is missing; if so, the opcode is undefined:
+// raise the exception and return true, so that the opcode is aborted.
+func (cpu *Cpu) opCopMissing(copnum uint32) bool {
+	if cpu.cops[copnum] != nil {
+		return false
 	}
+	log.WithFields(log.Fields{
+		"pc":  cpu.pc - 4,
+		"cop": copnum,
+	}).Warn("access to missing coprocessor")
+	cpu.Exception(ExceptionUndefined)
+	return true
+}
 
-	return cop.Read(op, cn, cm, cp)
+func (cpu *Cpu) opCopRead(copnum uint32, op uint32, cn, cm, cp uint32) uint32 {
+	return cpu.cops[copnum].Read(op, cn, cm, cp)
 }
 
 func (cpu *Cpu) opCopWrite(copnum uint32, op uint32, cn, cm, cp uint32, value uint32) {
-	cop := cpu.cops[copnum]
-	if cop == nil {
-		log.WithFields(log.Fields{
-			"pc":  cpu.pc,
-			"cop": copnum,
-		}).Error("invalid coprocessor access")
-		return
-	}
-
-	cop.Write(op, cn, cm, cp, value)
+	cpu.cops[copnum].Write(op, cn, cm, cp, value)
 }
 
 func (cpu *Cpu) opCopExec(copnum uint32, op uint32, cn, cm, cp, cd uint32) {
-	cop := cpu.cops[copnum]
-	if cop == nil {
-		log.WithFields(log.Fields{
-			"pc":  cpu.pc,
-			"cop": copnum,
-		}).Error("invalid coprocessor access")
-		return
-	}
-
-	cop.Exec(op, cn, cm, cp, cd)
+	cpu.cops[copnum].Exec(op, cn, cm, cp, cd)
 }
 
 type BranchType int
@@ -142,6 +141,7 @@ const (
 
 func (cpu *Cpu) branch(newpc reg, reason BranchType) {
 	cpu.Clock += 2
+	cpu.refill = true
 	cpu.tightExit = true
 	cpu.prevpc = cpu.pc
 	cpu.pc = newpc
@@ -174,6 +174,9 @@ func (cpu *Cpu) Run(until int64) {
 	}
 
 	for cpu.Clock < cpu.targetCycles {
+		if cpu.abort {
+			cpu.takeDataAbort()
+		}
 		lines := cpu.lines
 		if lines&LineHalt != 0 {
 			cpu.Clock = cpu.targetCycles
@@ -211,10 +214,26 @@ func (cpu *Cpu) Run(until int64) {
 		if mem == nil {
 			cpu.breakpoint("ARMv%d jump to non-linear memory at %v", cpu.arch, cpu.pc)
 		}
-		if mem[0] == 0 && mem[1] == 0 && mem[2] == 0 && mem[3] == 0 {
+		// With the protection unit enabled, check that the code can be
+		// executed, and stop the tight loop at the end of the page, where
+		// permissions might change. The zero area check is done before
+		// truncating, as a thumb opcode at the end of the page would leave
+		// only two bytes.
+		if cpu.cp15 != nil && cpu.cp15.puEnabled && !cpu.cp15.fetchAllowed(uint32(cpu.pc)) {
+			cpu.Exception(ExceptionPrefetchAbort)
+			continue
+		}
+
+		if len(mem) >= 4 && mem[0] == 0 && mem[1] == 0 && mem[2] == 0 && mem[3] == 0 {
 			cpu.breakpoint("ARMv%d jump to 0 area at %v from %v", cpu.arch, cpu.pc, cpu.prevpc)
 		}
 
+		if cpu.cp15 != nil && cpu.cp15.puEnabled {
+			if n := puPageSize - int(cpu.pc&(puPageSize-1)); n < len(mem) {
+				mem = mem[:n]
+			}
+		}
+
 		// Welcome to the tight loop. This is the innest execution loop that is
 		// very very hot performance-wise. This loops is kept mostly for linear
 		// execution of opcodes. The fetch pointer is linearly incremented. We
@@ -240,6 +259,13 @@ func (cpu *Cpu) Run(until int64) {
 		//  for memory bounds (though in a more optimized way).
 		//
 		cpu.tightExit = false
+		fetch, icache := cpu.fetchCycles()
+		iline := ^reg(0)
+
+		if cpu.dcache != nil {
+			cpu.runCached(mem, trace, fetch, icache)
+			continue
+		}
 
 		if !cpu.Cpsr.T() {
 			for i := 0; i < len(mem)-3; i += 4 {
@@ -251,7 +277,11 @@ func (cpu *Cpu) Run(until int64) {
 				}
 
 				op := binary.LittleEndian.Uint32(mem[i:])
-				cpu.Clock++
+				cpu.Clock += fetch
+				if icache && (cpu.pc-4)&^(cacheLineSize-1) != iline {
+					iline = (cpu.pc - 4) &^ (cacheLineSize - 1)
+					cpu.Clock += cpu.cp15.icacheFetch(uint32(iline))
+				}
 
 				// Check the condition flags on each instruction (bits 28-31).
 				// * 0xE means always, and is by far the most common occurrence.
@@ -276,7 +306,11 @@ func (cpu *Cpu) Run(until int64) {
 				}
 
 				op := binary.LittleEndian.Uint16(mem[i:])
-				cpu.Clock++
+				cpu.Clock += fetch
+				if icache && (cpu.pc-2)&^(cacheLineSize-1) != iline {
+					iline = (cpu.pc - 2) &^ (cacheLineSize - 1)
+					cpu.Clock += cpu.cp15.icacheFetch(uint32(iline))
+				}
 
 				opThumbTable[op>>8](cpu, op)
 
@@ -286,6 +320,9 @@ func (cpu *Cpu) Run(until int64) {
 			}
 		}
 	}
+	if cpu.abort {
+		cpu.takeDataAbort()
+	}
 
 	cpu.Regs[15] = cpu.pc
 }
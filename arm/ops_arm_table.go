@@ -1,4 +1,4 @@
-// Generated on 2017-03-05 01:17:59.241905837 +0100 CET
+// Generated on 2026-10-16 15:12:30.025080086 +0000 UTC m=+0.000903755
 package arm
 
 import "bytes"
@@ -268,6 +268,10 @@ func (cpu *Cpu) opArm00B(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -302,12 +306,26 @@ func (cpu *Cpu) opArm00D(op uint32) {
 	}
 	off := uint32(cpu.Regs[rmx])
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -342,8 +360,16 @@ func (cpu *Cpu) opArm00F(op uint32) {
 	}
 	off := uint32(cpu.Regs[rmx])
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -378,7 +404,7 @@ func (cpu *Cpu) opArm010(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -423,7 +449,7 @@ func (cpu *Cpu) opArm011(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -451,7 +477,7 @@ func (cpu *Cpu) opArm012(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -480,7 +506,7 @@ func (cpu *Cpu) opArm013(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -508,7 +534,7 @@ func (cpu *Cpu) opArm014(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -537,7 +563,7 @@ func (cpu *Cpu) opArm015(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -562,13 +588,13 @@ func (cpu *Cpu) opArm016(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -598,7 +624,7 @@ func (cpu *Cpu) opArm017(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -665,6 +691,10 @@ func (cpu *Cpu) opArm01B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -705,6 +735,10 @@ func (cpu *Cpu) opArm01D(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -748,6 +782,10 @@ func (cpu *Cpu) opArm01F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -1043,7 +1081,7 @@ func (cpu *Cpu) opArm030(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1088,7 +1126,7 @@ func (cpu *Cpu) opArm031(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1116,7 +1154,7 @@ func (cpu *Cpu) opArm032(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -1145,7 +1183,7 @@ func (cpu *Cpu) opArm033(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1173,7 +1211,7 @@ func (cpu *Cpu) opArm034(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -1202,7 +1240,7 @@ func (cpu *Cpu) opArm035(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1227,13 +1265,13 @@ func (cpu *Cpu) opArm036(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -1263,7 +1301,7 @@ func (cpu *Cpu) opArm037(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -1551,6 +1589,10 @@ func (cpu *Cpu) opArm04B(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -1581,12 +1623,26 @@ func (cpu *Cpu) opArm04D(op uint32) {
 	cpu.Regs[15] += 4
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -1617,8 +1673,16 @@ func (cpu *Cpu) opArm04F(op uint32) {
 	cpu.Regs[15] += 4
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -1654,7 +1718,7 @@ func (cpu *Cpu) opArm050(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1701,7 +1765,7 @@ func (cpu *Cpu) opArm051(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1731,7 +1795,7 @@ func (cpu *Cpu) opArm052(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -1762,7 +1826,7 @@ func (cpu *Cpu) opArm053(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1792,7 +1856,7 @@ func (cpu *Cpu) opArm054(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -1823,7 +1887,7 @@ func (cpu *Cpu) opArm055(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -1850,13 +1914,13 @@ func (cpu *Cpu) opArm056(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -1888,7 +1952,7 @@ func (cpu *Cpu) opArm057(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -1916,6 +1980,10 @@ func (cpu *Cpu) opArm05B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -1952,6 +2020,10 @@ func (cpu *Cpu) opArm05D(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -1991,6 +2063,10 @@ func (cpu *Cpu) opArm05F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -2247,7 +2323,7 @@ func (cpu *Cpu) opArm070(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -2294,7 +2370,7 @@ func (cpu *Cpu) opArm071(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -2324,7 +2400,7 @@ func (cpu *Cpu) opArm072(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := op2 - rn
@@ -2355,7 +2431,7 @@ func (cpu *Cpu) opArm073(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -2385,7 +2461,7 @@ func (cpu *Cpu) opArm074(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := op2 - rn
@@ -2416,7 +2492,7 @@ func (cpu *Cpu) opArm075(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -2443,13 +2519,13 @@ func (cpu *Cpu) opArm076(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := op2 - rn
@@ -2481,7 +2557,7 @@ func (cpu *Cpu) opArm077(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := op2 - rn
@@ -2768,6 +2844,10 @@ func (cpu *Cpu) opArm08B(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -2802,12 +2882,26 @@ func (cpu *Cpu) opArm08D(op uint32) {
 	}
 	off := uint32(cpu.Regs[rmx])
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -2842,8 +2936,16 @@ func (cpu *Cpu) opArm08F(op uint32) {
 	}
 	off := uint32(cpu.Regs[rmx])
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -2878,7 +2980,7 @@ func (cpu *Cpu) opArm090(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -2925,7 +3027,7 @@ func (cpu *Cpu) opArm091(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -2955,7 +3057,7 @@ func (cpu *Cpu) opArm092(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -2986,7 +3088,7 @@ func (cpu *Cpu) opArm093(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -3016,7 +3118,7 @@ func (cpu *Cpu) opArm094(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -3047,7 +3149,7 @@ func (cpu *Cpu) opArm095(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -3074,13 +3176,13 @@ func (cpu *Cpu) opArm096(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -3112,7 +3214,7 @@ func (cpu *Cpu) opArm097(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -3187,6 +3289,10 @@ func (cpu *Cpu) opArm09B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -3227,6 +3333,10 @@ func (cpu *Cpu) opArm09D(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -3270,6 +3380,10 @@ func (cpu *Cpu) opArm09F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -3576,17 +3690,13 @@ func (cpu *Cpu) opArm0B0(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3628,17 +3738,13 @@ func (cpu *Cpu) opArm0B1(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3663,16 +3769,12 @@ func (cpu *Cpu) opArm0B2(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3699,17 +3801,13 @@ func (cpu *Cpu) opArm0B3(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3734,16 +3832,12 @@ func (cpu *Cpu) opArm0B4(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3770,17 +3864,13 @@ func (cpu *Cpu) opArm0B5(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3802,22 +3892,18 @@ func (cpu *Cpu) opArm0B6(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3845,16 +3931,12 @@ func (cpu *Cpu) opArm0B7(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4184,6 +4266,10 @@ func (cpu *Cpu) opArm0CB(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -4214,12 +4300,26 @@ func (cpu *Cpu) opArm0CD(op uint32) {
 	cpu.Regs[15] += 4
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -4250,8 +4350,16 @@ func (cpu *Cpu) opArm0CF(op uint32) {
 	cpu.Regs[15] += 4
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -4287,17 +4395,13 @@ func (cpu *Cpu) opArm0D0(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4339,17 +4443,13 @@ func (cpu *Cpu) opArm0D1(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4374,16 +4474,12 @@ func (cpu *Cpu) opArm0D2(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4410,17 +4506,13 @@ func (cpu *Cpu) opArm0D3(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4445,16 +4537,12 @@ func (cpu *Cpu) opArm0D4(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4481,17 +4569,13 @@ func (cpu *Cpu) opArm0D5(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4513,22 +4597,18 @@ func (cpu *Cpu) opArm0D6(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4556,16 +4636,12 @@ func (cpu *Cpu) opArm0D7(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -4631,6 +4707,10 @@ func (cpu *Cpu) opArm0DB(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -4667,6 +4747,10 @@ func (cpu *Cpu) opArm0DD(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -4706,6 +4790,10 @@ func (cpu *Cpu) opArm0DF(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -5021,18 +5109,14 @@ func (cpu *Cpu) opArm0F0(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5074,18 +5158,14 @@ func (cpu *Cpu) opArm0F1(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5110,17 +5190,13 @@ func (cpu *Cpu) opArm0F2(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5147,18 +5223,14 @@ func (cpu *Cpu) opArm0F3(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5183,17 +5255,13 @@ func (cpu *Cpu) opArm0F4(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5220,18 +5288,14 @@ func (cpu *Cpu) opArm0F5(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5253,23 +5317,19 @@ func (cpu *Cpu) opArm0F6(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5297,17 +5357,13 @@ func (cpu *Cpu) opArm0F7(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -5398,10 +5454,45 @@ func (cpu *Cpu) opArm101(op uint32) {
 	cpu.InvalidOpArm(op, "invalid ALU test function without flags")
 }
 
+func (cpu *Cpu) opArm105(op uint32) {
+	// qadd
+	if op&0x0F900FF0 != 0x01000050 {
+		cpu.InvalidOpArm(op, "invalid opcode decoded as QADD/QSUB")
+		return
+	}
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rm := int64(int32(cpu.Regs[op&0xF]))
+	rn := int64(int32(cpu.Regs[(op>>16)&0xF]))
+	res, sat := saturate32(rm + rn)
+	if sat {
+		cpu.Cpsr.SetQ(true)
+	}
+	cpu.Regs[(op>>12)&0xF] = reg(res)
+	cpu.Clock += 1
+}
+
+func (cpu *Cpu) disasmArm105(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("qadd", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := op & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg2])
+	return out.String()
+}
+
 func (cpu *Cpu) opArm108(op uint32) {
 	// smlabb
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -5409,12 +5500,17 @@ func (cpu *Cpu) opArm108(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	rnx := (op >> 12) & 0xF
 	rn := uint32(cpu.Regs[rnx])
 	hrm := int16(rm & 0xFFFF)
 	hrs := int16(rs & 0xFFFF)
 	res := reg(int32(hrm) * int32(hrs))
-	res += reg(rn)
+	acc := res + reg(rn)
+	if (^(uint32(res)^rn)&(uint32(acc)^rn))>>31 != 0 {
+		cpu.Cpsr.SetQ(true)
+	}
+	res = acc
 	cpu.Regs[rdx] = reg(res)
 }
 
@@ -5452,6 +5548,10 @@ func (cpu *Cpu) opArm109(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = res
 	cpu.Write32(rn, rm)
 	cpu.Clock += 1
@@ -5477,7 +5577,7 @@ func (cpu *Cpu) disasmArm109(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm10A(op uint32) {
 	// smlatb
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -5485,12 +5585,17 @@ func (cpu *Cpu) opArm10A(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	rnx := (op >> 12) & 0xF
 	rn := uint32(cpu.Regs[rnx])
 	hrm := int16(rm >> 16)
 	hrs := int16(rs & 0xFFFF)
 	res := reg(int32(hrm) * int32(hrs))
-	res += reg(rn)
+	acc := res + reg(rn)
+	if (^(uint32(res)^rn)&(uint32(acc)^rn))>>31 != 0 {
+		cpu.Cpsr.SetQ(true)
+	}
+	res = acc
 	cpu.Regs[rdx] = reg(res)
 }
 
@@ -5549,7 +5654,7 @@ func (cpu *Cpu) disasmArm10B(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm10C(op uint32) {
 	// smlabt
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -5557,12 +5662,17 @@ func (cpu *Cpu) opArm10C(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	rnx := (op >> 12) & 0xF
 	rn := uint32(cpu.Regs[rnx])
 	hrm := int16(rm & 0xFFFF)
 	hrs := int16(rs >> 16)
 	res := reg(int32(hrm) * int32(hrs))
-	res += reg(rn)
+	acc := res + reg(rn)
+	if (^(uint32(res)^rn)&(uint32(acc)^rn))>>31 != 0 {
+		cpu.Cpsr.SetQ(true)
+	}
+	res = acc
 	cpu.Regs[rdx] = reg(res)
 }
 
@@ -5597,8 +5707,18 @@ func (cpu *Cpu) opArm10D(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn -= off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
@@ -5626,7 +5746,7 @@ func (cpu *Cpu) disasmArm10D(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm10E(op uint32) {
 	// smlatt
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -5634,12 +5754,17 @@ func (cpu *Cpu) opArm10E(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	rnx := (op >> 12) & 0xF
 	rn := uint32(cpu.Regs[rnx])
 	hrm := int16(rm >> 16)
 	hrs := int16(rs >> 16)
 	res := reg(int32(hrm) * int32(hrs))
-	res += reg(rn)
+	acc := res + reg(rn)
+	if (^(uint32(res)^rn)&(uint32(acc)^rn))>>31 != 0 {
+		cpu.Cpsr.SetQ(true)
+	}
+	res = acc
 	cpu.Regs[rdx] = reg(res)
 }
 
@@ -5674,6 +5799,10 @@ func (cpu *Cpu) opArm10F(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn -= off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
 	cpu.Clock += 1
@@ -5708,7 +5837,7 @@ func (cpu *Cpu) opArm110(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -5749,7 +5878,7 @@ func (cpu *Cpu) opArm111(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -5776,7 +5905,7 @@ func (cpu *Cpu) opArm112(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -5804,7 +5933,7 @@ func (cpu *Cpu) opArm113(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -5831,7 +5960,7 @@ func (cpu *Cpu) opArm114(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -5859,7 +5988,7 @@ func (cpu *Cpu) opArm115(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -5883,13 +6012,13 @@ func (cpu *Cpu) opArm116(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -5918,7 +6047,7 @@ func (cpu *Cpu) opArm117(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -5949,6 +6078,10 @@ func (cpu *Cpu) opArm11B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -5988,6 +6121,10 @@ func (cpu *Cpu) opArm11D(op uint32) {
 	rn -= off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -6030,6 +6167,10 @@ func (cpu *Cpu) opArm11F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -6157,10 +6298,67 @@ func (cpu *Cpu) disasmArm123(op uint32, pc uint32) string {
 	return out.String()
 }
 
+func (cpu *Cpu) opArm125(op uint32) {
+	// qsub
+	if op&0x0F900FF0 != 0x01000050 {
+		cpu.InvalidOpArm(op, "invalid opcode decoded as QADD/QSUB")
+		return
+	}
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rm := int64(int32(cpu.Regs[op&0xF]))
+	rn := int64(int32(cpu.Regs[(op>>16)&0xF]))
+	res, sat := saturate32(rm - rn)
+	if sat {
+		cpu.Cpsr.SetQ(true)
+	}
+	cpu.Regs[(op>>12)&0xF] = reg(res)
+	cpu.Clock += 1
+}
+
+func (cpu *Cpu) disasmArm125(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("qsub", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := op & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg2])
+	return out.String()
+}
+
+func (cpu *Cpu) opArm127(op uint32) {
+	// bkpt
+	if op&0xFFF000F0 != 0xE1200070 {
+		cpu.InvalidOpArm(op, "invalid opcode decoded as BKPT")
+		return
+	}
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	cpu.bkpt()
+}
+
+func (cpu *Cpu) disasmArm127(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	out.WriteString("bkpt      ")
+	arg0 := int64((op>>4)&0xFFF0 | op&0xF)
+	out.WriteString("#0x")
+	out.WriteString(strconv.FormatInt(arg0, 16))
+	return out.String()
+}
+
 func (cpu *Cpu) opArm128(op uint32) {
 	// smlawb
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -6168,11 +6366,16 @@ func (cpu *Cpu) opArm128(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	rnx := (op >> 12) & 0xF
 	rn := uint32(cpu.Regs[rnx])
 	hrs := int16(rs & 0xFFFF)
 	res := reg((int64(int32(rm)) * int64(hrs)) >> 16)
-	res += reg(rn)
+	acc := res + reg(rn)
+	if (^(uint32(res)^rn)&(uint32(acc)^rn))>>31 != 0 {
+		cpu.Cpsr.SetQ(true)
+	}
+	res = acc
 	cpu.Regs[rdx] = reg(res)
 }
 
@@ -6197,7 +6400,7 @@ func (cpu *Cpu) disasmArm128(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm12A(op uint32) {
 	// smulwb
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -6205,6 +6408,7 @@ func (cpu *Cpu) opArm12A(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	hrs := int16(rs & 0xFFFF)
 	res := reg((int64(int32(rm)) * int64(hrs)) >> 16)
 	cpu.Regs[rdx] = reg(res)
@@ -6239,6 +6443,10 @@ func (cpu *Cpu) opArm12B(op uint32) {
 	rn -= off
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -6264,7 +6472,7 @@ func (cpu *Cpu) disasmArm12B(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm12C(op uint32) {
 	// smlawt
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -6272,11 +6480,16 @@ func (cpu *Cpu) opArm12C(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	rnx := (op >> 12) & 0xF
 	rn := uint32(cpu.Regs[rnx])
 	hrs := int16(rs >> 16)
 	res := reg((int64(int32(rm)) * int64(hrs)) >> 16)
-	res += reg(rn)
+	acc := res + reg(rn)
+	if (^(uint32(res)^rn)&(uint32(acc)^rn))>>31 != 0 {
+		cpu.Cpsr.SetQ(true)
+	}
+	res = acc
 	cpu.Regs[rdx] = reg(res)
 }
 
@@ -6311,12 +6524,26 @@ func (cpu *Cpu) opArm12D(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn -= off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -6342,7 +6569,7 @@ func (cpu *Cpu) disasmArm12D(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm12E(op uint32) {
 	// smulwt
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -6350,6 +6577,7 @@ func (cpu *Cpu) opArm12E(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	hrs := int16(rs >> 16)
 	res := reg((int64(int32(rm)) * int64(hrs)) >> 16)
 	cpu.Regs[rdx] = reg(res)
@@ -6383,8 +6611,16 @@ func (cpu *Cpu) opArm12F(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn -= off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -6419,7 +6655,7 @@ func (cpu *Cpu) opArm130(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -6460,7 +6696,7 @@ func (cpu *Cpu) opArm131(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -6487,7 +6723,7 @@ func (cpu *Cpu) opArm132(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -6515,7 +6751,7 @@ func (cpu *Cpu) opArm133(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -6542,7 +6778,7 @@ func (cpu *Cpu) opArm134(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -6570,7 +6806,7 @@ func (cpu *Cpu) opArm135(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -6594,13 +6830,13 @@ func (cpu *Cpu) opArm136(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -6629,7 +6865,7 @@ func (cpu *Cpu) opArm137(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -6660,6 +6896,10 @@ func (cpu *Cpu) opArm13B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -6701,6 +6941,10 @@ func (cpu *Cpu) opArm13D(op uint32) {
 	rn -= off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -6745,6 +6989,10 @@ func (cpu *Cpu) opArm13F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -6803,8 +7051,84 @@ func (cpu *Cpu) disasmArm140(op uint32, pc uint32) string {
 	return out.String()
 }
 
+func (cpu *Cpu) opArm145(op uint32) {
+	// qdadd
+	if op&0x0F900FF0 != 0x01000050 {
+		cpu.InvalidOpArm(op, "invalid opcode decoded as QADD/QSUB")
+		return
+	}
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rm := int64(int32(cpu.Regs[op&0xF]))
+	rn := int64(int32(cpu.Regs[(op>>16)&0xF]))
+	dbl, sat1 := saturate32(rn * 2)
+	if sat1 {
+		cpu.Cpsr.SetQ(true)
+	}
+	rn = int64(dbl)
+	res, sat := saturate32(rm + rn)
+	if sat {
+		cpu.Cpsr.SetQ(true)
+	}
+	cpu.Regs[(op>>12)&0xF] = reg(res)
+	cpu.Clock += 1
+}
+
+func (cpu *Cpu) disasmArm145(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("qdadd", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := op & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg2])
+	return out.String()
+}
+
 func (cpu *Cpu) opArm148(op uint32) {
-	cpu.InvalidOpArm(op, "unhandled mul-type (10)")
+	// smlalbb
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rsx := (op >> 8) & 0xF
+	rs := uint32(cpu.Regs[rsx])
+	rmx := (op >> 0) & 0xF
+	rm := uint32(cpu.Regs[rmx])
+	rdx := (op >> 16) & 0xF
+	cpu.Clock += 2
+	hrm := int16(rm & 0xFFFF)
+	hrs := int16(rs & 0xFFFF)
+	rnx := (op >> 12) & 0xF
+	res64 := int64(int32(hrm) * int32(hrs))
+	res64 += int64(uint64(cpu.Regs[rnx]) + uint64(cpu.Regs[rdx])<<32)
+	cpu.Regs[rnx] = reg(res64)
+	res := uint32(res64 >> 32)
+	cpu.Regs[rdx] = reg(res)
+}
+
+func (cpu *Cpu) disasmArm148(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("smlalbb", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 0) & 0xF
+	out.WriteString(RegNames[arg2])
+	out.WriteString(", ")
+	arg3 := (op >> 8) & 0xF
+	out.WriteString(RegNames[arg3])
+	return out.String()
 }
 
 func (cpu *Cpu) opArm149(op uint32) {
@@ -6818,7 +7142,12 @@ func (cpu *Cpu) opArm149(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 12) & 0xF
-	cpu.Regs[rdx] = reg(cpu.Read8(rn))
+	res := reg(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = res
 	cpu.Write8(rn, uint8(rm))
 	cpu.Clock += 1
 }
@@ -6840,6 +7169,46 @@ func (cpu *Cpu) disasmArm149(op uint32, pc uint32) string {
 	return out.String()
 }
 
+func (cpu *Cpu) opArm14A(op uint32) {
+	// smlaltb
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rsx := (op >> 8) & 0xF
+	rs := uint32(cpu.Regs[rsx])
+	rmx := (op >> 0) & 0xF
+	rm := uint32(cpu.Regs[rmx])
+	rdx := (op >> 16) & 0xF
+	cpu.Clock += 2
+	hrm := int16(rm >> 16)
+	hrs := int16(rs & 0xFFFF)
+	rnx := (op >> 12) & 0xF
+	res64 := int64(int32(hrm) * int32(hrs))
+	res64 += int64(uint64(cpu.Regs[rnx]) + uint64(cpu.Regs[rdx])<<32)
+	cpu.Regs[rnx] = reg(res64)
+	res := uint32(res64 >> 32)
+	cpu.Regs[rdx] = reg(res)
+}
+
+func (cpu *Cpu) disasmArm14A(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("smlaltb", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 0) & 0xF
+	out.WriteString(RegNames[arg2])
+	out.WriteString(", ")
+	arg3 := (op >> 8) & 0xF
+	out.WriteString(RegNames[arg3])
+	return out.String()
+}
+
 func (cpu *Cpu) opArm14B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6869,13 +7238,58 @@ func (cpu *Cpu) disasmArm14B(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
 }
 
+func (cpu *Cpu) opArm14C(op uint32) {
+	// smlalbt
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rsx := (op >> 8) & 0xF
+	rs := uint32(cpu.Regs[rsx])
+	rmx := (op >> 0) & 0xF
+	rm := uint32(cpu.Regs[rmx])
+	rdx := (op >> 16) & 0xF
+	cpu.Clock += 2
+	hrm := int16(rm & 0xFFFF)
+	hrs := int16(rs >> 16)
+	rnx := (op >> 12) & 0xF
+	res64 := int64(int32(hrm) * int32(hrs))
+	res64 += int64(uint64(cpu.Regs[rnx]) + uint64(cpu.Regs[rdx])<<32)
+	cpu.Regs[rnx] = reg(res64)
+	res := uint32(res64 >> 32)
+	cpu.Regs[rdx] = reg(res)
+}
+
+func (cpu *Cpu) disasmArm14C(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("smlalbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 0) & 0xF
+	out.WriteString(RegNames[arg2])
+	out.WriteString(", ")
+	arg3 := (op >> 8) & 0xF
+	out.WriteString(RegNames[arg3])
+	return out.String()
+}
+
 func (cpu *Cpu) opArm14D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6884,8 +7298,18 @@ func (cpu *Cpu) opArm14D(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn -= off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
@@ -6910,13 +7334,58 @@ func (cpu *Cpu) disasmArm14D(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
 }
 
+func (cpu *Cpu) opArm14E(op uint32) {
+	// smlaltt
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rsx := (op >> 8) & 0xF
+	rs := uint32(cpu.Regs[rsx])
+	rmx := (op >> 0) & 0xF
+	rm := uint32(cpu.Regs[rmx])
+	rdx := (op >> 16) & 0xF
+	cpu.Clock += 2
+	hrm := int16(rm >> 16)
+	hrs := int16(rs >> 16)
+	rnx := (op >> 12) & 0xF
+	res64 := int64(int32(hrm) * int32(hrs))
+	res64 += int64(uint64(cpu.Regs[rnx]) + uint64(cpu.Regs[rdx])<<32)
+	cpu.Regs[rnx] = reg(res64)
+	res := uint32(res64 >> 32)
+	cpu.Regs[rdx] = reg(res)
+}
+
+func (cpu *Cpu) disasmArm14E(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("smlaltt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 0) & 0xF
+	out.WriteString(RegNames[arg2])
+	out.WriteString(", ")
+	arg3 := (op >> 8) & 0xF
+	out.WriteString(RegNames[arg3])
+	return out.String()
+}
+
 func (cpu *Cpu) opArm14F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6925,6 +7394,10 @@ func (cpu *Cpu) opArm14F(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn -= off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
 	cpu.Clock += 1
@@ -6947,8 +7420,13 @@ func (cpu *Cpu) disasmArm14F(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -6966,7 +7444,7 @@ func (cpu *Cpu) opArm150(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7009,7 +7487,7 @@ func (cpu *Cpu) opArm151(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7038,7 +7516,7 @@ func (cpu *Cpu) opArm152(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -7068,7 +7546,7 @@ func (cpu *Cpu) opArm153(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7097,7 +7575,7 @@ func (cpu *Cpu) opArm154(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -7127,7 +7605,7 @@ func (cpu *Cpu) opArm155(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7153,13 +7631,13 @@ func (cpu *Cpu) opArm156(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -7190,7 +7668,7 @@ func (cpu *Cpu) opArm157(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -7218,6 +7696,10 @@ func (cpu *Cpu) opArm15B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -7243,8 +7725,13 @@ func (cpu *Cpu) disasmArm15B(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -7259,6 +7746,10 @@ func (cpu *Cpu) opArm15D(op uint32) {
 	rn -= off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -7284,8 +7775,13 @@ func (cpu *Cpu) disasmArm15D(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -7303,6 +7799,10 @@ func (cpu *Cpu) opArm15F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -7328,8 +7828,13 @@ func (cpu *Cpu) disasmArm15F(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -7391,7 +7896,7 @@ func (cpu *Cpu) opArm161(op uint32) {
 		return
 	}
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "invalid CLZ opcode on pre-ARMv5 CPU")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rdx := (op >> 12) & 0xF
@@ -7418,10 +7923,50 @@ func (cpu *Cpu) disasmArm161(op uint32, pc uint32) string {
 	return out.String()
 }
 
+func (cpu *Cpu) opArm165(op uint32) {
+	// qdsub
+	if op&0x0F900FF0 != 0x01000050 {
+		cpu.InvalidOpArm(op, "invalid opcode decoded as QADD/QSUB")
+		return
+	}
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	rm := int64(int32(cpu.Regs[op&0xF]))
+	rn := int64(int32(cpu.Regs[(op>>16)&0xF]))
+	dbl, sat1 := saturate32(rn * 2)
+	if sat1 {
+		cpu.Cpsr.SetQ(true)
+	}
+	rn = int64(dbl)
+	res, sat := saturate32(rm - rn)
+	if sat {
+		cpu.Cpsr.SetQ(true)
+	}
+	cpu.Regs[(op>>12)&0xF] = reg(res)
+	cpu.Clock += 1
+}
+
+func (cpu *Cpu) disasmArm165(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("qdsub", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := op & 0xF
+	out.WriteString(RegNames[arg1])
+	out.WriteString(", ")
+	arg2 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg2])
+	return out.String()
+}
+
 func (cpu *Cpu) opArm168(op uint32) {
 	// smulbb
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -7429,6 +7974,7 @@ func (cpu *Cpu) opArm168(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	hrm := int16(rm & 0xFFFF)
 	hrs := int16(rs & 0xFFFF)
 	res := reg(int32(hrm) * int32(hrs))
@@ -7453,7 +7999,7 @@ func (cpu *Cpu) disasmArm168(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm16A(op uint32) {
 	// smultb
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -7461,6 +8007,7 @@ func (cpu *Cpu) opArm16A(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	hrm := int16(rm >> 16)
 	hrs := int16(rs & 0xFFFF)
 	res := reg(int32(hrm) * int32(hrs))
@@ -7491,6 +8038,10 @@ func (cpu *Cpu) opArm16B(op uint32) {
 	rn -= off
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -7512,8 +8063,13 @@ func (cpu *Cpu) disasmArm16B(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -7523,7 +8079,7 @@ func (cpu *Cpu) disasmArm16B(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm16C(op uint32) {
 	// smulbt
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -7531,6 +8087,7 @@ func (cpu *Cpu) opArm16C(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	hrm := int16(rm & 0xFFFF)
 	hrs := int16(rs >> 16)
 	res := reg(int32(hrm) * int32(hrs))
@@ -7560,12 +8117,26 @@ func (cpu *Cpu) opArm16D(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn -= off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -7587,8 +8158,13 @@ func (cpu *Cpu) disasmArm16D(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -7598,7 +8174,7 @@ func (cpu *Cpu) disasmArm16D(op uint32, pc uint32) string {
 func (cpu *Cpu) opArm16E(op uint32) {
 	// smultt
 	if cpu.arch < ARMv5 {
-		cpu.InvalidOpArm(op, "half-width mul not available on ARMv4 or before")
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
 	rsx := (op >> 8) & 0xF
@@ -7606,6 +8182,7 @@ func (cpu *Cpu) opArm16E(op uint32) {
 	rmx := (op >> 0) & 0xF
 	rm := uint32(cpu.Regs[rmx])
 	rdx := (op >> 16) & 0xF
+	cpu.Clock += 1
 	hrm := int16(rm >> 16)
 	hrs := int16(rs >> 16)
 	res := reg(int32(hrm) * int32(hrs))
@@ -7635,8 +8212,16 @@ func (cpu *Cpu) opArm16F(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn -= off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -7658,9 +8243,14 @@ func (cpu *Cpu) disasmArm16F(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
+		out.WriteString("]")
 		out.WriteString("!")
 	}
 	return out.String()
@@ -7678,7 +8268,7 @@ func (cpu *Cpu) opArm170(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7721,7 +8311,7 @@ func (cpu *Cpu) opArm171(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7750,7 +8340,7 @@ func (cpu *Cpu) opArm172(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -7780,7 +8370,7 @@ func (cpu *Cpu) opArm173(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7809,7 +8399,7 @@ func (cpu *Cpu) opArm174(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -7839,7 +8429,7 @@ func (cpu *Cpu) opArm175(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -7865,13 +8455,13 @@ func (cpu *Cpu) opArm176(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -7902,7 +8492,7 @@ func (cpu *Cpu) opArm177(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -7930,6 +8520,10 @@ func (cpu *Cpu) opArm17B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -7956,8 +8550,13 @@ func (cpu *Cpu) disasmArm17B(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -7973,6 +8572,10 @@ func (cpu *Cpu) opArm17D(op uint32) {
 	rn -= off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -7999,8 +8602,13 @@ func (cpu *Cpu) disasmArm17D(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -8019,6 +8627,10 @@ func (cpu *Cpu) opArm17F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -8045,8 +8657,13 @@ func (cpu *Cpu) disasmArm17F(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -8316,8 +8933,18 @@ func (cpu *Cpu) opArm18D(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn += off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
@@ -8355,6 +8982,10 @@ func (cpu *Cpu) opArm18F(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn += off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
 	cpu.Clock += 1
@@ -8389,7 +9020,7 @@ func (cpu *Cpu) opArm190(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -8434,7 +9065,7 @@ func (cpu *Cpu) opArm191(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -8462,7 +9093,7 @@ func (cpu *Cpu) opArm192(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn | op2
@@ -8491,7 +9122,7 @@ func (cpu *Cpu) opArm193(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -8519,7 +9150,7 @@ func (cpu *Cpu) opArm194(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn | op2
@@ -8548,7 +9179,7 @@ func (cpu *Cpu) opArm195(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -8573,13 +9204,13 @@ func (cpu *Cpu) opArm196(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn | op2
@@ -8609,7 +9240,7 @@ func (cpu *Cpu) opArm197(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn | op2
@@ -8641,6 +9272,10 @@ func (cpu *Cpu) opArm19B(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -8680,6 +9315,10 @@ func (cpu *Cpu) opArm19D(op uint32) {
 	rn += off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -8722,6 +9361,10 @@ func (cpu *Cpu) opArm19F(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -9006,6 +9649,10 @@ func (cpu *Cpu) opArm1AB(op uint32) {
 	rn += off
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -9041,12 +9688,26 @@ func (cpu *Cpu) opArm1AD(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn += off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -9082,8 +9743,16 @@ func (cpu *Cpu) opArm1AF(op uint32) {
 	off := uint32(cpu.Regs[rmx])
 	rn += off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -9118,7 +9787,7 @@ func (cpu *Cpu) opArm1B0(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9164,7 +9833,7 @@ func (cpu *Cpu) opArm1B1(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9196,7 +9865,7 @@ func (cpu *Cpu) opArm1B2(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	if rnx != 0 {
@@ -9229,7 +9898,7 @@ func (cpu *Cpu) opArm1B3(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9261,7 +9930,7 @@ func (cpu *Cpu) opArm1B4(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	if rnx != 0 {
@@ -9294,7 +9963,7 @@ func (cpu *Cpu) opArm1B5(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9323,13 +9992,13 @@ func (cpu *Cpu) opArm1B6(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	if rnx != 0 {
@@ -9363,7 +10032,7 @@ func (cpu *Cpu) opArm1B7(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	if rnx != 0 {
@@ -9399,6 +10068,10 @@ func (cpu *Cpu) opArm1BB(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -9440,6 +10113,10 @@ func (cpu *Cpu) opArm1BD(op uint32) {
 	rn += off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -9484,6 +10161,10 @@ func (cpu *Cpu) opArm1BF(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -9756,8 +10437,13 @@ func (cpu *Cpu) disasmArm1CB(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -9771,8 +10457,18 @@ func (cpu *Cpu) opArm1CD(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn += off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
@@ -9797,8 +10493,13 @@ func (cpu *Cpu) disasmArm1CD(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -9812,6 +10513,10 @@ func (cpu *Cpu) opArm1CF(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn += off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
 	cpu.Clock += 1
@@ -9834,8 +10539,13 @@ func (cpu *Cpu) disasmArm1CF(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -9853,7 +10563,7 @@ func (cpu *Cpu) opArm1D0(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9898,7 +10608,7 @@ func (cpu *Cpu) opArm1D1(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9926,7 +10636,7 @@ func (cpu *Cpu) opArm1D2(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & ^op2
@@ -9955,7 +10665,7 @@ func (cpu *Cpu) opArm1D3(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -9983,7 +10693,7 @@ func (cpu *Cpu) opArm1D4(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & ^op2
@@ -10012,7 +10722,7 @@ func (cpu *Cpu) opArm1D5(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -10037,13 +10747,13 @@ func (cpu *Cpu) opArm1D6(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & ^op2
@@ -10073,7 +10783,7 @@ func (cpu *Cpu) opArm1D7(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & ^op2
@@ -10100,6 +10810,10 @@ func (cpu *Cpu) opArm1DB(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -10125,8 +10839,13 @@ func (cpu *Cpu) disasmArm1DB(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -10141,6 +10860,10 @@ func (cpu *Cpu) opArm1DD(op uint32) {
 	rn += off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -10166,8 +10889,13 @@ func (cpu *Cpu) disasmArm1DD(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -10185,6 +10913,10 @@ func (cpu *Cpu) opArm1DF(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -10210,8 +10942,13 @@ func (cpu *Cpu) disasmArm1DF(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -10439,6 +11176,10 @@ func (cpu *Cpu) opArm1EB(op uint32) {
 	rn += off
 	// STRH
 	cpu.Write16(rn, uint16(cpu.Regs[rdx]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -10460,8 +11201,13 @@ func (cpu *Cpu) disasmArm1EB(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -10476,12 +11222,26 @@ func (cpu *Cpu) opArm1ED(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn += off
 	// LDRD
-	cpu.Regs[rdx] = reg(cpu.Read32(rn))
-	cpu.Regs[rdx+1] = reg(cpu.Read32(rn + 4))
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
+	lo := cpu.Read32(rn)
+	hi := cpu.Read32(rn + 4)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(lo)
+	cpu.Regs[rdx+1] = reg(hi)
 	if rdx == 14 {
 		cpu.InvalidOpArm(op, "LDRD PC not implemented")
 		return
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -10503,8 +11263,13 @@ func (cpu *Cpu) disasmArm1ED(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -10519,8 +11284,16 @@ func (cpu *Cpu) opArm1EF(op uint32) {
 	off := (op & 0xF) | ((op & 0xF00) >> 4)
 	rn += off
 	// STRD
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
+		return
+	}
 	cpu.Write32(rn, uint32(cpu.Regs[rdx]))
 	cpu.Write32(rn+4, uint32(cpu.Regs[rdx+1]))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
@@ -10542,8 +11315,13 @@ func (cpu *Cpu) disasmArm1EF(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -10562,7 +11340,7 @@ func (cpu *Cpu) opArm1F0(op uint32) {
 		goto op2end
 	}
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -10604,7 +11382,7 @@ func (cpu *Cpu) opArm1F1(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 <<= shift - 1
-	cpu.Cpsr.SetC(op2>>31 != 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 	op2 <<= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -10632,7 +11410,7 @@ func (cpu *Cpu) opArm1F2(op uint32) {
 		shift = 32
 	}
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 	rn := uint32(cpu.Regs[rnx])
 	res := ^op2
@@ -10661,7 +11439,7 @@ func (cpu *Cpu) opArm1F3(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 >>= shift - 1
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 >>= 1
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -10689,7 +11467,7 @@ func (cpu *Cpu) opArm1F4(op uint32) {
 		shift = 32
 	}
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 	rn := uint32(cpu.Regs[rnx])
 	res := ^op2
@@ -10718,7 +11496,7 @@ func (cpu *Cpu) opArm1F5(op uint32) {
 	}
 	cpu.Clock += 1
 	op2 = uint32(int32(op2) >> (shift - 1))
-	cpu.Cpsr.SetC(op2&1 != 0)
+	cpu.Cpsr.SetCBit(op2)
 	op2 = uint32(int32(op2) >> 1)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
@@ -10743,13 +11521,13 @@ func (cpu *Cpu) opArm1F6(op uint32) {
 	op2 := uint32(cpu.Regs[op&0xF])
 	shift := uint32((op >> 7) & 0x1F)
 	if shift == 0 { // becomes RRX #1
-		cpu.Cpsr.SetC(op2&1 != 0)
+		cpu.Cpsr.SetCBit(op2)
 		op2 = (op2 >> 1) | (cf << 31)
 		goto op2end
 	}
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := ^op2
@@ -10779,7 +11557,7 @@ func (cpu *Cpu) opArm1F7(op uint32) {
 	cpu.Clock += 1
 	shift &= 31
 	op2 = (op2 >> shift) | (op2 << (32 - shift))
-	cpu.Cpsr.SetC(int32(op2) < 0)
+	cpu.Cpsr.SetCBit(op2 >> 31)
 op2end:
 	rn := uint32(cpu.Regs[rnx])
 	res := ^op2
@@ -10806,6 +11584,10 @@ func (cpu *Cpu) opArm1FB(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		res = (res >> 8) | (res << 8)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRH PC not implemented")
@@ -10832,8 +11614,13 @@ func (cpu *Cpu) disasmArm1FB(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -10849,6 +11636,10 @@ func (cpu *Cpu) opArm1FD(op uint32) {
 	rn += off
 	// LDRSB
 	data := int32(int8(cpu.Read8(rn)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSB PC not implemented")
@@ -10875,8 +11666,13 @@ func (cpu *Cpu) disasmArm1FD(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -10895,6 +11691,10 @@ func (cpu *Cpu) opArm1FF(op uint32) {
 	if rn&1 != 0 && cpu.arch < ARMv5 {
 		data >>= 8
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(data)
 	if rdx == 15 {
 		cpu.InvalidOpArm(op, "LDRSH PC not implemented")
@@ -10921,8 +11721,13 @@ func (cpu *Cpu) disasmArm1FF(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -10971,7 +11776,7 @@ func (cpu *Cpu) opArm210(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -11044,7 +11849,7 @@ func (cpu *Cpu) opArm230(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -11117,7 +11922,7 @@ func (cpu *Cpu) opArm250(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -11192,7 +11997,7 @@ func (cpu *Cpu) opArm270(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := op2 - rn
@@ -11267,7 +12072,7 @@ func (cpu *Cpu) opArm290(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -11343,16 +12148,12 @@ func (cpu *Cpu) opArm2B0(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > res)
-	} else {
-		cpu.Cpsr.SetC(rn >= res)
-	}
+	cpu.Cpsr.SetCAdd(rn, op2, cf)
 	cpu.Cpsr.SetVAdd(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -11424,16 +12225,12 @@ func (cpu *Cpu) opArm2D0(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -11506,17 +12303,13 @@ func (cpu *Cpu) opArm2F0(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	rn, op2 = op2, rn
 	res := rn - op2
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rn > op2)
-	} else {
-		cpu.Cpsr.SetC(rn >= op2)
-	}
+	cpu.Cpsr.SetCSub(rn, op2, cf)
 	cpu.Cpsr.SetVSub(rn, op2, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -11553,7 +12346,7 @@ func (cpu *Cpu) opArm310(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & op2
@@ -11639,7 +12432,7 @@ func (cpu *Cpu) opArm330(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn ^ op2
@@ -11674,7 +12467,7 @@ func (cpu *Cpu) opArm350(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn - op2
@@ -11762,7 +12555,7 @@ func (cpu *Cpu) opArm370(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn + op2
@@ -11833,7 +12626,7 @@ func (cpu *Cpu) opArm390(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn | op2
@@ -11907,7 +12700,7 @@ func (cpu *Cpu) opArm3B0(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	if rnx != 0 {
@@ -11981,7 +12774,7 @@ func (cpu *Cpu) opArm3D0(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := rn & ^op2
@@ -12051,7 +12844,7 @@ func (cpu *Cpu) opArm3F0(op uint32) {
 	rot := uint((op >> 7) & 0x1E)
 	op2 := ((op & 0xFF) >> rot) | ((op & 0xFF) << (32 - rot))
 	if rot != 0 {
-		cpu.Cpsr.SetC(op2>>31 != 0)
+		cpu.Cpsr.SetCBit(op2 >> 31)
 	}
 	rn := uint32(cpu.Regs[rnx])
 	res := ^op2
@@ -12081,7 +12874,7 @@ func (cpu *Cpu) disasmArm3F0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm400(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12091,6 +12884,10 @@ func (cpu *Cpu) opArm400(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -12109,15 +12906,14 @@ func (cpu *Cpu) disasmArm400(op uint32, pc uint32) string {
 	out.WriteString(RegNames[arg1])
 	out.WriteString("]")
 	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
 	return out.String()
 }
 
 func (cpu *Cpu) opArm410(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12130,6 +12926,10 @@ func (cpu *Cpu) opArm410(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12153,15 +12953,14 @@ func (cpu *Cpu) disasmArm410(op uint32, pc uint32) string {
 	out.WriteString(RegNames[arg1])
 	out.WriteString("]")
 	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
 	return out.String()
 }
 
 func (cpu *Cpu) opArm420(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12171,6 +12970,10 @@ func (cpu *Cpu) opArm420(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -12178,9 +12981,26 @@ func (cpu *Cpu) opArm420(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm420(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm430(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12193,6 +13013,10 @@ func (cpu *Cpu) opArm430(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12205,9 +13029,26 @@ func (cpu *Cpu) opArm430(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm430(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm440(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12217,6 +13058,10 @@ func (cpu *Cpu) opArm440(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -12235,15 +13080,14 @@ func (cpu *Cpu) disasmArm440(op uint32, pc uint32) string {
 	out.WriteString(RegNames[arg1])
 	out.WriteString("]")
 	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
 	return out.String()
 }
 
 func (cpu *Cpu) opArm450(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12252,6 +13096,10 @@ func (cpu *Cpu) opArm450(op uint32) {
 	cpu.Regs[15] += 4
 	off := op & 0xFFF
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12275,15 +13123,14 @@ func (cpu *Cpu) disasmArm450(op uint32, pc uint32) string {
 	out.WriteString(RegNames[arg1])
 	out.WriteString("]")
 	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
 	return out.String()
 }
 
 func (cpu *Cpu) opArm460(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12293,6 +13140,10 @@ func (cpu *Cpu) opArm460(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -12300,9 +13151,26 @@ func (cpu *Cpu) opArm460(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm460(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm470(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12311,6 +13179,10 @@ func (cpu *Cpu) opArm470(op uint32) {
 	cpu.Regs[15] += 4
 	off := op & 0xFFF
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12323,9 +13195,26 @@ func (cpu *Cpu) opArm470(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm470(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "#-0x" + strconv.FormatInt(int64(op&0xFFF), 16)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm480(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12335,6 +13224,10 @@ func (cpu *Cpu) opArm480(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -12361,7 +13254,7 @@ func (cpu *Cpu) disasmArm480(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm490(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12374,6 +13267,10 @@ func (cpu *Cpu) opArm490(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12405,7 +13302,7 @@ func (cpu *Cpu) disasmArm490(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm4A0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12415,6 +13312,10 @@ func (cpu *Cpu) opArm4A0(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -12422,9 +13323,27 @@ func (cpu *Cpu) opArm4A0(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm4A0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := int64(op & 0xFFF)
+	out.WriteString("#0x")
+	out.WriteString(strconv.FormatInt(arg2, 16))
+	return out.String()
+}
+
 func (cpu *Cpu) opArm4B0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12437,7 +13356,11 @@ func (cpu *Cpu) opArm4B0(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
-	cpu.Regs[rdx] = reg(res)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
 		cpu.branch(reg(res&^1), BranchJump)
@@ -12449,9 +13372,27 @@ func (cpu *Cpu) opArm4B0(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm4B0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := int64(op & 0xFFF)
+	out.WriteString("#0x")
+	out.WriteString(strconv.FormatInt(arg2, 16))
+	return out.String()
+}
+
 func (cpu *Cpu) opArm4C0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12461,6 +13402,10 @@ func (cpu *Cpu) opArm4C0(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -12487,7 +13432,7 @@ func (cpu *Cpu) disasmArm4C0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm4D0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12496,6 +13441,10 @@ func (cpu *Cpu) opArm4D0(op uint32) {
 	cpu.Regs[15] += 4
 	off := op & 0xFFF
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12527,7 +13476,7 @@ func (cpu *Cpu) disasmArm4D0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm4E0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12537,6 +13486,10 @@ func (cpu *Cpu) opArm4E0(op uint32) {
 	off := op & 0xFFF
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -12544,9 +13497,27 @@ func (cpu *Cpu) opArm4E0(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm4E0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := int64(op & 0xFFF)
+	out.WriteString("#0x")
+	out.WriteString(strconv.FormatInt(arg2, 16))
+	return out.String()
+}
+
 func (cpu *Cpu) opArm4F0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12555,6 +13526,10 @@ func (cpu *Cpu) opArm4F0(op uint32) {
 	cpu.Regs[15] += 4
 	off := op & 0xFFF
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12567,9 +13542,27 @@ func (cpu *Cpu) opArm4F0(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm4F0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := int64(op & 0xFFF)
+	out.WriteString("#0x")
+	out.WriteString(strconv.FormatInt(arg2, 16))
+	return out.String()
+}
+
 func (cpu *Cpu) opArm500(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12601,8 +13594,13 @@ func (cpu *Cpu) disasmArm500(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -12610,7 +13608,7 @@ func (cpu *Cpu) disasmArm500(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm510(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12624,6 +13622,10 @@ func (cpu *Cpu) opArm510(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12650,8 +13652,13 @@ func (cpu *Cpu) disasmArm510(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -12659,7 +13666,7 @@ func (cpu *Cpu) disasmArm510(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm520(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12670,6 +13677,10 @@ func (cpu *Cpu) opArm520(op uint32) {
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -12692,8 +13703,13 @@ func (cpu *Cpu) disasmArm520(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -12702,7 +13718,7 @@ func (cpu *Cpu) disasmArm520(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm530(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12716,6 +13732,10 @@ func (cpu *Cpu) opArm530(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12743,8 +13763,13 @@ func (cpu *Cpu) disasmArm530(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -12753,7 +13778,7 @@ func (cpu *Cpu) disasmArm530(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm540(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12785,8 +13810,13 @@ func (cpu *Cpu) disasmArm540(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -12794,7 +13824,15 @@ func (cpu *Cpu) disasmArm540(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm550(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12804,6 +13842,10 @@ func (cpu *Cpu) opArm550(op uint32) {
 	off := op & 0xFFF
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12814,6 +13856,30 @@ func (cpu *Cpu) opArm550(op uint32) {
 }
 
 func (cpu *Cpu) disasmArm550(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("pld       ")
+		arg0a := (op >> 16) & 0xF
+		arg0b := -int32(op & 0xFFF)
+		if RegNames[arg0a] == "pc" && !false {
+			arg0c := uint32(arg0b) + uint32((pc+8)&^2)
+			arg0v := cpu.Read32(arg0c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg0v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg0a])
+			if int64(arg0b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg0b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg0b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
 	var out bytes.Buffer
 	opcode := cpu.disasmAddCond("ldrb", op)
 	out.WriteString((opcode + "                ")[:10])
@@ -12830,8 +13896,13 @@ func (cpu *Cpu) disasmArm550(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -12839,7 +13910,7 @@ func (cpu *Cpu) disasmArm550(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm560(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12850,6 +13921,10 @@ func (cpu *Cpu) opArm560(op uint32) {
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -12872,8 +13947,13 @@ func (cpu *Cpu) disasmArm560(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -12882,7 +13962,7 @@ func (cpu *Cpu) disasmArm560(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm570(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12892,6 +13972,10 @@ func (cpu *Cpu) opArm570(op uint32) {
 	off := op & 0xFFF
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -12919,8 +14003,13 @@ func (cpu *Cpu) disasmArm570(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -12929,7 +14018,7 @@ func (cpu *Cpu) disasmArm570(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm580(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12961,8 +14050,13 @@ func (cpu *Cpu) disasmArm580(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -12970,7 +14064,7 @@ func (cpu *Cpu) disasmArm580(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm590(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -12984,6 +14078,10 @@ func (cpu *Cpu) opArm590(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13010,8 +14108,13 @@ func (cpu *Cpu) disasmArm590(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -13019,7 +14122,7 @@ func (cpu *Cpu) disasmArm590(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm5A0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13030,6 +14133,10 @@ func (cpu *Cpu) opArm5A0(op uint32) {
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -13052,8 +14159,13 @@ func (cpu *Cpu) disasmArm5A0(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -13062,7 +14174,7 @@ func (cpu *Cpu) disasmArm5A0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm5B0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13076,6 +14188,10 @@ func (cpu *Cpu) opArm5B0(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13103,8 +14219,13 @@ func (cpu *Cpu) disasmArm5B0(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -13113,7 +14234,7 @@ func (cpu *Cpu) disasmArm5B0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm5C0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13145,8 +14266,13 @@ func (cpu *Cpu) disasmArm5C0(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -13154,7 +14280,15 @@ func (cpu *Cpu) disasmArm5C0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm5D0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13164,6 +14298,10 @@ func (cpu *Cpu) opArm5D0(op uint32) {
 	off := op & 0xFFF
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13174,6 +14312,30 @@ func (cpu *Cpu) opArm5D0(op uint32) {
 }
 
 func (cpu *Cpu) disasmArm5D0(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("pld       ")
+		arg0a := (op >> 16) & 0xF
+		arg0b := int32(op & 0xFFF)
+		if RegNames[arg0a] == "pc" && !false {
+			arg0c := uint32(arg0b) + uint32((pc+8)&^2)
+			arg0v := cpu.Read32(arg0c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg0v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg0a])
+			if int64(arg0b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg0b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg0b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
 	var out bytes.Buffer
 	opcode := cpu.disasmAddCond("ldrb", op)
 	out.WriteString((opcode + "                ")[:10])
@@ -13190,8 +14352,13 @@ func (cpu *Cpu) disasmArm5D0(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -13199,7 +14366,7 @@ func (cpu *Cpu) disasmArm5D0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm5E0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13210,6 +14377,10 @@ func (cpu *Cpu) opArm5E0(op uint32) {
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -13232,8 +14403,13 @@ func (cpu *Cpu) disasmArm5E0(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -13242,7 +14418,7 @@ func (cpu *Cpu) disasmArm5E0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm5F0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13252,6 +14428,10 @@ func (cpu *Cpu) opArm5F0(op uint32) {
 	off := op & 0xFFF
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13279,8 +14459,13 @@ func (cpu *Cpu) disasmArm5F0(op uint32, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 		out.WriteString("!")
 	}
@@ -13289,7 +14474,7 @@ func (cpu *Cpu) disasmArm5F0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm600(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13309,6 +14494,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13339,7 +14528,7 @@ func (cpu *Cpu) opArm601(op uint32) {
 
 func (cpu *Cpu) opArm602(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13358,6 +14547,10 @@ func (cpu *Cpu) opArm602(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13366,7 +14559,7 @@ func (cpu *Cpu) opArm602(op uint32) {
 
 func (cpu *Cpu) opArm604(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13385,6 +14578,10 @@ func (cpu *Cpu) opArm604(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13393,7 +14590,7 @@ func (cpu *Cpu) opArm604(op uint32) {
 
 func (cpu *Cpu) opArm606(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13415,6 +14612,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13423,7 +14624,7 @@ op2end:
 
 func (cpu *Cpu) opArm610(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13446,6 +14647,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13476,7 +14681,7 @@ func (cpu *Cpu) disasmArm610(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm612(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13498,6 +14703,10 @@ func (cpu *Cpu) opArm612(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13511,7 +14720,7 @@ func (cpu *Cpu) opArm612(op uint32) {
 
 func (cpu *Cpu) opArm614(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13533,6 +14742,10 @@ func (cpu *Cpu) opArm614(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13546,7 +14759,7 @@ func (cpu *Cpu) opArm614(op uint32) {
 
 func (cpu *Cpu) opArm616(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13571,6 +14784,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13584,7 +14801,7 @@ op2end:
 
 func (cpu *Cpu) opArm620(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13604,6 +14821,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -13611,9 +14832,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm620(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "-" + cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm622(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13632,6 +14870,10 @@ func (cpu *Cpu) opArm622(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -13641,7 +14883,7 @@ func (cpu *Cpu) opArm622(op uint32) {
 
 func (cpu *Cpu) opArm624(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13660,6 +14902,10 @@ func (cpu *Cpu) opArm624(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -13669,7 +14915,7 @@ func (cpu *Cpu) opArm624(op uint32) {
 
 func (cpu *Cpu) opArm626(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13691,6 +14937,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -13700,7 +14950,7 @@ op2end:
 
 func (cpu *Cpu) opArm630(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13723,6 +14973,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13735,9 +14989,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm630(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "-" + cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm632(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13759,6 +15030,10 @@ func (cpu *Cpu) opArm632(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13773,7 +15048,7 @@ func (cpu *Cpu) opArm632(op uint32) {
 
 func (cpu *Cpu) opArm634(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13795,6 +15070,10 @@ func (cpu *Cpu) opArm634(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13809,7 +15088,7 @@ func (cpu *Cpu) opArm634(op uint32) {
 
 func (cpu *Cpu) opArm636(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13834,6 +15113,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -13848,7 +15131,7 @@ op2end:
 
 func (cpu *Cpu) opArm640(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13868,6 +15151,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13893,7 +15180,7 @@ func (cpu *Cpu) disasmArm640(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm642(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13912,6 +15199,10 @@ func (cpu *Cpu) opArm642(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13920,7 +15211,7 @@ func (cpu *Cpu) opArm642(op uint32) {
 
 func (cpu *Cpu) opArm644(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13939,6 +15230,10 @@ func (cpu *Cpu) opArm644(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13947,7 +15242,7 @@ func (cpu *Cpu) opArm644(op uint32) {
 
 func (cpu *Cpu) opArm646(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13969,6 +15264,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.Regs[rnx] = reg(rn)
@@ -13977,7 +15276,7 @@ op2end:
 
 func (cpu *Cpu) opArm650(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -13996,6 +15295,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14026,7 +15329,7 @@ func (cpu *Cpu) disasmArm650(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm652(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14044,6 +15347,10 @@ func (cpu *Cpu) opArm652(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14057,7 +15364,7 @@ func (cpu *Cpu) opArm652(op uint32) {
 
 func (cpu *Cpu) opArm654(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14075,6 +15382,10 @@ func (cpu *Cpu) opArm654(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14088,7 +15399,7 @@ func (cpu *Cpu) opArm654(op uint32) {
 
 func (cpu *Cpu) opArm656(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14109,6 +15420,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14122,7 +15437,7 @@ op2end:
 
 func (cpu *Cpu) opArm660(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14142,16 +15457,37 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
-	// strb
-	rn -= off
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	// strb
+	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm660(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "-" + cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm662(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14170,6 +15506,10 @@ func (cpu *Cpu) opArm662(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14179,7 +15519,7 @@ func (cpu *Cpu) opArm662(op uint32) {
 
 func (cpu *Cpu) opArm664(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14198,6 +15538,10 @@ func (cpu *Cpu) opArm664(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14207,7 +15551,7 @@ func (cpu *Cpu) opArm664(op uint32) {
 
 func (cpu *Cpu) opArm666(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14229,6 +15573,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn -= off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14238,7 +15586,7 @@ op2end:
 
 func (cpu *Cpu) opArm670(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14257,6 +15605,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14269,9 +15621,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm670(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := "-" + cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm672(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14289,6 +15658,10 @@ func (cpu *Cpu) opArm672(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14303,7 +15676,7 @@ func (cpu *Cpu) opArm672(op uint32) {
 
 func (cpu *Cpu) opArm674(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14321,6 +15694,10 @@ func (cpu *Cpu) opArm674(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14335,7 +15712,7 @@ func (cpu *Cpu) opArm674(op uint32) {
 
 func (cpu *Cpu) opArm676(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14356,6 +15733,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14370,7 +15751,7 @@ op2end:
 
 func (cpu *Cpu) opArm680(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14390,6 +15771,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -14415,7 +15800,7 @@ func (cpu *Cpu) disasmArm680(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm682(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14434,6 +15819,10 @@ func (cpu *Cpu) opArm682(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -14442,7 +15831,7 @@ func (cpu *Cpu) opArm682(op uint32) {
 
 func (cpu *Cpu) opArm684(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14461,6 +15850,10 @@ func (cpu *Cpu) opArm684(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -14469,7 +15862,7 @@ func (cpu *Cpu) opArm684(op uint32) {
 
 func (cpu *Cpu) opArm686(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14491,6 +15884,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -14499,7 +15896,7 @@ op2end:
 
 func (cpu *Cpu) opArm690(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14522,6 +15919,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14552,7 +15953,7 @@ func (cpu *Cpu) disasmArm690(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm692(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14574,6 +15975,10 @@ func (cpu *Cpu) opArm692(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14587,7 +15992,7 @@ func (cpu *Cpu) opArm692(op uint32) {
 
 func (cpu *Cpu) opArm694(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14609,6 +16014,10 @@ func (cpu *Cpu) opArm694(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14622,7 +16031,7 @@ func (cpu *Cpu) opArm694(op uint32) {
 
 func (cpu *Cpu) opArm696(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14647,6 +16056,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14660,7 +16073,7 @@ op2end:
 
 func (cpu *Cpu) opArm6A0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14680,6 +16093,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14687,9 +16104,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm6A0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm6A2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14708,6 +16142,10 @@ func (cpu *Cpu) opArm6A2(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14717,7 +16155,7 @@ func (cpu *Cpu) opArm6A2(op uint32) {
 
 func (cpu *Cpu) opArm6A4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14736,6 +16174,10 @@ func (cpu *Cpu) opArm6A4(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14745,7 +16187,7 @@ func (cpu *Cpu) opArm6A4(op uint32) {
 
 func (cpu *Cpu) opArm6A6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14767,6 +16209,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -14776,7 +16222,7 @@ op2end:
 
 func (cpu *Cpu) opArm6B0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14799,6 +16245,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14811,9 +16261,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm6B0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm6B2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14835,6 +16302,10 @@ func (cpu *Cpu) opArm6B2(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14849,7 +16320,7 @@ func (cpu *Cpu) opArm6B2(op uint32) {
 
 func (cpu *Cpu) opArm6B4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14871,6 +16342,10 @@ func (cpu *Cpu) opArm6B4(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14885,7 +16360,7 @@ func (cpu *Cpu) opArm6B4(op uint32) {
 
 func (cpu *Cpu) opArm6B6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14910,6 +16385,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -14924,7 +16403,7 @@ op2end:
 
 func (cpu *Cpu) opArm6C0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14944,6 +16423,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -14969,7 +16452,7 @@ func (cpu *Cpu) disasmArm6C0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm6C2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -14988,6 +16471,10 @@ func (cpu *Cpu) opArm6C2(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -14996,7 +16483,7 @@ func (cpu *Cpu) opArm6C2(op uint32) {
 
 func (cpu *Cpu) opArm6C4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15015,6 +16502,10 @@ func (cpu *Cpu) opArm6C4(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -15023,7 +16514,7 @@ func (cpu *Cpu) opArm6C4(op uint32) {
 
 func (cpu *Cpu) opArm6C6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15045,6 +16536,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.Regs[rnx] = reg(rn)
@@ -15053,7 +16548,7 @@ op2end:
 
 func (cpu *Cpu) opArm6D0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15072,6 +16567,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15102,7 +16601,7 @@ func (cpu *Cpu) disasmArm6D0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm6D2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15120,6 +16619,10 @@ func (cpu *Cpu) opArm6D2(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15133,7 +16636,7 @@ func (cpu *Cpu) opArm6D2(op uint32) {
 
 func (cpu *Cpu) opArm6D4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15151,6 +16654,10 @@ func (cpu *Cpu) opArm6D4(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15164,7 +16671,7 @@ func (cpu *Cpu) opArm6D4(op uint32) {
 
 func (cpu *Cpu) opArm6D6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15185,6 +16692,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15198,7 +16709,7 @@ op2end:
 
 func (cpu *Cpu) opArm6E0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15218,6 +16729,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -15225,9 +16740,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm6E0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("strbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm6E2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15246,6 +16778,10 @@ func (cpu *Cpu) opArm6E2(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -15255,7 +16791,7 @@ func (cpu *Cpu) opArm6E2(op uint32) {
 
 func (cpu *Cpu) opArm6E4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15274,6 +16810,10 @@ func (cpu *Cpu) opArm6E4(op uint32) {
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -15283,7 +16823,7 @@ func (cpu *Cpu) opArm6E4(op uint32) {
 
 func (cpu *Cpu) opArm6E6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15305,6 +16845,10 @@ op2end:
 	off := op2
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	rn += off
 	cpu.InvalidOpArm(op, "forced-unprivileged memory access")
@@ -15314,7 +16858,7 @@ op2end:
 
 func (cpu *Cpu) opArm6F0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15333,6 +16877,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15345,9 +16893,26 @@ op2end:
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm6F0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrbt", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := (op >> 16) & 0xF
+	out.WriteString("[")
+	out.WriteString(RegNames[arg1])
+	out.WriteString("]")
+	out.WriteString(", ")
+	arg2 := cpu.disasmOp2(op)
+	out.WriteString(arg2)
+	return out.String()
+}
+
 func (cpu *Cpu) opArm6F2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15365,6 +16930,10 @@ func (cpu *Cpu) opArm6F2(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15379,7 +16948,7 @@ func (cpu *Cpu) opArm6F2(op uint32) {
 
 func (cpu *Cpu) opArm6F4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15397,6 +16966,10 @@ func (cpu *Cpu) opArm6F4(op uint32) {
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15411,7 +16984,7 @@ func (cpu *Cpu) opArm6F4(op uint32) {
 
 func (cpu *Cpu) opArm6F6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15432,6 +17005,10 @@ op2end:
 	_ = cf
 	off := op2
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15446,7 +17023,7 @@ op2end:
 
 func (cpu *Cpu) opArm700(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15490,7 +17067,7 @@ func (cpu *Cpu) disasmArm700(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm702(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15516,7 +17093,7 @@ func (cpu *Cpu) opArm702(op uint32) {
 
 func (cpu *Cpu) opArm704(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15542,7 +17119,7 @@ func (cpu *Cpu) opArm704(op uint32) {
 
 func (cpu *Cpu) opArm706(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15571,7 +17148,7 @@ op2end:
 
 func (cpu *Cpu) opArm710(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15595,6 +17172,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15623,7 +17204,7 @@ func (cpu *Cpu) disasmArm710(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm712(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15646,6 +17227,10 @@ func (cpu *Cpu) opArm712(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15657,7 +17242,7 @@ func (cpu *Cpu) opArm712(op uint32) {
 
 func (cpu *Cpu) opArm714(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15680,6 +17265,10 @@ func (cpu *Cpu) opArm714(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15691,7 +17280,7 @@ func (cpu *Cpu) opArm714(op uint32) {
 
 func (cpu *Cpu) opArm716(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15717,6 +17306,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15728,7 +17321,7 @@ op2end:
 
 func (cpu *Cpu) opArm720(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15749,6 +17342,10 @@ op2end:
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -15774,7 +17371,7 @@ func (cpu *Cpu) disasmArm720(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm722(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15794,6 +17391,10 @@ func (cpu *Cpu) opArm722(op uint32) {
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -15801,7 +17402,7 @@ func (cpu *Cpu) opArm722(op uint32) {
 
 func (cpu *Cpu) opArm724(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15821,6 +17422,10 @@ func (cpu *Cpu) opArm724(op uint32) {
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -15828,7 +17433,7 @@ func (cpu *Cpu) opArm724(op uint32) {
 
 func (cpu *Cpu) opArm726(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15851,6 +17456,10 @@ op2end:
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -15858,7 +17467,7 @@ op2end:
 
 func (cpu *Cpu) opArm730(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15882,6 +17491,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15912,7 +17525,7 @@ func (cpu *Cpu) disasmArm730(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm732(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15935,6 +17548,10 @@ func (cpu *Cpu) opArm732(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15947,7 +17564,7 @@ func (cpu *Cpu) opArm732(op uint32) {
 
 func (cpu *Cpu) opArm734(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -15970,6 +17587,10 @@ func (cpu *Cpu) opArm734(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -15982,7 +17603,7 @@ func (cpu *Cpu) opArm734(op uint32) {
 
 func (cpu *Cpu) opArm736(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16008,6 +17629,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16020,7 +17645,7 @@ op2end:
 
 func (cpu *Cpu) opArm740(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16064,7 +17689,7 @@ func (cpu *Cpu) disasmArm740(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm742(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16090,7 +17715,7 @@ func (cpu *Cpu) opArm742(op uint32) {
 
 func (cpu *Cpu) opArm744(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16116,7 +17741,7 @@ func (cpu *Cpu) opArm744(op uint32) {
 
 func (cpu *Cpu) opArm746(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16145,7 +17770,15 @@ op2end:
 
 func (cpu *Cpu) opArm750(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16165,6 +17798,10 @@ op2end:
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16175,8 +17812,20 @@ op2end:
 }
 
 func (cpu *Cpu) disasmArm750(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("pld       ")
+		arg0a := (op >> 16) & 0xF
+		arg0b := "-" + cpu.disasmOp2(op)
+		out.WriteString("[")
+		out.WriteString(RegNames[arg0a])
+		out.WriteString(", ")
+		out.WriteString(arg0b)
+		out.WriteString("]")
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldrb", op)
 	out.WriteString((opcode + "                ")[:10])
 	arg0 := (op >> 12) & 0xF
 	out.WriteString(RegNames[arg0])
@@ -16193,7 +17842,15 @@ func (cpu *Cpu) disasmArm750(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm752(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16212,6 +17869,10 @@ func (cpu *Cpu) opArm752(op uint32) {
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16223,7 +17884,15 @@ func (cpu *Cpu) opArm752(op uint32) {
 
 func (cpu *Cpu) opArm754(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16242,6 +17911,10 @@ func (cpu *Cpu) opArm754(op uint32) {
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16253,7 +17926,15 @@ func (cpu *Cpu) opArm754(op uint32) {
 
 func (cpu *Cpu) opArm756(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16275,6 +17956,10 @@ op2end:
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16286,7 +17971,7 @@ op2end:
 
 func (cpu *Cpu) opArm760(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16307,6 +17992,10 @@ op2end:
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16332,7 +18021,7 @@ func (cpu *Cpu) disasmArm760(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm762(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16352,6 +18041,10 @@ func (cpu *Cpu) opArm762(op uint32) {
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16359,7 +18052,7 @@ func (cpu *Cpu) opArm762(op uint32) {
 
 func (cpu *Cpu) opArm764(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16379,6 +18072,10 @@ func (cpu *Cpu) opArm764(op uint32) {
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16386,7 +18083,7 @@ func (cpu *Cpu) opArm764(op uint32) {
 
 func (cpu *Cpu) opArm766(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16409,6 +18106,10 @@ op2end:
 	rn -= off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16416,7 +18117,7 @@ op2end:
 
 func (cpu *Cpu) opArm770(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16436,6 +18137,10 @@ op2end:
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16466,7 +18171,7 @@ func (cpu *Cpu) disasmArm770(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm772(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16485,6 +18190,10 @@ func (cpu *Cpu) opArm772(op uint32) {
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16497,7 +18206,7 @@ func (cpu *Cpu) opArm772(op uint32) {
 
 func (cpu *Cpu) opArm774(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16516,6 +18225,10 @@ func (cpu *Cpu) opArm774(op uint32) {
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16528,7 +18241,7 @@ func (cpu *Cpu) opArm774(op uint32) {
 
 func (cpu *Cpu) opArm776(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16550,6 +18263,10 @@ op2end:
 	off := op2
 	rn -= off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16562,7 +18279,7 @@ op2end:
 
 func (cpu *Cpu) opArm780(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16606,7 +18323,7 @@ func (cpu *Cpu) disasmArm780(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm782(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16632,7 +18349,7 @@ func (cpu *Cpu) opArm782(op uint32) {
 
 func (cpu *Cpu) opArm784(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16658,7 +18375,7 @@ func (cpu *Cpu) opArm784(op uint32) {
 
 func (cpu *Cpu) opArm786(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16687,7 +18404,7 @@ op2end:
 
 func (cpu *Cpu) opArm790(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16711,6 +18428,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16739,7 +18460,7 @@ func (cpu *Cpu) disasmArm790(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm792(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16762,6 +18483,10 @@ func (cpu *Cpu) opArm792(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16773,7 +18498,7 @@ func (cpu *Cpu) opArm792(op uint32) {
 
 func (cpu *Cpu) opArm794(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16796,6 +18521,10 @@ func (cpu *Cpu) opArm794(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16807,7 +18536,7 @@ func (cpu *Cpu) opArm794(op uint32) {
 
 func (cpu *Cpu) opArm796(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16833,6 +18562,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -16844,7 +18577,7 @@ op2end:
 
 func (cpu *Cpu) opArm7A0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16865,6 +18598,10 @@ op2end:
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16890,7 +18627,7 @@ func (cpu *Cpu) disasmArm7A0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm7A2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16910,6 +18647,10 @@ func (cpu *Cpu) opArm7A2(op uint32) {
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16917,7 +18658,7 @@ func (cpu *Cpu) opArm7A2(op uint32) {
 
 func (cpu *Cpu) opArm7A4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16937,6 +18678,10 @@ func (cpu *Cpu) opArm7A4(op uint32) {
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16944,7 +18689,7 @@ func (cpu *Cpu) opArm7A4(op uint32) {
 
 func (cpu *Cpu) opArm7A6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16967,6 +18712,10 @@ op2end:
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write32(rn, uint32(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// str
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -16974,7 +18723,7 @@ op2end:
 
 func (cpu *Cpu) opArm7B0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -16998,6 +18747,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17028,7 +18781,7 @@ func (cpu *Cpu) disasmArm7B0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm7B2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17051,6 +18804,10 @@ func (cpu *Cpu) opArm7B2(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17063,7 +18820,7 @@ func (cpu *Cpu) opArm7B2(op uint32) {
 
 func (cpu *Cpu) opArm7B4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17086,6 +18843,10 @@ func (cpu *Cpu) opArm7B4(op uint32) {
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17098,7 +18859,7 @@ func (cpu *Cpu) opArm7B4(op uint32) {
 
 func (cpu *Cpu) opArm7B6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17124,6 +18885,10 @@ op2end:
 		rot := (rn & 3) * 8
 		res = (res >> rot) | (res << (32 - rot))
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17136,7 +18901,7 @@ op2end:
 
 func (cpu *Cpu) opArm7C0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17180,7 +18945,7 @@ func (cpu *Cpu) disasmArm7C0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm7C2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17206,7 +18971,7 @@ func (cpu *Cpu) opArm7C2(op uint32) {
 
 func (cpu *Cpu) opArm7C4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17232,7 +18997,7 @@ func (cpu *Cpu) opArm7C4(op uint32) {
 
 func (cpu *Cpu) opArm7C6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17261,7 +19026,15 @@ op2end:
 
 func (cpu *Cpu) opArm7D0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17281,6 +19054,10 @@ op2end:
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17291,6 +19068,18 @@ op2end:
 }
 
 func (cpu *Cpu) disasmArm7D0(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("pld       ")
+		arg0a := (op >> 16) & 0xF
+		arg0b := cpu.disasmOp2(op)
+		out.WriteString("[")
+		out.WriteString(RegNames[arg0a])
+		out.WriteString(", ")
+		out.WriteString(arg0b)
+		out.WriteString("]")
+		return out.String()
+	}
 	var out bytes.Buffer
 	opcode := cpu.disasmAddCond("ldrb", op)
 	out.WriteString((opcode + "                ")[:10])
@@ -17309,7 +19098,15 @@ func (cpu *Cpu) disasmArm7D0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm7D2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17328,6 +19125,10 @@ func (cpu *Cpu) opArm7D2(op uint32) {
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17339,7 +19140,15 @@ func (cpu *Cpu) opArm7D2(op uint32) {
 
 func (cpu *Cpu) opArm7D4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17358,6 +19167,10 @@ func (cpu *Cpu) opArm7D4(op uint32) {
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17369,7 +19182,15 @@ func (cpu *Cpu) opArm7D4(op uint32) {
 
 func (cpu *Cpu) opArm7D6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		if cpu.arch < ARMv5 {
+			cpu.Exception(ExceptionUndefined)
+			return
+		}
+		if op&0x0000F000 != 0x0000F000 {
+			cpu.InvalidOpArm(op, "invalid opcode decoded as PLD")
+			return
+		}
+		cpu.Clock += 1
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17391,6 +19212,10 @@ op2end:
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17402,7 +19227,7 @@ op2end:
 
 func (cpu *Cpu) opArm7E0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17423,6 +19248,10 @@ op2end:
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -17448,7 +19277,7 @@ func (cpu *Cpu) disasmArm7E0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm7E2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17468,6 +19297,10 @@ func (cpu *Cpu) opArm7E2(op uint32) {
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -17475,7 +19308,7 @@ func (cpu *Cpu) opArm7E2(op uint32) {
 
 func (cpu *Cpu) opArm7E4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17495,6 +19328,10 @@ func (cpu *Cpu) opArm7E4(op uint32) {
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -17502,7 +19339,7 @@ func (cpu *Cpu) opArm7E4(op uint32) {
 
 func (cpu *Cpu) opArm7E6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17525,6 +19362,10 @@ op2end:
 	rn += off
 	rd := cpu.Regs[rdx]
 	cpu.Write8(rn, uint8(rd))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	// strb
 	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
@@ -17532,7 +19373,7 @@ op2end:
 
 func (cpu *Cpu) opArm7F0(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17552,6 +19393,10 @@ op2end:
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17582,7 +19427,7 @@ func (cpu *Cpu) disasmArm7F0(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArm7F2(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17601,6 +19446,10 @@ func (cpu *Cpu) opArm7F2(op uint32) {
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17613,7 +19462,7 @@ func (cpu *Cpu) opArm7F2(op uint32) {
 
 func (cpu *Cpu) opArm7F4(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17632,6 +19481,10 @@ func (cpu *Cpu) opArm7F4(op uint32) {
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17644,7 +19497,7 @@ func (cpu *Cpu) opArm7F4(op uint32) {
 
 func (cpu *Cpu) opArm7F6(op uint32) {
 	if (op >> 28) == 0xF {
-		cpu.InvalidOpArm(op, "PLD not supported")
+		cpu.InvalidOpArm(op, "invalid NV opcode in LDR/STR space")
 		return
 	}
 	rnx := (op >> 16) & 0xF
@@ -17666,6 +19519,10 @@ op2end:
 	off := op2
 	rn += off
 	res := uint32(cpu.Read8(rn))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[rdx] = reg(res)
 	if rdx == 15 {
 		cpu.Cpsr.SetT((res & 1) != 0)
@@ -17685,16 +19542,23 @@ func (cpu *Cpu) opArm800(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	cpu.Clock += 1
 }
@@ -17731,24 +19595,37 @@ func (cpu *Cpu) opArm810(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
+		}
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
 		}
-		mask >>= 1
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
 	cpu.Clock += 1
 }
@@ -17786,19 +19663,34 @@ func (cpu *Cpu) opArm820(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
 	}
-	cpu.Regs[rnx] = reg(orn)
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
@@ -17835,27 +19727,42 @@ func (cpu *Cpu) opArm830(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
-	cpu.Regs[rnx] = reg(orn)
 	cpu.Clock += 1
 }
 
@@ -17893,21 +19800,28 @@ func (cpu *Cpu) opArm840(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
 	cpu.Regs[15] += 4
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
@@ -17915,6 +19829,31 @@ func (cpu *Cpu) opArm840(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm840(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stmda", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm850(op uint32) {
 	// ldmda
 	rnx := (op >> 16) & 0xF
@@ -17923,37 +19862,74 @@ func (cpu *Cpu) opArm850(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm850(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldmda", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm860(op uint32) {
 	// stmda
 	rnx := (op >> 16) & 0xF
@@ -17963,30 +19939,71 @@ func (cpu *Cpu) opArm860(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
 	cpu.Regs[15] += 4
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(orn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm860(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stmda", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm870(op uint32) {
 	// ldmda
 	rnx := (op >> 16) & 0xF
@@ -17995,39 +20012,79 @@ func (cpu *Cpu) opArm870(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
-	usrbnk := (mask & 0x8000) == 0
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
+	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(orn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm870(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldmda", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm880(op uint32) {
 	// stm
 	rnx := (op >> 16) & 0xF
@@ -18037,15 +20094,18 @@ func (cpu *Cpu) opArm880(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	cpu.Clock += 1
 }
@@ -18082,23 +20142,32 @@ func (cpu *Cpu) opArm890(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
 	cpu.Clock += 1
 }
@@ -18136,17 +20205,33 @@ func (cpu *Cpu) opArm8A0(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(rn)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
@@ -18183,25 +20268,41 @@ func (cpu *Cpu) opArm8B0(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
-	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
 
@@ -18239,20 +20340,23 @@ func (cpu *Cpu) opArm8C0(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
 	cpu.Regs[15] += 4
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
@@ -18260,6 +20364,31 @@ func (cpu *Cpu) opArm8C0(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm8C0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stm", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm8D0(op uint32) {
 	// ldm
 	rnx := (op >> 16) & 0xF
@@ -18268,36 +20397,69 @@ func (cpu *Cpu) opArm8D0(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm8D0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldm", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm8E0(op uint32) {
 	// stm
 	rnx := (op >> 16) & 0xF
@@ -18307,28 +20469,70 @@ func (cpu *Cpu) opArm8E0(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
 	cpu.Regs[15] += 4
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(rn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm8E0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stm", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm8F0(op uint32) {
 	// ldm
 	rnx := (op >> 16) & 0xF
@@ -18337,37 +20541,78 @@ func (cpu *Cpu) opArm8F0(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(rn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm8F0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldm", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm900(op uint32) {
 	// stmdb
 	rnx := (op >> 16) & 0xF
@@ -18377,16 +20622,23 @@ func (cpu *Cpu) opArm900(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	cpu.Clock += 1
 }
@@ -18423,24 +20675,37 @@ func (cpu *Cpu) opArm910(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
 	cpu.Clock += 1
 }
@@ -18478,19 +20743,34 @@ func (cpu *Cpu) opArm920(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(orn)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
@@ -18527,27 +20807,42 @@ func (cpu *Cpu) opArm930(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
-	cpu.Regs[rnx] = reg(orn)
 	cpu.Clock += 1
 }
 
@@ -18585,21 +20880,28 @@ func (cpu *Cpu) opArm940(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
 	cpu.Regs[15] += 4
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
@@ -18607,6 +20909,31 @@ func (cpu *Cpu) opArm940(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm940(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stmdb", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm950(op uint32) {
 	// ldmdb
 	rnx := (op >> 16) & 0xF
@@ -18615,70 +20942,148 @@ func (cpu *Cpu) opArm950(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) opArm960(op uint32) {
-	// stmdb
-	rnx := (op >> 16) & 0xF
-	if rnx == 15 {
-		cpu.InvalidOpArm(op, "invalid use of PC in LDM/STM")
-		return
-	}
-	rn := uint32(cpu.Regs[rnx])
+func (cpu *Cpu) disasmArm950(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldmdb", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
+func (cpu *Cpu) opArm960(op uint32) {
+	// stmdb
+	rnx := (op >> 16) & 0xF
+	if rnx == 15 {
+		cpu.InvalidOpArm(op, "invalid use of PC in LDM/STM")
+		return
+	}
+	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
 	cpu.Regs[15] += 4
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			var val uint32
-			val = uint32(cpu.Regs[i])
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(orn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm960(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stmdb", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm970(op uint32) {
 	// ldmdb
 	rnx := (op >> 16) & 0xF
@@ -18687,39 +21092,79 @@ func (cpu *Cpu) opArm970(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	rn -= uint32(4 * popcount16(mask))
-	orn := rn
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	rn -= size
+	wbrn := rn
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 			rn += 4
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(orn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm970(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldmdb", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm980(op uint32) {
 	// stmib
 	rnx := (op >> 16) & 0xF
@@ -18729,15 +21174,18 @@ func (cpu *Cpu) opArm980(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	cpu.Clock += 1
 }
@@ -18774,23 +21222,32 @@ func (cpu *Cpu) opArm990(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
+		}
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
 		}
-		mask >>= 1
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
 	cpu.Clock += 1
 }
@@ -18828,17 +21285,33 @@ func (cpu *Cpu) opArm9A0(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
 	cpu.Regs[15] += 4
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(rn)
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
@@ -18875,25 +21348,41 @@ func (cpu *Cpu) opArm9B0(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 		}
-		mask >>= 1
+		m >>= 1
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		if cpu.arch >= ARMv5 && cpu.Regs[15]&1 != 0 {
+			cpu.Cpsr.SetT(true)
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
 	}
-	cpu.Regs[rnx] = reg(rn)
 	cpu.Clock += 1
 }
 
@@ -18931,20 +21420,23 @@ func (cpu *Cpu) opArm9C0(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
 	cpu.Regs[15] += 4
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
@@ -18952,6 +21444,31 @@ func (cpu *Cpu) opArm9C0(op uint32) {
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm9C0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stmib", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm9D0(op uint32) {
 	// ldmib
 	rnx := (op >> 16) & 0xF
@@ -18960,36 +21477,69 @@ func (cpu *Cpu) opArm9D0(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
+	if mask == 0 && cpu.arch < ARMv5 {
+		mask = 0x8000
+	}
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
-			}
+			cpu.Regs[i] = reg(cpu.Read32(rn))
 		}
-		mask >>= 1
+		m >>= 1
 	}
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm9D0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldmib", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm9E0(op uint32) {
 	// stmib
 	rnx := (op >> 16) & 0xF
@@ -18999,28 +21549,70 @@ func (cpu *Cpu) opArm9E0(op uint32) {
 	}
 	rn := uint32(cpu.Regs[rnx])
 	mask := uint16(op & 0xFFFF)
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
 	cpu.Regs[15] += 4
+	newbase := cpu.arch < ARMv5 && mask&((1<<rnx)-1) != 0
 	usrbnk := true
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			var val uint32
-			val = uint32(cpu.Regs[i])
+			val := uint32(cpu.Regs[i])
+			if i == int(rnx) && newbase {
+				val = wbrn
+			}
 			cpu.Write32(rn, val)
 		}
-		mask >>= 1
+		m >>= 1
 	}
-	cpu.Regs[rnx] = reg(rn)
 	if usrbnk {
 		cpu.Cpsr.SetMode(oldmode, cpu)
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rnx] = reg(wbrn)
 	cpu.Clock += 1
 }
 
+func (cpu *Cpu) disasmArm9E0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stmib", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
+			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
 func (cpu *Cpu) opArm9F0(op uint32) {
 	// ldmib
 	rnx := (op >> 16) & 0xF
@@ -19029,130 +21621,1518 @@ func (cpu *Cpu) opArm9F0(op uint32) {
 		return
 	}
 	rn := uint32(cpu.Regs[rnx])
+	base := cpu.Regs[rnx]
 	mask := uint16(op & 0xFFFF)
+	size := uint32(4 * popcount16(mask))
+	if mask == 0 {
+		size = 0x40
+		if cpu.arch < ARMv5 {
+			mask = 0x8000
+		}
+	}
+	wbrn := rn + size
 	usrbnk := (mask & 0x8000) == 0
 	oldmode := cpu.Cpsr.GetMode()
 	if usrbnk {
 		cpu.Cpsr.SetMode(CpuModeUser, cpu)
 	}
-	for i := 0; mask != 0; i++ {
-		if mask&1 != 0 {
+	m := mask
+	for i := 0; m != 0; i++ {
+		if m&1 != 0 {
 			rn += 4
-			val := reg(cpu.Read32(rn))
-			cpu.Regs[i] = val
-			if i == 15 {
-				cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
-				if cpu.Regs[15]&1 != 0 {
-					cpu.Cpsr.SetT(true)
-					cpu.Regs[15] &^= 1
-				} else {
-					cpu.Regs[15] &^= 3
-				}
-				cpu.branch(cpu.Regs[15], BranchJump)
+			cpu.Regs[i] = reg(cpu.Read32(rn))
+		}
+		m >>= 1
+	}
+	if usrbnk {
+		cpu.Cpsr.SetMode(oldmode, cpu)
+	}
+	if cpu.abort {
+		cpu.Regs[rnx] = base
+		cpu.Clock += 1
+		return
+	}
+	if mask&(1<<rnx) == 0 || (cpu.arch >= ARMv5 && (mask == 1<<rnx || mask>>(rnx+1) != 0)) {
+		cpu.Regs[rnx] = reg(wbrn)
+	}
+	if mask&0x8000 != 0 {
+		cpu.Cpsr.Set(uint32(*cpu.RegSpsr()), cpu)
+		if cpu.Cpsr.T() {
+			cpu.Regs[15] &^= 1
+		} else {
+			cpu.Regs[15] &^= 3
+		}
+		cpu.branch(cpu.Regs[15], BranchJump)
+	}
+	cpu.Clock += 1
+}
+
+func (cpu *Cpu) disasmArm9F0(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldmib", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg0])
+	out.WriteString("!")
+	out.WriteString(", ")
+	arg1 := uint16(op & 0xFFFF)
+	out.WriteString("{")
+	for i := 0; arg1 != 0; i++ {
+		if arg1&1 != 0 {
+			out.WriteString(RegNames[i])
+			arg1 >>= 1
+			if arg1 != 0 {
+				out.WriteString(", ")
 			}
+		} else {
+			arg1 >>= 1
+		}
+	}
+	out.WriteString("}")
+	out.WriteString("^")
+	return out.String()
+}
+
+func (cpu *Cpu) opArmA00(op uint32) {
+	if op>>28 == 0xF {
+		// BLX_imm
+		off := int32(op<<8) >> 6
+		cpu.Regs[14] = cpu.Regs[15] - 4
+		cpu.Regs[15] += reg(off)
+		cpu.Cpsr.SetT(true)
+		cpu.branch(cpu.Regs[15], BranchCall)
+		return
+	}
+	// B
+	off := int32(op<<8) >> 6
+	cpu.Regs[15] += reg(off)
+	cpu.branch(cpu.Regs[15], BranchCall)
+}
+
+func (cpu *Cpu) disasmArmA00(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("blx       ")
+		arg0 := int32(int32(op<<8) >> 6)
+		arg0x := pc + 8 + uint32(arg0)
+		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("b", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := int32(int32(op<<8) >> 6)
+	arg0x := pc + 8 + uint32(arg0)
+	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+	return out.String()
+}
+
+func (cpu *Cpu) opArmB00(op uint32) {
+	if op>>28 == 0xF {
+		// BLX_imm
+		off := int32(op<<8) >> 6
+		cpu.Regs[14] = cpu.Regs[15] - 4
+		cpu.Regs[15] += reg(off)
+		cpu.Regs[15] += 2
+		cpu.Cpsr.SetT(true)
+		cpu.branch(cpu.Regs[15], BranchCall)
+		return
+	}
+	// BL
+	off := int32(op<<8) >> 6
+	cpu.Regs[14] = cpu.Regs[15] - 4
+	cpu.Regs[15] += reg(off)
+	cpu.branch(cpu.Regs[15], BranchCall)
+}
+
+func (cpu *Cpu) disasmArmB00(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("blx       ")
+		arg0 := int32(int32(op<<8)>>6 + 2)
+		arg0x := pc + 8 + uint32(arg0)
+		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("bl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := int32(int32(op<<8) >> 6)
+	arg0x := pc + 8 + uint32(arg0)
+	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+	return out.String()
+}
+
+func (cpu *Cpu) opArmC00(op uint32) {
+	// stc
+	if cpu.opCopMissing((op >> 8) & 0xF) {
+		return
+	}
+	cpu.InvalidOpArm(op, "stc not implemented")
+}
+
+func (cpu *Cpu) disasmArmC00(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) opArmC10(op uint32) {
+	// ldc
+	if cpu.opCopMissing((op >> 8) & 0xF) {
+		return
+	}
+	cpu.InvalidOpArm(op, "ldc not implemented")
+}
+
+func (cpu *Cpu) disasmArmC10(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) opArmC40(op uint32) {
+	// mcrr
+	if cpu.opCopMissing((op >> 8) & 0xF) {
+		return
+	}
+	cpu.InvalidOpArm(op, "mcrr not implemented")
+}
+
+func (cpu *Cpu) disasmArmC40(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("mcrr", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := int64((op >> 4) & 0xF)
+	out.WriteString("#")
+	out.WriteString(strconv.FormatInt(arg1, 10))
+	out.WriteString(", ")
+	arg2 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg2])
+	out.WriteString(", ")
+	arg3 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg3])
+	out.WriteString(", ")
+	arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
+	out.WriteString(arg4)
+	return out.String()
+}
+
+func (cpu *Cpu) opArmC50(op uint32) {
+	// mrrc
+	if cpu.opCopMissing((op >> 8) & 0xF) {
+		return
+	}
+	cpu.InvalidOpArm(op, "mrrc not implemented")
+}
+
+func (cpu *Cpu) disasmArmC50(op uint32, pc uint32) string {
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("mrrc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := int64((op >> 4) & 0xF)
+	out.WriteString("#")
+	out.WriteString(strconv.FormatInt(arg1, 10))
+	out.WriteString(", ")
+	arg2 := (op >> 12) & 0xF
+	out.WriteString(RegNames[arg2])
+	out.WriteString(", ")
+	arg3 := (op >> 16) & 0xF
+	out.WriteString(RegNames[arg3])
+	out.WriteString(", ")
+	arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
+	out.WriteString(arg4)
+	return out.String()
+}
+
+func (cpu *Cpu) opArmC60(op uint32) {
+	// stcl
+	if cpu.opCopMissing((op >> 8) & 0xF) {
+		return
+	}
+	cpu.InvalidOpArm(op, "stcl not implemented")
+}
+
+func (cpu *Cpu) disasmArmC60(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) opArmC70(op uint32) {
+	// ldcl
+	if cpu.opCopMissing((op >> 8) & 0xF) {
+		return
+	}
+	cpu.InvalidOpArm(op, "ldcl not implemented")
+}
+
+func (cpu *Cpu) disasmArmC70(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(-int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmC80(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmC90(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmCC0(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmCD0(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+		out.WriteString(arg2)
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2 := "[" + RegNames[(op>>16)&0xF] + "], #" + strconv.FormatInt(int64(int32(op&0xFF)*4), 10)
+	out.WriteString(arg2)
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD00(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD10(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD20(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD30(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD40(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD50(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD60(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD70(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := -int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := -int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD80(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmD90(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmDA0(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("stc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
+	return out.String()
+}
+
+func (cpu *Cpu) disasmArmDB0(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("ldc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
+	}
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("ldc", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
 		}
-		mask >>= 1
-	}
-	cpu.Regs[rnx] = reg(rn)
-	if usrbnk {
-		cpu.Cpsr.SetMode(oldmode, cpu)
+		out.WriteString("]")
+		out.WriteString("!")
 	}
-	cpu.Clock += 1
+	return out.String()
 }
 
-func (cpu *Cpu) opArmA00(op uint32) {
+func (cpu *Cpu) disasmArmDC0(op uint32, pc uint32) string {
 	if op>>28 == 0xF {
-		// BLX_imm
-		off := int32(op<<8) >> 6
-		cpu.Regs[14] = cpu.Regs[15] - 4
-		cpu.Regs[15] += reg(off)
-		cpu.Cpsr.SetT(true)
-		cpu.branch(cpu.Regs[15], BranchCall)
-		return
+		var out bytes.Buffer
+		out.WriteString("stc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
+		return out.String()
 	}
-	// B
-	off := int32(op<<8) >> 6
-	cpu.Regs[15] += reg(off)
-	cpu.branch(cpu.Regs[15], BranchCall)
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
+	return out.String()
 }
 
-func (cpu *Cpu) disasmArmA00(op uint32, pc uint32) string {
+func (cpu *Cpu) disasmArmDD0(op uint32, pc uint32) string {
 	if op>>28 == 0xF {
 		var out bytes.Buffer
-		out.WriteString("blx       ")
-		arg0 := int32(int32(op<<8) >> 6)
-		arg0x := pc + 8 + uint32(arg0)
-		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+		out.WriteString("ldc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !false {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+		}
 		return out.String()
 	}
 	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("b", op)
+	opcode := cpu.disasmAddCond("ldcl", op)
 	out.WriteString((opcode + "                ")[:10])
-	arg0 := int32(int32(op<<8) >> 6)
-	arg0x := pc + 8 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !false {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+	}
 	return out.String()
 }
 
-func (cpu *Cpu) opArmB00(op uint32) {
+func (cpu *Cpu) disasmArmDE0(op uint32, pc uint32) string {
 	if op>>28 == 0xF {
-		// BLX_imm
-		off := int32(op<<8) >> 6
-		cpu.Regs[14] = cpu.Regs[15] - 4
-		cpu.Regs[15] += reg(off)
-		cpu.Regs[15] += 2
-		cpu.Cpsr.SetT(true)
-		cpu.branch(cpu.Regs[15], BranchCall)
-		return
+		var out bytes.Buffer
+		out.WriteString("stc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
+		return out.String()
 	}
-	// BL
-	off := int32(op<<8) >> 6
-	cpu.Regs[14] = cpu.Regs[15] - 4
-	cpu.Regs[15] += reg(off)
-	cpu.branch(cpu.Regs[15], BranchCall)
+	var out bytes.Buffer
+	opcode := cpu.disasmAddCond("stcl", op)
+	out.WriteString((opcode + "                ")[:10])
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
+	return out.String()
 }
 
-func (cpu *Cpu) disasmArmB00(op uint32, pc uint32) string {
+func (cpu *Cpu) disasmArmDF0(op uint32, pc uint32) string {
 	if op>>28 == 0xF {
 		var out bytes.Buffer
-		out.WriteString("blx       ")
-		arg0 := int32(int32(op<<8) >> 6)
-		arg0x := pc + 8 + uint32(arg0)
-		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+		out.WriteString("ldc2l     ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg1)
+		out.WriteString(", ")
+		arg2a := (op >> 16) & 0xF
+		arg2b := int32(op&0xFF) * 4
+		if RegNames[arg2a] == "pc" && !true {
+			arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+			arg2v := cpu.Read32(arg2c)
+			out.WriteString("= 0x")
+			out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+		} else {
+			out.WriteString("[")
+			out.WriteString(RegNames[arg2a])
+			if int64(arg2b) < 0 {
+				out.WriteString(", #-0x")
+				out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+			} else {
+				out.WriteString(", #0x")
+				out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+			}
+			out.WriteString("]")
+			out.WriteString("!")
+		}
 		return out.String()
 	}
 	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bl", op)
+	opcode := cpu.disasmAddCond("ldcl", op)
 	out.WriteString((opcode + "                ")[:10])
-	arg0 := int32(int32(op<<8) >> 6)
-	arg0x := pc + 8 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
+	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+	out.WriteString(arg0)
+	out.WriteString(", ")
+	arg1 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+	out.WriteString(arg1)
+	out.WriteString(", ")
+	arg2a := (op >> 16) & 0xF
+	arg2b := int32(op&0xFF) * 4
+	if RegNames[arg2a] == "pc" && !true {
+		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
+		arg2v := cpu.Read32(arg2c)
+		out.WriteString("= 0x")
+		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
+	} else {
+		out.WriteString("[")
+		out.WriteString(RegNames[arg2a])
+		if int64(arg2b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg2b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg2b), 16))
+		}
+		out.WriteString("]")
+		out.WriteString("!")
+	}
 	return out.String()
 }
 
-func (cpu *Cpu) opArmC00(op uint32) {
-	cpu.InvalidOpArm(op, "unimplemented")
-}
-
 func (cpu *Cpu) opArmE00(op uint32) {
 	// CDP
+	copnum := (op >> 8) & 0xF
+	if cpu.opCopMissing(copnum) {
+		return
+	}
 	opc := (op >> 21) & 0x7
 	cn := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
-	copnum := (op >> 8) & 0xF
 	cp := (op >> 5) & 0x7
 	cm := (op >> 0) & 0xF
 	cpu.opCopExec(copnum, opc, cn, cm, cp, rdx)
 }
 
 func (cpu *Cpu) disasmArmE00(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("cdp2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := int64((op >> 20) & 0xF)
+		out.WriteString("#")
+		out.WriteString(strconv.FormatInt(arg1, 10))
+		out.WriteString(", ")
+		arg2 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
+		out.WriteString(arg2)
+		out.WriteString(", ")
+		arg3 := "c" + strconv.FormatInt(int64(op>>16)&0xF, 10)
+		out.WriteString(arg3)
+		out.WriteString(", ")
+		arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
+		out.WriteString(arg4)
+		out.WriteString(", ")
+		arg5 := int64((op >> 5) & 0x7)
+		out.WriteString("#")
+		out.WriteString(strconv.FormatInt(arg5, 10))
+		return out.String()
+	}
 	var out bytes.Buffer
 	opcode := cpu.disasmAddCond("cdp", op)
 	out.WriteString((opcode + "                ")[:10])
 	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
 	out.WriteString(arg0)
 	out.WriteString(", ")
-	arg1 := int64((op >> 21) & 0x7)
+	arg1 := int64((op >> 20) & 0xF)
 	out.WriteString("#")
 	out.WriteString(strconv.FormatInt(arg1, 10))
 	out.WriteString(", ")
@@ -19173,10 +23153,13 @@ func (cpu *Cpu) disasmArmE00(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArmE01(op uint32) {
 	// MCR
+	copnum := (op >> 8) & 0xF
+	if cpu.opCopMissing(copnum) {
+		return
+	}
 	opc := (op >> 21) & 0x7
 	cn := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
-	copnum := (op >> 8) & 0xF
 	cp := (op >> 5) & 0x7
 	cm := (op >> 0) & 0xF
 	cpu.Regs[15] += 4
@@ -19186,6 +23169,30 @@ func (cpu *Cpu) opArmE01(op uint32) {
 }
 
 func (cpu *Cpu) disasmArmE01(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("mcr2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := int64((op >> 21) & 0x7)
+		out.WriteString("#")
+		out.WriteString(strconv.FormatInt(arg1, 10))
+		out.WriteString(", ")
+		arg2 := (op >> 12) & 0xF
+		out.WriteString(RegNames[arg2])
+		out.WriteString(", ")
+		arg3 := "c" + strconv.FormatInt(int64(op>>16)&0xF, 10)
+		out.WriteString(arg3)
+		out.WriteString(", ")
+		arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
+		out.WriteString(arg4)
+		out.WriteString(", ")
+		arg5 := int64((op >> 5) & 0x7)
+		out.WriteString("#")
+		out.WriteString(strconv.FormatInt(arg5, 10))
+		return out.String()
+	}
 	var out bytes.Buffer
 	opcode := cpu.disasmAddCond("mcr", op)
 	out.WriteString((opcode + "                ")[:10])
@@ -19213,10 +23220,13 @@ func (cpu *Cpu) disasmArmE01(op uint32, pc uint32) string {
 
 func (cpu *Cpu) opArmE11(op uint32) {
 	// MRC
+	copnum := (op >> 8) & 0xF
+	if cpu.opCopMissing(copnum) {
+		return
+	}
 	opc := (op >> 21) & 0x7
 	cn := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
-	copnum := (op >> 8) & 0xF
 	cp := (op >> 5) & 0x7
 	cm := (op >> 0) & 0xF
 	res := cpu.opCopRead(copnum, opc, cn, cm, cp)
@@ -19229,6 +23239,30 @@ func (cpu *Cpu) opArmE11(op uint32) {
 }
 
 func (cpu *Cpu) disasmArmE11(op uint32, pc uint32) string {
+	if op>>28 == 0xF {
+		var out bytes.Buffer
+		out.WriteString("mrc2      ")
+		arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
+		out.WriteString(arg0)
+		out.WriteString(", ")
+		arg1 := int64((op >> 21) & 0x7)
+		out.WriteString("#")
+		out.WriteString(strconv.FormatInt(arg1, 10))
+		out.WriteString(", ")
+		arg2 := (op >> 12) & 0xF
+		out.WriteString(RegNames[arg2])
+		out.WriteString(", ")
+		arg3 := "c" + strconv.FormatInt(int64(op>>16)&0xF, 10)
+		out.WriteString(arg3)
+		out.WriteString(", ")
+		arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
+		out.WriteString(arg4)
+		out.WriteString(", ")
+		arg5 := int64((op >> 5) & 0x7)
+		out.WriteString("#")
+		out.WriteString(strconv.FormatInt(arg5, 10))
+		return out.String()
+	}
 	var out bytes.Buffer
 	opcode := cpu.disasmAddCond("mrc", op)
 	out.WriteString((opcode + "                ")[:10])
@@ -19335,7 +23369,7 @@ var opArmTable = [4096]func(*Cpu, uint32){
 	(*Cpu).opArm0F0, (*Cpu).opArm0F9, (*Cpu).opArm0F2, (*Cpu).opArm0DB,
 	(*Cpu).opArm0F4, (*Cpu).opArm0DD, (*Cpu).opArm0F6, (*Cpu).opArm0DF,
 	(*Cpu).opArm100, (*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101,
-	(*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101,
+	(*Cpu).opArm101, (*Cpu).opArm105, (*Cpu).opArm101, (*Cpu).opArm101,
 	(*Cpu).opArm108, (*Cpu).opArm109, (*Cpu).opArm10A, (*Cpu).opArm10B,
 	(*Cpu).opArm10C, (*Cpu).opArm10D, (*Cpu).opArm10E, (*Cpu).opArm10F,
 	(*Cpu).opArm110, (*Cpu).opArm111, (*Cpu).opArm112, (*Cpu).opArm113,
@@ -19343,7 +23377,7 @@ var opArmTable = [4096]func(*Cpu, uint32){
 	(*Cpu).opArm110, (*Cpu).opArm049, (*Cpu).opArm112, (*Cpu).opArm11B,
 	(*Cpu).opArm114, (*Cpu).opArm11D, (*Cpu).opArm116, (*Cpu).opArm11F,
 	(*Cpu).opArm120, (*Cpu).opArm121, (*Cpu).opArm101, (*Cpu).opArm123,
-	(*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101,
+	(*Cpu).opArm101, (*Cpu).opArm125, (*Cpu).opArm101, (*Cpu).opArm127,
 	(*Cpu).opArm128, (*Cpu).opArm049, (*Cpu).opArm12A, (*Cpu).opArm12B,
 	(*Cpu).opArm12C, (*Cpu).opArm12D, (*Cpu).opArm12E, (*Cpu).opArm12F,
 	(*Cpu).opArm130, (*Cpu).opArm131, (*Cpu).opArm132, (*Cpu).opArm133,
@@ -19351,15 +23385,15 @@ var opArmTable = [4096]func(*Cpu, uint32){
 	(*Cpu).opArm130, (*Cpu).opArm049, (*Cpu).opArm132, (*Cpu).opArm13B,
 	(*Cpu).opArm134, (*Cpu).opArm13D, (*Cpu).opArm136, (*Cpu).opArm13F,
 	(*Cpu).opArm140, (*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101,
-	(*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101,
-	(*Cpu).opArm148, (*Cpu).opArm149, (*Cpu).opArm148, (*Cpu).opArm14B,
-	(*Cpu).opArm148, (*Cpu).opArm14D, (*Cpu).opArm148, (*Cpu).opArm14F,
+	(*Cpu).opArm101, (*Cpu).opArm145, (*Cpu).opArm101, (*Cpu).opArm101,
+	(*Cpu).opArm148, (*Cpu).opArm149, (*Cpu).opArm14A, (*Cpu).opArm14B,
+	(*Cpu).opArm14C, (*Cpu).opArm14D, (*Cpu).opArm14E, (*Cpu).opArm14F,
 	(*Cpu).opArm150, (*Cpu).opArm151, (*Cpu).opArm152, (*Cpu).opArm153,
 	(*Cpu).opArm154, (*Cpu).opArm155, (*Cpu).opArm156, (*Cpu).opArm157,
 	(*Cpu).opArm150, (*Cpu).opArm049, (*Cpu).opArm152, (*Cpu).opArm15B,
 	(*Cpu).opArm154, (*Cpu).opArm15D, (*Cpu).opArm156, (*Cpu).opArm15F,
 	(*Cpu).opArm160, (*Cpu).opArm161, (*Cpu).opArm101, (*Cpu).opArm101,
-	(*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101, (*Cpu).opArm101,
+	(*Cpu).opArm101, (*Cpu).opArm165, (*Cpu).opArm101, (*Cpu).opArm101,
 	(*Cpu).opArm168, (*Cpu).opArm049, (*Cpu).opArm16A, (*Cpu).opArm16B,
 	(*Cpu).opArm16C, (*Cpu).opArm16D, (*Cpu).opArm16E, (*Cpu).opArm16F,
 	(*Cpu).opArm170, (*Cpu).opArm171, (*Cpu).opArm172, (*Cpu).opArm173,
@@ -20042,130 +24076,130 @@ var opArmTable = [4096]func(*Cpu, uint32){
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40,
+	(*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40,
+	(*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40,
+	(*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40, (*Cpu).opArmC40,
+	(*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50,
+	(*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50,
+	(*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50,
+	(*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50, (*Cpu).opArmC50,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
 	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
-	(*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00, (*Cpu).opArmC00,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10, (*Cpu).opArmC10,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60, (*Cpu).opArmC60,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
+	(*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70, (*Cpu).opArmC70,
 	(*Cpu).opArmE00, (*Cpu).opArmE01, (*Cpu).opArmE00, (*Cpu).opArmE01,
 	(*Cpu).opArmE00, (*Cpu).opArmE01, (*Cpu).opArmE00, (*Cpu).opArmE01,
 	(*Cpu).opArmE00, (*Cpu).opArmE01, (*Cpu).opArmE00, (*Cpu).opArmE01,
@@ -20361,7 +24395,7 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm0F0, (*Cpu).disasmArm0F9, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0DB,
 	(*Cpu).disasmArm0F0, (*Cpu).disasmArm0DD, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0DF,
 	(*Cpu).disasmArm100, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm049, (*Cpu).disasmArm105, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm108, (*Cpu).disasmArm109, (*Cpu).disasmArm10A, (*Cpu).disasmArm10B,
 	(*Cpu).disasmArm10C, (*Cpu).disasmArm10D, (*Cpu).disasmArm10E, (*Cpu).disasmArm10F,
 	(*Cpu).disasmArm110, (*Cpu).disasmArm110, (*Cpu).disasmArm110, (*Cpu).disasmArm110,
@@ -20369,7 +24403,7 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm110, (*Cpu).disasmArm049, (*Cpu).disasmArm110, (*Cpu).disasmArm11B,
 	(*Cpu).disasmArm110, (*Cpu).disasmArm11D, (*Cpu).disasmArm110, (*Cpu).disasmArm11F,
 	(*Cpu).disasmArm120, (*Cpu).disasmArm121, (*Cpu).disasmArm049, (*Cpu).disasmArm123,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm049, (*Cpu).disasmArm125, (*Cpu).disasmArm049, (*Cpu).disasmArm127,
 	(*Cpu).disasmArm128, (*Cpu).disasmArm049, (*Cpu).disasmArm12A, (*Cpu).disasmArm12B,
 	(*Cpu).disasmArm12C, (*Cpu).disasmArm12D, (*Cpu).disasmArm12E, (*Cpu).disasmArm12F,
 	(*Cpu).disasmArm130, (*Cpu).disasmArm130, (*Cpu).disasmArm130, (*Cpu).disasmArm130,
@@ -20377,15 +24411,15 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm130, (*Cpu).disasmArm049, (*Cpu).disasmArm130, (*Cpu).disasmArm13B,
 	(*Cpu).disasmArm130, (*Cpu).disasmArm13D, (*Cpu).disasmArm130, (*Cpu).disasmArm13F,
 	(*Cpu).disasmArm140, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm149, (*Cpu).disasmArm049, (*Cpu).disasmArm14B,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm14D, (*Cpu).disasmArm049, (*Cpu).disasmArm14F,
+	(*Cpu).disasmArm049, (*Cpu).disasmArm145, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm148, (*Cpu).disasmArm149, (*Cpu).disasmArm14A, (*Cpu).disasmArm14B,
+	(*Cpu).disasmArm14C, (*Cpu).disasmArm14D, (*Cpu).disasmArm14E, (*Cpu).disasmArm14F,
 	(*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150,
 	(*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150,
 	(*Cpu).disasmArm150, (*Cpu).disasmArm049, (*Cpu).disasmArm150, (*Cpu).disasmArm15B,
 	(*Cpu).disasmArm150, (*Cpu).disasmArm15D, (*Cpu).disasmArm150, (*Cpu).disasmArm15F,
 	(*Cpu).disasmArm160, (*Cpu).disasmArm161, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm049, (*Cpu).disasmArm165, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm168, (*Cpu).disasmArm049, (*Cpu).disasmArm16A, (*Cpu).disasmArm16B,
 	(*Cpu).disasmArm16C, (*Cpu).disasmArm16D, (*Cpu).disasmArm16E, (*Cpu).disasmArm16F,
 	(*Cpu).disasmArm170, (*Cpu).disasmArm170, (*Cpu).disasmArm170, (*Cpu).disasmArm170,
@@ -20560,22 +24594,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
 	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
 	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
+	(*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420,
+	(*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420,
+	(*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420,
+	(*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420, (*Cpu).disasmArm420,
+	(*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430,
+	(*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430,
+	(*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430,
+	(*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430, (*Cpu).disasmArm430,
 	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
 	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
 	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
@@ -20584,6 +24610,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
 	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
 	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
+	(*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460,
+	(*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460,
+	(*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460,
+	(*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460, (*Cpu).disasmArm460,
+	(*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470,
+	(*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470,
+	(*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470,
+	(*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470, (*Cpu).disasmArm470,
 	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
 	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
 	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
@@ -20592,22 +24626,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
 	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
 	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
+	(*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0,
+	(*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0,
+	(*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0,
+	(*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0, (*Cpu).disasmArm4A0,
+	(*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0,
+	(*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0,
+	(*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0,
+	(*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0, (*Cpu).disasmArm4B0,
 	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
 	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
 	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
@@ -20616,6 +24642,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
 	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
 	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
+	(*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0,
+	(*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0,
+	(*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0,
+	(*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0, (*Cpu).disasmArm4E0,
+	(*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0,
+	(*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0,
+	(*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0,
+	(*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0, (*Cpu).disasmArm4F0,
 	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
 	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
 	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
@@ -20688,22 +24722,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm620, (*Cpu).disasmArm049, (*Cpu).disasmArm620, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm620, (*Cpu).disasmArm049, (*Cpu).disasmArm620, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm620, (*Cpu).disasmArm049, (*Cpu).disasmArm620, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm620, (*Cpu).disasmArm049, (*Cpu).disasmArm620, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm630, (*Cpu).disasmArm049, (*Cpu).disasmArm630, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm630, (*Cpu).disasmArm049, (*Cpu).disasmArm630, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm630, (*Cpu).disasmArm049, (*Cpu).disasmArm630, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm630, (*Cpu).disasmArm049, (*Cpu).disasmArm630, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
@@ -20712,6 +24738,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm660, (*Cpu).disasmArm049, (*Cpu).disasmArm660, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm660, (*Cpu).disasmArm049, (*Cpu).disasmArm660, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm660, (*Cpu).disasmArm049, (*Cpu).disasmArm660, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm660, (*Cpu).disasmArm049, (*Cpu).disasmArm660, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm670, (*Cpu).disasmArm049, (*Cpu).disasmArm670, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm670, (*Cpu).disasmArm049, (*Cpu).disasmArm670, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm670, (*Cpu).disasmArm049, (*Cpu).disasmArm670, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm670, (*Cpu).disasmArm049, (*Cpu).disasmArm670, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
@@ -20720,22 +24754,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6A0, (*Cpu).disasmArm049, (*Cpu).disasmArm6A0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6A0, (*Cpu).disasmArm049, (*Cpu).disasmArm6A0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6A0, (*Cpu).disasmArm049, (*Cpu).disasmArm6A0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6A0, (*Cpu).disasmArm049, (*Cpu).disasmArm6A0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6B0, (*Cpu).disasmArm049, (*Cpu).disasmArm6B0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6B0, (*Cpu).disasmArm049, (*Cpu).disasmArm6B0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6B0, (*Cpu).disasmArm049, (*Cpu).disasmArm6B0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6B0, (*Cpu).disasmArm049, (*Cpu).disasmArm6B0, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
@@ -20744,6 +24770,14 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6E0, (*Cpu).disasmArm049, (*Cpu).disasmArm6E0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6E0, (*Cpu).disasmArm049, (*Cpu).disasmArm6E0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6E0, (*Cpu).disasmArm049, (*Cpu).disasmArm6E0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6E0, (*Cpu).disasmArm049, (*Cpu).disasmArm6E0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6F0, (*Cpu).disasmArm049, (*Cpu).disasmArm6F0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6F0, (*Cpu).disasmArm049, (*Cpu).disasmArm6F0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6F0, (*Cpu).disasmArm049, (*Cpu).disasmArm6F0, (*Cpu).disasmArm049,
+	(*Cpu).disasmArm6F0, (*Cpu).disasmArm049, (*Cpu).disasmArm6F0, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
 	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
@@ -20824,38 +24858,22 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
 	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
 	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
+	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
+	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
+	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
+	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
+	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
+	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
+	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
+	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
+	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
+	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
+	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
+	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
+	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
+	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
+	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
+	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
 	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
 	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
 	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
@@ -20872,6 +24890,22 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
 	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
 	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
+	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
+	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
+	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
+	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
+	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
+	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
+	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
+	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
+	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
+	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
+	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
+	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
+	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
+	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
+	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
+	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
 	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
 	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
 	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
@@ -20888,38 +24922,22 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
 	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
 	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
+	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
+	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
+	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
+	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
+	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
+	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
+	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
+	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
+	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
+	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
+	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
+	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
+	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
+	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
+	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
+	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
 	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
 	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
 	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
@@ -20936,6 +24954,22 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
 	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
 	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
+	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
+	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
+	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
+	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
+	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
+	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
+	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
+	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
+	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
+	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
+	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
+	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
+	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
+	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
+	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
+	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
 	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
 	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
 	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
@@ -21064,134 +25098,134 @@ var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
 	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
 	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
 	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00, (*Cpu).disasmArmC00,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10, (*Cpu).disasmArmC10,
+	(*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40,
+	(*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40,
+	(*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40,
+	(*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40, (*Cpu).disasmArmC40,
+	(*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50,
+	(*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50,
+	(*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50,
+	(*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50, (*Cpu).disasmArmC50,
+	(*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60,
+	(*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60,
+	(*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60,
+	(*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60, (*Cpu).disasmArmC60,
+	(*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70,
+	(*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70,
+	(*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70,
+	(*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70, (*Cpu).disasmArmC70,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80, (*Cpu).disasmArmC80,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90, (*Cpu).disasmArmC90,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0, (*Cpu).disasmArmCC0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0, (*Cpu).disasmArmCD0,
+	(*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00,
+	(*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00,
+	(*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00,
+	(*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00, (*Cpu).disasmArmD00,
+	(*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10,
+	(*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10,
+	(*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10,
+	(*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10, (*Cpu).disasmArmD10,
+	(*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20,
+	(*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20,
+	(*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20,
+	(*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20, (*Cpu).disasmArmD20,
+	(*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30,
+	(*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30,
+	(*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30,
+	(*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30, (*Cpu).disasmArmD30,
+	(*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40,
+	(*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40,
+	(*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40,
+	(*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40, (*Cpu).disasmArmD40,
+	(*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50,
+	(*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50,
+	(*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50,
+	(*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50, (*Cpu).disasmArmD50,
+	(*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60,
+	(*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60,
+	(*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60,
+	(*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60, (*Cpu).disasmArmD60,
+	(*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70,
+	(*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70,
+	(*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70,
+	(*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70, (*Cpu).disasmArmD70,
+	(*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80,
+	(*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80,
+	(*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80,
+	(*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80, (*Cpu).disasmArmD80,
+	(*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90,
+	(*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90,
+	(*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90,
+	(*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90, (*Cpu).disasmArmD90,
+	(*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0,
+	(*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0,
+	(*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0,
+	(*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0, (*Cpu).disasmArmDA0,
+	(*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0,
+	(*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0,
+	(*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0,
+	(*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0, (*Cpu).disasmArmDB0,
+	(*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0,
+	(*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0,
+	(*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0,
+	(*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0, (*Cpu).disasmArmDC0,
+	(*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0,
+	(*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0,
+	(*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0,
+	(*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0, (*Cpu).disasmArmDD0,
+	(*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0,
+	(*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0,
+	(*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0,
+	(*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0, (*Cpu).disasmArmDE0,
+	(*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0,
+	(*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0,
+	(*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0,
+	(*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0, (*Cpu).disasmArmDF0,
 	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
 	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
 	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
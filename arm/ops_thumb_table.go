@@ -1,4 +1,4 @@
-// Generated on 2017-03-05 01:17:59.811099017 +0100 CET
+// Generated on 2026-10-16 15:12:57.378028531 +0000 UTC m=+0.000663761
 package arm
 
 import "bytes"
@@ -48,7 +48,7 @@ func (cpu *Cpu) opThumb00(op uint16) {
 	offset := (op >> 6) & 0x1F
 	rs := uint32(cpu.Regs[rsx])
 	if offset != 0 {
-		cpu.Cpsr.SetC(rs&(1<<(32-offset)) != 0)
+		cpu.Cpsr.SetCBit(rs >> (32 - offset))
 	}
 	res := rs << offset
 	cpu.Cpsr.SetNZ(res)
@@ -79,7 +79,7 @@ func (cpu *Cpu) opThumb08(op uint16) {
 	if offset == 0 {
 		offset = 32
 	}
-	cpu.Cpsr.SetC(rs&(1<<(offset-1)) != 0)
+	cpu.Cpsr.SetCBit(rs >> (offset - 1))
 	res := rs >> offset
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -109,7 +109,7 @@ func (cpu *Cpu) opThumb10(op uint16) {
 	if offset == 0 {
 		offset = 32
 	}
-	cpu.Cpsr.SetC(rs&(1<<(offset-1)) != 0)
+	cpu.Cpsr.SetCBit(rs >> (offset - 1))
 	res := uint32(int32(rs) >> offset)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -723,7 +723,12 @@ func (cpu *Cpu) opThumb48(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[0] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[0] = val
 	cpu.Clock += 1
 }
 
@@ -745,7 +750,12 @@ func (cpu *Cpu) opThumb49(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[1] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[1] = val
 	cpu.Clock += 1
 }
 
@@ -753,7 +763,12 @@ func (cpu *Cpu) opThumb4A(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[2] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[2] = val
 	cpu.Clock += 1
 }
 
@@ -761,7 +776,12 @@ func (cpu *Cpu) opThumb4B(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[3] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[3] = val
 	cpu.Clock += 1
 }
 
@@ -769,7 +789,12 @@ func (cpu *Cpu) opThumb4C(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[4] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[4] = val
 	cpu.Clock += 1
 }
 
@@ -777,7 +802,12 @@ func (cpu *Cpu) opThumb4D(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[5] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[5] = val
 	cpu.Clock += 1
 }
 
@@ -785,7 +815,12 @@ func (cpu *Cpu) opThumb4E(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[6] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[6] = val
 	cpu.Clock += 1
 }
 
@@ -793,7 +828,12 @@ func (cpu *Cpu) opThumb4F(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
 	pc += uint32((op & 0xFF) * 4)
-	cpu.Regs[7] = reg(cpu.Read32(pc))
+	val := reg(cpu.Read32(pc))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[7] = val
 	cpu.Clock += 1
 }
 
@@ -881,7 +921,12 @@ func (cpu *Cpu) opThumb56(op uint16) {
 	rbx := (op >> 3) & 7
 	rdx := op & 7
 	addr := uint32(cpu.Regs[rbx] + cpu.Regs[rox])
-	cpu.Regs[rdx] = reg(int8(cpu.Read8(addr)))
+	val := reg(int8(cpu.Read8(addr)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -907,7 +952,12 @@ func (cpu *Cpu) opThumb58(op uint16) {
 	rbx := (op >> 3) & 7
 	rdx := op & 7
 	addr := uint32(cpu.Regs[rbx] + cpu.Regs[rox])
-	cpu.Regs[rdx] = reg(cpu.Read32(addr))
+	val := reg(cpu.Read32(addr))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -933,7 +983,12 @@ func (cpu *Cpu) opThumb5A(op uint16) {
 	rbx := (op >> 3) & 7
 	rdx := op & 7
 	addr := uint32(cpu.Regs[rbx] + cpu.Regs[rox])
-	cpu.Regs[rdx] = reg(cpu.Read16(addr))
+	val := reg(cpu.Read16(addr))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -959,7 +1014,12 @@ func (cpu *Cpu) opThumb5C(op uint16) {
 	rbx := (op >> 3) & 7
 	rdx := op & 7
 	addr := uint32(cpu.Regs[rbx] + cpu.Regs[rox])
-	cpu.Regs[rdx] = reg(cpu.Read8(addr))
+	val := reg(cpu.Read8(addr))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -985,7 +1045,12 @@ func (cpu *Cpu) opThumb5E(op uint16) {
 	rbx := (op >> 3) & 7
 	rdx := op & 7
 	addr := uint32(cpu.Regs[rbx] + cpu.Regs[rox])
-	cpu.Regs[rdx] = reg(int16(cpu.Read16(addr)))
+	val := reg(int16(cpu.Read16(addr)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -1033,8 +1098,13 @@ func (cpu *Cpu) disasmThumb60(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1047,7 +1117,12 @@ func (cpu *Cpu) opThumb68(op uint16) {
 	rdx := op & 0x7
 	rb := uint32(cpu.Regs[rbx])
 	offset *= 4
-	cpu.Regs[rdx] = reg(cpu.Read32(rb + offset))
+	val := reg(cpu.Read32(rb + offset))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -1067,8 +1142,13 @@ func (cpu *Cpu) disasmThumb68(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1101,8 +1181,13 @@ func (cpu *Cpu) disasmThumb70(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1114,7 +1199,12 @@ func (cpu *Cpu) opThumb78(op uint16) {
 	rbx := (op >> 3) & 0x7
 	rdx := op & 0x7
 	rb := uint32(cpu.Regs[rbx])
-	cpu.Regs[rdx] = reg(cpu.Read8(rb + offset))
+	val := reg(cpu.Read8(rb + offset))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -1134,8 +1224,13 @@ func (cpu *Cpu) disasmThumb78(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1169,8 +1264,13 @@ func (cpu *Cpu) disasmThumb80(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1183,7 +1283,12 @@ func (cpu *Cpu) opThumb88(op uint16) {
 	rdx := op & 0x7
 	rb := uint32(cpu.Regs[rbx])
 	offset *= 2
-	cpu.Regs[rdx] = reg(cpu.Read16(rb + offset))
+	val := reg(cpu.Read16(rb + offset))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[rdx] = val
 	cpu.Clock += 1
 }
 
@@ -1203,8 +1308,13 @@ func (cpu *Cpu) disasmThumb88(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1234,8 +1344,13 @@ func (cpu *Cpu) disasmThumb90(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1301,7 +1416,12 @@ func (cpu *Cpu) opThumb98(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[0] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[0] = val
 	cpu.Clock += 1
 }
 
@@ -1321,8 +1441,13 @@ func (cpu *Cpu) disasmThumb98(op uint16, pc uint32) string {
 	} else {
 		out.WriteString("[")
 		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		if int64(arg1b) < 0 {
+			out.WriteString(", #-0x")
+			out.WriteString(strconv.FormatInt(-int64(arg1b), 16))
+		} else {
+			out.WriteString(", #0x")
+			out.WriteString(strconv.FormatInt(int64(arg1b), 16))
+		}
 		out.WriteString("]")
 	}
 	return out.String()
@@ -1332,7 +1457,12 @@ func (cpu *Cpu) opThumb99(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[1] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[1] = val
 	cpu.Clock += 1
 }
 
@@ -1340,7 +1470,12 @@ func (cpu *Cpu) opThumb9A(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[2] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[2] = val
 	cpu.Clock += 1
 }
 
@@ -1348,7 +1483,12 @@ func (cpu *Cpu) opThumb9B(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[3] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[3] = val
 	cpu.Clock += 1
 }
 
@@ -1356,7 +1496,12 @@ func (cpu *Cpu) opThumb9C(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[4] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[4] = val
 	cpu.Clock += 1
 }
 
@@ -1364,7 +1509,12 @@ func (cpu *Cpu) opThumb9D(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[5] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[5] = val
 	cpu.Clock += 1
 }
 
@@ -1372,7 +1522,12 @@ func (cpu *Cpu) opThumb9E(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[6] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[6] = val
 	cpu.Clock += 1
 }
 
@@ -1380,7 +1535,12 @@ func (cpu *Cpu) opThumb9F(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
 	sp := uint32(cpu.Regs[13])
-	cpu.Regs[7] = reg(cpu.Read32(sp + uint32(offset)))
+	val := reg(cpu.Read32(sp + uint32(offset)))
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[7] = val
 	cpu.Clock += 1
 }
 
@@ -1557,43 +1717,47 @@ func (cpu *Cpu) opThumbB4(op uint16) {
 	count := popcount16(op & 0x1FF)
 	sp := uint32(cpu.Regs[13])
 	sp -= uint32(count * 4)
-	cpu.Regs[13] = reg(sp)
-	if (op>>0)&1 != 0 {
+	if op&(1<<0) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[0]))
 		sp += 4
 	}
-	if (op>>1)&1 != 0 {
+	if op&(1<<1) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[1]))
 		sp += 4
 	}
-	if (op>>2)&1 != 0 {
+	if op&(1<<2) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[2]))
 		sp += 4
 	}
-	if (op>>3)&1 != 0 {
+	if op&(1<<3) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[3]))
 		sp += 4
 	}
-	if (op>>4)&1 != 0 {
+	if op&(1<<4) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[4]))
 		sp += 4
 	}
-	if (op>>5)&1 != 0 {
+	if op&(1<<5) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[5]))
 		sp += 4
 	}
-	if (op>>6)&1 != 0 {
+	if op&(1<<6) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[6]))
 		sp += 4
 	}
-	if (op>>7)&1 != 0 {
+	if op&(1<<7) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[7]))
 		sp += 4
 	}
-	if (op>>8)&1 != 0 {
+	if op&(1<<8) != 0 {
 		cpu.Write32(sp, uint32(cpu.Regs[14]))
 		sp += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
+	cpu.Regs[13] -= reg(count * 4)
 	cpu.Clock += 1
 }
 
@@ -1640,45 +1804,53 @@ func (cpu *Cpu) disasmThumbB5(op uint16, pc uint32) string {
 func (cpu *Cpu) opThumbBC(op uint16) {
 	// pop
 	sp := uint32(cpu.Regs[13])
-	if (op>>0)&1 != 0 {
+	if op&(1<<0) != 0 {
 		cpu.Regs[0] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>1)&1 != 0 {
+	if op&(1<<1) != 0 {
 		cpu.Regs[1] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>2)&1 != 0 {
+	if op&(1<<2) != 0 {
 		cpu.Regs[2] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>3)&1 != 0 {
+	if op&(1<<3) != 0 {
 		cpu.Regs[3] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>4)&1 != 0 {
+	if op&(1<<4) != 0 {
 		cpu.Regs[4] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>5)&1 != 0 {
+	if op&(1<<5) != 0 {
 		cpu.Regs[5] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>6)&1 != 0 {
+	if op&(1<<6) != 0 {
 		cpu.Regs[6] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>7)&1 != 0 {
+	if op&(1<<7) != 0 {
 		cpu.Regs[7] = reg(cpu.Read32(sp))
 		sp += 4
 	}
-	if (op>>8)&1 != 0 {
+	if op&(1<<8) != 0 {
 		switch cpu.arch {
 		case ARMv4:
 			pc := reg(cpu.Read32(sp) &^ 1)
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
 			cpu.branch(pc, BranchReturn)
 		case ARMv5:
 			pc := reg(cpu.Read32(sp))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
 			if pc&1 == 0 {
 				cpu.Cpsr.SetT(false)
 				pc = pc &^ 3
@@ -1691,6 +1863,10 @@ func (cpu *Cpu) opThumbBC(op uint16) {
 		}
 		sp += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	cpu.Regs[13] = reg(sp)
 	cpu.Clock += 1
 }
@@ -1735,29 +1911,48 @@ func (cpu *Cpu) disasmThumbBD(op uint16, pc uint32) string {
 	return out.String()
 }
 
-func (cpu *Cpu) opThumbC0(op uint16) {
-	// stm
-	if op&(1<<0) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
+func (cpu *Cpu) opThumbBE(op uint16) {
+	// bkpt
+	if cpu.arch < ARMv5 {
+		cpu.Exception(ExceptionUndefined)
 		return
 	}
+	cpu.bkpt()
+}
+
+func (cpu *Cpu) disasmThumbBE(op uint16, pc uint32) string {
+	var out bytes.Buffer
+	out.WriteString("bkpt      ")
+	arg0 := int64(op & 0xFF)
+	out.WriteString("#0x")
+	out.WriteString(strconv.FormatInt(arg0, 16))
+	return out.String()
+}
+
+func (cpu *Cpu) opThumbC0(op uint16) {
+	// stm
 	ptr := uint32(cpu.Regs[0])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[0] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[0] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[0] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
 	if op&(1<<0) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[0]))
+		val := uint32(cpu.Regs[0])
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<1) != 0 {
@@ -1788,6 +1983,10 @@ func (cpu *Cpu) opThumbC0(op uint16) {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[0] = reg(ptr)
 	}
@@ -1823,31 +2022,36 @@ func (cpu *Cpu) disasmThumbC0(op uint16, pc uint32) string {
 
 func (cpu *Cpu) opThumbC1(op uint16) {
 	// stm
-	if op&(1<<1) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[1])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[1] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[1] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[1] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
 		ptr += 4
 	}
 	if op&(1<<1) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[1]))
+		val := uint32(cpu.Regs[1])
+		if cpu.arch < ARMv5 && op&0x1 != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<2) != 0 {
@@ -1874,6 +2078,10 @@ func (cpu *Cpu) opThumbC1(op uint16) {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[1] = reg(ptr)
 	}
@@ -1882,24 +2090,25 @@ func (cpu *Cpu) opThumbC1(op uint16) {
 
 func (cpu *Cpu) opThumbC2(op uint16) {
 	// stm
-	if op&(1<<2) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[2])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[2] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[2] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[2] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
@@ -1910,7 +2119,11 @@ func (cpu *Cpu) opThumbC2(op uint16) {
 		ptr += 4
 	}
 	if op&(1<<2) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[2]))
+		val := uint32(cpu.Regs[2])
+		if cpu.arch < ARMv5 && op&0x3 != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<3) != 0 {
@@ -1933,6 +2146,10 @@ func (cpu *Cpu) opThumbC2(op uint16) {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[2] = reg(ptr)
 	}
@@ -1941,24 +2158,25 @@ func (cpu *Cpu) opThumbC2(op uint16) {
 
 func (cpu *Cpu) opThumbC3(op uint16) {
 	// stm
-	if op&(1<<3) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[3])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[3] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[3] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[3] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
@@ -1973,7 +2191,11 @@ func (cpu *Cpu) opThumbC3(op uint16) {
 		ptr += 4
 	}
 	if op&(1<<3) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[3]))
+		val := uint32(cpu.Regs[3])
+		if cpu.arch < ARMv5 && op&0x7 != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<4) != 0 {
@@ -1992,6 +2214,10 @@ func (cpu *Cpu) opThumbC3(op uint16) {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[3] = reg(ptr)
 	}
@@ -2000,24 +2226,25 @@ func (cpu *Cpu) opThumbC3(op uint16) {
 
 func (cpu *Cpu) opThumbC4(op uint16) {
 	// stm
-	if op&(1<<4) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[4])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[4] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[4] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[4] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
@@ -2036,7 +2263,11 @@ func (cpu *Cpu) opThumbC4(op uint16) {
 		ptr += 4
 	}
 	if op&(1<<4) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[4]))
+		val := uint32(cpu.Regs[4])
+		if cpu.arch < ARMv5 && op&0xf != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<5) != 0 {
@@ -2051,6 +2282,10 @@ func (cpu *Cpu) opThumbC4(op uint16) {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[4] = reg(ptr)
 	}
@@ -2059,24 +2294,25 @@ func (cpu *Cpu) opThumbC4(op uint16) {
 
 func (cpu *Cpu) opThumbC5(op uint16) {
 	// stm
-	if op&(1<<5) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[5])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[5] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[5] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[5] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
@@ -2099,7 +2335,11 @@ func (cpu *Cpu) opThumbC5(op uint16) {
 		ptr += 4
 	}
 	if op&(1<<5) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[5]))
+		val := uint32(cpu.Regs[5])
+		if cpu.arch < ARMv5 && op&0x1f != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<6) != 0 {
@@ -2110,6 +2350,10 @@ func (cpu *Cpu) opThumbC5(op uint16) {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[5] = reg(ptr)
 	}
@@ -2118,24 +2362,25 @@ func (cpu *Cpu) opThumbC5(op uint16) {
 
 func (cpu *Cpu) opThumbC6(op uint16) {
 	// stm
-	if op&(1<<6) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[6])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[6] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[6] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[6] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
@@ -2162,13 +2407,21 @@ func (cpu *Cpu) opThumbC6(op uint16) {
 		ptr += 4
 	}
 	if op&(1<<6) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[6]))
+		val := uint32(cpu.Regs[6])
+		if cpu.arch < ARMv5 && op&0x3f != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
 	if op&(1<<7) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[7]))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[6] = reg(ptr)
 	}
@@ -2177,24 +2430,25 @@ func (cpu *Cpu) opThumbC6(op uint16) {
 
 func (cpu *Cpu) opThumbC7(op uint16) {
 	// stm
-	if op&(1<<7) != 0 {
-		cpu.InvalidOpThumb(op, "unimplemented: base reg in register list in STM")
-		return
-	}
 	ptr := uint32(cpu.Regs[7])
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Write32(ptr, uint32(cpu.Regs[15]))
-			ptr += 0x40
+			cpu.Write32(ptr, uint32(cpu.Regs[15]+2))
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[7] = reg(ptr + 0x40)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[7] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[7] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
+	wbptr := ptr + uint32(4*popcount16(op&0xFF))
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Write32(ptr, uint32(cpu.Regs[0]))
@@ -2225,9 +2479,17 @@ func (cpu *Cpu) opThumbC7(op uint16) {
 		ptr += 4
 	}
 	if op&(1<<7) != 0 {
-		cpu.Write32(ptr, uint32(cpu.Regs[7]))
+		val := uint32(cpu.Regs[7])
+		if cpu.arch < ARMv5 && op&0x7f != 0 {
+			val = wbptr
+		}
+		cpu.Write32(ptr, val)
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[7] = reg(ptr)
 	}
@@ -2237,23 +2499,29 @@ func (cpu *Cpu) opThumbC7(op uint16) {
 func (cpu *Cpu) opThumbC8(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[0])
+	base := cpu.Regs[0]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[0] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[0] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[0] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
 	if op&(1<<0) != 0 {
 		cpu.Regs[0] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x1 || op&0xfe != 0)
 		ptr += 4
 	}
 	if op&(1<<1) != 0 {
@@ -2284,6 +2552,11 @@ func (cpu *Cpu) opThumbC8(op uint16) {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[0] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[0] = reg(ptr)
 	}
@@ -2320,17 +2593,23 @@ func (cpu *Cpu) disasmThumbC8(op uint16, pc uint32) string {
 func (cpu *Cpu) opThumbC9(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[1])
+	base := cpu.Regs[1]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[1] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[1] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[1] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2340,7 +2619,7 @@ func (cpu *Cpu) opThumbC9(op uint16) {
 	}
 	if op&(1<<1) != 0 {
 		cpu.Regs[1] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x2 || op&0xfc != 0)
 		ptr += 4
 	}
 	if op&(1<<2) != 0 {
@@ -2367,6 +2646,11 @@ func (cpu *Cpu) opThumbC9(op uint16) {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[1] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[1] = reg(ptr)
 	}
@@ -2376,17 +2660,23 @@ func (cpu *Cpu) opThumbC9(op uint16) {
 func (cpu *Cpu) opThumbCA(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[2])
+	base := cpu.Regs[2]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[2] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[2] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[2] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2400,7 +2690,7 @@ func (cpu *Cpu) opThumbCA(op uint16) {
 	}
 	if op&(1<<2) != 0 {
 		cpu.Regs[2] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x4 || op&0xf8 != 0)
 		ptr += 4
 	}
 	if op&(1<<3) != 0 {
@@ -2423,6 +2713,11 @@ func (cpu *Cpu) opThumbCA(op uint16) {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[2] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[2] = reg(ptr)
 	}
@@ -2432,17 +2727,23 @@ func (cpu *Cpu) opThumbCA(op uint16) {
 func (cpu *Cpu) opThumbCB(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[3])
+	base := cpu.Regs[3]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[3] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[3] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[3] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2460,7 +2761,7 @@ func (cpu *Cpu) opThumbCB(op uint16) {
 	}
 	if op&(1<<3) != 0 {
 		cpu.Regs[3] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x8 || op&0xf0 != 0)
 		ptr += 4
 	}
 	if op&(1<<4) != 0 {
@@ -2479,6 +2780,11 @@ func (cpu *Cpu) opThumbCB(op uint16) {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[3] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[3] = reg(ptr)
 	}
@@ -2488,17 +2794,23 @@ func (cpu *Cpu) opThumbCB(op uint16) {
 func (cpu *Cpu) opThumbCC(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[4])
+	base := cpu.Regs[4]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[4] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[4] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[4] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2520,7 +2832,7 @@ func (cpu *Cpu) opThumbCC(op uint16) {
 	}
 	if op&(1<<4) != 0 {
 		cpu.Regs[4] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x10 || op&0xe0 != 0)
 		ptr += 4
 	}
 	if op&(1<<5) != 0 {
@@ -2535,6 +2847,11 @@ func (cpu *Cpu) opThumbCC(op uint16) {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[4] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[4] = reg(ptr)
 	}
@@ -2544,17 +2861,23 @@ func (cpu *Cpu) opThumbCC(op uint16) {
 func (cpu *Cpu) opThumbCD(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[5])
+	base := cpu.Regs[5]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[5] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[5] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[5] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2580,7 +2903,7 @@ func (cpu *Cpu) opThumbCD(op uint16) {
 	}
 	if op&(1<<5) != 0 {
 		cpu.Regs[5] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x20 || op&0xc0 != 0)
 		ptr += 4
 	}
 	if op&(1<<6) != 0 {
@@ -2591,6 +2914,11 @@ func (cpu *Cpu) opThumbCD(op uint16) {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[5] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[5] = reg(ptr)
 	}
@@ -2600,17 +2928,23 @@ func (cpu *Cpu) opThumbCD(op uint16) {
 func (cpu *Cpu) opThumbCE(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[6])
+	base := cpu.Regs[6]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[6] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[6] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[6] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2640,13 +2974,18 @@ func (cpu *Cpu) opThumbCE(op uint16) {
 	}
 	if op&(1<<6) != 0 {
 		cpu.Regs[6] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x40 || op&0x80 != 0)
 		ptr += 4
 	}
 	if op&(1<<7) != 0 {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[6] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[6] = reg(ptr)
 	}
@@ -2656,17 +2995,23 @@ func (cpu *Cpu) opThumbCE(op uint16) {
 func (cpu *Cpu) opThumbCF(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[7])
+	base := cpu.Regs[7]
 	if op&0xFF == 0 {
 		switch cpu.arch {
 		case ARMv4:
-			cpu.Regs[15] = reg(cpu.Read32(ptr))
-			ptr += 0x40
+			pc := reg(cpu.Read32(ptr)) &^ 1
+			if cpu.abort {
+				cpu.Clock += 1
+				return
+			}
+			cpu.Regs[7] = reg(ptr + 0x40)
+			cpu.branch(pc, BranchJump)
 		case ARMv5:
-			ptr += 0x40
+			cpu.Regs[7] = reg(ptr + 0x40)
 		default:
 			panic("unimplemented arch-dependent behavior")
 		}
-		cpu.Regs[7] = reg(ptr)
+		cpu.Clock += 1
 		return
 	}
 	wb := true
@@ -2700,9 +3045,14 @@ func (cpu *Cpu) opThumbCF(op uint16) {
 	}
 	if op&(1<<7) != 0 {
 		cpu.Regs[7] = reg(cpu.Read32(ptr))
-		wb = false
+		wb = cpu.arch >= ARMv5 && (op&0xFF == 0x80 || op&0x0 != 0)
 		ptr += 4
 	}
+	if cpu.abort {
+		cpu.Regs[7] = base
+		cpu.Clock += 1
+		return
+	}
 	if wb {
 		cpu.Regs[7] = reg(ptr)
 	}
@@ -3093,7 +3443,7 @@ func (cpu *Cpu) opThumbAlu02(op uint16) {
 	rd := uint32(cpu.Regs[rdx])
 	shift := (rs & 0xFF)
 	if shift != 0 {
-		cpu.Cpsr.SetC((rd<<(shift-1))&0x80000000 != 0)
+		cpu.Cpsr.SetCBit((rd << (shift - 1)) >> 31)
 	}
 	res := rd << shift
 	cpu.Cpsr.SetNZ(res)
@@ -3119,7 +3469,7 @@ func (cpu *Cpu) opThumbAlu03(op uint16) {
 	rd := uint32(cpu.Regs[rdx])
 	shift := (rs & 0xFF)
 	if shift != 0 {
-		cpu.Cpsr.SetC((rd>>(shift-1))&1 != 0)
+		cpu.Cpsr.SetCBit(rd >> (shift - 1))
 	}
 	res := rd >> shift
 	cpu.Cpsr.SetNZ(res)
@@ -3145,7 +3495,7 @@ func (cpu *Cpu) opThumbAlu04(op uint16) {
 	rd := uint32(cpu.Regs[rdx])
 	shift := (rs & 0xFF)
 	if shift != 0 {
-		cpu.Cpsr.SetC((int32(rd)>>(shift-1))&1 != 0)
+		cpu.Cpsr.SetCBit(uint32(int32(rd) >> (shift - 1)))
 	}
 	res := uint32(int32(rd) >> shift)
 	cpu.Cpsr.SetNZ(res)
@@ -3172,11 +3522,7 @@ func (cpu *Cpu) opThumbAlu05(op uint16) {
 	cf := cpu.Cpsr.CB()
 	res := rd + rs
 	res += cf
-	if cf == 0 {
-		cpu.Cpsr.SetC(res < rd)
-	} else {
-		cpu.Cpsr.SetC(res <= rd)
-	}
+	cpu.Cpsr.SetCAdd(rd, rs, cf)
 	cpu.Cpsr.SetVAdd(rd, rs, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3202,11 +3548,7 @@ func (cpu *Cpu) opThumbAlu06(op uint16) {
 	cf := cpu.Cpsr.CB()
 	res := rd - rs
 	res += cf - 1
-	if cf == 0 {
-		cpu.Cpsr.SetC(rd > rs)
-	} else {
-		cpu.Cpsr.SetC(rd >= rs)
-	}
+	cpu.Cpsr.SetCSub(rd, rs, cf)
 	cpu.Cpsr.SetVSub(rd, rs, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3231,7 +3573,7 @@ func (cpu *Cpu) opThumbAlu07(op uint16) {
 	rd := uint32(cpu.Regs[rdx])
 	rot := (rs & 0xFF)
 	if rot != 0 {
-		cpu.Cpsr.SetC((rd<<(rot-1))&0x80000000 != 0)
+		cpu.Cpsr.SetCBit(rd >> ((rot - 1) & 31))
 	}
 	rot = (rs & 0x1F)
 	res := (rd >> rot) | (rd << (32 - rot))
@@ -3277,7 +3619,7 @@ func (cpu *Cpu) opThumbAlu09(op uint16) {
 	rs := uint32(cpu.Regs[rsx])
 	rdx := op & 0x7
 	res := 0 - rs
-	cpu.Cpsr.SetC(false)
+	cpu.Cpsr.SetC(rs == 0)
 	cpu.Cpsr.SetVSub(0, rs, res)
 	cpu.Cpsr.SetNZ(res)
 	cpu.Regs[rdx] = reg(res)
@@ -3478,7 +3820,7 @@ var opThumbTable = [256]func(*Cpu, uint16){
 	(*Cpu).opThumbB0, (*Cpu).opThumbB1, (*Cpu).opThumbB1, (*Cpu).opThumbB1,
 	(*Cpu).opThumbB4, (*Cpu).opThumbB4, (*Cpu).opThumbB1, (*Cpu).opThumbB1,
 	(*Cpu).opThumbB1, (*Cpu).opThumbB1, (*Cpu).opThumbB1, (*Cpu).opThumbB1,
-	(*Cpu).opThumbBC, (*Cpu).opThumbBC, (*Cpu).opThumbB1, (*Cpu).opThumbB1,
+	(*Cpu).opThumbBC, (*Cpu).opThumbBC, (*Cpu).opThumbBE, (*Cpu).opThumbB1,
 	(*Cpu).opThumbC0, (*Cpu).opThumbC1, (*Cpu).opThumbC2, (*Cpu).opThumbC3,
 	(*Cpu).opThumbC4, (*Cpu).opThumbC5, (*Cpu).opThumbC6, (*Cpu).opThumbC7,
 	(*Cpu).opThumbC8, (*Cpu).opThumbC9, (*Cpu).opThumbCA, (*Cpu).opThumbCB,
@@ -3544,7 +3886,7 @@ var disasmThumbTable = [256]func(*Cpu, uint16, uint32) string{
 	(*Cpu).disasmThumbB0, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
 	(*Cpu).disasmThumbB4, (*Cpu).disasmThumbB5, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
 	(*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
-	(*Cpu).disasmThumbBC, (*Cpu).disasmThumbBD, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
+	(*Cpu).disasmThumbBC, (*Cpu).disasmThumbBD, (*Cpu).disasmThumbBE, (*Cpu).disasmThumbB1,
 	(*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0,
 	(*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0,
 	(*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8,
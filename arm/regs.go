@@ -69,6 +69,26 @@ func (r *regCpsr) SetC(val bool) {
 	r.r.BitChange(29, val)
 }
 
+// SetCBit sets the carry flag to bit 0 of val. On the hot paths (eg: the
+// carry-out of the shifter) the carry is already available as a bit, so
+// this avoids converting it to a bool and back.
+func (r *regCpsr) SetCBit(val uint32) {
+	r.r = r.r&^(1<<29) | reg(val&1)<<29
+}
+
+// SetCAdd sets the carry flag for the addition a+b+cf (with cf being 0 or
+// 1, as in ADC), computing it in 64-bit instead of comparing the result
+// with the operands.
+func (r *regCpsr) SetCAdd(a, b, cf uint32) {
+	r.SetCBit(uint32((uint64(a) + uint64(b) + uint64(cf)) >> 32))
+}
+
+// SetCSub sets the carry flag for the subtraction a-b-(1-cf) (as in SBC):
+// the carry is set if there is no borrow.
+func (r *regCpsr) SetCSub(a, b, cf uint32) {
+	r.SetCBit(^uint32((uint64(a) - uint64(b) - uint64(1-cf)) >> 32))
+}
+
 func (r *regCpsr) SetVAdd(s1, s2, res uint32) {
 	v := ^(s1 ^ s2) & (s1 ^ res) & 0x80000000
 	r.r &^= 0x10000000
@@ -81,6 +101,10 @@ func (r *regCpsr) SetVSub(s1, s2, res uint32) {
 	r.r |= reg(v >> 3)
 }
 
+func (r *regCpsr) SetQ(val bool) {
+	r.r.BitChange(27, val)
+}
+
 func (r *regCpsr) SetI(val bool) {
 	r.r.BitChange(7, val)
 }
@@ -0,0 +1,82 @@
+package arm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCarryHelpers(t *testing.T) {
+	vals := []uint32{0, 1, 2, 0x7FFFFFFF, 0x80000000, 0x80000001, 0xFFFFFFFE, 0xFFFFFFFF}
+	for i := 0; i < 64; i++ {
+		vals = append(vals, rand.Uint32())
+	}
+
+	var r regCpsr
+	for _, a := range vals {
+		for _, b := range vals {
+			for cf := uint32(0); cf < 2; cf++ {
+				// Reference: 64-bit result of the operation
+				add := uint64(a) + uint64(b) + uint64(cf)
+				r.SetCAdd(a, b, cf)
+				if r.C() != (add>>32 != 0) {
+					t.Errorf("adc %08x+%08x+%d: carry=%v", a, b, cf, r.C())
+				}
+
+				sub := int64(a) - int64(b) - int64(1-cf)
+				r.SetCSub(a, b, cf)
+				if r.C() != (sub >= 0) {
+					t.Errorf("sbc %08x-%08x-%d: carry=%v", a, b, 1-cf, r.C())
+				}
+			}
+		}
+
+		r.r = 0xFFFFFFFF
+		r.SetCBit(a)
+		if r.C() != (a&1 != 0) || r.r|(1<<29) != 0xFFFFFFFF {
+			t.Errorf("setcbit %08x: cpsr=%08x", a, uint32(r.r))
+		}
+	}
+}
+
+// Operands for the benchmarks, so that the compiler can't precompute flags
+var benchOps = func() []uint32 {
+	ops := make([]uint32, 1024)
+	for i := range ops {
+		ops[i] = rand.Uint32()
+	}
+	return ops
+}()
+
+func BenchmarkSetCBool(b *testing.B) {
+	var r regCpsr
+	for i := 0; i < b.N; i++ {
+		r.SetC(benchOps[i&1023]>>31 != 0)
+	}
+}
+
+func BenchmarkSetCBit(b *testing.B) {
+	var r regCpsr
+	for i := 0; i < b.N; i++ {
+		r.SetCBit(benchOps[i&1023] >> 31)
+	}
+}
+
+func BenchmarkSetCAdcBranch(b *testing.B) {
+	var r regCpsr
+	for i := 0; i < b.N; i++ {
+		rn, op2, cf := benchOps[i&1023], benchOps[(i+1)&1023], uint32(i&1)
+		res := rn + op2 + cf
+		if cf == 0 {
+			r.SetC(rn > res)
+		} else {
+			r.SetC(rn >= res)
+		}
+	}
+}
+
+func BenchmarkSetCAdd(b *testing.B) {
+	var r regCpsr
+	for i := 0; i < b.N; i++ {
+		r.SetCAdd(benchOps[i&1023], benchOps[(i+1)&1023], uint32(i&1))
+	}
+}
@@ -0,0 +1,148 @@
+package arm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// Flat memory bus, starting at address 0
+type testBus struct {
+	mem []byte
+}
+
+func newTestBus(code []uint32, thumb []uint16, thumbAddr uint32) *testBus {
+	bus := &testBus{mem: make([]byte, 0x2000)}
+	for i, op := range code {
+		binary.LittleEndian.PutUint32(bus.mem[i*4:], op)
+	}
+	for i, op := range thumb {
+		binary.LittleEndian.PutUint16(bus.mem[thumbAddr+uint32(i*2):], op)
+	}
+	return bus
+}
+
+func (b *testBus) WaitStates() int { return 0 }
+func (b *testBus) Read32(addr uint32) uint32 {
+	return binary.LittleEndian.Uint32(b.mem[addr&uint32(len(b.mem)-1):])
+}
+func (b *testBus) Write32(addr uint32, val uint32) {
+	binary.LittleEndian.PutUint32(b.mem[addr&uint32(len(b.mem)-1):], val)
+}
+func (b *testBus) Read16(addr uint32) uint16 {
+	return binary.LittleEndian.Uint16(b.mem[addr&uint32(len(b.mem)-1):])
+}
+func (b *testBus) Write16(addr uint32, val uint16) {
+	binary.LittleEndian.PutUint16(b.mem[addr&uint32(len(b.mem)-1):], val)
+}
+func (b *testBus) Read8(addr uint32) uint8       { return b.mem[addr&uint32(len(b.mem)-1)] }
+func (b *testBus) Write8(addr uint32, val uint8) { b.mem[addr&uint32(len(b.mem)-1)] = val }
+func (b *testBus) FetchPointer(addr uint32) []uint8 {
+	return b.mem[addr&uint32(len(b.mem)-1):]
+}
+
+var testRunArm = []uint32{
+	// Loop with conditional opcodes and stores
+	0xE3A00000, // 00: mov   r0, #0
+	0xE3A01064, // 04: mov   r1, #100
+	0xE0800001, // 08: add   r0, r0, r1
+	0xE3110001, // 0C: tst   r1, #1
+	0x12822003, // 10: addne r2, r2, #3
+	0xE4830004, // 14: str   r0, [r3], #4
+	0xE2511001, // 18: subs  r1, r1, #1
+	0x1AFFFFF9, // 1C: bne   08
+
+	// Self-modifying code: the opcode at 0x38 is executed, then replaced by
+	// an opcode of a different class
+	0xE3A06002, // 20: mov   r6, #2
+	0xE3560001, // 24: cmp   r6, #1
+	0x059F401C, // 28: ldreq r4, [pc, #0x1C] (=0x4C)
+	0x058F4004, // 2C: streq r4, [pc, #4] (=0x38)
+	0xE2566001, // 30: subs  r6, r6, #1
+	0xE1A00000, // 34: nop
+	0xE2855001, // 38: add   r5, r5, #1
+	0x1AFFFFF8, // 3C: bne   24
+
+	// Switch to thumb
+	0xE3A07C01, // 40: mov   r7, #0x100
+	0xE2877001, // 44: add   r7, r7, #1
+	0xE12FFF17, // 48: bx    r7
+	0xE22550FF, // 4C: eor   r5, r5, #0xFF
+}
+
+var testRunThumb = []uint16{
+	0x2005, // 100: mov r0, #5
+	0x3801, // 102: sub r0, #1
+	0xD1FD, // 104: bne 102
+	0xE7FE, // 106: b   106
+}
+
+func runTestProgram(d Dispatch, cycles int64) (*Cpu, *testBus) {
+	bus := newTestBus(testRunArm, testRunThumb, 0x100)
+	cpu := NewCpu(ARMv4, bus)
+	cpu.SetDispatch(d)
+	cpu.SetPC(0)
+	cpu.Regs[3] = 0x1000
+	cpu.Run(cycles)
+	return cpu, bus
+}
+
+func TestRunProgram(t *testing.T) {
+	cpu, bus := runTestProgram(DispatchInterp, 5000)
+
+	if cpu.Regs[2] != 150 {
+		t.Errorf("loop: r2=%v, want 150", cpu.Regs[2])
+	}
+	if v := bus.Read32(0x1000 + 99*4); v != 5050 {
+		t.Errorf("loop stores: last value %v, want 5050", v)
+	}
+	if cpu.Regs[5] != 0xFE {
+		t.Errorf("self-modifying code: r5=%v, want 0xFE", cpu.Regs[5])
+	}
+	if !cpu.Cpsr.T() || cpu.pc != 0x106 {
+		t.Errorf("thumb code not reached: pc=%v", cpu.pc)
+	}
+}
+
+func TestDispatchCached(t *testing.T) {
+	c1, b1 := runTestProgram(DispatchInterp, 5000)
+	c2, b2 := runTestProgram(DispatchCached, 5000)
+
+	if c1.Regs != c2.Regs {
+		t.Errorf("different registers:\ninterp: %v\ncached: %v", c1.Regs, c2.Regs)
+	}
+	if c1.Cpsr != c2.Cpsr || c1.pc != c2.pc || c1.Clock != c2.Clock {
+		t.Errorf("different state: cpsr=%v/%v pc=%v/%v clock=%d/%d",
+			c1.Cpsr.r, c2.Cpsr.r, c1.pc, c2.pc, c1.Clock, c2.Clock)
+	}
+	if !bytes.Equal(b1.mem, b2.mem) {
+		t.Errorf("different memory contents")
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	// Endless version of the first loop of the test program
+	code := append([]uint32(nil), testRunArm[:8]...)
+	code[7] = 0xEAFFFFF9 // bal 08
+	code[6] = 0xE2811001 // add r1, r1, #1
+	code[5] = 0xE5830000 // str r0, [r3]
+
+	for _, bm := range []struct {
+		name string
+		d    Dispatch
+	}{
+		{"interp", DispatchInterp},
+		{"cached", DispatchCached},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			cpu := NewCpu(ARMv4, newTestBus(code, nil, 0))
+			cpu.SetDispatch(bm.d)
+			cpu.SetPC(0)
+			cpu.Regs[3] = 0x1000
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cpu.Run(cpu.Clock + 10000)
+			}
+		})
+	}
+}
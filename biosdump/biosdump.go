@@ -0,0 +1,172 @@
+// Package biosdump validates dumps of the NDS BIOS and firmware, so that a
+// corrupt or incomplete dump can be reported as such, rather than showing up
+// as a game that doesn't boot.
+//
+// BIOS images are checked against the CRC32 of the known good dumps. The
+// firmware contains per-console data (MAC address, user settings,
+// calibration), so it can't be matched against a fixed checksum; instead,
+// its size and the CRC16 of the internal data blocks are checked.
+//
+// Dumps are usually kept in split layout (three files: biosnds9.rom,
+// biosnds7.rom and firmware.bin), but some dumping tools save a single
+// combined file with the ARM9 BIOS, the ARM7 BIOS and the firmware
+// concatenated in this order; SplitCombined extracts the three images.
+package biosdump
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+const (
+	Bios9Size = 4 * 1024
+	Bios7Size = 16 * 1024
+
+	Bios9CRC uint32 = 0x2AB23573
+	Bios7CRC uint32 = 0x1280F0D5
+)
+
+// Severity of a problem found in a dump
+type Severity int
+
+const (
+	// The dump has a wrong checksum: it might be a bad dump, or a
+	// replacement BIOS. It might still work.
+	Warning Severity = iota
+
+	// The dump is unusable (wrong size or layout).
+	Fatal
+)
+
+// A Problem describes an issue found in a dump.
+type Problem struct {
+	File     string // name of the image ("ARM9 BIOS", "ARM7 BIOS", "firmware")
+	Severity Severity
+	Msg      string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.File, p.Msg)
+}
+
+// Report is the list of problems found while checking one or more dumps.
+type Report []Problem
+
+// Fatal returns true if any of the problems makes the dumps unusable.
+func (r Report) Fatal() bool {
+	for _, p := range r {
+		if p.Severity == Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+func checkBios(name string, data []byte, size int, crc uint32) Report {
+	if len(data) != size {
+		return Report{{name, Fatal, fmt.Sprintf("invalid size: %d bytes (expected %d)", len(data), size)}}
+	}
+	if got := crc32.ChecksumIEEE(data); got != crc {
+		return Report{{name, Warning, fmt.Sprintf("CRC mismatch: %08X (expected %08X); bad dump?", got, crc)}}
+	}
+	return nil
+}
+
+// CheckBios9 verifies an ARM9 BIOS image.
+func CheckBios9(data []byte) Report {
+	return checkBios("ARM9 BIOS", data, Bios9Size, Bios9CRC)
+}
+
+// CheckBios7 verifies an ARM7 BIOS image.
+func CheckBios7(data []byte) Report {
+	return checkBios("ARM7 BIOS", data, Bios7Size, Bios7CRC)
+}
+
+// CRC16 as used by the firmware (and the BIOS GetCRC16 function)
+func crc16(crc uint16, data []byte) uint16 {
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Valid sizes of the firmware flash (256K on DS and DS Lite, 512K on iQue)
+var firmwareSizes = []int{256 * 1024, 512 * 1024}
+
+// CheckFirmware verifies a firmware image.
+func CheckFirmware(data []byte) Report {
+	const name = "firmware"
+
+	valid := false
+	for _, sz := range firmwareSizes {
+		valid = valid || len(data) == sz
+	}
+	if !valid {
+		msg := fmt.Sprintf("invalid size: %d bytes (expected 256K or 512K)", len(data))
+		if len(data) == 128*1024 {
+			msg += "; DSi firmware dumps are not supported"
+		}
+		return Report{{name, Fatal, msg}}
+	}
+
+	var rep Report
+
+	// Wifi settings: CRC16 at 0x2A, of the block starting at 0x2C (whose
+	// length is the first halfword)
+	wlen := int(binary.LittleEndian.Uint16(data[0x2C:]))
+	if wlen < 2 || 0x2C+wlen > len(data) {
+		rep = append(rep, Problem{name, Warning, fmt.Sprintf("invalid wifi settings length: %d", wlen)})
+	} else if got, exp := crc16(0, data[0x2C:0x2C+wlen]), binary.LittleEndian.Uint16(data[0x2A:]); got != exp {
+		rep = append(rep, Problem{name, Warning, fmt.Sprintf("wifi settings CRC mismatch: %04X (expected %04X); bad dump?", got, exp)})
+	}
+
+	// User settings: two copies at the end of the flash (the firmware uses
+	// the valid one, with the higher update counter). Each one is 0x100
+	// bytes, with a CRC16 at 0x72 of the first 0x70 bytes.
+	user := len(data) - 0x200
+	nvalid := 0
+	for i := 0; i < 2; i++ {
+		blk := data[user+i*0x100 : user+(i+1)*0x100]
+		if crc16(0xFFFF, blk[:0x70]) == binary.LittleEndian.Uint16(blk[0x72:]) {
+			nvalid++
+		}
+	}
+	if nvalid == 0 {
+		rep = append(rep, Problem{name, Warning, "both copies of user settings are corrupt; the firmware will ask to enter them again"})
+	}
+	return rep
+}
+
+// SplitCombined extracts the ARM9 BIOS, the ARM7 BIOS and the firmware from
+// a combined dump. It returns an error if the size of the data doesn't
+// match any combined layout.
+func SplitCombined(data []byte) (bios9, bios7, firmware []byte, err error) {
+	for _, sz := range firmwareSizes {
+		if len(data) == Bios9Size+Bios7Size+sz {
+			bios9 = data[:Bios9Size]
+			bios7 = data[Bios9Size : Bios9Size+Bios7Size]
+			firmware = data[Bios9Size+Bios7Size:]
+			return
+		}
+	}
+	return nil, nil, nil, fmt.Errorf("invalid size for a combined BIOS/firmware dump: %d bytes", len(data))
+}
+
+// IsCombined returns true if a file of the specified size is a combined
+// dump, rather than a firmware image alone.
+func IsCombined(size int64) bool {
+	for _, sz := range firmwareSizes {
+		if size == int64(Bios9Size+Bios7Size+sz) {
+			return true
+		}
+	}
+	return false
+}
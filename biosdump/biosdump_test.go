@@ -0,0 +1,44 @@
+package biosdump
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	if crc := crc16(0xFFFF, []byte("123456789")); crc != 0x4B37 {
+		t.Errorf("invalid CRC16: %04x", crc)
+	}
+}
+
+func TestCheckFirmware(t *testing.T) {
+	fw := make([]byte, 256*1024)
+	binary.LittleEndian.PutUint16(fw[0x2C:], 0x138)
+	binary.LittleEndian.PutUint16(fw[0x2A:], crc16(0, fw[0x2C:0x2C+0x138]))
+	user := fw[len(fw)-0x100:]
+	user[0] = 5
+	binary.LittleEndian.PutUint16(user[0x72:], crc16(0xFFFF, user[:0x70]))
+
+	if rep := CheckFirmware(fw); len(rep) != 0 {
+		t.Errorf("valid firmware: %v", rep)
+	}
+
+	// One corrupt copy of user settings is fine, both are not
+	user[1] = 1
+	if rep := CheckFirmware(fw); len(rep) != 1 || rep.Fatal() {
+		t.Errorf("corrupt user settings: %v", rep)
+	}
+
+	if rep := CheckFirmware(fw[:128*1024]); !rep.Fatal() {
+		t.Errorf("truncated firmware: %v", rep)
+	}
+
+	comb := make([]byte, Bios9Size+Bios7Size+len(fw))
+	copy(comb[Bios9Size+Bios7Size:], fw)
+	if !IsCombined(int64(len(comb))) || IsCombined(int64(len(fw))) {
+		t.Errorf("combined layout not detected")
+	}
+	if _, _, fw2, err := SplitCombined(comb); err != nil || len(fw2) != len(fw) || fw2[len(fw2)-0x100] != 5 {
+		t.Errorf("invalid split: %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"io/ioutil"
+	"ndsemu/emu/gfx"
+	log "ndsemu/emu/logger"
+	"ndsemu/hlebios"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var flagUpdateBoot = flag.Bool("update-boot", false, "regenerate the golden frame hashes of the boot tests")
+
+// Boot tests run the stock firmware headlessly (without a game card), driven
+// by an input script, and compare the frame hashes (see FrameHasher) taken
+// at regular checkpoints with the golden ones. They cover the whole boot
+// path: SPI devices (firmware, touchscreen calibration, power management),
+// RTC, VRAM mapping and the 2D/3D engines used by the menu.
+//
+// BIOS and firmware dumps can't be distributed, so the tests are skipped
+// unless they're found in the directory specified by $NDSEMU_BIOS (by
+// default, "bios"). Golden hashes depend on the exact dumps, so they're not
+// distributed either: run the tests once with -update-boot on a known good
+// build to generate them in testdata/boot. TestBootHomebrew runs without any
+// dump instead, and its golden hashes are distributed.
+var bootTests = []struct {
+	name      string
+	firstBoot bool // reset the RTC to its power-on state
+	frames    int
+}{
+	// Health and safety screen, then the main menu
+	{"menu", false, 900},
+	// Main menu, then the alarm clock settings
+	{"alarm", false, 1200},
+	// Power-on with a reset RTC: the firmware asks to set the clock
+	{"firstboot", true, 600},
+}
+
+const bootCheckpoint = 60 // frames between checkpoints
+
+func TestBoot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("boot tests are slow")
+	}
+	dir := os.Getenv("NDSEMU_BIOS")
+	if dir == "" {
+		dir = "bios"
+	}
+	for _, fn := range []string{"biosnds9.rom", "biosnds7.rom", "firmware.bin"} {
+		if _, err := os.Stat(filepath.Join(dir, fn)); err != nil {
+			t.Skipf("BIOS/firmware dumps not available: %v", err)
+		}
+	}
+	fw, err := ioutil.ReadFile(filepath.Join(dir, "firmware.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	biosDir = dir
+	log.Disable()
+
+	for _, bt := range bootTests {
+		t.Run(bt.name, func(t *testing.T) {
+			golden := filepath.Join("testdata", "boot", bt.name+".hash")
+			if _, err := os.Stat(golden); err != nil && !*flagUpdateBoot {
+				t.Skipf("no golden hashes (generate them with -update-boot): %v", err)
+			}
+			inplay, err := LoadInputScript(filepath.Join("testdata", "boot", bt.name+".input"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The firmware is written by the emulated console (eg: user
+			// settings), so use a fresh copy for each run
+			tmpdir, err := ioutil.TempDir("", "ndsemu-boot")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(tmpdir)
+			fwsav := filepath.Join(tmpdir, "firmware.bin")
+			if err := ioutil.WriteFile(fwsav, fw, 0666); err != nil {
+				t.Fatal(err)
+			}
+
+			Emu = NewNDSEmulator(fwsav)
+			if err := Emu.Hw.Ff.MapFirmwareFile(fwsav); err != nil {
+				t.Fatal(err)
+			}
+			if bt.firstBoot {
+				Emu.Hw.Rtc.ResetDefaults()
+			}
+			t0 := time.Date(2010, time.January, 1, 12, 0, 0, 0, time.UTC)
+			Emu.Hw.Rtc.SetClock(func() time.Time {
+				return t0.Add(time.Duration(Emu.framecount) * time.Second / 60)
+			})
+
+			checkBootHashes(t, golden, tmpdir, inplay, bt.frames)
+		})
+	}
+}
+
+// A minimal homebrew ROM, written for this test: the ARM9 shows a pattern
+// in VRAM bank A (in VRAM display mode) that changes every frame, while the
+// ARM7 increments a counter in its WRAM.
+var homebrewArm9 = []uint32{
+	0xE321F0DF, // 00: msr  cpsr_c, #0xDF (system mode, IRQs disabled)
+	0xE3A00301, // 04: mov  r0, #0x4000000
+	0xE3A01902, // 08: mov  r1, #0x8000
+	0xE3811003, // 0C: orr  r1, r1, #3
+	0xE5801304, // 10: str  r1, [r0, #0x304] (POWCNT1)
+	0xE3A01802, // 14: mov  r1, #0x20000
+	0xE5801000, // 18: str  r1, [r0] (DISPCNT: VRAM display, bank A)
+	0xE3A0251A, // 1C: mov  r2, #0x6800000
+	0xE3A03000, // 20: mov  r3, #0
+	0xE1D040B4, // 24: ldrh r4, [r0, #4] (wait for the end of VBlank)
+	0xE3140001, // 28: tst  r4, #1
+	0x1AFFFFFC, // 2C: bne  24
+	0xE1D040B4, // 30: ldrh r4, [r0, #4] (wait for VBlank)
+	0xE3140001, // 34: tst  r4, #1
+	0x0AFFFFFC, // 38: beq  30
+	0xE1A05002, // 3C: mov  r5, r2
+	0xE3A06000, // 40: mov  r6, #0
+	0xE0867003, // 44: add  r7, r6, r3
+	0xE0C570B2, // 48: strh r7, [r5], #2
+	0xE2866001, // 4C: add  r6, r6, #1
+	0xE3560903, // 50: cmp  r6, #0xC000
+	0x1AFFFFFA, // 54: bne  44
+	0xE2833001, // 58: add  r3, r3, #1
+	0xEAFFFFF0, // 5C: b    24
+}
+
+var homebrewArm7 = []uint32{
+	0xE321F0DF, // 00: msr  cpsr_c, #0xDF
+	0xE3A0050E, // 04: mov  r0, #0x3800000
+	0xE3A01000, // 08: mov  r1, #0
+	0xE2811001, // 0C: add  r1, r1, #1
+	0xE5801000, // 10: str  r1, [r0]
+	0xEAFFFFFC, // 14: b    0C
+}
+
+// Build the image of the homebrew ROM: header, ARM9 code at 0x200 (loaded
+// at 0x2000000), ARM7 code at 0x400 (loaded at 0x2380000).
+func homebrewRom() []byte {
+	rom := make([]byte, 0x600)
+	copy(rom, "NDSEMU TEST")
+	copy(rom[0xC:], "####")
+	hdr := []uint32{
+		0x200, 0x2000000, 0x2000000, uint32(len(homebrewArm9) * 4),
+		0x400, 0x2380000, 0x2380000, uint32(len(homebrewArm7) * 4),
+	}
+	for i, v := range hdr {
+		binary.LittleEndian.PutUint32(rom[0x20+i*4:], v)
+	}
+	for i, op := range homebrewArm9 {
+		binary.LittleEndian.PutUint32(rom[0x200+i*4:], op)
+	}
+	for i, op := range homebrewArm7 {
+		binary.LittleEndian.PutUint32(rom[0x400+i*4:], op)
+	}
+	return rom
+}
+
+// TestBootHomebrew boots a homebrew ROM without any dump: the boot is
+// skipped and the BIOS is replaced by the HLE stub, so the golden hashes
+// are distributed with the sources.
+func TestBootHomebrew(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "ndsemu-boot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	romfn := filepath.Join(tmpdir, "homebrew.nds")
+	if err := ioutil.WriteFile(romfn, homebrewRom(), 0666); err != nil {
+		t.Fatal(err)
+	}
+	// The firmware is never read when the boot is skipped
+	fwsav := filepath.Join(tmpdir, "firmware.bin")
+	if err := ioutil.WriteFile(fwsav, make([]byte, 256*1024), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	// Force the BIOS stubs, even if dumps are available
+	biosDir = tmpdir
+	biosStub9, biosStub7 = true, true
+	defer func() { biosStub9, biosStub7 = false, false }()
+	log.Disable()
+
+	Emu = NewNDSEmulator(fwsav)
+	hlebios.Install9(nds9.Cpu, nds9.Cp15, true)
+	hlebios.Install7(nds7.Cpu, true)
+	if err := Emu.Hw.Gc.MapCartFile(romfn); err != nil {
+		t.Fatal(err)
+	}
+	if err := Emu.Hw.Ff.MapFirmwareFile(fwsav); err != nil {
+		t.Fatal(err)
+	}
+	if err := Emu.SkipBoot(); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "boot", "homebrew.hash")
+	checkBootHashes(t, golden, tmpdir, new(InputPlayer), 180)
+}
+
+// Run the emulator for the specified number of frames, driven by an input
+// script, and compare the frame hashes at the checkpoints with the golden
+// ones (or regenerate them, with -update-boot).
+func checkBootHashes(t *testing.T, golden, tmpdir string, inplay *InputPlayer, frames int) {
+	hashfn := filepath.Join(tmpdir, "boot.hash")
+	fhash, err := NewFrameHasher(hashfn, bootCheckpoint, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := gfx.NewBufferMem(256, 192+90+192)
+	audio := make([]int16, cAudioFreq/60*2)
+	for nframe := 0; nframe < frames; nframe++ {
+		in := inplay.Update(nframe)
+		Emu.Hw.Key.SetButtons(in.Buttons)
+		Emu.Hw.Key.SetPenDown(in.PenDown)
+		Emu.Hw.Tsc.SetPen(in.PenDown, in.PenX, in.PenY)
+
+		Emu.RunOneFrame(screen, audio)
+		fhash.Hash(nframe, screen, audio)
+	}
+	if err := fhash.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readCheckpoints(hashfn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *flagUpdateBoot {
+		data := strings.Join(got, "\n") + "\n"
+		if err := ioutil.WriteFile(golden, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := readCheckpoints(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d checkpoints, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			// Later checkpoints are bound to differ as well
+			t.Fatalf("first divergence:\ngot:  %s\nwant: %s", got[i], want[i])
+		}
+	}
+}
+
+// Return the lines of a frame hash log taken at the checkpoints (that is,
+// those that include the machine state).
+func readCheckpoints(fn string) ([]string, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []string
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		fields := strings.Fields(scan.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if n, err := strconv.Atoi(fields[0]); err == nil && n%bootCheckpoint == 0 {
+			res = append(res, scan.Text())
+		}
+	}
+	return res, scan.Err()
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"ndsemu/memdump"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ControlServer listens on a TCP socket for simple line-based commands, so
+// that external tools (eg: dashboards or visualizers) can inspect the
+// emulator while it runs. Each command receives a single-line JSON reply.
+//
+// Supported commands:
+//
+//	regs    snapshot of all named I/O registers of both CPUs
+//	analyze strings and pointer tables found in main RAM (see memdump)
+//	dma     per-channel DMA statistics of the last frame
+//	dmatrace on|off
+//	        enable or disable the logging of each DMA transfer
+//	vram    table of the VRAM banks backing each 16K region of the VRAM
+//	        address spaces, and each texture/extended palette slot
+//	irqlog  last IRQs delivered to both CPUs, oldest first (see IrqLog)
+//	dumpframe
+//	        save the layers of the next frame as PNG files (see
+//	        NDSEmulator.DumpNextFrame)
+//	layers [a|b|3d MASK]
+//	        set the layers displayed by a 2D engine (see
+//	        e2d.SetDebugLayerMask) or the polygons drawn by the 3D engine
+//	        (see raster3d.SetDebugLayerMask); reply with the current masks
+//
+// The machine state can only be accessed while the emulation goroutine is
+// idle, so requests are queued and served by Poll, which the main loop calls
+// between frames.
+type ControlServer struct {
+	ln  net.Listener
+	req chan controlReq
+}
+
+// A command that must be served by Poll
+type controlReq struct {
+	cmd   string
+	reply chan []byte
+}
+
+type controlRegs struct {
+	Frame int             `json:"frame"`
+	Arm9  []hwio.RegValue `json:"arm9"`
+	Arm7  []hwio.RegValue `json:"arm7"`
+}
+
+type controlDma struct {
+	Frame int         `json:"frame"`
+	Arm9  [4]DmaStats `json:"arm9"`
+	Arm7  [4]DmaStats `json:"arm7"`
+}
+
+type controlVram struct {
+	Frame int      `json:"frame"`
+	Table []string `json:"table"`
+}
+
+type controlIrqLog struct {
+	Frame  int        `json:"frame"`
+	Events []IrqEvent `json:"events"`
+}
+
+type controlLayers struct {
+	A   uint32 `json:"a"`
+	B   uint32 `json:"b"`
+	E3d uint32 `json:"3d"`
+}
+
+type controlOk struct {
+	Ok bool `json:"ok"`
+}
+
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// NewControlServer starts listening on the specified address (eg:
+// "localhost:7777").
+func NewControlServer(addr string) (*ControlServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	cs := &ControlServer{ln: ln, req: make(chan controlReq)}
+	go cs.serve()
+	return cs, nil
+}
+
+func (cs *ControlServer) serve() {
+	for {
+		conn, err := cs.ln.Accept()
+		if err != nil {
+			return
+		}
+		go cs.handle(conn)
+	}
+}
+
+func (cs *ControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+	scan := bufio.NewScanner(conn)
+	for scan.Scan() {
+		var reply []byte
+		switch cmd := strings.TrimSpace(scan.Text()); cmd {
+		case "":
+			continue
+		case "regs", "analyze", "dma", "dmatrace on", "dmatrace off", "vram", "irqlog", "dumpframe":
+			req := controlReq{cmd, make(chan []byte)}
+			cs.req <- req
+			reply = <-req.reply
+		default:
+			if cmd != "layers" && !strings.HasPrefix(cmd, "layers ") {
+				reply = marshalReply(controlError{"unknown command: " + cmd})
+				break
+			}
+			req := controlReq{cmd, make(chan []byte)}
+			cs.req <- req
+			reply = <-req.reply
+		}
+		if _, err := conn.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+// Poll serves all the pending requests. It must be called while the
+// emulation goroutine is idle.
+func (cs *ControlServer) Poll() {
+	for {
+		select {
+		case req := <-cs.req:
+			switch req.cmd {
+			case "regs":
+				req.reply <- marshalReply(controlRegs{
+					Frame: Emu.framecount,
+					Arm9:  nds9.Bus.Snapshot(),
+					Arm7:  nds7.Bus.Snapshot(),
+				})
+			case "analyze":
+				req.reply <- marshalReply(memdump.Analyze(Emu.Mem.Ram[:], 0x02000000, memdump.DefaultOptions))
+			case "dma":
+				stats := controlDma{Frame: Emu.framecount}
+				for i := range stats.Arm9 {
+					stats.Arm9[i] = nds9.Dma[i].Stats()
+					stats.Arm7[i] = nds7.Dma[i].Stats()
+				}
+				req.reply <- marshalReply(stats)
+			case "dmatrace on":
+				log.EnableDebugModules(modDmaTrace.Mask())
+				req.reply <- marshalReply(controlOk{true})
+			case "dmatrace off":
+				log.DisableDebugModules(modDmaTrace.Mask())
+				req.reply <- marshalReply(controlOk{true})
+			case "vram":
+				vram := controlVram{Frame: Emu.framecount}
+				for _, r := range Emu.Hw.Mc.VramLayout() {
+					vram.Table = append(vram.Table, r.String())
+				}
+				req.reply <- marshalReply(vram)
+			case "dumpframe":
+				Emu.DumpNextFrame()
+				req.reply <- marshalReply(controlOk{true})
+			case "irqlog":
+				req.reply <- marshalReply(controlIrqLog{
+					Frame:  Emu.framecount,
+					Events: Emu.IrqLog.Events(cIrqLogSize),
+				})
+			default:
+				req.reply <- controlSetLayers(strings.Fields(req.cmd)[1:])
+			}
+		default:
+			return
+		}
+	}
+}
+
+func controlSetLayers(args []string) []byte {
+	if len(args) != 0 {
+		if len(args) != 2 {
+			return marshalReply(controlError{"usage: layers [a|b|3d MASK]"})
+		}
+		mask, err := strconv.ParseUint(args[1], 0, 32)
+		if err != nil {
+			return marshalReply(controlError{"invalid mask: " + args[1]})
+		}
+		switch args[0] {
+		case "a":
+			Emu.Hw.E2d[0].SetDebugLayerMask(uint32(mask))
+		case "b":
+			Emu.Hw.E2d[1].SetDebugLayerMask(uint32(mask))
+		case "3d":
+			Emu.Hw.E3d.SetDebugLayerMask(uint32(mask))
+		default:
+			return marshalReply(controlError{"invalid engine: " + args[0]})
+		}
+	}
+	return marshalReply(controlLayers{
+		A:   Emu.Hw.E2d[0].DebugLayerMask(),
+		B:   Emu.Hw.E2d[1].DebugLayerMask(),
+		E3d: Emu.Hw.E3d.DebugLayerMask(),
+	})
+}
+
+// Close stops listening for new connections
+func (cs *ControlServer) Close() error {
+	return cs.ln.Close()
+}
+
+func marshalReply(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.ModEmu.Errorf("control: %v", err)
+		data, _ = json.Marshal(controlError{err.Error()})
+	}
+	return append(data, '\n')
+}
@@ -4,6 +4,7 @@ import (
 	"ndsemu/emu"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"unsafe"
 )
 
 type DmaEvent int
@@ -14,6 +15,7 @@ const (
 	DmaEventGamecard
 	DmaEventHBlank
 	DmaEventGxFifo
+	DmaEventMainMemDisplay
 )
 
 type HwDmaFill struct {
@@ -43,6 +45,9 @@ type HwDmaChannel struct {
 	debugRepeat  bool
 	inProgress   bool
 	pendingEvent DmaEvent
+
+	stats     DmaStats // current frame
+	lastStats DmaStats // last complete frame
 }
 
 func NewHwDmaChannel(cpu CpuNum, ch int, bus emu.Bus, irq *HwIrq) *HwDmaChannel {
@@ -78,6 +83,8 @@ func (dma *HwDmaChannel) startEvent() DmaEvent {
 			return DmaEventImmediate
 		case 2:
 			return DmaEventHBlank
+		case 4:
+			return DmaEventMainMemDisplay
 		case 5:
 			return DmaEventGamecard
 		case 7:
@@ -131,6 +138,7 @@ func (dma *HwDmaChannel) xfer() {
 	sad := dma.DmaSad.Value
 	dad := dma.DmaDad.Value
 
+	evt := dma.startEvent()
 	irq := (ctrl>>14)&1 != 0
 	start := (ctrl >> 11) & 7
 	w32 := (ctrl>>10)&1 != 0
@@ -197,7 +205,17 @@ func (dma *HwDmaChannel) xfer() {
 		}
 	}
 
+	cycles := dma.xferCycles(sad, dad, cnt, wordsize)
+	dma.account(evt, sad, dad, cnt, wordsize, cycles)
+	dma.stall(cycles)
 	dma.inProgress = true
+	if dma.xferFast(sad, dad, cnt, wordsize, sinc, dinc) {
+		if sinc == 0 {
+			sad += cnt * wordsize
+		}
+		dad += cnt * wordsize
+		cnt = 0
+	}
 	for ; cnt != 0; cnt-- {
 		if w32 {
 			dma.Bus.Write32(dad, dma.Bus.Read32(sad))
@@ -237,6 +255,100 @@ func (dma *HwDmaChannel) xfer() {
 	}
 }
 
+// xferCycles returns the bus cycles taken by a transfer of cnt units,
+// using the timings of the regions of the first source and destination
+// addresses: the first unit is a non-sequential access, and the following
+// ones are sequential. The cost is the same whether the transfer is done
+// by xferFast or word by word.
+func (dma *HwDmaChannel) xferCycles(sad, dad, cnt, wordsize uint32) int64 {
+	bus, ok := dma.Bus.(emu.TimedBus)
+	if !ok || cnt == 0 {
+		return 0
+	}
+	timings := bus.RegionTimings()
+	st, dt := &timings[sad>>24], &timings[dad>>24]
+	if wordsize == 4 {
+		return int64(st.N32+dt.N32) + int64(cnt-1)*int64(st.S32+dt.S32)
+	}
+	return int64(st.N16+dt.N16) + int64(cnt-1)*int64(st.S16+dt.S16)
+}
+
+// stall charges the cycles of a transfer to the CPU that owns the channel,
+// which can't access the bus while the DMA is running. The bus timings are
+// already expressed in cycles of that CPU (see arm9Timings).
+func (dma *HwDmaChannel) stall(cycles int64) {
+	if dma.Irq != nil && dma.Irq.Cpu != nil {
+		dma.Irq.Cpu.Clock += cycles
+	}
+}
+
+// dmaFastBus is implemented by buses that give direct access to writable
+// memory (see hwio.Table.FetchPointerRW), and that can tell whether memory
+// hooks are installed over an area (see hwio.Table.Hooked).
+type dmaFastBus interface {
+	FetchPointerRW(addr uint32) []uint8
+	Hooked(begin, end uint32) bool
+}
+
+// xferFast performs the most common kinds of transfers (fills from a fixed
+// source, and copies with incrementing addresses) between memory areas in
+// one step, using Go copies rather than going through the bus for each word;
+// these are very frequent during loading screens and screen clears. It
+// returns false if the transfer must be done word by word instead (because
+// it involves I/O registers, decrementing addresses, unaligned addresses,
+// the end of a memory area, overlapping areas, or pages with memory hooks,
+// which must see each access).
+func (dma *HwDmaChannel) xferFast(sad, dad, cnt, wordsize uint32, sinc, dinc uint16) bool {
+	bus, ok := dma.Bus.(dmaFastBus)
+	if !ok || (sad|dad)&(wordsize-1) != 0 || (dinc != 0 && dinc != 3) {
+		return false
+	}
+
+	size := int(cnt * wordsize)
+	srcsize := wordsize
+	if sinc == 0 {
+		srcsize = uint32(size)
+	}
+	if bus.Hooked(dad, dad+uint32(size)-1) || bus.Hooked(sad, sad+srcsize-1) {
+		return false
+	}
+
+	dst := bus.FetchPointerRW(dad)
+	src := dma.Bus.FetchPointer(sad)
+	if len(dst) < size || len(src) < int(wordsize) {
+		return false
+	}
+	dst = dst[:size]
+
+	switch sinc {
+	case 2:
+		// Fill: replicate the source word, doubling the filled area
+		// at each step
+		var pattern [4]byte
+		copy(pattern[:], src[:wordsize])
+		copy(dst, pattern[:wordsize])
+		for n := int(wordsize); n < size; n *= 2 {
+			copy(dst[n:], dst[:n])
+		}
+	case 0:
+		if len(src) < size {
+			return false
+		}
+		// The DMA copies word by word in increasing order, so if the
+		// destination begins within the source area, data written by the
+		// transfer is read back again; copy would behave like memmove.
+		s0 := uintptr(unsafe.Pointer(&src[0]))
+		d0 := uintptr(unsafe.Pointer(&dst[0]))
+		if d0 > s0 && d0 < s0+uintptr(size) {
+			return false
+		}
+		copy(dst, src[:size])
+	default:
+		return false
+	}
+	return true
+}
+
 func (dma *HwDmaChannel) TriggerEvent(event DmaEvent) {
 	if event == DmaEventInvalid {
 		log.ModDma.Fatalf("invalid DMA event triggered (?)")
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"ndsemu/arm"
+	"ndsemu/emu"
+	"ndsemu/emu/hwio"
+	"testing"
+)
+
+func TestDmaCycles(t *testing.T) {
+	bus := hwio.NewTable("bus7")
+	bus.SetWaitStates(0)
+	bus.SetRegionTimings(0x02000000, 0x02FFFFFF, emu.AccessTimings{N16: 8, S16: 1, N32: 9, S32: 2})
+	ram := make([]byte, 0x10000)
+	bus.MapMemorySlice(0x02000000, 0x0200FFFF, ram, false)
+	cpu := arm.NewCpu(arm.ARMv4, bus)
+	dma := NewHwDmaChannel(CpuNds7, 0, bus, NewHwIrq("irq7", cpu))
+
+	for i := range ram[:0x100] {
+		ram[i] = byte(i)
+	}
+
+	tests := []struct {
+		name  string
+		ctrl  uint16
+		dad   uint32
+		fast  bool
+		clock int64
+	}{
+		// N+N for the first unit, S+S for the following 15 ones
+		{"copy16", 0x8000, 0x2001000, true, 16 + 15*2},
+		{"copy32", 0x8400, 0x2001000, true, 18 + 15*4},
+		{"fill32", 0x8500, 0x2001000, true, 18 + 15*4},
+		// Decrementing destination: not handled by xferFast
+		{"copy16-dec", 0x8020, 0x2001100, false, 16 + 15*2},
+	}
+	for _, tt := range tests {
+		dinc := (tt.ctrl >> 5) & 3
+		sinc := (tt.ctrl >> 7) & 3
+		wordsize := uint32(2 + 2*((tt.ctrl>>10)&1))
+		if fast := dma.xferFast(0x2000000, 0x2002000, 16, wordsize, sinc, dinc); fast != tt.fast {
+			t.Errorf("%s: xferFast=%v, want %v", tt.name, fast, tt.fast)
+		}
+
+		cpu.Clock = 0
+		dma.DmaSad.Value = 0x2000000
+		dma.DmaDad.Value = tt.dad
+		dma.DmaCount.Value = 16
+		dma.DmaCntrl.Value = tt.ctrl
+		dma.xfer()
+		if cpu.Clock != tt.clock {
+			t.Errorf("%s: charged %d cycles, want %d", tt.name, cpu.Clock, tt.clock)
+		}
+	}
+}
+
+func TestDmaHooks(t *testing.T) {
+	bus := hwio.NewTable("bus7")
+	bus.SetWaitStates(0)
+	ram := make([]byte, 0x10000)
+	bus.MapMemorySlice(0x02000000, 0x0200FFFF, ram, false)
+	cpu := arm.NewCpu(arm.ARMv4, bus)
+	dma := NewHwDmaChannel(CpuNds7, 0, bus, NewHwIrq("irq7", cpu))
+
+	var writes []uint32
+	bus.AddHook(0x2001000, 0x2001FFF, nil, func(addr uint32, size int, val uint32) (uint32, bool) {
+		writes = append(writes, addr)
+		return val, true
+	})
+
+	// Copy of 16 words into the hooked page: each write must be seen
+	ram[0] = 0x55
+	dma.DmaSad.Value = 0x2000000
+	dma.DmaDad.Value = 0x2001000
+	dma.DmaCount.Value = 16
+	dma.DmaCntrl.Value = 0x8400
+	dma.xfer()
+	if len(writes) != 16 || writes[0] != 0x2001000 || writes[15] != 0x200103C {
+		t.Errorf("hook saw %d writes: %x", len(writes), writes)
+	}
+	if ram[0x1000] != 0x55 {
+		t.Errorf("data not copied")
+	}
+
+	// Transfers outside the hooked pages still take the fast path
+	if !dma.xferFast(0x2000000, 0x2002000, 16, 4, 0, 0) {
+		t.Errorf("fast path not used outside the hooked pages")
+	}
+}
+
+func TestDmaStats(t *testing.T) {
+	bus := hwio.NewTable("bus7")
+	bus.SetWaitStates(0)
+	bus.SetRegionTimings(0x02000000, 0x02FFFFFF, emu.AccessTimings{N16: 8, S16: 1, N32: 9, S32: 2})
+	ram := make([]byte, 0x10000)
+	bus.MapMemorySlice(0x02000000, 0x0200FFFF, ram, false)
+	cpu := arm.NewCpu(arm.ARMv4, bus)
+	dma := NewHwDmaChannel(CpuNds7, 0, bus, NewHwIrq("irq7", cpu))
+
+	// Two 32-bit transfers of 16 words
+	for i := 0; i < 2; i++ {
+		dma.DmaSad.Value = 0x2000000
+		dma.DmaDad.Value = 0x2001000
+		dma.DmaCount.Value = 16
+		dma.DmaCntrl.Value = 0x8400
+		dma.xfer()
+	}
+	want := DmaStats{Transfers: 2, Units: 32, Bytes: 128, Cycles: 2 * (18 + 15*4)}
+	if dma.stats != want {
+		t.Errorf("invalid stats: %+v, want %+v", dma.stats, want)
+	}
+	if cpu.Clock != want.Cycles {
+		t.Errorf("cycles charged to the CPU: %d, want %d", cpu.Clock, want.Cycles)
+	}
+
+	dma.endFrame()
+	if s := dma.Stats(); s != want {
+		t.Errorf("invalid stats of last frame: %+v, want %+v", s, want)
+	}
+
+	// With dmatrace disabled, the end of the frame doesn't build the log
+	// fields
+	if allocs := testing.AllocsPerRun(10, func() {
+		dma.stats = want
+		dma.endFrame()
+	}); allocs != 0 {
+		t.Errorf("endFrame allocates with dmatrace disabled: %v", allocs)
+	}
+}
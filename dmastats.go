@@ -0,0 +1,76 @@
+package main
+
+import (
+	"ndsemu/emu"
+	log "ndsemu/emu/logger"
+
+	"gopkg.in/Sirupsen/logrus.v0"
+)
+
+// Every DMA transfer, and the per-frame statistics of each channel, are
+// logged by this module. Enable with "-log dmatrace", or at runtime through
+// the control socket ("dmatrace on").
+var modDmaTrace = log.NewModule("dmatrace")
+
+var dmaEventNames = [...]string{"invalid", "immediate", "gamecard", "hblank", "gxfifo", "mainmem"}
+
+func (evt DmaEvent) String() string {
+	if int(evt) < len(dmaEventNames) {
+		return dmaEventNames[evt]
+	}
+	return "unknown"
+}
+
+// DmaStats accumulates the activity of a DMA channel during a frame
+type DmaStats struct {
+	Transfers int   `json:"transfers"`
+	Units     int   `json:"units"` // number of halfwords/words
+	Bytes     int   `json:"bytes"`
+	Cycles    int64 `json:"cycles"` // bus cycles charged to the CPU (see xferCycles)
+}
+
+// Account for a transfer of cnt units of wordsize bytes, taking the
+// specified number of cycles, and trace it. Since DMA transfers are
+// performed instantly, the cycle counter is the time at which the transfer
+// was started.
+func (dma *HwDmaChannel) account(evt DmaEvent, sad, dad, cnt, wordsize uint32, cycles int64) {
+	dma.stats.Transfers++
+	dma.stats.Units += int(cnt)
+	dma.stats.Bytes += int(cnt * wordsize)
+	dma.stats.Cycles += cycles
+
+	modDmaTrace.WithDelayedFields(func() log.Fields {
+		return log.Fields{
+			"cpu":     [2]string{"arm9", "arm7"}[dma.Cpu],
+			"ch":      dma.Channel,
+			"trigger": evt.String(),
+			"sad":     emu.Hex32(sad),
+			"dad":     emu.Hex32(dad),
+			"cnt":     cnt,
+			"wsize":   wordsize,
+			"cycles":  cycles,
+			"clk":     Emu.Sync.Cycles(),
+		}
+	}).Infof("transfer")
+}
+
+// Stats returns the statistics of the channel for the last emulated frame
+func (dma *HwDmaChannel) Stats() DmaStats {
+	return dma.lastStats
+}
+
+// Must be called at the end of each frame
+func (dma *HwDmaChannel) endFrame() {
+	if dma.stats.Transfers != 0 && modDmaTrace.Enabled(logrus.InfoLevel) {
+		modDmaTrace.WithFields(log.Fields{
+			"cpu":       [2]string{"arm9", "arm7"}[dma.Cpu],
+			"ch":        dma.Channel,
+			"transfers": dma.stats.Transfers,
+			"units":     dma.stats.Units,
+			"bytes":     dma.stats.Bytes,
+			"cycles":    dma.stats.Cycles,
+		}).Infof("frame stats")
+	}
+	dma.lastStats = dma.stats
+	dma.stats = DmaStats{}
+}
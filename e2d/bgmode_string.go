@@ -4,9 +4,9 @@ package e2d
 
 import "fmt"
 
-const _BgMode_name = "BgModeTextBgModeAffineBgModeAffineMap16BgModeAffineBitmapBgModeAffineBitmapDirectBgModeLargeBitmapBgMode3D"
+const _BgMode_name = "BgModeTextBgModeAffineBgModeAffineMap16BgModeAffineBitmapBgModeAffineBitmapDirectBgModeLargeBitmapBgMode3DBgModeNone"
 
-var _BgMode_index = [...]uint8{0, 10, 22, 39, 57, 81, 98, 106}
+var _BgMode_index = [...]uint8{0, 10, 22, 39, 57, 81, 98, 106, 116}
 
 func (i BgMode) String() string {
 	if i < 0 || i >= BgMode(len(_BgMode_index)-1) {
@@ -0,0 +1,139 @@
+package e2d
+
+import (
+	"ndsemu/emu/gfx"
+	log "ndsemu/emu/logger"
+)
+
+/************************************************
+ * Display capture (engine A only)
+ ************************************************/
+
+// Display capture writes the lines being displayed into a VRAM bank (that
+// should be mapped to LCDC). It is activated through DISPCAPCNT, and lasts
+// for one frame. Its sources are:
+//
+//	A: the final output of engine A, or the 3D output only
+//	B: the VRAM bank selected in DISPCNT (as in VRAM display mode), or the
+//	   main memory display FIFO
+//
+// The captured line is either one of the two sources, or a blend of them.
+
+func (e2d *HwEngine2d) startCapture() {
+	dc := &e2d.dispcap
+	source := (e2d.DispCapCnt.Value >> 29) & 3
+	srca := (e2d.DispCapCnt.Value >> 24) & 1
+	srcb := (e2d.DispCapCnt.Value >> 25) & 1
+
+	if srca != 0 && e2d.e3d == nil {
+		modLcd.Fatalf("unimplemented display capture source=%d srca=%d srb=%d", source, srca, srcb)
+	}
+
+	// Begin capturing this frame
+	dc.Enabled = true
+	dc.Mode = int(source)
+	dc.Src3D = srca != 0
+	dc.SrcFifo = srcb != 0
+	dc.WBank = int((e2d.DispCapCnt.Value >> 16) & 3)
+	dc.WOffset = ((e2d.DispCapCnt.Value >> 18) & 3) * 0x8000
+	dc.RBank = int((e2d.DispCnt.Value >> 18) & 3)
+	dc.ROffset = ((e2d.DispCapCnt.Value >> 26) & 3) * 0x8000
+	dc.AlphaA = e2d.DispCapCnt.Value & 0x1F
+	dc.AlphaB = (e2d.DispCapCnt.Value >> 8) & 0x1F
+	if dc.AlphaA > 16 {
+		dc.AlphaA = 16
+	}
+	if dc.AlphaB > 16 {
+		dc.AlphaB = 16
+	}
+
+	switch (e2d.DispCapCnt.Value >> 20) & 3 {
+	case 0:
+		dc.Width, dc.Height = 128, 128
+	case 1:
+		dc.Width, dc.Height = 256, 64
+	case 2:
+		dc.Width, dc.Height = 256, 128
+	case 3:
+		dc.Width, dc.Height = 256, 192
+	}
+
+	modLcd.WithDelayedFields(func() log.Fields {
+		return log.Fields{
+			"src":   source,
+			"sa":    srca,
+			"sb":    srcb,
+			"wbank": string(rune('A' + dc.WBank)),
+			"woff":  dc.WOffset,
+			"w":     dc.Width,
+			"h":     dc.Height,
+		}
+	}).Infof("Capture activated")
+}
+
+// Capture line y of the current frame; screen is the output of engine A,
+// before master brightness is applied.
+func (e2d *HwEngine2d) captureLine(y int, screen gfx.Line) {
+	dc := &e2d.dispcap
+	capbuf := gfx.NewLine(e2d.mc.VramRawBank(dc.WBank)[dc.WOffset:])
+
+	// Source A is either the final screen output, or the 3D output only. In
+	// the latter case, pixels where nothing was drawn are transparent.
+	srca := screen
+	if dc.Src3D {
+		srca = e2d.e3d.Line3D(y)
+	}
+	pixa := func(x int) uint16 {
+		pix := srca.Get32(x)
+		if dc.Src3D && int32(pix) >= 0 {
+			return 0
+		}
+		return uint16(pix&0x7FFF) | 0x8000
+	}
+
+	// Source B pixels are read as they are, including their alpha bit
+	var srcb gfx.Line
+	if !dc.SrcFifo {
+		srcb = gfx.NewLine(e2d.mc.VramRawBank(dc.RBank)[dc.ROffset:])
+	}
+	pixb := func(x int) uint16 {
+		if dc.SrcFifo {
+			return e2d.mmfifo[x]
+		}
+		return srcb.Get16(x)
+	}
+
+	for x := 0; x < dc.Width; x++ {
+		var pix uint16
+		switch dc.Mode {
+		case 0:
+			pix = pixa(x)
+		case 1:
+			pix = pixb(x)
+		case 2, 3:
+			pix = captureBlend(pixa(x), pixb(x), dc.AlphaA, dc.AlphaB)
+		}
+		capbuf.Set16(x, pix)
+	}
+
+	// Offsets wrap within the 128K bank
+	dc.ROffset = (dc.ROffset + uint32(dc.Width*2)) & 0x1FFFF
+	dc.WOffset = (dc.WOffset + uint32(dc.Width*2)) & 0x1FFFF
+}
+
+// Blend two captured pixels: transparent pixels (bit 15 clear) don't
+// contribute to the result, which is transparent only if no source
+// contributed to it.
+func captureBlend(a, b uint16, eva, evb uint32) uint16 {
+	if a&0x8000 == 0 {
+		eva = 0
+	}
+	if b&0x8000 == 0 {
+		evb = 0
+	}
+	pix := blend555(a, b, eva, evb, 4)
+	if eva != 0 || evb != 0 {
+		pix |= 0x8000
+	}
+	return pix
+}
@@ -0,0 +1,62 @@
+package e2d
+
+import (
+	"ndsemu/emu"
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+type testCapture3D struct{ line []byte }
+
+func (c *testCapture3D) Line3D(y int) gfx.Line { return gfx.NewLine(c.line) }
+
+func (c *testCapture3D) DrawLines(ctx *gfx.LayerCtx, y int, out func(y int, dst gfx.Line)) {
+	for line := ctx.NextLine(); !line.IsNil(); line = ctx.NextLine() {
+		out(y, line)
+		y++
+	}
+}
+
+func TestDisplayCapture(t *testing.T) {
+	mc := newTestMemCtrl()
+	for i := range mc.raw {
+		mc.raw[i] = make([]byte, 128*1024)
+	}
+	c3d := &testCapture3D{line: make([]byte, cScreenWidth*4)}
+	e2d := NewHwEngine2d(0, mc, c3d)
+
+	screen := gfx.NewLine(make([]byte, cScreenWidth*4))
+	screen.Set32(0, 0x001F)
+	screen.Set32(1, 0x7C00)
+	emu.Write32LE(c3d.line[0:], 0x800003E0) // drawn
+	emu.Write32LE(c3d.line[4:], 0x000003E0) // not drawn
+	emu.Write16LE(mc.raw[1][0x8000:], 0x801F)
+	emu.Write16LE(mc.raw[1][0x8002:], 0x001F) // transparent
+
+	for _, tc := range []struct {
+		name   string
+		cnt    uint32
+		p0, p1 uint16
+	}{
+		{"screen", 0 << 29, 0x801F, 0xFC00},
+		{"3D", 0<<29 | 1<<24, 0x83E0, 0x0000},
+		{"VRAM", 1<<29 | 1<<26, 0x801F, 0x001F},
+		{"blend", 2<<29 | 1<<26 | 8<<8 | 8, 0x801F, 0xBC00},
+		{"blend, saturated", 2<<29 | 1<<26 | 16<<8 | 16, 0x801F, 0xFC00},
+		{"blend 3D, transparent", 2<<29 | 1<<24 | 1<<26 | 8<<8 | 8, 0x81EF, 0x0000},
+	} {
+		// Capture into bank D at offset 0x10000, 128x128, reading from bank B
+		e2d.DispCnt.Value = 1 << 18
+		e2d.DispCapCnt.Value = 1<<31 | tc.cnt | 3<<16 | 2<<18
+		e2d.startCapture()
+		e2d.captureLine(0, screen)
+		p0 := emu.Read16LE(mc.raw[3][0x10000:])
+		p1 := emu.Read16LE(mc.raw[3][0x10002:])
+		if p0 != tc.p0 || p1 != tc.p1 {
+			t.Errorf("%s: got %04x,%04x, want %04x,%04x", tc.name, p0, p1, tc.p0, tc.p1)
+		}
+		if e2d.dispcap.WOffset != 0x10000+128*2 {
+			t.Errorf("%s: write offset not advanced: %x", tc.name, e2d.dispcap.WOffset)
+		}
+	}
+}
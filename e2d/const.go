@@ -10,5 +10,3 @@ const (
 )
 
 var modLcd = log.ModGfx
-
-var gKeyState []byte
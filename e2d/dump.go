@@ -0,0 +1,127 @@
+package e2d
+
+import (
+	"image"
+	"image/color"
+	"ndsemu/emu/gfx"
+)
+
+// FrameDump is the output of an engine for a single frame, split by layer,
+// to debug rendering issues without tracing the drawing code (see
+// DumpNextFrame). Pixels that were not drawn on a layer are transparent.
+// Layers are dumped as they are drawn, before windows and color special
+// effects are applied; BG and OBJ layers are only drawn in the normal
+// display mode (mode 1), so they're empty in the other modes.
+type FrameDump struct {
+	Engine    byte // 'A' or 'B'
+	BG        [4]*image.NRGBA
+	OBJ       *image.NRGBA
+	Layer3D   *image.NRGBA // 3D output, if displayed as BG0 (nil otherwise)
+	Composite *image.NRGBA // final output, after master brightness
+}
+
+type dumpReq struct {
+	done func(*FrameDump)
+}
+
+// DumpNextFrame asks the engine to dump the layers of the next frame; done
+// is called with the dump at the end of the frame, from the emulation
+// goroutine. It can be called from any goroutine.
+func (e2d *HwEngine2d) DumpNextFrame(done func(*FrameDump)) {
+	select {
+	case e2d.dumpCh <- dumpReq{done}:
+	default:
+		modLcd.Warnf("%c: frame dump already pending", e2d.Name())
+	}
+}
+
+func newDumpImage() *image.NRGBA {
+	return image.NewNRGBA(image.Rect(0, 0, cScreenWidth, cScreenHeight))
+}
+
+// Called at the beginning of the frame: start dumping it if requested
+func (e2d *HwEngine2d) beginDump() {
+	select {
+	case req := <-e2d.dumpCh:
+		d := &FrameDump{
+			Engine:    e2d.Name(),
+			OBJ:       newDumpImage(),
+			Composite: newDumpImage(),
+		}
+		for i := range d.BG {
+			d.BG[i] = newDumpImage()
+		}
+		if e2d.A() && e2d.e3d != nil {
+			d.Layer3D = newDumpImage()
+		}
+		e2d.dump, e2d.dumpDone = d, req.done
+		e2d.lm.Cfg.Snoop = e2d.dumpLayers
+	default:
+	}
+}
+
+// Called at the end of the frame: hand over the dump, if any
+func (e2d *HwEngine2d) endDump() {
+	if e2d.dump == nil {
+		return
+	}
+	if !e2d.dump3D {
+		e2d.dump.Layer3D = nil
+	}
+	e2d.dumpDone(e2d.dump)
+	e2d.dump, e2d.dumpDone, e2d.dump3D = nil, nil, false
+	e2d.lm.Cfg.Snoop = nil
+}
+
+func rgb555(c uint16) color.NRGBA {
+	r, g, b := uint8(c&0x1F), uint8(c>>5)&0x1F, uint8(c>>10)&0x1F
+	return color.NRGBA{r<<3 | r>>2, g<<3 | g>>2, b<<3 | b>>2, 0xFF}
+}
+
+// Snoop function of the layer manager (see gfx.LayerManagerConfig): convert
+// the pixels of the BG and OBJ layers to RGB, using the palettes of the
+// current line.
+func (e2d *HwEngine2d) dumpLayers(y int, layers []gfx.Line) {
+	d := e2d.dump
+	for lidx := 0; lidx < 4; lidx++ {
+		// Palettes are stored in priority order (see Mode1_BeginLine)
+		pri := 0
+		for i := range e2d.bgWinBits {
+			if e2d.bgWinBits[i] == 1<<uint(lidx) {
+				pri = i
+			}
+		}
+		for x := 0; x < cScreenWidth; x++ {
+			if pix := LayerPixel(layers[lidx].Get32(x)); !pix.Transparent() {
+				d.BG[lidx].SetNRGBA(x, y, rgb555(e2d.bgColor(pri, pix)))
+			}
+		}
+	}
+	for x := 0; x < cScreenWidth; x++ {
+		if pix := LayerPixel(layers[4].Get32(x)); !pix.Transparent() {
+			d.OBJ.SetNRGBA(x, y, rgb555(e2d.objColor(pix)))
+		}
+	}
+
+	// The 3D output is available only when it's displayed
+	if d.Layer3D != nil && e2d.bgmodes[0] == BgMode3D {
+		e2d.dump3D = true
+		line := e2d.e3d.Line3D(y)
+		for x := 0; x < cScreenWidth; x++ {
+			if pix := line.Get32(x); pix&(1<<31) != 0 {
+				c := rgb555(uint16(pix))
+				a := uint8(pix>>16) & 0x1F
+				c.A = a<<3 | a>>2
+				d.Layer3D.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// Dump a line of the final output (in the screen format, 0x00BBGGRR)
+func (e2d *HwEngine2d) dumpComposite(y int, screen gfx.Line) {
+	for x := 0; x < cScreenWidth; x++ {
+		pix := screen.Get32(x)
+		e2d.dump.Composite.SetNRGBA(x, y, color.NRGBA{uint8(pix), uint8(pix >> 8), uint8(pix >> 16), 0xFF})
+	}
+}
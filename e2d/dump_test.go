@@ -0,0 +1,67 @@
+package e2d
+
+import (
+	"image/color"
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestDumpFrame(t *testing.T) {
+	mc := newTestMemCtrl()
+	e2d := NewHwEngine2d(1, mc, nil)
+
+	// Mode 1, BG0 only. All VRAM bytes are 0x11, so that BG0 is filled by
+	// tile 0x111 with palette 1, whose pixels use color 1 (red).
+	e2d.DispCnt.Value = 1<<16 | 1<<8
+	for i := range mc.vram.Ptr {
+		for j := range mc.vram.Ptr[i] {
+			mc.vram.Ptr[i][j] = 0x11
+		}
+	}
+	mc.pal[17*2] = 0x1F
+
+	var dump *FrameDump
+	e2d.DumpNextFrame(func(d *FrameDump) { dump = d })
+
+	screen := gfx.NewBufferMem(cScreenWidth, cScreenHeight)
+	e2d.BeginFrame()
+	for y := 0; y < cScreenHeight; y++ {
+		e2d.BeginLine(y, screen.Line(y))
+		e2d.EndLine(y)
+	}
+	e2d.EndFrame()
+
+	if dump == nil {
+		t.Fatal("frame not dumped")
+	}
+	if dump.Engine != 'B' || dump.Layer3D != nil {
+		t.Errorf("invalid dump: engine=%c 3d=%v", dump.Engine, dump.Layer3D != nil)
+	}
+	red := color.NRGBA{0xFF, 0, 0, 0xFF}
+	for _, p := range [][2]int{{0, 0}, {100, 100}, {255, 191}} {
+		if c := dump.BG[0].NRGBAAt(p[0], p[1]); c != red {
+			t.Errorf("BG0 %v: got %v, want %v", p, c, red)
+		}
+		if c := dump.BG[1].NRGBAAt(p[0], p[1]); c.A != 0 {
+			t.Errorf("BG1 %v: got %v, want transparent", p, c)
+		}
+		if c := dump.OBJ.NRGBAAt(p[0], p[1]); c.A != 0 {
+			t.Errorf("OBJ %v: got %v, want transparent", p, c)
+		}
+		if c := dump.Composite.NRGBAAt(p[0], p[1]); c != red {
+			t.Errorf("composite %v: got %v, want %v", p, c, red)
+		}
+	}
+
+	// Only the requested frame is dumped
+	dump = nil
+	e2d.BeginFrame()
+	for y := 0; y < cScreenHeight; y++ {
+		e2d.BeginLine(y, screen.Line(y))
+		e2d.EndLine(y)
+	}
+	e2d.EndFrame()
+	if dump != nil {
+		t.Errorf("frame dumped twice")
+	}
+}
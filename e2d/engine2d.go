@@ -5,6 +5,7 @@ import (
 	"ndsemu/emu/gfx"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"sync/atomic"
 )
 
 type bgRegs struct {
@@ -13,11 +14,47 @@ type bgRegs struct {
 	PA, PB     *uint16
 	PC, PD     *uint16
 	PX, PY     *uint32
+
+	// Set when the reference point is written, so that the internal
+	// reference point is reloaded at the start of the next line
+	reloadX, reloadY bool
+
+	// Values latched at the start of each line (see latch), used by the
+	// layers while the CPUs keep running: scrolling, affine parameters, and
+	// the internal reference point of affine layers.
+	xofs, yofs     int
+	pa, pb, pc, pd int32
+	refX, refY     int32
 }
 
 func (r *bgRegs) priority() uint16 { return (*r.Cnt & 3) }
 func (r *bgRegs) depth256() bool   { return (*r.Cnt>>7)&1 != 0 }
 
+// Latch the registers for line y. The internal reference point of affine
+// layers is loaded from BGxX/BGxY at the start of the frame (or when they're
+// written), and incremented by PB/PD after each line.
+func (r *bgRegs) latch(y int) {
+	r.xofs, r.yofs = int(*r.XOfs), int(*r.YOfs)
+	if r.PX == nil {
+		return
+	}
+
+	if y == 0 || r.reloadX {
+		r.refX = int32(*r.PX<<4) >> 4
+	} else {
+		r.refX += int32(int16(*r.PB))
+	}
+	if y == 0 || r.reloadY {
+		r.refY = int32(*r.PY<<4) >> 4
+	} else {
+		r.refY += int32(int16(*r.PD))
+	}
+	r.reloadX, r.reloadY = false, false
+
+	r.pa, r.pb = int32(int16(*r.PA)), int32(int16(*r.PB))
+	r.pc, r.pd = int32(int16(*r.PC)), int32(int16(*r.PD))
+}
+
 type HwEngine2d struct {
 	Idx      int
 	DispCnt  hwio.Reg32 `hwio:"offset=0x00,wcb"`
@@ -37,14 +74,14 @@ type HwEngine2d struct {
 	Bg2PB    hwio.Reg16 `hwio:"offset=0x22,writeonly"`
 	Bg2PC    hwio.Reg16 `hwio:"offset=0x24,writeonly"`
 	Bg2PD    hwio.Reg16 `hwio:"offset=0x26,writeonly"`
-	Bg2PX    hwio.Reg32 `hwio:"offset=0x28,writeonly"`
-	Bg2PY    hwio.Reg32 `hwio:"offset=0x2C,writeonly"`
+	Bg2PX    hwio.Reg32 `hwio:"offset=0x28,writeonly,wcb"`
+	Bg2PY    hwio.Reg32 `hwio:"offset=0x2C,writeonly,wcb"`
 	Bg3PA    hwio.Reg16 `hwio:"offset=0x30,writeonly"`
 	Bg3PB    hwio.Reg16 `hwio:"offset=0x32,writeonly"`
 	Bg3PC    hwio.Reg16 `hwio:"offset=0x34,writeonly"`
 	Bg3PD    hwio.Reg16 `hwio:"offset=0x36,writeonly"`
-	Bg3PX    hwio.Reg32 `hwio:"offset=0x38,writeonly"`
-	Bg3PY    hwio.Reg32 `hwio:"offset=0x3C,writeonly"`
+	Bg3PX    hwio.Reg32 `hwio:"offset=0x38,writeonly,wcb"`
+	Bg3PY    hwio.Reg32 `hwio:"offset=0x3C,writeonly,wcb"`
 	Win0X    hwio.Reg16 `hwio:"offset=0x40,writeonly"`
 	Win1X    hwio.Reg16 `hwio:"offset=0x42,writeonly"`
 	Win0Y    hwio.Reg16 `hwio:"offset=0x44,writeonly"`
@@ -63,11 +100,20 @@ type HwEngine2d struct {
 
 	bgregs    [4]bgRegs
 	bgmodes   [4]BgMode
+	badBgMode bool // BG mode in DISPCNT is invalid (already reported)
 	mc        MemoryController
 	lineBuf   [4 * (cScreenWidth + 16)]byte
+	objMosBuf [4 * (cScreenWidth + 16)]byte // scratch line for mosaic sprites
 	lm        gfx.LayerManager
-	l3d       gfx.Layer
+	e3d       Engine3D
 	dispmode  int
+	dbgHidden uint32 // atomic; layers hidden for debugging (see SetDebugLayerMask)
+	dispcnt   uint32 // DISPCNT, latched at the start of each line
+	bldcnt    uint32 // BLDCNT, latched at the start of each line
+	bldEva    uint32 // BLDALPHA/BLDY coefficients (clamped to 16)
+	bldEvb    uint32
+	bldEvy    uint32
+	mosaic    uint32 // MOSAIC, latched at the start of each line
 	curline   int
 	curscreen gfx.Line
 	modeTable [4]struct {
@@ -79,6 +125,8 @@ type HwEngine2d struct {
 	dispcap struct {
 		Enabled        bool
 		Mode           int
+		Src3D          bool
+		SrcFifo        bool
 		WBank          int
 		WOffset        uint32
 		RBank          int
@@ -101,14 +149,53 @@ type HwEngine2d struct {
 	objPal    []byte
 	bgExtPals [4][]byte
 	objExtPal []byte
+	bgWinBits [4]uint32
+	bgPris    [4]uint32
+
+	// Main memory display FIFO: pixels of the current line, written by DMA
+	// through DISPMMEMFIFO
+	mmfifo    [cScreenWidth]uint16
+	mmfifoLen int
+
+	// Frame dump (see DumpNextFrame): pending requests, and the dump of
+	// the current frame
+	dumpCh   chan dumpReq
+	dump     *FrameDump
+	dumpDone func(*FrameDump)
+	dump3D   bool // the 3D layer was displayed in the dumped frame
+}
+
+// Engine3D gives access to the output of the 3D engine, which is displayed
+// by engine A as BG0 (see DrawBG3D), and can be captured by its display
+// capture unit.
+type Engine3D interface {
+	// Line3D returns the 3D output of the specified line: pixels are
+	// 32-bit, with the RGB555 color in bits 0-14, the alpha in bits
+	// 16-20 and bit 31 set if the pixel was drawn.
+	Line3D(y int) gfx.Line
+
+	// DrawLines renders the 3D output starting from line y, for the lines
+	// requested through ctx (see gfx.Layer), and calls out to copy each
+	// line (in the Line3D format) into the layer buffer.
+	DrawLines(ctx *gfx.LayerCtx, y int, out func(y int, dst gfx.Line))
 }
 
-func NewHwEngine2d(idx int, mc MemoryController, l3d gfx.Layer) *HwEngine2d {
+// DISPCNT bits that exist only on engine A: BG0 2D/3D selection (bit 3),
+// display modes 2-3 (bit 17), VRAM display block (bits 18-19), bitmap OBJ
+// 1D boundary (bit 22), and char/screen base offsets (bits 24-29). On engine
+// B, they're read-only and always zero.
+const dispcntOnlyA = 0x3F4E0008
+
+func NewHwEngine2d(idx int, mc MemoryController, e3d Engine3D) *HwEngine2d {
 	e2d := new(HwEngine2d)
 	hwio.MustInitRegs(e2d)
 	e2d.Idx = idx
+	if e2d.B() {
+		e2d.DispCnt.RoMask |= dispcntOnlyA
+	}
 	e2d.mc = mc
-	e2d.l3d = l3d
+	e2d.e3d = e3d
+	e2d.dumpCh = make(chan dumpReq, 1)
 	e2d.masterBrightChanged = true // force initial table calculation
 
 	// Initialize bgregs data structure which is easier to index
@@ -175,20 +262,62 @@ func NewHwEngine2d(idx int, mc MemoryController, l3d gfx.Layer) *HwEngine2d {
 	}
 
 	// Background layers
-	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBG})
-	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBG})
-	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBG})
-	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBG})
+	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBGText})
+	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBGText})
+	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBGText})
+	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawBGText})
 
 	// Sprites layer
 	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawOBJ})
+
+	// Window layer (not visible, used by the mixer to mask the other layers)
+	e2d.lm.AddLayer(gfx.LayerFunc{Func: e2d.DrawWindow})
 	return e2d
 }
 
+// Let the two engines share the work of mixing their lines, so that the
+// goroutine of an engine that is done with its line helps the other one
+// (see gfx.MixGroup).
+func ShareMixing(a, b *HwEngine2d) {
+	gfx.NewMixGroup(&a.lm, &b.lm)
+}
+
 func (e2d *HwEngine2d) A() bool    { return e2d.Idx == 0 }
 func (e2d *HwEngine2d) B() bool    { return e2d.Idx != 0 }
 func (e2d *HwEngine2d) Name() byte { return 'A' + byte(e2d.Idx) }
 
+// Layers that can be hidden for debugging purposes (see SetDebugLayerMask)
+const (
+	DebugLayerBG0 = 1 << iota
+	DebugLayerBG1
+	DebugLayerBG2
+	DebugLayerBG3
+	DebugLayerOBJ
+
+	DebugLayerBGs = DebugLayerBG0 | DebugLayerBG1 | DebugLayerBG2 | DebugLayerBG3
+	DebugLayerAll = DebugLayerBGs | DebugLayerOBJ
+)
+
+// SetDebugLayerMask selects the layers that are displayed (by default, all
+// of them). Layers whose bit is clear are not drawn, whatever the value of
+// DISPCNT; this is a debugging aid (eg: to isolate a layer in screenshots),
+// and it can be changed at any time, from any goroutine. Sprites in OBJ
+// window mode are not affected, so that windows still work when sprites are
+// hidden.
+func (e2d *HwEngine2d) SetDebugLayerMask(mask uint32) {
+	atomic.StoreUint32(&e2d.dbgHidden, ^mask&DebugLayerAll)
+}
+
+// DebugLayerMask returns the layers that are displayed (see
+// SetDebugLayerMask).
+func (e2d *HwEngine2d) DebugLayerMask() uint32 {
+	return ^atomic.LoadUint32(&e2d.dbgHidden) & DebugLayerAll
+}
+
+func (e2d *HwEngine2d) debugHidden(layer uint32) bool {
+	return atomic.LoadUint32(&e2d.dbgHidden)&layer != 0
+}
+
 func (e2d *HwEngine2d) WriteDISPCNT(old, val uint32) {
 	modLcd.WithFields(log.Fields{
 		"name": string('A' + e2d.Idx),
@@ -196,12 +325,44 @@ func (e2d *HwEngine2d) WriteDISPCNT(old, val uint32) {
 	}).Info("write dispcnt")
 }
 
+// Each write to DISPMMEMFIFO pushes two pixels into the main memory display
+// FIFO; pixels written after the line is complete are dropped.
 func (e2d *HwEngine2d) WriteDISPMMEMFIFO(old, val uint32) {
-	if val != 0 {
-		modLcd.Fatalf("unimplemented DISP MMEM FIFO")
+	if e2d.mmfifoLen < cScreenWidth {
+		e2d.mmfifo[e2d.mmfifoLen] = uint16(val)
+		e2d.mmfifo[e2d.mmfifoLen+1] = uint16(val >> 16)
+		e2d.mmfifoLen += 2
 	}
 }
 
+// MainMemFifoWords returns the number of 32-bit words that must still be
+// written into DISPMMEMFIFO for the next line to be complete. It is zero if
+// the FIFO is not in use (that is, the display is not in main memory display
+// mode, and it's not a source of display capture).
+//
+// It is called before BeginLine, so DISPCNT is latched here: the FIFO must be
+// sized for the display mode of the line about to begin, not the previous one.
+func (e2d *HwEngine2d) MainMemFifoWords() int {
+	e2d.dispcnt = e2d.DispCnt.Value
+	if e2d.B() || (e2d.lineDispMode() != 3 && !(e2d.dispcap.Enabled && e2d.dispcap.SrcFifo)) {
+		return 0
+	}
+	return (cScreenWidth - e2d.mmfifoLen) / 2
+}
+
+// The reference point of affine layers is latched into internal registers
+// at the start of each frame, and then incremented after each line. Writing
+// it mid-frame also reloads the internal register, and the new value is
+// used starting from the next line.
+func (e2d *HwEngine2d) WriteBG2PX(old, val uint32) { e2d.bgregs[2].reloadX = true }
+func (e2d *HwEngine2d) WriteBG2PY(old, val uint32) { e2d.bgregs[2].reloadY = true }
+func (e2d *HwEngine2d) WriteBG3PX(old, val uint32) { e2d.bgregs[3].reloadX = true }
+func (e2d *HwEngine2d) WriteBG3PY(old, val uint32) { e2d.bgregs[3].reloadY = true }
+
+// MASTER_BRIGHT: bits 0-4 are the factor (/16, clamped to 16), bits 14-15
+// the mode (1: up, towards white; 2: down, towards black). It applies to
+// the final output of the engine, and is used by most games for fades. The
+// lookup tables are rebuilt at the start of the next line.
 func (e2d *HwEngine2d) WriteMBRIGHT(old, val uint32) {
 	if old != val {
 		e2d.masterBrightChanged = true
@@ -20,81 +20,73 @@ const (
 	BgModeAffineBitmapDirect
 	BgModeLargeBitmap
 	BgMode3D
+	BgModeNone // layer not available in the current BG mode
 )
 
-func (e2d *HwEngine2d) Mode1_BeginFrame() {
-	// Set the 4 BG layer priorities
-	for i := 0; i < 4; i++ {
-		pri := uint(e2d.bgregs[i].priority())
-		e2d.lm.SetLayerPriority(i, pri)
+// Layers of each BG mode (DISPCNT bits 0-2). Extended layers (bgModeExt)
+// are further decoded through BGxCNT (see bgLayerModes).
+const bgModeExt = BgMode(-1)
+
+var bgModeLayers = [8][4]BgMode{
+	{BgModeText, BgModeText, BgModeText, BgModeText},
+	{BgModeText, BgModeText, BgModeText, BgModeAffine},
+	{BgModeText, BgModeText, BgModeAffine, BgModeAffine},
+	{BgModeText, BgModeText, BgModeText, bgModeExt},
+	{BgModeText, BgModeText, BgModeAffine, bgModeExt},
+	{BgModeText, BgModeText, bgModeExt, bgModeExt},
+	{BgMode3D, BgModeNone, BgModeLargeBitmap, BgModeNone},
+	{BgModeNone, BgModeNone, BgModeNone, BgModeNone},
+}
+
+// bgLayerModes decodes the mode of the four BG layers, given the value of
+// DISPCNT and of the four BGxCNT registers. It returns false if the BG mode
+// is invalid for the engine (mode 7 on both engines, and mode 6 on engine
+// B): in that case, all layers are disabled.
+func bgLayerModes(dispcnt uint32, engineA bool, cnt [4]uint16) (modes [4]BgMode, ok bool) {
+	bgmode := dispcnt & 7
+	if bgmode == 7 || (bgmode == 6 && !engineA) {
+		return bgModeLayers[7], false
 	}
-	e2d.lm.SetLayerPriority(4, 100) // put sprites always last in the mixer
 
-	bgmode := e2d.DispCnt.Value & 7
-	bg3d := (e2d.DispCnt.Value>>3)&1 != 0
+	modes = bgModeLayers[bgmode]
 
-	// Switch bg0 between text and 3d layer, depending on setting in DISPCNT
-	// Notice also that in bgmode 6, layer 0 is always 3d
-	if e2d.A() {
-		if bg3d || bgmode == 6 {
-			e2d.Mode1_setBgMode(0, BgMode3D)
-		} else {
-			e2d.Mode1_setBgMode(0, BgModeText)
-		}
+	// BG0 can be switched to the 3D layer (engine A only)
+	if bg3d := (dispcnt>>3)&1 != 0; engineA && bg3d {
+		modes[0] = BgMode3D
 	}
 
-	// Bg1 is always text
-	e2d.Mode1_setBgMode(1, BgModeText)
-
-	// Compute extended mode (only for bg2/bg3)
-	var ext [4]BgMode
-	for idx := 2; idx < 4; idx++ {
-		if *e2d.bgregs[idx].Cnt&(1<<7) == 0 {
-			ext[idx] = BgModeAffineMap16
-		} else if *e2d.bgregs[idx].Cnt&(1<<2) == 0 {
-			ext[idx] = BgModeAffineBitmap
+	// Extended layers are either affine with 16-bit map entries, or 256-color
+	// bitmaps, or direct color bitmaps.
+	for i, mode := range modes {
+		if mode != bgModeExt {
+			continue
+		}
+		if cnt[i]&(1<<7) == 0 {
+			modes[i] = BgModeAffineMap16
+		} else if cnt[i]&(1<<2) == 0 {
+			modes[i] = BgModeAffineBitmap
 		} else {
-			ext[idx] = BgModeAffineBitmapDirect
+			modes[i] = BgModeAffineBitmapDirect
 		}
 	}
+	return modes, true
+}
 
-	// Change bg2/bg3 depending on mode
-	switch bgmode {
-	case 0:
-		e2d.Mode1_setBgMode(2, BgModeText)
-		e2d.Mode1_setBgMode(3, BgModeText)
-	case 1:
-		e2d.Mode1_setBgMode(2, BgModeText)
-		e2d.Mode1_setBgMode(3, BgModeAffine)
-	case 2:
-		e2d.Mode1_setBgMode(2, BgModeAffine)
-		e2d.Mode1_setBgMode(3, BgModeAffine)
-	case 3:
-		e2d.Mode1_setBgMode(2, BgModeText)
-		e2d.Mode1_setBgMode(3, ext[3])
-	case 4:
-		e2d.Mode1_setBgMode(2, BgModeAffine)
-		e2d.Mode1_setBgMode(3, ext[3])
-	case 5:
-		e2d.Mode1_setBgMode(2, ext[2])
-		e2d.Mode1_setBgMode(3, ext[3])
-	case 6:
-		if e2d.A() {
-			// Large bitmap mode only supported on engine A
-			e2d.Mode1_setBgMode(2, BgModeLargeBitmap)
-		}
-	}
+func (e2d *HwEngine2d) Mode1_BeginFrame() {
+	e2d.lm.SetLayerPriority(4, 100) // put sprites always last in the mixer
+	e2d.lm.SetLayerPriority(5, 101) // ...followed by the window layer
+	e2d.Mode1_latchLayers()
 	e2d.lm.BeginFrame()
 
-	bg0on := (e2d.DispCnt.Value >> 8) & 1
-	bg1on := (e2d.DispCnt.Value >> 9) & 1
-	bg2on := (e2d.DispCnt.Value >> 10) & 1
-	bg3on := (e2d.DispCnt.Value >> 11) & 1
+	bg0on := (e2d.dispcnt >> 8) & 1
+	bg1on := (e2d.dispcnt >> 9) & 1
+	bg2on := (e2d.dispcnt >> 10) & 1
+	bg3on := (e2d.dispcnt >> 11) & 1
 
-	objon := (e2d.DispCnt.Value >> 12) & 1
-	win0on := (e2d.DispCnt.Value >> 13) & 1
-	win1on := (e2d.DispCnt.Value >> 14) & 1
-	objwinon := (e2d.DispCnt.Value >> 15) & 1
+	objon := (e2d.dispcnt >> 12) & 1
+	win0on := (e2d.dispcnt >> 13) & 1
+	win1on := (e2d.dispcnt >> 14) & 1
+	objwinon := (e2d.dispcnt >> 15) & 1
 
 	modLcd.Infof("%s: modes=%v bg=[%d,%d,%d,%d] obj=%d win=[%d,%d,%d]",
 		string('A'+e2d.Idx), e2d.bgmodes, bg0on, bg1on, bg2on, bg3on, objon, win0on, win1on, objwinon)
@@ -107,11 +99,37 @@ func (e2d *HwEngine2d) Mode1_BeginFrame() {
 	// 	e2d.Bg0Cnt.Value>>14, e2d.Bg3Cnt.Value>>13)
 }
 
+// Decode the mode and the priority of the BG layers for the current line.
+// BG modes can be changed mid-frame (eg: to switch a layer between text
+// and bitmap at a given line): the layer is then restarted with the new
+// draw routine, from the current line.
+func (e2d *HwEngine2d) Mode1_latchLayers() {
+	for i := 0; i < 4; i++ {
+		pri := uint(e2d.bgregs[i].priority())
+		e2d.lm.SetLayerPriority(i, pri)
+	}
+
+	var cnt [4]uint16
+	for i := range cnt {
+		cnt[i] = *e2d.bgregs[i].Cnt
+	}
+	modes, ok := bgLayerModes(e2d.dispcnt, e2d.A(), cnt)
+	if !ok && !e2d.badBgMode {
+		modLcd.Warnf("%s: invalid BG mode %d, all BG layers disabled", string(rune('A'+e2d.Idx)), e2d.dispcnt&7)
+	}
+	e2d.badBgMode = !ok
+	for i, mode := range modes {
+		e2d.Mode1_setBgMode(i, mode)
+	}
+}
+
 func (e2d *HwEngine2d) Mode1_EndFrame() {
 	e2d.lm.EndFrame()
 }
 
 func (e2d *HwEngine2d) Mode1_BeginLine(y int, screen gfx.Line) {
+	e2d.Mode1_latchLayers()
+
 	pram := e2d.mc.VramPalette(e2d.Idx)
 	e2d.bgPal = pram[:512]
 	e2d.objPal = pram[512:]
@@ -146,11 +164,19 @@ func (e2d *HwEngine2d) Mode1_BeginLine(y int, screen gfx.Line) {
 		}
 
 		e2d.bgExtPals[i] = bgextpal.FetchPointer(8 * 1024 * slotnum)
+
+		// Bit of the layer in the window masks, and priority of the layer,
+		// again in priority order
+		e2d.bgWinBits[i] = 1 << uint(lidx)
+		e2d.bgPris[i] = uint32(e2d.bgregs[lidx].priority())
 	}
 
 	objextpal := e2d.mc.VramLinearBank(e2d.Idx, VramLinearOBJExtPal, 0)
 	e2d.objExtPal = objextpal.FetchPointer(0)
 
+	// Lines are counted by the LCD controller, which clocks both engines
+	// (and the 3D layer) at once: make sure we draw the line it asks for.
+	e2d.lm.SyncLine(y)
 	e2d.lm.BeginLine(screen)
 }
 
@@ -166,12 +192,21 @@ func (e2d *HwEngine2d) Mode1_setBgMode(lidx int, mode BgMode) {
 	e2d.bgmodes[lidx] = mode
 	switch mode {
 	case BgModeText:
-		e2d.lm.ChangeLayer(lidx, gfx.LayerFunc{Func: e2d.DrawBG})
+		e2d.lm.ChangeLayer(lidx, gfx.LayerFunc{Func: e2d.DrawBGText})
 	case BgMode3D:
-		e2d.lm.ChangeLayer(lidx, e2d.l3d)
-	case BgModeAffineMap16, BgModeAffineBitmapDirect, BgModeAffineBitmap, BgModeAffine:
+		e2d.lm.ChangeLayer(lidx, gfx.LayerFunc{Func: e2d.DrawBG3D})
+	case BgModeAffineMap16, BgModeAffineBitmapDirect, BgModeAffineBitmap, BgModeAffine, BgModeLargeBitmap:
 		e2d.lm.ChangeLayer(lidx, gfx.LayerFunc{Func: e2d.DrawBGAffine})
+	case BgModeNone:
+		e2d.lm.ChangeLayer(lidx, gfx.LayerFunc{Func: e2d.drawBGNone})
 	default:
 		panic(fmt.Errorf("bgmode %v not implemented", mode))
 	}
 }
+
+// Layer function for BG layers that are not available in the current mode:
+// nothing is drawn.
+func (e2d *HwEngine2d) drawBGNone(ctx *gfx.LayerCtx, lidx int, y int) {
+	for !ctx.NextLine().IsNil() {
+	}
+}
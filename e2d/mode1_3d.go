@@ -0,0 +1,29 @@
+package e2d
+
+import "ndsemu/emu/gfx"
+
+// DrawBG3D is the layer function of BG0 when it displays the 3D output
+// (engine A only). The 3D engine renders the lines, and each of them is
+// copied into the layer buffer (if BG0 is enabled), scrolled horizontally
+// by BG0HOFS.
+func (e2d *HwEngine2d) DrawBG3D(ctx *gfx.LayerCtx, lidx int, y int) {
+	e2d.e3d.DrawLines(ctx, y, func(y int, dst gfx.Line) {
+		if e2d.dispcnt&(1<<8) == 0 || e2d.debugHidden(DebugLayerBG0) {
+			return
+		}
+		e2d.copy3DLine(e2d.e3d.Line3D(y), dst, e2d.bgregs[0].xofs)
+	})
+}
+
+// Copy a line of 3D output into the layer buffer, scrolled horizontally by
+// xofs. The scroll is a 9-bit signed value, and the 3D output doesn't wrap
+// around: pixels scrolled in from outside of it are transparent. The
+// vertical scroll is ignored.
+func (e2d *HwEngine2d) copy3DLine(src, dst gfx.Line, xofs int) {
+	xofs = (xofs&0x1FF ^ 0x100) - 0x100
+	for x := 0; x < cScreenWidth; x++ {
+		if sx := x + xofs; sx >= 0 && sx < cScreenWidth {
+			dst.Set32(x, src.Get32(sx))
+		}
+	}
+}
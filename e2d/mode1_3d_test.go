@@ -0,0 +1,33 @@
+package e2d
+
+import (
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestBG3DScroll(t *testing.T) {
+	e2d := NewHwEngine2d(0, newTestMemCtrl(), nil)
+	src := gfx.NewLine(make([]byte, cScreenWidth*4))
+	for x := 0; x < cScreenWidth; x++ {
+		src.Set32(x, 0x80000000|uint32(x))
+	}
+
+	for _, tc := range []struct {
+		xofs int
+		want [4]uint32 // pixels 0, 1, 254, 255
+	}{
+		{0, [4]uint32{0x80000000, 0x80000001, 0x800000FE, 0x800000FF}},
+		{2, [4]uint32{0x80000002, 0x80000003, 0, 0}},
+		{0x1FE, [4]uint32{0, 0, 0x800000FC, 0x800000FD}}, // -2
+		{0x100, [4]uint32{0, 0, 0, 0}},                   // -256
+		{0xFF, [4]uint32{0x800000FF, 0, 0, 0}},
+		{0x202, [4]uint32{0x80000002, 0x80000003, 0, 0}}, // upper bits ignored
+	} {
+		dst := gfx.NewLine(make([]byte, cScreenWidth*4))
+		e2d.copy3DLine(src, dst, tc.xofs)
+		got := [4]uint32{dst.Get32(0), dst.Get32(1), dst.Get32(254), dst.Get32(255)}
+		if got != tc.want {
+			t.Errorf("xofs=%03x: got %08x, want %08x", tc.xofs, got, tc.want)
+		}
+	}
+}
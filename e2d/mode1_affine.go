@@ -2,13 +2,18 @@ package e2d
 
 import (
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
 )
 
 var bmpSize = []struct{ w, h int }{
 	{128, 128}, {256, 256}, {512, 256}, {512, 512},
 }
 
+// Sizes of the large bitmap (BG mode 6), selected by bit 14 of BG2CNT. The
+// 256-color bitmap fills the whole 512K of BG VRAM.
+var largeBmpSize = []struct{ w, h int }{
+	{512, 1024}, {1024, 512},
+}
+
 func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 	regs := &e2d.bgregs[lidx]
 	bgmode := e2d.bgmodes[lidx]
@@ -26,30 +31,27 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 		mapBase = int((*regs.Cnt>>8)&0x1F) * 2 * 1024
 		charBase = int((*regs.Cnt>>2)&0xF) * 16 * 1024
 		if e2d.A() {
-			mapBase += int((e2d.DispCnt.Value>>27)&7) * 64 * 1024
-			charBase += int((e2d.DispCnt.Value>>24)&7) * 64 * 1024
+			mapBase += int((e2d.dispcnt>>27)&7) * 64 * 1024
+			charBase += int((e2d.dispcnt>>24)&7) * 64 * 1024
 		}
 	}
 
 	tmap := e2d.mc.VramLinearBank(e2d.Idx, VramLinearBG, mapBase)
 	chars := e2d.mc.VramLinearBank(e2d.Idx, VramLinearBG, charBase)
-	onmask := uint32(1 << uint(8+lidx))
-	startx := int32(*regs.PX<<4) >> 4
-	starty := int32(*regs.PY<<4) >> 4
-
-	if e2d.DispCnt.Value&onmask != 0 {
-		ch := string('A' + e2d.Idx)
-		dx := int32(int16(*regs.PA))
-		dy := int32(int16(*regs.PC))
-		dmx := int32(int16(*regs.PB))
-		dmy := int32(int16(*regs.PD))
-		modLcd.Infof("%s%d: %v pos=(%x,%x), dx=(%x,%x), dy=(%x,%x) map=%x", ch, lidx, bgmode,
-			startx, starty, dx, dy, dmx, dmy, mapBase)
-	}
 
-	if y != 0 {
-		panic("unimplemented initial line not zero on affine plane")
+	// Linear banks are 256K big, so the large bitmap needs two of them
+	var lbmp [2]VramLinearBank
+	if bgmode == BgModeLargeBitmap {
+		lbmp[0] = tmap
+		lbmp[1] = e2d.mc.VramLinearBank(e2d.Idx, VramLinearBG, 256*1024)
 	}
+	onmask := uint32(1 << uint(8+lidx))
+
+	// Reference point of the first line of the current vertical mosaic
+	// block, which is repeated for the whole block (or of the first line
+	// drawn, if the layer is started mid-block)
+	var mosx, mosy int32
+	first := true
 
 	for {
 		line := ctx.NextLine()
@@ -57,26 +59,34 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 			return
 		}
 
-		if e2d.DispCnt.Value&onmask == 0 || gKeyState[hw.SCANCODE_1+lidx] != 0 {
-			y++
-			continue
+		// Registers are latched by the engine at the start of each line
+		if y == 0 && e2d.dispcnt&onmask != 0 {
+			modLcd.Infof("%s%d: %v pos=(%x,%x), dx=(%x,%x), dy=(%x,%x) map=%x",
+				string(rune('A'+e2d.Idx)), lidx, bgmode, regs.refX, regs.refY,
+				regs.pa, regs.pc, regs.pb, regs.pd, mapBase)
+		}
+
+		mosaic := (*regs.Cnt>>6)&1 != 0
+		mosw, mosh := e2d.bgMosaic()
+		if !mosaic || y%mosh == 0 || first {
+			mosx, mosy = regs.refX, regs.refY
 		}
-		if (e2d.A() && gKeyState[hw.SCANCODE_9] != 0) || (e2d.B() && gKeyState[hw.SCANCODE_8] != 0) {
+		first = false
+
+		if e2d.dispcnt&onmask == 0 || e2d.debugHidden(DebugLayerBG0<<uint(lidx)) {
 			y++
 			continue
 		}
 
-		pri := uint32(regs.priority())
-
-		mapx := startx
-		mapy := starty
+		mapx := mosx
+		mapy := mosy
+		bgline := line
 
 		// Layers 0/1 always wrap
 		// Layers 2/3 wrap only if bit 13 is set in BGxCNT
 		wrap := lidx < 2 || ((*regs.Cnt>>13)&1 != 0)
 
-		dx := int32(int16(*regs.PA))
-		dy := int32(int16(*regs.PC))
+		dx, dy := regs.pa, regs.pc
 
 		switch bgmode {
 		case BgModeAffineBitmap:
@@ -87,27 +97,45 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 				py := int(mapy >> 8)
 				// Bitmap modes wraparound on NDS (not GBA)
 				if wrap || (px >= 0 && px < size.w && py >= 0 && py < size.h) {
+					px &= size.w - 1
+					py &= size.h - 1
 					// 8-bit bitmap layers don't use extended palettes, so
-					// create a layer pixel without ext pal number
-					// We only encode the priority bit
-					col := uint32(tmap.Get8(py*size.w + px))
-					col |= pri << 29
-					line.Set32(x, col)
+					// the layer pixel is just the color index (0 is
+					// transparent)
+					line.Set32(x, uint32(tmap.Get8(py*size.w+px)))
 				}
 
 				mapx += dx
 				mapy += dy
 			}
 
+		case BgModeLargeBitmap:
+			size := largeBmpSize[(*regs.Cnt>>14)&1]
+
+			for x := 0; x < cScreenWidth; x++ {
+				px := int(mapx >> 8)
+				py := int(mapy >> 8)
+				if wrap || (px >= 0 && px < size.w && py >= 0 && py < size.h) {
+					px &= size.w - 1
+					py &= size.h - 1
+					off := py*size.w + px
+					line.Set32(x, uint32(lbmp[off>>18].Get8(off&0x3FFFF)))
+				}
+				mapx += dx
+				mapy += dy
+			}
+
 		case BgModeAffineBitmapDirect:
 			size := bmpSize[((*regs.Cnt >> 14) & 3)]
-			attrs := 0x80000000 | pri<<29
+			attrs := uint32(0x80000000)
 
 			for x := 0; x < cScreenWidth; x++ {
 				px := int(mapx >> 8)
 				py := int(mapy >> 8)
 				// Bitmap modes wraparound on NDS (not GBA)
 				if wrap || (px >= 0 && px < size.w && py >= 0 && py < size.h) {
+					px &= size.w - 1
+					py &= size.h - 1
 					// In Direct Color Bitmaps, bit 15 is used as a transparency
 					// bit, so if not set, the pixel is not displayed.
 					col := uint32(tmap.Get16(py*size.w + px))
@@ -122,7 +150,7 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 		case BgModeAffineMap16:
 			// Check if we are in extended palette mode (more palettes available for
 			// 256-color tiles).
-			useExtPal := (e2d.DispCnt.Value & (1 << 30)) != 0
+			useExtPal := (e2d.dispcnt & (1 << 30)) != 0
 
 			size := 128 << ((*regs.Cnt >> 14) & 3)
 
@@ -154,7 +182,7 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 					// can have multiple palettes in extended palette mode.
 					// So we ignore the palette number if extended palette is disabled
 					// (it should be already zero, but better safe than sorry)
-					attrs := pri << 29
+					attrs := uint32(0)
 					if useExtPal {
 						attrs |= uint32(pal<<8) | (1 << 12)
 					}
@@ -195,7 +223,7 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 					tx = px & 7
 					p0 := chars.Get8(tnum*64 + ty*8 + tx)
 					if p0 != 0 {
-						line.Set32(0, uint32(p0)|(pri<<29))
+						line.Set32(0, uint32(p0))
 					}
 				}
 
@@ -206,11 +234,9 @@ func (e2d *HwEngine2d) DrawBGAffine(ctx *gfx.LayerCtx, lidx int, y int) {
 		default:
 			panic("unimplemented")
 		}
-
-		dmx := int32(int16(*regs.PB))
-		dmy := int32(int16(*regs.PD))
-		startx += dmx
-		starty += dmy
+		if mosaic {
+			mosaicLine(bgline, mosw)
+		}
 
 		y++
 	}
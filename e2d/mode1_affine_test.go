@@ -0,0 +1,81 @@
+package e2d
+
+import (
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestLargeBitmap(t *testing.T) {
+	for _, tc := range []struct {
+		cnt  uint16
+		x    int // x coordinate of the pixel at the left of the screen
+		want uint32
+	}{
+		{0 << 14, 0, 1},         // 512x1024
+		{1 << 14, 0, 1},         // 1024x512
+		{0<<14 | 1<<13, 600, 2}, // 512x1024, wraps to x=88
+		{1<<14 | 1<<13, 600, 3}, // 1024x512
+		{1 << 14, 1100, 0},      // outside, no wraparound
+	} {
+		mc := newTestMemCtrl()
+		mc.vram.Ptr[0][0] = 1
+		mc.vram.Ptr[0][88] = 2
+		mc.vram.Ptr[0][600] = 3
+
+		e2d := NewHwEngine2d(0, mc, nil)
+		e2d.DispCnt.Value = 6 | 1<<10
+		e2d.Bg2Cnt.Value = tc.cnt
+		e2d.Bg2PA.Value = 0x100
+		e2d.Bg2PD.Value = 0x100
+		e2d.Bg2PX.Value = uint32(tc.x << 8)
+		e2d.latchLineRegs()
+		e2d.latchBgRegs(0)
+		e2d.Mode1_setBgMode(2, BgModeLargeBitmap)
+
+		line := drawLayerLine(func(ctx *gfx.LayerCtx, lidx int, y int) {
+			e2d.DrawBGAffine(ctx, 2, y)
+		})
+		if pix := line[0]; pix != tc.want {
+			t.Errorf("cnt=%04x x=%d: got %d, want %d", tc.cnt, tc.x, pix, tc.want)
+		}
+	}
+}
+
+func TestAffineRefPoint(t *testing.T) {
+	e2d := NewHwEngine2d(0, newTestMemCtrl(), nil)
+	regs := &e2d.bgregs[2]
+
+	e2d.Bg2PX.Value = 0x0FFFFF00 // -1.0 (28-bit signed)
+	e2d.Bg2PY.Value = 0x200
+	e2d.Bg2PB.Value = 0x10
+	e2d.Bg2PD.Value = 0xFFF0 // -0x10
+	e2d.Bg2XOfs.Value = 3
+
+	check := func(y int, x, yy int32) {
+		t.Helper()
+		e2d.latchBgRegs(y)
+		if regs.refX != x || regs.refY != yy {
+			t.Errorf("line %d: ref=(%x,%x), want (%x,%x)", y, regs.refX, regs.refY, x, yy)
+		}
+	}
+
+	// Loaded at the start of the frame, incremented after each line
+	check(0, -0x100, 0x200)
+	check(1, -0xF0, 0x1F0)
+	check(2, -0xE0, 0x1E0)
+
+	// Writing the reference point mid-frame reloads it for the next line,
+	// while changes to PB only affect the following increments
+	e2d.Bg2PX.Value = 0x1000
+	e2d.WriteBG2PX(0, 0x1000)
+	e2d.Bg2PB.Value = 0x20
+	check(3, 0x1000, 0x1D0)
+	check(4, 0x1020, 0x1C0)
+
+	// Scrolling registers are latched as well, for every layer
+	e2d.Bg0XOfs.Value = 7
+	e2d.latchBgRegs(5)
+	if e2d.bgregs[0].xofs != 7 || e2d.bgregs[2].xofs != 3 {
+		t.Errorf("invalid latched scrolling: %d %d", e2d.bgregs[0].xofs, e2d.bgregs[2].xofs)
+	}
+}
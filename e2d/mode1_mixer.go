@@ -3,108 +3,159 @@ package e2d
 import "ndsemu/emu"
 
 // A pixel in a layer of the layer manager. It is composed as follows:
-//   Bits 0-11: color index in the palette
-//   Bit 12: set if the pixel uses the extended palette for its layer (either obj or bg)
-//   Bit 29-30: priority
-//   Bit 31: direct color
+//
+//	Bits 0-11: color index in the palette
+//	Bit 12: set if the pixel uses the extended palette for its layer (either obj or bg)
+//	Bits 16-20: alpha (direct color pixels of the 3D layer and bitmap sprites)
+//	Bit 27: set if the pixel is semi-transparent (obj layer only)
+//	Bit 28: set if the pixel is within the OBJ window (obj layer only)
+//	Bit 29-30: priority (obj layer only)
+//	Bit 31: direct color
+//
+// BG layers (including the 3D layer) have a single priority, set in BGxCNT,
+// so it's not stored in their pixels: the mixer takes it from bgPris.
 type LayerPixel uint32
 
-func (p LayerPixel) ColorIndex() uint16  { return uint16(p & 0xFFF) }
-func (p LayerPixel) ExtPal() bool        { return (p & (1 << 12)) != 0 }
-func (p LayerPixel) Priority() uint32    { return uint32(p>>29) & 3 }
-func (p LayerPixel) Direct() bool        { return int32(p) < 0 }
-func (p LayerPixel) DirectColor() uint16 { return uint16(p & 0x7FFF) }
-func (p LayerPixel) Transparent() bool   { return p == 0 }
+func (p LayerPixel) ColorIndex() uint16    { return uint16(p & 0xFFF) }
+func (p LayerPixel) ExtPal() bool          { return (p & (1 << 12)) != 0 }
+func (p LayerPixel) Priority() uint32      { return uint32(p>>29) & 3 }
+func (p LayerPixel) Direct() bool          { return int32(p) < 0 }
+func (p LayerPixel) DirectColor() uint16   { return uint16(p & 0x7FFF) }
+func (p LayerPixel) Alpha() uint32         { return uint32(p>>16) & 0x1F }
+func (p LayerPixel) SemiTransparent() bool { return (p & objBlendBit) != 0 }
+func (p LayerPixel) ObjWindow() bool       { return (p & objWindowBit) != 0 }
+func (p LayerPixel) Transparent() bool     { return p&^objWindowBit == 0 }
+
+const (
+	objBlendBit  = 1 << 27
+	objWindowBit = 1 << 28
+)
+
+// Targets of color special effects, in the format of BLDCNT (bits 0-3 are
+// the BG layers, as in bgWinBits)
+const (
+	bldObj      = 1 << 4
+	bldBackdrop = 1 << 5
+)
+
+func (e2d *HwEngine2d) bgColor(i int, pix LayerPixel) uint16 {
+	if pix.Direct() {
+		return pix.DirectColor()
+	}
+	cram := e2d.bgPal
+	if pix.ExtPal() {
+		cram = e2d.bgExtPals[i]
+	}
+	return emu.Read16LE(cram[pix.ColorIndex()*2:])
+}
+
+func (e2d *HwEngine2d) objColor(pix LayerPixel) uint16 {
+	if pix.Direct() {
+		return pix.DirectColor()
+	}
+	cram := e2d.objPal
+	if pix.ExtPal() {
+		cram = e2d.objExtPal
+	}
+	return emu.Read16LE(cram[pix.ColorIndex()*2:])
+}
 
 func e2dMixer_Normal(layers []uint32, ctx interface{}) (res uint32) {
-	var objpix, bgpix LayerPixel
-	var pix uint16
-	var cram []uint8
-	var c16 uint16
 	e2d := ctx.(*HwEngine2d)
 
-	// Extract the layers. They've been already sorted in priority order,
-	// so the first layer with a non-transparent pixel is the one that gets
-	// drawn.
-	bgpix = LayerPixel(layers[0])
-	if !bgpix.Transparent() {
-		if bgpix.ExtPal() {
-			cram = e2d.bgExtPals[0]
-		} else {
-			cram = e2d.bgPal
+	// Compute the layers enabled by the windows on this pixel. The OBJ
+	// window is marked in the obj layer, so we need that first.
+	objpix := LayerPixel(layers[4])
+	mask := WindowPixel(layers[5]).Mask(objpix.ObjWindow())
+	objon := mask&winObj != 0 && !objpix.Transparent()
+
+	// Find the two topmost pixels, which are the first and second target of
+	// color special effects. BG layers have been already sorted in priority
+	// order, and objects are drawn in front of BGs with the same priority.
+	// If there are not enough pixels, the backdrop is used.
+	var pix [2]LayerPixel
+	var col [2]uint16
+	var id [2]uint32
+	n := 0
+	for i := 0; i < 4 && n < 2; i++ {
+		bgpix := LayerPixel(layers[i])
+		if mask&e2d.bgWinBits[i] == 0 || bgpix.Transparent() {
+			continue
 		}
-		goto checkobj
-	}
-	bgpix = LayerPixel(layers[1])
-	if !bgpix.Transparent() {
-		if bgpix.ExtPal() {
-			cram = e2d.bgExtPals[1]
-		} else {
-			cram = e2d.bgPal
+		if objon && objpix.Priority() <= e2d.bgPris[i] {
+			pix[n], col[n], id[n] = objpix, e2d.objColor(objpix), bldObj
+			objon = false
+			if n++; n == 2 {
+				break
+			}
 		}
-		goto checkobj
+		pix[n], col[n], id[n] = bgpix, e2d.bgColor(i, bgpix), e2d.bgWinBits[i]
+		n++
+	}
+	if objon && n < 2 {
+		pix[n], col[n], id[n] = objpix, e2d.objColor(objpix), bldObj
+		n++
 	}
-	bgpix = LayerPixel(layers[2])
-	if !bgpix.Transparent() {
-		if bgpix.ExtPal() {
-			cram = e2d.bgExtPals[2]
+	if n < 2 {
+		col[n], id[n] = emu.Read16LE(e2d.bgPal), bldBackdrop
+	}
+
+	// Just return the 16-bit value, the post-processing function will take
+	// care of the last step
+	c := col[0]
+	if mask&winEffects == 0 {
+		return uint32(c)
+	}
+	second := id[1]&(e2d.bldcnt>>8) != 0
+
+	// Semi-transparent objects and translucent 3D pixels are blended with
+	// the second target regardless of the selected effect. If there is no
+	// second target below them, the normal effect is applied instead.
+	if second && id[0] == bldObj && pix[0].SemiTransparent() {
+		if pix[0].Direct() {
+			if a := pix[0].Alpha(); a < 31 {
+				return uint32(blend555(c, col[1], a+1, 31-a, 5))
+			}
 		} else {
-			cram = e2d.bgPal
+			return uint32(blend555(c, col[1], e2d.bldEva, e2d.bldEvb, 4))
 		}
-		goto checkobj
 	}
-	bgpix = LayerPixel(layers[3])
-	if !bgpix.Transparent() {
-		if bgpix.ExtPal() {
-			cram = e2d.bgExtPals[3]
-		} else {
-			cram = e2d.bgPal
+	if second && id[0] == 1 && e2d.bgmodes[0] == BgMode3D {
+		if a := pix[0].Alpha(); a < 31 {
+			return uint32(blend555(c, col[1], a+1, 31-a, 5))
 		}
-		goto checkobj
 	}
 
-	// No bglayer was drawn here, so see if there is at least an obj, in which
-	// case we draw it directly
-	objpix = LayerPixel(layers[4])
-	if !objpix.Transparent() {
-		goto drawobj
+	if id[0]&e2d.bldcnt == 0 {
+		return uint32(c)
 	}
-
-	// No objlayer, and no bglayer. Draw the backdrop.
-	// pix = 0
-	cram = e2d.bgPal
-	goto lookup
-
-checkobj:
-	// We found a bg pixel; now check if there is an object pixel here: if so,
-	// we need to check the priority to choose between bg and obj which pixel
-	// to draw (if the priorities are equal, objects win)
-	objpix = LayerPixel(layers[4])
-	if objpix.Transparent() || objpix.Priority() > bgpix.Priority() {
-		if bgpix.Direct() {
-			c16 = bgpix.DirectColor()
-			goto draw
+	switch (e2d.bldcnt >> 6) & 3 {
+	case 1:
+		if second {
+			c = blend555(c, col[1], e2d.bldEva, e2d.bldEvb, 4)
 		}
-		pix = bgpix.ColorIndex()
-		goto lookup
+	case 2:
+		c = blend555(c, 0x7FFF, 16-e2d.bldEvy, e2d.bldEvy, 4)
+	case 3:
+		c = blend555(c, 0, 16-e2d.bldEvy, e2d.bldEvy, 4)
 	}
+	return uint32(c)
+}
 
-drawobj:
-	if objpix.Direct() {
-		c16 = objpix.DirectColor()
-		goto draw
+// Blend two RGB555 colors with the specified coefficients, which are
+// fractions of 1<<shift. Each component is saturated.
+func blend555(c1, c2 uint16, eva, evb uint32, shift uint) uint16 {
+	r := (uint32(c1&0x1F)*eva + uint32(c2&0x1F)*evb) >> shift
+	g := (uint32((c1>>5)&0x1F)*eva + uint32((c2>>5)&0x1F)*evb) >> shift
+	b := (uint32((c1>>10)&0x1F)*eva + uint32((c2>>10)&0x1F)*evb) >> shift
+	if r > 31 {
+		r = 31
 	}
-	pix = objpix.ColorIndex()
-	if objpix.ExtPal() {
-		cram = e2d.objExtPal
-	} else {
-		cram = e2d.objPal
+	if g > 31 {
+		g = 31
 	}
-
-lookup:
-	c16 = emu.Read16LE(cram[pix*2:])
-draw:
-	// Just return the 16-bit value for now, the post-processing
-	// function will take care of the last step
-	return uint32(c16)
+	if b > 31 {
+		b = 31
+	}
+	return uint16(r | g<<5 | b<<10)
 }
@@ -0,0 +1,108 @@
+package e2d
+
+import (
+	"ndsemu/emu"
+	"testing"
+)
+
+func TestColorEffects(t *testing.T) {
+	e2d := &HwEngine2d{bgPal: make([]byte, 512), objPal: make([]byte, 512)}
+	for i := range e2d.bgWinBits {
+		e2d.bgWinBits[i] = 1 << uint(i)
+	}
+	emu.Write16LE(e2d.bgPal[0:], 0x0000)  // backdrop: black
+	emu.Write16LE(e2d.bgPal[2:], 0x001F)  // BG0: red
+	emu.Write16LE(e2d.bgPal[4:], 0x7C00)  // BG1: blue
+	emu.Write16LE(e2d.objPal[2:], 0x03E0) // OBJ: green
+
+	bg0, bg1, obj := uint32(1), uint32(2), uint32(1)
+	for _, tc := range []struct {
+		name   string
+		bldcnt uint16
+		layers []uint32
+		res    uint16
+	}{
+		{"none", 0x0101, []uint32{bg0, bg1, 0, 0, 0, winAll}, 0x001F},
+		{"alpha", 0x0241, []uint32{bg0, bg1, 0, 0, 0, winAll}, 0x3C0F},
+		{"alpha, not 2nd target", 0x0441, []uint32{bg0, bg1, 0, 0, 0, winAll}, 0x001F},
+		{"alpha, not 1st target", 0x0242, []uint32{bg0, bg1, 0, 0, 0, winAll}, 0x001F},
+		{"alpha, effects disabled", 0x0241, []uint32{bg0, bg1, 0, 0, 0, winAll &^ winEffects}, 0x001F},
+		{"brighten", 0x0081, []uint32{bg0, bg1, 0, 0, 0, winAll}, 0x3DFF},
+		{"darken", 0x00C1, []uint32{bg0, bg1, 0, 0, 0, winAll}, 0x000F},
+		{"backdrop", 0x00A0, []uint32{0, 0, 0, 0, 0, winAll}, 0x3DEF},
+		{"semi-transparent obj", 0x0100, []uint32{bg0, bg1, 0, 0, obj | objBlendBit, winAll}, 0x01EF},
+		{"semi-transparent obj, no 2nd target", 0x00D0, []uint32{bg0, bg1, 0, 0, obj | objBlendBit, winAll}, 0x01E0},
+		{"bitmap obj", 0x0100, []uint32{bg0, bg1, 0, 0, 0x80007C00 | 15<<16 | objBlendBit, winAll}, 0x3C0F},
+	} {
+		e2d.BldCnt.Value = tc.bldcnt
+		e2d.BldAlpha.Value = 8<<8 | 8
+		e2d.BldY.Value = 8
+		e2d.latchLineRegs()
+		if res := uint16(e2dMixer_Normal(tc.layers, e2d)); res != tc.res {
+			t.Errorf("%s: got %04x, want %04x", tc.name, res, tc.res)
+		}
+	}
+
+	// Translucent 3D pixels are blended with the second target using their
+	// own alpha, even if no effect is selected
+	e2d.bgmodes[0] = BgMode3D
+	e2d.BldCnt.Value = 0x0200
+	e2d.latchLineRegs()
+	pix3d := uint32(0x8000001F | 15<<16)
+	if res := e2dMixer_Normal([]uint32{pix3d, bg1, 0, 0, 0, winAll}, e2d); res != 0x3C0F {
+		t.Errorf("3D: got %04x", res)
+	}
+	if res := e2dMixer_Normal([]uint32{pix3d | 31<<16, bg1, 0, 0, 0, winAll}, e2d); res != 0x001F {
+		t.Errorf("opaque 3D: got %04x", res)
+	}
+}
+
+func TestLayerPriority(t *testing.T) {
+	e2d := &HwEngine2d{bgPal: make([]byte, 512), objPal: make([]byte, 512)}
+	for i := range e2d.bgWinBits {
+		e2d.bgWinBits[i] = 1 << uint(i)
+	}
+	emu.Write16LE(e2d.bgPal[2:], 0x001F)  // BG: red
+	emu.Write16LE(e2d.objPal[2:], 0x03E0) // OBJ: green
+
+	// The 3D layer has no priority bits in its pixels: it's the one of BG0
+	pix3d := uint32(0x8000001F | 31<<16)
+	obj := uint32(1) | 1<<29 // priority 1
+	for _, tc := range []struct {
+		bgpri uint32
+		layer []uint32
+		res   uint16
+	}{
+		{0, []uint32{pix3d, 0, 0, 0, obj, winAll}, 0x001F},
+		{1, []uint32{pix3d, 0, 0, 0, obj, winAll}, 0x03E0}, // OBJ wins ties
+		{3, []uint32{pix3d, 0, 0, 0, obj, winAll}, 0x03E0},
+		{0, []uint32{1, 0, 0, 0, obj, winAll}, 0x001F},
+		{2, []uint32{1, 0, 0, 0, obj, winAll}, 0x03E0},
+	} {
+		e2d.bgPris[0] = tc.bgpri
+		if res := e2dMixer_Normal(tc.layer, e2d); res != uint32(tc.res) {
+			t.Errorf("bg pri=%d, layer %08x: got %04x, want %04x", tc.bgpri, tc.layer[0], res, tc.res)
+		}
+	}
+}
+
+func TestExtPalettes(t *testing.T) {
+	e2d := &HwEngine2d{bgPal: make([]byte, 512), objPal: make([]byte, 512)}
+	for i := range e2d.bgWinBits {
+		e2d.bgWinBits[i] = 1 << uint(i)
+		e2d.bgExtPals[i] = make([]byte, 8*1024)
+	}
+	e2d.objExtPal = make([]byte, 8*1024)
+	emu.Write16LE(e2d.bgExtPals[1][(3*256+5)*2:], 0x1234) // slot of the 2nd layer, palette 3
+	emu.Write16LE(e2d.objExtPal[(15*256+255)*2:], 0x4321) // palette 15
+
+	extpal := uint32(1 << 12)
+	bg := 3<<8 | 5 | extpal
+	obj := 15<<8 | 255 | extpal
+	if res := e2dMixer_Normal([]uint32{0, bg, 0, 0, 0, winAll}, e2d); res != 0x1234 {
+		t.Errorf("BG: got %04x", res)
+	}
+	if res := e2dMixer_Normal([]uint32{0, bg, 0, 0, obj, winAll}, e2d); res != 0x4321 {
+		t.Errorf("OBJ: got %04x", res)
+	}
+}
@@ -0,0 +1,54 @@
+package e2d
+
+import "ndsemu/emu/gfx"
+
+// Size of the mosaic blocks for BG layers, as set in the MOSAIC register
+// (latched at the start of each line). Mosaic is enabled on a layer through
+// bit 6 of BGxCNT.
+func (e2d *HwEngine2d) bgMosaic() (w, h int) {
+	return int(e2d.mosaic&0xF) + 1, int((e2d.mosaic>>4)&0xF) + 1
+}
+
+// Size of the mosaic blocks for sprites. Mosaic is enabled on a sprite
+// through bit 12 of OBJ attribute 0.
+func (e2d *HwEngine2d) objMosaic() (w, h int) {
+	return int((e2d.mosaic>>8)&0xF) + 1, int((e2d.mosaic>>12)&0xF) + 1
+}
+
+// Apply the horizontal mosaic to a line: blocks of w pixels (starting from
+// the left border of the screen) are filled with their leftmost pixel.
+func mosaicLine(line gfx.Line, w int) {
+	if w <= 1 {
+		return
+	}
+	for x := 0; x < cScreenWidth; x += w {
+		pix := line.Get32(x)
+		for i := 1; i < w && x+i < cScreenWidth; i++ {
+			line.Set32(x+i, pix)
+		}
+	}
+}
+
+// Copy the pixels of a mosaic sprite (drawn alone in src) into dst, within
+// [x0,x1). Each pixel is taken from the left border of its mosaic block,
+// which can fall outside of the sprite; in that case, the pixel is
+// transparent.
+func mosaicObj(dst, src gfx.Line, x0, x1 int, w int, winmode bool) {
+	if x0 < 0 {
+		x0 = 0
+	}
+	if x1 > cScreenWidth {
+		x1 = cScreenWidth
+	}
+	for x := x0; x < x1; x++ {
+		pix := src.Get32(x - x%w)
+		if pix == 0 {
+			continue
+		}
+		if winmode {
+			dst.Set32(x, dst.Get32(x)|objWindowBit)
+		} else {
+			dst.Set32(x, pix)
+		}
+	}
+}
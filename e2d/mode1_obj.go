@@ -28,53 +28,131 @@ var objWidth = []struct{ w, h int }{
 	{1, 2}, {1, 4}, {2, 4}, {4, 8},
 }
 
-func objBitmap_CalcAddress_2D128(tilenum int) int {
-	return int((tilenum&0xF)*0x10 + (tilenum & ^0xF)*0x80)
+// Number of cycles available to the OBJ renderer for each line. Rendering
+// a normal sprite costs one cycle per pixel of its width, while an affine
+// sprite costs two cycles per pixel of its (possibly doubled) width, plus 10.
+// Sprites are rendered pixel by pixel, so the sprite being rendered when the
+// budget runs out is cut off, and the following ones are not drawn on that
+// line. If DISPCNT bit 23 ("OBJ processing during H-Blank") is clear, the
+// renderer can't use the H-Blank period and the budget is smaller.
+const (
+	objLineCycles       = 2130
+	objLineCyclesNoHBlk = 1530
+)
+
+// objLineLimit returns the number of sprites (in OAM order) that can be
+// fully processed on line sy within the cycle budget; the remaining ones are
+// dropped, except for the first one, of which only the leftmost partial
+// pixels are drawn (partial can be zero).
+func objLineLimit(oam []byte, sy int, budget int) (n int, partial int) {
+	for i := 0; i < 128; i++ {
+		a0, a1 := emu.Read16LE(oam[i*8:]), emu.Read16LE(oam[i*8+2:])
+		mode := (a0 >> 8) & 3
+		if mode == objModeHidden {
+			continue
+		}
+
+		sz := objWidth[((a0>>14)<<2)|(a1>>14)]
+		w, h := sz.w*8, sz.h*8
+		if mode == objModeAffineDouble {
+			w, h = w*2, h*2
+		}
+		y := int(a0 & 0xFF)
+		if y >= cScreenHeight {
+			y -= 256
+		}
+		if sy < y || sy >= y+h {
+			continue
+		}
+
+		cost := w
+		if mode != objModeNormal {
+			cost = 2*w + 10
+		}
+		if cost > budget {
+			partial = budget
+			if mode != objModeNormal {
+				partial = (budget - 10) / 2
+			}
+			if partial < 0 {
+				partial = 0
+			}
+			return i, partial
+		}
+		budget -= cost
+	}
+	return 128, 0
 }
 
-func objBitmap_CalcAddress_2D256(tilenum int) int {
-	return int((tilenum&0x1F)*0x10 + (tilenum & ^0x1F)*0x80)
+// objMapping describes how the chars (or the pixels, for bitmap sprites) of
+// sprites are laid out in OBJ VRAM, as selected by DISPCNT:
+//
+//	bit 4       char mapping: 0=2D (grid 32 chars wide), 1=1D (linear)
+//	bits 5-6    bitmap mapping: 0=2D, 128 pixels wide; 1=2D, 256 pixels
+//	            wide; 2=1D (linear); 3=prohibited (treated as 1D)
+//	bits 20-21  1D char mapping: boundary of the char number (32 << n bytes)
+//	bit 22      1D bitmap mapping: boundary of the char number (128 or 256 bytes)
+//
+// With 2D mapping, the char number selects the char (or the 8x8 block of
+// pixels) at the top-left corner of the sprite within the grid; with 1D
+// mapping, the sprite is stored linearly starting at char number * boundary.
+type objMapping struct {
+	char1d       bool
+	charBoundary int // bytes
+	bmp1d        bool
+	bmpBoundary  int // bytes (1D)
+	bmpWidth     int // pixels (2D)
+}
+
+func newObjMapping(dispcnt uint32) objMapping {
+	m := objMapping{
+		char1d:       (dispcnt>>4)&1 != 0,
+		charBoundary: 32,
+		bmp1d:        (dispcnt>>6)&1 != 0,
+		bmpBoundary:  128,
+		bmpWidth:     128,
+	}
+	if m.char1d {
+		m.charBoundary <<= (dispcnt >> 20) & 3
+	}
+	if (dispcnt>>22)&1 != 0 {
+		m.bmpBoundary = 256
+	}
+	if (dispcnt>>5)&1 != 0 {
+		m.bmpWidth = 256
+	}
+	return m
 }
 
-func objBitmap_CalcAddress_1D128(tilenum int) int {
-	return int(tilenum) * 128
+// Offset within OBJ VRAM of the top-left char of a sprite, and pitch (in
+// chars) of a row of chars. tw is the width of the sprite in chars.
+func (m objMapping) charAddr(tilenum int, tw int, depth256 bool) (off int, pitch int) {
+	off = tilenum * m.charBoundary
+	if m.char1d {
+		return off, tw
+	}
+	// The grid is 32 chars wide, that is 16 chars in 256-color mode
+	if depth256 {
+		return off, 16
+	}
+	return off, 32
 }
 
-func objBitmap_CalcAddress_1D256(tilenum int) int {
-	return int(tilenum) * 256
+// Offset within OBJ VRAM of the top-left pixel of a bitmap sprite, and pitch
+// (in blocks of 8 pixels) of a row of pixels.
+func (m objMapping) bitmapAddr(tilenum int, tw int) (off int, pitch int) {
+	if m.bmp1d {
+		return tilenum * m.bmpBoundary, tw
+	}
+	// The char number selects a block of 8x8 pixels within the bitmap
+	bw := m.bmpWidth / 8
+	return (tilenum%bw)*8*2 + (tilenum/bw)*8*m.bmpWidth*2, bw
 }
 
 func (e2d *HwEngine2d) DrawOBJ(ctx *gfx.LayerCtx, lidx int, sy int) {
 	oam := e2d.mc.VramOAM(e2d.Idx)
 	tiles := e2d.mc.VramLinearBank(e2d.Idx, VramLinearOAM, 0)
 
-	mapping1d := (e2d.DispCnt.Value>>4)&1 != 0
-	boundary := 32
-	if mapping1d {
-		boundary <<= (e2d.DispCnt.Value >> 20) & 3
-	}
-
-	var vramBitmapCalcAddress func(int) int
-	var objPitch int
-	bitmapMapping1d := (e2d.DispCnt.Value>>6)&1 != 0
-	if !bitmapMapping1d {
-		// OBJ Bitmap 2D mapping
-		if (e2d.DispCnt.Value>>5)&1 == 0 {
-			vramBitmapCalcAddress = objBitmap_CalcAddress_2D128
-			objPitch = 16
-		} else {
-			vramBitmapCalcAddress = objBitmap_CalcAddress_2D256
-			objPitch = 32
-		}
-	} else {
-		// OBJ Bitmap 1D mapping
-		if (e2d.DispCnt.Value>>22)&1 == 0 {
-			vramBitmapCalcAddress = objBitmap_CalcAddress_1D128
-		} else {
-			vramBitmapCalcAddress = objBitmap_CalcAddress_1D256
-		}
-	}
-
 	if e2d.A() && false {
 		for i := 0; i < 128; i++ {
 			a0, a1, _ := emu.Read16LE(oam[i*8:]), emu.Read16LE(oam[i*8+2:]), emu.Read16LE(oam[i*8+4:])
@@ -108,238 +186,333 @@ func (e2d *HwEngine2d) DrawOBJ(ctx *gfx.LayerCtx, lidx int, sy int) {
 		}
 	}
 
+	// Mosaic sprites are drawn alone into a scratch line, and then copied
+	// into the layer applying the horizontal mosaic (or clipping)
+	mosline := gfx.NewLine(e2d.objMosBuf[:])
+	mosline.Add32(8)
+
 	for {
 		line := ctx.NextLine()
 		if line.IsNil() {
 			return
 		}
+		// The mapping is read from the DISPCNT latched for this line
+		mapping := newObjMapping(e2d.dispcnt)
 
 		// If sprites are globally disabled, nothing to do
-		if e2d.DispCnt.Value&(1<<12) == 0 {
+		if e2d.dispcnt&(1<<12) == 0 {
 			sy++
 			continue
 		}
 
-		useExtPal := (e2d.DispCnt.Value & (1 << 31)) != 0
+		useExtPal := (e2d.dispcnt & (1 << 31)) != 0
+		objHidden := e2d.debugHidden(DebugLayerOBJ)
+
+		// Sprites are processed in OAM order, so those that exceed the
+		// per-line cycle budget are the last ones.
+		budget := objLineCycles
+		if e2d.dispcnt&(1<<23) == 0 {
+			budget = objLineCyclesNoHBlk
+		}
+		nobjs, partial := objLineLimit(oam, sy, budget)
+		first := nobjs - 1
+		if partial > 0 {
+			first = nobjs
+		}
+		mosw, mosh := e2d.objMosaic()
 
 		// Go through the sprite list in reverse order, because an object with
 		// lower index has HIGHER priority (so it gets drawn in front of all).
-		// FIXME: This is actually a temporary hack because it will fail once we
-		// emulate the sprite line limits; the correct solution would be
-		// to go through in the correct order, but avoiding writing pixels
-		// that have been already written to.
-		for i := 127; i >= 0; i-- {
-			a0, a1, a2 := emu.Read16LE(oam[i*8:]), emu.Read16LE(oam[i*8+2:]), emu.Read16LE(oam[i*8+4:])
-
-			// Sprite mode: 0=normal, 1=affine, 2=hidden, 3=affine double
-			// We immediately skip hidden sprites, so from this point onward,
-			// affine!=0 means affine mode.
-			mode := (a0 >> 8) & 3
-			if mode == objModeHidden {
-				continue
-			}
-
-			// Sprite pixel mode: 0=normal, 1=semi-transparent, 2=window, 3=bitmap
-			pixmode := (a0 >> 10) & 3
-
-			const XMask = 0x1FF
-			const YMask = 0xFF
-
-			x := int(a1 & XMask)
-			y := int(a0 & YMask)
-			if x >= cScreenWidth {
-				x -= XMask + 1
-			}
-			if y >= cScreenHeight {
-				y -= YMask + 1
-			}
+		//
+		// Sprites in window mode are never drawn: they just mark the pixels
+		// that belong to the OBJ window. They're processed in a second pass,
+		// after all normal sprites, so that their mark can't be overwritten.
+		haswin := false
+		for pass := 0; pass < 2; pass++ {
+			for i := first; i >= 0; i-- {
+				a0, a1, a2 := emu.Read16LE(oam[i*8:]), emu.Read16LE(oam[i*8+2:]), emu.Read16LE(oam[i*8+4:])
+
+				// Sprite mode: 0=normal, 1=affine, 2=hidden, 3=affine double
+				// We immediately skip hidden sprites, so from this point onward,
+				// affine!=0 means affine mode.
+				mode := (a0 >> 8) & 3
+				if mode == objModeHidden {
+					continue
+				}
 
-			// Get the object size. The size is expressed in number of chars,
-			// not pixels.
-			sz := objWidth[((a0>>14)<<2)|(a1>>14)]
-			tw, th := sz.w, sz.h
-			tws, ths := tw, th
+				// Sprite pixel mode: 0=normal, 1=semi-transparent, 2=window, 3=bitmap
+				pixmode := (a0 >> 10) & 3
+				winmode := pixmode == objPixModeWindow
+				if winmode != (pass == 1) {
+					haswin = haswin || winmode
+					continue
+				}
+				if !winmode && objHidden {
+					continue
+				}
 
-			if mode == objModeAffineDouble {
-				tws *= 2
-				ths *= 2
-			}
+				const XMask = 0x1FF
+				const YMask = 0xFF
 
-			// If the sprite is visible
-			// FIXME: this doesn't handle wrapping yet
-			if sy >= y && sy < (y+ths*8) && (x < cScreenWidth && (x+tws*8) >= 0) {
-				tilenum := int(a2 & 1023)
-				depth256 := (a0>>13)&1 != 0
-				hflip := (a1>>12)&1 != 0 && mode == objModeNormal // hflip not available in affine mode
-				vflip := (a1>>13)&1 != 0 && mode == objModeNormal // vflip not available in affine mode
-				pri := (a2 >> 10) & 3
-				pal := (a2 >> 12) & 0xF
-
-				// Size of a char (in byte), depending on the color setting
-				charSize := 32
-				if depth256 {
-					charSize = 64
+				x := int(a1 & XMask)
+				y := int(a0 & YMask)
+				if x >= cScreenWidth {
+					x -= XMask + 1
 				}
-
-				// Compute the offset within VRAM of the current object (for
-				// now, its top-left pixel)
-				var vramOffset int
-				if pixmode == objPixModeBitmap {
-					vramOffset = vramBitmapCalcAddress(tilenum)
-				} else {
-					vramOffset = tilenum * boundary
+				if y >= cScreenHeight {
+					y -= YMask + 1
 				}
 
-				// Compute the line being drawn *within* the current object.
-				// This must also handle vertical flip (in which the whole
-				// object is flipped, not just the single chars)
-				y0 := (sy - y)
-				if vflip {
-					y0 = ths*8 - y0 - 1
+				// Get the object size. The size is expressed in number of chars,
+				// not pixels.
+				sz := objWidth[((a0>>14)<<2)|(a1>>14)]
+				tw, th := sz.w, sz.h
+				tws, ths := tw, th
+
+				if mode == objModeAffineDouble {
+					tws *= 2
+					ths *= 2
 				}
 
-				// Calculate the pitch of a sprite, expressed in number of chars)
-				// This depends on the 1D vs 2D tile mapping in VRAM; 1D
-				// mapping means that tiles are arranged linearly in memory so the
-				// pitch is just the size of the sprite (in tiles).
-				// In 2D mapping, tiles are arranged in a 2D grid with a fixed size
-				// depending on the BPP, and thus not
-				pitch := tw
-				if pixmode == objPixModeBitmap {
-					if !bitmapMapping1d {
-						pitch = objPitch
-					}
-				} else {
-					if !mapping1d {
-						if depth256 {
-							pitch = 16
-						} else {
-							pitch = 32
+				// If the sprite is visible
+				// FIXME: this doesn't handle wrapping yet
+				if sy >= y && sy < (y+ths*8) && (x < cScreenWidth && (x+tws*8) >= 0) {
+					tilenum := int(a2 & 1023)
+					depth256 := (a0>>13)&1 != 0
+					hflip := (a1>>12)&1 != 0 && mode == objModeNormal // hflip not available in affine mode
+					vflip := (a1>>13)&1 != 0 && mode == objModeNormal // vflip not available in affine mode
+					pri := (a2 >> 10) & 3
+					pal := (a2 >> 12) & 0xF
+					mosaic := (a0>>12)&1 != 0
+
+					// Semi-transparent sprites are blended with the layers
+					// below using BLDALPHA; bitmap sprites are blended
+					// using their own alpha (in place of the palette
+					// number), and are not displayed at all if it is zero.
+					var blend uint32
+					switch pixmode {
+					case objPixModeAlpha:
+						blend = objBlendBit
+					case objPixModeBitmap:
+						if pal == 0 {
+							continue
 						}
+						blend = objBlendBit | uint32(pal*2+1)<<16
 					}
-				}
 
-				// See if we need to draw in affine mode
-				if mode != objModeNormal {
-					if pixmode == objPixModeBitmap {
-						panic("bitmap mode not supported in affine")
+					// Size of a char (in byte), depending on the color setting
+					charSize := 32
+					if depth256 {
+						charSize = 64
 					}
 
-					parms := ((a1>>9)&0x1F)*0x20 + 0x6
-					dx := int(int16(emu.Read16LE(oam[parms:])))
-					dmx := int(int16(emu.Read16LE(oam[parms+8:])))
-					dy := int(int16(emu.Read16LE(oam[parms+16:])))
-					dmy := int(int16(emu.Read16LE(oam[parms+24:])))
-
-					sx := (tw*8/2)<<8 - (tws*8/2)*dx - (ths*8/2)*dmx + y0*dmx
-					sy := (th*8/2)<<8 - (tws*8/2)*dy - (ths*8/2)*dmy + y0*dmy
-
-					src := tiles.FetchPointer(vramOffset)
-					dst := line
-
-					attrs := uint32(pri) << 29
-					if depth256 {
-						if useExtPal {
-							attrs |= uint32(pal<<8) | (1 << 12)
-						}
+					// Compute the offset within VRAM of the current object (for
+					// now, its top-left pixel), and its pitch (in chars): the
+					// layout of the sprite depends on the 1D vs 2D mapping (see
+					// objMapping).
+					var vramOffset, pitch int
+					if pixmode == objPixModeBitmap {
+						vramOffset, pitch = mapping.bitmapAddr(tilenum, tw)
 					} else {
-						attrs |= uint32(pal << 4)
-						if useExtPal {
-							attrs |= (1 << 12)
-						}
+						vramOffset, pitch = mapping.charAddr(tilenum, tw, depth256)
 					}
 
-					for j := 0; j < tws*8; j++ {
-						if x >= 0 && x < cScreenWidth {
-							isx, isy := sx>>8, sy>>8
-							if isx >= 0 && isx < tw*8 && isy >= 0 && isy < th*8 {
-								ty := isy / 8
-								off := (pitch * charSize) * ty
-								isy &= 7
-
-								tx := isx / 8
-								off += charSize * tx
-								isx &= 7
-
-								if depth256 {
-									pix := uint32(src[off+isy*8+isx])
-									if pix != 0 {
-										dst.Set32(x, pix|attrs)
-									}
-								} else {
-									pix := uint32(src[off+isy*4+isx/2])
-									pix >>= 4 * uint(isx&1)
-									pix &= 0xF
-									if pix != 0 {
-										dst.Set32(x, pix|attrs)
-									}
-								}
-							}
+					// Compute the line being drawn *within* the current object.
+					// This must also handle vertical flip (in which the whole
+					// object is flipped, not just the single chars)
+					// With vertical mosaic, the first line of each block
+					// (relative to the screen) is repeated.
+					y0 := (sy - y)
+					if mosaic {
+						if y0 -= sy % mosh; y0 < 0 {
+							y0 = 0
 						}
-
-						sx += dx
-						sy += dy
-						x++
+					}
+					if vflip {
+						y0 = ths*8 - y0 - 1
 					}
 
-				} else {
-					if pixmode == objPixModeBitmap {
-						if hflip {
-							modLcd.Fatal("horizontal flip in obj bitmap")
+					// Mosaic sprites, and the sprite that is cut off by the
+					// cycle budget, are drawn into the scratch line, and
+					// then copied within [x0,x1).
+					target, x0, x1 := line, x, x+tws*8
+					clipped := i == nobjs
+					if clipped {
+						x1 = x0 + partial
+					}
+					if mosaic || clipped {
+						for i := range e2d.objMosBuf {
+							e2d.objMosBuf[i] = 0
 						}
+						target = mosline
+					}
+
+					// See if we need to draw in affine mode
+					if mode != objModeNormal {
+						parms := ((a1>>9)&0x1F)*0x20 + 0x6
+						dx := int(int16(emu.Read16LE(oam[parms:])))
+						dmx := int(int16(emu.Read16LE(oam[parms+8:])))
+						dy := int(int16(emu.Read16LE(oam[parms+16:])))
+						dmy := int(int16(emu.Read16LE(oam[parms+24:])))
+
+						sx := (tw*8/2)<<8 - (tws*8/2)*dx - (ths*8/2)*dmx + y0*dmx
+						sy := (th*8/2)<<8 - (tws*8/2)*dy - (ths*8/2)*dmy + y0*dmy
 
-						vramOffset += (pitch * 8 * y0) * 2
 						src := tiles.FetchPointer(vramOffset)
-						dst := line
+						dst := target
+
+						attrs := uint32(pri)<<29 | blend
+						if pixmode == objPixModeBitmap {
+							attrs |= 0x80000000
+						} else if depth256 {
+							if useExtPal {
+								attrs |= uint32(pal<<8) | (1 << 12)
+							}
+						} else {
+							attrs |= uint32(pal << 4)
+							if useExtPal {
+								attrs |= (1 << 12)
+							}
+						}
 
-						attrs := (uint32(pri) << 29) | 0x80000000
-						for j := 0; j < tw*8; j++ {
+						for j := 0; j < tws*8; j++ {
 							if x >= 0 && x < cScreenWidth {
-								px := uint32(emu.Read16LE(src[j*2:]))
-								dst.Set32(x, px|attrs)
+								isx, isy := sx>>8, sy>>8
+								if isx >= 0 && isx < tw*8 && isy >= 0 && isy < th*8 {
+									if pixmode == objPixModeBitmap {
+										// Bitmap sprites are linear, with bit 15 of
+										// each pixel used as opaque flag
+										px := uint32(emu.Read16LE(src[(isy*pitch*8+isx)*2:]))
+										if px&0x8000 != 0 {
+											dst.Set32(x, px|attrs)
+										}
+									} else {
+										ty := isy / 8
+										off := (pitch * charSize) * ty
+										isy &= 7
+
+										tx := isx / 8
+										off += charSize * tx
+										isx &= 7
+
+										var pix uint32
+										if depth256 {
+											pix = uint32(src[off+isy*8+isx])
+										} else {
+											pix = uint32(src[off+isy*4+isx/2])
+											pix >>= 4 * uint(isx&1)
+											pix &= 0xF
+										}
+										if pix != 0 {
+											if winmode {
+												dst.Set32(x, dst.Get32(x)|objWindowBit)
+											} else {
+												dst.Set32(x, pix|attrs)
+											}
+										}
+									}
+								}
 							}
+
+							sx += dx
+							sy += dy
 							x++
 						}
 
 					} else {
-						// Calculate the char row being drawn.
-						ty := y0 / 8
+						if pixmode == objPixModeBitmap {
+							vramOffset += (pitch * 8 * y0) * 2
+							src := tiles.FetchPointer(vramOffset)
+							dst := target
+
+							attrs := (uint32(pri) << 29) | 0x80000000 | blend
+							for j := 0; j < tw*8; j++ {
+								if x >= 0 && x < cScreenWidth {
+									sj := j
+									if hflip {
+										sj = tw*8 - j - 1
+									}
+									// Bit 15 is the opaque flag
+									px := uint32(emu.Read16LE(src[sj*2:]))
+									if px&0x8000 != 0 {
+										dst.Set32(x, px|attrs)
+									}
+								}
+								x++
+							}
 
-						// Adjust the offset to the beginning of the correct char row
-						// within the object.
-						vramOffset += (pitch * charSize) * ty
+						} else {
+							// Calculate the char row being drawn.
+							ty := y0 / 8
 
-						// Now calculate the line being drawn within the current char row
-						y0 &= 7
+							// Adjust the offset to the beginning of the correct char row
+							// within the object.
+							vramOffset += (pitch * charSize) * ty
 
-						// Prepare initial src/dst pointer for drawing
-						src := tiles.FetchPointer(vramOffset)
-						dst := line
-						dst.Add32(x)
+							// Now calculate the line being drawn within the current char row
+							y0 &= 7
 
-						for j := 0; j < tw; j++ {
-							tsrc := src[charSize*j:]
-							if hflip {
-								tsrc = src[charSize*(tw-j-1):]
-							}
+							// Prepare initial src/dst pointer for drawing
+							src := tiles.FetchPointer(vramOffset)
+							dst := target
+							dst.Add32(x)
+
+							for j := 0; j < tw; j++ {
+								tsrc := src[charSize*j:]
+								if hflip {
+									tsrc = src[charSize*(tw-j-1):]
+								}
 
-							if x > -8 && x < cScreenWidth {
-								if depth256 {
-									if !useExtPal {
-										pal = 0
+								if x > -8 && x < cScreenWidth {
+									if winmode {
+										e2d.drawObjWindowChar(y0, tsrc, dst, hflip, depth256)
+									} else if depth256 {
+										if !useExtPal {
+											pal = 0
+										}
+										e2d.drawChar256(y0, tsrc, dst, hflip, pri, pal, useExtPal, blend)
+									} else {
+										e2d.drawChar16(y0, tsrc, dst, hflip, pri, pal, false, blend)
 									}
-									e2d.drawChar256(y0, tsrc, dst, hflip, pri, pal, useExtPal)
-								} else {
-									e2d.drawChar16(y0, tsrc, dst, hflip, pri, pal, false)
 								}
+								dst.Add32(8)
+								x += 8
 							}
-							dst.Add32(8)
-							x += 8
 						}
 					}
+					if mosaic {
+						mosaicObj(line, mosline, x0, x1, mosw, winmode)
+					} else if clipped {
+						mosaicObj(line, mosline, x0, x1, 1, winmode)
+					}
 				}
 			}
+			// The OBJ window pass is only needed if there are window sprites,
+			// and the OBJ window is enabled.
+			if !haswin || e2d.dispcnt&(1<<15) == 0 {
+				break
+			}
 		}
 		sy++
 	}
 }
+
+// Mark the pixels of a char of a sprite in OBJ window mode: the non-transparent
+// pixels of the char become part of the OBJ window, but nothing is drawn.
+func (e2d *HwEngine2d) drawObjWindowChar(y int, src []byte, dst gfx.Line, hflip bool, depth256 bool) {
+	for x := 0; x < 8; x++ {
+		sx := x
+		if hflip {
+			sx = 7 - x
+		}
+		var p0 byte
+		if depth256 {
+			p0 = src[y*8+sx]
+		} else {
+			p0 = (src[y*4+sx/2] >> (4 * uint(sx&1))) & 0xF
+		}
+		if p0 != 0 {
+			dst.Set32(x, dst.Get32(x)|objWindowBit)
+		}
+	}
+}
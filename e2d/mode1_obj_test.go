@@ -0,0 +1,301 @@
+package e2d
+
+import (
+	"ndsemu/emu"
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+type testMemCtrl struct {
+	pal  []byte
+	oam  []byte
+	vram VramLinearBank
+	raw  [4][]byte
+}
+
+func newTestMemCtrl() *testMemCtrl {
+	mc := &testMemCtrl{
+		pal: make([]byte, 1024),
+		oam: make([]byte, 1024),
+	}
+	for i := range mc.vram.Ptr {
+		mc.vram.Ptr[i] = make([]byte, VramSmallestBankSize)
+	}
+	return mc
+}
+
+func (mc *testMemCtrl) VramPalette(engine int) []byte { return mc.pal }
+func (mc *testMemCtrl) VramOAM(engine int) []byte     { return mc.oam }
+func (mc *testMemCtrl) VramRawBank(bank int) []byte   { return mc.raw[bank] }
+func (mc *testMemCtrl) VramLinearBank(engine int, which VramLinearBankId, baseOffset int) VramLinearBank {
+	return mc.vram
+}
+
+// Draw a frame with a layer manager containing just the specified layer,
+// returning the raw layer pixels of the first line
+func drawLayerLine(draw func(ctx *gfx.LayerCtx, lidx int, y int)) []uint32 {
+	var lm gfx.LayerManager
+	lm.Cfg = gfx.LayerManagerConfig{
+		Width:          256,
+		Height:         192,
+		ScreenBpp:      4,
+		LayerBpp:       4,
+		OverflowPixels: 8,
+		Mixer:          func(pixels []uint32, ctx interface{}) uint32 { return pixels[0] },
+	}
+	lm.AddLayer(gfx.LayerFunc{Func: draw})
+
+	screen := gfx.NewBufferMem(256, 192)
+	lm.BeginFrame()
+	for y := 0; y < 192; y++ {
+		lm.BeginLine(screen.Line(y))
+		lm.EndLine()
+	}
+	lm.EndFrame()
+
+	res := make([]uint32, 256)
+	for x := range res {
+		res[x] = screen.Line(0).Get32(x)
+	}
+	return res
+}
+
+// Draw a frame with just the OBJ layer, returning the raw layer pixels of
+// the first line
+func drawObjLine(e2d *HwEngine2d) []uint32 {
+	e2d.latchLineRegs()
+	return drawLayerLine(e2d.DrawOBJ)
+}
+
+func TestObjWindow(t *testing.T) {
+	mc := newTestMemCtrl()
+
+	// Char 1: 16-color, all pixels with color 1, except the last column
+	// which is transparent
+	tile := mc.vram.Ptr[0][32:64]
+	for i := range tile {
+		tile[i] = 0x11
+		if i%4 == 3 {
+			tile[i] = 0x01
+		}
+	}
+
+	// Hide all sprites, then setup:
+	//   OBJ 0: normal 8x8 at (0,0), using char 1
+	//   OBJ 1: window 8x8 at (4,0), using char 1
+	for i := 0; i < 128; i++ {
+		emu.Write16LE(mc.oam[i*8:], objModeHidden<<8)
+	}
+	emu.Write16LE(mc.oam[0:], 0)
+	emu.Write16LE(mc.oam[2:], 0)
+	emu.Write16LE(mc.oam[4:], 1)
+	emu.Write16LE(mc.oam[8:], objPixModeWindow<<10)
+	emu.Write16LE(mc.oam[10:], 4)
+	emu.Write16LE(mc.oam[12:], 1)
+
+	e2d := NewHwEngine2d(0, mc, nil)
+
+	// OBJ enabled, 1D mapping, OBJ window enabled
+	e2d.DispCnt.Value = 1<<4 | 1<<12 | 1<<15
+	pix := drawObjLine(e2d)
+	for x := 0; x < 16; x++ {
+		p := LayerPixel(pix[x])
+		drawn := x < 7
+		inwin := x >= 4 && x < 11
+		if p.Transparent() == drawn {
+			t.Errorf("x=%d: drawn=%v, want %v", x, !p.Transparent(), drawn)
+		}
+		if p.ObjWindow() != inwin {
+			t.Errorf("x=%d: window=%v, want %v", x, p.ObjWindow(), inwin)
+		}
+		if drawn && p.ColorIndex() != 1 {
+			t.Errorf("x=%d: color=%d, want 1", x, p.ColorIndex())
+		}
+	}
+
+	// With OBJ window disabled, window sprites must not affect the layer
+	e2d.DispCnt.Value &^= 1 << 15
+	pix = drawObjLine(e2d)
+	for x := 0; x < 16; x++ {
+		if p := LayerPixel(pix[x]); p.ObjWindow() || p.Transparent() != (x >= 7) {
+			t.Errorf("x=%d: unexpected pixel with OBJ window disabled: %08x", x, pix[x])
+		}
+	}
+
+	// Sprites hidden for debugging: the OBJ window still works
+	e2d.DispCnt.Value |= 1 << 15
+	e2d.SetDebugLayerMask(DebugLayerAll &^ DebugLayerOBJ)
+	if m := e2d.DebugLayerMask(); m != DebugLayerBGs {
+		t.Errorf("invalid debug layer mask: %x", m)
+	}
+	pix = drawObjLine(e2d)
+	for x := 0; x < 16; x++ {
+		inwin := x >= 4 && x < 11
+		if p := LayerPixel(pix[x]); p.ObjWindow() != inwin || !p.Transparent() {
+			t.Errorf("x=%d: unexpected pixel with sprites hidden: %08x", x, pix[x])
+		}
+	}
+}
+
+func TestObjLineLimit(t *testing.T) {
+	oam := make([]byte, 1024)
+
+	// 64x64 normal sprites at y=0 (size 3, square), and an affine 8x8
+	// sprite for every 16 of them, at y=16
+	for i := 0; i < 128; i++ {
+		emu.Write16LE(oam[i*8:], 0)
+		emu.Write16LE(oam[i*8+2:], 3<<14)
+		if i%16 == 15 {
+			emu.Write16LE(oam[i*8:], objModeAffine<<8|16)
+			emu.Write16LE(oam[i*8+2:], 0)
+		}
+	}
+
+	// The limit is the index of the first sprite that is dropped
+	for _, tc := range []struct {
+		y, budget, limit int
+	}{
+		{0, objLineCycles, 35},       // 33 normal sprites: 2112 cycles
+		{0, objLineCyclesNoHBlk, 24}, // 23 normal sprites: 1472 cycles
+		{16, objLineCycles, 34},      // 32 normal + 2 affine: 2100 cycles
+		{24, objLineCycles, 35},      // affine sprites end at line 23
+		{100, objLineCycles, 128},    // no sprites on the line
+	} {
+		if n, _ := objLineLimit(oam, tc.y, tc.budget); n != tc.limit {
+			t.Errorf("line %d, budget %d: got limit %d, want %d", tc.y, tc.budget, n, tc.limit)
+		}
+	}
+}
+
+func TestObjMapping(t *testing.T) {
+	for _, tc := range []struct {
+		dispcnt  uint32
+		bitmap   bool
+		depth256 bool
+		off      int
+		pitch    int
+	}{
+		// Char number 37 of a 2-chars wide sprite
+		{0, false, false, 37 * 32, 32},                  // 2D
+		{0, false, true, 37 * 32, 16},                   // 2D, 256 colors
+		{1 << 4, false, false, 37 * 32, 2},              // 1D, 32 bytes boundary
+		{1<<4 | 2<<20, false, true, 37 * 128, 2},        // 1D, 128 bytes boundary
+		{1<<4 | 3<<20, false, false, 37 * 256, 2},       // 1D, 256 bytes boundary
+		{0, true, false, 5*16 + 2*8*256, 16},            // 2D bitmap, 128 pixels
+		{1 << 5, true, false, 5*16 + 1*8*512, 32},       // 2D bitmap, 256 pixels
+		{1 << 6, true, false, 37 * 128, 2},              // 1D bitmap, 128 bytes boundary
+		{1<<6 | 1<<22, true, false, 37 * 256, 2},        // 1D bitmap, 256 bytes boundary
+		{1<<4 | 3<<20 | 1<<6, true, false, 37 * 128, 2}, // char boundary is ignored
+	} {
+		m := newObjMapping(tc.dispcnt)
+		var off, pitch int
+		if tc.bitmap {
+			off, pitch = m.bitmapAddr(37, 2)
+		} else {
+			off, pitch = m.charAddr(37, 2, tc.depth256)
+		}
+		if off != tc.off || pitch != tc.pitch {
+			t.Errorf("dispcnt=%08x bitmap=%v: got offset %x pitch %d, want %x %d",
+				tc.dispcnt, tc.bitmap, off, pitch, tc.off, tc.pitch)
+		}
+	}
+}
+
+func TestObjLinePartial(t *testing.T) {
+	oam := make([]byte, 1024)
+	for i := 0; i < 128; i++ {
+		emu.Write16LE(oam[i*8:], objModeHidden<<8)
+	}
+
+	// Sprite 0: normal 64x64 (64 cycles); sprite 1: affine double 32x32
+	// (2*64+10 cycles); sprite 2: normal 64x64
+	emu.Write16LE(oam[0:], 0)
+	emu.Write16LE(oam[2:], 3<<14)
+	emu.Write16LE(oam[8:], objModeAffineDouble<<8)
+	emu.Write16LE(oam[10:], 2<<14)
+	emu.Write16LE(oam[16:], 0)
+	emu.Write16LE(oam[18:], 3<<14)
+
+	for _, tc := range []struct {
+		budget, limit, partial int
+	}{
+		{64 + 138 + 64, 128, 0},
+		{64 + 138 + 20, 2, 20}, // normal sprite: one pixel per cycle
+		{64 + 50, 1, 20},       // affine sprite: 10 cycles, then two per pixel
+		{64 + 5, 1, 0},
+		{30, 0, 30},
+	} {
+		n, partial := objLineLimit(oam, 0, tc.budget)
+		if n != tc.limit || partial != tc.partial {
+			t.Errorf("budget %d: got (%d,%d), want (%d,%d)", tc.budget, n, partial, tc.limit, tc.partial)
+		}
+	}
+}
+
+func TestObjMosaic(t *testing.T) {
+	mc := newTestMemCtrl()
+
+	// Char 1: 16-color, the pixels of each line have colors 1-8
+	tile := mc.vram.Ptr[0][32:64]
+	for i := range tile {
+		tile[i] = byte((i%4)*2+1) | byte((i%4)*2+2)<<4
+	}
+
+	// OBJ 0: normal 8x8 at (2,0), using char 1, with mosaic
+	for i := 0; i < 128; i++ {
+		emu.Write16LE(mc.oam[i*8:], objModeHidden<<8)
+	}
+	emu.Write16LE(mc.oam[0:], 1<<12)
+	emu.Write16LE(mc.oam[2:], 2)
+	emu.Write16LE(mc.oam[4:], 1)
+
+	e2d := NewHwEngine2d(0, mc, nil)
+	e2d.DispCnt.Value = 1<<4 | 1<<12
+	e2d.Mosaic.Value = 3 << 8 // 4-pixel horizontal blocks
+
+	// Each pixel is taken from the left border of its block: the first
+	// block begins before the sprite, so it's transparent.
+	want := []uint32{0, 0, 0, 0, 3, 3, 3, 3, 7, 7, 0, 0}
+	pix := drawObjLine(e2d)
+	for x, w := range want {
+		if c := LayerPixel(pix[x]).ColorIndex(); c != uint16(w) {
+			t.Errorf("x=%d: color=%d, want %d", x, c, w)
+		}
+	}
+}
+
+func TestObjCycleCutoff(t *testing.T) {
+	mc := newTestMemCtrl()
+
+	// Chars 1-64: 16-color, all pixels with color 1
+	tiles := mc.vram.Ptr[0][32 : 32+64*32]
+	for i := range tiles {
+		tiles[i] = 0x11
+	}
+
+	// OBJ 0-32: normal 64x64 at (0,0), 2112 cycles in total; OBJ 33: normal
+	// 64x64 at (100,0), of which only 18 pixels fit in the budget
+	for i := 0; i < 128; i++ {
+		emu.Write16LE(mc.oam[i*8:], objModeHidden<<8)
+	}
+	for i := 0; i < 34; i++ {
+		emu.Write16LE(mc.oam[i*8:], 0)
+		emu.Write16LE(mc.oam[i*8+2:], 3<<14)
+		emu.Write16LE(mc.oam[i*8+4:], 1)
+	}
+	emu.Write16LE(mc.oam[33*8+2:], 3<<14|100)
+
+	e2d := NewHwEngine2d(0, mc, nil)
+	e2d.DispCnt.Value = 1<<4 | 1<<12 | 1<<23
+
+	pix := drawObjLine(e2d)
+	for x := 64; x < 200; x++ {
+		want := uint16(0)
+		if x >= 100 && x < 118 {
+			want = 1
+		}
+		if c := LayerPixel(pix[x]).ColorIndex(); c != want {
+			t.Errorf("x=%d: color=%d, want %d", x, c, want)
+		}
+	}
+}
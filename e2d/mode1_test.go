@@ -0,0 +1,42 @@
+package e2d
+
+import "testing"
+
+func TestBgLayerModes(t *testing.T) {
+	const (
+		T  = BgModeText
+		A  = BgModeAffine
+		M  = BgModeAffineMap16
+		B  = BgModeAffineBitmap
+		D  = BgModeAffineBitmapDirect
+		L  = BgModeLargeBitmap
+		G  = BgMode3D
+		no = BgModeNone
+	)
+	cnt := [4]uint16{0, 0, 1 << 7, 1<<7 | 1<<2} // BG2: 256-color bitmap, BG3: direct color
+
+	for _, tc := range []struct {
+		dispcnt uint32
+		engineA bool
+		cnt     [4]uint16
+		modes   [4]BgMode
+		ok      bool
+	}{
+		{0, true, cnt, [4]BgMode{T, T, T, T}, true},
+		{1, true, cnt, [4]BgMode{T, T, T, A}, true},
+		{2, false, cnt, [4]BgMode{T, T, A, A}, true},
+		{3, true, cnt, [4]BgMode{T, T, T, D}, true},
+		{4, false, [4]uint16{}, [4]BgMode{T, T, A, M}, true},
+		{5, true, cnt, [4]BgMode{T, T, B, D}, true},
+		{5 | 1<<3, true, cnt, [4]BgMode{G, T, B, D}, true},
+		{5 | 1<<3, false, cnt, [4]BgMode{T, T, B, D}, true}, // no 3D on engine B
+		{6, true, cnt, [4]BgMode{G, no, L, no}, true},
+		{6, false, cnt, [4]BgMode{no, no, no, no}, false},
+		{7, true, cnt, [4]BgMode{no, no, no, no}, false},
+	} {
+		modes, ok := bgLayerModes(tc.dispcnt, tc.engineA, tc.cnt)
+		if modes != tc.modes || ok != tc.ok {
+			t.Errorf("dispcnt=%x A=%v: got %v,%v, want %v,%v", tc.dispcnt, tc.engineA, modes, ok, tc.modes, tc.ok)
+		}
+	}
+}
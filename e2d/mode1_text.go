@@ -2,12 +2,14 @@ package e2d
 
 import (
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
 )
 
-func (e2d *HwEngine2d) drawChar16(y int, src []byte, dst gfx.Line, hflip bool, pri uint16, pal uint16, extpal bool) {
+// Draw a line of a 16-color char. flags are additional LayerPixel bits set on
+// all the pixels; pri is the priority, which is only stored in OBJ pixels
+// (BG layers pass 0).
+func (e2d *HwEngine2d) drawChar16(y int, src []byte, dst gfx.Line, hflip bool, pri uint16, pal uint16, extpal bool, flags uint32) {
 	src = src[y*4:]
-	attrs := uint32(pri)<<29 | uint32(pal)<<4
+	attrs := uint32(pri)<<29 | uint32(pal)<<4 | flags
 	if extpal {
 		attrs |= (1 << 12)
 	}
@@ -38,9 +40,10 @@ func (e2d *HwEngine2d) drawChar16(y int, src []byte, dst gfx.Line, hflip bool, p
 	}
 }
 
-func (e2d *HwEngine2d) drawChar256(y int, src []byte, dst gfx.Line, hflip bool, pri uint16, pal uint16, extpal bool) {
+// Draw a line of a 256-color char. flags and pri are as in drawChar16.
+func (e2d *HwEngine2d) drawChar256(y int, src []byte, dst gfx.Line, hflip bool, pri uint16, pal uint16, extpal bool, flags uint32) {
 	src = src[y*8:]
-	attrs := uint32(pri)<<29 | uint32(pal)<<8
+	attrs := uint32(pri)<<29 | uint32(pal)<<8 | flags
 	if extpal {
 		attrs |= (1 << 12)
 	}
@@ -63,15 +66,27 @@ func (e2d *HwEngine2d) drawChar256(y int, src []byte, dst gfx.Line, hflip bool,
 	}
 }
 
-func (e2d *HwEngine2d) DrawBG(ctx *gfx.LayerCtx, lidx int, y int) {
+// DrawBGText draws a text (tiled, non-affine) BG layer; it's the counterpart
+// of DrawBGAffine. BGxCNT selects:
+//
+//   - the screen size (bits 14-15): 256x256, 512x256, 256x512 or 512x512,
+//     made of one to four 32x32 tile maps of 2K each;
+//   - the color depth (bit 7): 16 colors with 16 palettes, or 256 colors,
+//     with 16 palettes only when extended palettes are enabled in DISPCNT;
+//   - mosaic (bit 6).
+//
+// Map entries select the tile, the palette and the horizontal/vertical flip.
+// The layer is scrolled through BGxHOFS/BGxVOFS, wrapping around at the
+// screen size.
+func (e2d *HwEngine2d) DrawBGText(ctx *gfx.LayerCtx, lidx int, y int) {
 	regs := &e2d.bgregs[lidx]
 
 	mapBase := int((*regs.Cnt>>8)&0x1F) * 2 * 1024
 	charBase := int((*regs.Cnt>>2)&0xF) * 16 * 1024
 
 	if e2d.A() {
-		mapBase += int((e2d.DispCnt.Value>>27)&7) * 64 * 1024
-		charBase += int((e2d.DispCnt.Value>>24)&7) * 64 * 1024
+		mapBase += int((e2d.dispcnt>>27)&7) * 64 * 1024
+		charBase += int((e2d.dispcnt>>24)&7) * 64 * 1024
 	}
 
 	var tmaps [4]VramLinearBank
@@ -87,26 +102,29 @@ func (e2d *HwEngine2d) DrawBG(ctx *gfx.LayerCtx, lidx int, y int) {
 			return
 		}
 
-		if e2d.DispCnt.Value&onmask == 0 || gKeyState[hw.SCANCODE_1+lidx] != 0 {
-			y++
-			continue
-		}
-		if (e2d.A() && gKeyState[hw.SCANCODE_9] != 0) || (e2d.B() && gKeyState[hw.SCANCODE_8] != 0) {
+		if e2d.dispcnt&onmask == 0 || e2d.debugHidden(DebugLayerBG0<<uint(lidx)) {
 			y++
 			continue
 		}
 
 		// Check if we are in extended palette mode (more palettes available for
 		// 256-color tiles).
-		useExtPal := (e2d.DispCnt.Value & (1 << 30)) != 0
+		useExtPal := (e2d.dispcnt & (1 << 30)) != 0
 
-		pri := regs.priority()
 		depth256 := regs.depth256()
 
+		// With vertical mosaic, the first line of each block is repeated
+		mosaic := (*regs.Cnt>>6)&1 != 0
+		mosw, mosh := e2d.bgMosaic()
+		my := y
+		if mosaic {
+			my -= y % mosh
+		}
+
 		doubleh := (*regs.Cnt>>14)&0x1 != 0
 		doublev := (*regs.Cnt>>15)&0x1 != 0
-		mapx := int(*regs.XOfs)
-		mapy := (y + int(*regs.YOfs))
+		mapx := regs.xofs
+		mapy := my + regs.yofs
 		tmapidx := 0
 
 		if doublev {
@@ -123,6 +141,7 @@ func (e2d *HwEngine2d) DrawBG(ctx *gfx.LayerCtx, lidx int, y int) {
 			}
 		}
 
+		bgline := line
 		line.Add32(-(mapx & 7))
 		for x := 0; x <= cScreenWidth/8; x++ {
 			if doubleh {
@@ -153,17 +172,20 @@ func (e2d *HwEngine2d) DrawBG(ctx *gfx.LayerCtx, lidx int, y int) {
 				if !useExtPal {
 					pal = 0
 				}
-				e2d.drawChar256(ty, ch, line, hflip, pri, pal, useExtPal)
+				e2d.drawChar256(ty, ch, line, hflip, 0, pal, useExtPal, 0)
 			} else {
 				ch := chars.FetchPointer(tnum * 32)
 				// 16-color tiles don't use extended palettes, so we always pass false
 				// to the drawChar16() function
-				e2d.drawChar16(ty, ch, line, hflip, pri, pal, false)
+				e2d.drawChar16(ty, ch, line, hflip, 0, pal, false, 0)
 			}
 			line.Add32(8)
 
 			mapx += 8
 		}
+		if mosaic {
+			mosaicLine(bgline, mosw)
+		}
 
 		y++
 	}
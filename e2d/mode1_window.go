@@ -0,0 +1,92 @@
+package e2d
+
+import "ndsemu/emu/gfx"
+
+// A pixel in the window layer. It is composed as follows:
+//
+//	Bits 0-5: layers enabled in the window the pixel belongs to (WIN0, WIN1
+//	          or outside), in the same format as WININ/WINOUT: BG0-BG3, OBJ,
+//	          color special effects
+//	Bits 8-13: layers enabled in the OBJ window
+//	Bit 15: set if the OBJ window applies to this pixel (that is, it is
+//	        enabled and the pixel is outside WIN0/WIN1)
+//
+// Whether a pixel is part of the OBJ window is only known after the OBJ layer
+// has been drawn, so the mixer selects the final mask using Mask.
+type WindowPixel uint32
+
+const (
+	winObj       = 1 << 4
+	winEffects   = 1 << 5
+	winAll       = 0x3F
+	winObjActive = 1 << 15
+)
+
+func (p WindowPixel) Mask(objwin bool) uint32 {
+	if objwin && p&winObjActive != 0 {
+		return uint32(p>>8) & winAll
+	}
+	return uint32(p) & winAll
+}
+
+// DrawWindow draws the window layer, which doesn't produce any visible
+// pixel, but tells the mixer which layers are enabled for each pixel.
+func (e2d *HwEngine2d) DrawWindow(ctx *gfx.LayerCtx, lidx int, y int) {
+	for {
+		line := ctx.NextLine()
+		if line.IsNil() {
+			return
+		}
+		e2d.drawWindowLine(line, y)
+		y++
+	}
+}
+
+func (e2d *HwEngine2d) drawWindowLine(line gfx.Line, y int) {
+	dispcnt := e2d.dispcnt
+
+	// If no window is enabled, everything is visible everywhere
+	if dispcnt&(7<<13) == 0 {
+		for x := 0; x < cScreenWidth; x++ {
+			line.Set32(x, winAll)
+		}
+		return
+	}
+
+	out := uint32(e2d.WinOut.Value) & winAll
+	if dispcnt&(1<<15) != 0 {
+		out |= uint32(e2d.WinOut.Value)&(winAll<<8) | winObjActive
+	}
+	for x := 0; x < cScreenWidth; x++ {
+		line.Set32(x, out)
+	}
+
+	// WIN0 has priority over WIN1, so draw it last
+	if dispcnt&(1<<14) != 0 {
+		drawWindowRect(line, y, e2d.Win1X.Value, e2d.Win1Y.Value, uint32(e2d.WinIn.Value>>8)&winAll)
+	}
+	if dispcnt&(1<<13) != 0 {
+		drawWindowRect(line, y, e2d.Win0X.Value, e2d.Win0Y.Value, uint32(e2d.WinIn.Value)&winAll)
+	}
+}
+
+// Fill the span of a rectangular window on line y. The registers contain
+// the left/top coordinate in the upper byte, and the right/bottom coordinate
+// (exclusive) in the lower byte. If the start coordinate is bigger than the
+// end one, the window extends to the end of the screen.
+func drawWindowRect(line gfx.Line, y int, wx, wy uint16, mask uint32) {
+	x1, x2 := int(wx>>8), int(wx&0xFF)
+	y1, y2 := int(wy>>8), int(wy&0xFF)
+	if y1 > y2 {
+		y2 = cScreenHeight
+	}
+	if y < y1 || y >= y2 {
+		return
+	}
+	if x1 > x2 {
+		x2 = cScreenWidth
+	}
+	for x := x1; x < x2; x++ {
+		line.Set32(x, mask)
+	}
+}
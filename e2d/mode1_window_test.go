@@ -0,0 +1,100 @@
+package e2d
+
+import (
+	"ndsemu/emu"
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestWindowMask(t *testing.T) {
+	e2d := &HwEngine2d{}
+	e2d.DispCnt.Value = 1<<13 | 1<<14 | 1<<15
+	e2d.Win0X.Value = 16<<8 | 32 // x=16..31
+	e2d.Win0Y.Value = 8<<8 | 16  // y=8..15
+	e2d.Win1X.Value = 24<<8 | 8  // x=24..255 (x1 > x2)
+	e2d.Win1Y.Value = 0<<8 | 192 // y=0..191
+	e2d.WinIn.Value = 0x11<<8 | 0x01
+	e2d.WinOut.Value = 0x14<<8 | 0x08
+	e2d.latchLineRegs()
+
+	buf := make([]byte, cScreenWidth*4)
+	line := gfx.NewLine(buf)
+	for _, tc := range []struct {
+		x, y        int
+		mask, objwn uint32
+	}{
+		{0, 0, 0x08, 0x14},   // outside, OBJ window applies
+		{20, 0, 0x08, 0x14},  // outside (WIN0 is not on this line)
+		{24, 0, 0x11, 0x11},  // WIN1
+		{255, 0, 0x11, 0x11}, // WIN1 extends to the end of the line
+		{20, 8, 0x01, 0x01},  // WIN0
+		{28, 8, 0x01, 0x01},  // WIN0 has priority over WIN1
+		{32, 8, 0x11, 0x11},  // WIN1
+		{28, 16, 0x11, 0x11}, // WIN1 (after the end of WIN0)
+	} {
+		e2d.drawWindowLine(line, tc.y)
+		pix := WindowPixel(line.Get32(tc.x))
+		if m := pix.Mask(false); m != tc.mask {
+			t.Errorf("(%d,%d): mask %02x, want %02x", tc.x, tc.y, m, tc.mask)
+		}
+		if m := pix.Mask(true); m != tc.objwn {
+			t.Errorf("(%d,%d): OBJ window mask %02x, want %02x", tc.x, tc.y, m, tc.objwn)
+		}
+	}
+
+	// With no window enabled, all layers are visible
+	e2d.DispCnt.Value = 0
+	e2d.latchLineRegs()
+	e2d.drawWindowLine(line, 8)
+	if m := WindowPixel(line.Get32(20)).Mask(true); m != winAll {
+		t.Errorf("no windows: mask %02x", m)
+	}
+}
+
+func TestWindowMixer(t *testing.T) {
+	e2d := &HwEngine2d{bgPal: make([]byte, 512), objPal: make([]byte, 512)}
+	for i := range e2d.bgWinBits {
+		e2d.bgWinBits[i] = 1 << uint(i)
+	}
+	emu.Write16LE(e2d.bgPal[0:], 0x1111)  // backdrop
+	emu.Write16LE(e2d.bgPal[2:], 0x2222)  // BG0
+	emu.Write16LE(e2d.bgPal[4:], 0x3333)  // BG1
+	emu.Write16LE(e2d.objPal[2:], 0x4444) // OBJ
+
+	bg0, bg1, obj := uint32(1), uint32(2), uint32(1)
+	for _, tc := range []struct {
+		layers []uint32
+		res    uint32
+	}{
+		{[]uint32{bg0, bg1, 0, 0, obj, winAll}, 0x4444},
+		{[]uint32{bg0, bg1, 0, 0, obj, winAll &^ winObj}, 0x2222},
+		{[]uint32{bg0, bg1, 0, 0, 0, winAll &^ 1}, 0x3333},
+		{[]uint32{bg0, bg1, 0, 0, 0, 0}, 0x1111},
+		// OBJ window: the pixel marked by a window sprite uses its mask
+		{[]uint32{bg0, bg1, 0, 0, objWindowBit, 0x02<<8 | winObjActive | 0x01}, 0x3333},
+		{[]uint32{bg0, bg1, 0, 0, 0, 0x02<<8 | winObjActive | 0x01}, 0x2222},
+	} {
+		if res := e2dMixer_Normal(tc.layers, e2d); res != tc.res {
+			t.Errorf("%x: got %04x, want %04x", tc.layers, res, tc.res)
+		}
+	}
+}
+
+func TestDispCntLatch(t *testing.T) {
+	e2d := &HwEngine2d{}
+	buf := make([]byte, cScreenWidth*4)
+	line := gfx.NewLine(buf)
+
+	// Enabling a window mid-line has no effect until the next line
+	e2d.latchLineRegs()
+	e2d.DispCnt.Value = 1 << 13
+	e2d.drawWindowLine(line, 0)
+	if m := WindowPixel(line.Get32(0)).Mask(false); m != winAll {
+		t.Errorf("DISPCNT change applied mid-line: mask %02x", m)
+	}
+	e2d.latchLineRegs()
+	e2d.drawWindowLine(line, 1)
+	if m := WindowPixel(line.Get32(0)).Mask(false); m != 0 {
+		t.Errorf("DISPCNT change not applied on next line: mask %02x", m)
+	}
+}
@@ -6,14 +6,18 @@ import "ndsemu/emu/gfx"
  * Display Mode 3: Main memory display
  ************************************************/
 
+// In main memory display mode, each line is transferred by DMA from main
+// memory into the display FIFO (see MainMemFifoWords), and displayed as it
+// is (RGB555), bypassing the 2D layers.
+
 func (e2d *HwEngine2d) Mode3_BeginFrame() {
-	modLcd.Warn("mode 3 not implemented")
+	modLcd.Infof("%s: mode=Main-Memory-Display", string(rune('A'+e2d.Idx)))
 }
 func (e2d *HwEngine2d) Mode3_EndFrame() {}
 
 func (e2d *HwEngine2d) Mode3_BeginLine(y int, screen gfx.Line) {
 	for x := 0; x < cScreenWidth; x++ {
-		screen.Set32(x, 0x0000FF)
+		screen.Set32(x, uint32(e2d.mmfifo[x]))
 	}
 }
 func (e2d *HwEngine2d) Mode3_EndLine(y int) {}
@@ -0,0 +1,47 @@
+package e2d
+
+import (
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestMainMemFifo(t *testing.T) {
+	e2d := NewHwEngine2d(0, newTestMemCtrl(), nil)
+	if n := e2d.MainMemFifoWords(); n != 0 {
+		t.Errorf("FIFO requested while not in use: %d words", n)
+	}
+
+	// The display mode is taken from DISPCNT as it is when the line
+	// begins, not from the previous line
+	e2d.DispCnt.Value = 3 << 16
+	if n := e2d.MainMemFifoWords(); n != cScreenWidth/2 {
+		t.Fatalf("requested %d words, want %d", n, cScreenWidth/2)
+	}
+	for i := 0; i < cScreenWidth/2+1; i++ {
+		e2d.DispMMemFifo.Write32(0, uint32(i*2+1)<<16|uint32(i*2))
+	}
+	if n := e2d.MainMemFifoWords(); n != 0 {
+		t.Errorf("line complete, but %d words requested", n)
+	}
+
+	screen := gfx.NewLine(make([]byte, cScreenWidth*4))
+	e2d.curscreen = screen
+	e2d.Mode3_BeginLine(0, screen)
+	for _, x := range []int{0, 1, 100, 255} {
+		if pix := screen.Get32(x); pix != uint32(x) {
+			t.Errorf("x=%d: got %04x", x, pix)
+		}
+	}
+
+	// The FIFO is emptied at the end of the line
+	e2d.EndLine(0)
+	if n := e2d.MainMemFifoWords(); n != cScreenWidth/2 {
+		t.Errorf("after end of line: requested %d words, want %d", n, cScreenWidth/2)
+	}
+
+	e2d.dispmode = 3
+	e2d.DispCnt.Value = 1 << 16
+	if n := e2d.MainMemFifoWords(); n != 0 {
+		t.Errorf("FIFO requested after leaving main memory display: %d words", n)
+	}
+}
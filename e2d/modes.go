@@ -2,8 +2,6 @@ package e2d
 
 import (
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
-	log "ndsemu/emu/logger"
 )
 
 /************************************************
@@ -11,73 +9,25 @@ import (
  ************************************************/
 
 func (e2d *HwEngine2d) BeginFrame() {
+	e2d.latchLineRegs()
 
-	if gKeyState == nil {
-		gKeyState = hw.GetKeyboardState()
+	// Check if display capture is activated (engine A only)
+	if e2d.A() && e2d.DispCapCnt.Value&(1<<31) != 0 {
+		e2d.startCapture()
 	}
 
-	// Check if display capture is activated
-	if e2d.DispCapCnt.Value&(1<<31) != 0 {
-		source := (e2d.DispCapCnt.Value >> 29) & 3
-		srca := (e2d.DispCapCnt.Value >> 24) & 1
-		srcb := (e2d.DispCapCnt.Value >> 25) & 1
-
-		if srca != 0 || srcb != 0 {
-			modLcd.Fatalf("unimplemented display capture source=%d srca=%d srb=%d", source, srca, srcb)
-		}
-
-		// Begin capturing this frame
-		e2d.dispcap.Enabled = true
-		e2d.dispcap.Mode = int(source)
-		e2d.dispcap.WBank = int((e2d.DispCapCnt.Value >> 16) & 3)
-		e2d.dispcap.WOffset = ((e2d.DispCapCnt.Value >> 18) & 3) * 0x8000
-		e2d.dispcap.RBank = int((e2d.DispCnt.Value >> 18) & 3)
-		e2d.dispcap.ROffset = ((e2d.DispCapCnt.Value >> 26) & 3) * 0x8000
-		e2d.dispcap.AlphaA = e2d.DispCapCnt.Value & 0x1F
-		e2d.dispcap.AlphaB = (e2d.DispCapCnt.Value >> 8) & 0x1F
-		if e2d.dispcap.AlphaA > 16 {
-			e2d.dispcap.AlphaA = 16
-		}
-		if e2d.dispcap.AlphaB > 16 {
-			e2d.dispcap.AlphaB = 16
-		}
-
-		switch (e2d.DispCapCnt.Value >> 20) & 3 {
-		case 0:
-			e2d.dispcap.Width = 128
-			e2d.dispcap.Height = 128
-		case 1:
-			e2d.dispcap.Width = 256
-			e2d.dispcap.Height = 64
-		case 2:
-			e2d.dispcap.Width = 256
-			e2d.dispcap.Height = 128
-		case 3:
-			e2d.dispcap.Width = 256
-			e2d.dispcap.Height = 192
-		}
-
-		modLcd.WithDelayedFields(func() log.Fields {
-			return log.Fields{
-				"src":   source,
-				"sa":    srca,
-				"sb":    srcb,
-				"wbank": string(e2d.dispcap.WBank + 'A'),
-				"woff":  e2d.dispcap.WOffset,
-				"w":     e2d.dispcap.Width,
-				"h":     e2d.dispcap.Height,
-			}
-		}).Infof("Capture activated")
-
-	}
+	e2d.dispmode = e2d.lineDispMode()
+	e2d.beginDump()
+	e2d.modeTable[e2d.dispmode].BeginFrame()
+}
 
-	// Read current display mode once per frame (do not switch between
-	// display modes within a frame)
-	e2d.dispmode = int((e2d.DispCnt.Value >> 16) & 3)
+// Display mode of the current line, from the latched DISPCNT
+func (e2d *HwEngine2d) lineDispMode() int {
+	mode := int((e2d.dispcnt >> 16) & 3)
 	if e2d.B() {
-		e2d.dispmode &= 1
+		mode &= 1
 	}
-	e2d.modeTable[e2d.dispmode].BeginFrame()
+	return mode
 }
 
 func (e2d *HwEngine2d) EndFrame() {
@@ -88,6 +38,7 @@ func (e2d *HwEngine2d) EndFrame() {
 		e2d.dispcap.Enabled = false
 		e2d.DispCapCnt.Value &^= 1 << 31
 	}
+	e2d.endDump()
 }
 
 func (e2d *HwEngine2d) BeginLine(y int, screen gfx.Line) {
@@ -96,67 +47,65 @@ func (e2d *HwEngine2d) BeginLine(y int, screen gfx.Line) {
 		e2d.masterBrightChanged = false
 	}
 
+	e2d.latchLineRegs()
+	e2d.latchBgRegs(y)
+
+	// The display mode can be changed mid-frame: the frame of the previous
+	// mode is ended, and the new one is begun from the current line.
+	if mode := e2d.lineDispMode(); mode != e2d.dispmode {
+		e2d.modeTable[e2d.dispmode].EndFrame()
+		e2d.dispmode = mode
+		e2d.modeTable[mode].BeginFrame()
+	}
+
 	e2d.curline = y
 	e2d.curscreen = screen
 	e2d.modeTable[e2d.dispmode].BeginLine(y, screen)
 }
 
+// The layers are drawn in background while the CPUs keep running, so
+// registers that affect the whole line are latched when the line begins:
+// changes made mid-line take effect on the next line, as on the hardware,
+// instead of corrupting the line being drawn.
+func (e2d *HwEngine2d) latchLineRegs() {
+	e2d.dispcnt = e2d.DispCnt.Value
+	e2d.bldcnt = uint32(e2d.BldCnt.Value)
+	e2d.mosaic = uint32(e2d.Mosaic.Value)
+
+	clamp := func(v uint32) uint32 {
+		if v > 16 {
+			return 16
+		}
+		return v
+	}
+	e2d.bldEva = clamp(uint32(e2d.BldAlpha.Value) & 0x1F)
+	e2d.bldEvb = clamp(uint32(e2d.BldAlpha.Value>>8) & 0x1F)
+	e2d.bldEvy = clamp(e2d.BldY.Value & 0x1F)
+}
+
+// Latch the scrolling and affine registers of the BG layers for line y, so
+// that raster effects (registers changed at each HBlank, usually through
+// DMA) are drawn on the correct lines.
+func (e2d *HwEngine2d) latchBgRegs(y int) {
+	for i := range e2d.bgregs {
+		e2d.bgregs[i].latch(y)
+	}
+}
+
 func (e2d *HwEngine2d) EndLine(y int) {
 	e2d.modeTable[e2d.dispmode].EndLine(y)
 
 	screen := e2d.curscreen
 
-	// If capture is enabled, capture the screen output
-	// and since we go through the pixels, also apply the
-	// master brightness (which must be applied AFTER capturing)
+	// If capture is enabled, capture the screen output. This must be done
+	// before applying the master brightness, which doesn't affect capture.
 	if e2d.dispcap.Enabled && e2d.curline < e2d.dispcap.Height {
-		vram := e2d.mc.VramRawBank(e2d.dispcap.WBank)
-		vram = vram[e2d.dispcap.WOffset:]
-		capbuf := gfx.NewLine(vram)
-
-		vram = e2d.mc.VramRawBank(e2d.dispcap.RBank)
-		vram = vram[e2d.dispcap.ROffset:]
-		readbuf := gfx.NewLine(vram)
-
-		switch e2d.dispcap.Mode {
-		case 0:
-			for i := 0; i < e2d.dispcap.Width; i++ {
-				pix := screen.Get32(i)
-				capbuf.Set16(i, uint16(pix)|0x8000)
-			}
-		case 1:
-			for i := 0; i < e2d.dispcap.Width; i++ {
-				pix := readbuf.Get16(i)
-				capbuf.Set16(i, uint16(pix)|0x8000)
-			}
-		case 2, 3:
-			eva := e2d.dispcap.AlphaA
-			evb := e2d.dispcap.AlphaB
-			for i := 0; i < e2d.dispcap.Width; i++ {
-				pix1 := uint16(screen.Get32(i))
-				pix2 := uint16(readbuf.Get16(i))
-				r1, g1, b1 := (pix1 & 0x1F), ((pix1 >> 5) & 0x1F), ((pix1 >> 10) & 0x1F)
-				r2, g2, b2 := (pix2 & 0x1F), ((pix2 >> 5) & 0x1F), ((pix2 >> 10) & 0x1F)
-
-				r := (uint32(r1)*eva + uint32(r2)*evb) >> 4
-				g := (uint32(g1)*eva + uint32(g2)*evb) >> 4
-				b := (uint32(b1)*eva + uint32(b2)*evb) >> 4
-
-				capbuf.Set16(i, 0x8000|uint16(r)|uint16(g)<<5|uint16(b)<<10)
-			}
-		}
-
-		e2d.dispcap.ROffset += uint32(e2d.dispcap.Width * 2)
-		if e2d.dispcap.ROffset == 128*1024 {
-			e2d.dispcap.ROffset = 0
-		}
-
-		e2d.dispcap.WOffset += uint32(e2d.dispcap.Width * 2)
-		if e2d.dispcap.WOffset == 128*1024 {
-			e2d.dispcap.WOffset = 0
-		}
+		e2d.captureLine(y, screen)
 	}
 
+	// The main memory display FIFO has been consumed
+	e2d.mmfifoLen = 0
+
 	// Apply master brightness and output to the screen
 	for i := 0; i < 256; i++ {
 		pix := screen.Get32(i)
@@ -165,4 +114,7 @@ func (e2d *HwEngine2d) EndLine(y int) {
 		b := uint8(pix>>10) & 0x1F
 		screen.Set32(i, e2d.masterBrightR[r]|e2d.masterBrightG[g]|e2d.masterBrightB[b])
 	}
+	if e2d.dump != nil {
+		e2d.dumpComposite(y, screen)
+	}
 }
@@ -0,0 +1,129 @@
+package e2d
+
+import (
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestMasterBright(t *testing.T) {
+	for _, tc := range []struct {
+		mbright uint32
+		want    uint32 // 8-bit output of a 5-bit channel with value 16
+	}{
+		{0, 134},          // no effect
+		{1<<14 | 8, 195},  // up
+		{2<<14 | 8, 69},   // down
+		{2<<14 | 31, 0},   // down, factor clamped to 16
+		{1<<14 | 16, 255}, // up, full
+		{3<<14 | 16, 134}, // reserved mode: no effect
+	} {
+		e2d := NewHwEngine2d(1, newTestMemCtrl(), nil)
+		e2d.MBright.Write32(0, tc.mbright)
+
+		screen := gfx.NewLine(make([]byte, cScreenWidth*4))
+		e2d.BeginLine(0, screen) // display off (white), updates the tables
+		screen.Set32(0, 16|16<<5|16<<10)
+		e2d.EndLine(0)
+
+		want := tc.want | tc.want<<8 | tc.want<<16
+		if pix := screen.Get32(0); pix != want {
+			t.Errorf("mbright=%04x: got %06x, want %06x", tc.mbright, pix, want)
+		}
+	}
+}
+
+func TestEngineBDispCnt(t *testing.T) {
+	// BG0 3D, display mode 2, VRAM block 3, bitmap OBJ 256 bytes boundary,
+	// char/screen base offsets; and the bits available on both engines
+	const onlyA = 1<<3 | 2<<16 | 3<<18 | 1<<22 | 7<<24 | 7<<27
+	const common = 0xC0B1FFF7
+
+	a := NewHwEngine2d(0, newTestMemCtrl(), nil)
+	a.DispCnt.Write32(0, 0xFFFFFFFF)
+	if a.DispCnt.Value != onlyA|common {
+		t.Errorf("engine A: DISPCNT=%08x, want %08x", a.DispCnt.Value, uint32(onlyA|common))
+	}
+
+	b := NewHwEngine2d(1, newTestMemCtrl(), nil)
+	b.DispCnt.Write32(0, 0xFFFFFFFF)
+	if b.DispCnt.Value != common {
+		t.Errorf("engine B: DISPCNT=%08x, want %08x", b.DispCnt.Value, uint32(common))
+	}
+	if modes, _ := bgLayerModes(b.DispCnt.Value, false, [4]uint16{}); modes[0] == BgMode3D {
+		t.Errorf("engine B: BG0 is the 3D layer")
+	}
+}
+
+func TestVramPoolSize(t *testing.T) {
+	for _, tc := range []struct {
+		engine int
+		which  VramLinearBankId
+		want   int
+	}{
+		{0, VramLinearBG, 512 * 1024},
+		{0, VramLinearOAM, 256 * 1024},
+		{1, VramLinearBG, 128 * 1024},
+		{1, VramLinearOAM, 128 * 1024},
+	} {
+		if got := VramPoolSize(tc.engine, tc.which); got != tc.want {
+			t.Errorf("engine %d, pool %d: got %dK, want %dK", tc.engine, tc.which, got/1024, tc.want/1024)
+		}
+	}
+}
+
+func TestMidFrameModes(t *testing.T) {
+	mc := newTestMemCtrl()
+	e2d := NewHwEngine2d(1, mc, nil)
+
+	// Mode 1, BG0 only, filled with red (see TestDumpFrame)
+	const bg0 = 1<<16 | 1<<8
+	e2d.DispCnt.Value = bg0
+	for i := range mc.vram.Ptr {
+		for j := range mc.vram.Ptr[i] {
+			mc.vram.Ptr[i][j] = 0x11
+		}
+	}
+	mc.pal[17*2] = 0x1F
+
+	screen := gfx.NewBufferMem(cScreenWidth, cScreenHeight)
+	e2d.BeginFrame()
+	for y := 0; y < cScreenHeight; y++ {
+		switch y {
+		case 64:
+			e2d.DispCnt.Value = 0 // display off
+		case 128:
+			e2d.DispCnt.Value = bg0 | 1 // back to BG/OBJ, BG mode 1
+		}
+		e2d.BeginLine(y, screen.Line(y))
+		e2d.EndLine(y)
+	}
+	e2d.EndFrame()
+
+	red, white := screen.Line(0).Get32(0), screen.Line(64).Get32(0)
+	if red == white {
+		t.Fatalf("display mode not switched: %06x", red)
+	}
+	for _, y := range []int{63, 128, 191} {
+		if pix := screen.Line(y).Get32(100); pix != red {
+			t.Errorf("line %d: got %06x, want %06x", y, pix, red)
+		}
+	}
+	if pix := screen.Line(127).Get32(100); pix != white {
+		t.Errorf("line 127: got %06x, want %06x", pix, white)
+	}
+	if e2d.bgmodes[3] != BgModeAffine {
+		t.Errorf("BG mode not switched: BG3 is %v", e2d.bgmodes[3])
+	}
+
+	// A new frame starts in the mode of its first line
+	e2d.DispCnt.Value = bg0
+	e2d.BeginFrame()
+	for y := 0; y < cScreenHeight; y++ {
+		e2d.BeginLine(y, screen.Line(y))
+		e2d.EndLine(y)
+	}
+	e2d.EndFrame()
+	if pix := screen.Line(100).Get32(100); pix != red || e2d.bgmodes[3] != BgModeText {
+		t.Errorf("second frame: got %06x (BG3 %v)", pix, e2d.bgmodes[3])
+	}
+}
@@ -37,6 +37,21 @@ const (
 	VramLinearOBJExtPal
 )
 
+// VramPoolSize returns the size of the BG or OBJ VRAM that can be accessed by
+// the specified engine (see VramLinearBank): 512K/256K for engine A, 128K for
+// engine B. The address space of each pool is larger, and it is mirrored
+// every VramPoolSize bytes.
+func VramPoolSize(engine int, which VramLinearBankId) int {
+	switch {
+	case engine != 0:
+		return 128 * 1024
+	case which == VramLinearBG:
+		return 512 * 1024
+	default:
+		return 256 * 1024
+	}
+}
+
 type MemoryController interface {
 
 	// Get access to the palette RAM for the specified engine
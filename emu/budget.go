@@ -0,0 +1,57 @@
+package emu
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// FrameBudget tracks the host time available to emulate the current frame.
+// Expensive optional work (debug views, on-screen display, texture dumping,
+// etc.) can query it to skip a frame's worth of work when the emulation is
+// running late, rather than making it even later.
+//
+// The frontend calls Start before emulating each frame; the budget can then
+// be queried from any goroutine.
+type FrameBudget struct {
+	deadline int64 // atomic; host time (see now) at which the frame should be completed
+
+	// Returns the current host time in nanoseconds; can be overridden for
+	// testing
+	now func() int64
+}
+
+var hostEpoch = time.Now()
+
+func (b *FrameBudget) hostNow() int64 {
+	if b.now != nil {
+		return b.now()
+	}
+	return int64(time.Since(hostEpoch))
+}
+
+// Start begins a new frame, that should be emulated within the specified
+// host time. If the previous frame was completed ahead of its deadline, the
+// spare time is carried over; if it was late, the delay is not recovered, so
+// that a single slow frame doesn't cause optional work to be skipped for
+// several frames in a row.
+func (b *FrameBudget) Start(period time.Duration) {
+	now := b.hostNow()
+	deadline := atomic.LoadInt64(&b.deadline)
+	if deadline < now {
+		deadline = now
+	}
+	atomic.StoreInt64(&b.deadline, deadline+int64(period))
+}
+
+// Remaining returns the host time left before the current frame should be
+// completed. It is negative if the frame is already late. If Start was never
+// called, the budget is considered exhausted.
+func (b *FrameBudget) Remaining() time.Duration {
+	return time.Duration(atomic.LoadInt64(&b.deadline) - b.hostNow())
+}
+
+// Allow returns true if there is enough time left in the current frame to
+// perform optional work of the specified (estimated) cost.
+func (b *FrameBudget) Allow(cost time.Duration) bool {
+	return b.Remaining() >= cost
+}
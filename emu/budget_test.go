@@ -0,0 +1,39 @@
+package emu
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameBudget(t *testing.T) {
+	now := int64(100 * time.Millisecond)
+	b := FrameBudget{now: func() int64 { return now }}
+
+	if b.Allow(0) {
+		t.Errorf("budget available before start")
+	}
+
+	// Frame completed early: spare time is carried over
+	b.Start(16 * time.Millisecond)
+	now += int64(10 * time.Millisecond)
+	if r := b.Remaining(); r != 6*time.Millisecond {
+		t.Errorf("invalid remaining time: %v", r)
+	}
+	b.Start(16 * time.Millisecond)
+	if r := b.Remaining(); r != 22*time.Millisecond {
+		t.Errorf("invalid remaining time: %v", r)
+	}
+	if !b.Allow(20*time.Millisecond) || b.Allow(30*time.Millisecond) {
+		t.Errorf("invalid allow")
+	}
+
+	// Frame completed late: the delay is not recovered
+	now += int64(40 * time.Millisecond)
+	if r := b.Remaining(); r != -18*time.Millisecond {
+		t.Errorf("invalid remaining time: %v", r)
+	}
+	b.Start(16 * time.Millisecond)
+	if r := b.Remaining(); r != 16*time.Millisecond {
+		t.Errorf("invalid remaining time: %v", r)
+	}
+}
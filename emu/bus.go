@@ -14,3 +14,20 @@ type Bus interface {
 
 	FetchPointer(address uint32) []uint8
 }
+
+// AccessTimings are the cycles taken by accesses to a memory region, for
+// non-sequential (N) and sequential (S) accesses of 16 and 32 bits. 8-bit
+// accesses take as long as 16-bit ones.
+type AccessTimings struct {
+	N16, S16 int
+	N32, S32 int
+}
+
+// A TimedBus is a Bus that also describes the timings of its memory regions,
+// with a granularity of 16MB (that is, they're indexed by the top 8 bits of
+// the address). The returned table can be modified later by the bus owner
+// (eg: when waitstates are reconfigured), so it should not be copied.
+type TimedBus interface {
+	Bus
+	RegionTimings() *[256]AccessTimings
+}
@@ -225,8 +225,13 @@ func (g *Generator) WriteDisasm(opcode string, args ...string) {
 			fmt.Fprintf(&g.Disasm, "} else {\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(\"[\")\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(RegNames[%sa])\n", tmpname)
+			fmt.Fprintf(&g.Disasm, "if int64(%sb) < 0 {\n", tmpname)
+			fmt.Fprintf(&g.Disasm, "out.WriteString(\", #-0x\")\n")
+			fmt.Fprintf(&g.Disasm, "out.WriteString(strconv.FormatInt(-int64(%sb), 16))\n", tmpname)
+			fmt.Fprintf(&g.Disasm, "} else {\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(\", #0x\")\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(strconv.FormatInt(int64(%sb), 16))\n", tmpname)
+			fmt.Fprintf(&g.Disasm, "}\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(\"]\")\n")
 			if wb {
 				fmt.Fprintf(&g.Disasm, "out.WriteString(\"!\")\n")
@@ -260,7 +265,13 @@ func (g *Generator) WriteDisasm(opcode string, args ...string) {
 			fmt.Fprintf(&g.Disasm, "out.WriteString(\"= 0x\")\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(strconv.FormatInt(int64(%sv), 16))\n", tmpname)
 		case "k:":
-			// register bitmask
+			// register bitmask (with optional "^" suffix, eg: ARM LDM/STM
+			// with user bank or CPSR transfer)
+			caret := false
+			if strings.HasSuffix(a, ":^") {
+				caret = true
+				a = a[:len(a)-2]
+			}
 			fmt.Fprintf(&g.Disasm, "%s:=%s\n", tmpname, a[2:])
 			fmt.Fprintf(&g.Disasm, "out.WriteString(\"{\")\n")
 			fmt.Fprintf(&g.Disasm, "for i:=0;%s!=0;i++ {\n", tmpname)
@@ -273,6 +284,9 @@ func (g *Generator) WriteDisasm(opcode string, args ...string) {
 			fmt.Fprintf(&g.Disasm, "  }\n")
 			fmt.Fprintf(&g.Disasm, "}\n")
 			fmt.Fprintf(&g.Disasm, "out.WriteString(\"}\")\n")
+			if caret {
+				fmt.Fprintf(&g.Disasm, "out.WriteString(\"^\")\n")
+			}
 		case "o:":
 			// PC offset (signed)
 			fmt.Fprintf(&g.Disasm, "%s:=int32(%s)\n", tmpname, a[2:])
@@ -296,6 +310,49 @@ func (g *Generator) WriteDisasm(opcode string, args ...string) {
 	fmt.Fprintf(&g.Disasm, "return out.String()\n")
 }
 
+// OpDesc is a declarative description of a group of opcodes. The group is
+// identified by a mask/value pair over the bits of the opcode that are used
+// to index the dispatch table; Gen is invoked to write both the execution
+// and the disassembly code for all opcodes in the group.
+//
+// A decoding table is a slice of OpDesc, where entries are evaluated in
+// order and the first match wins; this allows to express special-cases
+// (eg: BX within the ALU space) by simply listing them first.
+type OpDesc struct {
+	Name  string
+	Mask  int
+	Value int
+	Gen   func(op uint32)
+}
+
+func (d *OpDesc) Match(idx int) bool {
+	return idx&d.Mask == d.Value
+}
+
+// WriteOpFromTable generates the handler for the table index idx, using the
+// first matching entry within ops. op is the opcode value passed to the Gen
+// function (that is, idx expanded into a full opcode).
+func (g *Generator) WriteOpFromTable(ops []OpDesc, idx int, op uint32) {
+	g.WriteOpHeader(idx)
+	found := false
+	for i := range ops {
+		d := &ops[i]
+		if d.Value&^d.Mask != 0 || d.Mask >= 1<<g.TableBits {
+			panic(fmt.Errorf("invalid opcode description: %s", d.Name))
+		}
+		if d.Match(idx) {
+			d.Gen(op)
+			found = true
+			break
+		}
+	}
+	if !found {
+		g.WriteOpInvalid("not implemented")
+		g.WriteDisasmInvalid()
+	}
+	g.WriteOpFooter(idx)
+}
+
 func Main(do func(g *Generator)) {
 	flag.Parse()
 
@@ -66,6 +66,16 @@ type Debugger struct {
 	breakch chan string
 
 	log *logReader
+
+	// Additional views that can be displayed in place of the logging
+	// panel (see AddView); curview is 0 for the logging panel.
+	views   []view
+	curview int
+}
+
+type view struct {
+	name  string
+	lines func(n int) []string
 }
 
 type dbgForCpu struct {
@@ -334,6 +344,13 @@ func (dbg *Debugger) Run() {
 		}
 	})
 
+	ui.Handle("/sys/kbd/v", func(ui.Event) {
+		if !dbg.running[dbg.curcpu] {
+			dbg.curview = (dbg.curview + 1) % (len(dbg.views) + 1)
+			dbg.refreshUi()
+		}
+	})
+
 	ui.Handle("/sys/kbd/q", func(ui.Event) {
 		dbg.stopMonitored()
 		ui.StopLoop()
@@ -348,6 +365,14 @@ func (dbg *Debugger) Run() {
 	ui.Loop()
 }
 
+// AddView adds a view that can be displayed in place of the logging panel
+// (views are cycled with "v"), to show emulator-specific state. lines is
+// called while the emulation is stopped, and must return at most n lines.
+// Views must be added before calling Run.
+func (dbg *Debugger) AddView(name string, lines func(n int) []string) {
+	dbg.views = append(dbg.views, view{name, lines})
+}
+
 func (dbg *Debugger) AddBreakpoint(pc uint32) {
 	dbg.userBkps = append(dbg.userBkps, pc)
 }
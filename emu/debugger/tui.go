@@ -154,7 +154,14 @@ func (dbg *Debugger) refreshCall() {
 }
 
 func (dbg *Debugger) refreshLog() {
-	dbg.uiLog.Items = dbg.log.Lines()
+	if dbg.curview == 0 {
+		dbg.uiLog.BorderLabel = "Logging"
+		dbg.uiLog.Items = dbg.log.Lines()
+		return
+	}
+	v := dbg.views[dbg.curview-1]
+	dbg.uiLog.BorderLabel = v.name
+	dbg.uiLog.Items = v.lines(dbg.uiLog.Height - 2)
 }
 
 func (dbg *Debugger) refreshUi() {
@@ -13,8 +13,7 @@ func NewFixed12(val int32) Fixed12 {
 func newFixed12FromInt64(val int64) Fixed12 {
 	val32 := int32(val)
 	if int64(val32) != val {
-		fmt.Printf("%v %x\n", val, val)
-		panic("fixed point overflow")
+		panic(fmt.Sprintf("fixed point overflow: %x", val))
 	}
 	return Fixed12{val32}
 }
@@ -24,7 +23,8 @@ func (f Fixed12) ToFloat64() float64 {
 }
 
 func (f Fixed12) NearInt32() int32 {
-	return (f.V + (1 << 11)) >> 12
+	// Computed on 64 bits to avoid overflowing on the largest values
+	return int32((int64(f.V) + (1 << 11)) >> 12)
 }
 
 func (f Fixed12) TruncInt32() int32 {
@@ -0,0 +1,216 @@
+package emu
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// Fixed-point arithmetic is checked against a big.Int reference, which
+// cannot overflow. The semantics to verify are those of the hardware:
+//
+//   - multiplications are computed at full precision, and the result is
+//     shifted right, that is truncated towards negative infinity (like the
+//     geometry engine matrix unit and the 2D affine units);
+//   - divisions truncate towards zero (like the DIV unit);
+//   - rounding to integer rounds halves towards positive infinity.
+
+func refMul(a, b int64, shift uint) *big.Int {
+	r := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	return r.Rsh(r, shift)
+}
+
+func refDiv(a, b int64, shift uint) *big.Int {
+	r := new(big.Int).Lsh(big.NewInt(a), shift)
+	return r.Quo(r, big.NewInt(b))
+}
+
+func refNear(a int64, shift uint) *big.Int {
+	r := big.NewInt(a)
+	r.Add(r, big.NewInt(1<<(shift-1)))
+	return r.Rsh(r, shift)
+}
+
+func fitsInt32(r *big.Int) bool {
+	return r.IsInt64() && r.Int64() >= math.MinInt32 && r.Int64() <= math.MaxInt32
+}
+
+func panics(f func()) (p bool) {
+	defer func() {
+		p = recover() != nil
+	}()
+	f()
+	return
+}
+
+// Interesting values around the boundaries of integer and fractional parts
+var fixedEdges = []int32{
+	0, 1, 0x7FF, 0x800, 0x801, 0xFFF, 0x1000, 0x1001, 0x1800,
+	0x7FFF, 0x8000, 0xFFFF, 0x10000, 0x7FFFFF, 0x800000,
+	0x7FFFFFFF, 0x40000000,
+}
+
+func fixedValues() []int32 {
+	var vals []int32
+	for _, v := range fixedEdges {
+		vals = append(vals, v, -v)
+	}
+	return append(vals, math.MinInt32)
+}
+
+func checkFixed12(t *testing.T, a, b int32) {
+	fa, fb := Fixed12{a}, Fixed12{b}
+
+	if exp := refMul(int64(a), int64(b), 12); !fitsInt32(exp) {
+		if !panics(func() { fa.MulFixed(fb) }) {
+			t.Errorf("%08x.MulFixed(%08x): overflow not detected", a, b)
+		}
+	} else if got := fa.MulFixed(fb); int64(got.V) != exp.Int64() {
+		t.Errorf("%08x.MulFixed(%08x): got %08x, want %08x", a, b, got.V, exp.Int64())
+	}
+
+	if b != 0 {
+		if exp := refDiv(int64(a), int64(b), 12); !fitsInt32(exp) {
+			if !panics(func() { fa.DivFixed(fb) }) {
+				t.Errorf("%08x.DivFixed(%08x): overflow not detected", a, b)
+			}
+		} else if got := fa.DivFixed(fb); int64(got.V) != exp.Int64() {
+			t.Errorf("%08x.DivFixed(%08x): got %08x, want %08x", a, b, got.V, exp.Int64())
+		}
+
+		// Div is a plain integer division, wrapping on overflow
+		exp := int32(refDiv(int64(a), int64(b), 0).Int64())
+		if got := fa.Div(b); got.V != exp {
+			t.Errorf("%08x.Div(%d): got %08x, want %08x", a, b, got.V, exp)
+		}
+	}
+
+	if exp := refNear(int64(a), 12); fa.NearInt32() != int32(exp.Int64()) {
+		t.Errorf("%08x.NearInt32(): got %d, want %d", a, fa.NearInt32(), exp.Int64())
+	}
+	if exp := a >> 12; fa.TruncInt32() != exp {
+		t.Errorf("%08x.TruncInt32(): got %d, want %d", a, fa.TruncInt32(), exp)
+	}
+}
+
+func checkFixed8(t *testing.T, a, b int32) {
+	fa, fb := Fixed8{int64(a)}, Fixed8{int64(b)}
+
+	if exp := refMul(int64(a), int64(b), 8); fa.MulFixed(fb).v != exp.Int64() {
+		t.Errorf("%08x.MulFixed(%08x): got %x, want %x", a, b, fa.MulFixed(fb).v, exp.Int64())
+	}
+	if b != 0 {
+		if exp := refDiv(int64(a), int64(b), 8); fa.DivFixed(fb).v != exp.Int64() {
+			t.Errorf("%08x.DivFixed(%08x): got %x, want %x", a, b, fa.DivFixed(fb).v, exp.Int64())
+		}
+		if exp := refDiv(int64(a), int64(b), 0); fa.Div(int64(b)).v != exp.Int64() {
+			t.Errorf("%08x.Div(%d): got %x, want %x", a, b, fa.Div(int64(b)).v, exp.Int64())
+		}
+	}
+	if exp := refNear(int64(a), 8); fa.ToInt64() != exp.Int64() {
+		t.Errorf("%08x.ToInt64(): got %d, want %d", a, fa.ToInt64(), exp.Int64())
+	}
+}
+
+func TestFixed12Vectors(t *testing.T) {
+	for _, tc := range []struct {
+		op        string
+		a, b, res int32
+	}{
+		{"mul", 0x1000, 0x1000, 0x1000},   // 1 * 1 = 1
+		{"mul", 0x1800, 0x1800, 0x2400},   // 1.5 * 1.5 = 2.25
+		{"mul", 0x0001, 0x0800, 0x0000},   // 1/4096 * 0.5 truncates to 0...
+		{"mul", -0x0001, 0x0800, -0x0001}, // ...but -1/4096 * 0.5 to -1/4096
+		{"mul", -0x1000, 0x0001, -0x0001}, // -1 * 1/4096
+		{"mul", 0x7FFF, 0x7FFF, 0x3FFF0},  // max 16-bit coordinates
+		{"div", 0x1000, 0x3000, 0x0555},   // 1/3
+		{"div", -0x1000, 0x3000, -0x0555}, // -1/3 truncates towards zero
+		{"div", 0x3000, -0x0800, -0x6000}, // 3 / -0.5
+		{"div", 0x0001, 0x2000, 0x0000},   // 1/4096 / 2
+		{"near", 0x0800, 0, 1},            // 0.5 rounds up...
+		{"near", -0x0800, 0, 0},           // ...and so does -0.5
+		{"near", -0x0801, 0, -1},
+		{"near", 0x7FFFFFFF, 0, 0x80000}, // no overflow
+	} {
+		a, b := Fixed12{tc.a}, Fixed12{tc.b}
+		var got int32
+		switch tc.op {
+		case "mul":
+			got = a.MulFixed(b).V
+		case "div":
+			got = a.DivFixed(b).V
+		case "near":
+			got = a.NearInt32()
+		}
+		if got != tc.res {
+			t.Errorf("%s(%08x,%08x): got %08x, want %08x", tc.op, tc.a, tc.b, got, tc.res)
+		}
+	}
+}
+
+func TestFixed12Lerp(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, ratio, res int32
+	}{
+		{0x1000, 0x3000, 0x0800, 0x2000}, // 1 + (3-1)*0.5 = 2
+		{0x3000, 0x1000, 0x0400, 0x2800}, // 3 + (1-3)*0.25 = 2.5
+		{0x0000, 0x0003, 0x0800, 0x0001}, // 0 + 3/4096*0.5, truncated
+		{0x0003, 0x0000, 0x0800, 0x0001}, // 3/4096 - 3/4096*0.5, truncated
+		{0x1000, 0x3000, 0x1000, 0x3000}, // ratio=1 gives the endpoint
+	} {
+		if got := (Fixed12{tc.a}).Lerp(Fixed12{tc.b}, Fixed12{tc.ratio}); got.V != tc.res {
+			t.Errorf("lerp(%08x,%08x,%08x): got %08x, want %08x", tc.a, tc.b, tc.ratio, got.V, tc.res)
+		}
+	}
+}
+
+func TestFixed12Edges(t *testing.T) {
+	vals := fixedValues()
+	for _, a := range vals {
+		for _, b := range vals {
+			checkFixed12(t, a, b)
+		}
+	}
+}
+
+func TestFixed12Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		// Mix full-range values with values in the range of 16-bit
+		// coordinates, which don't overflow
+		a, b := int32(r.Uint32()), int32(r.Uint32())
+		if i&1 != 0 {
+			a, b = int32(int16(a)), int32(int16(b))
+		}
+		checkFixed12(t, a, b)
+	}
+}
+
+func TestFixed8Edges(t *testing.T) {
+	vals := fixedValues()
+	for _, a := range vals {
+		for _, b := range vals {
+			checkFixed8(t, a, b)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		checkFixed8(t, int32(r.Uint32()), int32(r.Uint32()))
+	}
+}
+
+func FuzzFixed12(f *testing.F) {
+	for _, v := range fixedEdges {
+		f.Add(v, -v)
+	}
+	f.Fuzz(checkFixed12)
+}
+
+func FuzzFixed8(f *testing.F) {
+	for _, v := range fixedEdges {
+		f.Add(v, -v)
+	}
+	f.Fuzz(checkFixed8)
+}
@@ -1,151 +1,141 @@
-// Generated on 2016-01-10 15:36:52.822465008 +0100 CET
+// Generated on 2026-10-16 15:50:31.025002862 +0000 UTC
 package gfx
 
-func (lm *LayerManager) fastmixer_1_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_1_8_8(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		out := mix(in, mixctx)
 		screen.Set8(x, uint8(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_1_16_8(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		out := mix(in, mixctx)
 		screen.Set8(x, uint8(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_1_32_8(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		out := mix(in, mixctx)
 		screen.Set8(x, uint8(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_1_8_16(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		out := mix(in, mixctx)
 		screen.Set16(x, uint16(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_1_16_16(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		out := mix(in, mixctx)
 		screen.Set16(x, uint16(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_1_32_16(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		out := mix(in, mixctx)
 		screen.Set16(x, uint16(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_1_8_32(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		out := mix(in, mixctx)
 		screen.Set32(x, uint32(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_1_16_32(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		out := mix(in, mixctx)
 		screen.Set32(x, uint32(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_1_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_1_32_32(screen Line, x0, x1 int) {
 	var inbuf [1]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		out := mix(in, mixctx)
 		screen.Set32(x, uint32(out))
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_2_8_8(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		out := mix(in, mixctx)
@@ -153,16 +143,15 @@ func (lm *LayerManager) fastmixer_2_8_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_2_16_8(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		out := mix(in, mixctx)
@@ -170,16 +159,15 @@ func (lm *LayerManager) fastmixer_2_16_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_2_32_8(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		out := mix(in, mixctx)
@@ -187,16 +175,15 @@ func (lm *LayerManager) fastmixer_2_32_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_2_8_16(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		out := mix(in, mixctx)
@@ -204,16 +191,15 @@ func (lm *LayerManager) fastmixer_2_8_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_2_16_16(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		out := mix(in, mixctx)
@@ -221,16 +207,15 @@ func (lm *LayerManager) fastmixer_2_16_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_2_32_16(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		out := mix(in, mixctx)
@@ -238,16 +223,15 @@ func (lm *LayerManager) fastmixer_2_32_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_2_8_32(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		out := mix(in, mixctx)
@@ -255,16 +239,15 @@ func (lm *LayerManager) fastmixer_2_8_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_2_16_32(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		out := mix(in, mixctx)
@@ -272,16 +255,15 @@ func (lm *LayerManager) fastmixer_2_16_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_2_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_2_32_32(screen Line, x0, x1 int) {
 	var inbuf [2]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		out := mix(in, mixctx)
@@ -289,17 +271,16 @@ func (lm *LayerManager) fastmixer_2_32_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_3_8_8(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -308,17 +289,16 @@ func (lm *LayerManager) fastmixer_3_8_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_3_16_8(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -327,17 +307,16 @@ func (lm *LayerManager) fastmixer_3_16_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_3_32_8(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -346,17 +325,16 @@ func (lm *LayerManager) fastmixer_3_32_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_3_8_16(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -365,17 +343,16 @@ func (lm *LayerManager) fastmixer_3_8_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_3_16_16(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -384,17 +361,16 @@ func (lm *LayerManager) fastmixer_3_16_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_3_32_16(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -403,17 +379,16 @@ func (lm *LayerManager) fastmixer_3_32_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_3_8_32(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -422,17 +397,16 @@ func (lm *LayerManager) fastmixer_3_8_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_3_16_32(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -441,17 +415,16 @@ func (lm *LayerManager) fastmixer_3_16_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_3_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_3_32_32(screen Line, x0, x1 int) {
 	var inbuf [3]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
 	l0 := NewLine(lm.layers[lm.PriorityOrder[0]].linebuf[off0:])
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -460,10 +433,9 @@ func (lm *LayerManager) fastmixer_3_32_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_4_8_8(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -471,7 +443,7 @@ func (lm *LayerManager) fastmixer_4_8_8(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -481,10 +453,9 @@ func (lm *LayerManager) fastmixer_4_8_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_4_16_8(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -492,7 +463,7 @@ func (lm *LayerManager) fastmixer_4_16_8(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -502,10 +473,9 @@ func (lm *LayerManager) fastmixer_4_16_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_4_32_8(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -513,7 +483,7 @@ func (lm *LayerManager) fastmixer_4_32_8(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -523,10 +493,9 @@ func (lm *LayerManager) fastmixer_4_32_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_4_8_16(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -534,7 +503,7 @@ func (lm *LayerManager) fastmixer_4_8_16(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -544,10 +513,9 @@ func (lm *LayerManager) fastmixer_4_8_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_4_16_16(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -555,7 +523,7 @@ func (lm *LayerManager) fastmixer_4_16_16(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -565,10 +533,9 @@ func (lm *LayerManager) fastmixer_4_16_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_4_32_16(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -576,7 +543,7 @@ func (lm *LayerManager) fastmixer_4_32_16(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -586,10 +553,9 @@ func (lm *LayerManager) fastmixer_4_32_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_4_8_32(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -597,7 +563,7 @@ func (lm *LayerManager) fastmixer_4_8_32(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -607,10 +573,9 @@ func (lm *LayerManager) fastmixer_4_8_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_4_16_32(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -618,7 +583,7 @@ func (lm *LayerManager) fastmixer_4_16_32(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -628,10 +593,9 @@ func (lm *LayerManager) fastmixer_4_16_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_4_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_4_32_32(screen Line, x0, x1 int) {
 	var inbuf [4]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -639,7 +603,7 @@ func (lm *LayerManager) fastmixer_4_32_32(screen Line) {
 	l1 := NewLine(lm.layers[lm.PriorityOrder[1]].linebuf[off0:])
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -649,10 +613,9 @@ func (lm *LayerManager) fastmixer_4_32_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_5_8_8(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -661,7 +624,7 @@ func (lm *LayerManager) fastmixer_5_8_8(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -672,10 +635,9 @@ func (lm *LayerManager) fastmixer_5_8_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_5_16_8(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -684,7 +646,7 @@ func (lm *LayerManager) fastmixer_5_16_8(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -695,10 +657,9 @@ func (lm *LayerManager) fastmixer_5_16_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_5_32_8(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -707,7 +668,7 @@ func (lm *LayerManager) fastmixer_5_32_8(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -718,10 +679,9 @@ func (lm *LayerManager) fastmixer_5_32_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_5_8_16(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -730,7 +690,7 @@ func (lm *LayerManager) fastmixer_5_8_16(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -741,10 +701,9 @@ func (lm *LayerManager) fastmixer_5_8_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_5_16_16(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -753,7 +712,7 @@ func (lm *LayerManager) fastmixer_5_16_16(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -764,10 +723,9 @@ func (lm *LayerManager) fastmixer_5_16_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_5_32_16(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -776,7 +734,7 @@ func (lm *LayerManager) fastmixer_5_32_16(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -787,10 +745,9 @@ func (lm *LayerManager) fastmixer_5_32_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_5_8_32(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -799,7 +756,7 @@ func (lm *LayerManager) fastmixer_5_8_32(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -810,10 +767,9 @@ func (lm *LayerManager) fastmixer_5_8_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_5_16_32(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -822,7 +778,7 @@ func (lm *LayerManager) fastmixer_5_16_32(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -833,10 +789,9 @@ func (lm *LayerManager) fastmixer_5_16_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_5_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_5_32_32(screen Line, x0, x1 int) {
 	var inbuf [5]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -845,7 +800,7 @@ func (lm *LayerManager) fastmixer_5_32_32(screen Line) {
 	l2 := NewLine(lm.layers[lm.PriorityOrder[2]].linebuf[off0:])
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -856,10 +811,9 @@ func (lm *LayerManager) fastmixer_5_32_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_6_8_8(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -869,7 +823,7 @@ func (lm *LayerManager) fastmixer_6_8_8(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -881,10 +835,9 @@ func (lm *LayerManager) fastmixer_6_8_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_6_16_8(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -894,7 +847,7 @@ func (lm *LayerManager) fastmixer_6_16_8(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -906,10 +859,9 @@ func (lm *LayerManager) fastmixer_6_16_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_6_32_8(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -919,7 +871,7 @@ func (lm *LayerManager) fastmixer_6_32_8(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -931,10 +883,9 @@ func (lm *LayerManager) fastmixer_6_32_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_6_8_16(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -944,7 +895,7 @@ func (lm *LayerManager) fastmixer_6_8_16(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -956,10 +907,9 @@ func (lm *LayerManager) fastmixer_6_8_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_6_16_16(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -969,7 +919,7 @@ func (lm *LayerManager) fastmixer_6_16_16(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -981,10 +931,9 @@ func (lm *LayerManager) fastmixer_6_16_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_6_32_16(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -994,7 +943,7 @@ func (lm *LayerManager) fastmixer_6_32_16(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -1006,10 +955,9 @@ func (lm *LayerManager) fastmixer_6_32_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_6_8_32(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1019,7 +967,7 @@ func (lm *LayerManager) fastmixer_6_8_32(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -1031,10 +979,9 @@ func (lm *LayerManager) fastmixer_6_8_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_6_16_32(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1044,7 +991,7 @@ func (lm *LayerManager) fastmixer_6_16_32(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -1056,10 +1003,9 @@ func (lm *LayerManager) fastmixer_6_16_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_6_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_6_32_32(screen Line, x0, x1 int) {
 	var inbuf [6]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1069,7 +1015,7 @@ func (lm *LayerManager) fastmixer_6_32_32(screen Line) {
 	l3 := NewLine(lm.layers[lm.PriorityOrder[3]].linebuf[off0:])
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -1081,10 +1027,9 @@ func (lm *LayerManager) fastmixer_6_32_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_8_8(screen Line) {
+func (lm *LayerManager) fastmixer_7_8_8(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1095,7 +1040,7 @@ func (lm *LayerManager) fastmixer_7_8_8(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -1108,10 +1053,9 @@ func (lm *LayerManager) fastmixer_7_8_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_16_8(screen Line) {
+func (lm *LayerManager) fastmixer_7_16_8(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1122,7 +1066,7 @@ func (lm *LayerManager) fastmixer_7_16_8(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -1135,10 +1079,9 @@ func (lm *LayerManager) fastmixer_7_16_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_32_8(screen Line) {
+func (lm *LayerManager) fastmixer_7_32_8(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1149,7 +1092,7 @@ func (lm *LayerManager) fastmixer_7_32_8(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -1162,10 +1105,9 @@ func (lm *LayerManager) fastmixer_7_32_8(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_8_16(screen Line) {
+func (lm *LayerManager) fastmixer_7_8_16(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1176,7 +1118,7 @@ func (lm *LayerManager) fastmixer_7_8_16(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -1189,10 +1131,9 @@ func (lm *LayerManager) fastmixer_7_8_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_16_16(screen Line) {
+func (lm *LayerManager) fastmixer_7_16_16(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1203,7 +1144,7 @@ func (lm *LayerManager) fastmixer_7_16_16(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -1216,10 +1157,9 @@ func (lm *LayerManager) fastmixer_7_16_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_32_16(screen Line) {
+func (lm *LayerManager) fastmixer_7_32_16(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1230,7 +1170,7 @@ func (lm *LayerManager) fastmixer_7_32_16(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -1243,10 +1183,9 @@ func (lm *LayerManager) fastmixer_7_32_16(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_8_32(screen Line) {
+func (lm *LayerManager) fastmixer_7_8_32(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 1
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1257,7 +1196,7 @@ func (lm *LayerManager) fastmixer_7_8_32(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get8(x))
 		inbuf[1] = uint32(l1.Get8(x))
 		inbuf[2] = uint32(l2.Get8(x))
@@ -1270,10 +1209,9 @@ func (lm *LayerManager) fastmixer_7_8_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_16_32(screen Line) {
+func (lm *LayerManager) fastmixer_7_16_32(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 2
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1284,7 +1222,7 @@ func (lm *LayerManager) fastmixer_7_16_32(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = uint32(l0.Get16(x))
 		inbuf[1] = uint32(l1.Get16(x))
 		inbuf[2] = uint32(l2.Get16(x))
@@ -1297,10 +1235,9 @@ func (lm *LayerManager) fastmixer_7_16_32(screen Line) {
 	}
 }
 
-func (lm *LayerManager) fastmixer_7_32_32(screen Line) {
+func (lm *LayerManager) fastmixer_7_32_32(screen Line, x0, x1 int) {
 	var inbuf [7]uint32
 	in := inbuf[:]
-	width := lm.Cfg.Width
 	off0 := lm.Cfg.OverflowPixels * 4
 	mix := lm.Cfg.Mixer
 	mixctx := lm.Cfg.MixerCtx
@@ -1311,7 +1248,7 @@ func (lm *LayerManager) fastmixer_7_32_32(screen Line) {
 	l4 := NewLine(lm.layers[lm.PriorityOrder[4]].linebuf[off0:])
 	l5 := NewLine(lm.layers[lm.PriorityOrder[5]].linebuf[off0:])
 	l6 := NewLine(lm.layers[lm.PriorityOrder[6]].linebuf[off0:])
-	for x := 0; x < width; x++ {
+	for x := x0; x < x1; x++ {
 		inbuf[0] = l0.Get32(x)
 		inbuf[1] = l1.Get32(x)
 		inbuf[2] = l2.Get32(x)
@@ -1324,7 +1261,7 @@ func (lm *LayerManager) fastmixer_7_32_32(screen Line) {
 	}
 }
 
-var fastMixerTable = [128]func(*LayerManager, Line){
+var fastMixerTable = [128]func(*LayerManager, Line, int, int){
 	nil,
 	nil,
 	nil,
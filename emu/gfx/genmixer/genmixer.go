@@ -17,11 +17,10 @@ type Generator struct {
 }
 
 func (g *Generator) genMixer(nl int, obpp, ibpp int) {
-	fmt.Fprintf(g, "func (lm *LayerManager) fastmixer_%d_%d_%d(screen Line) {\n", nl, ibpp*8, obpp*8)
+	fmt.Fprintf(g, "func (lm *LayerManager) fastmixer_%d_%d_%d(screen Line, x0, x1 int) {\n", nl, ibpp*8, obpp*8)
 	fmt.Fprintf(g, "var inbuf [%d]uint32\n", nl)
 	fmt.Fprintf(g, "in := inbuf[:]\n")
 
-	fmt.Fprintf(g, "width := lm.Cfg.Width\n")
 	fmt.Fprintf(g, "off0 := lm.Cfg.OverflowPixels * %d\n", ibpp)
 	fmt.Fprintf(g, "mix := lm.Cfg.Mixer\n")
 	fmt.Fprintf(g, "mixctx := lm.Cfg.MixerCtx\n")
@@ -30,7 +29,7 @@ func (g *Generator) genMixer(nl int, obpp, ibpp int) {
 		fmt.Fprintf(g, "l%d := NewLine(lm.layers[lm.PriorityOrder[%d]].linebuf[off0:])\n", i, i)
 	}
 
-	fmt.Fprintf(g, "for x := 0; x < width; x++ {\n")
+	fmt.Fprintf(g, "for x := x0; x < x1; x++ {\n")
 	for i := 0; i < nl; i++ {
 		switch ibpp {
 		case 1:
@@ -73,7 +72,7 @@ func (g *Generator) Run() {
 		}
 	}
 
-	fmt.Fprintf(g, "var fastMixerTable = [%d]func(*LayerManager,Line) {\n",
+	fmt.Fprintf(g, "var fastMixerTable = [%d]func(*LayerManager,Line,int,int) {\n",
 		*maxlayers*16)
 	for i := 0; i < *maxlayers*16; i++ {
 		nl := i / 16
@@ -1,6 +1,9 @@
 package gfx
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 //go:generate go run genmixer/genmixer.go -filename fastmixer.go
 
@@ -69,6 +72,12 @@ type layerData struct {
 	linebuf []byte   // pixel buffer for this layer
 }
 
+// Goroutine running the draw routine of a layer, starting from line y
+func (l *layerData) run(idx int, y int) {
+	l.DrawLayer(&l.ctx, idx, y)
+	l.ctx.endLineCh <- false
+}
+
 type LayerManagerConfig struct {
 	// Size of the screen
 	Width, Height int
@@ -107,6 +116,12 @@ type LayerManagerConfig struct {
 	PostProc func(line Line, ctx interface{})
 
 	PostProcCtx interface{}
+
+	// Optional function called for each line after all the layers have
+	// drawn it, before they're mixed, with the line of each layer (in layer
+	// order, not priority order). This is meant for debugging (eg: to dump
+	// the contents of the layers), and can be changed between frames.
+	Snoop func(y int, layers []Line)
 }
 
 type LayerManager struct {
@@ -121,9 +136,53 @@ type LayerManager struct {
 	PriorityOrder []int
 
 	layers  []*layerData
+	lines   []Line // lines of the layers, for Cfg.Snoop
 	y       int
 	setupWg sync.WaitGroup
-	lineWg  sync.WaitGroup
+
+	// Lines are drawn by a goroutine that lives for the whole frame (see
+	// renderFrame), so that each layer manager (eg: each 2D engine) renders
+	// on its own goroutine, without spawning one for each line.
+	lineCh   chan Line
+	lineDone chan bool
+	drawing  bool // a line is being drawn (BeginLine was called)
+
+	// Mixing of the current line, when the layer manager is part of a
+	// MixGroup (see mixChunk).
+	group   *MixGroup
+	mixLine Line           // line being mixed
+	mixLeft int32          // chunks of mixLine not claimed yet (atomic)
+	mixWg   sync.WaitGroup // chunks of mixLine not mixed yet
+}
+
+// Number of chunks in which the mixing of a line is split, within a MixGroup
+const mixChunks = 4
+
+// A MixGroup is a set of layer managers (eg: the two 2D engines) that share
+// the work of mixing their lines. Each layer manager still draws its lines
+// on its own goroutine, but the mixing of each line is split into chunks,
+// which are offered to the group: the goroutines of the other layer managers
+// steal them while they are idle (eg: they are done with their own line), and
+// so does EndLine while waiting for the line. This evens out the load when
+// the engines are drawing a very different number of layers or sprites.
+//
+// Lines are not drawn ahead of the display: the layers read VRAM, palettes
+// and the registers latched at the beginning of each line directly, so a
+// deeper pipeline would require snapshotting all of that for each line.
+type MixGroup struct {
+	work chan *LayerManager // layer managers with chunks to be mixed
+}
+
+// Create a MixGroup out of the specified layer managers. A layer manager can
+// be part of only one group, and this must be done before the first frame.
+func NewMixGroup(lms ...*LayerManager) *MixGroup {
+	g := &MixGroup{
+		work: make(chan *LayerManager, mixChunks*len(lms)),
+	}
+	for _, lm := range lms {
+		lm.group = g
+	}
+	return g
 }
 
 func (lm *LayerManager) AddLayer(l Layer) int {
@@ -133,7 +192,17 @@ func (lm *LayerManager) AddLayer(l Layer) int {
 	return idx
 }
 
+// Replace a layer. This can also be done within a frame, between lines: the
+// new layer is started at the next line (see RestartDraw).
 func (lm *LayerManager) ChangeLayer(lidx int, l Layer) {
+	if lm.lineCh != nil {
+		if lm.y < 0 {
+			lm.setupWg.Wait()
+		}
+		lm.layers[lidx].Layer = l
+		lm.RestartDraw(lidx)
+		return
+	}
 	lm.layers[lidx] = &layerData{
 		Layer: l,
 		ctx: LayerCtx{
@@ -173,21 +242,43 @@ func (lm *LayerManager) BeginFrame() {
 		}
 	}
 
+	lm.lineCh = make(chan Line, 1)
+	lm.lineDone = make(chan bool, 1)
 	lm.setupWg.Add(1)
-	go func() {
-		lm.setupFrame()
-		lm.setupWg.Done()
-	}()
+	go lm.renderFrame(lm.lineCh, lm.lineDone)
+}
+
+// Goroutine that draws the lines of a frame: it does the initial setup of
+// the layers, and then draws each line sent by BeginLine, until EndFrame.
+// In between, it helps mixing the lines of the other layer managers of the
+// MixGroup.
+func (lm *LayerManager) renderFrame(lineCh <-chan Line, done chan<- bool) {
+	lm.setupFrame()
+	lm.setupWg.Done()
+
+	work := lm.groupWork()
+	for {
+		select {
+		case line, ok := <-lineCh:
+			if !ok {
+				return
+			}
+			lm.drawLine(line)
+			if lm.Cfg.PostProc != nil {
+				lm.Cfg.PostProc(line, lm.Cfg.PostProcCtx)
+			}
+			done <- true
+		case other := <-work:
+			other.mixChunk()
+		}
+	}
 }
 
 func (lm *LayerManager) setupFrame() {
 	buflen := (lm.Cfg.Width + lm.Cfg.OverflowPixels*2) * lm.Cfg.LayerBpp
 
 	for idx, l := range lm.layers {
-		go func(l *layerData, idx int) {
-			l.DrawLayer(&l.ctx, idx, 0)
-			l.ctx.endLineCh <- false
-		}(l, idx)
+		go l.run(idx, 0)
 
 		// Allocate the line buffer for this layer, if we haven't already
 		if len(l.linebuf) != buflen {
@@ -228,26 +319,115 @@ func (lm *LayerManager) drawLine(line Line) {
 	off0 := lm.Cfg.OverflowPixels * lm.Cfg.LayerBpp
 	for idx, l := range lm.layers {
 		if l.ctx.restart {
-			// Restart drawing on this layer (from the current line)
+			// Restart drawing on this layer (from the current line): wait
+			// for the current draw routine to exit before starting the new
+			// one, so that their signals on endLineCh don't get mixed up.
 			l.ctx.restart = false
-			l.ctx.nextLineCh <- Line{0}
-			go l.DrawLayer(&l.ctx, idx, lm.y)
+			l.ctx.nextLineCh <- Line{}
+			l.ctx.waitDead()
+			go l.run(idx, lm.y)
 			<-l.ctx.endLineCh
 		}
 		for i := range l.linebuf {
 			l.linebuf[i] = 0x0
 		}
-		l.ctx.nextLineCh <- NewLine(l.linebuf[off0:])
+		// Create the line over the whole buffer (including overflow pixels),
+		// so that bounds checking (in debug builds) allows accesses to them.
+		line := NewLine(l.linebuf)
+		line.Add8(off0)
+		l.ctx.nextLineCh <- line
+		if lm.Cfg.Snoop != nil {
+			lm.lines = append(lm.lines[:idx], line)
+		}
 	}
 
 	// Wait for each layer to finish its current line
 	for _, l := range lm.layers {
 		l.ctx.waitReady()
 	}
+	if lm.Cfg.Snoop != nil {
+		lm.Cfg.Snoop(lm.y, lm.lines)
+	}
 
 	// Now run the mixer
+	if lm.group == nil {
+		lm.mix(line, 0, lm.Cfg.Width)
+		return
+	}
+
+	// Publish the chunks of the line (the store makes the line visible to
+	// the other goroutines of the group) and offer them to the group; if
+	// the work queue is full, there's nobody idle anyway. Then mix the
+	// chunks nobody stole, and wait for the stolen ones.
+	lm.mixLine = line
+	lm.mixWg.Add(mixChunks)
+	atomic.StoreInt32(&lm.mixLeft, mixChunks)
+	for i := 1; i < mixChunks; i++ {
+		select {
+		case lm.group.work <- lm:
+		default:
+		}
+	}
+	for lm.mixChunk() {
+	}
+	lm.mixWg.Wait()
+}
+
+// Work queue of the MixGroup, or nil if the layer manager isn't part of one
+// (receiving from it then blocks forever).
+func (lm *LayerManager) groupWork() chan *LayerManager {
+	if lm.group == nil {
+		return nil
+	}
+	return lm.group.work
+}
+
+// Claim one of the chunks of the line being mixed, and mix it. It returns
+// false if there are no chunks left (eg: the entry of the work queue was
+// stale). This can be called by any goroutine: the state of the line can't
+// change until all its chunks are mixed, as drawLine waits for them.
+func (lm *LayerManager) mixChunk() bool {
+	// Check before claiming, so that the counter isn't decremented forever
+	// while the layer manager is idle (eg: the engine is disabled).
+	if atomic.LoadInt32(&lm.mixLeft) <= 0 {
+		return false
+	}
+	c := int(atomic.AddInt32(&lm.mixLeft, -1))
+	if c < 0 {
+		return false
+	}
+	w := (lm.Cfg.Width + mixChunks - 1) / mixChunks
+	x0, x1 := c*w, (c+1)*w
+	if x1 > lm.Cfg.Width {
+		x1 = lm.Cfg.Width
+	}
+	lm.mix(lm.mixLine, x0, x1)
+	lm.mixWg.Done()
+	return true
+}
+
+// Mix the pixels of the layers in the range [x0, x1) of the line
+func (lm *LayerManager) mix(line Line, x0, x1 int) {
 	idx := (len(lm.layers) << 4) | ((lm.Cfg.LayerBpp - 1) << 2) | (lm.Cfg.ScreenBpp - 1)
-	fastMixerTable[idx](lm, line)
+	fastMixerTable[idx](lm, line, x0, x1)
+}
+
+// Align the layer manager with the line y that the display is about to
+// draw. The layer manager normally just counts lines since BeginFrame; if
+// the next line isn't y (eg: some lines were not drawn through this layer
+// manager), all layers are restarted from line y, so that the output stays
+// aligned with the display timing. This must be called before BeginLine.
+func (lm *LayerManager) SyncLine(y int) {
+	if lm.y+1 == y {
+		return
+	}
+	if lm.y < 0 {
+		lm.setupWg.Wait()
+	}
+	lm.y = y - 1
+	for idx := range lm.layers {
+		lm.RestartDraw(idx)
+	}
 }
 
 // Begin drawing next line in background, onto the specified screen buffer
@@ -256,19 +436,22 @@ func (lm *LayerManager) BeginLine(line Line) {
 		lm.setupWg.Wait()
 	}
 	lm.y++
-	lm.lineWg.Add(1)
-	go func() {
-		lm.drawLine(line)
-		if lm.Cfg.PostProc != nil {
-			lm.Cfg.PostProc(line, lm.Cfg.PostProcCtx)
-		}
-		lm.lineWg.Done()
-	}()
+	lm.drawing = true
+	lm.lineCh <- line
 }
 
-// Wait for the current line to be fully drawn.
+// Wait for the current line to be fully drawn. Meanwhile, help mixing the
+// lines of the MixGroup, if any.
 func (lm *LayerManager) EndLine() {
-	lm.lineWg.Wait()
+	work := lm.groupWork()
+	for lm.drawing {
+		select {
+		case <-lm.lineDone:
+			lm.drawing = false
+		case other := <-work:
+			other.mixChunk()
+		}
+	}
 }
 
 // Force a restart of the draw routine of a layer. After calling this function,
@@ -279,13 +462,22 @@ func (lm *LayerManager) RestartDraw(layerIdx int) {
 	lm.layers[layerIdx].ctx.restart = true
 }
 
+// End the current frame. This is normally called after the last line, but
+// it can also be called earlier, if the remaining lines are not drawn
+// through the layer manager.
 func (lm *LayerManager) EndFrame() {
-	if lm.y != lm.Cfg.Height-1 {
-		panic("end frame called before all lines")
+	if lm.y >= lm.Cfg.Height {
+		panic("end frame called after too many lines")
+	}
+	if lm.y < 0 {
+		lm.setupWg.Wait()
 	}
+	lm.EndLine()
+	close(lm.lineCh)
+	lm.lineCh = nil
 
 	for _, l := range lm.layers {
-		l.ctx.nextLineCh <- Line{0}
+		l.ctx.nextLineCh <- Line{}
 		l.ctx.waitDead()
 	}
 }
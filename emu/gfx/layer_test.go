@@ -0,0 +1,166 @@
+package gfx
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLayerManagerSyncLine(t *testing.T) {
+	var lm LayerManager
+	lm.Cfg = LayerManagerConfig{
+		Width:     4,
+		Height:    8,
+		ScreenBpp: 4,
+		LayerBpp:  4,
+		Mixer:     func(pixels []uint32, ctx interface{}) uint32 { return pixels[0] },
+	}
+
+	// A layer that draws the line number it thinks it's drawing
+	lm.AddLayer(LayerFunc{Func: func(ctx *LayerCtx, lidx int, y int) {
+		for {
+			line := ctx.NextLine()
+			if line.IsNil() {
+				return
+			}
+			line.Set32(0, uint32(y))
+			y++
+		}
+	}})
+
+	screen := NewBufferMem(4, 8)
+	lm.BeginFrame()
+	for _, y := range []int{0, 1, 4, 5, 6, 7} {
+		lm.SyncLine(y)
+		lm.BeginLine(screen.Line(y))
+		lm.EndLine()
+		if got := screen.Line(y).Get32(0); got != uint32(y) {
+			t.Errorf("line %d: layer drew line %d", y, got)
+		}
+	}
+	lm.EndFrame()
+}
+
+func BenchmarkLayerManagerFrame(b *testing.B) {
+	var lm LayerManager
+	lm.Cfg = LayerManagerConfig{
+		Width:     256,
+		Height:    192,
+		ScreenBpp: 4,
+		LayerBpp:  4,
+		Mixer:     func(pixels []uint32, ctx interface{}) uint32 { return pixels[0] | pixels[1] },
+	}
+	for i := 0; i < 2; i++ {
+		lm.AddLayer(LayerFunc{Func: func(ctx *LayerCtx, lidx int, y int) {
+			for {
+				line := ctx.NextLine()
+				if line.IsNil() {
+					return
+				}
+				for x := 0; x < 256; x++ {
+					line.Set32(x, uint32(x+y))
+				}
+				y++
+			}
+		}})
+	}
+
+	screen := NewBufferMem(256, 192)
+	for i := 0; i < b.N; i++ {
+		lm.BeginFrame()
+		for y := 0; y < 192; y++ {
+			lm.BeginLine(screen.Line(y))
+			lm.EndLine()
+		}
+		lm.EndFrame()
+	}
+}
+
+func TestMixGroup(t *testing.T) {
+	// Two layer managers with a different number of layers, drawing lines
+	// concurrently like the two 2D engines. Each layer draws x+y*256+lidx.
+	var lms [2]LayerManager
+	var mixed [192]int32 // pixels mixed so far for each line of lms[1]
+	stalled := false
+	for i := range lms {
+		i := i
+		lm := &lms[i]
+		lm.Cfg = LayerManagerConfig{
+			Width:     256,
+			Height:    192,
+			ScreenBpp: 4,
+			LayerBpp:  4,
+			Mixer: func(pixels []uint32, ctx interface{}) uint32 {
+				var res uint32
+				for _, p := range pixels {
+					res += p
+				}
+				if i == 1 {
+					// Stall on the first pixel of the last chunk until the
+					// other chunks have been mixed: this only completes if
+					// they are stolen by other goroutines.
+					x, y := int(pixels[0]%256), int(pixels[0]/256)
+					if x == 192 {
+						deadline := time.Now().Add(time.Second)
+						for atomic.LoadInt32(&mixed[y]) < 192 && !stalled {
+							if time.Now().After(deadline) {
+								stalled = true
+							}
+							runtime.Gosched()
+						}
+					}
+					atomic.AddInt32(&mixed[y], 1)
+				}
+				return res
+			},
+		}
+		for l := 0; l < 2+i*3; l++ {
+			lm.AddLayer(LayerFunc{Func: func(ctx *LayerCtx, lidx int, y int) {
+				for {
+					line := ctx.NextLine()
+					if line.IsNil() {
+						return
+					}
+					for x := 0; x < 256; x++ {
+						line.Set32(x, uint32(x+y*256+lidx))
+					}
+					y++
+				}
+			}})
+		}
+	}
+	NewMixGroup(&lms[0], &lms[1])
+
+	screens := [2]Buffer{NewBufferMem(256, 192), NewBufferMem(256, 192)}
+	for i := range lms {
+		lms[i].BeginFrame()
+	}
+	for y := 0; y < 192; y++ {
+		for i := range lms {
+			lms[i].BeginLine(screens[i].Line(y))
+		}
+		for i := range lms {
+			lms[i].EndLine()
+		}
+	}
+	for i := range lms {
+		lms[i].EndFrame()
+	}
+	if stalled {
+		t.Errorf("chunks were not stolen")
+	}
+
+	for i := range lms {
+		nl := uint32(len(lms[i].layers))
+		for y := 0; y < 192; y++ {
+			line := screens[i].Line(y)
+			for x := 0; x < 256; x++ {
+				want := nl*uint32(x+y*256) + nl*(nl-1)/2
+				if got := line.Get32(x); got != want {
+					t.Fatalf("lm %d: pixel (%d,%d) = %d, want %d", i, x, y, got, want)
+				}
+			}
+		}
+	}
+}
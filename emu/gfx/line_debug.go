@@ -0,0 +1,36 @@
+//go:build gfxdebug
+// +build gfxdebug
+
+package gfx
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Debug is true when the package is built with the "gfxdebug" tag
+const Debug = true
+
+// In debug builds, a Line also tracks the bounds of the underlying buffer,
+// so that out-of-bounds accesses (usually caused by bugs in coordinate
+// calculations) panic instead of silently corrupting memory.
+type Line struct {
+	ptr   unsafe.Pointer
+	begin unsafe.Pointer
+	size  uintptr
+}
+
+func newLine(ptr unsafe.Pointer, size int) Line {
+	return Line{ptr: ptr, begin: ptr, size: uintptr(size)}
+}
+
+func (l Line) check(off uintptr, size uintptr) {
+	// Offset from the beginning of the buffer. Both pointers are converted
+	// in the same expression, so they're consistent even if the buffer is
+	// moved (eg: on stack growth).
+	pos := int(uintptr(l.ptr) + off - uintptr(l.begin))
+	if pos < 0 || uintptr(pos)+size > l.size {
+		panic(fmt.Sprintf("gfx.Line: out of bounds access: offset %d (size %d), bounds [%d,%d)",
+			pos, size, 0, l.size))
+	}
+}
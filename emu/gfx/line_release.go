@@ -0,0 +1,22 @@
+//go:build !gfxdebug
+// +build !gfxdebug
+
+package gfx
+
+import "unsafe"
+
+// Debug is true when the package is built with the "gfxdebug" tag
+const Debug = false
+
+// Line is a pointer to a line of pixels, to be accessed with the Get/Set
+// methods. In release builds, accesses are not bounds-checked; build with
+// the "gfxdebug" tag to panic on out-of-bounds accesses.
+type Line struct {
+	ptr unsafe.Pointer
+}
+
+func newLine(ptr unsafe.Pointer, size int) Line {
+	return Line{ptr: ptr}
+}
+
+func (l Line) check(off uintptr, size uintptr) {}
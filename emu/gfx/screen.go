@@ -1,67 +1,70 @@
 package gfx
 
 import (
-	"reflect"
 	"unsafe"
 )
 
-type Line struct {
-	ptr uintptr
-}
-
+// NewLine creates a Line to access the pixels in the specified buffer
 func NewLine(mem []byte) Line {
-	return Line{uintptr(unsafe.Pointer(&mem[0]))}
+	return newLine(unsafe.Pointer(&mem[0]), len(mem))
 }
 
-func (l Line) IsNil() bool { return l.ptr == 0 }
+func (l Line) IsNil() bool { return l.ptr == nil }
 
 func (l *Line) Add8(x int) {
-	l.ptr += uintptr(x)
+	l.ptr = unsafe.Add(l.ptr, x)
 }
 
 func (l *Line) Add16(x int) {
-	l.ptr += uintptr(x * 2)
+	l.ptr = unsafe.Add(l.ptr, x*2)
 }
 
 func (l *Line) Add32(x int) {
-	l.ptr += uintptr(x * 4)
+	l.ptr = unsafe.Add(l.ptr, x*4)
 }
 
 func (l Line) Get8(x int) uint8 {
 	xx := uintptr(x)
-	return *(*uint8)(unsafe.Pointer(l.ptr + xx))
+	l.check(xx, 1)
+	return *(*uint8)(unsafe.Add(l.ptr, xx))
 }
 
 func (l Line) Get16(x int) uint16 {
 	xx := uintptr(x * 2)
-	return *(*uint16)(unsafe.Pointer(l.ptr + xx))
+	l.check(xx, 2)
+	return *(*uint16)(unsafe.Add(l.ptr, xx))
 }
 
 func (l Line) Get32(x int) uint32 {
 	xx := uintptr(x * 4)
-	return *(*uint32)(unsafe.Pointer(l.ptr + xx))
+	l.check(xx, 4)
+	return *(*uint32)(unsafe.Add(l.ptr, xx))
 }
 
 func (l Line) Set8(x int, val uint8) {
 	xx := uintptr(x)
-	*(*uint8)(unsafe.Pointer(l.ptr + xx)) = val
+	l.check(xx, 1)
+	*(*uint8)(unsafe.Add(l.ptr, xx)) = val
 }
 
 func (l Line) Set16(x int, val uint16) {
 	xx := uintptr(x * 2)
-	*(*uint16)(unsafe.Pointer(l.ptr + xx)) = val
+	l.check(xx, 2)
+	*(*uint16)(unsafe.Add(l.ptr, xx)) = val
 }
 
 func (l Line) Set32(x int, val uint32) {
 	xx := uintptr(x * 4)
-	*(*uint32)(unsafe.Pointer(l.ptr + xx)) = val
+	l.check(xx, 4)
+	*(*uint32)(unsafe.Add(l.ptr, xx)) = val
 }
 
 func (l Line) SetRGB(x int, r, g, b uint8) {
 	xx := uintptr(x * 4)
-	*(*uint8)(unsafe.Pointer(l.ptr + xx)) = r
-	*(*uint8)(unsafe.Pointer(l.ptr + xx + 1)) = g
-	*(*uint8)(unsafe.Pointer(l.ptr + xx + 2)) = b
+	l.check(xx, 3)
+	*(*uint8)(unsafe.Add(l.ptr, xx)) = r
+	*(*uint8)(unsafe.Add(l.ptr, xx+1)) = g
+	*(*uint8)(unsafe.Add(l.ptr, xx+2)) = b
 }
 
 type Buffer struct {
@@ -83,19 +86,21 @@ func (buf *Buffer) Pointer() unsafe.Pointer {
 	return buf.ptr
 }
 
+// Pitch returns the distance in bytes between two consecutive lines
+func (buf *Buffer) Pitch() int {
+	return buf.pitch
+}
+
 func (buf *Buffer) Line(y int) Line {
 	if y >= 0 && y < buf.Height {
-		ptr := uintptr(buf.ptr) + uintptr(y*buf.pitch)
-		return Line{ptr}
+		return newLine(unsafe.Add(buf.ptr, y*buf.pitch), buf.pitch)
 	}
 	panic("invalid line")
 }
 
 func (buf *Buffer) LineAsSlice(y int) []uint8 {
 	if y >= 0 && y < buf.Height {
-		ptr := uintptr(buf.ptr) + uintptr(y*buf.pitch)
-		slice := reflect.SliceHeader{Data: ptr, Len: buf.Width * 4, Cap: buf.Width * 4}
-		return *(*[]uint8)(unsafe.Pointer(&slice))
+		return unsafe.Slice((*uint8)(unsafe.Add(buf.ptr, y*buf.pitch)), buf.Width*4)
 	}
 	return nil
 }
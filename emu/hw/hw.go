@@ -2,6 +2,7 @@ package hw
 
 import (
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
@@ -13,7 +14,28 @@ import (
 )
 
 const (
-	kHwAudioBuffers = 3
+	// Number of audio buffers (one per frame) in the ring shared with the
+	// audio callback. When running faster than normal speed, the emulator
+	// is allowed to run ahead of the audio by multiple frames, so this must
+	// be large enough to accommodate kMaxSpeed.
+	kHwAudioBuffers = 16
+
+	// Range of supported emulation speeds, in percent
+	kMinSpeed = 10
+	kMaxSpeed = 1000
+
+	// Length (in sample frames) of the volume ramps applied when audio
+	// stops (pause, or emulation not producing audio fast enough) and
+	// restarts, to avoid clicks.
+	kAudioFadeLen = 256
+
+	// In energy saver mode, maximum time to wait for the audio device to
+	// consume a buffer before checking again (in case audio is stuck)
+	kEnergySaverMaxWait = 100 * time.Millisecond
+
+	// Number of frame textures: one being drawn by the emulation, one
+	// completed and waiting to be presented, and one being presented.
+	kHwFrameBuffers = 3
 )
 
 type OutputConfig struct {
@@ -24,19 +46,29 @@ type OutputConfig struct {
 	AudioFrequency    int    // Audio frequency in hertz
 	AudioChannels     int    // Number of output channels (1 or 2)
 	AudioSampleSigned bool   // True if samples are signed, False if unsigned
+
+	Present     PresentPolicy // How frames are presented on the host display
+	RefreshRate int           // Refresh rate of the host display in hertz (0: detect)
 }
 
 type Output struct {
 	cfg OutputConfig
 
-	screen      *sdl.Window
-	renderer    *sdl.Renderer
-	frame       *sdl.Texture
-	framebuf    [2][]byte
-	framebufidx int
+	screen   *sdl.Window
+	renderer *sdl.Renderer
+
+	// Frames are drawn directly into the memory of streaming textures,
+	// which stay locked until they're presented. If video is disabled,
+	// plain memory buffers are used instead.
+	frames   [kHwFrameBuffers]*sdl.Texture
+	framemem [kHwFrameBuffers]unsafe.Pointer // locked memory, nil if not locked
+	framebuf [kHwFrameBuffers][]byte
+	frameidx int
 
 	videoEnabled bool
 	audioEnabled bool
+	vsync        bool       // true if presentation waits for vsync (PresentNearest)
+	pacer        framePacer // selects the frames to present, if vsync is enabled
 	framecounter int
 	fpscounter   int
 	fpsclock     uint32
@@ -45,6 +77,22 @@ type Output struct {
 	aindexw      int32 // atomic
 	aindexr      int32 // atomic
 	audiobuf     [kHwAudioBuffers]AudioBuffer
+	underruns    int32 // atomic; number of audio callbacks that ran out of emulated audio
+
+	speed int32   // atomic; emulation speed in percent (100 = normal speed)
+	apos  float64 // position within current audio buffer (in sample frames), used for resampling
+
+	paused   int32       // atomic; true if emulation is paused
+	fade     int         // current volume ramp level (0..kAudioFadeLen), used by the audio callback
+	lastSmp  [2]int16    // last sample frame sent to the audio device (for fading out)
+	audiodrp AudioBuffer // audio buffer used to drop audio when producing too fast
+
+	energySaver int32         // atomic; true if waiting for audio must block rather than poll
+	audioWake   chan struct{} // signaled by the audio callback when a buffer is consumed
+
+	msgLock  sync.Mutex
+	msg      string    // message shown in the title bar (see ShowMessage)
+	msgUntil time.Time // time at which msg expires
 }
 
 func NewOutput(cfg OutputConfig) *Output {
@@ -52,13 +100,15 @@ func NewOutput(cfg OutputConfig) *Output {
 		sdl.Init(sdl.INIT_VIDEO | sdl.INIT_AUDIO)
 	}
 
-	return &Output{
-		cfg: cfg,
-		framebuf: [2][]byte{
-			make([]byte, cfg.Width*cfg.Height*4),
-			make([]byte, cfg.Width*cfg.Height*4),
-		},
+	out := &Output{
+		cfg:       cfg,
+		speed:     100,
+		audioWake: make(chan struct{}, 1),
 	}
+	for i := range out.framebuf {
+		out.framebuf[i] = make([]byte, cfg.Width*cfg.Height*4)
+	}
+	return out
 }
 
 func (out *Output) EnableVideo(enable bool) {
@@ -72,9 +122,36 @@ func (out *Output) EnableVideo(enable bool) {
 			panic(err)
 		}
 
-		// Create a renderer than never sync with vsync.
-		// Syncing is always done with audio, not vsync,
-		out.renderer, err = sdl.CreateRenderer(out.screen, -1, 0)
+		// Emulation speed is always synced with audio, not vsync. Presenting
+		// with vsync is only used to avoid tearing, and never when running
+		// unthrottled.
+		policy := out.cfg.Present
+		hostHz := out.cfg.RefreshRate
+		if hostHz == 0 {
+			hostHz = out.detectRefreshRate()
+		}
+		if policy == PresentAuto {
+			policy = PresentImmediate
+			if hostHz > 0 {
+				policy = PresentNearest
+			}
+		}
+		if !out.cfg.EnforceSpeed {
+			policy = PresentImmediate
+		}
+		if policy == PresentNearest && hostHz <= 0 {
+			log.ModHw.Warnf("unknown display refresh rate, assuming %dHz", out.cfg.FramePerSecond)
+			hostHz = out.cfg.FramePerSecond
+		}
+		log.ModHw.Infof("presentation: %v (display: %dHz)", policy, hostHz)
+
+		var flags uint32
+		out.vsync = policy == PresentNearest
+		if out.vsync {
+			flags |= sdl.RENDERER_PRESENTVSYNC
+			out.pacer = framePacer{hostHz: hostHz, fps: out.cfg.FramePerSecond}
+		}
+		out.renderer, err = sdl.CreateRenderer(out.screen, -1, flags)
 		if err != nil {
 			panic(err)
 		}
@@ -83,16 +160,24 @@ func (out *Output) EnableVideo(enable bool) {
 		// make the scaled rendering look smoother.
 		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "nearest")
 
-		out.frame, err = out.renderer.CreateTexture(
-			sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING,
-			out.cfg.Width, out.cfg.Height)
-		if err != nil {
-			panic(err)
+		for i := range out.frames {
+			out.frames[i], err = out.renderer.CreateTexture(
+				sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING,
+				out.cfg.Width, out.cfg.Height)
+			if err != nil {
+				panic(err)
+			}
 		}
 
 	} else {
-		out.frame.Destroy()
-		out.frame = nil
+		for i := range out.frames {
+			if out.framemem[i] != nil {
+				out.frames[i].Unlock()
+				out.framemem[i] = nil
+			}
+			out.frames[i].Destroy()
+			out.frames[i] = nil
+		}
 		out.renderer.Destroy()
 		out.renderer = nil
 		out.screen.Destroy()
@@ -122,6 +207,7 @@ func (out *Output) EnableAudio(enable bool) {
 	for i := range out.audiobuf {
 		out.audiobuf[i] = make(AudioBuffer, samplesPerFrame*out.cfg.AudioChannels)
 	}
+	out.audiodrp = make(AudioBuffer, samplesPerFrame*out.cfg.AudioChannels)
 
 	spec := sdl.AudioSpec{
 		Freq:     int32(out.cfg.AudioFrequency),
@@ -139,20 +225,97 @@ func (out *Output) EnableAudio(enable bool) {
 	out.audioEnabled = enable
 }
 
+// SetSpeed changes the emulation speed, as a percentage of the normal speed
+// (clamped within 10%-1000%). Both frame pacing and audio are scaled:
+// audio is resampled so that it always plays in sync with emulation.
+func (out *Output) SetSpeed(percent int) {
+	if percent < kMinSpeed {
+		percent = kMinSpeed
+	} else if percent > kMaxSpeed {
+		percent = kMaxSpeed
+	}
+	atomic.StoreInt32(&out.speed, int32(percent))
+}
+
+// Speed returns the current emulation speed, in percent.
+func (out *Output) Speed() int {
+	return int(atomic.LoadInt32(&out.speed))
+}
+
+// AudioUnderruns returns the number of times the audio device asked for more
+// audio than the emulation had produced (not counting pauses).
+func (out *Output) AudioUnderruns() int {
+	return int(atomic.LoadInt32(&out.underruns))
+}
+
+// SetPaused notifies the output that the emulation has been paused or
+// resumed. While paused, audio is faded out and then silenced.
+func (out *Output) SetPaused(paused bool) {
+	var v int32
+	if paused {
+		v = 1
+	}
+	atomic.StoreInt32(&out.paused, v)
+}
+
+// SetEnergySaver enables or disables the energy saver mode. When a frame
+// is completed ahead of time, the emulator normally polls the audio device
+// with short sleeps, to resume as soon as possible; in energy saver mode, it
+// blocks until the audio device requests more data, which lets the CPU idle
+// for longer periods, at the cost of a slightly higher jitter.
+func (out *Output) SetEnergySaver(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&out.energySaver, v)
+}
+
+// Called by the audio callbacks each time a whole emulated audio buffer
+// has been consumed.
+func (out *Output) audioConsumed() {
+	atomic.AddInt32(&out.audiocounter, 1)
+	select {
+	case out.audioWake <- struct{}{}:
+	default:
+	}
+}
+
+// BeginFrame returns the buffers for the next frame to be emulated. The
+// screen buffer is the memory of a locked texture (with undefined contents),
+// so the whole frame must be drawn; it stays valid until the frame is passed
+// to EndFrame.
 func (out *Output) BeginFrame() (gfx.Buffer, AudioBuffer) {
-	out.framebufidx = 1 - out.framebufidx
-	fbuf := gfx.NewBuffer(unsafe.Pointer(&out.framebuf[out.framebufidx][0]),
-		out.cfg.Width, out.cfg.Height, out.cfg.Width*4)
+	idx := out.frameidx
+	out.frameidx = (out.frameidx + 1) % kHwFrameBuffers
+
+	var fbuf gfx.Buffer
+	if out.videoEnabled {
+		var pixels unsafe.Pointer
+		var pitch int
+		if err := out.frames[idx].Lock(nil, &pixels, &pitch); err != nil {
+			panic(err)
+		}
+		out.framemem[idx] = pixels
+		fbuf = gfx.NewBuffer(pixels, out.cfg.Width, out.cfg.Height, pitch)
+	} else {
+		fbuf = gfx.NewBuffer(unsafe.Pointer(&out.framebuf[idx][0]),
+			out.cfg.Width, out.cfg.Height, out.cfg.Width*4)
+	}
 
 	aindexw := atomic.LoadInt32(&out.aindexw)
 	if aindexw >= atomic.LoadInt32(&out.aindexr)+kHwAudioBuffers {
-		if out.cfg.EnforceSpeed {
-			log.ModHw.WithFields(log.Fields{
-				"fc": fmt.Sprintf("%04d", out.framecounter),
-				"ar": fmt.Sprintf("%04d", atomic.LoadInt32(&out.aindexr)),
-				"aw": fmt.Sprintf("%04d", atomic.LoadInt32(&out.aindexw)),
-			}).Warn("overflow audio buffer (producing too fast)")
+		if !out.cfg.EnforceSpeed {
+			// We're running unthrottled, so we're producing audio much
+			// faster than it can be played. Drop this frame's audio,
+			// rather than overwriting a buffer which might be playing.
+			return fbuf, out.audiodrp
 		}
+		log.ModHw.WithFields(log.Fields{
+			"fc": fmt.Sprintf("%04d", out.framecounter),
+			"ar": fmt.Sprintf("%04d", atomic.LoadInt32(&out.aindexr)),
+			"aw": fmt.Sprintf("%04d", atomic.LoadInt32(&out.aindexw)),
+		}).Warn("overflow audio buffer (producing too fast)")
 	}
 	abuf := out.audiobuf[aindexw%kHwAudioBuffers]
 	atomic.AddInt32(&out.aindexw, 1)
@@ -163,11 +326,38 @@ func (out *Output) BeginFrame() (gfx.Buffer, AudioBuffer) {
 func (out *Output) EndFrame(screen gfx.Buffer, audio AudioBuffer) {
 	out.framecounter++
 
+	// When running faster than normal, we let the emulation run ahead of
+	// the audio, as each audio callback will consume more than one frame.
+	// We also skip presenting frames that would be displayed for less
+	// than a host frame anyway: with vsync, the pacer knows exactly which
+	// ones, otherwise we assume a host refresh rate equal to the emulated
+	// one.
+	speed := out.Speed()
+	ahead := (speed - 1) / 100
+	present := out.framecounter%(ahead+1) == 0
+	if out.vsync {
+		present = out.pacer.next(speed) > 0
+	}
+
 	if out.videoEnabled {
-		if int(atomic.LoadInt32(&out.audiocounter)) < out.framecounter {
-			out.frame.Update(nil, screen.Pointer(), out.cfg.Width*4)
+		// Unlock the texture the frame was drawn into; it must be done even
+		// if the frame is not presented, to be able to lock it again.
+		var frame *sdl.Texture
+		for i, mem := range out.framemem {
+			if mem != nil && mem == screen.Pointer() {
+				frame = out.frames[i]
+				frame.Unlock()
+				out.framemem[i] = nil
+				break
+			}
+		}
+		if frame == nil {
+			panic("EndFrame called with a buffer not returned by BeginFrame")
+		}
+
+		if int(atomic.LoadInt32(&out.audiocounter)) < out.framecounter && present {
 			out.renderer.Clear()
-			out.renderer.Copy(out.frame, nil, nil)
+			out.renderer.Copy(frame, nil, nil)
 			out.renderer.Present()
 			out.fpscounter++
 
@@ -175,29 +365,70 @@ func (out *Output) EndFrame(screen gfx.Buffer, audio AudioBuffer) {
 				// Wait until audio catches up; this is where we slow down emulation
 				// to match the desired framerate (but we do that syncing with audio
 				// rathern than a timer).
-				for int(atomic.LoadInt32(&out.audiocounter)) < out.framecounter {
-					time.Sleep(1 * time.Millisecond)
+				for int(atomic.LoadInt32(&out.audiocounter))+ahead < out.framecounter {
+					if atomic.LoadInt32(&out.energySaver) != 0 {
+						select {
+						case <-out.audioWake:
+						case <-time.After(kEnergySaverMaxWait):
+						}
+					} else {
+						time.Sleep(1 * time.Millisecond)
+					}
 				}
 			}
 		}
 
 		if out.fpsclock+1000 < sdl.GetTicks() {
-			out.screen.SetTitle(fmt.Sprintf("%s - %d FPS", out.cfg.Title, out.fpscounter))
+			title := fmt.Sprintf("%s - %d FPS", out.cfg.Title, out.fpscounter)
+			if speed != 100 {
+				title = fmt.Sprintf("%s - %d FPS (speed: %d%%)", out.cfg.Title, out.fpscounter, speed)
+			}
+			if msg := out.message(); msg != "" {
+				title += " - " + msg
+			}
+			out.screen.SetTitle(title)
 			out.fpscounter = 0
 			out.fpsclock += 1000
 		}
 	}
 }
 
+// ShowMessage displays a short message to the user for the specified
+// duration. The message is shown in the title bar, next to the FPS counter,
+// so it doesn't cover the emulated screens.
+func (out *Output) ShowMessage(msg string, d time.Duration) {
+	out.msgLock.Lock()
+	out.msg, out.msgUntil = msg, time.Now().Add(d)
+	out.msgLock.Unlock()
+}
+
+// Return the message to be shown, if it hasn't expired yet
+func (out *Output) message() string {
+	out.msgLock.Lock()
+	defer out.msgLock.Unlock()
+	if out.msg != "" && time.Now().After(out.msgUntil) {
+		out.msg = ""
+	}
+	return out.msg
+}
+
 func (out *Output) audioCallback(outbuf []int16) {
+	if atomic.LoadInt32(&out.paused) != 0 {
+		out.audioFadeOut(outbuf)
+		return
+	}
+
+	if atomic.LoadInt32(&out.speed) != 100 || out.apos != 0 {
+		out.audioCallbackResample(outbuf)
+		return
+	}
 
 	aindexr := atomic.LoadInt32(&out.aindexr)
 
 	if out.aindexr == atomic.LoadInt32(&out.aindexw) {
-		// fmt.Println("audio underflow: no audio generated, silencing")
-		for i := range outbuf {
-			outbuf[i] = 0
-		}
+		// Audio underflow: no audio generated, fade out to silence
+		atomic.AddInt32(&out.underruns, 1)
+		out.audioFadeOut(outbuf)
 		return
 	}
 
@@ -207,9 +438,93 @@ func (out *Output) audioCallback(outbuf []int16) {
 		panic("invalid audio buffer size")
 	}
 	copy(outbuf, buf)
+	out.audioFadeIn(outbuf)
 
 	atomic.AddInt32(&out.aindexr, 1)
-	atomic.AddInt32(&out.audiocounter, 1)
+	out.audioConsumed()
+}
+
+// audioFadeOut fills the buffer by ramping down the volume of the last
+// sample frame that was played, and then silence. Just silencing the output
+// (or repeating the last buffer) would cause clicks or a buzz.
+func (out *Output) audioFadeOut(outbuf []int16) {
+	nch := out.cfg.AudioChannels
+	for i := 0; i < len(outbuf); i += nch {
+		if out.fade > 0 {
+			out.fade--
+		}
+		for c := 0; c < nch; c++ {
+			outbuf[i+c] = int16(int(out.lastSmp[c]) * out.fade / kAudioFadeLen)
+		}
+	}
+}
+
+// audioFadeIn ramps up the volume of a buffer that was generated by the
+// emulation, after audio was faded out. It also keeps track of the last
+// sample frame played, to be used for next fade out.
+func (out *Output) audioFadeIn(outbuf []int16) {
+	nch := out.cfg.AudioChannels
+	for i := 0; i < len(outbuf) && out.fade < kAudioFadeLen; i += nch {
+		out.fade++
+		for c := 0; c < nch; c++ {
+			outbuf[i+c] = int16(int(outbuf[i+c]) * out.fade / kAudioFadeLen)
+		}
+	}
+	copy(out.lastSmp[:nch], outbuf[len(outbuf)-nch:])
+}
+
+// audioCallbackResample is used when the emulation is not running at normal
+// speed: each output buffer is generated by stepping through the emulated
+// audio buffers at a rate proportional to the speed (using linear
+// interpolation within each buffer), so that slowing down or speeding up
+// the emulation also slows down or speeds up the audio.
+func (out *Output) audioCallbackResample(outbuf []int16) {
+	nch := out.cfg.AudioChannels
+	step := float64(atomic.LoadInt32(&out.speed)) / 100
+
+	for i := 0; i < len(outbuf); i += nch {
+		aindexr := atomic.LoadInt32(&out.aindexr)
+		if aindexr == atomic.LoadInt32(&out.aindexw) {
+			// Underflow: fade out the rest of the buffer
+			atomic.AddInt32(&out.underruns, 1)
+			if i > 0 {
+				out.audioFadeIn(outbuf[:i])
+			}
+			out.audioFadeOut(outbuf[i:])
+			return
+		}
+
+		buf := out.audiobuf[aindexr%kHwAudioBuffers]
+		nframes := len(buf) / nch
+		idx := int(out.apos)
+		frac := out.apos - float64(idx)
+		next := idx + 1
+		if next >= nframes {
+			next = idx
+		}
+		for c := 0; c < nch; c++ {
+			s0 := float64(buf[idx*nch+c])
+			s1 := float64(buf[next*nch+c])
+			outbuf[i+c] = int16(s0 + (s1-s0)*frac)
+		}
+
+		out.apos += step
+		for out.apos >= float64(nframes) {
+			out.apos -= float64(nframes)
+			atomic.AddInt32(&out.aindexr, 1)
+			out.audioConsumed()
+			if step == 1 {
+				// Back to normal speed: realign to the buffer start, so that
+				// next callbacks can go through the fast path.
+				out.apos = 0
+			}
+			if atomic.LoadInt32(&out.aindexr) == atomic.LoadInt32(&out.aindexw) {
+				out.apos = 0
+				break
+			}
+		}
+	}
+	out.audioFadeIn(outbuf)
 }
 
 type MouseButtons int
@@ -259,10 +574,12 @@ func (out *Output) Poll() bool {
 	return true
 }
 
-func (out *Output) Screenshot(fn string) error {
+// Screenshot saves a frame (as returned by BeginFrame) into a BMP file. It
+// must be called before the frame is passed to EndFrame.
+func (out *Output) Screenshot(screen gfx.Buffer, fn string) error {
 	surf, err := sdl.CreateRGBSurfaceFrom(
-		unsafe.Pointer(&out.framebuf[0]),
-		out.cfg.Width, out.cfg.Height, 32, out.cfg.Width*4,
+		screen.Pointer(),
+		out.cfg.Width, out.cfg.Height, 32, screen.Pitch(),
 		0x00000FF, 0x0000FF00, 0x00FF0000, 0)
 	if err != nil {
 		return err
@@ -0,0 +1,70 @@
+package hw
+
+import (
+	log "ndsemu/emu/logger"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type PadAxis int
+type PadButton int
+
+const (
+	PadAxisLeftX  PadAxis = sdl.CONTROLLER_AXIS_LEFTX
+	PadAxisLeftY  PadAxis = sdl.CONTROLLER_AXIS_LEFTY
+	PadAxisRightX PadAxis = sdl.CONTROLLER_AXIS_RIGHTX
+	PadAxisRightY PadAxis = sdl.CONTROLLER_AXIS_RIGHTY
+)
+
+const (
+	PadButtonA             PadButton = sdl.CONTROLLER_BUTTON_A
+	PadButtonB             PadButton = sdl.CONTROLLER_BUTTON_B
+	PadButtonX             PadButton = sdl.CONTROLLER_BUTTON_X
+	PadButtonY             PadButton = sdl.CONTROLLER_BUTTON_Y
+	PadButtonBack          PadButton = sdl.CONTROLLER_BUTTON_BACK
+	PadButtonStart         PadButton = sdl.CONTROLLER_BUTTON_START
+	PadButtonLeftStick     PadButton = sdl.CONTROLLER_BUTTON_LEFTSTICK
+	PadButtonRightStick    PadButton = sdl.CONTROLLER_BUTTON_RIGHTSTICK
+	PadButtonLeftShoulder  PadButton = sdl.CONTROLLER_BUTTON_LEFTSHOULDER
+	PadButtonRightShoulder PadButton = sdl.CONTROLLER_BUTTON_RIGHTSHOULDER
+)
+
+// Pad is a game controller attached to the host. Axis values are reported in
+// the range [-32768, 32767], as SDL does.
+type Pad struct {
+	ctrl *sdl.GameController
+}
+
+// OpenPad opens the first game controller found on the host. It returns nil
+// if no game controller is attached.
+func OpenPad() *Pad {
+	if sdl.WasInit(sdl.INIT_GAMECONTROLLER) == 0 {
+		if err := sdl.InitSubSystem(sdl.INIT_GAMECONTROLLER); err != nil {
+			log.ModHw.Warnf("cannot initialize game controllers: %v", err)
+			return nil
+		}
+	}
+
+	for i := 0; i < sdl.NumJoysticks(); i++ {
+		if !sdl.IsGameController(i) {
+			continue
+		}
+		if ctrl := sdl.GameControllerOpen(i); ctrl != nil {
+			log.ModHw.Infof("using game controller: %s", ctrl.Name())
+			return &Pad{ctrl: ctrl}
+		}
+	}
+	return nil
+}
+
+func (p *Pad) Axis(axis PadAxis) int {
+	return int(p.ctrl.GetAxis(sdl.GameControllerAxis(axis)))
+}
+
+func (p *Pad) Button(btn PadButton) bool {
+	return p.ctrl.GetButton(sdl.GameControllerButton(btn)) != 0
+}
+
+func (p *Pad) Close() {
+	p.ctrl.Close()
+}
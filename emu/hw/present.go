@@ -0,0 +1,103 @@
+package hw
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// PresentPolicy selects how emulated frames are presented on a host display
+// whose refresh rate may differ from the emulated one.
+type PresentPolicy int
+
+const (
+	// PresentAuto uses PresentNearest if the refresh rate of the display
+	// is known, and PresentImmediate otherwise.
+	PresentAuto PresentPolicy = iota
+
+	// PresentImmediate presents each frame as soon as it is emulated,
+	// without waiting for the vertical blank of the display. Pacing is
+	// perfect on 60Hz displays, but there might be tearing.
+	PresentImmediate
+
+	// PresentNearest waits for the vertical blank of the display, and shows
+	// on each refresh the emulated frame that is nearest in time. On faster
+	// displays (eg: 75/120/144Hz) frames are repeated, on slower displays
+	// (eg: 50Hz) some frames are dropped, evenly spaced.
+	PresentNearest
+)
+
+// ParsePresentPolicy parses the name of a policy: auto, immediate or nearest.
+func ParsePresentPolicy(s string) (PresentPolicy, error) {
+	switch s {
+	case "auto":
+		return PresentAuto, nil
+	case "immediate":
+		return PresentImmediate, nil
+	case "nearest":
+		return PresentNearest, nil
+	}
+	return 0, fmt.Errorf("invalid presentation policy: %q", s)
+}
+
+func (p PresentPolicy) String() string {
+	switch p {
+	case PresentAuto:
+		return "auto"
+	case PresentImmediate:
+		return "immediate"
+	case PresentNearest:
+		return "nearest"
+	}
+	return fmt.Sprintf("PresentPolicy(%d)", int(p))
+}
+
+// Detect the refresh rate (in Hz) of the display the window is on. Returns 0
+// if unknown.
+func (out *Output) detectRefreshRate() int {
+	idx, err := out.screen.GetDisplayIndex()
+	if err != nil {
+		return 0
+	}
+	var mode sdl.DisplayMode
+	if err := sdl.GetCurrentDisplayMode(idx, &mode); err != nil {
+		return 0
+	}
+	return int(mode.RefreshRate)
+}
+
+// framePacer implements PresentNearest: it computes how many refreshes of
+// the host display each emulated frame must stay on screen.
+type framePacer struct {
+	hostHz int   // refresh rate of the host display
+	fps    int   // emulated frames per second, at normal speed
+	speed  int   // emulation speed (in percent) since frame n=0
+	n      int64 // number of frames emulated since the last speed change
+}
+
+// next returns the number of host refreshes for the next emulated frame;
+// 0 means that the frame must be dropped. speed is the current emulation
+// speed, in percent.
+func (p *framePacer) next(speed int) int {
+	if speed != p.speed {
+		p.speed, p.n = speed, 0
+	}
+
+	// With an emulated frame period T and a host period H, frame n is the
+	// nearest one to the host refreshes k where (n-1/2)*T <= k*H < (n+1/2)*T.
+	// Exact integer math avoids any drift over time.
+	num := int64(p.hostHz) * 100
+	den := int64(p.fps) * int64(p.speed)
+	first := ceilDiv((2*p.n-1)*num, 2*den)
+	last := ceilDiv((2*p.n+1)*num, 2*den)
+	p.n++
+	return int(last - first)
+}
+
+func ceilDiv(a, b int64) int64 {
+	q := a / b
+	if a%b > 0 {
+		q++
+	}
+	return q
+}
@@ -0,0 +1,31 @@
+package hw
+
+import "testing"
+
+func TestFramePacer(t *testing.T) {
+	for _, tc := range []struct {
+		hostHz, speed int
+		want          []int
+	}{
+		{60, 100, []int{1, 1, 1, 1, 1, 1}},
+		{120, 100, []int{2, 2, 2, 2, 2, 2}},
+		{144, 100, []int{3, 2, 2, 3, 2, 3, 2, 2, 3, 2}},
+		{75, 100, []int{1, 1, 2, 1, 1, 1, 2, 1}},
+		{50, 100, []int{1, 1, 1, 0, 1, 1, 1, 1, 1, 0}},
+		{60, 200, []int{1, 0, 1, 0, 1, 0}},
+	} {
+		p := framePacer{hostHz: tc.hostHz, fps: 60}
+		total := 0
+		for i, want := range tc.want {
+			n := p.next(tc.speed)
+			if n != want {
+				t.Errorf("%dHz, speed %d%%: frame %d: got %d refreshes, want %d", tc.hostHz, tc.speed, i, n, want)
+			}
+			total += n
+		}
+		// Over a whole period, the number of refreshes must match exactly
+		if exp := len(tc.want) * tc.hostHz * 100 / (60 * tc.speed); total != exp {
+			t.Errorf("%dHz, speed %d%%: %d refreshes in total, want %d", tc.hostHz, tc.speed, total, exp)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package hwio
+
+// Memory hooks allow external code (cheats, scripts, watchpoints) to
+// intercept accesses going through a Table. To keep the cost of hooks
+// close to zero when they are not used, they are enabled per 4KB page:
+// accesses to pages without hooks only pay for a nil check and a bit test.
+
+const (
+	cHookPageShift = 12
+	cHookNumPages  = 1 << (32 - cHookPageShift)
+)
+
+// MemReadHook is called after a read of the specified size (1, 2 or 4 bytes)
+// has been performed; it returns the value that will be seen by the caller,
+// so it can be used to patch memory contents on the fly.
+type MemReadHook func(addr uint32, size int, val uint32) uint32
+
+// MemWriteHook is called before a write of the specified size (1, 2 or 4
+// bytes) is performed; it returns the value that will be actually written,
+// and false if the write must be discarded.
+type MemWriteHook func(addr uint32, size int, val uint32) (uint32, bool)
+
+type memHook struct {
+	id         int
+	begin, end uint32
+	read       MemReadHook
+	write      MemWriteHook
+}
+
+// AddHook installs a read and/or write hook over the address range [begin,
+// end], and returns an identifier that can be used to remove it. Either of
+// the two callbacks can be nil.
+//
+// Notice that hooks only see accesses that go through the table: memory
+// accessed by pointer (eg: opcode fetches, or TCM on ARM9) is not intercepted.
+func (t *Table) AddHook(begin, end uint32, read MemReadHook, write MemWriteHook) int {
+	if begin > end {
+		panic("invalid hook range")
+	}
+	t.hookId++
+	t.hooks = append(t.hooks, memHook{
+		id:    t.hookId,
+		begin: begin,
+		end:   end,
+		read:  read,
+		write: write,
+	})
+
+	if t.hookBitmap == nil {
+		t.hookBitmap = make([]uint64, cHookNumPages/64)
+	}
+	t.markHookPages(begin>>cHookPageShift, end>>cHookPageShift, true)
+	t.hookPages = t.hookBitmap
+	return t.hookId
+}
+
+// RemoveHook removes a hook previously installed with AddHook.
+func (t *Table) RemoveHook(id int) {
+	var h memHook
+	for i := range t.hooks {
+		if t.hooks[i].id == id {
+			h = t.hooks[i]
+			t.hooks = append(t.hooks[:i], t.hooks[i+1:]...)
+			break
+		}
+	}
+	if h.id == 0 {
+		return
+	}
+
+	// Clear the pages of the hook, and then mark again those that are
+	// shared with other hooks.
+	first, last := h.begin>>cHookPageShift, h.end>>cHookPageShift
+	t.markHookPages(first, last, false)
+	for _, o := range t.hooks {
+		ofirst, olast := o.begin>>cHookPageShift, o.end>>cHookPageShift
+		if ofirst < first {
+			ofirst = first
+		}
+		if olast > last {
+			olast = last
+		}
+		if ofirst <= olast {
+			t.markHookPages(ofirst, olast, true)
+		}
+	}
+	if len(t.hooks) == 0 {
+		t.hookPages = nil
+	}
+}
+
+// Set or clear the bits of the pages [first, last] in the hook bitmap
+func (t *Table) markHookPages(first, last uint32, hooked bool) {
+	for p := first; p <= last; p++ {
+		if hooked {
+			t.hookBitmap[p/64] |= 1 << (p % 64)
+		} else {
+			t.hookBitmap[p/64] &^= 1 << (p % 64)
+		}
+	}
+}
+
+// Hooked reports whether any hook covers a page within the address range
+// [begin, end]. Code that accesses memory by pointer instead of going through
+// the table (see FetchPointerRW) can use it to fall back to normal accesses,
+// so that hooks are not bypassed.
+func (t *Table) Hooked(begin, end uint32) bool {
+	if t.hookPages == nil {
+		return false
+	}
+	for p := begin >> cHookPageShift; p <= end>>cHookPageShift; p++ {
+		if t.hookPages[p/64]&(1<<(p%64)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Table) pageHooked(addr uint32) bool {
+	p := addr >> cHookPageShift
+	return t.hookPages[p/64]&(1<<(p%64)) != 0
+}
+
+func (t *Table) hookedRead(addr uint32, size int) uint32 {
+	var val uint32
+	switch size {
+	case 1:
+		if io := t.table8.Search(addr); io != nil {
+			val = uint32(io.(BankIO8).Read8(addr))
+		}
+	case 2:
+		if io := t.table16.Search(addr); io != nil {
+			val = uint32(io.(BankIO16).Read16(addr))
+		}
+	case 4:
+		if io := t.table32.Search(addr); io != nil {
+			val = io.(BankIO32).Read32(addr)
+		}
+	}
+
+	for _, h := range t.hooks {
+		if h.read != nil && addr >= h.begin && addr <= h.end {
+			val = h.read(addr, size, val)
+		}
+	}
+	return val
+}
+
+func (t *Table) hookedWrite(addr uint32, size int, val uint32) {
+	for _, h := range t.hooks {
+		if h.write != nil && addr >= h.begin && addr <= h.end {
+			var ok bool
+			if val, ok = h.write(addr, size, val); !ok {
+				return
+			}
+		}
+	}
+
+	switch size {
+	case 1:
+		if io := t.table8.Search(addr); io != nil {
+			io.(BankIO8).Write8(addr, uint8(val))
+		}
+	case 2:
+		if io := t.table16.Search(addr); io != nil {
+			io.(BankIO16).Write16(addr, uint16(val))
+		}
+	case 4:
+		if io := t.table32.Search(addr); io != nil {
+			io.(BankIO32).Write32(addr, val)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package hwio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type mappedBank struct {
+	addr    uint32
+	bank    interface{}
+	bankNum int
+}
+
+// RegValue is the value of a register at a given time, as returned by
+// Table.Snapshot.
+type RegValue struct {
+	Addr  uint32 `json:"addr"`
+	Bank  string `json:"bank"` // type of the structure declaring the register
+	Name  string `json:"name"`
+	Size  int    `json:"size"` // in bits
+	Value uint64 `json:"value"`
+}
+
+// Snapshot returns the current value of all the named registers mapped with
+// MapBank, sorted by address. Values are read directly, without invoking
+// read callbacks (that might have side effects), so registers whose value is
+// computed on read report their stored value. Memory areas are not included.
+//
+// The snapshot must be taken while the emulation is stopped (eg: between
+// frames), to get a consistent view of the hardware.
+func (t *Table) Snapshot() []RegValue {
+	var res []RegValue
+	for _, b := range t.banks {
+		regs, err := bankGetRegs(b.bank, b.bankNum)
+		if err != nil {
+			panic(err)
+		}
+		bname := strings.TrimPrefix(fmt.Sprintf("%T", b.bank), "*")
+		for _, reg := range regs {
+			rv := RegValue{Addr: b.addr + reg.offset, Bank: bname}
+			switch r := reg.regPtr.(type) {
+			case *Reg64:
+				rv.Name, rv.Size, rv.Value = r.Name, 64, r.Value
+			case *Reg32:
+				rv.Name, rv.Size, rv.Value = r.Name, 32, uint64(r.Value)
+			case *Reg16:
+				rv.Name, rv.Size, rv.Value = r.Name, 16, uint64(r.Value)
+			case *Reg8:
+				rv.Name, rv.Size, rv.Value = r.Name, 8, uint64(r.Value)
+			default:
+				continue
+			}
+			res = append(res, rv)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Addr < res[j].Addr })
+	return res
+}
@@ -31,9 +31,33 @@ type Table struct {
 	Name string
 	ws   int
 
+	// Access timings of each 16MB region (see emu.TimedBus)
+	timings [256]emu.AccessTimings
+
 	table8  radixTree
 	table16 radixTree
 	table32 radixTree
+
+	hooks      []memHook
+	hookId     int
+	hookPages  []uint64 // bitmap of 4KB pages with hooks, nil if none
+	hookBitmap []uint64 // storage of hookPages, allocated by the first hook
+
+	banks []mappedBank // banks mapped with MapBank, used by Snapshot
+
+	// OpenBus, if set, returns the value of reads from unmapped addresses,
+	// as a 32-bit word for the word-aligned address (narrower reads extract
+	// the addressed bytes). If not set, unmapped reads return zero.
+	OpenBus func(addr uint32) uint32
+
+	// LogUnmapped enables logging of accesses to unmapped addresses
+	LogUnmapped bool
+
+	// Abort, if set, is called on accesses to unmapped addresses, to signal
+	// a bus error to the master that performed the access (see
+	// arm.Cpu.BusError). Reads still return the open bus value, and writes
+	// are ignored.
+	Abort func(addr uint32)
 }
 
 type io32to16 Table
@@ -65,18 +89,57 @@ func (t *io16to8) Write16(addr uint32, val uint16) {
 func NewTable(name string) *Table {
 	t := new(Table)
 	t.Name = name
+	t.LogUnmapped = true
+	t.SetWaitStates(0)
 	t.Reset()
 	return t
 }
 
+// Value returned by a read from an unmapped address
+func (t *Table) openBus(addr uint32, size uint32) uint32 {
+	if t.LogUnmapped {
+		log.ModHwIo.WithFields(log.Fields{
+			"name": t.Name,
+			"addr": emu.Hex32(addr),
+		}).Errorf("unmapped Read%d", size*8)
+	}
+	if t.Abort != nil {
+		t.Abort(addr)
+	}
+	if t.OpenBus == nil {
+		return 0
+	}
+	return t.OpenBus(addr&^3) >> (8 * (addr & 3 &^ (size - 1)))
+}
+
+// SetWaitStates sets the waitstates of all accesses. This also resets the
+// timings of all regions (see SetRegionTimings) to ws+1 cycles.
 func (t *Table) SetWaitStates(ws int) {
 	t.ws = ws
+	for i := range t.timings {
+		t.timings[i] = emu.AccessTimings{N16: ws + 1, S16: ws + 1, N32: ws + 1, S32: ws + 1}
+	}
+}
+
+// SetRegionTimings sets the timings of the accesses to the 16MB regions
+// covering the specified address range.
+func (t *Table) SetRegionTimings(begin, end uint32, timings emu.AccessTimings) {
+	for i := begin >> 24; i <= end>>24; i++ {
+		t.timings[i] = timings
+	}
+}
+
+// RegionTimings returns the timings of the accesses to all regions; it
+// implements emu.TimedBus.
+func (t *Table) RegionTimings() *[256]emu.AccessTimings {
+	return &t.timings
 }
 
 func (t *Table) Reset() {
 	t.table8 = radixTree{}
 	t.table16 = radixTree{}
 	t.table32 = radixTree{}
+	t.banks = nil
 }
 
 // Map a register bank (that is, a structure containing mulitple IoReg* fields).
@@ -98,6 +161,7 @@ func (t *Table) MapBank(addr uint32, bank interface{}, bankNum int) {
 	if err != nil {
 		panic(err)
 	}
+	t.banks = append(t.banks, mappedBank{addr, bank, bankNum})
 
 	for _, reg := range regs {
 		switch r := reg.regPtr.(type) {
@@ -122,6 +186,12 @@ func (t *Table) UnmapBank(addr uint32, bank interface{}, bankNum int) {
 	if err != nil {
 		panic(err)
 	}
+	for i, b := range t.banks {
+		if b == (mappedBank{addr, bank, bankNum}) {
+			t.banks = append(t.banks[:i], t.banks[i+1:]...)
+			break
+		}
+	}
 
 	for _, reg := range regs {
 		switch r := reg.regPtr.(type) {
@@ -237,13 +307,12 @@ func (t *Table) Unmap(begin uint32, end uint32) {
 }
 
 func (t *Table) Read8(addr uint32) uint8 {
+	if t.hookPages != nil && t.pageHooked(addr) {
+		return uint8(t.hookedRead(addr, 1))
+	}
 	io := t.table8.Search(addr)
 	if io == nil {
-		log.ModHwIo.WithFields(log.Fields{
-			"name": t.Name,
-			"addr": emu.Hex32(addr),
-		}).Error("unmapped Read8")
-		return 0
+		return uint8(t.openBus(addr, 1))
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.Read8(addr)
@@ -252,13 +321,22 @@ func (t *Table) Read8(addr uint32) uint8 {
 }
 
 func (t *Table) Write8(addr uint32, val uint8) {
+	if t.hookPages != nil && t.pageHooked(addr) {
+		t.hookedWrite(addr, 1, uint32(val))
+		return
+	}
 	io := t.table8.Search(addr)
 	if io == nil {
-		log.ModHwIo.WithFields(log.Fields{
-			"name": t.Name,
-			"val":  emu.Hex8(val),
-			"addr": emu.Hex32(addr),
-		}).Error("unmapped Write8")
+		if t.LogUnmapped {
+			log.ModHwIo.WithFields(log.Fields{
+				"name": t.Name,
+				"val":  emu.Hex8(val),
+				"addr": emu.Hex32(addr),
+			}).Error("unmapped Write8")
+		}
+		if t.Abort != nil {
+			t.Abort(addr)
+		}
 		return
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
@@ -279,13 +357,12 @@ func (t *Table) Write8(addr uint32, val uint8) {
 }
 
 func (t *Table) Read16(addr uint32) uint16 {
+	if t.hookPages != nil && t.pageHooked(addr) {
+		return uint16(t.hookedRead(addr, 2))
+	}
 	io := t.table16.Search(addr)
 	if io == nil {
-		log.ModHwIo.WithFields(log.Fields{
-			"name": t.Name,
-			"addr": emu.Hex32(addr),
-		}).Error("unmapped Read16")
-		return 0
+		return uint16(t.openBus(addr, 2))
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.Read16(addr)
@@ -294,13 +371,22 @@ func (t *Table) Read16(addr uint32) uint16 {
 }
 
 func (t *Table) Write16(addr uint32, val uint16) {
+	if t.hookPages != nil && t.pageHooked(addr) {
+		t.hookedWrite(addr, 2, uint32(val))
+		return
+	}
 	io := t.table16.Search(addr)
 	if io == nil {
-		log.ModHwIo.WithFields(log.Fields{
-			"name": t.Name,
-			"val":  emu.Hex16(val),
-			"addr": emu.Hex32(addr),
-		}).Error("unmapped Write16")
+		if t.LogUnmapped {
+			log.ModHwIo.WithFields(log.Fields{
+				"name": t.Name,
+				"val":  emu.Hex16(val),
+				"addr": emu.Hex32(addr),
+			}).Error("unmapped Write16")
+		}
+		if t.Abort != nil {
+			t.Abort(addr)
+		}
 		return
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
@@ -321,13 +407,12 @@ func (t *Table) Write16(addr uint32, val uint16) {
 }
 
 func (t *Table) Read32(addr uint32) uint32 {
+	if t.hookPages != nil && t.pageHooked(addr) {
+		return t.hookedRead(addr, 4)
+	}
 	io := t.table32.Search(addr)
 	if io == nil {
-		log.ModHwIo.WithFields(log.Fields{
-			"name": t.Name,
-			"addr": emu.Hex32(addr),
-		}).Error("unmapped Read32")
-		return 0
+		return t.openBus(addr, 4)
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.Read32(addr)
@@ -336,13 +421,22 @@ func (t *Table) Read32(addr uint32) uint32 {
 }
 
 func (t *Table) Write32(addr uint32, val uint32) {
+	if t.hookPages != nil && t.pageHooked(addr) {
+		t.hookedWrite(addr, 4, uint32(val))
+		return
+	}
 	io := t.table32.Search(addr)
 	if io == nil {
-		log.ModHwIo.WithFields(log.Fields{
-			"name": t.Name,
-			"val":  emu.Hex32(val),
-			"addr": emu.Hex32(addr),
-		}).Error("unmapped Write32")
+		if t.LogUnmapped {
+			log.ModHwIo.WithFields(log.Fields{
+				"name": t.Name,
+				"val":  emu.Hex32(val),
+				"addr": emu.Hex32(addr),
+			}).Error("unmapped Write32")
+		}
+		if t.Abort != nil {
+			t.Abort(addr)
+		}
 		return
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
@@ -370,6 +464,18 @@ func (t *Table) FetchPointer(addr uint32) []uint8 {
 	return nil
 }
 
+// FetchPointerRW is like FetchPointer, but only returns memory that can be
+// modified directly through the returned slice; it returns nil for
+// read-only memory, and for memory with a write callback (which would be
+// bypassed).
+func (t *Table) FetchPointerRW(addr uint32) []uint8 {
+	io := t.table8.Search(addr)
+	if mem, ok := io.(*memUnalignedLE); ok && !mem.ro && mem.wcb == nil {
+		return mem.FetchPointer(addr)
+	}
+	return nil
+}
+
 func (t *Table) WaitStates() int {
 	return t.ws
 }
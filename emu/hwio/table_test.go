@@ -174,3 +174,169 @@ func TestTableWrite(t *testing.T) {
 		t.Error("invalid regs after write32", r1, r2, r3, r4, r5, r6, r7, r8, r9)
 	}
 }
+
+func TestTableHooks(t *testing.T) {
+	ram := make([]byte, 0x4000)
+	table := NewTable("t1")
+	table.MapMemorySlice(0x2000000, 0x2003FFF, ram, false)
+
+	var reads, writes int
+	id := table.AddHook(0x2001010, 0x2001013,
+		func(addr uint32, size int, val uint32) uint32 {
+			reads++
+			return val + 1
+		},
+		func(addr uint32, size int, val uint32) (uint32, bool) {
+			writes++
+			return val, addr != 0x2001012
+		})
+
+	table.Write32(0x2001010, 0x11223344)
+	table.Write16(0x2001012, 0x5566)
+	table.Write32(0x2002010, 0xAABBCCDD)
+	if writes != 2 {
+		t.Errorf("invalid number of write hook calls: %d", writes)
+	}
+	if got := table.Read32(0x2001010); got != 0x11223345 {
+		t.Errorf("invalid hooked read: %08x", got)
+	}
+	if got := table.Read32(0x2002010); got != 0xAABBCCDD {
+		t.Errorf("invalid unhooked read: %08x", got)
+	}
+	if got := table.Read8(0x2001014); got != 0 {
+		t.Errorf("invalid read outside hook range: %02x", got)
+	}
+	if reads != 1 {
+		t.Errorf("invalid number of read hook calls: %d", reads)
+	}
+	if !table.Hooked(0x2000000, 0x2001000) || table.Hooked(0x2002000, 0x2003FFF) {
+		t.Errorf("invalid hooked pages")
+	}
+
+	table.RemoveHook(id)
+	if table.hookPages != nil {
+		t.Errorf("hook pages not released")
+	}
+	if table.Hooked(0x2000000, 0x2003FFF) {
+		t.Errorf("pages still hooked after hook removal")
+	}
+	if got := table.Read32(0x2001010); got != 0x11223344 {
+		t.Errorf("invalid read after hook removal: %08x", got)
+	}
+}
+
+func TestTableHookPages(t *testing.T) {
+	table := NewTable("t1")
+	nop := func(addr uint32, size int, val uint32) uint32 { return val }
+
+	// Two hooks sharing a page, and one spanning two pages
+	id1 := table.AddHook(0x2000010, 0x2000013, nop, nil)
+	id2 := table.AddHook(0x2000800, 0x2000803, nop, nil)
+	id3 := table.AddHook(0x2003FF0, 0x2004010, nop, nil)
+	bitmap := &table.hookBitmap[0]
+
+	table.RemoveHook(id1)
+	if !table.Hooked(0x2000000, 0x2000FFF) {
+		t.Errorf("shared page released while still hooked")
+	}
+	table.RemoveHook(id2)
+	if table.Hooked(0x2000000, 0x2002FFF) {
+		t.Errorf("page still hooked after removing its hooks")
+	}
+	if !table.Hooked(0x2003000, 0x2003000) || !table.Hooked(0x2004000, 0x2004000) {
+		t.Errorf("pages of remaining hook not hooked")
+	}
+	table.RemoveHook(id3)
+	if table.hookPages != nil || table.Hooked(0, 0xFFFFFFFF) {
+		t.Errorf("hook pages not released")
+	}
+
+	// The bitmap is allocated only once
+	table.AddHook(0x2000000, 0x2000000, nop, nil)
+	if &table.hookBitmap[0] != bitmap {
+		t.Errorf("hook bitmap reallocated")
+	}
+}
+
+func TestTableOpenBus(t *testing.T) {
+	table := Table{Name: "t1"}
+	table.Reset()
+	table.OpenBus = func(addr uint32) uint32 {
+		if addr&3 != 0 {
+			t.Errorf("unaligned open bus address: %08x", addr)
+		}
+		return 0xAABBCCDD
+	}
+
+	if v := table.Read32(0x1000); v != 0xAABBCCDD {
+		t.Errorf("Read32: got %08x", v)
+	}
+	if v := table.Read16(0x1002); v != 0xAABB {
+		t.Errorf("Read16: got %04x", v)
+	}
+	for i, exp := range []uint8{0xDD, 0xCC, 0xBB, 0xAA} {
+		if v := table.Read8(0x1000 + uint32(i)); v != exp {
+			t.Errorf("Read8(%d): got %02x, want %02x", i, v, exp)
+		}
+	}
+}
+
+func TestTableFetchPointerRW(t *testing.T) {
+	table := Table{Name: "t1"}
+	table.Reset()
+	table.MapMemorySlice(0x1000, 0x1FFF, make([]byte, 0x1000), false)
+	table.MapMemorySlice(0x2000, 0x2FFF, make([]byte, 0x1000), true)
+	table.MapMem(0x3000, &Mem{
+		Data:    make([]byte, 0x1000),
+		Flags:   MemFlag8 | MemFlag16Unaligned | MemFlag32Unaligned,
+		WriteCb: func(uint32, int) {},
+	})
+
+	if buf := table.FetchPointerRW(0x1800); len(buf) != 0x800 {
+		t.Errorf("invalid RW pointer to memory: len=%x", len(buf))
+	}
+	if table.FetchPointerRW(0x2800) != nil || table.FetchPointer(0x2800) == nil {
+		t.Errorf("RW pointer returned for read-only memory")
+	}
+	if table.FetchPointerRW(0x3800) != nil {
+		t.Errorf("RW pointer returned for memory with write callback")
+	}
+}
+
+type testSnapshot struct {
+	Reg1 Reg16 `hwio:"offset=0x10,reset=0x123"`
+	Reg2 Reg32 `hwio:"offset=0x4,bank=1,rcb"`
+}
+
+func (t *testSnapshot) ReadREG2(val uint32) uint32 {
+	return val | 1
+}
+
+func TestTableSnapshot(t *testing.T) {
+	ts := &testSnapshot{}
+	MustInitRegs(ts)
+
+	table := NewTable("test")
+	table.MapBank(0x4000000, ts, 1)
+	table.MapBank(0x4000000, ts, 0)
+
+	// Registers are sorted by address, and read callbacks are not invoked
+	snap := table.Snapshot()
+	if len(snap) != 2 {
+		t.Fatalf("invalid snapshot: %+v", snap)
+	}
+	exp := []RegValue{
+		{Addr: 0x4000004, Bank: "hwio.testSnapshot", Name: "Reg2", Size: 32, Value: 0},
+		{Addr: 0x4000010, Bank: "hwio.testSnapshot", Name: "Reg1", Size: 16, Value: 0x123},
+	}
+	for i := range exp {
+		if snap[i] != exp[i] {
+			t.Errorf("reg %d: got %+v, want %+v", i, snap[i], exp[i])
+		}
+	}
+
+	table.UnmapBank(0x4000000, ts, 1)
+	if snap := table.Snapshot(); len(snap) != 1 || snap[0].Name != "Reg1" {
+		t.Errorf("invalid snapshot after unmap: %+v", snap)
+	}
+}
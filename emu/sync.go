@@ -5,6 +5,7 @@ import (
 	"fmt"
 	log "ndsemu/emu/logger"
 	"sort"
+	"time"
 
 	"gopkg.in/Sirupsen/logrus.v0"
 )
@@ -65,8 +66,9 @@ type Cpu interface {
 // count to the correct frequency
 type syncSubsystem struct {
 	Subsystem
-	scaler Fixed8
-	name   string
+	scaler  Fixed8
+	name    string
+	elapsed time.Duration // host time spent in Run (if profiling is enabled)
 }
 
 func (s syncSubsystem) Cycles() int64 {
@@ -153,6 +155,12 @@ type Sync struct {
 	subOthers   []syncSubsystem
 	reqSyncs    []int64
 	cycles      int64
+	profile     bool
+
+	// Host time available to emulate the current frame. This is not used
+	// for scheduling (which is based on emulated cycles only), but it is
+	// exposed here so that subsystems can defer optional work.
+	Budget FrameBudget
 }
 
 func NewSync(cfg SyncConfig) (*Sync, error) {
@@ -380,7 +388,7 @@ func (s *Sync) RunUntil(target int64) {
 
 		for idx := range s.subCpus {
 			s.runningSub = &s.subCpus[idx]
-			s.runningSub.Run(next)
+			s.runSub(next)
 			cycles := s.runningSub.Cycles()
 			if next > cycles {
 				next = cycles
@@ -390,7 +398,7 @@ func (s *Sync) RunUntil(target int64) {
 
 		for idx := range s.subOthers {
 			s.runningSub = &s.subOthers[idx]
-			s.runningSub.Run(next)
+			s.runSub(next)
 			s.runningSub = nil
 		}
 	}
@@ -399,6 +407,42 @@ func (s *Sync) RunUntil(target int64) {
 	s.cycles = target
 }
 
+func (s *Sync) runSub(target int64) {
+	if !s.profile {
+		s.runningSub.Run(target)
+		return
+	}
+	t0 := time.Now()
+	s.runningSub.Run(target)
+	s.runningSub.elapsed += time.Since(t0)
+}
+
+// SetProfiling enables or disables the measurement of the host time spent
+// running each subsystem (see Timings). It's disabled by default, as it adds
+// a small overhead to each run.
+func (s *Sync) SetProfiling(enable bool) {
+	s.profile = enable
+}
+
+// SubsystemTime is the total host time spent running a subsystem.
+type SubsystemTime struct {
+	Name    string
+	Elapsed time.Duration
+}
+
+// Timings returns the host time spent running each subsystem (CPUs first),
+// since profiling was enabled. It must be called while the emulation is not
+// running.
+func (s *Sync) Timings() []SubsystemTime {
+	res := make([]SubsystemTime, 0, len(s.subCpus)+len(s.subOthers))
+	for _, subs := range [][]syncSubsystem{s.subCpus, s.subOthers} {
+		for _, sub := range subs {
+			res = append(res, SubsystemTime{sub.name, sub.elapsed})
+		}
+	}
+	return res
+}
+
 func (s *Sync) CurrentSubsystem() Subsystem {
 	if s.runningSub == nil {
 		return nil
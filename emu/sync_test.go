@@ -71,7 +71,7 @@ func TestHVSyncs(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	sync.AddSubsystem(&tsub)
+	sync.AddSubsystem(&tsub, "test")
 	sync.RunOneFrame()
 
 	expHsyncs := []dotpos{{5, 0}, {5, 1}, {5, 2}, {5, 3}, {5, 4}}
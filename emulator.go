@@ -3,15 +3,18 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"ndsemu/biosdump"
 	"ndsemu/e2d"
 	"ndsemu/emu"
 	"ndsemu/emu/debugger"
 	"ndsemu/emu/gfx"
 	log "ndsemu/emu/logger"
+	"ndsemu/hlebios"
 	"ndsemu/raster3d"
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 )
@@ -29,6 +32,16 @@ type NDSRom struct {
 	Bios7 []byte
 }
 
+// ConsoleModel is the hardware revision being emulated. Emulation is the
+// same for all models, except for the few differences that are visible to
+// software.
+type ConsoleModel int
+
+const (
+	ModelDS     ConsoleModel = iota // original DS ("phat")
+	ModelDSLite                     // DS Lite
+)
+
 type NDSHardware struct {
 	E2d  [2]*e2d.HwEngine2d
 	E3d  *raster3d.HwEngine3d
@@ -48,6 +61,7 @@ type NDSHardware struct {
 	Geom *HwGeometry
 	Bkp  *HwBackupRam
 	Sl2  *HwSlot2
+	Pm   *HwPowerMan
 }
 
 type NDSEmulator struct {
@@ -56,11 +70,21 @@ type NDSEmulator struct {
 	Hw   *NDSHardware
 	Sync *emu.Sync
 
+	// Watchdog, if not nil, is checked at the end of each frame to detect
+	// an emulation deadlock.
+	Watchdog *Watchdog
+
+	// IrqLog records the last IRQs delivered to both CPUs
+	IrqLog *IrqLog
+
 	dbg        *debugger.Debugger
 	screen     gfx.Buffer
 	audio      []int16
 	framecount int
 	powcnt     uint32
+
+	hookMu sync.Mutex
+	hooks  []*frameHook // see RegisterFrameHook
 }
 
 var Emu *NDSEmulator
@@ -73,8 +97,9 @@ func NewNDSHardware(mem *NDSMemory, firmware string) *NDSHardware {
 	nds7 = NewNDS7()
 	hw.Mc = NewMemoryController(nds9, nds7, mem.Vram[:])
 	hw.E3d = raster3d.NewHwEngine3d()
-	hw.E2d[0] = e2d.NewHwEngine2d(0, hw.Mc, gfx.LayerFunc{Func: hw.E3d.Draw3D})
+	hw.E2d[0] = e2d.NewHwEngine2d(0, hw.Mc, hw.E3d)
 	hw.E2d[1] = e2d.NewHwEngine2d(1, hw.Mc, nil)
+	e2d.ShareMixing(hw.E2d[0], hw.E2d[1])
 	hw.Lcd9 = NewHwLcd(nds9.Irq)
 	hw.Lcd7 = NewHwLcd(nds7.Irq)
 	hw.Ipc = NewHwIpc(nds9.Irq, nds7.Irq)
@@ -88,28 +113,65 @@ func NewNDSHardware(mem *NDSMemory, firmware string) *NDSHardware {
 	hw.Snd = NewHwSound(nds7.Bus)
 	hw.Geom = NewHwGeometry(nds9.Irq, hw.E3d)
 	hw.Sl2 = NewHwSlot2()
+	hw.Sl2.Irq = nds9.Irq
 
 	hw.Spi = NewHwSpiBus()
 	hw.Ff = NewHwFirmwareFlash()
-	hw.Spi.AddDevice(0, NewHwPowerMan())
+	hw.Pm = NewHwPowerMan()
+	hw.Spi.AddDevice(0, hw.Pm)
 	hw.Spi.AddDevice(1, hw.Ff)
 	hw.Spi.AddDevice(2, hw.Tsc)
 
 	return hw
 }
 
+// Log the problems found while verifying BIOS/firmware dumps, and exit if
+// they can't be used.
+func checkDumps(rep biosdump.Report) {
+	for _, p := range rep {
+		log.ModEmu.Warnf("%v", p)
+	}
+	if rep.Fatal() {
+		log.ModEmu.Fatal("unusable BIOS/firmware dump; run tools/verify-bios for details")
+	}
+}
+
+// Directory containing the BIOS dumps. If empty, the "bios" subdirectory
+// next to the executable is used.
+var biosDir string
+
+// Replace a missing BIOS dump with a stub. This is possible only if the
+// BIOS calls are emulated (see hlebios), and the boot is skipped.
+var biosStub9, biosStub7 bool
+
 func NewNDSRom() *NDSRom {
 	rom := new(NDSRom)
-	bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+	dir := biosDir
+	if dir == "" {
+		bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+		dir = filepath.Join(bindir, "bios")
+	}
 
-	bios9, err := ioutil.ReadFile(filepath.Join(bindir, "bios/biosnds9.rom"))
-	if err != nil {
+	bios9, err := ioutil.ReadFile(filepath.Join(dir, "biosnds9.rom"))
+	switch {
+	case err == nil:
+		checkDumps(biosdump.CheckBios9(bios9))
+	case biosStub9:
+		log.ModEmu.Warn("ARM9 BIOS not found, using the HLE stub")
+		bios9 = hlebios.Stub9()
+	default:
 		log.ModEmu.Fatal("error loading rom:", err)
 	}
 	rom.Bios9 = bios9
 
-	bios7, err := ioutil.ReadFile(filepath.Join(bindir, "bios/biosnds7.rom"))
-	if err != nil {
+	bios7, err := ioutil.ReadFile(filepath.Join(dir, "biosnds7.rom"))
+	switch {
+	case err == nil:
+		checkDumps(biosdump.CheckBios7(bios7))
+	case biosStub7:
+		log.ModEmu.Warn("ARM7 BIOS not found, using the HLE stub")
+		bios7 = hlebios.Stub7()
+	default:
 		log.ModEmu.Fatal("error loading rom:", err)
 	}
 	rom.Bios7 = bios7
@@ -134,11 +196,14 @@ func NewNDSEmulator(firmware string) *NDSEmulator {
 	sync.AddSubsystem(hw.Geom, "gx")
 
 	e := &NDSEmulator{
-		Mem:  mem,
-		Hw:   hw,
-		Rom:  rom,
-		Sync: sync,
+		Mem:    mem,
+		Hw:     hw,
+		Rom:    rom,
+		Sync:   sync,
+		IrqLog: new(IrqLog),
 	}
+	nds9.Irq.EnableLog(e)
+	nds7.Irq.EnableLog(e)
 
 	// Set the hsync callback to this instance's function
 	e.Sync.SetHSyncCallback(e.hsync)
@@ -153,12 +218,59 @@ func NewNDSEmulator(firmware string) *NDSEmulator {
 	// Initialize the memory map and reset the CPUs
 	nds9.InitBus(e)
 	nds7.InitBus(e)
+	e.initBusTimings()
 	nds9.Reset()
 	nds7.Reset()
 
 	return e
 }
 
+// SetConsoleModel configures the console model to emulate (the default is
+// the original DS). Only the power management device is affected (see
+// HwPowerMan.SetModel). EXTKEYIN is emulated in the same way on both
+// models: no difference in its bits is documented, so none is modeled.
+func (emu *NDSEmulator) SetConsoleModel(model ConsoleModel) {
+	emu.Hw.Pm.SetModel(model)
+}
+
+// SkipBoot loads the game card directly into memory, and sets up the
+// hardware as the BIOS and firmware leave it before jumping to the entry
+// points.
+func (emu *NDSEmulator) SkipBoot() error {
+	if err := InjectGamecard(emu.Hw.Gc, emu.Mem); err != nil {
+		return err
+	}
+
+	// Shared wram: map everything to ARM7
+	emu.Hw.Mc.WramCnt.Write8(0, 3)
+
+	// Set post-boot flag to 1
+	nds9.misc.PostFlg.Value = 1
+	nds7.misc.PostFlg.Value = 1
+
+	nds9.Irq.Ime.Value = 0x1
+	nds7.Irq.Ime.Value = 0x1
+	nds9.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
+	nds7.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
+
+	// VRAM: map everything in "LCDC mode"
+	emu.Hw.Mc.VramCntA.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntB.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntC.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntD.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntE.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntF.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntG.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntH.Write8(0, 0x80)
+	emu.Hw.Mc.VramCntI.Write8(0, 0x80)
+
+	// Gamecard: skip directly to key2 status
+	emu.Hw.Gc.stat = gcStatusKey2
+
+	nds9.Cp15.ConfigureControlReg(0x52078, 0x00FF085)
+	return nil
+}
+
 func (emu *NDSEmulator) StartDebugger() {
 	emu.dbg = debugger.New([]debugger.Cpu{nds7.Cpu, nds9.Cpu}, emu.Sync)
 
@@ -189,6 +301,7 @@ func (emu *NDSEmulator) StartDebugger() {
 		}
 	}
 
+	emu.dbg.AddView("IRQ log", emu.irqLogView)
 	go emu.dbg.Run()
 }
 
@@ -205,6 +318,10 @@ func (emu *NDSEmulator) eaOn() bool       { return emu.powcnt&(1<<1) != 0 }
 func (emu *NDSEmulator) ebOn() bool       { return emu.powcnt&(1<<9) != 0 }
 func (emu *NDSEmulator) lcdSwapped() bool { return emu.powcnt&(1<<15) != 0 }
 
+// hsync is the LCD controller: it's called by Sync at the dot positions
+// configured in SyncConfig, and clocks the DISPSTAT of both CPUs, both 2D
+// engines and the 3D engine from the same line counter, so that they are
+// never out of step with each other.
 func (emu *NDSEmulator) hsync(x, y int) {
 	emu.Hw.Lcd9.SyncEvent(x, y)
 	emu.Hw.Lcd7.SyncEvent(x, y)
@@ -266,10 +383,26 @@ func (emu *NDSEmulator) RunOneFrame(screen gfx.Buffer, audio []int16) {
 	// really necessary and it's hard to handle with our parallel system
 	emu.powcnt = nds9.misc.PowCnt.Value
 
+	// The screen buffer might be mapped texture memory with undefined
+	// contents, so the gap between the two screens must be cleared on
+	// every frame.
 	emu.screen = screen
+	for y := 192; y < 192+90; y++ {
+		clearScreenLine(screen.Line(y))
+	}
 	emu.audio = audio
 	emu.Sync.RunOneFrame()
 	emu.audio = nil
+
+	for i := range nds9.Dma {
+		nds9.Dma[i].endFrame()
+		nds7.Dma[i].endFrame()
+	}
+	emu.runFrameHooks(screen, audio)
+
+	if emu.Watchdog != nil {
+		emu.Watchdog.Check(emu)
+	}
 }
 
 func (emu *NDSEmulator) beginLine(y int) {
@@ -280,10 +413,32 @@ func (emu *NDSEmulator) beginLine(y int) {
 	}
 
 	if emu.eaOn() {
+		// In main memory display mode, the pixels of the line are
+		// transferred by DMA into the display FIFO of engine A, which
+		// requests a few words at a time.
+		for n := emu.Hw.E2d[0].MainMemFifoWords(); n > 0; {
+			nds9.TriggerDmaEvent(DmaEventMainMemDisplay)
+			m := emu.Hw.E2d[0].MainMemFifoWords()
+			if m == n {
+				// no DMA channel is feeding the FIFO
+				break
+			}
+			n = m
+		}
 		emu.Hw.E2d[0].BeginLine(y, emu.screen.Line(ya))
+	} else {
+		clearScreenLine(emu.screen.Line(ya))
 	}
 	if emu.ebOn() {
 		emu.Hw.E2d[1].BeginLine(y, emu.screen.Line(yb))
+	} else {
+		clearScreenLine(emu.screen.Line(yb))
+	}
+}
+
+func clearScreenLine(line gfx.Line) {
+	for x := 0; x < 256; x++ {
+		line.Set32(x, 0)
 	}
 }
 
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"ndsemu/e2d"
+	log "ndsemu/emu/logger"
+	"os"
+)
+
+// DumpNextFrame saves the layers of the next frame drawn by both 2D engines
+// (BG0-BG3, OBJ, the 3D layer if displayed, and the final output) as
+// separate PNG files, named after the frame number (eg:
+// "frame-000123-A-bg0.png"). An engine that is powered off is dumped when
+// it's turned back on. It can be called from any goroutine: the frame
+// number is read when the dump is complete, from the emulation goroutine.
+func (emu *NDSEmulator) DumpNextFrame() {
+	for _, e := range emu.Hw.E2d {
+		e.DumpNextFrame(func(d *e2d.FrameDump) {
+			// Encoding is slow, don't hold the emulation
+			go saveFrameDump(d, emu.framecount)
+		})
+	}
+}
+
+func saveFrameDump(d *e2d.FrameDump, frame int) {
+	prefix := fmt.Sprintf("frame-%06d-%c-", frame, d.Engine)
+	save := func(name string, img image.Image) {
+		fn := prefix + name + ".png"
+		f, err := os.Create(fn)
+		if err != nil {
+			log.ModEmu.Errorf("frame dump: %v", err)
+			return
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			log.ModEmu.Errorf("frame dump: %s: %v", fn, err)
+		}
+	}
+
+	for i, bg := range d.BG {
+		save(fmt.Sprintf("bg%d", i), bg)
+	}
+	save("obj", d.OBJ)
+	if d.Layer3D != nil {
+		save("3d", d.Layer3D)
+	}
+	save("composite", d.Composite)
+	log.ModEmu.Warnf("frame %d of engine %c dumped", frame, d.Engine)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"ndsemu/arm"
+	"ndsemu/emu/gfx"
+	"os"
+)
+
+// FrameHasher writes a log with a hash of the emulator output and state for
+// each frame, so that two runs of the same input script (eg: with two
+// different builds) can be compared to find out the first frame, and the
+// subsystem, where emulation diverges (see tools/framebisect).
+//
+// Each line of the log contains the frame number followed by a list of
+// subsystem=hash pairs:
+//
+//	120 video=6c62272e07bb0142 audio=4d4ae8ac1d5e86f5
+//
+// Output (video and audio) is hashed on every frame. Hashing the full
+// machine state (CPU registers and memories) is much slower, so it's done
+// periodically, plus on all frames within an optional window, which is
+// used to narrow down a divergence found between two checkpoints.
+type FrameHasher struct {
+	f      *os.File
+	w      *bufio.Writer
+	h      hash.Hash64
+	buf    []byte
+	period int
+	win0   int
+	win1   int
+}
+
+// NewFrameHasher creates a frame hash log. The machine state is hashed every
+// period frames (0 means never), and on every frame in [win0, win1] (use
+// an empty range like [0, -1] to disable the window).
+func NewFrameHasher(fn string, period int, win0, win1 int) (*FrameHasher, error) {
+	f, err := os.Create(fn)
+	if err != nil {
+		return nil, err
+	}
+	return &FrameHasher{
+		f:      f,
+		w:      bufio.NewWriter(f),
+		h:      fnv.New64a(),
+		period: period,
+		win0:   win0,
+		win1:   win1,
+	}, nil
+}
+
+func (fh *FrameHasher) sum(data ...[]byte) uint64 {
+	fh.h.Reset()
+	for _, d := range data {
+		fh.h.Write(d)
+	}
+	return fh.h.Sum64()
+}
+
+func (fh *FrameHasher) scratch(n int) []byte {
+	if len(fh.buf) < n {
+		fh.buf = make([]byte, n)
+	}
+	return fh.buf[:n]
+}
+
+func (fh *FrameHasher) cpuState(cpu *arm.Cpu) []byte {
+	buf := fh.scratch(17 * 4)
+	for i, r := range cpu.Regs {
+		binary.LittleEndian.PutUint32(buf[i*4:], uint32(r))
+	}
+	binary.LittleEndian.PutUint32(buf[16*4:], cpu.Cpsr.Uint32())
+	return buf
+}
+
+// Hash must be called at the end of each frame, while the emulation is
+// stopped.
+func (fh *FrameHasher) Hash(frame int, screen gfx.Buffer, audio []int16) {
+	fh.h.Reset()
+	for y := 0; y < screen.Height; y++ {
+		fh.h.Write(screen.LineAsSlice(y))
+	}
+	video := fh.h.Sum64()
+
+	abuf := fh.scratch(len(audio) * 2)
+	for i, s := range audio {
+		binary.LittleEndian.PutUint16(abuf[i*2:], uint16(s))
+	}
+	fmt.Fprintf(fh.w, "%d video=%016x audio=%016x", frame, video, fh.sum(abuf))
+
+	if (fh.period > 0 && frame%fh.period == 0) || (frame >= fh.win0 && frame <= fh.win1) {
+		mem := Emu.Mem
+		fmt.Fprintf(fh.w, " cpu9=%016x", fh.sum(fh.cpuState(nds9.Cpu)))
+		fmt.Fprintf(fh.w, " cpu7=%016x", fh.sum(fh.cpuState(nds7.Cpu)))
+		fmt.Fprintf(fh.w, " ram=%016x", fh.sum(mem.Ram[:]))
+		fmt.Fprintf(fh.w, " wram=%016x", fh.sum(mem.Wram[:]))
+		fmt.Fprintf(fh.w, " vram=%016x", fh.sum(mem.Vram[:], mem.PaletteRam[:], mem.OamRam[:]))
+	}
+	fmt.Fprintf(fh.w, "\n")
+}
+
+func (fh *FrameHasher) Close() error {
+	if err := fh.w.Flush(); err != nil {
+		fh.f.Close()
+		return err
+	}
+	return fh.f.Close()
+}
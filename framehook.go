@@ -0,0 +1,52 @@
+package main
+
+import "ndsemu/emu/gfx"
+
+// FrameHook is a function that observes each frame completed by the
+// emulator, eg: to stream or analyze the output. video contains both screens
+// (the upper one at the top, then a 90-line gap, then the lower one), and
+// audio the stereo samples of the frame (interleaved, at cAudioFreq); audio
+// is nil if the emulator is not producing audio.
+//
+// Hooks are called from the emulation goroutine, at the end of each frame,
+// and the buffers are reused for the next frames: they must be copied if
+// needed after the hook returns. Hooks should be fast, as they delay the
+// emulation.
+type FrameHook func(video gfx.Buffer, audio []int16)
+
+type frameHook struct {
+	fn FrameHook
+}
+
+// RegisterFrameHook adds a hook that is called after each frame (see
+// FrameHook), and returns a function that removes it. Hooks can be added and
+// removed from any goroutine, and are called in the order in which they were
+// added.
+func (emu *NDSEmulator) RegisterFrameHook(fn FrameHook) (unregister func()) {
+	h := &frameHook{fn}
+	emu.hookMu.Lock()
+	emu.hooks = append(emu.hooks, h)
+	emu.hookMu.Unlock()
+
+	return func() {
+		emu.hookMu.Lock()
+		defer emu.hookMu.Unlock()
+		for i := range emu.hooks {
+			if emu.hooks[i] == h {
+				// Copy the slice, as it might be in use by runFrameHooks
+				emu.hooks = append(emu.hooks[:i:i], emu.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (emu *NDSEmulator) runFrameHooks(video gfx.Buffer, audio []int16) {
+	emu.hookMu.Lock()
+	hooks := emu.hooks
+	emu.hookMu.Unlock()
+
+	for _, h := range hooks {
+		h.fn(video, audio)
+	}
+}
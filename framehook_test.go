@@ -0,0 +1,37 @@
+package main
+
+import (
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func TestFrameHooks(t *testing.T) {
+	emu := new(NDSEmulator)
+	var calls []string
+	hook := func(name string) FrameHook {
+		return func(video gfx.Buffer, audio []int16) {
+			if len(audio) != 4 {
+				t.Errorf("%s: got %d audio samples, want 4", name, len(audio))
+			}
+			calls = append(calls, name)
+		}
+	}
+	unreg1 := emu.RegisterFrameHook(hook("a"))
+	emu.RegisterFrameHook(hook("b"))
+
+	video := gfx.NewBufferMem(256, 192+90+192)
+	audio := make([]int16, 4)
+	emu.runFrameHooks(video, audio)
+	unreg1()
+	unreg1() // no-op
+	emu.runFrameHooks(video, audio)
+
+	if got, want := len(calls), 3; got != want {
+		t.Fatalf("got %d calls (%v), want %d", got, calls, want)
+	}
+	for i, want := range []string{"a", "b", "b"} {
+		if calls[i] != want {
+			t.Errorf("call %d: got %s, want %s", i, calls[i], want)
+		}
+	}
+}
@@ -61,9 +61,12 @@ func NewGamecard(biosfn string, bkp *HwBackupRam) *Gamecard {
 	gc.spi.AddDevice(0, bkp)
 	gc.bkp = bkp
 
+	// The KEY1 tables are only needed to boot through the BIOS, so they
+	// can be missing if the boot is skipped
 	f, err := os.Open(biosfn)
 	if err != nil {
-		panic(err)
+		modGamecard.Warn("KEY1 encryption not available: ", err)
+		return gc
 	}
 	f.ReadAt(gc.key1Tables[:], 0x30)
 	f.Close()
@@ -104,6 +107,29 @@ func (gc *Gamecard) MapCartFile(fn string) error {
 	return nil
 }
 
+// A ReaderAt for an empty slot: the data lines are pulled up, so all bytes
+// read as 0xFF.
+type openBusReader struct{}
+
+func (openBusReader) ReadAt(buf []byte, off int64) (int, error) {
+	for i := range buf {
+		buf[i] = 0xFF
+	}
+	return len(buf), nil
+}
+
+// Eject simulates the removal of the card from slot 1: from now on, all
+// reads return 0xFF (including the chip ID, which is how games detect the
+// removal), and the card IRQ is raised on the CPU that owns the slot.
+func (gc *Gamecard) Eject() {
+	gc.MapCart(openBusReader{})
+	gc.Size = 0
+	gc.chipid = [4]byte{0xFF, 0xFF, 0xFF, 0xFF}
+	if gc.Irq != nil {
+		gc.Irq.Raise(IrqGameCardEject)
+	}
+}
+
 func (gc *Gamecard) WriteAUXSPICNT(old, value uint16) {
 	modGamecard.Infof("Write AUXSPICNT %04x", value)
 	if (old^value)&(1<<13) != 0 {
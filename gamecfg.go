@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	log "ndsemu/emu/logger"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GameSettings are the graphics enhancement settings that can be stored for
+// each game in the game configuration database, so that they don't need to
+// be passed on the command line at every run. Each field mirrors the
+// command line flag with the same name; empty/zero fields are not applied.
+type GameSettings struct {
+	Renderer string `json:"3drenderer,omitempty"`
+	Scale    int    `json:"3dscale,omitempty"`
+	FillRule string `json:"3dfillrule,omitempty"`
+}
+
+// GameConfigDB is the game configuration database, indexed by the 4-char
+// game code found in the ROM header. It's saved as a JSON file.
+type GameConfigDB map[string]GameSettings
+
+// LoadGameConfigDB loads the database from a file. A missing file is not an
+// error, and returns an empty database.
+func LoadGameConfigDB(fn string) (GameConfigDB, error) {
+	db := make(GameConfigDB)
+	data, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return db, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn, err)
+	}
+	return db, nil
+}
+
+func (db GameConfigDB) Save(fn string) error {
+	data, err := json.MarshalIndent(db, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fn, append(data, '\n'), 0666)
+}
+
+// Read the game code from the header of a ROM file
+func romGameCode(fn string) (string, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var code [4]byte
+	if _, err := f.ReadAt(code[:], 0x0C); err != nil {
+		return "", fmt.Errorf("%s: cannot read game code: %v", fn, err)
+	}
+	return string(code[:]), nil
+}
+
+// Flags that are part of GameSettings
+var gameSettingsFlags = map[string]bool{
+	"3drenderer": true,
+	"3dscale":    true,
+	"3dfillrule": true,
+}
+
+// Resolve a relative path of the game configuration database. It's looked up
+// in each of the specified directories in order, and if it doesn't exist yet,
+// it will be created in the last one.
+func gameConfigPath(fn string, dirs ...string) string {
+	if filepath.IsAbs(fn) || len(dirs) == 0 {
+		return fn
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, fn)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dirs[len(dirs)-1], fn)
+}
+
+// applyGameSettings looks up the settings of the game in the ROM file, and
+// applies them to the flags of fs that were not explicitly set on the command
+// line. If save is true, the current values of the flags are stored into the
+// database instead, for the next runs. It returns a short note describing
+// what was done (empty if nothing), to be shown to the user.
+func applyGameSettings(fs *flag.FlagSet, dbfn, romfn string, save bool) (string, error) {
+	code, err := romGameCode(romfn)
+	if err != nil {
+		return "", err
+	}
+	db, err := LoadGameConfigDB(dbfn)
+	if err != nil {
+		return "", err
+	}
+
+	if save {
+		var gs GameSettings
+		gs.Renderer = fs.Lookup("3drenderer").Value.String()
+		gs.Scale = fs.Lookup("3dscale").Value.(flag.Getter).Get().(int)
+		gs.FillRule = fs.Lookup("3dfillrule").Value.String()
+		db[code] = gs
+		if err := db.Save(dbfn); err != nil {
+			return "", err
+		}
+		log.ModEmu.WithField("game", strconv.Quote(code)).Warnf("graphics settings saved into %s: %+v", dbfn, gs)
+		return "game settings saved", nil
+	}
+
+	gs, found := db[code]
+	if !found {
+		return "", nil
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		if gameSettingsFlags[f.Name] {
+			explicit[f.Name] = true
+		}
+	})
+
+	var applied []string
+	set := func(name, val string, empty bool) {
+		if empty || explicit[name] {
+			return
+		}
+		if err := fs.Set(name, val); err != nil {
+			log.ModEmu.Warnf("invalid %s in game settings: %v", name, err)
+			return
+		}
+		applied = append(applied, "-"+name+"="+val)
+	}
+	set("3drenderer", gs.Renderer, gs.Renderer == "")
+	set("3dscale", strconv.Itoa(gs.Scale), gs.Scale == 0)
+	set("3dfillrule", gs.FillRule, gs.FillRule == "")
+
+	if len(applied) == 0 {
+		return "", nil
+	}
+	log.ModEmu.WithField("game", strconv.Quote(code)).Warnf("applied graphics settings: %v", applied)
+	return "game settings: " + strings.Join(applied, " "), nil
+}
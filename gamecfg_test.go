@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Write a fake ROM file with the specified game code in its header
+func writeTestRom(t *testing.T, dir, code string) string {
+	rom := make([]byte, 0x200)
+	copy(rom[0x0C:], code)
+	fn := filepath.Join(dir, code+".nds")
+	if err := ioutil.WriteFile(fn, rom, 0666); err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
+
+func newGameSettingsFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("3drenderer", "soft", "")
+	fs.Int("3dscale", 1, "")
+	fs.String("3dfillrule", "nds", "")
+	return fs
+}
+
+func TestLoadGameConfigDB(t *testing.T) {
+	dir := t.TempDir()
+
+	// A missing database is empty
+	db, err := LoadGameConfigDB(filepath.Join(dir, "missing.json"))
+	if err != nil || len(db) != 0 {
+		t.Fatalf("missing file: got %v, %v", db, err)
+	}
+
+	fn := filepath.Join(dir, "games.json")
+	want := GameConfigDB{
+		"ABCD": {Renderer: "gl", Scale: 2},
+		"EFGH": {FillRule: "topleft"},
+	}
+	if err := want.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	db, err = LoadGameConfigDB(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(db, want) {
+		t.Errorf("got %v, want %v", db, want)
+	}
+
+	if err := ioutil.WriteFile(fn, []byte(`{"ABCD": {"3dscale": "x"}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGameConfigDB(fn); err == nil {
+		t.Errorf("invalid database loaded without errors")
+	}
+}
+
+func TestApplyGameSettings(t *testing.T) {
+	dir := t.TempDir()
+	dbfn := filepath.Join(dir, "games.json")
+	db := GameConfigDB{"ABCD": {Renderer: "gl", Scale: 2, FillRule: "topleft"}}
+	if err := db.Save(dbfn); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the flags not set on the command line are changed
+	fs := newGameSettingsFlags()
+	fs.Parse([]string{"-3dscale=4"})
+	note, err := applyGameSettings(fs, dbfn, writeTestRom(t, dir, "ABCD"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"3drenderer": "gl", "3dscale": "4", "3dfillrule": "topleft"} {
+		if got := fs.Lookup(name).Value.String(); got != want {
+			t.Errorf("-%s=%s, want %s", name, got, want)
+		}
+	}
+	if want := "game settings: -3drenderer=gl -3dfillrule=topleft"; note != want {
+		t.Errorf("note: got %q, want %q", note, want)
+	}
+
+	// Games without settings are left alone
+	fs = newGameSettingsFlags()
+	note, err = applyGameSettings(fs, dbfn, writeTestRom(t, dir, "WXYZ"), false)
+	if err != nil || note != "" || fs.Lookup("3drenderer").Value.String() != "soft" {
+		t.Errorf("unknown game: note=%q err=%v", note, err)
+	}
+
+	// Saving stores the current values of all the flags
+	fs = newGameSettingsFlags()
+	fs.Parse([]string{"-3dscale=3"})
+	if _, err := applyGameSettings(fs, dbfn, writeTestRom(t, dir, "WXYZ"), true); err != nil {
+		t.Fatal(err)
+	}
+	db, err = LoadGameConfigDB(dbfn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := db["WXYZ"], (GameSettings{Renderer: "soft", Scale: 3, FillRule: "nds"}); got != want {
+		t.Errorf("saved settings: got %+v, want %+v", got, want)
+	}
+	if len(db) != 2 {
+		t.Errorf("other games lost while saving: %v", db)
+	}
+}
+
+func TestGameConfigPath(t *testing.T) {
+	cwd, bindir := t.TempDir(), t.TempDir()
+
+	// Not found anywhere: created next to the executable
+	if got, want := gameConfigPath("games.json", cwd, bindir), filepath.Join(bindir, "games.json"); got != want {
+		t.Errorf("missing: got %s, want %s", got, want)
+	}
+	if err := ioutil.WriteFile(filepath.Join(bindir, "games.json"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gameConfigPath("games.json", cwd, bindir), filepath.Join(bindir, "games.json"); got != want {
+		t.Errorf("bindir: got %s, want %s", got, want)
+	}
+
+	// The working directory takes precedence
+	if err := ioutil.WriteFile(filepath.Join(cwd, "games.json"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gameConfigPath("games.json", cwd, bindir), filepath.Join(cwd, "games.json"); got != want {
+		t.Errorf("cwd: got %s, want %s", got, want)
+	}
+
+	abs := filepath.Join(os.TempDir(), "x.json")
+	if got := gameConfigPath(abs, cwd, bindir); got != abs {
+		t.Errorf("absolute path changed: %s", got)
+	}
+}
@@ -19,6 +19,15 @@ type GxCmd struct {
 	parm uint32    // 32-bit cmd arg
 }
 
+const (
+	// Number of entries in the GX FIFO, plus the additional entries of the
+	// PIPE that sits between the FIFO and the geometry engine. Commands are
+	// moved into the PIPE as soon as there is room, so the FIFO (as reported
+	// in GXSTAT) only begins to fill once the PIPE is full.
+	cGxFifoSize = 256
+	cGxPipeSize = 4
+)
+
 type GxCmdDesc struct {
 	parms   int
 	ncycles int64
@@ -35,6 +44,10 @@ type HwGeometry struct {
 	// Bank 1 (0x4000600). Status and results
 	GxStat     hwio.Reg32 `hwio:"bank=1,offset=0,rwmask=0xC0008000,rcb,wcb"`
 	RamCount   hwio.Reg32 `hwio:"bank=1,offset=4,readonly,rcb"`
+	PosResultX hwio.Reg32 `hwio:"bank=1,offset=0x20,readonly,rcb"`
+	PosResultY hwio.Reg32 `hwio:"bank=1,offset=0x24,readonly,rcb"`
+	PosResultZ hwio.Reg32 `hwio:"bank=1,offset=0x28,readonly,rcb"`
+	PosResultW hwio.Reg32 `hwio:"bank=1,offset=0x2C,readonly,rcb"`
 	VecResultX hwio.Reg16 `hwio:"bank=1,offset=0x30,readonly,rcb"`
 	VecResultY hwio.Reg16 `hwio:"bank=1,offset=0x32,readonly,rcb"`
 	VecResultZ hwio.Reg16 `hwio:"bank=1,offset=0x34,readonly,rcb"`
@@ -91,8 +104,10 @@ func (g *HwGeometry) ReadGXSTAT(val uint32) uint32 {
 		}
 	}
 
-	// FIXME: for now, always return OK to "box test" command (not implemented)
-	val |= 1 << 1
+	// Bit 1: result of last box test
+	if g.gx.boxTestResult {
+		val |= 1 << 1
+	}
 
 	if g.fifoLessThanHalfFull() {
 		val |= (1 << 25)
@@ -104,8 +119,8 @@ func (g *HwGeometry) ReadGXSTAT(val uint32) uint32 {
 		val |= (1 << 27) // busy bit
 	}
 
-	// Bits 16-24: Entries in the FIFO
-	val |= (uint32(len(g.fifo)) & 0x1ff) << 16
+	// Bits 16-24: Entries in the FIFO (excluding the PIPE)
+	val |= (uint32(g.fifoCount()) & 0x1ff) << 16
 
 	// Bits 8-12: Position matrix stack (only 5 bits)
 	val |= (uint32(g.gx.mtxStackPosPtr) & 0x1F) << 8
@@ -134,19 +149,15 @@ func (g *HwGeometry) ReadRAMCOUNT(_ uint32) uint32 {
 	vtx := Emu.Hw.E3d.NumVertices()
 	poly := Emu.Hw.E3d.NumPolygons()
 
-	if vtx > 2048 {
-		vtx = 2048
+	// Polygon RAM holds 2048 polygons, vertex RAM holds 6144 vertices
+	if poly > 2048 {
+		poly = 2048
 	}
-	if poly > 6144 {
-		poly = 6144
+	if vtx > 6144 {
+		vtx = 6144
 	}
 
-	// FIXME: used by zelda-hourglass, but we can't
-	// implement it properly until clipping is implemented,
-	// otherwise the returned value makes the game hang
-	//
-	// modGxFifo.Warnf("RAMCOUNT: %d,%d", vtx, poly)
-	return 0 //uint32(vtx)<<16 | uint32(poly)
+	return uint32(vtx)<<16 | uint32(poly)
 }
 
 func (g *HwGeometry) readVecResult(vec emu.Fixed12) uint16 {
@@ -167,19 +178,30 @@ func (g *HwGeometry) readVecResult(vec emu.Fixed12) uint16 {
 	return n
 }
 
+func (g *HwGeometry) ReadPOSRESULTX(_ uint32) uint32 { return uint32(g.gx.posTestResult[0].V) }
+func (g *HwGeometry) ReadPOSRESULTY(_ uint32) uint32 { return uint32(g.gx.posTestResult[1].V) }
+func (g *HwGeometry) ReadPOSRESULTZ(_ uint32) uint32 { return uint32(g.gx.posTestResult[2].V) }
+func (g *HwGeometry) ReadPOSRESULTW(_ uint32) uint32 { return uint32(g.gx.posTestResult[3].V) }
+
 func (g *HwGeometry) ReadVECRESULTX(_ uint16) uint16 { return g.readVecResult(g.gx.vecTestResult[0]) }
 func (g *HwGeometry) ReadVECRESULTY(_ uint16) uint16 { return g.readVecResult(g.gx.vecTestResult[1]) }
 func (g *HwGeometry) ReadVECRESULTZ(_ uint16) uint16 { return g.readVecResult(g.gx.vecTestResult[2]) }
 
 func (g *HwGeometry) WriteGXSTAT(old, val uint32) {
+	// Bit 15 is the matrix stack overflow flag, which is acknowledged
+	// by writing 1. The flag itself is kept in the geometry engine, so
+	// never store it into the register.
 	g.GxStat.Value &^= 0x8000
 	if val&0x8000 != 0 {
-		// turn off overflow flag
-		old &^= 0x8000
-		// Also reset matrix stat pointer
+		g.gx.mtxStackOverflow = false
+		// Also reset the projection and texture matrix stack pointers
 		g.gx.mtxStackProjPtr = 0
+		g.gx.mtxStackTexPtr = 0
 	}
-	g.GxStat.Value |= old & 0x8000
+
+	// The IRQ mode (bits 30-31) might have been changed, so the
+	// IRQ line must be reevaluated.
+	g.updateIrq()
 }
 
 func (g *HwGeometry) WriteGXFIFO(addr uint32, bytes int) {
@@ -253,23 +275,33 @@ func (g *HwGeometry) updateIrq() {
 	}
 }
 
+// fifoCount returns the number of entries in the FIFO. We store both the PIPE
+// and the FIFO in the same queue, where the first cGxPipeSize entries are
+// the PIPE.
+func (g *HwGeometry) fifoCount() int {
+	if len(g.fifo) <= cGxPipeSize {
+		return 0
+	}
+	return len(g.fifo) - cGxPipeSize
+}
+
 func (g *HwGeometry) fifoEmpty() bool {
-	// FIXME: this doesn't include the 4-slot pipe into account.
-	return len(g.fifo) == 0
+	return g.fifoCount() == 0
 }
 
 func (g *HwGeometry) fifoLessThanHalfFull() bool {
-	// FIXME: this doesn't include the 4-slot pipe into account.
-	// We should probably increase the fifo by 4 entries, and then use
-	// "< 128+4" here.
-	return len(g.fifo) < 128
+	return g.fifoCount() < cGxFifoSize/2
+}
+
+func (g *HwGeometry) fifoFull() bool {
+	return g.fifoCount() >= cGxFifoSize
 }
 
 func (g *HwGeometry) fifoPush(when int64, code uint8, parm uint32) {
 	// If the FIFO is full, try synchronize the geometry processor
 	// up to the current timestamp. This might be enough to flush
 	// the FIFO a little bit and make room for the new command.
-	if len(g.fifo) >= 256 {
+	if g.fifoFull() {
 		g.Run(Emu.Sync.Cycles())
 	}
 
@@ -277,7 +309,7 @@ func (g *HwGeometry) fifoPush(when int64, code uint8, parm uint32) {
 	// really writing to a full FIFO. The CPU will be blocked
 	// until the FIFO frees up a space.
 	panicCount := 0
-	for len(g.fifo) >= 256 {
+	for g.fifoFull() {
 		// Burn CPU cycles that should be enough to execute
 		// the next FIFO command.
 		cycles := g.nextCmdCycles()
@@ -288,6 +320,14 @@ func (g *HwGeometry) fifoPush(when int64, code uint8, parm uint32) {
 			// them from the FIFO and skip them.
 			cycles = 1
 		}
+
+		// The geometry engine might also be ahead of the CPU, still busy
+		// with the previous command: this is especially true after
+		// SWAP_BUFFERS, which halts the engine until VBlank. In this case,
+		// the CPU is stalled until the engine gets to the next command.
+		if now := Emu.Sync.Cycles(); g.cycles > now {
+			cycles += g.cycles - now
+		}
 		nds9.Cpu.Clock += cycles * 2
 
 		// Now synchronize the geometry engine. Since the CPU has
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"math/bits"
 	"ndsemu/emu"
 	"ndsemu/raster3d"
 )
@@ -168,6 +169,7 @@ type GeometryEngine struct {
 	// Material and lights
 	material  [4]fcolor
 	spectable bool
+	shininess [128]uint8
 	lights    [4]struct {
 		dir   vector
 		half  vector
@@ -190,7 +192,8 @@ type GeometryEngine struct {
 	}
 	vcnt int
 
-	// Pos/Vec test results
+	// Box/Pos/Vec test results
+	boxTestResult bool
 	posTestResult vector
 	vecTestResult vector
 
@@ -200,8 +203,19 @@ type GeometryEngine struct {
 
 func (gx *GeometryEngine) CalcCmdCycles(code GxCmdCode) int64 {
 	cycles := gxCmdDescs[code].ncycles
-	if gx.mtxmode == 2 && (code == 0x18 || code == 0x19 || code == 0x1A || code == 0x1C) {
-		cycles += 30
+	switch code {
+	case GX_MTX_MULT_4x4, GX_MTX_MULT_4x3, GX_MTX_MULT_3x3, GX_MTX_TRANS:
+		// In position&vector mode, the direction matrix is updated as well
+		if gx.mtxmode == 2 {
+			cycles += 30
+		}
+	case GX_NORMAL:
+		// Lighting is computed while the normal is being processed:
+		// each enabled light after the first one costs one more cycle
+		// (9..12 cycles).
+		if lights := bits.OnesCount32(gx.displist.polyattr & 0xF); lights > 1 {
+			cycles += int64(lights - 1)
+		}
 	}
 	return cycles
 }
@@ -355,17 +369,27 @@ func (gx *GeometryEngine) cmdViewport(parms []GxCmd) {
  * Matrix stack commands
  ******************************************************************/
 
+// Matrix stacks have the following sizes:
+//
+//   - Projection: 1 entry, 1-bit pointer.
+//   - Position/Direction: 31 entries (0-30), 6-bit pointer. Entry 31 exists
+//     too, and can be accessed after an overflow, or through MTX_STORE and
+//     MTX_RESTORE (setting the error flag anyway).
+//   - Texture: 1 entry, 1-bit pointer.
+//
+// Any overflow or underflow sets the error flag in GXSTAT. Pushing into a
+// full 1-entry stack (or popping from an empty one) has no other effect,
+// while the position stack pointer just keeps moving, wrapping at 64.
+
 func (gx *GeometryEngine) cmdMtxPush(parms []GxCmd) {
 	switch gx.mtxmode {
 	case 0:
 		if gx.mtxStackProjPtr > 0 {
 			gx.mtxStackOverflow = true
+			return
 		}
-
-		// The "1" entry is a mirror of "0", so always access 0
 		gx.mtxStackProj[0] = gx.mtx[0]
 		gx.mtxStackProjPtr++
-		gx.mtxStackProjPtr &= 1
 	case 1, 2:
 		if gx.mtxStackPosPtr > 30 {
 			gx.mtxStackOverflow = true
@@ -375,8 +399,13 @@ func (gx *GeometryEngine) cmdMtxPush(parms []GxCmd) {
 		gx.mtxStackDir[gx.mtxStackPosPtr&31] = gx.mtx[2]
 		gx.mtxStackPosPtr++
 		gx.mtxStackPosPtr &= 63
-	default:
-		modGx.Fatalf("unsupported MTX_PUSH for mode=%d", gx.mtxmode)
+	case 3:
+		if gx.mtxStackTexPtr > 0 {
+			gx.mtxStackOverflow = true
+			return
+		}
+		gx.mtxStackTex[0] = gx.mtx[3]
+		gx.mtxStackTexPtr++
 	}
 }
 
@@ -384,14 +413,11 @@ func (gx *GeometryEngine) cmdMtxPop(parms []GxCmd) {
 	switch gx.mtxmode {
 	case 0:
 		// NOTE: the offset parameter is ignored
-		gx.mtxStackProjPtr -= 1
-		gx.mtxStackProjPtr &= 1
-
-		if gx.mtxStackProjPtr > 0 {
+		if gx.mtxStackProjPtr == 0 {
 			gx.mtxStackOverflow = true
+			return
 		}
-
-		// The "1" entry is a mirror of "0", so always access 0
+		gx.mtxStackProjPtr--
 		gx.mtx[0] = gx.mtxStackProj[0]
 		gx.recalcClipMtx()
 	case 1, 2:
@@ -407,8 +433,14 @@ func (gx *GeometryEngine) cmdMtxPop(parms []GxCmd) {
 		gx.mtx[1] = gx.mtxStackPos[gx.mtxStackPosPtr&31]
 		gx.mtx[2] = gx.mtxStackDir[gx.mtxStackPosPtr&31]
 		gx.recalcClipMtx()
-	default:
-		modGx.Fatalf("unsupported MTX_POP for mode=%d", gx.mtxmode)
+	case 3:
+		// NOTE: the offset parameter is ignored
+		if gx.mtxStackTexPtr == 0 {
+			gx.mtxStackOverflow = true
+			return
+		}
+		gx.mtxStackTexPtr--
+		gx.mtx[3] = gx.mtxStackTex[0]
 	}
 }
 
@@ -419,8 +451,7 @@ func (gx *GeometryEngine) cmdMtxStore(parms []GxCmd) {
 	case 1, 2:
 		idx := int(parms[0].parm & 0x1F)
 		if idx > 30 {
-			// OVERFLOW FLAG
-			modGx.Fatal("MTX_STORE caused overflow in pos stack")
+			gx.mtxStackOverflow = true
 		}
 		gx.mtxStackPos[idx] = gx.mtx[1]
 		gx.mtxStackDir[idx] = gx.mtx[2]
@@ -437,8 +468,7 @@ func (gx *GeometryEngine) cmdMtxRestore(parms []GxCmd) {
 	case 1, 2:
 		idx := int(parms[0].parm & 0x1F)
 		if idx > 30 {
-			// OVERFLOW FLAG
-			modGx.Fatal("MTX_RESTORE caused overflow in pos stack")
+			gx.mtxStackOverflow = true
 		}
 		gx.mtx[1] = gx.mtxStackPos[idx]
 		gx.mtx[2] = gx.mtxStackDir[idx]
@@ -678,10 +708,11 @@ func (gx *GeometryEngine) cmdVtxDiff(parms []GxCmd) {
 	yd := int32(((parms[0].parm>>10)&0x3FF)<<22) >> 22
 	zd := int32(((parms[0].parm>>20)&0x3FF)<<22) >> 22
 
+	// Coordinates are kept in 16-bit registers, so the sum wraps around
 	var v vector
-	v[0].V = gx.displist.lastvtx[0].V + xd
-	v[1].V = gx.displist.lastvtx[1].V + yd
-	v[2].V = gx.displist.lastvtx[2].V + zd
+	v[0].V = int32(int16(gx.displist.lastvtx[0].V + xd))
+	v[1].V = int32(int16(gx.displist.lastvtx[1].V + yd))
+	v[2].V = int32(int16(gx.displist.lastvtx[2].V + zd))
 	v[3] = emu.NewFixed12(1)
 	modGx.Infof("vdiff: %08x -> %v\n", parms[0].parm, v)
 
@@ -766,7 +797,18 @@ func (gx *GeometryEngine) cmdNormal(parms []GxCmd) {
 		if shinelvl.V < 0 {
 			shinelvl.V = 0
 		}
-		shinelvl.V *= shinelvl.V
+		shinelvl = shinelvl.MulFixed(shinelvl)
+
+		// If the specular reflection table is enabled, the shininess level
+		// is used as an index into it (7-bit), and the 8-bit table entry
+		// is the actual level.
+		if gx.spectable {
+			idx := shinelvl.V >> 5
+			if idx > 127 {
+				idx = 127
+			}
+			shinelvl.V = int32(gx.shininess[idx]) << 4
+		}
 
 		for x := 0; x < 3; x++ {
 			if shinelvl.V > 0 {
@@ -784,7 +826,84 @@ func (gx *GeometryEngine) cmdNormal(parms []GxCmd) {
 }
 
 func (gx *GeometryEngine) cmdShininess(parms []GxCmd) {
-	// TODO: implement
+	for i := range parms {
+		gx.shininess[i*4+0] = uint8(parms[i].parm >> 0)
+		gx.shininess[i*4+1] = uint8(parms[i].parm >> 8)
+		gx.shininess[i*4+2] = uint8(parms[i].parm >> 16)
+		gx.shininess[i*4+3] = uint8(parms[i].parm >> 24)
+	}
+}
+
+func (gx *GeometryEngine) cmdBoxTest(parms []GxCmd) {
+	var x, y, z, w, h, d emu.Fixed12
+	x.V = int32(int16(parms[0].parm))
+	y.V = int32(int16(parms[0].parm >> 16))
+	z.V = int32(int16(parms[1].parm))
+	w.V = int32(int16(parms[1].parm >> 16))
+	h.V = int32(int16(parms[2].parm))
+	d.V = int32(int16(parms[2].parm >> 16))
+
+	// Transform the 8 corners of the box into clip space, and compute
+	// the same outcodes used by the rasterizer for clipping.
+	var clipany, clipall uint = 0, 0x3F
+	for i := 0; i < 8; i++ {
+		var v vector
+		v[0], v[1], v[2] = x, y, z
+		if i&1 != 0 {
+			v[0] = v[0].AddFixed(w)
+		}
+		if i&2 != 0 {
+			v[1] = v[1].AddFixed(h)
+		}
+		if i&4 != 0 {
+			v[2] = v[2].AddFixed(d)
+		}
+		v[3] = emu.NewFixed12(1)
+		vw := gx.clipmtx.VecMul(v)
+
+		var out uint
+		if vw[0].V < -vw[3].V {
+			out |= 1 << 0
+		}
+		if vw[0].V > vw[3].V {
+			out |= 1 << 1
+		}
+		if vw[1].V < -vw[3].V {
+			out |= 1 << 2
+		}
+		if vw[1].V > vw[3].V {
+			out |= 1 << 3
+		}
+		if vw[2].V < -vw[3].V {
+			out |= 1 << 4
+		}
+		if vw[2].V > vw[3].V {
+			out |= 1 << 5
+		}
+		clipany |= out
+		clipall &= out
+	}
+
+	// If any corner is inside the view volume, the box is visible.
+	// If all corners are outside of the same plane, it is not. The
+	// remaining cases (corners outside of different planes) are
+	// approximated as visible: this is conservative, as it might only
+	// cause a game to send geometry that will be clipped anyway.
+	gx.boxTestResult = clipany == 0 || clipall == 0
+	modGx.Debugf("box test: (%v,%v,%v)-(%v,%v,%v) -> %v", x, y, z, w, h, d, gx.boxTestResult)
+}
+
+func (gx *GeometryEngine) cmdPosTest(parms []GxCmd) {
+	var v vector
+	v[0].V = int32(int16(parms[0].parm))
+	v[1].V = int32(int16(parms[0].parm >> 16))
+	v[2].V = int32(int16(parms[1].parm))
+	v[3] = emu.NewFixed12(1)
+
+	// POS_TEST also sets the last vertex, which is used as a base
+	// by relative vertex commands (VTX_XY, VTX_DIFF, etc.)
+	gx.displist.lastvtx = v
+	gx.posTestResult = gx.clipmtx.VecMul(v)
 }
 
 func (gx *GeometryEngine) cmdVecTest(parms []GxCmd) {
@@ -799,7 +918,7 @@ func (gx *GeometryEngine) cmdVecTest(parms []GxCmd) {
 
 func (gx *GeometryEngine) cmdSwapBuffers(parms []GxCmd) {
 	gx.E3dCmdCh <- raster3d.Primitive_SwapBuffers{
-		AlphaYSort: parms[0].parm&1 != 0,
+		ManualSort: parms[0].parm&1 != 0,
 		WBuffering: parms[0].parm&2 != 0,
 	}
 	gx.vcnt = 0
@@ -912,7 +1031,7 @@ var gxCmdDescs = []GxCmdDesc{
 	// 0x6C
 	{0, 0, nil}, {0, 0, nil}, {0, 0, nil}, {0, 0, nil},
 	// 0x70
-	{0, 0, nil}, {0, 0, nil}, {1, 5, (*GeometryEngine).cmdVecTest}, {0, 0, nil},
+	{3, 103, (*GeometryEngine).cmdBoxTest}, {2, 9, (*GeometryEngine).cmdPosTest}, {1, 5, (*GeometryEngine).cmdVecTest}, {0, 0, nil},
 	// 0x74
 	{0, 0, nil}, {0, 0, nil}, {0, 0, nil}, {0, 0, nil},
 	// 0x78
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"ndsemu/emu"
+	"testing"
+)
+
+// Pack two 1.3.12 fixed point values (expressed in 1/4096th) into the
+// parameter of a geometry command
+func gxParm(lo, hi int16) GxCmd {
+	return GxCmd{parm: uint32(uint16(lo)) | uint32(uint16(hi))<<16}
+}
+
+// Scale by 0.5 and then translate by 1 on X: the view volume is x=[-4,0],
+// y=[-2,2], z=[-2,2] in object coordinates.
+func testClipMatrix() matrix {
+	half := emu.Fixed12{V: 0x800}
+	return matMul(newMatrixScale(half, half, half),
+		newMatrixTrans(emu.NewFixed12(1), emu.Fixed12{}, emu.Fixed12{}))
+}
+
+func TestBoxTest(t *testing.T) {
+	var gx GeometryEngine
+	gx.clipmtx = testClipMatrix()
+
+	const one = 0x1000
+	tests := []struct {
+		name    string
+		x, y, z int16
+		w, h, d int16
+		visible bool
+	}{
+		{"inside", -2 * one, -one / 2, -one / 2, one, one, one, true},
+		{"right", one / 2, -one / 2, -one / 2, one, one, one, false},
+		{"left", -6 * one, -one / 2, -one / 2, one, one, one, false},
+		{"top", -2 * one, 5 * one / 2, -one / 2, one, one / 2, one, false},
+		{"far", -2 * one, -one / 2, 3 * one, one, one, one, false},
+		{"straddling", -one / 2, -one / 2, -one / 2, one, one, one, true},
+		{"around", -5 * one, -3 * one, -3 * one, 6 * one, 6 * one, 6 * one, true},
+	}
+	for _, tt := range tests {
+		gx.boxTestResult = !tt.visible
+		gx.cmdBoxTest([]GxCmd{gxParm(tt.x, tt.y), gxParm(tt.z, tt.w), gxParm(tt.h, tt.d)})
+		if gx.boxTestResult != tt.visible {
+			t.Errorf("%s: visible=%v, want %v", tt.name, gx.boxTestResult, tt.visible)
+		}
+	}
+}
+
+func TestPosTest(t *testing.T) {
+	var gx GeometryEngine
+	gx.clipmtx = testClipMatrix()
+
+	gx.cmdPosTest([]GxCmd{gxParm(0x1000, 0x2000), gxParm(0x3000, 0)})
+	want := [4]int32{0x1800, 0x1000, 0x1800, 0x1000}
+	for i := range want {
+		if gx.posTestResult[i].V != want[i] {
+			t.Errorf("result[%d] = %x, want %x", i, gx.posTestResult[i].V, want[i])
+		}
+	}
+	if gx.displist.lastvtx[0].V != 0x1000 || gx.displist.lastvtx[2].V != 0x3000 {
+		t.Errorf("last vertex not set: %v", gx.displist.lastvtx)
+	}
+}
@@ -0,0 +1,54 @@
+package hlebios
+
+import "ndsemu/arm"
+
+// CpuSet: r0=source, r1=destination, r2=control (bits 0-20: number of
+// units, bit 24: fill with the value at source, bit 26: 32-bit units)
+func (b *Bios) cpuSet(cpu *arm.Cpu) int64 {
+	src, dst := uint32(cpu.Regs[0]), uint32(cpu.Regs[1])
+	cnt := uint32(cpu.Regs[2])
+	count := cnt & 0x1FFFFF
+	fill := cnt&(1<<24) != 0
+
+	if cnt&(1<<26) != 0 {
+		src &^= 3
+		dst &^= 3
+		val := cpu.Read32(src)
+		for i := uint32(0); i < count; i++ {
+			if !fill {
+				val = cpu.Read32(src + i*4)
+			}
+			cpu.Write32(dst+i*4, val)
+		}
+	} else {
+		src &^= 1
+		dst &^= 1
+		val := cpu.Read16(src)
+		for i := uint32(0); i < count; i++ {
+			if !fill {
+				val = cpu.Read16(src + i*2)
+			}
+			cpu.Write16(dst+i*2, val)
+		}
+	}
+	return int64(count) * 2
+}
+
+// CpuFastSet: r0=source, r1=destination, r2=control (bits 0-20: number of
+// words, rounded up to a multiple of 8; bit 24: fill with the value at
+// source)
+func (b *Bios) cpuFastSet(cpu *arm.Cpu) int64 {
+	src, dst := uint32(cpu.Regs[0])&^3, uint32(cpu.Regs[1])&^3
+	cnt := uint32(cpu.Regs[2])
+	count := (cnt&0x1FFFFF + 7) &^ 7
+	fill := cnt&(1<<24) != 0
+
+	val := cpu.Read32(src)
+	for i := uint32(0); i < count; i++ {
+		if !fill {
+			val = cpu.Read32(src + i*4)
+		}
+		cpu.Write32(dst+i*4, val)
+	}
+	return int64(count) / 4
+}
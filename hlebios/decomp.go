@@ -0,0 +1,229 @@
+package hlebios
+
+import (
+	"ndsemu/arm"
+	"ndsemu/emu"
+	log "ndsemu/emu/logger"
+)
+
+// Decompression functions. All of them take r0=source and r1=destination.
+// The source data starts with a header word (bits 4-7: compression type,
+// bits 8-31: decompressed size).
+//
+// The "ByCallback" variants read the source through user callbacks (r3),
+// which are usually needed to read from devices (eg: the gamecard). These
+// can't be called from HLE functions, so the source is read directly from
+// memory at r0, which is what callbacks reading from memory do. This is
+// why these variants are emulated only when the original BIOS is missing;
+// if the data at r0 doesn't look like compressed data, the callbacks are
+// reading from somewhere else, and the call is skipped with a warning.
+//
+// Data is decompressed into a buffer first, and then written with the
+// access width of the original function: the Write16bit variants are meant
+// for VRAM, which doesn't support 8-bit writes.
+
+const (
+	compLz77    = 1
+	compHuffman = 2
+	compRle     = 3
+)
+
+type srcReader struct {
+	cpu  *arm.Cpu
+	addr uint32
+}
+
+func (r *srcReader) read8() uint8 {
+	val := r.cpu.Read8(r.addr)
+	r.addr++
+	return val
+}
+
+// Read the header and check the compression type. Returns the
+// decompressed size, or -1 if the type doesn't match.
+func (r *srcReader) header(typ uint32) int {
+	hdr := r.cpu.Read32(r.addr)
+	r.addr += 4
+	if (hdr>>4)&0xF != typ {
+		modHle.WithField("src", r.addr-4).Warnf("invalid compression header: %08x", hdr)
+		return -1
+	}
+	return int(hdr >> 8)
+}
+
+// Write the decompressed data, using accesses of the specified width. The
+// last unit is padded with zeros.
+func writeOut(cpu *arm.Cpu, dst uint32, data []byte, width int) int64 {
+	for len(data)%width != 0 {
+		data = append(data, 0)
+	}
+	for i := 0; i < len(data); i += width {
+		switch width {
+		case 1:
+			cpu.Write8(dst, data[i])
+		case 2:
+			cpu.Write16(dst&^1, uint16(data[i])|uint16(data[i+1])<<8)
+		case 4:
+			cpu.Write32(dst&^3, uint32(data[i])|uint32(data[i+1])<<8|uint32(data[i+2])<<16|uint32(data[i+3])<<24)
+		}
+		dst += uint32(width)
+	}
+	return int64(len(data)) * 4
+}
+
+func decodeLz77(r *srcReader) []byte {
+	size := r.header(compLz77)
+	if size < 0 {
+		return nil
+	}
+	out := make([]byte, 0, size)
+	for len(out) < size {
+		flags := r.read8()
+		for i := 0; i < 8 && len(out) < size; i++ {
+			if flags&0x80 == 0 {
+				out = append(out, r.read8())
+			} else {
+				b1, b2 := r.read8(), r.read8()
+				n := int(b1>>4) + 3
+				disp := (int(b1&0xF)<<8 | int(b2)) + 1
+				for j := 0; j < n && len(out) < size; j++ {
+					// Reading before the start of the output is invalid
+					// data; the BIOS would read garbage from memory
+					var val byte
+					if pos := len(out) - disp; pos >= 0 {
+						val = out[pos]
+					}
+					out = append(out, val)
+				}
+			}
+			flags <<= 1
+		}
+	}
+	return out
+}
+
+func decodeRle(r *srcReader) []byte {
+	size := r.header(compRle)
+	if size < 0 {
+		return nil
+	}
+	out := make([]byte, 0, size)
+	for len(out) < size {
+		flag := r.read8()
+		if flag&0x80 != 0 {
+			n := int(flag&0x7F) + 3
+			val := r.read8()
+			for j := 0; j < n && len(out) < size; j++ {
+				out = append(out, val)
+			}
+		} else {
+			n := int(flag&0x7F) + 1
+			for j := 0; j < n && len(out) < size; j++ {
+				out = append(out, r.read8())
+			}
+		}
+	}
+	return out
+}
+
+// Huffman data is made of a tree of 8-bit nodes, followed by a bitstream
+// (in 32-bit units, starting from bit 31) that selects the path to follow
+// in the tree for each data unit (4 or 8 bits).
+func decodeHuffman(r *srcReader) []byte {
+	hdr := r.cpu.Read32(r.addr)
+	size := r.header(compHuffman)
+	if size < 0 {
+		return nil
+	}
+	bits := uint(hdr & 0xF)
+	if bits != 4 && bits != 8 {
+		modHle.WithField("src", r.addr-4).Warnf("invalid huffman data size: %d", bits)
+		return nil
+	}
+
+	tree := r.addr
+	root := tree + 1
+	stream := tree + (uint32(r.cpu.Read8(tree))+1)*2
+
+	out := make([]byte, 0, size)
+	var cur byte
+	var nbits uint
+	node, addr := r.cpu.Read8(root), root
+	for len(out) < size {
+		word := r.cpu.Read32(stream)
+		stream += 4
+		for i := 0; i < 32 && len(out) < size; i++ {
+			dir := (word >> 31) & 1
+			word <<= 1
+
+			next := addr&^1 + uint32(node&0x3F)*2 + 2 + dir
+			isdata := node&(0x80>>dir) != 0
+			node, addr = r.cpu.Read8(next), next
+			if !isdata {
+				continue
+			}
+
+			// Data units are packed starting from the low bits
+			cur |= node << nbits
+			nbits += bits
+			if nbits == 8 {
+				out = append(out, cur)
+				cur, nbits = 0, 0
+			}
+			node, addr = r.cpu.Read8(root), root
+		}
+	}
+	return out
+}
+
+// LZ77UnCompReadNormalWrite8bit
+func (b *Bios) lz77Write8(cpu *arm.Cpu) int64 {
+	data := decodeLz77(&srcReader{cpu, uint32(cpu.Regs[0])})
+	return writeOut(cpu, uint32(cpu.Regs[1]), data, 1)
+}
+
+// Check that the source of a "ByCallback" function is in memory (see above)
+func callbackSource(cpu *arm.Cpu, typ uint32) bool {
+	src := uint32(cpu.Regs[0])
+	if hdr := cpu.Read32(src); (hdr>>4)&0xF != typ {
+		modHle.WithFields(log.Fields{
+			"src":       emu.Hex32(src),
+			"callbacks": emu.Hex32(uint32(cpu.Regs[3])),
+		}).Warnf("decompression callbacks not emulated: source is not in memory (header: %08x)", hdr)
+		return false
+	}
+	return true
+}
+
+// LZ77UnCompReadByCallbackWrite16bit
+func (b *Bios) lz77Write16(cpu *arm.Cpu) int64 {
+	if !callbackSource(cpu, compLz77) {
+		return 0
+	}
+	data := decodeLz77(&srcReader{cpu, uint32(cpu.Regs[0])})
+	return writeOut(cpu, uint32(cpu.Regs[1]), data, 2)
+}
+
+// HuffUnCompReadByCallback
+func (b *Bios) huffman(cpu *arm.Cpu) int64 {
+	if !callbackSource(cpu, compHuffman) {
+		return 0
+	}
+	data := decodeHuffman(&srcReader{cpu, uint32(cpu.Regs[0])})
+	return writeOut(cpu, uint32(cpu.Regs[1]), data, 4)
+}
+
+// RLUnCompReadNormalWrite8bit
+func (b *Bios) rleWrite8(cpu *arm.Cpu) int64 {
+	data := decodeRle(&srcReader{cpu, uint32(cpu.Regs[0])})
+	return writeOut(cpu, uint32(cpu.Regs[1]), data, 1)
+}
+
+// RLUnCompReadByCallbackWrite16bit
+func (b *Bios) rleWrite16(cpu *arm.Cpu) int64 {
+	if !callbackSource(cpu, compRle) {
+		return 0
+	}
+	data := decodeRle(&srcReader{cpu, uint32(cpu.Regs[0])})
+	return writeOut(cpu, uint32(cpu.Regs[1]), data, 2)
+}
@@ -0,0 +1,159 @@
+// Package hlebios implements a high-level emulation of the NDS BIOS calls
+// (SWIs). The emulated calls are intercepted before the CPU jumps to the
+// SWI vector, so they work both with the original BIOS (replacing its
+// slower implementation) and without it.
+//
+// Without the original BIOS dumps, the emulator can still run games by
+// skipping the boot procedure; in this case, the BIOS is replaced by a
+// stub (see Stub9 and Stub7) that only contains the exception vectors and
+// the IRQ dispatcher, which jumps to the user handler like the original.
+package hlebios
+
+import (
+	"bytes"
+	"encoding/binary"
+	"ndsemu/arm"
+	"ndsemu/biosdump"
+	log "ndsemu/emu/logger"
+)
+
+var modHle = log.NewModule("hlebios")
+
+const (
+	regIme     = 0x4000208
+	regHaltCnt = 0x4000301
+
+	// Address of the IRQ check flags on ARM7 (on ARM9, they're at the end
+	// of DTCM)
+	irqCheck7 = 0x380FFF8
+)
+
+// Bios is the HLE implementation of the BIOS of one of the two CPUs
+type Bios struct {
+	cpu  *arm.Cpu
+	cp15 *arm.Cp15 // nil on ARM7
+	stub bool      // the original BIOS is not available
+}
+
+// Install9 installs the HLE BIOS calls on the ARM9. stub must be true if
+// the BIOS image is the stub (see IsStub).
+func Install9(cpu *arm.Cpu, cp15 *arm.Cp15, stub bool) *Bios {
+	b := &Bios{cpu: cpu, cp15: cp15, stub: stub}
+	b.install()
+	return b
+}
+
+// Install7 installs the HLE BIOS calls on the ARM7. stub must be true if
+// the BIOS image is the stub (see IsStub).
+func Install7(cpu *arm.Cpu, stub bool) *Bios {
+	b := &Bios{cpu: cpu, stub: stub}
+	b.install()
+	return b
+}
+
+func (b *Bios) install() {
+	swis := map[uint8]func(cpu *arm.Cpu) int64{
+		0x03: b.waitByLoop,
+		0x04: b.intrWait,
+		0x05: b.vblankIntrWait,
+		0x06: b.halt,
+		0x09: b.div,
+		0x0B: b.cpuSet,
+		0x0C: b.cpuFastSet,
+		0x0D: b.sqrt,
+		0x0E: b.getCrc16,
+		0x0F: b.isDebugger,
+		0x11: b.lz77Write8,
+		0x14: b.rleWrite8,
+	}
+	// The decompression functions that read through user callbacks can
+	// only be emulated partially (see decomp.go), so they are left to the
+	// original BIOS when it's available.
+	if b.stub {
+		swis[0x12] = b.lz77Write16
+		swis[0x13] = b.huffman
+		swis[0x15] = b.rleWrite16
+	}
+	for num, fn := range swis {
+		b.cpu.SetSwiHle(num, fn)
+	}
+}
+
+// Address of the IRQ check flags, set by the user IRQ handler to
+// acknowledge the interrupts IntrWait is waiting for
+func (b *Bios) irqCheckAddr() uint32 {
+	if b.cp15 != nil {
+		return b.cp15.Read(0, 9, 1, 0)&0xFFFFF000 + 0x3FF8
+	}
+	return irqCheck7
+}
+
+// Halt the CPU until an interrupt is requested, like the original BIOS
+// does: through CP15 on ARM9, and through HALTCNT on ARM7
+func (b *Bios) haltCpu() {
+	if b.cp15 != nil {
+		b.cp15.Write(0, 7, 0, 4, 0)
+		return
+	}
+	b.cpu.Write8(regHaltCnt, 0x80)
+}
+
+// Opcodes of the stub BIOS
+var (
+	stubVectors = []uint32{
+		0xEAFFFFFE, // 00: b   00 (reset)
+		0xEAFFFFFE, // 04: b   04 (undefined)
+		0xE1B0F00E, // 08: movs pc, lr (SWI not emulated)
+		0xEAFFFFFE, // 0C: b   0C (prefetch abort)
+		0xEAFFFFFE, // 10: b   10 (data abort)
+		0xEAFFFFFE, // 14: b   14
+		0xEA000000, // 18: b   20 (IRQ)
+		0xEAFFFFFE, // 1C: b   1C (FIQ)
+	}
+
+	stubIrq9 = []uint32{
+		0xE92D500F, // 20: stmfd sp!, {r0-r3, r12, lr}
+		0xEE190F11, // 24: mrc p15, 0, r0, c9, c1, 0 (DTCM base)
+		0xE1A00620, // 28: mov r0, r0, lsr #12
+		0xE1A00600, // 2C: mov r0, r0, lsl #12
+		0xE2800901, // 30: add r0, r0, #0x4000
+		0xE1A0E00F, // 34: mov lr, pc
+		0xE510F004, // 38: ldr pc, [r0, #-4]
+		0xE8BD500F, // 3C: ldmfd sp!, {r0-r3, r12, lr}
+		0xE25EF004, // 40: subs pc, lr, #4
+	}
+
+	stubIrq7 = []uint32{
+		0xE92D500F, // 20: stmfd sp!, {r0-r3, r12, lr}
+		0xE3A00301, // 24: mov r0, #0x4000000
+		0xE1A0E00F, // 28: mov lr, pc
+		0xE510F004, // 2C: ldr pc, [r0, #-4]
+		0xE8BD500F, // 30: ldmfd sp!, {r0-r3, r12, lr}
+		0xE25EF004, // 34: subs pc, lr, #4
+	}
+)
+
+func stub(size int, irq []uint32) []byte {
+	img := make([]byte, size)
+	for i, op := range append(stubVectors, irq...) {
+		binary.LittleEndian.PutUint32(img[i*4:], op)
+	}
+	return img
+}
+
+// Stub9 returns a minimal ARM9 BIOS image, to be used together with the
+// HLE BIOS calls when the original dump is not available.
+func Stub9() []byte {
+	return stub(biosdump.Bios9Size, stubIrq9)
+}
+
+// Stub7 returns a minimal ARM7 BIOS image, to be used together with the
+// HLE BIOS calls when the original dump is not available.
+func Stub7() []byte {
+	return stub(biosdump.Bios7Size, stubIrq7)
+}
+
+// IsStub reports whether a BIOS image is one of the stubs
+func IsStub(img []byte) bool {
+	return bytes.Equal(img, Stub9()) || bytes.Equal(img, Stub7())
+}
@@ -0,0 +1,241 @@
+package hlebios
+
+import (
+	"encoding/binary"
+	"ndsemu/arm"
+	"testing"
+)
+
+// Test bus: linear memory in the first 64KB, sparse memory elsewhere (for
+// I/O registers and the IRQ check flags)
+type testBus struct {
+	mem    [0x10000]byte
+	sparse map[uint32]byte
+}
+
+func newTestBus() *testBus {
+	return &testBus{sparse: make(map[uint32]byte)}
+}
+
+func (b *testBus) WaitStates() int { return 0 }
+
+func (b *testBus) Read8(addr uint32) uint8 {
+	if addr < uint32(len(b.mem)) {
+		return b.mem[addr]
+	}
+	return b.sparse[addr]
+}
+
+func (b *testBus) Write8(addr uint32, val uint8) {
+	if addr < uint32(len(b.mem)) {
+		b.mem[addr] = val
+		return
+	}
+	b.sparse[addr] = val
+}
+
+func (b *testBus) Read16(addr uint32) uint16 {
+	return uint16(b.Read8(addr)) | uint16(b.Read8(addr+1))<<8
+}
+
+func (b *testBus) Write16(addr uint32, val uint16) {
+	b.Write8(addr, uint8(val))
+	b.Write8(addr+1, uint8(val>>8))
+}
+
+func (b *testBus) Read32(addr uint32) uint32 {
+	return uint32(b.Read16(addr)) | uint32(b.Read16(addr+2))<<16
+}
+
+func (b *testBus) Write32(addr uint32, val uint32) {
+	b.Write16(addr, uint16(val))
+	b.Write16(addr+2, uint16(val>>16))
+}
+
+func (b *testBus) FetchPointer(addr uint32) []uint8 {
+	if addr < uint32(len(b.mem)) {
+		return b.mem[addr:]
+	}
+	return nil
+}
+
+func newTestBios() (*Bios, *arm.Cpu, *testBus) {
+	bus := newTestBus()
+	cpu := arm.NewCpu(arm.ARMv4, bus)
+	return Install7(cpu, true), cpu, bus
+}
+
+func TestMath(t *testing.T) {
+	b, cpu, bus := newTestBios()
+
+	divs := []struct {
+		num, den       int32
+		quot, rem, abs int32
+	}{
+		{7, 2, 3, 1, 3},
+		{-7, 2, -3, -1, 3},
+		{7, 0, -1, 7, 1},
+		{-7, 0, 1, -7, 1},
+	}
+	for _, tt := range divs {
+		cpu.SetReg(0, uint32(tt.num))
+		cpu.SetReg(1, uint32(tt.den))
+		b.div(cpu)
+		if q, r, a := int32(cpu.Regs[0]), int32(cpu.Regs[1]), int32(cpu.Regs[3]); q != tt.quot || r != tt.rem || a != tt.abs {
+			t.Errorf("div(%d,%d): got %d,%d,%d want %d,%d,%d", tt.num, tt.den, q, r, a, tt.quot, tt.rem, tt.abs)
+		}
+	}
+
+	for _, tt := range []struct{ val, res uint32 }{
+		{0, 0}, {1, 1}, {15, 3}, {16, 4}, {0xFFFFFFFF, 0xFFFF},
+	} {
+		cpu.SetReg(0, tt.val)
+		b.sqrt(cpu)
+		if uint32(cpu.Regs[0]) != tt.res {
+			t.Errorf("sqrt(%d): got %d, want %d", tt.val, cpu.Regs[0], tt.res)
+		}
+	}
+
+	copy(bus.mem[0x1000:], "12345678")
+	for _, tt := range []struct{ init, crc uint32 }{
+		{0, 0x3C9D}, {0xFFFF, 0x37DD},
+	} {
+		cpu.SetReg(0, tt.init)
+		cpu.SetReg(1, 0x1000)
+		cpu.SetReg(2, 8)
+		b.getCrc16(cpu)
+		if uint32(cpu.Regs[0]) != tt.crc {
+			t.Errorf("crc16(init=%x): got %x, want %x", tt.init, cpu.Regs[0], tt.crc)
+		}
+	}
+}
+
+func TestCpuSet(t *testing.T) {
+	b, cpu, bus := newTestBios()
+	for i := 0; i < 0x40; i++ {
+		bus.mem[0x1000+i] = byte(i + 1)
+	}
+
+	// 16-bit copy of 3 units
+	cpu.SetReg(0, 0x1000)
+	cpu.SetReg(1, 0x2000)
+	cpu.SetReg(2, 3)
+	b.cpuSet(cpu)
+	if v := bus.Read32(0x2004); v != 0x0605 {
+		t.Errorf("cpuset16: %08x", v)
+	}
+
+	// 32-bit fill of 2 units
+	cpu.SetReg(0, 0x1000)
+	cpu.SetReg(1, 0x3000)
+	cpu.SetReg(2, 1<<26|1<<24|2)
+	b.cpuSet(cpu)
+	if v1, v2, v3 := bus.Read32(0x3000), bus.Read32(0x3004), bus.Read32(0x3008); v1 != 0x04030201 || v2 != v1 || v3 != 0 {
+		t.Errorf("cpuset32 fill: %08x %08x %08x", v1, v2, v3)
+	}
+
+	// Fast copy: rounded up to 8 words
+	cpu.SetReg(0, 0x1000)
+	cpu.SetReg(1, 0x4000)
+	cpu.SetReg(2, 1)
+	b.cpuFastSet(cpu)
+	if v1, v2 := bus.Read32(0x401C), bus.Read32(0x4020); v1 != 0x201F1E1D || v2 != 0 {
+		t.Errorf("cpufastset: %08x %08x", v1, v2)
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(b *Bios, cpu *arm.Cpu) int64
+		src  []byte
+		want string
+	}{
+		{"lz77", (*Bios).lz77Write8, []byte{0x10, 8, 0, 0, 0x20, 'A', 'B', 0x30, 0x01}, "ABABABAB"},
+		{"lz77-16", (*Bios).lz77Write16, []byte{0x10, 8, 0, 0, 0x20, 'A', 'B', 0x30, 0x01}, "ABABABAB"},
+		{"rle", (*Bios).rleWrite8, []byte{0x30, 6, 0, 0, 0x82, 'A', 0x00, 'B'}, "AAAAAB"},
+		{"rle-16", (*Bios).rleWrite16, []byte{0x30, 6, 0, 0, 0x82, 'A', 0x00, 'B'}, "AAAAAB"},
+		// Tree: root with two data children ('A' and 'B'); bitstream 0110
+		{"huffman", (*Bios).huffman, []byte{0x28, 4, 0, 0, 1, 0xC0, 'A', 'B', 0, 0, 0, 0x60}, "ABBA"},
+	}
+	for _, tt := range tests {
+		b, cpu, bus := newTestBios()
+		copy(bus.mem[0x1000:], tt.src)
+		cpu.SetReg(0, 0x1000)
+		cpu.SetReg(1, 0x2000)
+		tt.fn(b, cpu)
+		if got := string(bus.mem[0x2000 : 0x2000+len(tt.want)]); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	// With the original BIOS, the callback variants are not emulated
+	bus := newTestBus()
+	cpu := arm.NewCpu(arm.ARMv4, bus)
+	Install7(cpu, false)
+	binary.LittleEndian.PutUint32(bus.mem[0x8:], 0xEAFFFFFE)    // b 08
+	binary.LittleEndian.PutUint32(bus.mem[0x1000:], 0xEF120000) // swi 0x12
+	cpu.Cpsr.SetMode(arm.CpuModeSystem, cpu)
+	cpu.SetPC(0x1000)
+	cpu.Run(10)
+	if mode := cpu.Cpsr.GetMode(); mode != arm.CpuModeSupervisor {
+		t.Errorf("SWI 0x12 emulated with the original BIOS: mode=%v", mode)
+	}
+
+	// With the stub, a source which is not compressed data in memory is
+	// skipped
+	b, cpu, bus := newTestBios()
+	copy(bus.mem[0x1000:], []byte{0x00, 8, 0, 0, 'A', 'B'})
+	bus.mem[0x2000] = 0xFF
+	cpu.SetReg(0, 0x1000)
+	cpu.SetReg(1, 0x2000)
+	b.lz77Write16(cpu)
+	if bus.mem[0x2000] != 0xFF {
+		t.Errorf("data written from an invalid source")
+	}
+}
+
+func TestIntrWait(t *testing.T) {
+	_, cpu, bus := newTestBios()
+
+	// swi 0x04 (IntrWait) followed by an infinite loop
+	binary.LittleEndian.PutUint32(bus.mem[0x1000:], 0xEF040000)
+	binary.LittleEndian.PutUint32(bus.mem[0x1004:], 0xEAFFFFFE)
+	cpu.SetPC(0x1000)
+	cpu.SetReg(0, 1)
+	cpu.SetReg(1, 1)
+	bus.Write32(irqCheck7, 0x3)
+
+	// Old flags are discarded: the CPU halts and the SWI is restarted
+	cpu.Run(100)
+	if bus.Read8(regHaltCnt) != 0x80 || bus.Read32(regIme) != 1 {
+		t.Errorf("CPU not halted: haltcnt=%x ime=%x", bus.Read8(regHaltCnt), bus.Read32(regIme))
+	}
+	if pc := uint32(cpu.Regs[15]); pc != 0x1000 {
+		t.Errorf("SWI not restarted: pc=%08x", pc)
+	}
+	if cpu.Regs[0] != 0 || bus.Read32(irqCheck7) != 0x2 {
+		t.Errorf("flags not discarded: r0=%v flags=%x", cpu.Regs[0], bus.Read32(irqCheck7))
+	}
+
+	// The IRQ handler sets the flag: IntrWait returns and acknowledges it
+	bus.Write32(irqCheck7, 0x3)
+	cpu.Run(200)
+	if pc := uint32(cpu.Regs[15]); pc != 0x1004 {
+		t.Errorf("IntrWait didn't return: pc=%08x", pc)
+	}
+	if v := bus.Read32(irqCheck7); v != 0x2 {
+		t.Errorf("flag not acknowledged: %x", v)
+	}
+}
+
+func TestStubs(t *testing.T) {
+	for _, img := range [][]byte{Stub9(), Stub7()} {
+		// IRQ vector jumps to the dispatcher
+		if op := binary.LittleEndian.Uint32(img[0x18:]); op != 0xEA000000 {
+			t.Errorf("IRQ vector: %08x", op)
+		}
+	}
+}
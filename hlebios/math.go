@@ -0,0 +1,64 @@
+package hlebios
+
+import "ndsemu/arm"
+
+// Div: r0=numerator, r1=denominator (signed). Returns r0=quotient,
+// r1=remainder, r3=abs(quotient). Division by zero returns the same values
+// as the hardware divider.
+func (b *Bios) div(cpu *arm.Cpu) int64 {
+	num, den := int32(cpu.Regs[0]), int32(cpu.Regs[1])
+	var quot, rem int32
+	if den == 0 {
+		quot, rem = -1, num
+		if num < 0 {
+			quot = 1
+		}
+	} else {
+		// MinInt32 / -1 doesn't panic in Go: it returns MinInt32
+		quot, rem = num/den, num%den
+	}
+	abs := quot
+	if abs < 0 {
+		abs = -abs
+	}
+	cpu.SetReg(0, uint32(quot))
+	cpu.SetReg(1, uint32(rem))
+	cpu.SetReg(3, uint32(abs))
+	return 100
+}
+
+// Sqrt: r0=value (unsigned). Returns r0=integer square root.
+func (b *Bios) sqrt(cpu *arm.Cpu) int64 {
+	val := uint32(cpu.Regs[0])
+	var res uint32
+	for bit := uint32(1 << 30); bit != 0; bit >>= 2 {
+		if val >= res+bit {
+			val -= res + bit
+			res = res>>1 + bit
+		} else {
+			res >>= 1
+		}
+	}
+	cpu.SetReg(0, res)
+	return 100
+}
+
+// GetCRC16: r0=initial value, r1=address, r2=length in bytes. Returns
+// r0=CRC16.
+func (b *Bios) getCrc16(cpu *arm.Cpu) int64 {
+	crc := uint16(cpu.Regs[0])
+	addr := uint32(cpu.Regs[1]) &^ 1
+	size := uint32(cpu.Regs[2]) &^ 1
+	for i := uint32(0); i < size; i++ {
+		crc ^= uint16(cpu.Read8(addr + i))
+		for j := 0; j < 8; j++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	cpu.SetReg(0, uint32(crc))
+	return int64(size) * 4
+}
@@ -0,0 +1,64 @@
+package hlebios
+
+import "ndsemu/arm"
+
+// WaitByLoop: r0=number of iterations of the delay loop
+func (b *Bios) waitByLoop(cpu *arm.Cpu) int64 {
+	count := int32(cpu.Regs[0])
+	if count <= 0 {
+		return 0
+	}
+	cpu.SetReg(0, 0)
+	return int64(count) * 4
+}
+
+// IntrWait: r0=discard the old flags, r1=mask of the interrupts to wait for
+//
+// The original BIOS loops within the SWI, halting the CPU and checking the
+// IRQ flags after each interrupt. To emulate this without blocking, the
+// CPU is halted and the SWI is restarted; when the CPU is woken up, the IRQ
+// is serviced and then the flags are checked again. This requires IRQs to
+// be enabled in CPSR when IntrWait is called (the original BIOS enables
+// them while waiting), which is always the case in practice.
+func (b *Bios) intrWait(cpu *arm.Cpu) int64 {
+	mask := uint32(cpu.Regs[1])
+	cpu.Write32(regIme, 1)
+
+	addr := b.irqCheckAddr()
+	flags := cpu.Read32(addr)
+	if cpu.Regs[0] != 0 {
+		// Discard the old flags. This is done only at the first call: the
+		// argument is cleared so that it's not done again when the SWI is
+		// restarted (the original BIOS doesn't preserve it either).
+		flags &^= mask
+		cpu.Write32(addr, flags)
+		cpu.SetReg(0, 0)
+	}
+	if flags&mask != 0 {
+		cpu.Write32(addr, flags&^mask)
+		return 0
+	}
+
+	b.haltCpu()
+	cpu.RestartSwi()
+	return 0
+}
+
+// VBlankIntrWait: IntrWait(1, IrqVBlank)
+func (b *Bios) vblankIntrWait(cpu *arm.Cpu) int64 {
+	cpu.SetReg(0, 1)
+	cpu.SetReg(1, 1)
+	return b.intrWait(cpu)
+}
+
+// Halt: halt the CPU until an interrupt is requested
+func (b *Bios) halt(cpu *arm.Cpu) int64 {
+	b.haltCpu()
+	return 0
+}
+
+// IsDebugger: returns 0 (retail console)
+func (b *Bios) isDebugger(cpu *arm.Cpu) int64 {
+	cpu.SetReg(0, 0)
+	return 0
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Input scripts are simple text files describing the input state of the
+// emulated console frame by frame. They're meant to quickly reproduce bugs
+// without requiring a full movie recording system.
+//
+// Each line contains a frame number, followed by the buttons pressed
+// starting from that frame (comma separated, or "-" for none), and
+// optionally the touchscreen position. The state is kept until the
+// next line. Empty lines and lines starting with '#' are ignored:
+//
+//	# frame buttons [touch x,y]
+//	120 START
+//	125 -
+//	300 A,UP
+//	310 - touch 128,96
+//	312 -
+
+type InputState struct {
+	Buttons    Buttons
+	PenDown    bool
+	PenX, PenY int
+}
+
+func (in InputState) String() string {
+	var names []string
+	for i := 0; i < numButtons; i++ {
+		if in.Buttons&(1<<uint(i)) != 0 {
+			names = append(names, buttonNames[i])
+		}
+	}
+	s := "-"
+	if len(names) > 0 {
+		s = strings.Join(names, ",")
+	}
+	if in.PenDown {
+		s += fmt.Sprintf(" touch %d,%d", in.PenX, in.PenY)
+	}
+	return s
+}
+
+func parseInputState(fields []string) (InputState, error) {
+	var in InputState
+
+	if fields[0] != "-" {
+		for _, name := range strings.Split(fields[0], ",") {
+			found := false
+			for i, bn := range buttonNames {
+				if strings.EqualFold(name, bn) {
+					in.Buttons |= 1 << uint(i)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return in, fmt.Errorf("invalid button: %q", name)
+			}
+		}
+	}
+
+	switch len(fields) {
+	case 1:
+	case 3:
+		if fields[1] != "touch" {
+			return in, fmt.Errorf("invalid keyword: %q", fields[1])
+		}
+		if _, err := fmt.Sscanf(fields[2], "%d,%d", &in.PenX, &in.PenY); err != nil {
+			return in, fmt.Errorf("invalid touch position: %q", fields[2])
+		}
+		in.PenDown = true
+	default:
+		return in, fmt.Errorf("invalid number of fields")
+	}
+
+	return in, nil
+}
+
+type inputEntry struct {
+	frame int
+	state InputState
+}
+
+// InputPlayer plays back an input script
+type InputPlayer struct {
+	entries []inputEntry
+	idx     int
+	cur     InputState
+}
+
+func LoadInputScript(fn string) (*InputPlayer, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := new(InputPlayer)
+	scan := bufio.NewScanner(f)
+	for nline := 1; scan.Scan(); nline++ {
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: invalid line", fn, nline)
+		}
+		frame, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid frame number: %q", fn, nline, fields[0])
+		}
+		if n := len(p.entries); n > 0 && p.entries[n-1].frame >= frame {
+			return nil, fmt.Errorf("%s:%d: frame numbers must be increasing", fn, nline)
+		}
+		state, err := parseInputState(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", fn, nline, err)
+		}
+		p.entries = append(p.entries, inputEntry{frame, state})
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Update returns the input state for the specified frame. It must be called
+// with increasing frame numbers.
+func (p *InputPlayer) Update(frame int) InputState {
+	for p.idx < len(p.entries) && p.entries[p.idx].frame <= frame {
+		p.cur = p.entries[p.idx].state
+		p.idx++
+	}
+	return p.cur
+}
+
+// InputRecorder records the input state into an input script. Only the
+// frames in which the state changes are written.
+type InputRecorder struct {
+	f     *os.File
+	w     *bufio.Writer
+	last  InputState
+	first bool
+}
+
+func NewInputRecorder(fn string) (*InputRecorder, error) {
+	f, err := os.Create(fn)
+	if err != nil {
+		return nil, err
+	}
+	r := &InputRecorder{f: f, w: bufio.NewWriter(f), first: true}
+	fmt.Fprintf(r.w, "# frame buttons [touch x,y]\n")
+	return r, nil
+}
+
+func (r *InputRecorder) Record(frame int, in InputState) {
+	if in == r.last && !r.first {
+		return
+	}
+	fmt.Fprintf(r.w, "%d %v\n", frame, in)
+	r.last = in
+	r.first = false
+}
+
+func (r *InputRecorder) Close() error {
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadTestInputScript(t *testing.T, script string) (*InputPlayer, error) {
+	fn := filepath.Join(t.TempDir(), "input.txt")
+	if err := ioutil.WriteFile(fn, []byte(script), 0666); err != nil {
+		t.Fatal(err)
+	}
+	return LoadInputScript(fn)
+}
+
+func TestLoadInputScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		entries []inputEntry
+		err     string // expected error (substring), if any
+	}{
+		{"empty", "", nil, ""},
+		{"comments", "# frame buttons\n\n   \n  # indented comment\n", nil, ""},
+		{"buttons", "120 START\n125 -\n300 a,Up\n", []inputEntry{
+			{120, InputState{Buttons: ButtonStart}},
+			{125, InputState{}},
+			{300, InputState{Buttons: ButtonA | ButtonUp}},
+		}, ""},
+		{"touch", "10 - touch 128,96\n12 L,R touch 0,191\n", []inputEntry{
+			{10, InputState{PenDown: true, PenX: 128, PenY: 96}},
+			{12, InputState{Buttons: ButtonL | ButtonR, PenDown: true, PenY: 191}},
+		}, ""},
+
+		{"no-state", "10\n", nil, ":1: invalid line"},
+		{"bad-frame", "abc START\n", nil, ":1: invalid frame number"},
+		{"bad-button", "10 A,TURBO\n", nil, ":1: invalid button: \"TURBO\""},
+		{"bad-keyword", "10 - tap 1,2\n", nil, ":1: invalid keyword"},
+		{"bad-touch", "10 - touch 1\n", nil, ":1: invalid touch position"},
+		{"extra-fields", "10 A touch 1,2 3\n", nil, ":1: invalid number of fields"},
+		{"line-number", "# header\n10 A\n\n20 B,\n", nil, ":4: invalid button"},
+
+		{"decreasing", "10 A\n5 B\n", nil, ":2: frame numbers must be increasing"},
+		{"repeated", "10 A\n10 B\n", nil, ":2: frame numbers must be increasing"},
+	}
+
+	for _, tt := range tests {
+		p, err := loadTestInputScript(t, tt.script)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: got error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(p.entries, tt.entries) {
+			t.Errorf("%s: got %v, want %v", tt.name, p.entries, tt.entries)
+		}
+	}
+}
+
+func TestInputPlayerUpdate(t *testing.T) {
+	p, err := loadTestInputScript(t, "10 A\n20 B touch 5,6\n30 -\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each state is kept until the next line, also when frames are skipped
+	for _, tt := range []struct {
+		frame int
+		state string
+	}{
+		{0, "-"}, {9, "-"}, {10, "A"}, {15, "A"},
+		{25, "B touch 5,6"}, {30, "-"}, {1000, "-"},
+	} {
+		if got := p.Update(tt.frame).String(); got != tt.state {
+			t.Errorf("frame %d: got %q, want %q", tt.frame, got, tt.state)
+		}
+	}
+}
+
+func TestInputRecorder(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "input.txt")
+	r, err := NewInputRecorder(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := []InputState{
+		{}, {}, {Buttons: ButtonX}, {Buttons: ButtonX},
+		{Buttons: ButtonX, PenDown: true, PenX: 3, PenY: 4}, {},
+	}
+	for i, in := range states {
+		r.Record(i, in)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only changes are recorded, and the script plays back the same input
+	p, err := LoadInputScript(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.entries) != 4 {
+		t.Errorf("got %d entries, want 4", len(p.entries))
+	}
+	for i, in := range states {
+		if got := p.Update(i); got != in {
+			t.Errorf("frame %d: got %v, want %v", i, got, in)
+		}
+	}
+}
@@ -53,10 +53,46 @@ type HwIpc struct {
 	Ipc7FifoRecv hwio.Reg32 `hwio:"bank=3,offset=0x0,readonly,rcb"`
 
 	data         [2]ipcFifo
+	decoder      [2]fifoDecoder
 	enable       [2]bool
 	err          [2]bool
 	irqEmptyFlag [2]bool
 	irqDataFlag  [2]bool
+
+	// Last accesses to the IPC registers, for post-mortem reports (see
+	// Watchdog)
+	trace  [8]ipcEvent
+	ntrace int
+}
+
+// An ipcEvent is a write to IPCSYNC, or a word pushed/popped through the
+// IPC FIFO.
+type ipcEvent struct {
+	Cpu  CpuNum
+	Kind string // "sync", "send" or "recv"
+	Val  uint32
+}
+
+func (ev ipcEvent) String() string {
+	return fmt.Sprintf("%s %s %08x", [2]string{"arm9", "arm7"}[ev.Cpu], ev.Kind, ev.Val)
+}
+
+func (ipc *HwIpc) record(cpunum CpuNum, kind string, val uint32) {
+	ipc.trace[ipc.ntrace%len(ipc.trace)] = ipcEvent{cpunum, kind, val}
+	ipc.ntrace++
+}
+
+// lastTraffic returns the most recent IPC accesses, oldest first.
+func (ipc *HwIpc) lastTraffic() []ipcEvent {
+	var res []ipcEvent
+	n := ipc.ntrace
+	if n > len(ipc.trace) {
+		n = len(ipc.trace)
+	}
+	for i := ipc.ntrace - n; i < ipc.ntrace; i++ {
+		res = append(res, ipc.trace[i%len(ipc.trace)])
+	}
+	return res
 }
 
 func NewHwIpc(irq9 *HwIrq, irq7 *HwIrq) *HwIpc {
@@ -97,6 +133,7 @@ func (ipc *HwIpc) updateIrqFlags() {
 func (ipc *HwIpc) WriteIPC7SYNC(_, value uint16) {
 	// See WriteIPC9SYNC comment for why this is required
 	nds9.Run(Emu.Sync.Cycles())
+	ipc.record(CpuNds7, "sync", uint32(value))
 
 	ipc.Ipc9Sync.Value &^= 0xF
 	ipc.Ipc9Sync.Value |= (value >> 8) & 0xF
@@ -114,6 +151,7 @@ func (ipc *HwIpc) WriteIPC9SYNC(_, value uint16) {
 	// over the ARM9 tight loop, assuming that it has already jumped away.
 	// This breaks emulation if we don't sync between the CPUs quick enough.
 	nds7.Run(Emu.Sync.Cycles())
+	ipc.record(CpuNds9, "sync", uint32(value))
 
 	ipc.Ipc7Sync.Value &^= 0xF
 	ipc.Ipc7Sync.Value |= (value >> 8) & 0xF
@@ -163,6 +201,7 @@ func (ipc *HwIpc) writeIPCFIFOCNT(cpunum CpuNum, val uint16) {
 
 	if val&(1<<3) != 0 {
 		send.Flush()
+		ipc.decoder[cpunum].Reset()
 	}
 	send.emptyIrq = val&(1<<2) != 0
 	recv.dataIrq = val&(1<<10) != 0
@@ -183,6 +222,10 @@ func (ipc *HwIpc) writeIPCFIFOSEND(cpunum CpuNum, val uint32) {
 			ipc.err[cpunum] = true
 		}
 		send.Push(val)
+		ipc.record(cpunum, "send", val)
+		if msg, ok := ipc.decoder[cpunum].Push(val); ok {
+			modIpcMsg.Infof("%s: %v", [2]string{"ARM9->ARM7", "ARM7->ARM9"}[cpunum], msg)
+		}
 	}
 	modIpc.WithField("val", fmt.Sprintf("%08x", val)).Infof("FIFO push")
 	ipc.updateIrqFlags()
@@ -201,6 +244,7 @@ func (ipc *HwIpc) readIPCFIFORECV(cpunum CpuNum) uint32 {
 	}
 
 	value := recv.Pop()
+	ipc.record(cpunum, "recv", value)
 	modIpc.WithField("val", fmt.Sprintf("%08x", value)).Infof("FIFO pop")
 	ipc.updateIrqFlags()
 	return value
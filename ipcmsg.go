@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	log "ndsemu/emu/logger"
+	"strings"
+
+	"gopkg.in/Sirupsen/logrus.v0"
+)
+
+// Messages exchanged through the IPC FIFO, decoded according to the
+// protocol of libnds (fifosystem), which is used by most homebrew. Enable
+// with "-log ipcmsg".
+var modIpcMsg = log.NewModule("ipcmsg")
+
+// Layout of a libnds FIFO word
+const (
+	fifoChannelShift = 28
+	fifoAddressBit   = 1 << 27
+	fifoImmediateBit = 1 << 26
+	fifoExtraBit     = 1 << 25
+	fifoValue32Mask  = fifoExtraBit - 1
+	fifoAddressMask  = 0x00FFFFFF
+	fifoAddressBase  = 0x02000000
+	fifoDataMsgMask  = fifoValue32Mask
+	fifoMaxDataBytes = 128
+)
+
+var fifoChannelNames = [16]string{
+	"pm", "sound", "system", "maxmod", "dswifi", "sdmmc", "firmware", "rsvd",
+	"user1", "user2", "user3", "user4", "user5", "user6", "user7", "user8",
+}
+
+const (
+	fifoChanPM     = 0
+	fifoChanSound  = 1
+	fifoChanSystem = 2
+)
+
+// Commands sent as values on the sound channel (FifoSoundCommand)
+var fifoSoundCommands = []string{
+	"SET_PAN", "SET_VOLUME", "SET_FREQ", "SET_WAVEDUTY", "MASTER_ENABLE",
+	"MASTER_DISABLE", "PAUSE", "RESUME", "KILL", "SET_MASTER_VOL", "MIC_STOP",
+}
+
+// Types of the data messages on the sound and system channels
+// (FifoMessageType)
+var fifoMessageTypes = map[uint16]string{
+	0x1234: "SOUND_PLAY",
+	0x1235: "SOUND_PSG",
+	0x1236: "SOUND_NOISE",
+	0x1237: "MIC_RECORD",
+	0x1238: "MIC_BUFFER_FULL",
+	0x1239: "SYS_INPUT",
+}
+
+// A decoded FIFO message. Formatting is done lazily by String, so that it
+// costs nothing when logging is disabled.
+type fifoMsg struct {
+	words []uint32
+}
+
+func (m fifoMsg) String() string {
+	hdr := m.words[0]
+	ch := hdr >> fifoChannelShift
+	s := fmt.Sprintf("ch=%s ", fifoChannelNames[ch])
+
+	switch {
+	case hdr&fifoAddressBit != 0:
+		return s + fmt.Sprintf("address=%08x", hdr&fifoAddressMask+fifoAddressBase)
+
+	case hdr&fifoImmediateBit != 0:
+		val := hdr & fifoValue32Mask
+		if hdr&fifoExtraBit != 0 {
+			val = m.words[1]
+		}
+		s += fmt.Sprintf("value=%08x", val)
+		if ch == fifoChanSound {
+			if cmd := int(val >> 20); cmd < len(fifoSoundCommands) {
+				s += fmt.Sprintf(" (%s chan=%d val=%04x)", fifoSoundCommands[cmd], (val>>16)&0xF, val&0xFFFF)
+			}
+		}
+		return s
+
+	case hdr&fifoDataMsgMask > fifoMaxDataBytes:
+		// Not a valid libnds message
+		return fmt.Sprintf("unknown=%08x", hdr)
+
+	default:
+		data := make([]byte, 0, len(m.words[1:])*4)
+		for _, w := range m.words[1:] {
+			data = append(data, byte(w), byte(w>>8), byte(w>>16), byte(w>>24))
+		}
+		data = data[:hdr&fifoDataMsgMask]
+		s += fmt.Sprintf("datamsg len=%d", len(data))
+		if (ch == fifoChanSound || ch == fifoChanSystem) && len(data) >= 2 {
+			typ := uint16(data[0]) | uint16(data[1])<<8
+			if name, ok := fifoMessageTypes[typ]; ok {
+				s += " type=" + name
+				if name == "SYS_INPUT" && len(data) >= 18 {
+					// touchPosition (rawx, rawy, px, py, z1, z2) + keys
+					get := func(off int) uint16 { return uint16(data[off]) | uint16(data[off+1])<<8 }
+					s += fmt.Sprintf(" touch=(%d,%d) raw=(%d,%d) keys=%04x",
+						get(8), get(10), get(4), get(6), get(16))
+				}
+			}
+		}
+		var hex []string
+		for _, b := range data {
+			hex = append(hex, fmt.Sprintf("%02x", b))
+		}
+		return s + " [" + strings.Join(hex, " ") + "]"
+	}
+}
+
+// fifoDecoder reassembles the words pushed into one direction of the IPC
+// FIFO into libnds messages.
+type fifoDecoder struct {
+	words []uint32
+	left  int // words still missing to complete the message
+}
+
+func (d *fifoDecoder) Reset() {
+	d.words = d.words[:0]
+	d.left = 0
+}
+
+// Push a word; if it completes a message, the message is returned. When
+// the ipcmsg module is disabled, words are not decoded at all (and nothing
+// is allocated), so decoding starts again from the next word pushed after
+// it is enabled.
+func (d *fifoDecoder) Push(val uint32) (fifoMsg, bool) {
+	if !modIpcMsg.Enabled(logrus.InfoLevel) {
+		d.Reset()
+		return fifoMsg{}, false
+	}
+	if d.left == 0 {
+		d.words = append(d.words[:0], val)
+		switch {
+		case val&fifoAddressBit != 0:
+		case val&fifoImmediateBit != 0:
+			if val&fifoExtraBit != 0 {
+				d.left = 1
+			}
+		case val&fifoDataMsgMask <= fifoMaxDataBytes:
+			d.left = int(val&fifoDataMsgMask+3) / 4
+		}
+	} else {
+		d.words = append(d.words, val)
+		d.left--
+	}
+
+	if d.left > 0 {
+		return fifoMsg{}, false
+	}
+	return fifoMsg{words: append([]uint32(nil), d.words...)}, true
+}
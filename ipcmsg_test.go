@@ -0,0 +1,36 @@
+package main
+
+import (
+	log "ndsemu/emu/logger"
+	"testing"
+)
+
+func TestFifoDecoderPush(t *testing.T) {
+	var d fifoDecoder
+
+	// Immediate value with an extra word, on the sound channel
+	hdr := uint32(fifoChanSound<<fifoChannelShift | fifoImmediateBit | fifoExtraBit)
+
+	// Disabled module: nothing is decoded nor allocated
+	if allocs := testing.AllocsPerRun(100, func() {
+		if _, ok := d.Push(hdr); ok {
+			t.Errorf("message decoded with logging disabled")
+		}
+	}); allocs != 0 {
+		t.Errorf("Push allocates with logging disabled: %v", allocs)
+	}
+
+	log.EnableDebugModules(modIpcMsg.Mask())
+	defer log.DisableDebugModules(modIpcMsg.Mask())
+
+	if _, ok := d.Push(hdr); ok {
+		t.Fatalf("message complete after the header")
+	}
+	msg, ok := d.Push(0x12345678)
+	if !ok {
+		t.Fatalf("message not complete")
+	}
+	if s := msg.String(); s != "ch=sound value=12345678" {
+		t.Errorf("invalid message: %q", s)
+	}
+}
@@ -16,6 +16,11 @@ type HwIrq struct {
 
 	// Mask of level-triggerd IRQs (can't be asserted by CPU)
 	lvlirq uint32
+
+	// IRQ delivery logging (see EnableLog): the time at which each IF bit
+	// was last set, to compute the latency of the delivery.
+	emu   *NDSEmulator
+	reqAt [32]int64
 }
 
 type IrqType uint32
@@ -35,6 +40,8 @@ const (
 	IrqDma2 IrqType = (1 << 10)
 	IrqDma3 IrqType = (1 << 11)
 
+	IrqSlot2 IrqType = (1 << 13) // GBA slot (also raised when the cartridge is removed)
+
 	IrqIpcSync     IrqType = (1 << 16)
 	IrqIpcSendFifo IrqType = (1 << 17)
 	IrqIpcRecvFifo IrqType = (1 << 18)
@@ -53,6 +60,52 @@ func NewHwIrq(name string, cpu *arm.Cpu) *HwIrq {
 	return irq
 }
 
+// EnableLog starts recording each IRQ delivered to the CPU into the
+// emulator's IRQ log (see IrqLog).
+func (irq *HwIrq) EnableLog(emu *NDSEmulator) {
+	irq.emu = emu
+	irq.Cpu.SetIrqHook(irq.delivered)
+}
+
+// Record the time at which the specified IF bits are set (if they were
+// clear), to compute the latency of the delivery.
+func (irq *HwIrq) request(bits uint32) {
+	if irq.emu == nil {
+		return
+	}
+	bits &^= irq.If.Value
+	if bits == 0 {
+		return
+	}
+	now := irq.emu.Sync.Cycles()
+	for i := uint(0); bits != 0; i, bits = i+1, bits>>1 {
+		if bits&1 != 0 {
+			irq.reqAt[i] = now
+		}
+	}
+}
+
+// Called by the CPU when it jumps to the IRQ vector
+func (irq *HwIrq) delivered(pc uint32) {
+	ev := IrqEvent{
+		Frame:   irq.emu.framecount,
+		Cycles:  irq.emu.Sync.Cycles(),
+		Cpu:     irq.Name,
+		Sources: irq.Ie.Value & irq.If.Value,
+		Ie:      irq.Ie.Value,
+		If:      irq.If.Value,
+		PC:      pc,
+	}
+	oldest := ev.Cycles
+	for i := uint(0); i < 32; i++ {
+		if ev.Sources&(1<<i) != 0 && irq.reqAt[i] < oldest {
+			oldest = irq.reqAt[i]
+		}
+	}
+	ev.Latency = ev.Cycles - oldest
+	irq.emu.IrqLog.Add(ev)
+}
+
 func (irq *HwIrq) Log() log.Entry {
 	return log.ModIrq.WithField("name", irq.Name)
 }
@@ -73,6 +126,20 @@ func (irq *HwIrq) updateLineStatus() {
 		}
 	}
 	irq.Cpu.SetLine(arm.LineIrq, irqstat)
+
+	// HALT is released by any enabled interrupt, irrespective of IME
+	if irq.Ie.Value&irq.If.Value != 0 {
+		irq.Cpu.SetLine(arm.LineHalt, false)
+	}
+}
+
+// Halt the CPU until an enabled interrupt is requested. If IME is clear,
+// the CPU resumes execution without jumping to the IRQ vector.
+func (irq *HwIrq) Halt() {
+	if irq.Ie.Value&irq.If.Value != 0 {
+		return
+	}
+	irq.Cpu.SetLine(arm.LineHalt, true)
 }
 
 func (irq *HwIrq) WriteIE(_, ie uint32) {
@@ -97,6 +164,7 @@ func (irq *HwIrq) WriteIF(old, ifx uint32) {
 // register, and can be acknowledged by the CPU at any time by writing
 // to the same reg.
 func (irq *HwIrq) Raise(irqtype IrqType) {
+	irq.request(uint32(irqtype))
 	irq.If.Value |= uint32(irqtype)
 	// irq.Log().Info("raise", irq.If)
 	irq.updateLineStatus()
@@ -107,6 +175,7 @@ func (irq *HwIrq) Raise(irqtype IrqType) {
 // only by a subsequent call to Assert().
 func (irq *HwIrq) Assert(irqtype IrqType, set bool) {
 	if set {
+		irq.request(uint32(irqtype))
 		irq.lvlirq |= uint32(irqtype)
 		irq.If.Value |= uint32(irqtype)
 	} else {
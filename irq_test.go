@@ -0,0 +1,38 @@
+package main
+
+import (
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
+	"testing"
+)
+
+func TestIrqHaltCp15(t *testing.T) {
+	cpu := arm.NewCpu(arm.ARMv5, hwio.NewTable("test"))
+	cp15 := cpu.EnableCp15()
+	irq := NewHwIrq("irq", cpu)
+	cp15.SetHaltHook(irq.Halt)
+	irq.Ie.Value = uint32(IrqVBlank)
+
+	// Wait for interrupt (mcr p15, 0, r0, c7, c0, 4) with an enabled
+	// interrupt already pending: the CPU doesn't halt, even if IME is clear
+	irq.Raise(IrqVBlank)
+	cp15.Write(0, 7, 0, 4, 0)
+	if cpu.Halted() {
+		t.Errorf("halted with a pending interrupt")
+	}
+
+	// Once acknowledged, the CPU halts until the next one
+	irq.If.Write32(0, uint32(IrqVBlank))
+	cp15.Write(0, 7, 0, 4, 0)
+	if !cpu.Halted() {
+		t.Fatalf("not halted")
+	}
+	irq.Raise(IrqHBlank)
+	if !cpu.Halted() {
+		t.Errorf("released by a disabled interrupt")
+	}
+	irq.Raise(IrqVBlank)
+	if cpu.Halted() {
+		t.Errorf("not released by an enabled interrupt")
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Number of IRQ deliveries kept in the IRQ log
+const cIrqLogSize = 256
+
+// IrqEvent describes an interrupt delivered to a CPU (that is, the CPU
+// jumped to the IRQ vector).
+type IrqEvent struct {
+	Frame   int    `json:"frame"`
+	Cycles  int64  `json:"cycles"` // emulation time of the delivery (see emu.Sync.Cycles)
+	Cpu     string `json:"cpu"`
+	Sources uint32 `json:"sources"` // IE & IF: the sources that caused the interrupt
+	Ie      uint32 `json:"ie"`
+	If      uint32 `json:"if"`
+	PC      uint32 `json:"pc"`      // address of the interrupted instruction
+	Latency int64  `json:"latency"` // cycles since the oldest source was requested
+}
+
+var irqNames = [32]string{
+	0: "vblank", 1: "hblank", 2: "vmatch", 3: "timer0", 4: "timer1",
+	5: "timer2", 6: "timer3", 7: "rtc", 8: "dma0", 9: "dma1", 10: "dma2",
+	11: "dma3", 12: "keypad", 13: "slot2", 16: "ipcsync", 17: "ipcsend",
+	18: "ipcrecv", 19: "cardxfer", 20: "cardeject", 21: "gxfifo",
+	22: "lid", 23: "spi", 24: "wifi",
+}
+
+// Return the names of the IRQ sources in the mask
+func irqSourceNames(mask uint32) string {
+	var names []string
+	for i := uint(0); i < 32; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		if irqNames[i] != "" {
+			names = append(names, irqNames[i])
+		} else {
+			names = append(names, fmt.Sprintf("irq%d", i))
+		}
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ",")
+}
+
+func (ev IrqEvent) String() string {
+	return fmt.Sprintf("%6d %s pc=%08x lat=%-6d %-20s IE=%08x IF=%08x",
+		ev.Frame, ev.Cpu, ev.PC, ev.Latency, irqSourceNames(ev.Sources), ev.Ie, ev.If)
+}
+
+// IrqLog is a ring buffer of the last IRQs delivered to both CPUs, so that
+// the history can be inspected (from the debugger, or the control socket)
+// when the emulation stops, eg: to debug games waiting for an IRQ that
+// never comes. It must be accessed only while the emulation is stopped.
+type IrqLog struct {
+	events [cIrqLogSize]IrqEvent
+	n      int // total number of events ever added
+}
+
+func (l *IrqLog) Add(ev IrqEvent) {
+	l.events[l.n%cIrqLogSize] = ev
+	l.n++
+}
+
+// Events returns (at most) the last n events, oldest first.
+func (l *IrqLog) Events(n int) []IrqEvent {
+	if n > l.n {
+		n = l.n
+	}
+	if n > cIrqLogSize {
+		n = cIrqLogSize
+	}
+	if n < 0 {
+		n = 0
+	}
+	res := make([]IrqEvent, n)
+	for i := range res {
+		res[i] = l.events[(l.n-n+i)%cIrqLogSize]
+	}
+	return res
+}
+
+// Lines of the IRQ view of the debugger: the current state of the IRQ
+// registers of both CPUs, followed by the last delivered IRQs (newest
+// first).
+func (emu *NDSEmulator) irqLogView(n int) []string {
+	var lines []string
+	for _, irq := range []*HwIrq{nds9.Irq, nds7.Irq} {
+		lines = append(lines, fmt.Sprintf("%s: IME=%d IE=%08x IF=%08x pending=%s",
+			irq.Name, irq.Ime.Value, irq.Ie.Value, irq.If.Value,
+			irqSourceNames(irq.Ie.Value&irq.If.Value)))
+	}
+	events := emu.IrqLog.Events(n - len(lines))
+	for i := len(events) - 1; i >= 0; i-- {
+		lines = append(lines, events[i].String())
+	}
+	return lines
+}
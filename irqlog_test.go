@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestIrqLog(t *testing.T) {
+	var l IrqLog
+	if evs := l.Events(10); len(evs) != 0 {
+		t.Errorf("empty log returned %d events", len(evs))
+	}
+
+	for i := 0; i < cIrqLogSize+10; i++ {
+		l.Add(IrqEvent{Frame: i})
+	}
+	evs := l.Events(3)
+	if len(evs) != 3 {
+		t.Fatalf("got %d events, want 3", len(evs))
+	}
+	for i, ev := range evs {
+		if want := cIrqLogSize + 7 + i; ev.Frame != want {
+			t.Errorf("event %d: frame %d, want %d", i, ev.Frame, want)
+		}
+	}
+
+	evs = l.Events(1000)
+	if len(evs) != cIrqLogSize {
+		t.Fatalf("got %d events, want %d", len(evs), cIrqLogSize)
+	}
+	if evs[0].Frame != 10 || evs[cIrqLogSize-1].Frame != cIrqLogSize+9 {
+		t.Errorf("wrong events after wrap: first %d, last %d", evs[0].Frame, evs[cIrqLogSize-1].Frame)
+	}
+}
+
+func TestIrqSourceNames(t *testing.T) {
+	for _, tt := range []struct {
+		mask uint32
+		want string
+	}{
+		{0, "-"},
+		{1 << 0, "vblank"},
+		{1<<3 | 1<<18, "timer0,ipcrecv"},
+		{1 << 30, "irq30"},
+	} {
+		if got := irqSourceNames(tt.mask); got != tt.want {
+			t.Errorf("irqSourceNames(%08x) = %q, want %q", tt.mask, got, tt.want)
+		}
+	}
+}
@@ -13,6 +13,49 @@ type HwKey struct {
 	ExtKeyIn hwio.Reg16 `hwio:"bank=1,offset=0x6,reset=0x7F,readonly,rcb"`
 
 	penDown bool
+	buttons Buttons // buttons pressed by input playback
+}
+
+// Buttons is a bitmask of NDS buttons. Bits 0-9 match the layout of KEYIN,
+// while bits 10-11 (X/Y) match bits 0-1 of EXTKEYIN.
+type Buttons uint16
+
+const (
+	ButtonA Buttons = 1 << iota
+	ButtonB
+	ButtonSelect
+	ButtonStart
+	ButtonRight
+	ButtonLeft
+	ButtonUp
+	ButtonDown
+	ButtonR
+	ButtonL
+	ButtonX
+	ButtonY
+	numButtons = iota
+)
+
+var buttonNames = [numButtons]string{
+	"A", "B", "SELECT", "START", "RIGHT", "LEFT", "UP", "DOWN", "R", "L", "X", "Y",
+}
+
+// Host keyboard mapping, in the same order of the buttons
+var buttonKeys = [numButtons]int{
+	hw.SCANCODE_Z, hw.SCANCODE_X, hw.SCANCODE_RSHIFT, hw.SCANCODE_RETURN,
+	hw.SCANCODE_RIGHT, hw.SCANCODE_LEFT, hw.SCANCODE_UP, hw.SCANCODE_DOWN,
+	hw.SCANCODE_A, hw.SCANCODE_S, hw.SCANCODE_D, hw.SCANCODE_C,
+}
+
+// KeyboardButtons returns the buttons currently pressed on the host keyboard
+func KeyboardButtons() Buttons {
+	var b Buttons
+	for i, sc := range buttonKeys {
+		if KeyState[sc] != 0 {
+			b |= 1 << uint(i)
+		}
+	}
+	return b
 }
 
 func NewHwKey() *HwKey {
@@ -25,6 +68,17 @@ func (key *HwKey) SetPenDown(value bool) {
 	key.penDown = value
 }
 
+// SetButtons sets buttons that are reported as pressed in addition to the
+// ones pressed on the keyboard (used for input playback)
+func (key *HwKey) SetButtons(b Buttons) {
+	key.buttons = b
+}
+
+// Buttons returns the buttons that are currently pressed
+func (key *HwKey) Buttons() Buttons {
+	return KeyboardButtons() | key.buttons
+}
+
 func (key *HwKey) WriteKEYCNT(_, val uint16) {
 	if val&(1<<14) != 0 {
 		log.ModInput.Fatal("key interrupt not implemented")
@@ -32,47 +86,11 @@ func (key *HwKey) WriteKEYCNT(_, val uint16) {
 }
 
 func (key *HwKey) ReadKEYIN(val uint16) uint16 {
-	if KeyState[hw.SCANCODE_Z] != 0 {
-		val &^= 1 << 0
-	}
-	if KeyState[hw.SCANCODE_X] != 0 {
-		val &^= 1 << 1
-	}
-	if KeyState[hw.SCANCODE_RSHIFT] != 0 {
-		val &^= 1 << 2
-	}
-	if KeyState[hw.SCANCODE_RETURN] != 0 {
-		val &^= 1 << 3
-	}
-	if KeyState[hw.SCANCODE_RIGHT] != 0 {
-		val &^= 1 << 4
-	}
-	if KeyState[hw.SCANCODE_LEFT] != 0 {
-		val &^= 1 << 5
-	}
-	if KeyState[hw.SCANCODE_UP] != 0 {
-		val &^= 1 << 6
-	}
-	if KeyState[hw.SCANCODE_DOWN] != 0 {
-		val &^= 1 << 7
-	}
-	if KeyState[hw.SCANCODE_A] != 0 {
-		val &^= 1 << 8
-	}
-	if KeyState[hw.SCANCODE_S] != 0 {
-		val &^= 1 << 9
-	}
-	return val
+	return val &^ uint16(key.Buttons()&0x3FF)
 }
 
 func (key *HwKey) ReadEXTKEYIN(val uint16) uint16 {
-
-	if KeyState[hw.SCANCODE_D] != 0 {
-		val &^= 1 << 0
-	}
-	if KeyState[hw.SCANCODE_C] != 0 {
-		val &^= 1 << 1
-	}
+	val &^= uint16(key.Buttons() >> 10)
 	if key.penDown {
 		val &^= 1 << 6
 	}
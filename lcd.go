@@ -21,10 +21,23 @@ const (
 	cHBlankFirstDot = 268
 )
 
+// HwLcd is the view of the LCD controller from one CPU. Each CPU has its own
+// DISPSTAT (with its own IRQ settings), but there is a single LCD timing,
+// derived from the Sync dot clock: VCOUNT thus reads the same on both CPUs,
+// and the same line is drawn by both 2D engines (see NDSEmulator.hsync).
+//
+// DISPSTAT layout:
+//
+//	0     VBlank flag (lines 192-261)
+//	1     HBlank flag (all lines, from dot 268)
+//	2     VCount match flag
+//	3-5   VBlank, HBlank, VCount match IRQ enables
+//	7     bit 8 of the VCount match value
+//	8-15  bits 0-7 of the VCount match value
 type HwLcd struct {
 	Irq *HwIrq
 
-	DispStat hwio.Reg16 `hwio:"offset=4,rwmask=0xFFF8,rcb"`
+	DispStat hwio.Reg16 `hwio:"offset=4,rwmask=0xFFB8,rcb"`
 	VCount   hwio.Reg16 `hwio:"offset=6,readonly,rcb"`
 }
 
@@ -34,23 +47,26 @@ func NewHwLcd(irq *HwIrq) *HwLcd {
 	return lcd
 }
 
+// Line number to be matched by the VCount match flag/IRQ
+func vcountMatch(stat uint16) int {
+	return int(stat>>8 | (stat&0x80)<<1)
+}
+
 func (lcd *HwLcd) ReadDISPSTAT(stat uint16) uint16 {
 	x, y := Emu.Sync.DotPos()
 
-	// VBlank: not set on line 227
+	// VBlank: not set on the last line (262)
 	if y >= cVBlankFirstLine && y <= cVBlankLastLine {
 		stat |= cVBlankFlag
 	}
 
 	// HBlank: the flag is kept to 0 for 268 dots / ~1600 cycles (even
 	// if the screen is 256 dots)
-	if x > cHBlankFirstDot {
+	if x >= cHBlankFirstDot {
 		stat |= cHBlankFlag
 	}
 
-	// VMatch
-	vmatch := int(stat>>8 | (stat&0x80)<<1)
-	if y == vmatch {
+	if y == vcountMatch(stat) {
 		stat |= cVMatchFlag
 	}
 
@@ -72,8 +88,7 @@ func (lcd *HwLcd) SyncEvent(x, y int) {
 			}
 		}
 
-		vmatch := int(lcd.DispStat.Value>>8 | (lcd.DispStat.Value&0x80)<<1)
-		if y == vmatch && lcd.DispStat.Value&cVMatchIrq != 0 {
+		if y == vcountMatch(lcd.DispStat.Value) && lcd.DispStat.Value&cVMatchIrq != 0 {
 			modLcd.WithFields(log.Fields{
 				"irq":  lcd.Irq.Name,
 				"line": y,
@@ -82,11 +97,9 @@ func (lcd *HwLcd) SyncEvent(x, y int) {
 		}
 
 	case cHBlankFirstDot:
-		if !(y >= cVBlankFirstLine && y <= cVBlankLastLine) {
-			if lcd.DispStat.Value&cHBlankIrq != 0 {
-				// modLcd.WithField("irq", lcd.Irq.Name).Info("HBlank IRQ")
-				lcd.Irq.Raise(IrqHBlank)
-			}
+		// Unlike HBlank DMAs, HBlank IRQs are also raised during VBlank
+		if lcd.DispStat.Value&cHBlankIrq != 0 {
+			lcd.Irq.Raise(IrqHBlank)
 		}
 	default:
 		// panic("unreachable")
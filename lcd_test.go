@@ -0,0 +1,75 @@
+package main
+
+import (
+	"ndsemu/arm"
+	"ndsemu/emu"
+	"ndsemu/emu/hwio"
+	"testing"
+)
+
+func newTestLcd() *HwLcd {
+	cpu := arm.NewCpu(arm.ARMv5, hwio.NewTable("test"))
+	return NewHwLcd(NewHwIrq("irq", cpu))
+}
+
+func TestLcdHBlankIrq(t *testing.T) {
+	lcd := newTestLcd()
+	lcd.DispStat.Value = cHBlankIrq
+
+	// HBlank IRQs are raised on every line, including VBlank
+	for _, y := range []int{0, 191, cVBlankFirstLine, 200, cVBlankLastLine, 262} {
+		lcd.Irq.If.Value = 0
+		lcd.SyncEvent(0, y)
+		if lcd.Irq.If.Value != 0 {
+			t.Errorf("line %d: IRQ raised at the start of the line: %x", y, lcd.Irq.If.Value)
+		}
+		lcd.SyncEvent(cHBlankFirstDot, y)
+		if lcd.Irq.If.Value != uint32(IrqHBlank) {
+			t.Errorf("line %d: HBlank IRQ not raised: %x", y, lcd.Irq.If.Value)
+		}
+	}
+
+	// Not raised if disabled
+	lcd.DispStat.Value = 0
+	lcd.Irq.If.Value = 0
+	lcd.SyncEvent(cHBlankFirstDot, 100)
+	if lcd.Irq.If.Value != 0 {
+		t.Errorf("disabled HBlank IRQ raised: %x", lcd.Irq.If.Value)
+	}
+}
+
+func TestLcdDispStatFlags(t *testing.T) {
+	sync, err := emu.NewSync(SyncConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Emu = &NDSEmulator{Sync: sync}
+	lcd := newTestLcd()
+
+	tests := []struct {
+		x, y  int
+		flags uint16
+	}{
+		{0, 0, 0},
+		{cHBlankFirstDot - 1, 0, 0},
+		{cHBlankFirstDot, 0, cHBlankFlag},
+		{354, 0, cHBlankFlag},
+		{0, 1, 0},
+		{cHBlankFirstDot, 191, cHBlankFlag},
+		{0, cVBlankFirstLine, cVBlankFlag},
+		{cHBlankFirstDot - 1, 200, cVBlankFlag},
+		{cHBlankFirstDot, 200, cVBlankFlag | cHBlankFlag},
+		{0, cVBlankLastLine, cVBlankFlag},
+		{0, 262, 0},
+		{cHBlankFirstDot, 262, cHBlankFlag},
+	}
+	for _, tt := range tests {
+		sync.RunUntil(sync.Cycles() + sync.DotPosDistance(tt.x, tt.y))
+		if x, y := sync.DotPos(); x != tt.x || y != tt.y {
+			t.Fatalf("invalid dot position: %d,%d, want %d,%d", x, y, tt.x, tt.y)
+		}
+		if stat := lcd.ReadDISPSTAT(lcd.DispStat.Value) & 3; stat != tt.flags {
+			t.Errorf("dot %d,%d: flags %x, want %x", tt.x, tt.y, stat, tt.flags)
+		}
+	}
+}
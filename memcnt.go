@@ -50,6 +50,22 @@ type HwMemoryController struct {
 	Texture        [4][]byte
 	TexturePalette [6][]byte
 
+	// Bank (letter) backing each texture image/palette slot, or 0 if none
+	texSlotBank [4]byte
+	palSlotBank [6]byte
+
+	// Bank (letter) backing each extended palette slot, or 0 if none
+	bgExtSlotBank  [2][4]byte
+	objExtSlotBank [2]byte
+
+	// Generation counters of texture image/palette slots, incremented
+	// whenever their contents might have changed (see VramTextureBank)
+	texGen [4]uint32
+	palGen [6]uint32
+
+	// Banks currently mapped as texture image or palette
+	texBank [9]bool
+
 	zero [16 * 1024]byte
 }
 
@@ -126,6 +142,7 @@ func (mc *HwMemoryController) ReadWRAMSTAT(_ uint8) uint8 {
 func (mc *HwMemoryController) WriteEXMEMCNT(old, val uint16) {
 	// Writable by NDS9. EXMEMSTAT reflects EXMEMCNT in higher bits
 	mc.ExMemStat.Value |= val & 0xFF80
+	setSlot2Timings(mc.Nds9.Bus, val, true)
 
 	// Bit 11 changed: gamecard nds9/nds7 mapping
 	if (old^val)&(1<<11) != 0 {
@@ -148,32 +165,43 @@ func (mc *HwMemoryController) WriteEXMEMCNT(old, val uint16) {
 
 	// Bit 7 changed: GBA slot nds9/nds7 mapping
 	if (old^val)&(1<<7) != 0 {
-		if val&(1<<7) != 0 {
-			// GBA slot mapped to NDS7. Since we don't emulate it yet, when
-			// there is no card in the slot, 0xFF is returned
-			nds7.Bus.Unmap(0x8000000, 0xAFFFFFF)
-			nds7.Bus.MapMemorySlice(0x8000000, 0x9FFFFFF, Emu.Hw.Sl2.Rom[:], true)
-			nds7.Bus.MapMemorySlice(0xA000000, 0xAFFFFFF, Emu.Hw.Sl2.Ram[:], false)
-
-			// NDS9 sees a zero-filled region
-			nds9.Bus.Unmap(0x8000000, 0xAFFFFFF)
-			nds9.Bus.MapMemorySlice(0x8000000, 0xAFFFFFF, mc.zero[:], true)
-		} else {
-			// GBA slot mapped to NDS9. Same as above, reversing roles
-			nds9.Bus.Unmap(0x8000000, 0xAFFFFFF)
-			nds9.Bus.MapMemorySlice(0x8000000, 0x9FFFFFF, Emu.Hw.Sl2.Rom[:], true)
-			nds9.Bus.MapMemorySlice(0xA000000, 0xAFFFFFF, Emu.Hw.Sl2.Ram[:], false)
+		mc.mapSlot2()
+	}
+}
 
-			nds7.Bus.Unmap(0x8000000, 0xAFFFFFF)
-			nds7.Bus.MapMemorySlice(0x8000000, 0xAFFFFFF, mc.zero[:], true)
-		}
+// Map the GBA slot to the CPU that owns it, as configured in EXMEMCNT. This
+// must also be called when the cartridge in the slot changes.
+func (mc *HwMemoryController) mapSlot2() {
+	if mc.ExMemCnt.Value&(1<<7) != 0 {
+		// GBA slot mapped to NDS7. Since we don't emulate it yet, when
+		// there is no card in the slot, 0xFF is returned
+		nds7.Bus.Unmap(0x8000000, 0xAFFFFFF)
+		nds7.Bus.MapMemorySlice(0x8000000, 0x9FFFFFF, Emu.Hw.Sl2.Rom[:], true)
+		nds7.Bus.MapMemorySlice(0xA000000, 0xAFFFFFF, Emu.Hw.Sl2.Ram[:], false)
+		Emu.Hw.Sl2.Irq = nds7.Irq
+
+		// NDS9 sees a zero-filled region
+		nds9.Bus.Unmap(0x8000000, 0xAFFFFFF)
+		nds9.Bus.MapMemorySlice(0x8000000, 0xAFFFFFF, mc.zero[:], true)
+	} else {
+		// GBA slot mapped to NDS9. Same as above, reversing roles
+		nds9.Bus.Unmap(0x8000000, 0xAFFFFFF)
+		nds9.Bus.MapMemorySlice(0x8000000, 0x9FFFFFF, Emu.Hw.Sl2.Rom[:], true)
+		nds9.Bus.MapMemorySlice(0xA000000, 0xAFFFFFF, Emu.Hw.Sl2.Ram[:], false)
+		Emu.Hw.Sl2.Irq = nds9.Irq
+
+		nds7.Bus.Unmap(0x8000000, 0xAFFFFFF)
+		nds7.Bus.MapMemorySlice(0x8000000, 0xAFFFFFF, mc.zero[:], true)
 	}
 }
 
 func (mc *HwMemoryController) WriteEXMEMSTAT(_, val uint16) {
 	// Writable by NDS7. Low bits are also carried over to EXMEMCNT, and since
-	// there is a rwmask here (preserving the higher bits), we can just copy it
+	// there is a rwmask here (preserving the higher bits), we can just copy it.
+	// The GBA slot timings of the NDS9 follow EXMEMCNT, so they change too.
 	mc.ExMemCnt.Value = mc.ExMemStat.Value
+	setSlot2Timings(mc.Nds7.Bus, val, false)
+	setSlot2Timings(mc.Nds9.Bus, mc.ExMemCnt.Value, true)
 }
 
 func (mc *HwMemoryController) mapVram7(idx byte, start uint32, end uint32) {
@@ -216,37 +244,31 @@ func (mc *HwMemoryController) mapVram9(idx byte, start uint32, end uint32) {
 	}
 }
 
+// Map a bank as BG extended palette of the specified engine, starting from
+// firstslot: each slot is 8K, and the bank covers as many slots as its size
+// allows (up to slot 3). Bank E only uses its first 32K.
 func (mc *HwMemoryController) mapBgExtPalette(idx byte, engIdx int, firstslot int) {
 	modMemCnt.WithFields(log.Fields{
 		"bank": string(idx),
 		"slot": "bg-ext-palette",
 	}).Infof("mapping VRAM on NDS9")
-	idx -= 'A'
 
-	var i int
-	ptr := mc.vram[idx]
+	ptr := mc.vram[idx-'A']
 	for i := firstslot; i < 4 && len(ptr) > 0; i++ {
 		mc.BgExtPalette[engIdx][i] = ptr[:8*1024]
+		mc.bgExtSlotBank[engIdx][i] = idx
 		ptr = ptr[8*1024:]
 	}
-	lastslot := i
-	mc.unmapVram[idx] = func() {
-		for i := firstslot; i < lastslot; i++ {
-			mc.BgExtPalette[engIdx][i] = nil
-		}
-	}
 }
 
+// Map the first 8K of a bank as OBJ extended palette of the specified engine
 func (mc *HwMemoryController) mapObjExtPalette(idx byte, engIdx int) {
 	modMemCnt.WithFields(log.Fields{
 		"bank": string(idx),
 		"slot": "obj-ext-palette",
 	}).Infof("mapping VRAM on NDS9")
-	idx -= 'A'
-	mc.ObjExtPalette[engIdx] = mc.vram[idx][:8*1024]
-	mc.unmapVram[idx] = func() {
-		mc.ObjExtPalette[engIdx] = nil
-	}
+	mc.ObjExtPalette[engIdx] = mc.vram[idx-'A'][:8*1024]
+	mc.objExtSlotBank[engIdx] = idx
 }
 
 func (mc *HwMemoryController) mapTexture(idx byte, slotnum int) {
@@ -254,7 +276,10 @@ func (mc *HwMemoryController) mapTexture(idx byte, slotnum int) {
 		"bank": string(idx),
 		"slot": "texture",
 	}).Infof("mapping VRAM on NDS9")
+	mc.texSlotBank[slotnum] = idx
+	mc.texGen[slotnum]++
 	idx -= 'A'
+	mc.texBank[idx] = true
 	mc.Texture[slotnum] = mc.vram[idx][:128*1024]
 	mc.unmapVram[idx] = func() {
 		mc.Texture[slotnum] = nil
@@ -266,12 +291,45 @@ func (mc *HwMemoryController) mapTexturePalette(idx byte, slotnum int, offset in
 		"bank": string(idx),
 		"slot": "texture-palette",
 	}).Infof("mapping VRAM on NDS9")
+	mc.palSlotBank[slotnum] = idx
+	mc.palGen[slotnum]++
 	idx -= 'A'
+	mc.texBank[idx] = true
 	mc.TexturePalette[slotnum] = mc.vram[idx][offset : offset+16*1024]
 }
 
 func (mc *HwMemoryController) writeVramCnt(idx byte, val uint8) (int, int) {
+	// The bank is being remapped: any texture slot it was backing must be
+	// decoded again, as the contents might change.
+	for i, b := range mc.texSlotBank {
+		if b == idx {
+			mc.texGen[i]++
+		}
+	}
+	for i, b := range mc.palSlotBank {
+		if b == idx {
+			mc.palGen[i]++
+		}
+	}
+
+	// Extended palettes are only accessible by the 2D engines, so they can
+	// be safely unmapped (unlike CPU mappings, see below): the slots become
+	// unavailable until they're mapped again.
+	for e := range mc.bgExtSlotBank {
+		for i, b := range mc.bgExtSlotBank[e] {
+			if b == idx {
+				mc.BgExtPalette[e][i] = nil
+				mc.bgExtSlotBank[e][i] = 0
+			}
+		}
+		if mc.objExtSlotBank[e] == idx {
+			mc.ObjExtPalette[e] = nil
+			mc.objExtSlotBank[e] = 0
+		}
+	}
+
 	idx -= 'A'
+	mc.texBank[idx] = false
 	// FIXME: the VRAM unmapping logic is broken. The hwio.Table.Unmap() function
 	// unmaps whatever happens to be present in that range, possibly a new mapping
 	// of a different bank. Consider this:
@@ -436,7 +494,7 @@ func (mc *HwMemoryController) WriteVRAMCNTF(_, val uint8) {
 		slot := int((ofs&1)*1 + (ofs&2)*2)
 		mc.mapTexturePalette('F', slot, 0)
 	case 4:
-		mc.mapBgExtPalette('F', 0, ofs*2)
+		mc.mapBgExtPalette('F', 0, (ofs&1)*2)
 	case 5:
 		mc.mapObjExtPalette('F', 0)
 	default:
@@ -465,7 +523,7 @@ func (mc *HwMemoryController) WriteVRAMCNTG(_, val uint8) {
 		slot := int((ofs&1)*1 + (ofs&2)*2)
 		mc.mapTexturePalette('G', slot, 0)
 	case 4:
-		mc.mapBgExtPalette('G', 0, ofs*2)
+		mc.mapBgExtPalette('G', 0, (ofs&1)*2)
 	case 5:
 		mc.mapObjExtPalette('G', 0)
 	default:
@@ -531,7 +589,8 @@ var empty [e2d.VramSmallestBankSize]byte
 //
 // If the requested bank is unmapped, a zero-filled area is returned. If the
 // requested bank is mapped for less than 256K, the missing areas will be
-// zero-filled as well.
+// zero-filled as well. Offsets beyond the VRAM of the engine (see
+// e2d.VramPoolSize) wrap around, as the VRAM is mirrored.
 func (mc *HwMemoryController) VramLinearBank(engine int, which e2d.VramLinearBankId, baseOffset int) (vb e2d.VramLinearBank) {
 	for i := 0; i < 32; i++ {
 		var ptr []byte
@@ -546,9 +605,11 @@ func (mc *HwMemoryController) VramLinearBank(engine int, which e2d.VramLinearBan
 				ptr = mc.ObjExtPalette[engine]
 			}
 		case e2d.VramLinearBG:
-			ptr = mc.Nds9.Bus.FetchPointer(uint32(0x6000000 + 0x200000*engine + baseOffset + i*e2d.VramSmallestBankSize))
+			off := (baseOffset + i*e2d.VramSmallestBankSize) % e2d.VramPoolSize(engine, which)
+			ptr = mc.Nds9.Bus.FetchPointer(uint32(0x6000000 + 0x200000*engine + off))
 		case e2d.VramLinearOAM:
-			ptr = mc.Nds9.Bus.FetchPointer(uint32(0x6400000 + 0x200000*engine + baseOffset + i*e2d.VramSmallestBankSize))
+			off := (baseOffset + i*e2d.VramSmallestBankSize) % e2d.VramPoolSize(engine, which)
+			ptr = mc.Nds9.Bus.FetchPointer(uint32(0x6400000 + 0x200000*engine + off))
 		default:
 			panic("unreachable")
 		}
@@ -577,10 +638,24 @@ func (mc *HwMemoryController) VramRawBank(bank int) []byte {
  * Raster3D VRAM
  ********************************************/
 
+// Texture slots are not unmapped when their bank is mapped elsewhere (see
+// writeVramCnt), so the CPU could still write into them: in that case, their
+// contents are assumed to change every frame.
+
 func (mc *HwMemoryController) VramTextureBank() raster3d.VramTextureBank {
-	return raster3d.VramTextureBank{Slots: mc.Texture}
+	for i, b := range mc.texSlotBank {
+		if b != 0 && !mc.texBank[b-'A'] {
+			mc.texGen[i]++
+		}
+	}
+	return raster3d.VramTextureBank{Slots: mc.Texture, Gen: mc.texGen}
 }
 
 func (mc *HwMemoryController) VramTexturePaletteBank() raster3d.VramTexturePaletteBank {
-	return raster3d.VramTexturePaletteBank{Slots: mc.TexturePalette}
+	for i, b := range mc.palSlotBank {
+		if b != 0 && !mc.texBank[b-'A'] {
+			mc.palGen[i]++
+		}
+	}
+	return raster3d.VramTexturePaletteBank{Slots: mc.TexturePalette, Gen: mc.palGen}
 }
@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+func newTestMemCtrl() *HwMemoryController {
+	return NewMemoryController(NewNDS9(), NewNDS7(), make([]byte, 656*1024))
+}
+
+func TestExtPaletteSlots(t *testing.T) {
+	mc := newTestMemCtrl()
+
+	// Check which bank (and offset) backs each BG extended palette slot of
+	// engine A, and the OBJ extended palette; "" means unmapped.
+	check := func(step string, bg [4]string, bgofs [4]int, obj string) {
+		for i := range bg {
+			bank, ofs := mc.vramBankOf(mc.BgExtPalette[0][i])
+			if bank != bg[i] || (bank != "" && ofs != bgofs[i]) {
+				t.Errorf("%s: bg slot %d: got %q+%x, want %q+%x", step, i, bank, ofs, bg[i], bgofs[i])
+			}
+		}
+		if bank, _ := mc.vramBankOf(mc.ObjExtPalette[0]); bank != obj {
+			t.Errorf("%s: obj slot: got %q, want %q", step, bank, obj)
+		}
+	}
+
+	// VRAMCNT: enable | ofs<<3 | mst
+	mc.WriteVRAMCNTE(0, 0x80|4)
+	check("E=bgext", [4]string{"E", "E", "E", "E"}, [4]int{0, 0x2000, 0x4000, 0x6000}, "")
+
+	// F and G cover two slots each, starting at 0 or 2 depending on OFS bit 0
+	mc.WriteVRAMCNTF(0, 0x80|1<<3|4)
+	check("F=bgext(2)", [4]string{"E", "E", "F", "F"}, [4]int{0, 0x2000, 0, 0x2000}, "")
+	mc.WriteVRAMCNTG(0, 0x80|2<<3|4)
+	check("G=bgext(0)", [4]string{"G", "G", "F", "F"}, [4]int{0, 0x2000, 0, 0x2000}, "")
+
+	// Remapping a bank elsewhere releases the slots it was backing
+	mc.WriteVRAMCNTG(0, 0x80|5)
+	check("G=objext", [4]string{"", "", "F", "F"}, [4]int{0, 0, 0, 0x2000}, "G")
+	mc.WriteVRAMCNTF(0, 0x80|0)
+	check("F=lcdc", [4]string{"", "", "", ""}, [4]int{}, "G")
+	mc.WriteVRAMCNTG(0, 0)
+	check("G=off", [4]string{"", "", "", ""}, [4]int{}, "")
+}
+
+func TestSlot2Timings(t *testing.T) {
+	mc := newTestMemCtrl()
+	check := func(step string, cnt9, cnt7 uint16) {
+		rom, ram := slot2Timings(cnt9)
+		if got := mc.Nds9.Bus.RegionTimings(); got[0x08] != arm9Timings(rom) || got[0x0A] != arm9Timings(ram) {
+			t.Errorf("%s: nds9 timings: got rom=%v ram=%v, want cnt=%04x", step, got[0x08], got[0x0A], cnt9)
+		}
+		rom, ram = slot2Timings(cnt7)
+		if got := mc.Nds7.Bus.RegionTimings(); got[0x08] != rom || got[0x0A] != ram {
+			t.Errorf("%s: nds7 timings: got rom=%v ram=%v, want cnt=%04x", step, got[0x08], got[0x0A], cnt7)
+		}
+	}
+
+	// As done by initBusTimings at reset
+	setSlot2Timings(mc.Nds9.Bus, 0, true)
+	setSlot2Timings(mc.Nds7.Bus, 0, false)
+	check("reset", 0, 0)
+
+	// ROM: 6 cycles for the first access; SRAM: 8 cycles
+	mc.ExMemCnt.Write16(0x4000204, 2<<2|1)
+	check("exmemcnt", 2<<2|1, 0)
+
+	// The low bits of EXMEMSTAT are carried over to EXMEMCNT, so both
+	// CPUs get the new timings
+	mc.ExMemStat.Write16(0x4000204, 1<<4|3)
+	check("exmemstat", 1<<4|3, 1<<4|3)
+}
@@ -0,0 +1,239 @@
+// Package memdump analyzes dumps of the NDS memory, looking for data that
+// is useful while reverse engineering a game: text strings (ASCII and
+// Shift-JIS), and tables of pointers into the known memory regions.
+//
+// Results are heuristic: a table of small integers might look like a
+// pointer table, and some binary data might look like text, so the
+// thresholds in Options can be tuned to trade false positives for missed
+// results.
+package memdump
+
+import "encoding/binary"
+
+// A Region is a memory area of the NDS address space that pointers found in
+// dumps can point into.
+type Region struct {
+	Name       string
+	Start, End uint32 // [Start, End)
+}
+
+var Regions = []Region{
+	{"itcm", 0x01000000, 0x01008000},
+	{"main", 0x02000000, 0x02400000},
+	{"swram", 0x03000000, 0x03008000},
+	{"wram7", 0x03800000, 0x03810000},
+	{"vram", 0x06000000, 0x06900000},
+}
+
+// RegionOf returns the region containing addr, or nil.
+func RegionOf(addr uint32) *Region {
+	for i := range Regions {
+		if addr >= Regions[i].Start && addr < Regions[i].End {
+			return &Regions[i]
+		}
+	}
+	return nil
+}
+
+// DefaultBase guesses the address at which a dump was taken, from its size.
+// It returns false if the size doesn't match any region.
+func DefaultBase(size int) (uint32, bool) {
+	for _, r := range Regions {
+		if int(r.End-r.Start) == size {
+			return r.Start, true
+		}
+	}
+	return 0, false
+}
+
+type Options struct {
+	MinStringLen  int // minimum length of strings, in characters
+	MinTableCount int // minimum number of entries in a pointer table
+}
+
+var DefaultOptions = Options{
+	MinStringLen:  6,
+	MinTableCount: 4,
+}
+
+// A String is a run of text found in the dump.
+type String struct {
+	Addr uint32 `json:"addr"`
+	Text string `json:"text"` // decoded text (see decodeSJIS for Shift-JIS)
+	SJIS bool   `json:"sjis"` // true if it contains Shift-JIS double-byte characters
+	Len  int    `json:"len"`  // length in bytes
+}
+
+// A PointerTable is a run of consecutive (aligned) words, all pointing into
+// the same memory region.
+type PointerTable struct {
+	Addr       uint32 `json:"addr"`
+	Count      int    `json:"count"`
+	Region     string `json:"region"`
+	StringRefs int    `json:"string_refs"` // number of entries pointing to a string found in the dump
+}
+
+type Report struct {
+	Base    uint32         `json:"base"`
+	Strings []String       `json:"strings"`
+	Tables  []PointerTable `json:"tables"`
+}
+
+// Analyze scans a dump of memory taken at address base.
+func Analyze(data []byte, base uint32, opts Options) *Report {
+	rep := &Report{
+		Base:    base,
+		Strings: FindStrings(data, base, opts.MinStringLen),
+		Tables:  FindPointerTables(data, base, opts.MinTableCount),
+	}
+
+	// Tables of pointers to strings are usually the most interesting ones
+	// (eg: message tables), so count how many entries point to strings.
+	strs := make(map[uint32]bool, len(rep.Strings))
+	for _, s := range rep.Strings {
+		strs[s.Addr] = true
+	}
+	for i := range rep.Tables {
+		t := &rep.Tables[i]
+		off := t.Addr - base
+		for j := 0; j < t.Count; j++ {
+			if strs[binary.LittleEndian.Uint32(data[off+uint32(j)*4:])] {
+				t.StringRefs++
+			}
+		}
+	}
+	return rep
+}
+
+func isText(b byte) bool {
+	return (b >= 0x20 && b < 0x7F) || b == '\n' || b == '\t' || b == '\r'
+}
+
+// Shift-JIS double-byte characters: lead byte, and valid trail bytes
+func isSJISLead(b byte) bool  { return (b >= 0x81 && b <= 0x9F) || (b >= 0xE0 && b <= 0xEF) }
+func isSJISTrail(b byte) bool { return b >= 0x40 && b <= 0xFC && b != 0x7F }
+
+func isLetter(b byte) bool { return (b|0x20) >= 'a' && (b|0x20) <= 'z' }
+
+// FindStrings finds runs of at least minLen characters of text. Each run can
+// mix ASCII and Shift-JIS characters (including half-width katakana).
+//
+// Half-width katakana use the single bytes 0xA1-0xDF, which are common in
+// binary data too, so runs containing them are accepted only if they also
+// contain ASCII letters or Shift-JIS double-byte characters.
+func FindStrings(data []byte, base uint32, minLen int) []String {
+	var res []String
+	for i := 0; i < len(data); {
+		start, nchars, sjis := i, 0, false
+		letters, halfw := false, false
+		for i < len(data) {
+			b := data[i]
+			if isText(b) {
+				letters = letters || isLetter(b)
+				i++
+			} else if b >= 0xA1 && b <= 0xDF {
+				halfw = true
+				i++
+			} else if isSJISLead(b) && i+1 < len(data) && isSJISTrail(data[i+1]) {
+				i += 2
+				sjis = true
+			} else {
+				break
+			}
+			nchars++
+		}
+		if nchars >= minLen && (!halfw || letters || sjis) {
+			s := String{Addr: base + uint32(start), SJIS: sjis, Len: i - start}
+			if sjis || halfw {
+				s.Text = decodeSJIS(data[start:i])
+			} else {
+				s.Text = string(data[start:i])
+			}
+			res = append(res, s)
+		}
+		if i == start {
+			i++
+		}
+	}
+	return res
+}
+
+// decodeSJIS converts Shift-JIS text into UTF-8. Only ASCII, kana, and the
+// full-width alphanumeric characters are decoded (which is usually enough to
+// recognize text); other characters (eg: kanji) are replaced with U+FFFD.
+func decodeSJIS(buf []byte) string {
+	var res []rune
+	for i := 0; i < len(buf); i++ {
+		b := buf[i]
+		switch {
+		case b < 0x80:
+			res = append(res, rune(b))
+		case b >= 0xA1 && b <= 0xDF:
+			res = append(res, 0xFF61+rune(b-0xA1))
+		case isSJISLead(b) && i+1 < len(buf):
+			res = append(res, sjisRune(uint16(b)<<8|uint16(buf[i+1])))
+			i++
+		default:
+			res = append(res, 0xFFFD)
+		}
+	}
+	return string(res)
+}
+
+func sjisRune(c uint16) rune {
+	switch {
+	case c == 0x8140:
+		return 0x3000 // ideographic space
+	case c == 0x815B:
+		return 0x30FC // prolonged sound mark
+	case c >= 0x824F && c <= 0x8258:
+		return 0xFF10 + rune(c-0x824F) // digits
+	case c >= 0x8260 && c <= 0x8279:
+		return 0xFF21 + rune(c-0x8260) // uppercase
+	case c >= 0x8281 && c <= 0x829A:
+		return 0xFF41 + rune(c-0x8281) // lowercase
+	case c >= 0x829F && c <= 0x82F1:
+		return 0x3041 + rune(c-0x829F) // hiragana
+	case c >= 0x8340 && c <= 0x837E:
+		return 0x30A1 + rune(c-0x8340) // katakana (first half)
+	case c >= 0x8380 && c <= 0x8396:
+		return 0x30E0 + rune(c-0x8380) // katakana (second half)
+	}
+	return 0xFFFD
+}
+
+// FindPointerTables finds runs of at least minCount aligned words that point
+// into the same region. Runs where all the words are equal are ignored,
+// since they're more likely to be fills.
+func FindPointerTables(data []byte, base uint32, minCount int) []PointerTable {
+	var res []PointerTable
+	// Align the scan to word addresses
+	off := int((4 - base&3) & 3)
+	for off+4 <= len(data) {
+		reg := RegionOf(binary.LittleEndian.Uint32(data[off:]))
+		if reg == nil {
+			off += 4
+			continue
+		}
+
+		first := binary.LittleEndian.Uint32(data[off:])
+		start, count, same := off, 0, true
+		for off+4 <= len(data) {
+			ptr := binary.LittleEndian.Uint32(data[off:])
+			if RegionOf(ptr) != reg {
+				break
+			}
+			same = same && ptr == first
+			count++
+			off += 4
+		}
+		if count >= minCount && !same {
+			res = append(res, PointerTable{
+				Addr:   base + uint32(start),
+				Count:  count,
+				Region: reg.Name,
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+package memdump
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestFindStrings(t *testing.T) {
+	data := make([]byte, 96)
+	copy(data[4:], "Hello, world")
+	copy(data[20:], "abc") // too short
+	// "ゲーム" in Shift-JIS, followed by full-width "ＯＫ"
+	copy(data[32:], []byte{0x83, 0x51, 0x81, 0x5B, 0x83, 0x80, 0x82, 0x6E, 0x82, 0x6A})
+	// Only bytes in the half-width katakana range: likely binary data
+	copy(data[48:], []byte{0xB0, 0xC1, 0xA5, 0x20, 0xD3, 0xBB, 0xA8})
+	// Half-width "ｽﾀｰﾄ" followed by ASCII
+	copy(data[64:], []byte{0xBD, 0xC0, 0xB0, 0xC4, ' ', 'O', 'K'})
+
+	strs := FindStrings(data, 0x02000000, 4)
+	if len(strs) != 3 {
+		t.Fatalf("found %d strings, want 3: %v", len(strs), strs)
+	}
+	if s := strs[0]; s.Addr != 0x02000004 || s.Text != "Hello, world" || s.SJIS {
+		t.Errorf("invalid ASCII string: %+v", s)
+	}
+	if s := strs[1]; s.Addr != 0x02000020 || s.Text != "ゲームＯＫ" || !s.SJIS || s.Len != 10 {
+		t.Errorf("invalid Shift-JIS string: %+v", s)
+	}
+	if s := strs[2]; s.Addr != 0x02000040 || s.Text != "ｽﾀｰﾄ OK" || s.SJIS || s.Len != 7 {
+		t.Errorf("invalid half-width string: %+v", s)
+	}
+}
+
+func TestFindPointerTables(t *testing.T) {
+	data := make([]byte, 128)
+	put := func(off int, vals ...uint32) {
+		for i, v := range vals {
+			binary.LittleEndian.PutUint32(data[off+i*4:], v)
+		}
+	}
+	copy(data[96:], "message")
+	put(0, 0x02000060, 0x02000060, 0x02001000, 0x02000060)       // main RAM, 3 to the string
+	put(16, 0x03800000, 0x03800010, 0x03800020)                  // too short
+	put(32, 0x02100000, 0x02100000, 0x02100000, 0x02100000)      // fill
+	put(48, 0x06000000, 0x06000100, 0x02000000, 0x02000004, 0x0) // mixed regions
+
+	rep := Analyze(data, 0x02000000, Options{MinStringLen: 4, MinTableCount: 4})
+	if len(rep.Tables) != 1 {
+		t.Fatalf("found %d tables, want 1: %+v", len(rep.Tables), rep.Tables)
+	}
+	if tb := rep.Tables[0]; tb.Addr != 0x02000000 || tb.Count != 4 || tb.Region != "main" || tb.StringRefs != 3 {
+		t.Errorf("invalid table: %+v", tb)
+	}
+}
+
+func TestDefaultBase(t *testing.T) {
+	if base, ok := DefaultBase(4 * 1024 * 1024); !ok || base != 0x02000000 {
+		t.Errorf("main RAM: got %08x,%v", base, ok)
+	}
+	if _, ok := DefaultBase(1234); ok {
+		t.Errorf("unknown size detected")
+	}
+}
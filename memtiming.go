@@ -0,0 +1,72 @@
+package main
+
+import (
+	"ndsemu/emu"
+	"ndsemu/emu/hwio"
+)
+
+// Timings of the memory regions, in cycles of the 33MHz bus (see GBATEK,
+// "DS Memory Timings"). Regions not listed here keep the default waitstates
+// of each bus. The GBA slot is configured separately (see slot2Timings).
+var busTimings = []struct {
+	begin, end uint32
+	t          emu.AccessTimings
+}{
+	// Main RAM: 16-bit bus, with a long latency for non-sequential accesses
+	{0x02000000, 0x02FFFFFF, emu.AccessTimings{N16: 8, S16: 1, N32: 9, S32: 2}},
+	// Shared WRAM and ARM7 WRAM
+	{0x03000000, 0x03FFFFFF, emu.AccessTimings{N16: 1, S16: 1, N32: 1, S32: 1}},
+	// I/O registers
+	{0x04000000, 0x04FFFFFF, emu.AccessTimings{N16: 1, S16: 1, N32: 1, S32: 1}},
+	// Palette, VRAM and OAM: 16-bit bus
+	{0x05000000, 0x07FFFFFF, emu.AccessTimings{N16: 1, S16: 1, N32: 2, S32: 2}},
+}
+
+// Timings of the ARM9, given the timings of the bus. The ARM9 runs at twice
+// the bus clock, and non-sequential accesses also pay for the
+// synchronization with the bus: 6 cycles, which makes 8 cycles for an
+// access to fast memory (as the flat waitstates of the ARM9 bus).
+func arm9Timings(t emu.AccessTimings) emu.AccessTimings {
+	return emu.AccessTimings{
+		N16: t.N16*2 + 6, S16: t.S16 * 2,
+		N32: t.N32*2 + 6, S32: t.S32 * 2,
+	}
+}
+
+// Timings of the GBA slot ROM and SRAM, as configured by the waitstate bits
+// of EXMEMCNT (ARM9) or EXMEMSTAT (ARM7). The slot has a 16-bit data bus
+// (8-bit for SRAM).
+func slot2Timings(cnt uint16) (rom, ram emu.AccessTimings) {
+	ws := [4]int{10, 8, 6, 18}
+	n := ws[(cnt>>2)&3]
+	s := 6
+	if cnt&(1<<4) != 0 {
+		s = 4
+	}
+	rom = emu.AccessTimings{N16: n, S16: s, N32: n + s, S32: s * 2}
+
+	sram := ws[cnt&3]
+	ram = emu.AccessTimings{N16: sram, S16: sram, N32: sram, S32: sram}
+	return
+}
+
+// Configure the timings of the GBA slot on a bus. arm9 selects the ARM9
+// timings (see arm9Timings).
+func setSlot2Timings(bus *hwio.Table, cnt uint16, arm9 bool) {
+	rom, ram := slot2Timings(cnt)
+	if arm9 {
+		rom, ram = arm9Timings(rom), arm9Timings(ram)
+	}
+	bus.SetRegionTimings(0x08000000, 0x09FFFFFF, rom)
+	bus.SetRegionTimings(0x0A000000, 0x0AFFFFFF, ram)
+}
+
+// Configure the timings of the memory regions on the buses of both CPUs
+func (emu *NDSEmulator) initBusTimings() {
+	for _, r := range busTimings {
+		nds9.Bus.SetRegionTimings(r.begin, r.end, arm9Timings(r.t))
+		nds7.Bus.SetRegionTimings(r.begin, r.end, r.t)
+	}
+	setSlot2Timings(nds9.Bus, emu.Hw.Mc.ExMemCnt.Value, true)
+	setSlot2Timings(nds7.Bus, emu.Hw.Mc.ExMemStat.Value, false)
+}
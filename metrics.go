@@ -0,0 +1,197 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"ndsemu/emu"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Number of frames over which frame time percentiles are computed (10
+// seconds at normal speed), and over which FPS are averaged.
+const (
+	metricsWindow    = 600
+	metricsFpsWindow = 60
+)
+
+// Metrics collects performance metrics of the emulation core, so that they
+// can be exported over HTTP (see ServeMetrics) and graphed during long
+// sessions. Frames are reported by the emulation goroutine, and snapshots
+// can be taken from any goroutine.
+type Metrics struct {
+	mu        sync.Mutex
+	frames    int64
+	total     time.Duration                // sum of all frame times
+	frameTime [metricsWindow]time.Duration // ring buffer of the last frame times
+	frameEnd  [metricsWindow]time.Time     // ring buffer of the last frame end times
+	subsys    []emu.SubsystemTime          // cumulative, as of the last frame
+	underruns func() int                   // number of audio underruns (optional)
+}
+
+// MetricsSnapshot is the state of the metrics at a given time. Durations are
+// in seconds.
+type MetricsSnapshot struct {
+	Frames         int64              `json:"frames"`
+	FPS            float64            `json:"fps"`
+	FrameTime      map[string]float64 `json:"frame_time"` // percentiles over the last frames ("p50", "p90", "p99", "max")
+	FrameTimeSum   float64            `json:"frame_time_sum"`
+	Subsystems     map[string]float64 `json:"subsystems"` // total time spent running each subsystem
+	AudioUnderruns int                `json:"audio_underruns"`
+	NumGC          uint32             `json:"gc_count"`
+	GCPauseTotal   float64            `json:"gc_pause_total"`
+	HeapAlloc      uint64             `json:"heap_alloc"`
+}
+
+var metricsPercentiles = []struct {
+	name string
+	q    float64
+}{
+	{"p50", 0.50},
+	{"p90", 0.90},
+	{"p99", 0.99},
+	{"max", 1},
+}
+
+func NewMetrics(underruns func() int) *Metrics {
+	return &Metrics{underruns: underruns}
+}
+
+// FrameDone records a frame that was emulated in the specified host time,
+// and ended at the specified instant. subsys are the current per-subsystem
+// timings (see emu.Sync.Timings).
+func (m *Metrics) FrameDone(end time.Time, dur time.Duration, subsys []emu.SubsystemTime) {
+	m.mu.Lock()
+	idx := m.frames % metricsWindow
+	m.frameTime[idx] = dur
+	m.frameEnd[idx] = end
+	m.frames++
+	m.total += dur
+	m.subsys = subsys
+	m.mu.Unlock()
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snap := MetricsSnapshot{
+		Frames:       m.frames,
+		FrameTime:    make(map[string]float64),
+		FrameTimeSum: m.total.Seconds(),
+		Subsystems:   make(map[string]float64),
+		NumGC:        ms.NumGC,
+		GCPauseTotal: time.Duration(ms.PauseTotalNs).Seconds(),
+		HeapAlloc:    ms.HeapAlloc,
+	}
+	if m.underruns != nil {
+		snap.AudioUnderruns = m.underruns()
+	}
+	for _, sub := range m.subsys {
+		snap.Subsystems[sub.Name] = sub.Elapsed.Seconds()
+	}
+
+	n := int(m.frames)
+	if n > metricsWindow {
+		n = metricsWindow
+	}
+	if n == 0 {
+		return snap
+	}
+
+	times := make([]time.Duration, n)
+	copy(times, m.frameTime[:n])
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, p := range metricsPercentiles {
+		idx := int(p.q*float64(n)+0.5) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		snap.FrameTime[p.name] = times[idx].Seconds()
+	}
+
+	// Average FPS over the last frames
+	nfps := n
+	if nfps > metricsFpsWindow {
+		nfps = metricsFpsWindow
+	}
+	if nfps > 1 {
+		last := m.frameEnd[(m.frames-1)%metricsWindow]
+		first := m.frameEnd[(m.frames-int64(nfps))%metricsWindow]
+		if d := last.Sub(first); d > 0 {
+			snap.FPS = float64(nfps-1) / d.Seconds()
+		}
+	}
+	return snap
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition
+// format.
+func (snap MetricsSnapshot) WritePrometheus(w io.Writer) {
+	metric := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	metric("ndsemu_frames_total", "counter", "Number of emulated frames.")
+	fmt.Fprintf(w, "ndsemu_frames_total %d\n", snap.Frames)
+	metric("ndsemu_fps", "gauge", "Emulated frames per second.")
+	fmt.Fprintf(w, "ndsemu_fps %g\n", snap.FPS)
+
+	metric("ndsemu_frame_time_seconds", "summary", "Host time spent emulating each frame.")
+	for _, p := range metricsPercentiles {
+		if v, ok := snap.FrameTime[p.name]; ok {
+			fmt.Fprintf(w, "ndsemu_frame_time_seconds{quantile=\"%g\"} %g\n", p.q, v)
+		}
+	}
+	fmt.Fprintf(w, "ndsemu_frame_time_seconds_sum %g\n", snap.FrameTimeSum)
+	fmt.Fprintf(w, "ndsemu_frame_time_seconds_count %d\n", snap.Frames)
+
+	metric("ndsemu_subsystem_seconds_total", "counter", "Host time spent running each emulated subsystem.")
+	names := make([]string, 0, len(snap.Subsystems))
+	for name := range snap.Subsystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "ndsemu_subsystem_seconds_total{subsystem=%q} %g\n", name, snap.Subsystems[name])
+	}
+
+	metric("ndsemu_audio_underruns_total", "counter", "Number of times the audio device ran out of emulated audio.")
+	fmt.Fprintf(w, "ndsemu_audio_underruns_total %d\n", snap.AudioUnderruns)
+
+	metric("ndsemu_gc_total", "counter", "Number of completed GC cycles.")
+	fmt.Fprintf(w, "ndsemu_gc_total %d\n", snap.NumGC)
+	metric("ndsemu_gc_pause_seconds_total", "counter", "Total time spent in GC stop-the-world pauses.")
+	fmt.Fprintf(w, "ndsemu_gc_pause_seconds_total %g\n", snap.GCPauseTotal)
+	metric("ndsemu_heap_alloc_bytes", "gauge", "Bytes of allocated heap objects.")
+	fmt.Fprintf(w, "ndsemu_heap_alloc_bytes %d\n", snap.HeapAlloc)
+}
+
+// ServeMetrics starts an HTTP server on the specified address, that exports
+// the metrics both through expvar (at /debug/vars, under "ndsemu", together
+// with the standard Go memory statistics), and in the Prometheus text format
+// (at /metrics).
+func ServeMetrics(addr string, m *Metrics) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	expvar.Publish("ndsemu", expvar.Func(func() interface{} { return m.Snapshot() }))
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.Snapshot().WritePrometheus(w)
+	})
+	go http.Serve(ln, mux)
+	return nil
+}
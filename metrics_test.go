@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"ndsemu/emu"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics(func() int { return 3 })
+	t0 := time.Date(2010, time.January, 1, 12, 0, 0, 0, time.UTC)
+	subsys := []emu.SubsystemTime{
+		{Name: "arm9", Elapsed: 2 * time.Second},
+		{Name: "arm7", Elapsed: time.Second},
+	}
+
+	// 1000 frames at 50 FPS, taking 1..100ms (older frames fall out of the
+	// percentile window)
+	for i := 0; i < 1000; i++ {
+		m.FrameDone(t0.Add(time.Duration(i)*20*time.Millisecond), time.Duration(i%100+1)*time.Millisecond, subsys)
+	}
+
+	snap := m.Snapshot()
+	if snap.Frames != 1000 {
+		t.Errorf("frames: got %d, want 1000", snap.Frames)
+	}
+	if snap.FPS < 49.9 || snap.FPS > 50.1 {
+		t.Errorf("fps: got %v, want 50", snap.FPS)
+	}
+	for name, want := range map[string]float64{"p50": 0.050, "p90": 0.090, "p99": 0.099, "max": 0.100} {
+		if got := snap.FrameTime[name]; got != want {
+			t.Errorf("frame time %s: got %v, want %v", name, got, want)
+		}
+	}
+	if snap.Subsystems["arm9"] != 2 || snap.AudioUnderruns != 3 {
+		t.Errorf("invalid snapshot: %+v", snap)
+	}
+
+	var buf bytes.Buffer
+	snap.WritePrometheus(&buf)
+	for _, line := range []string{
+		"ndsemu_frames_total 1000\n",
+		"ndsemu_frame_time_seconds{quantile=\"0.9\"} 0.09\n",
+		"ndsemu_subsystem_seconds_total{subsystem=\"arm7\"} 1\n",
+		"ndsemu_audio_underruns_total 3\n",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("missing %q in:\n%s", line, buf.String())
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"ndsemu/arm"
 	"ndsemu/emu"
 	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
 )
 
 type NDS7 struct {
@@ -20,6 +21,7 @@ func NewNDS7() *NDS7 {
 	bus.SetWaitStates(0)
 
 	cpu := arm.NewCpu(arm.ARMv4, bus)
+	cpu.EnableCp14()
 
 	nds7 := &NDS7{
 		Cpu: cpu,
@@ -37,6 +39,15 @@ func NewNDS7() *NDS7 {
 }
 
 func (n *NDS7) InitBus(emu *NDSEmulator) {
+	// Unmapped I/O registers read as zero; elsewhere, the ARM7 reads the
+	// value left on the bus by the last opcode prefetch, like the GBA.
+	// Some games accidentally depend on these values.
+	n.Bus.OpenBus = func(addr uint32) uint32 {
+		if addr>>24 == 0x04 {
+			return 0
+		}
+		return n.Cpu.OpenBus()
+	}
 
 	n.Bus.MapMemorySlice(0x00000000, 0x00003FFF, emu.Rom.Bios7, true)
 	n.Bus.MapMemorySlice(0x02000000, 0x02FFFFFF, emu.Mem.Ram[:], false)
@@ -125,6 +136,20 @@ type miscRegs7 struct {
 	Halt7 hwio.Reg8 `hwio:"wcb"`
 }
 
-func (m *miscRegs7) WriteHALT7(_, _ uint8) {
-	nds7.Cpu.SetLine(arm.LineHalt, true)
+// HALTCNT: bits 6-7 select the low-power mode to enter
+func (m *miscRegs7) WriteHALT7(_, val uint8) {
+	switch val >> 6 {
+	case 0:
+		// no function
+	case 1:
+		log.ModEmu.WithField("pc", nds7.Cpu.GetPC()).Warn("GBA mode switch not supported")
+	case 2:
+		nds7.Irq.Halt()
+	case 3:
+		// Sleep mode also stops the clocks, and is exited through the
+		// interrupts enabled in IE (typically keypad, RTC or lid); as
+		// far as the CPU is concerned, it behaves like halt.
+		log.ModEmu.WithField("pc", nds7.Cpu.GetPC()).Info("entering sleep mode")
+		nds7.Irq.Halt()
+	}
 }
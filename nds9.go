@@ -27,7 +27,9 @@ func NewNDS9() *NDS9 {
 	cpu := arm.NewCpu(arm.ARMv5, bus)
 	cp15 := cpu.EnableCp15()
 	cp15.ConfigureTcm(cItcmPhysicalSize, cDtcmPhysicalSize)
+	cp15.ConfigureCaches(8*1024, 4*1024)
 	cp15.ConfigureControlReg(0x2078, 0x00FF085)
+	cpu.EnableCp14()
 
 	nds9 := &NDS9{
 		Cpu:  cpu,
@@ -36,6 +38,7 @@ func NewNDS9() *NDS9 {
 	}
 
 	nds9.Irq = NewHwIrq("irq9", cpu)
+	cp15.SetHaltHook(nds9.Irq.Halt)
 	nds9.Timers = NewHWTimers("t9", nds9.Irq)
 	for i := 0; i < 4; i++ {
 		nds9.Dma[i] = NewHwDmaChannel(CpuNds9, i, nds9.Bus, nds9.Irq)
@@ -47,6 +50,8 @@ func NewNDS9() *NDS9 {
 }
 
 func (n *NDS9) InitBus(emu *NDSEmulator) {
+	// Unmapped regions (including unmapped I/O registers) read as zero on
+	// the ARM9, so Bus.OpenBus is left unset.
 
 	n.Bus.MapMemorySlice(0x02000000, 0x02FFFFFF, emu.Mem.Ram[:], false)
 	n.Bus.MapMemorySlice(0x05000000, 0x05FFFFFF, emu.Mem.PaletteRam[:], false)
@@ -79,6 +84,7 @@ func (n *NDS9) InitBus(emu *NDSEmulator) {
 	n.Bus.MapBank(0x4000300, emu.Hw.Geom, 2)
 	n.Bus.MapBank(0x4000400, emu.Hw.Geom, 0)
 	n.Bus.MapBank(0x4000600, emu.Hw.Geom, 1)
+	n.Bus.MapBank(0x4000600, emu.Hw.E3d, 2)
 	n.Bus.MapBank(0x4001000, emu.Hw.E2d[1], 0)
 
 	n.Bus.MapBank(0x4100000, emu.Hw.Ipc, 1)
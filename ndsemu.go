@@ -4,11 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"ndsemu/arm"
+	"ndsemu/biosdump"
 	"ndsemu/e2d"
 	"ndsemu/emu/gfx"
 	"ndsemu/emu/hw"
 	log "ndsemu/emu/logger"
+	"ndsemu/hlebios"
 	"ndsemu/homebrew"
+	"ndsemu/raster3d"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -35,24 +39,120 @@ const (
 const cFirmwareDefault = "bios/firmware.bin"
 
 var (
-	skipBiosArg  = flag.Bool("s", false, "skip bios and run immediately")
-	debug        = flag.Bool("debug", false, "run with debugger")
-	cpuprofile   = flag.String("cpuprofile", "", "write cpu profile to file")
-	flagLogging  = flag.String("log", "", "enable logging for specified modules")
-	flagVsync    = flag.Bool("vsync", true, "run at normal speed (60 FPS)")
-	flagFirmware = flag.String("firmware", cFirmwareDefault, "specify the firwmare file to use")
-	flagHbrewFat = flag.String("homebrew-fat", "", "FAT image to be mounted for homebrew ROM")
+	skipBiosArg   = flag.Bool("s", false, "skip bios and run immediately")
+	debug         = flag.Bool("debug", false, "run with debugger")
+	cpuprofile    = flag.String("cpuprofile", "", "write cpu profile to file")
+	flagLogging   = flag.String("log", "", "enable logging for specified modules")
+	flagVsync     = flag.Bool("vsync", true, "run at normal speed (60 FPS)")
+	flagFirmware  = flag.String("firmware", cFirmwareDefault, "specify the firwmare file to use")
+	flagHbrewFat  = flag.String("homebrew-fat", "", "FAT image to be mounted for homebrew ROM")
+	flagVCursor   = flag.Bool("vcursor", false, "use the game controller's left stick as a touchscreen cursor")
+	flagVCSpeed   = flag.Float64("vcursor-speed", 4, "virtual cursor speed (pixels per frame)")
+	flagSpeed     = flag.Int("speed", 100, "emulation speed in percent (10-1000)")
+	flagPlayIn    = flag.String("playinput", "", "play back input from the specified script file")
+	flagRecordIn  = flag.String("recordinput", "", "record input into the specified script file")
+	flag3dThreads = flag.Int("3dthreads", default3dThreads(), "number of threads used for 3D rasterization")
+	flag3dRender  = flag.String("3drenderer", "soft", "3D renderer: soft (software rasterizer) or gl (OpenGL ES, requires a build with -tags gl)")
+	flag3dScale   = flag.Int("3dscale", 1, "internal resolution multiplier for the gl 3D renderer (1-8)")
+	flagFillRule  = flag.String("3dfillrule", "nds", "rule for drawing pixels on 3D polygon edges: nds (hardware) or topleft (PC GPUs)")
+	flagReplay3d  = flag.String("replay3d", "", "render a 3D scene dump (saved with F12) into a PNG file, and exit")
+	flagReplayOut = flag.String("replay3d-out", "scene3d.png", "output file for -replay3d")
+	flagDumpTex   = flag.String("dumptex", "", "save decoded 4x4-compressed textures as PNG files into the specified directory, when the frame budget allows it")
+	flagFrames    = flag.Int("frames", 0, "exit after the specified number of frames (0: run forever)")
+	flagFrameHash = flag.String("framehash", "", "write a log of per-frame output/state hashes into the specified file")
+	flagFHPeriod  = flag.Int("framehash-period", 60, "hash the full machine state every N frames (with -framehash)")
+	flagFHWindow  = flag.String("framehash-window", "", "hash the full machine state on every frame within FIRST:LAST (with -framehash)")
+	flagModel     = flag.String("model", "ds", "console model to emulate: ds (original) or lite")
+	flagPresent   = flag.String("present", "auto", "frame presentation: immediate (no vsync), nearest (vsync, repeat/drop frames to match the display refresh rate) or auto")
+	flagRefresh   = flag.Int("refresh", 0, "refresh rate of the display in Hz, for -present nearest (0: detect)")
+	flagEnergy    = flag.Bool("energy-saver", false, "reduce host CPU usage by sleeping longer when ahead of schedule (may add some jitter)")
+	flagVersion   = flag.Bool("version", false, "print version information and exit")
+	flagLogUnmap  = flag.Bool("log-unmapped", true, "log accesses to unmapped memory and I/O registers")
+	flagBusAbort  = flag.Bool("abort-unmapped", false, "raise a data abort on CPU accesses to unmapped memory (for debugging: the real bus never aborts)")
+	flagUpdate    = flag.Bool("check-update", false, "check online whether a newer release is available")
+	flagSwapLR    = flag.Bool("audio-swap", false, "swap left and right audio channels")
+	flagMono      = flag.Bool("audio-mono", false, "downmix audio output to mono")
+	flagWidth     = flag.Int("audio-width", 100, "stereo separation in percent (0: mono, 100: normal, max 200)")
+	flagWatchdog  = flag.Int("watchdog", 120, "report when both CPUs stay halted with no wakeup source for N frames (0: disable)")
+	flagWdBreak   = flag.Bool("watchdog-break", false, "break into the debugger (or abort, without -debug) when the watchdog triggers")
+	flagTouchMin  = flag.Int("touch-min-press", 0, "minimum length of a touchscreen press, in frames (extends short taps)")
+	flagTouchRel  = flag.Int("touch-release", 0, "frames the pen must stay up before the touchscreen release is reported (ignores short lifts while dragging)")
+	flagTouchAvg  = flag.Int("touch-average", 0, "smooth the touchscreen position with a moving average over N frames (max 8)")
+	flagGameCfg   = flag.String("gamecfg", "games.json", "per-game settings database (JSON), applied to the flags not set on the command line")
+	flagGameSave  = flag.Bool("gamecfg-save", false, "save the current graphics settings (-3drenderer, -3dscale, -3dfillrule) for this game into the database")
+	flagCpu       = flag.String("cpu", "interp", "ARM opcode dispatch: interp (decode each opcode when executed) or cached (keep a cache of decoded opcodes)")
+	flagHleBios   = flag.String("hlebios", "none", "emulate the BIOS calls (SWIs) of the specified CPUs: none, arm9, arm7 or both (with -s, missing BIOS dumps are not required)")
+	flagControl   = flag.String("control", "", "listen for control commands (eg: JSON register snapshots) on the specified TCP address")
+	flagMetrics   = flag.String("metrics", "", "export performance metrics over HTTP on the specified address (expvar at /debug/vars, Prometheus at /metrics)")
 
 	nds7     *NDS7
 	nds9     *NDS9
 	KeyState = make([]uint8, 256)
 )
 
+// Default number of 3D rasterization threads. Two cores are left to the
+// emulation goroutine and the 2D engines, and more than 4 threads don't pay
+// off: the bands of scanlines become too small compared to the cost of
+// dispatching them.
+func default3dThreads() int {
+	n := runtime.NumCPU() - 2
+	if n > 4 {
+		n = 4
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
 func main() {
 	// Required by go-sdl2, to be run at the beginning of main
 	runtime.LockOSThread()
 
 	flag.Parse()
+	if *flagVersion {
+		fmt.Println(VersionString())
+		return
+	}
+	if flag.Arg(0) == "analyze" {
+		if err := runAnalyze(flag.Args()[1:]); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+		return
+	}
+
+	// Apply the stored settings of the game, if any. Relative paths are
+	// looked up in the working directory (eg: with "go run"), and then next
+	// to the executable, as the firmware; that's also where a new database
+	// is created.
+	var gameNote string
+	if *flagGameCfg != "" && *flagReplay3d == "" && flag.NArg() > 0 {
+		cwd, _ := os.Getwd()
+		bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+		*flagGameCfg = gameConfigPath(*flagGameCfg, cwd, bindir)
+		var err error
+		gameNote, err = applyGameSettings(flag.CommandLine, *flagGameCfg, flag.Arg(0), *flagGameSave)
+		if err != nil {
+			log.ModEmu.Warnf("game settings not applied: %v", err)
+		}
+	}
+
+	var fillRule raster3d.FillRule
+	switch *flagFillRule {
+	case "nds":
+		fillRule = raster3d.FillRuleNDS
+	case "topleft":
+		fillRule = raster3d.FillRuleTopLeft
+	default:
+		log.ModEmu.Fatal("invalid 3D fill rule:", *flagFillRule)
+	}
+
+	if *flagReplay3d != "" {
+		if err := Replay3D(*flagReplay3d, *flagReplayOut, *flag3dThreads, *flag3dRender, *flag3dScale, fillRule); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+		return
+	}
 	if len(flag.Args()) < 1 {
 		fmt.Println("game card file is required")
 		return
@@ -65,17 +165,18 @@ func main() {
 		*flagFirmware = filepath.Join(bindir, *flagFirmware)
 	}
 
-	if _, err := os.Stat(*flagFirmware); err != nil {
+	fw, err := ioutil.ReadFile(*flagFirmware)
+	if err != nil {
 		log.ModEmu.Fatal("cannot open firmware:", err)
 	}
+	if biosdump.IsCombined(int64(len(fw))) {
+		log.ModEmu.Fatal("firmware file is a combined BIOS/firmware dump; split it with: verify-bios -split DIR ", *flagFirmware)
+	}
+	checkDumps(biosdump.CheckFirmware(fw))
 
 	firstboot := false
 	fwsav := *flagFirmware + ".sav"
 	if _, err := os.Stat(fwsav); err != nil {
-		fw, err := ioutil.ReadFile(*flagFirmware)
-		if err != nil {
-			log.ModEmu.Fatal("cannot load firwmare:", err)
-		}
 		err = ioutil.WriteFile(fwsav, fw, 0777)
 		if err != nil {
 			log.ModEmu.Fatal("cannot save firwmare:", err)
@@ -83,7 +184,66 @@ func main() {
 		firstboot = true
 	}
 
+	var hle9, hle7 bool
+	switch *flagHleBios {
+	case "none":
+	case "arm9":
+		hle9 = true
+	case "arm7":
+		hle7 = true
+	case "both":
+		hle9, hle7 = true, true
+	default:
+		log.ModEmu.Fatal("invalid HLE BIOS selection:", *flagHleBios)
+	}
+	biosStub9 = hle9 && *skipBiosArg
+	biosStub7 = hle7 && *skipBiosArg
+
 	Emu = NewNDSEmulator(fwsav)
+	if hle9 {
+		hlebios.Install9(nds9.Cpu, nds9.Cp15, hlebios.IsStub(Emu.Rom.Bios9))
+	}
+	if hle7 {
+		hlebios.Install7(nds7.Cpu, hlebios.IsStub(Emu.Rom.Bios7))
+	}
+	nds9.Bus.LogUnmapped = *flagLogUnmap
+	nds7.Bus.LogUnmapped = *flagLogUnmap
+	if *flagBusAbort {
+		nds9.Bus.Abort = nds9.Cpu.BusError
+		nds7.Bus.Abort = nds7.Cpu.BusError
+	}
+	switch *flagModel {
+	case "ds":
+		Emu.SetConsoleModel(ModelDS)
+	case "lite":
+		Emu.SetConsoleModel(ModelDSLite)
+	default:
+		log.ModEmu.Fatal("invalid console model:", *flagModel)
+	}
+	switch *flagCpu {
+	case "interp":
+	case "cached":
+		nds9.Cpu.SetDispatch(arm.DispatchCached)
+		nds7.Cpu.SetDispatch(arm.DispatchCached)
+	default:
+		log.ModEmu.Fatal("invalid CPU dispatch method:", *flagCpu)
+	}
+	Emu.Hw.E3d.SetThreads(*flag3dThreads)
+	Emu.Hw.E3d.SetFillRule(fillRule)
+	if *flagDumpTex != "" {
+		Emu.Hw.E3d.DumpTextures(*flagDumpTex, &Emu.Sync.Budget)
+	}
+	if err := Emu.Hw.E3d.SetRenderer(*flag3dRender, *flag3dScale); err != nil {
+		log.ModEmu.Fatal(err)
+	}
+	if *flagWidth < 0 || *flagWidth > 200 {
+		log.ModEmu.Fatal("invalid stereo width:", *flagWidth)
+	}
+	Emu.Hw.Snd.Stereo = StereoConfig{Swap: *flagSwapLR, Width: *flagWidth}
+	Emu.Hw.Tsc.Filter = TouchFilter{MinPress: *flagTouchMin, Release: *flagTouchRel, Average: *flagTouchAvg}
+	if *flagMono {
+		Emu.Hw.Snd.Stereo.Width = 0
+	}
 
 	// Check if the NDS ROM is homebrew. If so, directly load it into slot2
 	// like PassMe does.
@@ -204,44 +364,20 @@ func main() {
 	}()
 
 	if *skipBiosArg {
-		if err := InjectGamecard(Emu.Hw.Gc, Emu.Mem); err != nil {
+		if err := Emu.SkipBoot(); err != nil {
 			fmt.Println(err)
 			return
 		}
-
-		// Shared wram: map everything to ARM7
-		Emu.Hw.Mc.WramCnt.Write8(0, 3)
-
-		// Set post-boot flag to 1
-		nds9.misc.PostFlg.Value = 1
-		nds7.misc.PostFlg.Value = 1
-
-		nds9.Irq.Ime.Value = 0x1
-		nds7.Irq.Ime.Value = 0x1
-		nds9.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
-		nds7.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
-
-		// VRAM: map everything in "LCDC mode"
-		Emu.Hw.Mc.VramCntA.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntB.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntC.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntD.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntE.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntF.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntG.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntH.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntI.Write8(0, 0x80)
-
-		// Gamecard: skip directly to key2 status
-		Emu.Hw.Gc.stat = gcStatusKey2
-
-		nds9.Cp15.ConfigureControlReg(0x52078, 0x00FF085)
 	}
 
 	if *debug {
 		Emu.StartDebugger()
 	}
 
+	if *flagWatchdog > 0 {
+		Emu.Watchdog = &Watchdog{Frames: *flagWatchdog, Break: *flagWdBreak}
+	}
+
 	if *cpuprofile != "" {
 		f, err := os.Create(*cpuprofile)
 		if err != nil {
@@ -265,8 +401,17 @@ func main() {
 		log.EnableDebugModules(modmask)
 	}
 
+	log.ModEmu.Warnf("%s (%s, %s/%s)", VersionString(), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if *flagUpdate {
+		go CheckUpdate()
+	}
+
+	present, err := hw.ParsePresentPolicy(*flagPresent)
+	if err != nil {
+		log.ModEmu.Fatal(err)
+	}
 	hwout := hw.NewOutput(hw.OutputConfig{
-		Title:             "NDSEmu - Nintendo DS Emulator",
+		Title:             "NDSEmu " + Version + " - Nintendo DS Emulator",
 		Width:             256,
 		Height:            192 + 90 + 192,
 		FramePerSecond:    60,
@@ -274,9 +419,16 @@ func main() {
 		AudioFrequency:    cAudioFreq,
 		AudioChannels:     2,
 		AudioSampleSigned: true,
+		Present:           present,
+		RefreshRate:       *flagRefresh,
 	})
 	hwout.EnableVideo(true)
 	hwout.EnableAudio(true)
+	hwout.SetSpeed(*flagSpeed)
+	hwout.SetEnergySaver(*flagEnergy)
+	if gameNote != "" {
+		hwout.ShowMessage(gameNote, 5*time.Second)
+	}
 
 	var fprof *os.File
 	profiling := 0
@@ -301,6 +453,16 @@ func main() {
 	// fully hide the double-buffering logic within hw.BeginFrame/hw.EndFrame.
 	framein := make(chan frame, 1)
 	frameout := make(chan frame, 1)
+
+	var metrics *Metrics
+	if *flagMetrics != "" {
+		metrics = NewMetrics(hwout.AudioUnderruns)
+		if err := ServeMetrics(*flagMetrics, metrics); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+		Emu.Sync.SetProfiling(true)
+	}
+
 	go func() {
 		for {
 			frame := <-framein
@@ -310,7 +472,11 @@ func main() {
 				tracing = Emu.framecount
 			}
 
+			t0 := time.Now()
 			Emu.RunOneFrame(frame.screen, ([]int16)(frame.audio))
+			if metrics != nil {
+				metrics.FrameDone(time.Now(), time.Since(t0), Emu.Sync.Timings())
+			}
 
 			if tracing > 0 { //&& tracing < Emu.framecount-1 {
 				trace.Stop()
@@ -324,17 +490,103 @@ func main() {
 		}
 	}()
 
+	var vcursor *VirtualCursor
+	if *flagVCursor {
+		if pad := hw.OpenPad(); pad != nil {
+			vcursor = NewVirtualCursor(pad, *flagVCSpeed)
+		} else {
+			log.ModEmu.Warnf("no game controller found, virtual cursor disabled")
+		}
+	}
+
+	var inplay *InputPlayer
+	if *flagPlayIn != "" {
+		var err error
+		if inplay, err = LoadInputScript(*flagPlayIn); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+	}
+
+	var inrec *InputRecorder
+	if *flagRecordIn != "" {
+		var err error
+		if inrec, err = NewInputRecorder(*flagRecordIn); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+		defer inrec.Close()
+	}
+
+	var fhash *FrameHasher
+	if *flagFrameHash != "" {
+		win0, win1 := 0, -1
+		if *flagFHWindow != "" {
+			if _, err := fmt.Sscanf(*flagFHWindow, "%d:%d", &win0, &win1); err != nil {
+				log.ModEmu.Fatal("invalid frame hash window:", *flagFHWindow)
+			}
+		}
+		var err error
+		if fhash, err = NewFrameHasher(*flagFrameHash, *flagFHPeriod, win0, win1); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+		defer fhash.Close()
+
+		// Runs must be reproducible to be compared: use an emulated clock
+		// for the RTC, starting from a fixed date.
+		t0 := time.Date(2010, time.January, 1, 12, 0, 0, 0, time.UTC)
+		Emu.Hw.Rtc.SetClock(func() time.Time {
+			return t0.Add(time.Duration(Emu.framecount) * time.Second / 60)
+		})
+	}
+
+	var control *ControlServer
+	if *flagControl != "" {
+		var err error
+		if control, err = NewControlServer(*flagControl); err != nil {
+			log.ModEmu.Fatal(err)
+		}
+		defer control.Close()
+	}
+
+	// Host time available to emulate each frame at the current speed,
+	// exposed to subsystems through Emu.Sync.Budget
+	framePeriod := func() time.Duration {
+		fps := Emu.Sync.Fps().ToFloat64()
+		return time.Duration(float64(time.Second) / fps * 100 / float64(hwout.Speed()))
+	}
+
 	v, a := hwout.BeginFrame()
+	Emu.Sync.Budget.Start(framePeriod())
 	framein <- frame{v, a}
 
+	// Speed presets selectable at runtime with -/= (backspace resets
+	// to normal speed)
+	speeds := []int{10, 25, 50, 75, 100, 150, 200, 300, 500, 1000}
+	speedKey := false
+	paused, pauseKey := false, false
+	sceneKey, rendKey, dumpKey := false, false, false
+	layerKeys := [2]uint32{}
+	ejectKeys := [2]bool{}
+
 	KeyState = hw.GetKeyboardState()
-	for {
+	for nframe := 0; ; {
 		if !hwout.Poll() {
 			break
 		}
-		if KeyState[hw.SCANCODE_P] != 0 {
-			time.Sleep(1 * time.Second)
+
+		// P toggles pause. While paused, keep polling events (so that the
+		// window stays responsive), but don't emulate; audio is faded out.
+		if p := KeyState[hw.SCANCODE_P] != 0; p != pauseKey {
+			if p {
+				paused = !paused
+				hwout.SetPaused(paused)
+			}
+			pauseKey = p
 		}
+		if paused {
+			time.Sleep(16 * time.Millisecond)
+			continue
+		}
+
 		if KeyState[hw.SCANCODE_L] != 0 && profiling == 0 {
 			fprof, _ = os.Create("profile.dump")
 			pprof.StartCPUProfile(fprof)
@@ -348,18 +600,142 @@ func main() {
 			log.ModEmu.Warnf("profile dumped")
 		}
 
+		// F12 saves the next 3D scene, to be replayed with -replay3d
+		if k := KeyState[hw.SCANCODE_F12] != 0; k != sceneKey {
+			if k {
+				Emu.Hw.E3d.RecordNextScene(fmt.Sprintf("scene3d-%06d.bin", Emu.framecount))
+			}
+			sceneKey = k
+		}
+
+		// F8 saves the layers of the next frame of both engines as PNG files
+		if k := KeyState[hw.SCANCODE_F8] != 0; k != dumpKey {
+			if k {
+				Emu.DumpNextFrame()
+			}
+			dumpKey = k
+		}
+
+		// F11 cycles through the 3D renderers; the switch happens at the
+		// next frame, to compare the backends on the same scene
+		if k := KeyState[hw.SCANCODE_F11] != 0; k != rendKey {
+			if k {
+				Emu.Hw.E3d.SwitchRenderer("")
+			}
+			rendKey = k
+		}
+
+		// Holding 1-4 hides BG0-BG3 on both engines, and 9/8 hide all the
+		// BG layers of engine A/B. The masks are only updated when the keys
+		// change, so that they don't override those set through the control
+		// socket.
+		var hide [2]uint32
+		for i := 0; i < 4; i++ {
+			if KeyState[hw.SCANCODE_1+i] != 0 {
+				hide[0] |= e2d.DebugLayerBG0 << uint(i)
+				hide[1] |= e2d.DebugLayerBG0 << uint(i)
+			}
+		}
+		if KeyState[hw.SCANCODE_9] != 0 {
+			hide[0] |= e2d.DebugLayerBGs
+		}
+		if KeyState[hw.SCANCODE_8] != 0 {
+			hide[1] |= e2d.DebugLayerBGs
+		}
+		if hide != layerKeys {
+			for i := range hide {
+				Emu.Hw.E2d[i].SetDebugLayerMask(e2d.DebugLayerAll &^ hide[i])
+			}
+			layerKeys = hide
+		}
+
+		dec := KeyState[hw.SCANCODE_MINUS] != 0
+		inc := KeyState[hw.SCANCODE_EQUALS] != 0
+		reset := KeyState[hw.SCANCODE_BACKSPACE] != 0
+		if (dec || inc || reset) && !speedKey {
+			speed := hwout.Speed()
+			if reset {
+				speed = 100
+			} else if inc {
+				for _, s := range speeds {
+					if s > speed {
+						speed = s
+						break
+					}
+				}
+			} else {
+				for i := len(speeds) - 1; i >= 0; i-- {
+					if speeds[i] < speed {
+						speed = speeds[i]
+						break
+					}
+				}
+			}
+			hwout.SetSpeed(speed)
+			log.ModEmu.Infof("emulation speed: %d%%", speed)
+		}
+		speedKey = dec || inc || reset
+
 		x, y, btn := hwout.GetMouseState()
 		y -= 192 + 90
 		pendown := btn&hw.MouseButtonLeft != 0
-		Emu.Hw.Key.SetPenDown(pendown)
+		if vcursor != nil {
+			if vdown, vx, vy := vcursor.Update(); vdown || !pendown {
+				pendown, x, y = vdown, vx, vy
+			}
+		}
+		if inplay != nil {
+			in := inplay.Update(nframe)
+			Emu.Hw.Key.SetButtons(in.Buttons)
+			if in.PenDown {
+				pendown, x, y = true, in.PenX, in.PenY
+			}
+		}
+		if inrec != nil {
+			in := InputState{Buttons: Emu.Hw.Key.Buttons(), PenDown: pendown}
+			if pendown {
+				in.PenX, in.PenY = x, y
+			}
+			inrec.Record(nframe, in)
+		}
 		Emu.Hw.Tsc.SetPen(pendown, x, y)
+		Emu.Hw.Key.SetPenDown(Emu.Hw.Tsc.PenDown())
 
 		// Wait until the current frame is fully drawn. Then start immediately
 		// emulating next frame (by sending the new screen buffer to the emulation
 		// goroutine), and present the current frame to the screen
 		cframe := <-frameout
+		if fhash != nil {
+			fhash.Hash(nframe, cframe.screen, cframe.audio)
+		}
+		if control != nil {
+			control.Poll()
+		}
+
+		// F9/F10 simulate the removal of the card in slot 1 / slot 2. This
+		// is done between frames, while the emulation goroutine is idle.
+		for i, sc := range []int{hw.SCANCODE_F9, hw.SCANCODE_F10} {
+			if k := KeyState[sc] != 0; k != ejectKeys[i] {
+				if k && i == 0 {
+					Emu.Hw.Gc.Eject()
+					log.ModEmu.Warnf("slot 1 card removed")
+				} else if k {
+					Emu.Hw.Sl2.Eject()
+					log.ModEmu.Warnf("slot 2 cartridge removed")
+				}
+				ejectKeys[i] = k
+			}
+		}
 		v, a := hwout.BeginFrame()
+		Emu.Sync.Budget.Start(framePeriod())
 		framein <- frame{v, a}
+		if vcursor != nil {
+			vcursor.Draw(cframe.screen)
+		}
 		hwout.EndFrame(cframe.screen, cframe.audio)
+		nframe++
+		if *flagFrames > 0 && nframe >= *flagFrames {
+			break
+		}
 	}
 }
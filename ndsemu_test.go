@@ -27,7 +27,7 @@ func BenchmarkCpuSpeed(b *testing.B) {
 		Emu.Hw.Rtc.ResetDefaults()
 
 		for j := 0; j < 300; j++ {
-			Emu.RunOneFrame(screen)
+			Emu.RunOneFrame(screen, nil)
 		}
 	}
 }
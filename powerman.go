@@ -9,13 +9,32 @@ import (
 var modPower = log.NewModule("powerman")
 
 type HwPowerMan struct {
-	cntrl uint8
+	cntrl     uint8
+	backlight uint8 // DS Lite only: backlight levels
+	lite      bool
 }
 
 func NewHwPowerMan() *HwPowerMan {
 	return &HwPowerMan{}
 }
 
+// SetModel configures the console model being emulated. The original DS
+// has only registers 0-3 (mirrored at 4-7), while the DS Lite adds the
+// backlight levels register at index 4, which is also used by software to
+// tell the two models apart.
+func (ff *HwPowerMan) SetModel(model ConsoleModel) {
+	ff.lite = model == ModelDSLite
+}
+
+// Return the register being accessed, handling mirroring
+func (ff *HwPowerMan) regIndex(index uint8) uint8 {
+	index &= 0x7F
+	if !ff.lite {
+		index &= 3
+	}
+	return index
+}
+
 func (ff *HwPowerMan) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 	index := data[0]
 	if index&0x80 == 0 {
@@ -24,19 +43,27 @@ func (ff *HwPowerMan) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 			return nil, spi.ReqContinue
 		}
 		val := data[1]
-		switch index & 0x7F {
+		switch ff.regIndex(index) {
 		case 0:
 			ff.cntrl = val
 			modPower.Infof("write control: %02x", data)
+		case 4:
+			// Bits 0-1: brightness level, bit 2: force max brightness
+			// with external power. Bit 3 (external power) is read-only.
+			ff.backlight = val & 7
+			modPower.Infof("write backlight: %02x", val)
 		default:
 			modPower.Infof("write reg %d: %02x", index&0x7F, val)
 		}
 		return nil, spi.ReqFinish
 	} else {
 		// Read reg
-		switch index & 0x7F {
+		switch ff.regIndex(index) {
 		case 0:
 			return []byte{ff.cntrl}, spi.ReqFinish
+		case 4:
+			// Bit 6 is always set (no external power connected)
+			return []byte{ff.backlight | 0x40}, spi.ReqFinish
 		default:
 			modPower.Infof("read reg %d", index&0x7F)
 			return nil, spi.ReqFinish
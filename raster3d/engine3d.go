@@ -10,12 +10,36 @@ import (
 	"os"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 var mod3d = log.NewModule("e3d")
 
 const (
 	kFarClipping = 512
+
+	// Capacity of polygon RAM and vertex RAM
+	kMaxPolygons = 2048
+	kMaxVertices = 6144
+)
+
+// Bits of DISP3DCNT
+type disp3dCnt uint32
+
+func (c disp3dCnt) TexMapping() bool      { return c&(1<<0) != 0 }
+func (c disp3dCnt) Highlight() bool       { return c&(1<<1) != 0 } // toon polygons use highlight shading
+func (c disp3dCnt) AlphaTest() bool       { return c&(1<<2) != 0 }
+func (c disp3dCnt) AlphaBlending() bool   { return c&(1<<3) != 0 }
+func (c disp3dCnt) AntiAliasing() bool    { return c&(1<<4) != 0 }
+func (c disp3dCnt) EdgeMarking() bool     { return c&(1<<5) != 0 }
+func (c disp3dCnt) FogAlphaOnly() bool    { return c&(1<<6) != 0 }
+func (c disp3dCnt) Fog() bool             { return c&(1<<7) != 0 }
+func (c disp3dCnt) FogShift() uint        { return uint(c>>8) & 0xF }
+func (c disp3dCnt) RearPlaneBitmap() bool { return c&(1<<14) != 0 }
+
+const (
+	disp3dCntLineUnderflow = 1 << 12 // color buffer underflow (ack)
+	disp3dCntRamOverflow   = 1 << 13 // polygon/vertex RAM overflow (ack)
 )
 
 type buffer3d struct {
@@ -29,13 +53,20 @@ func (b *buffer3d) Reset() {
 }
 
 type HwEngine3d struct {
-	Disp3dCnt hwio.Reg32 `hwio:"offset=0,rwmask=0x7FFF"`
-	ToonTable hwio.Mem   `hwio:"bank=1,offset=0x80,size=0x40,writeonly"`
+	Disp3dCnt    hwio.Reg32 `hwio:"offset=0,rwmask=0x7FFF,rcb,wcb"`
+	ToonTable    hwio.Mem   `hwio:"bank=1,offset=0x80,size=0x40,writeonly"`
+	AlphaTestRef hwio.Reg16 `hwio:"bank=1,offset=0x40,rwmask=0x1F,writeonly"`
+
+	Disp1DotDepth hwio.Reg16 `hwio:"bank=2,offset=0x10,reset=0x7FFF,rwmask=0x7FFF,writeonly"`
 
 	// Channel to receive new primitives (sent by GxFifo)
 	CmdCh chan interface{}
 
-	// Current viewport (last received viewport command)
+	// Current viewport (last received viewport command). Vertices are
+	// transformed into screen space as soon as a polygon using them is
+	// received, so a viewport change mid-frame applies to all the following
+	// polygons, in command order. Vertices shared with previous polygons
+	// (eg: strips) keep their existing screen coordinates, like on hardware.
 	viewport Primitive_SetViewport
 
 	pool sync.Pool
@@ -46,6 +77,28 @@ type HwEngine3d struct {
 	// Next vram/pram (being accumulated for next frame)
 	next buffer3d
 
+	// Number of polygons/vertices stored in next vram/pram, as they would be
+	// counted by the hardware (RAM_COUNT). They are accessed atomically as
+	// they're read by the geometry engine in a different goroutine.
+	numPolys int32
+	numVerts int32
+
+	// Set (atomically) when polygons were dropped because polygon or vertex
+	// RAM was full. Reported in DISP3DCNT until acknowledged.
+	ramOverflow int32
+
+	// DISP3DCNT, as latched at the beginning of the frame being drawn
+	cnt disp3dCnt
+
+	// Alpha test reference value for the frame being drawn: pixels with an
+	// alpha less or equal than this are discarded (-1 if alpha test is
+	// disabled).
+	alphaRef int
+
+	// Rendering features requested by the game that are not emulated
+	// (bitmask of DISP3DCNT bits), to warn only once.
+	unsupported disp3dCnt
+
 	nextCh chan buffer3d
 
 	// Texture/palette VRAM
@@ -57,6 +110,33 @@ type HwEngine3d struct {
 	// the compressed format.
 	texCache texCache
 
+	// Multi-threaded rasterization: number of worker goroutines, per-worker
+	// polygon copies, and per-line output buffers with their ready status.
+	threads   int
+	bandPolys [][]Polygon
+	lineBuf   [192][256 * 4]byte
+	lineReady [192]bool
+	lineMtx   sync.Mutex
+	lineCond  *sync.Cond
+
+	// Rule for drawing pixels on polygon edges (software rasterizer)
+	fillRule FillRule
+
+	// Polygon classes hidden for debugging (atomic, see SetDebugLayerMask)
+	dbgHidden uint32
+
+	// Alternative rendering backend (nil: software rasterizer), and the
+	// pending runtime switch (see SwitchRenderer)
+	renderer      renderer
+	rendererName  string
+	rendererScale int
+	switchCh      chan string
+
+	// Scene recording (see RecordNextScene)
+	recCh    chan string
+	recFile  string
+	recScene *Scene
+
 	framecnt int
 }
 
@@ -65,6 +145,9 @@ func NewHwEngine3d() *HwEngine3d {
 	hwio.MustInitRegs(e3d)
 
 	e3d.CmdCh = make(chan interface{}, 4096)
+	e3d.lineCond = sync.NewCond(&e3d.lineMtx)
+	e3d.recCh = make(chan string, 1)
+	e3d.switchCh = make(chan string, 1)
 
 	e3d.pool.New = func() interface{} {
 		return buffer3d{
@@ -79,9 +162,29 @@ func NewHwEngine3d() *HwEngine3d {
 	return e3d
 }
 
+func (e3d *HwEngine3d) ReadDISP3DCNT(val uint32) uint32 {
+	if atomic.LoadInt32(&e3d.ramOverflow) != 0 {
+		val |= disp3dCntRamOverflow
+	}
+	return val
+}
+
+func (e3d *HwEngine3d) WriteDISP3DCNT(old, val uint32) {
+	// Bits 12-13 are status flags, that are acknowledged by writing 1.
+	// The color buffer can never underflow in our renderer, while the RAM
+	// overflow flag is kept separately as it's set by another goroutine.
+	e3d.Disp3dCnt.Value &^= disp3dCntLineUnderflow | disp3dCntRamOverflow
+	if val&disp3dCntRamOverflow != 0 {
+		atomic.StoreInt32(&e3d.ramOverflow, 0)
+	}
+}
+
 func (e3d *HwEngine3d) recvCmd() {
 	for {
 		cmdi := <-e3d.CmdCh
+		if e3d.recScene != nil {
+			e3d.recScene.Cmds = append(e3d.recScene.Cmds, cmdi)
+		}
 		switch cmd := cmdi.(type) {
 		case Primitive_SwapBuffers:
 			e3d.cmdSwapBuffers(cmd)
@@ -172,22 +275,28 @@ func (e3d *HwEngine3d) cmdPolygon(cmd Primitive_Polygon) {
 		return
 	}
 
+	// Polygons crossing the far plane are hidden, unless the polygon
+	// attributes ask to clip them.
+	if clipany&RVFClipFar != 0 && !flags.FarPlaneRender() {
+		return
+	}
+
 	// Transform all vertices (that weren't transformed already)
 	for _, vtx := range vtxs {
 		e3d.vtxTransform(vtx)
 	}
 
-	// Do backface culling
-	d0x := vtxs[0].x.SubFixed(vtxs[1].x)
-	d0y := vtxs[0].y.SubFixed(vtxs[1].y)
-	d1x := vtxs[2].x.SubFixed(vtxs[1].x)
-	d1y := vtxs[2].y.SubFixed(vtxs[1].y)
-	if int64(d0x.V)*int64(d1y.V) <= int64(d1x.V)*int64(d0y.V) {
+	// Do backface culling. Polygons with no area on screen (all vertices
+	// aligned) are drawn as lines, whatever their facing flags. The facing
+	// is computed on the whole polygon, as the first three vertices of a
+	// quad might be aligned.
+	switch dot := -polyArea(vtxs); {
+	case dot < 0:
 		// Facing the back: see if we must render the back
 		if flags&PFRenderBack == 0 {
 			return
 		}
-	} else {
+	case dot > 0:
 		// Facing the front: see if we must render the front
 		if flags&PFRenderFront == 0 {
 			return
@@ -212,17 +321,117 @@ func (e3d *HwEngine3d) cmdPolygon(cmd Primitive_Polygon) {
 		e3d.vtxTransform(vtx)
 	}
 
-	// Split the clipped polygon into triangles and add them to pram
+	// Polygons that collapse to a single dot on screen are discarded if they
+	// are farther than DISP_1DOT_DEPTH, unless the polygon attributes
+	// explicitly ask to render them.
+	if flags&PFRender1Dot == 0 && e3d.isFar1Dot(vtxs) {
+		return
+	}
+
+	// The polygon is going to be stored in polygon RAM: update the counters.
+	// Vertices shared with previous polygons (eg: strips) are stored only
+	// once, while vertices generated by clipping are always new.
+	nv := int32(0)
+	for _, vtx := range vtxs {
+		if vtx.flags&RVFInRam == 0 {
+			nv++
+		}
+	}
+	// If there's no more space in polygon or vertex RAM, the polygon is
+	// dropped, and the overflow is reported in DISP3DCNT.
+	if atomic.LoadInt32(&e3d.numPolys) >= kMaxPolygons || atomic.LoadInt32(&e3d.numVerts)+nv > kMaxVertices {
+		atomic.StoreInt32(&e3d.ramOverflow, 1)
+		return
+	}
+	top, bottom := vtxs[0].y.TruncInt32(), vtxs[0].y.TruncInt32()
+	for _, vtx := range vtxs {
+		vtx.flags |= RVFInRam
+		if y := vtx.y.TruncInt32(); y < top {
+			top = y
+		} else if y > bottom {
+			bottom = y
+		}
+	}
+	atomic.AddInt32(&e3d.numPolys, 1)
+	atomic.AddInt32(&e3d.numVerts, nv)
+
+	// Split the clipped polygon into triangles and add them to pram.
+	// Remember which edges were created by the split, as they must not
+	// be drawn in wireframe mode.
+	//
+	// Degeneracy is decided once for the whole polygon: if it's a line, all
+	// the triangles are drawn as lines. Otherwise, triangles with no area
+	// are dropped: their edges lie on the diagonals shared with the
+	// adjacent triangles, which thus become outer edges.
+	line := polyArea(vtxs) == 0
+	skipped := false
 	for i := 1; i < len(vtxs)-1; i++ {
+		if !line && triArea(vtxs[0], vtxs[i], vtxs[i+1]) == 0 {
+			if i > 1 && !skipped {
+				e3d.next.Pram[len(e3d.next.Pram)-1].inner[1] = [2]*Vertex{}
+			}
+			skipped = true
+			continue
+		}
 		poly := Polygon{
 			flags: flags,
 			tex:   cmd.Tex,
 			vtx: [3]*Vertex{
 				vtxs[0], vtxs[i], vtxs[i+1],
 			},
+			sortTop:    top,
+			sortBottom: bottom,
+			line:       line,
+		}
+		if i > 1 && !skipped {
+			poly.inner[0] = [2]*Vertex{vtxs[0], vtxs[i]}
+		}
+		if i < len(vtxs)-2 {
+			poly.inner[1] = [2]*Vertex{vtxs[0], vtxs[i+1]}
 		}
 		e3d.next.Pram = append(e3d.next.Pram, poly)
+		skipped = false
+	}
+}
+
+// Twice the signed area of a triangle on screen
+func triArea(v0, v1, v2 *Vertex) int64 {
+	ax, ay := int64(v1.x.V-v0.x.V), int64(v1.y.V-v0.y.V)
+	bx, by := int64(v2.x.V-v0.x.V), int64(v2.y.V-v0.y.V)
+	return ax*by - bx*ay
+}
+
+// Twice the signed area of a polygon on screen (zero if all its vertices
+// are aligned). Polygons are always convex, so it's the sum of the areas of
+// the triangles of a fan.
+func polyArea(vtxs []*Vertex) int64 {
+	var area int64
+	for i := 1; i < len(vtxs)-1; i++ {
+		area += triArea(vtxs[0], vtxs[i], vtxs[i+1])
 	}
+	return area
+}
+
+// Check whether a polygon is a 1-dot polygon (all its vertices map to the same
+// screen pixel) placed beyond the depth configured in DISP_1DOT_DEPTH.
+func (e3d *HwEngine3d) isFar1Dot(vtxs []*Vertex) bool {
+	x, y := vtxs[0].x.TruncInt32(), vtxs[0].y.TruncInt32()
+	for _, v := range vtxs[1:] {
+		if v.x.TruncInt32() != x || v.y.TruncInt32() != y {
+			return false
+		}
+	}
+
+	// The register holds an unsigned W coordinate with 3 fractional bits;
+	// the comparison is always performed against W, irrespective of
+	// Z/W buffering.
+	depth := emu.Fixed12{V: int32(e3d.Disp1DotDepth.Value&0x7FFF) << 9}
+	for _, v := range vtxs {
+		if v.cw.V <= depth.V {
+			return false
+		}
+	}
+	return true
 }
 
 func (v0 *Vertex) Lerp(v1 *Vertex, ratio emu.Fixed12) *Vertex {
@@ -356,27 +565,37 @@ func (e3d *HwEngine3d) vtxTransform(vtx *Vertex) {
 		return
 	}
 
-	viewwidth := emu.NewFixed12(int32(e3d.viewport.VX1 - e3d.viewport.VX0))
-	viewheight := emu.NewFixed12(int32(e3d.viewport.VY1 - e3d.viewport.VY0))
+	x0, top, width, height := e3d.viewport.ScreenRect()
 	// Compute viewsize / (2*v.w) in two steps, to avoid overflows
 	// (viewwidth could be 256<<12, which would overflow when further
 	// shifted in preparation for division)
-	dx := viewwidth.Div(2).DivFixed(vtx.cw)
-	dy := viewheight.Div(2).DivFixed(vtx.cw)
-
-	mirror := vtx.cw.Mul(2)
-
-	// sx = (v.x + v.w) * viewwidth / (2*v.w) + viewx0
-	// sy = (v.y + v.w) * viewheight / (2*v.w) + viewy0
-	vtx.x = vtx.cx.AddFixed(vtx.cw).MulFixed(dx).Add(int32(e3d.viewport.VX0)).Round()
-	vtx.y = mirror.SubFixed(vtx.cy.AddFixed(vtx.cw)).MulFixed(dy).Add(int32(e3d.viewport.VY0)).Round()
-	vtx.z = vtx.cz.AddFixed(vtx.cw).Div(2).DivFixed(vtx.cw)
+	dx := emu.NewFixed12(int32(width)).Div(2).DivFixed(vtx.cw)
+	dy := emu.NewFixed12(int32(height)).Div(2).DivFixed(vtx.cw)
+
+	// sx = (v.x + v.w) * width / (2*v.w) + x0
+	// sy = (v.w - v.y) * height / (2*v.w) + top
+	vtx.x = vtx.cx.AddFixed(vtx.cw).MulFixed(dx).Add(int32(x0)).Round()
+	vtx.y = vtx.cw.SubFixed(vtx.cy).MulFixed(dy).Add(int32(top)).Round()
+
+	// depth = (v.z + v.w) * 0xFFFFFF / (2*v.w), computed in 64-bit as the
+	// product doesn't fit 32 bits. cz is within [-cw,cw] after clipping.
+	vtx.depth = 0
+	if vtx.cw.V > 0 {
+		vtx.depth = int32((int64(vtx.cz.V) + int64(vtx.cw.V)) * 0xFFFFFF / (2 * int64(vtx.cw.V)))
+	}
+	vtx.invw = 1 << 30
+	if vtx.cw.V > 1 {
+		vtx.invw = (1 << 30) / vtx.cw.V
+	}
 
 	// Clamp screen coord. This is only required because clipping in clip-space
 	// cannot be accurate with fixed point coordinates (at least not with 12 bit),
-	// and thus it can generate coordinates that are slightly out
-	vtx.x = vtx.x.Clamp(emu.NewFixed12(int32(e3d.viewport.VX0)), emu.NewFixed12(int32(e3d.viewport.VX1)))
-	vtx.y = vtx.y.Clamp(emu.NewFixed12(int32(e3d.viewport.VY0)), emu.NewFixed12(int32(e3d.viewport.VY1)))
+	// and thus it can generate coordinates that are slightly out. The right
+	// and bottom edges are exclusive, so they can be equal to the screen size.
+	vtx.x = vtx.x.Clamp(emu.NewFixed12(int32(x0)), emu.NewFixed12(int32(x0+width)))
+	vtx.y = vtx.y.Clamp(emu.NewFixed12(int32(top)), emu.NewFixed12(int32(top+height)))
+	vtx.x = vtx.x.Clamp(emu.NewFixed12(0), emu.NewFixed12(256))
+	vtx.y = vtx.y.Clamp(emu.NewFixed12(0), emu.NewFixed12(192))
 
 	vtx.flags |= RVFTransformed
 }
@@ -408,7 +627,8 @@ func (e3d *HwEngine3d) preparePolys() {
 		}
 
 		// Calculate the four slopes for each coordinate.  The coordinates
-		// we need to interpolate are: position (X), depth (Z), texture (S & T).
+		// we need to interpolate are: position (X), depth (Z), 1/W,
+		// texture (S & T), and color.
 		//
 		// Assuming a triangle where:
 		//    * v0 is at top
@@ -421,46 +641,52 @@ func (e3d *HwEngine3d) preparePolys() {
 		// slopes for the upper and lower part will obviously be the same (as it's just one
 		// segment).
 		var dxl0, dxl1, dxr0, dxr1 emu.Fixed22
-		var dzl0, dzl1, dzr0, dzr1 emu.Fixed22
+		var dzl0, dzl1, dzr0, dzr1 int32
+		var dwl0, dwl1, dwr0, dwr1 int32
 		var dsl0, dsl1, dsr0, dsr1 emu.Fixed12
 		var dtl0, dtl1, dtr0, dtr1 emu.Fixed12
 		var dcl0, dcl1, dcr0, dcr1 colorDelta
 
 		dxl0 = v1.x.SubFixed(v0.x).ToFixed22()
-		dzl0 = v1.z.SubFixed(v0.z).ToFixed22()
 		dsl0 = v1.s.SubFixed(v0.s)
 		dtl0 = v1.t.SubFixed(v0.t)
 		dcl0 = v1.rgb.SubColor(v0.rgb)
 
 		dxl1 = v2.x.SubFixed(v1.x).ToFixed22()
-		dzl1 = v1.z.SubFixed(v1.z).ToFixed22()
 		dsl1 = v2.s.SubFixed(v1.s)
 		dtl1 = v2.t.SubFixed(v1.t)
 		dcl1 = v2.rgb.SubColor(v1.rgb)
 
 		if hy1 > 0 {
 			dxl0 = dxl0.Div(hy1)
-			dzl0 = dzl0.Div(hy1)
 			dsl0 = dsl0.Div(hy1)
 			dtl0 = dtl0.Div(hy1)
 			dcl0 = dcl0.Div(hy1)
 		}
 		if hy2 > 0 {
 			dxl1 = dxl1.Div(hy2)
-			dzl1 = dzl1.Div(hy2)
 			dsl1 = dsl1.Div(hy2)
 			dtl1 = dtl1.Div(hy2)
 			dcl1 = dcl1.Div(hy2)
 		}
+
+		// Depth and 1/W can use the full 32-bit range, so their deltas
+		// are computed with 64-bit intermediate values.
+		z0, z1, z2 := v0.depth<<kDepthFracBits, v1.depth<<kDepthFracBits, v2.depth<<kDepthFracBits
+		dzl0, dzl1 = lerpDelta(z0, z1, hy1), lerpDelta(z1, z2, hy2)
+		dwl0, dwl1 = lerpDelta(v0.invw, v1.invw, hy1), lerpDelta(v1.invw, v2.invw, hy2)
 		if hy1+hy2 > 0 {
+			dzr0 = lerpDelta(z0, z2, hy1+hy2)
+			dwr0 = lerpDelta(v0.invw, v2.invw, hy1+hy2)
+			dzr1 = dzr0
+			dwr1 = dwr0
+
 			dxr0 = v2.x.SubFixed(v0.x).ToFixed22().Div(hy1 + hy2)
-			dzr0 = v2.z.SubFixed(v0.z).ToFixed22().Div(hy1 + hy2)
 			dsr0 = v2.s.SubFixed(v0.s).Div(hy1 + hy2)
 			dtr0 = v2.t.SubFixed(v0.t).Div(hy1 + hy2)
 			dcr0 = v2.rgb.SubColor(v0.rgb).Div(hy1 + hy2)
 
 			dxr1 = dxr0
-			dzr1 = dzr0
 			dsr1 = dsr0
 			dtr1 = dtr0
 			dcr1 = dcr0
@@ -470,8 +696,11 @@ func (e3d *HwEngine3d) preparePolys() {
 		poly.left[LerpX] = newLerp(v0.x.ToFixed22(), dxl0, dxl1)
 		poly.right[LerpX] = newLerp(v0.x.ToFixed22(), dxr0, dxr1)
 
-		poly.left[LerpZ] = newLerp(v0.z.ToFixed22(), dzl0, dzl1)
-		poly.right[LerpZ] = newLerp(v0.z.ToFixed22(), dzr0, dzr1)
+		poly.left[LerpZ] = newLerpFromInt(z0, dzl0, dzl1)
+		poly.right[LerpZ] = newLerpFromInt(z0, dzr0, dzr1)
+
+		poly.left[LerpW] = newLerpFromInt(v0.invw, dwl0, dwl1)
+		poly.right[LerpW] = newLerpFromInt(v0.invw, dwr0, dwr1)
 
 		poly.left[LerpS] = newLerp12(v0.s, dsl0, dsl1)
 		poly.right[LerpS] = newLerp12(v0.s, dsr0, dsr1)
@@ -487,9 +716,11 @@ func (e3d *HwEngine3d) preparePolys() {
 		// to reflect this. Given that there was no divsion above, delta[0] is the
 		// full different between v1 and v0, so we just need to add it to the start
 		// coordinate (v0) to transform it into v1.
+		poly.longLeft = false
 		if hy1 == 0 {
 			if v0.x.V < v1.x.V {
 				poly.left, poly.right = poly.right, poly.left
+				poly.longLeft = true
 				for idx := range poly.left {
 					rp := &poly.right[idx]
 					rp.start += rp.delta[0]
@@ -510,11 +741,36 @@ func (e3d *HwEngine3d) preparePolys() {
 			// interpolators.
 			if dxl0.V > dxr0.V {
 				poly.left, poly.right = poly.right, poly.left
+				poly.longLeft = true
 			}
 		}
 
 		poly.hy = v1.y.TruncInt32()
 
+		// Values at the middle vertex: the edges that end there restart
+		// from the exact vertex values, while the long edge continues.
+		mids := [NumLerps]int32{
+			LerpX:   v1.x.ToFixed22().V,
+			LerpZ:   z1,
+			LerpW:   v1.invw,
+			LerpS:   v1.s.V,
+			LerpT:   v1.t.V,
+			LerpRGB: int32(v1.rgb),
+		}
+		short, long := &poly.left, &poly.right
+		if poly.longLeft {
+			short, long = long, short
+		}
+		for idx := range mids {
+			short[idx].mid = mids[idx]
+			long[idx].mid = long[idx].start + long[idx].delta[0]*hy1
+		}
+		if hy1 == 0 {
+			for idx := range mids {
+				long[idx].mid = long[idx].start
+			}
+		}
+
 		// if poly.flags.ColorMode() == fillerconfig.ColorModeToon {
 		// 	left := poly.left[LerpRGB]
 		// 	right := poly.right[LerpRGB]
@@ -531,21 +787,54 @@ func (e3d *HwEngine3d) preparePolys() {
 	}
 }
 
-type polySorter []Polygon
+// Translucent returns true if the polygon is rendered in the translucent
+// pass: either its alpha is between 1 and 30, or it uses a texture format
+// with an alpha channel (A3I5, A5I3). Wireframe polygons (alpha=0) are
+// opaque.
+func (poly *Polygon) Translucent() bool {
+	if a := poly.flags.Alpha(); a != 0 && a != 31 {
+		return true
+	}
+	return poly.tex.Format == TexA3I5 || poly.tex.Format == TexA5I3
+}
 
-func (p polySorter) Len() int      { return len(p) }
-func (p polySorter) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+// polySorter sorts polygons in the order in which the hardware renders them:
+// all opaque polygons first, then the translucent ones. Opaque polygons are
+// always sorted by their bottom (and then top) Y coordinate; translucent
+// polygons are sorted in the same way only if ysort is set, otherwise
+// they're kept in the order in which they were sent. Polygons with the same
+// coordinates are kept in the order in which they were sent, which matters
+// for coplanar polygons (eg: UI elements drawn with depth test "equal" or
+// disabled). The coordinates are those of the original polygons (before
+// they were split into triangles), so that the triangles of a polygon are
+// kept together, as on the hardware.
+type polySorter struct {
+	polys []Polygon
+	ysort bool
+}
+
+func (p polySorter) Len() int      { return len(p.polys) }
+func (p polySorter) Swap(i, j int) { p.polys[i], p.polys[j] = p.polys[j], p.polys[i] }
 func (p polySorter) Less(i, j int) bool {
-	ai := p[i].flags.Alpha()
-	aj := p[j].flags.Alpha()
-	return aj > ai
+	pi, pj := &p.polys[i], &p.polys[j]
+	ti, tj := pi.Translucent(), pj.Translucent()
+	if ti != tj {
+		return tj
+	}
+	if !ti || p.ysort {
+		if pi.sortBottom != pj.sortBottom {
+			return pi.sortBottom < pj.sortBottom
+		}
+		return pi.sortTop < pj.sortTop
+	}
+	return false
 }
 
-func (e3d *HwEngine3d) sortPolys() {
+func (e3d *HwEngine3d) sortPolys(ysort bool) {
 	// Do a stable sort, so that we keep the existing order for all
-	// solid polygons (alpha=0x1F). This should be consistent with the order
+	// polygons that compare equal. This should be consistent with the order
 	// the NDS renderes the display list.
-	sort.Stable(polySorter(e3d.next.Pram))
+	sort.Stable(polySorter{e3d.next.Pram, ysort})
 }
 
 func (e3d *HwEngine3d) polysSetWBuffer() {
@@ -554,11 +843,15 @@ func (e3d *HwEngine3d) polysSetWBuffer() {
 
 		// Change screen Z coordinates with W (from clipping space,
 		// which is obvioulsy the only one that exists). Polyfilers use
-		// the screen Z for zbuffering, so this basically switches to
-		// W-buffering.
-		poly.vtx[0].z = poly.vtx[0].cw
-		poly.vtx[1].z = poly.vtx[1].cw
-		poly.vtx[2].z = poly.vtx[2].cw
+		// the depth for zbuffering, so this basically switches to
+		// W-buffering. W is at most kFarClipping after clipping, so it
+		// always fits the 24-bit depth.
+		for _, v := range poly.vtx {
+			v.depth = v.cw.V
+			if v.depth > 0xFFFFFF {
+				v.depth = 0xFFFFFF
+			}
+		}
 	}
 }
 
@@ -603,6 +896,8 @@ func (e3d *HwEngine3d) cmdSwapBuffers(cmd Primitive_SwapBuffers) {
 	// The next frame primitives are complete; we can now do full-frame processing
 	// in preparation for drawing next frame
 
+	e3d.recordSwap()
+
 	// Turn on wbuffering instead of zbuffering, if requested
 	if cmd.WBuffering {
 		e3d.polysSetWBuffer()
@@ -611,8 +906,10 @@ func (e3d *HwEngine3d) cmdSwapBuffers(cmd Primitive_SwapBuffers) {
 	// Computer interpolators/slopes for all polygons
 	e3d.preparePolys()
 
-	// Sort polygons from back to front
-	e3d.sortPolys()
+	// Sort polygons in rendering order. Bit 0 of SWAP_BUFFERS selects manual
+	// sorting of translucent polygons (keep the order in which they were
+	// sent) instead of Y-sorting; opaque polygons are always Y-sorted.
+	e3d.sortPolys(!cmd.ManualSort)
 
 	// Debug dump of scene
 	// e3d.dumpNextScene()
@@ -627,16 +924,36 @@ func (e3d *HwEngine3d) cmdSwapBuffers(cmd Primitive_SwapBuffers) {
 
 	// Get a new buffer from the pool, ready for next frame
 	e3d.next = e3d.pool.Get().(buffer3d)
+	atomic.StoreInt32(&e3d.numPolys, 0)
+	atomic.StoreInt32(&e3d.numVerts, 0)
 }
 
+// Draw3D is the layer function of the 3D engine (see gfx.Layer), used when
+// the 3D output is displayed on its own.
 func (e3d *HwEngine3d) Draw3D(ctx *gfx.LayerCtx, lidx int, y int) {
+	e3d.DrawLines(ctx, y, e3d.copyLine)
+}
+
+// DrawLines renders the current frame, starting from line y, for the lines
+// requested through ctx; out is called to copy each line of the 3D output
+// (see Line3D) into the layer buffer. This allows the 2D engine to display
+// the 3D output as one of its BG layers.
+func (e3d *HwEngine3d) DrawLines(ctx *gfx.LayerCtx, y int, out func(y int, dst gfx.Line)) {
+	if e3d.renderer != nil {
+		e3d.drawRenderer(ctx, y, out)
+		return
+	}
 
-	texMappingEnabled := e3d.Disp3dCnt.Value&1 != 0
+	texMappingEnabled := e3d.cnt.TexMapping()
 
 	// Initialize rasterizer.
 	var polyPerLine [192][]uint16
+	hidden := atomic.LoadUint32(&e3d.dbgHidden)
 	for idx := range e3d.cur.Pram {
 		poly := &e3d.cur.Pram[idx]
+		if hidden != 0 && poly.debugHidden(hidden) {
+			continue
+		}
 
 		// Set current segment to the initial one computed in preparePolys
 		// This is required because we might need to redraw the exact
@@ -656,7 +973,7 @@ func (e3d *HwEngine3d) Draw3D(ctx *gfx.LayerCtx, lidx int, y int) {
 
 		// Update the per-line polygon list, by adding this polygon's index
 		// to the lines in which it is visible.
-		for j := v0.y.TruncInt32(); j <= v2.y.TruncInt32(); j++ {
+		for j := v0.y.TruncInt32(); j <= poly.bottomLine() && j < 192; j++ {
 			polyPerLine[j] = append(polyPerLine[j], uint16(idx))
 		}
 
@@ -713,7 +1030,7 @@ func (e3d *HwEngine3d) Draw3D(ctx *gfx.LayerCtx, lidx int, y int) {
 		default:
 			fcfg.FillMode = fillerconfig.FillModeAlpha
 		}
-		if fcfg.ColorMode == fillerconfig.ColorModeToon && e3d.Disp3dCnt.Value&(1<<1) != 0 {
+		if fcfg.ColorMode == fillerconfig.ColorModeToon && e3d.cnt.Highlight() {
 			fcfg.ColorMode = fillerconfig.ColorModeHighlight
 		}
 
@@ -735,72 +1052,339 @@ func (e3d *HwEngine3d) Draw3D(ctx *gfx.LayerCtx, lidx int, y int) {
 	// could not be ready.
 	e3d.texCache.Update(e3d.cur.Pram, e3d)
 
+	if e3d.threads > 1 {
+		e3d.drawParallel(ctx, &polyPerLine, y, out)
+		return
+	}
+
+	// Single-threaded rendering: draw each line as soon as it's requested.
+	e3d.advancePolys(e3d.cur.Pram, y)
 	for {
 		line := ctx.NextLine()
 		if line.IsNil() {
 			return
 		}
 
-		if e3d.Disp3dCnt.Value&(1<<14) != 0 {
-			panic("bitmap")
+		e3d.drawLine(e3d.cur.Pram, polyPerLine[y], y, e3d.clearLine(y))
+		out(y, line)
+		y++
+	}
+}
+
+// The 3D output is always drawn into an internal per-line buffer, and then
+// copied into the layer buffer. This is required for parallel rendering,
+// and it also makes the output available to the display capture unit.
+func (e3d *HwEngine3d) clearLine(y int) gfx.Line {
+	buf := e3d.lineBuf[y][:]
+	for i := range buf {
+		buf[i] = 0
+	}
+	return gfx.NewLine(buf)
+}
+
+func (e3d *HwEngine3d) copyLine(y int, dst gfx.Line) {
+	src := gfx.NewLine(e3d.lineBuf[y][:])
+	for i := 0; i < 256; i++ {
+		dst.Set32(i, src.Get32(i))
+	}
+}
+
+// Line3D returns the 3D output for line y of the current frame. Each pixel
+// is 32-bit, with the RGB555 color in bits 0-14, the alpha (0-31) in bits
+// 16-20, and bit 31 set if a polygon was drawn there (that is, alpha is not
+// zero). Translucent pixels drawn over the (transparent) rear plane are not
+// blended: the 2D engine blends them with the layers below using their alpha.
+//
+// NOTE: the line is only available after it has been drawn, as part of the
+// 3D layer; it is stale if the 3D layer is not being displayed.
+func (e3d *HwEngine3d) Line3D(y int) gfx.Line {
+	return gfx.NewLine(e3d.lineBuf[y][:])
+}
+
+// Move the interpolators of all polygons to line y, as if all lines
+// before it had been drawn.
+func (e3d *HwEngine3d) advancePolys(polys []Polygon, y int) {
+	for idx := range polys {
+		poly := &polys[idx]
+		top := int(poly.vtx[0].y.TruncInt32())
+		if top >= y {
+			continue
 		}
 
-		var abuf [256]byte
-		var zbuf [256 * 4]byte
-		zbuffer := gfx.NewLine(zbuf[:])
-		abuffer := gfx.NewLine(abuf[:])
-		for i := 0; i < 256; i++ {
-			zbuffer.Set32(i, 0x7FFFFFFF)
-			abuffer.Set8(i, 0x1F)
+		n0 := int(poly.hy) - top
+		if n0 > y-top {
+			n0 = y - top
+		}
+		if n0 < 0 {
+			n0 = 0
 		}
+		n1 := y - top - n0
+		for idx := 0; idx < NumLerps; idx++ {
+			poly.left[idx].Advance(0, n0)
+			poly.right[idx].Advance(0, n0)
+			if int32(y) >= poly.hy {
+				poly.left[idx].Mid()
+				poly.right[idx].Mid()
+			}
+			poly.left[idx].Advance(1, n1)
+			poly.right[idx].Advance(1, n1)
+		}
+	}
+}
 
-		for _, idx := range polyPerLine[y] {
-			poly := &e3d.cur.Pram[idx]
+// Draw the polygons visible in line y into the specified line buffer, and
+// advance their interpolators to the next line.
+func (e3d *HwEngine3d) drawLine(polys []Polygon, lpolys []uint16, y int, line gfx.Line) {
+	var abuf [256]byte
+	var zbuf [256 * 4]byte
+	var attrbuf [256 * 2]byte
+	zbuffer := gfx.NewLine(zbuf[:])
+	abuffer := gfx.NewLine(abuf[:])
+	attrbuffer := gfx.NewLine(attrbuf[:])
+	for i := 0; i < 256; i++ {
+		zbuffer.Set32(i, 0x7FFFFFFF)
+		abuffer.Set8(i, 0x1F)
+	}
 
-			x0, x1 := poly.left[LerpX].Cur().NearInt32(), poly.right[LerpX].Cur().NearInt32()
-			if x0 < 0 || x1 >= 256 || x1 < x0 {
-				fmt.Printf("%v,%v\n", poly.vtx[0].x.TruncInt32(), poly.vtx[0].y.TruncInt32())
-				fmt.Printf("%v,%v\n", poly.vtx[1].x.TruncInt32(), poly.vtx[1].y.TruncInt32())
-				fmt.Printf("%v,%v\n", poly.vtx[2].x.TruncInt32(), poly.vtx[2].y.TruncInt32())
-				fmt.Printf("left lerps: %v\n", poly.left)
-				fmt.Printf("right lerps: %v\n", poly.right)
-				fmt.Printf("x0,x1=%v,%v   y=%v, hy=%v\n", x0, x1, y, poly.hy)
-				// panic("out of bounds")
-			} else {
-				poly.filler(e3d, poly, line, zbuffer, abuffer)
-			}
+	for _, idx := range lpolys {
+		poly := &polys[idx]
 
-			if int32(y) < poly.hy {
-				for idx := 0; idx < NumLerps; idx++ {
-					poly.left[idx].Next(0)
-					poly.right[idx].Next(0)
-				}
-			} else {
-				for idx := 0; idx < NumLerps; idx++ {
-					poly.left[idx].Next(1)
-					poly.right[idx].Next(1)
-				}
-			}
+		e3d.setSpan(poly, y)
+		if poly.flags.Alpha() == 0 {
+			poly.setWireframeEdges(y)
+		}
+
+		// Check that the span is within the screen. This should never happen
+		// as vertices are clamped to the viewport; in debug builds, we want
+		// to catch bugs in the rasterizer, while in release builds the
+		// polyfillers clamp the span to the screen.
+		x0, x1 := poly.x0, poly.x1
+		if gfx.Debug && (x0 < 0 || x1 > 256 || x1 < x0) {
+			panic(fmt.Sprintf("3d: span out of screen: x0,x1=%v,%v y=%v hy=%v\n"+
+				"  vtx: (%v,%v) (%v,%v) (%v,%v)\n  left lerps: %v\n  right lerps: %v",
+				x0, x1, y, poly.hy,
+				poly.vtx[0].x.TruncInt32(), poly.vtx[0].y.TruncInt32(),
+				poly.vtx[1].x.TruncInt32(), poly.vtx[1].y.TruncInt32(),
+				poly.vtx[2].x.TruncInt32(), poly.vtx[2].y.TruncInt32(),
+				poly.left, poly.right))
+		}
+		poly.filler(e3d, poly, line, zbuffer, abuffer, attrbuffer)
+		poly.nextLine(y)
+	}
+}
+
+// Move the interpolators from line y to the next one
+func (poly *Polygon) nextLine(y int) {
+	switch {
+	case int32(y)+1 == poly.hy:
+		for idx := 0; idx < NumLerps; idx++ {
+			poly.left[idx].Mid()
+			poly.right[idx].Mid()
+		}
+	case int32(y) < poly.hy:
+		for idx := 0; idx < NumLerps; idx++ {
+			poly.left[idx].Next(0)
+			poly.right[idx].Next(0)
+		}
+	default:
+		for idx := 0; idx < NumLerps; idx++ {
+			poly.left[idx].Next(1)
+			poly.right[idx].Next(1)
+		}
+	}
+}
+
+// Check whether the edge between the two vertices is part of the outline
+// of the original polygon (rather than created by splitting it in triangles)
+func (poly *Polygon) outerEdge(a, b *Vertex) bool {
+	for _, e := range poly.inner {
+		if (e[0] == a && e[1] == b) || (e[0] == b && e[1] == a) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compute the number of pixels that belong to the left and right edges of
+// a wireframe polygon on line y. Each edge is as wide as the horizontal
+// distance it covers within the line, and flat top/bottom edges (or lines)
+// are drawn as the whole span.
+func (poly *Polygon) setWireframeEdges(y int) {
+	v0, v1, v2 := poly.vtx[0], poly.vtx[1], poly.vtx[2]
+
+	seg, short0, short1 := 0, v0, v1
+	if int32(y) >= poly.hy {
+		seg, short0, short1 = 1, v1, v2
+	}
+
+	edgeWidth := func(l *lerp, visible bool) int32 {
+		if !visible {
+			return 0
+		}
+		d := l.delta[seg]
+		if d < 0 {
+			d = -d
+		}
+		if w := (emu.Fixed22{V: d}).NearInt32(); w > 1 {
+			return w
 		}
+		return 1
+	}
+
+	long := poly.outerEdge(v0, v2)
+	short := poly.outerEdge(short0, short1)
+	if poly.longLeft {
+		poly.wireL = edgeWidth(&poly.left[LerpX], long)
+		poly.wireR = edgeWidth(&poly.right[LerpX], short)
+	} else {
+		poly.wireL = edgeWidth(&poly.left[LerpX], short)
+		poly.wireR = edgeWidth(&poly.right[LerpX], long)
+	}
+
+	if poly.line ||
+		(int32(y) == v0.y.TruncInt32() && v0.y.TruncInt32() == v1.y.TruncInt32() && poly.outerEdge(v0, v1)) ||
+		(int32(y) == poly.bottomLine() && v1.y.TruncInt32() == v2.y.TruncInt32() && poly.outerEdge(v1, v2)) {
+		poly.wireL = 256
+	}
+}
+
+// Multi-threaded rendering: the screen (starting from line y) is split into
+// horizontal bands, each one rendered by a different goroutine into a
+// private frame buffer; lines are then copied into the layer buffer as
+// they're requested, waiting for them to be ready.
+func (e3d *HwEngine3d) drawParallel(ctx *gfx.LayerCtx, polyPerLine *[192][]uint16, y int, out func(y int, dst gfx.Line)) {
+	nlines := 192 - y
+	nbands := e3d.threads
+	if nbands > nlines {
+		nbands = nlines
+	}
+	if len(e3d.bandPolys) < nbands {
+		e3d.bandPolys = make([][]Polygon, nbands)
+	}
+	for i := range e3d.lineReady {
+		e3d.lineReady[i] = false
+	}
 
-		/*
-			// TO BE TESTED
-			for i := 0; i < 256; i++ {
-				if abuffer.Get8(i) == 0 {
-					line.Set16(i, 0)
+	var wg sync.WaitGroup
+	var stop int32
+	for b := 0; b < nbands; b++ {
+		y0 := y + nlines*b/nbands
+		y1 := y + nlines*(b+1)/nbands
+
+		// Each band needs its own copy of the polygons, as interpolators
+		// are updated while drawing.
+		polys := append(e3d.bandPolys[b][:0], e3d.cur.Pram...)
+		e3d.bandPolys[b] = polys
+
+		wg.Add(1)
+		go func(polys []Polygon, y0, y1 int) {
+			defer wg.Done()
+			e3d.advancePolys(polys, y0)
+			for j := y0; j < y1; j++ {
+				if atomic.LoadInt32(&stop) != 0 {
+					return
 				}
+				e3d.drawLine(polys, polyPerLine[j], j, e3d.clearLine(j))
+
+				e3d.lineMtx.Lock()
+				e3d.lineReady[j] = true
+				e3d.lineCond.Broadcast()
+				e3d.lineMtx.Unlock()
 			}
-		*/
+		}(polys, y0, y1)
+	}
+
+	for {
+		line := ctx.NextLine()
+		if line.IsNil() {
+			// Drawing was interrupted (or the frame is over): make sure
+			// that all workers exit before returning, as the current
+			// scene might be recycled.
+			atomic.StoreInt32(&stop, 1)
+			wg.Wait()
+			return
+		}
+
+		e3d.lineMtx.Lock()
+		for !e3d.lineReady[y] {
+			e3d.lineCond.Wait()
+		}
+		e3d.lineMtx.Unlock()
+
+		out(y, line)
 		y++
 	}
 }
 
+// Polygon classes that can be hidden for debugging purposes (see
+// SetDebugLayerMask)
+const (
+	DebugLayerOpaque = 1 << iota
+	DebugLayerTranslucent
+
+	DebugLayerAll = DebugLayerOpaque | DebugLayerTranslucent
+)
+
+// SetDebugLayerMask selects the classes of polygons that are drawn (by
+// default, all of them); see Polygon.Translucent. This is a debugging aid
+// (eg: to isolate translucent polygons in screenshots), and it can be changed
+// at any time, from any goroutine: it's applied starting from the next frame.
+func (e3d *HwEngine3d) SetDebugLayerMask(mask uint32) {
+	atomic.StoreUint32(&e3d.dbgHidden, ^mask&DebugLayerAll)
+}
+
+// DebugLayerMask returns the classes of polygons that are drawn (see
+// SetDebugLayerMask).
+func (e3d *HwEngine3d) DebugLayerMask() uint32 {
+	return ^atomic.LoadUint32(&e3d.dbgHidden) & DebugLayerAll
+}
+
+func (poly *Polygon) debugHidden(hidden uint32) bool {
+	if poly.Translucent() {
+		return hidden&DebugLayerTranslucent != 0
+	}
+	return hidden&DebugLayerOpaque != 0
+}
+
+// DumpTextures saves each texture decoded through the texture cache (that
+// is, Tex4x4 textures) as a PNG file into dir, once. As this is slow, a
+// texture is only dumped when the frame budget allows it (budget can be nil
+// to always dump); otherwise, it's retried the next time it's used. It must
+// be called before the emulation is started.
+func (e3d *HwEngine3d) DumpTextures(dir string, budget *emu.FrameBudget) {
+	e3d.texCache.dumpDir = dir
+	e3d.texCache.dumpBudget = budget
+}
+
+// SetThreads configures the number of goroutines used to rasterize the
+// 3D scene. With 1 (or less), all rendering happens in the layer goroutine.
+func (e3d *HwEngine3d) SetThreads(n int) {
+	e3d.threads = n
+}
+
 func (e3d *HwEngine3d) SetVram(tex VramTextureBank, pal VramTexturePaletteBank) {
 	e3d.texVram = tex
 	e3d.palVram = pal
 }
 
 func (e3d *HwEngine3d) BeginFrame() {
+	e3d.switchRenderer()
+
+	// Latch the rendering configuration for the whole frame
+	e3d.cnt = disp3dCnt(e3d.Disp3dCnt.Value)
+	e3d.alphaRef = -1
+	if e3d.cnt.AlphaTest() {
+		e3d.alphaRef = int(e3d.AlphaTestRef.Value & 0x1F)
+	}
+
+	// Warn about features that are not emulated yet
+	const unsupported = 1<<4 | 1<<5 | 1<<7 | 1<<14
+	if req := e3d.cnt & unsupported &^ e3d.unsupported; req != 0 {
+		e3d.unsupported |= req
+		mod3d.WithField("disp3dcnt", fmt.Sprintf("%04x", uint32(e3d.cnt))).Warnf(
+			"unsupported 3D features: antialias=%v edgemark=%v fog=%v rearbitmap=%v",
+			e3d.cnt.AntiAliasing(), e3d.cnt.EdgeMarking(), e3d.cnt.Fog(), e3d.cnt.RearPlaneBitmap())
+	}
 }
 
 func (e3d *HwEngine3d) EndFrame() {
@@ -818,10 +1402,14 @@ func (e3d *HwEngine3d) EndFrame() {
 	}
 }
 
+// NumVertices returns the number of vertices stored so far in vertex RAM
+// for the next frame.
 func (e3d *HwEngine3d) NumVertices() int {
-	return len(e3d.cur.Vram)
+	return int(atomic.LoadInt32(&e3d.numVerts))
 }
 
+// NumPolygons returns the number of polygons stored so far in polygon RAM
+// for the next frame.
 func (e3d *HwEngine3d) NumPolygons() int {
-	return len(e3d.cur.Pram)
+	return int(atomic.LoadInt32(&e3d.numPolys))
 }
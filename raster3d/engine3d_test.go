@@ -0,0 +1,388 @@
+package raster3d
+
+import (
+	"ndsemu/emu"
+	"ndsemu/emu/gfx"
+	"ndsemu/raster3d/fillerconfig"
+	"testing"
+)
+
+func TestViewportTransform(t *testing.T) {
+	tests := []struct {
+		vp     Primitive_SetViewport
+		cx, cy float64
+		sx, sy int32
+	}{
+		// Full screen
+		{Primitive_SetViewport{0, 0, 255, 191}, -1, 1, 0, 0},
+		{Primitive_SetViewport{0, 0, 255, 191}, 1, -1, 256, 192},
+		{Primitive_SetViewport{0, 0, 255, 191}, 0, 0, 128, 96},
+		{Primitive_SetViewport{0, 0, 255, 191}, -0.5, 0.5, 64, 48},
+
+		// Upper half of the screen (Y is measured from the bottom)
+		{Primitive_SetViewport{0, 96, 255, 191}, 0, 1, 128, 0},
+		{Primitive_SetViewport{0, 96, 255, 191}, 0, -1, 128, 96},
+
+		// Lower-right quarter of the screen
+		{Primitive_SetViewport{128, 0, 255, 95}, -1, 1, 128, 96},
+		{Primitive_SetViewport{128, 0, 255, 95}, 1, -1, 256, 192},
+		{Primitive_SetViewport{128, 0, 255, 95}, 0, 0, 192, 144},
+	}
+
+	for _, test := range tests {
+		e3d := &HwEngine3d{viewport: test.vp}
+		vtx := Vertex{
+			cx: emu.Fixed12{V: int32(test.cx * 4096)},
+			cy: emu.Fixed12{V: int32(test.cy * 4096)},
+			cw: emu.NewFixed12(1),
+		}
+		e3d.vtxTransform(&vtx)
+		if sx, sy := vtx.x.TruncInt32(), vtx.y.TruncInt32(); sx != test.sx || sy != test.sy {
+			t.Errorf("viewport %v, vertex (%v,%v): got (%d,%d), want (%d,%d)",
+				test.vp, test.cx, test.cy, sx, sy, test.sx, test.sy)
+		}
+	}
+}
+
+func TestDepthInterpolation(t *testing.T) {
+	// Triangle with the middle vertex on the left: the left edge is made of
+	// two segments, and depth must be interpolated along both of them.
+	vtx := []Vertex{
+		{x: emu.NewFixed12(100), y: emu.NewFixed12(10), depth: 0x100000, invw: 1 << 18},
+		{x: emu.NewFixed12(20), y: emu.NewFixed12(60), depth: 0x800000, invw: 1 << 16},
+		{x: emu.NewFixed12(120), y: emu.NewFixed12(110), depth: 0xFFFFFF, invw: 1 << 10},
+	}
+	e3d := &HwEngine3d{}
+	e3d.next.Pram = []Polygon{{vtx: [3]*Vertex{&vtx[0], &vtx[1], &vtx[2]}}}
+	e3d.preparePolys()
+	poly := &e3d.next.Pram[0]
+
+	// Slopes are truncated, so allow for an error of one fractional unit
+	// per step.
+	check := func(what string, l *lerp, shift uint, want int32, steps int32) {
+		got := l.CurAsInt()
+		diff := int64(want)<<shift - int64(got)
+		if diff < -int64(steps) || diff > int64(steps) {
+			t.Errorf("%s: got %x, want %x", what, got>>shift, want)
+		}
+	}
+
+	for _, y := range []int{60, 110} {
+		for idx := range poly.left {
+			poly.left[idx].Reset()
+			poly.right[idx].Reset()
+		}
+		e3d.advancePolys(e3d.next.Pram, y)
+
+		v := &vtx[1]
+		if y == 110 {
+			v = &vtx[2]
+			check("right depth", &poly.right[LerpZ], kDepthFracBits, v.depth, 100)
+			check("right 1/W", &poly.right[LerpW], 0, v.invw, 100)
+		}
+		check("left depth", &poly.left[LerpZ], kDepthFracBits, v.depth, 100)
+		check("left 1/W", &poly.left[LerpW], 0, v.invw, 100)
+	}
+}
+
+func TestTranslucentPolygonRules(t *testing.T) {
+	vtx := []Vertex{
+		{x: emu.NewFixed12(0), y: emu.NewFixed12(0), depth: 0x1000},
+		{x: emu.NewFixed12(0), y: emu.NewFixed12(16), depth: 0x1000},
+		{x: emu.NewFixed12(16), y: emu.NewFixed12(16), depth: 0x1000},
+	}
+
+	tests := []struct {
+		flags   PolygonFlags
+		attr    uint16 // initial attribute of pixels
+		drawn   bool
+		zupdate bool
+	}{
+		// Translucent pixels don't update depth, unless requested
+		{5 << 24, 0, true, false},
+		{5<<24 | PFTransDepthUpdate, 0, true, true},
+		// Not drawn over translucent pixels of the same polygon ID
+		{5 << 24, attrTransValid | 5<<8, false, false},
+		{5 << 24, attrTransValid | 6<<8, true, false},
+		// Opaque polygon IDs don't matter
+		{5 << 24, 5, true, false},
+	}
+
+	for i, test := range tests {
+		e3d := &HwEngine3d{alphaRef: -1}
+		e3d.next.Pram = []Polygon{{
+			vtx:   [3]*Vertex{&vtx[0], &vtx[1], &vtx[2]},
+			flags: test.flags | 16<<16,
+		}}
+		e3d.preparePolys()
+		poly := &e3d.next.Pram[0]
+		e3d.advancePolys(e3d.next.Pram, 8)
+		e3d.setSpan(poly, 8)
+
+		var outbuf, zbuf [256 * 4]byte
+		var abuf [256]byte
+		var attrbuf [256 * 2]byte
+		out, zb := gfx.NewLine(outbuf[:]), gfx.NewLine(zbuf[:])
+		ab, attr := gfx.NewLine(abuf[:]), gfx.NewLine(attrbuf[:])
+		for x := 0; x < 256; x++ {
+			zb.Set32(x, 0x7FFFFFFF)
+			ab.Set8(x, 0x1F)
+			attr.Set16(x, test.attr)
+		}
+
+		cfg := fillerconfig.FillerConfig{FillMode: fillerconfig.FillModeAlpha}
+		polygonFillerTable[cfg.Key()](e3d, poly, out, zb, ab, attr)
+
+		if drawn := out.Get32(4) != 0; drawn != test.drawn {
+			t.Errorf("test %d: drawn=%v, want %v", i, drawn, test.drawn)
+		}
+		if zupdate := zb.Get32(4) != 0x7FFFFFFF; zupdate != test.zupdate {
+			t.Errorf("test %d: depth updated=%v, want %v", i, zupdate, test.zupdate)
+		}
+		if test.drawn && attr.Get16(4)&(attrTransValid|attrTransID) != attrTransValid|uint16(poly.flags.ID())<<8 {
+			t.Errorf("test %d: invalid attributes: %04x", i, attr.Get16(4))
+		}
+	}
+}
+
+func TestPolygonSortOrder(t *testing.T) {
+	// Polygons spanning different Y ranges; vertices are already sorted by Y
+	newPoly := func(top, bottom int, alpha PolygonFlags) Polygon {
+		v0 := &Vertex{y: emu.NewFixed12(int32(top))}
+		v2 := &Vertex{y: emu.NewFixed12(int32(bottom))}
+		return Polygon{vtx: [3]*Vertex{v0, v0, v2}, flags: alpha << 16,
+			sortTop: int32(top), sortBottom: int32(bottom)}
+	}
+	pram := func() []Polygon {
+		return []Polygon{
+			newPoly(50, 100, 16), // 0: translucent
+			newPoly(0, 20, 31),   // 1: opaque
+			newPoly(10, 40, 8),   // 2: translucent
+			newPoly(60, 90, 0),   // 3: wireframe (opaque)
+			newPoly(0, 40, 8),    // 4: translucent
+			newPoly(10, 20, 31),  // 5: opaque
+			newPoly(30, 50, 31),  // 6: opaque
+			newPoly(10, 20, 0),   // 7: wireframe, same coordinates as 5
+		}
+	}
+
+	// Opaque polygons are Y-sorted even with manual sorting, and polygons
+	// with the same coordinates keep their order
+	for _, test := range []struct {
+		ysort bool
+		order []int
+	}{
+		{true, []int{1, 5, 7, 6, 3, 4, 2, 0}},
+		{false, []int{1, 5, 7, 6, 3, 0, 2, 4}},
+	} {
+		e3d := &HwEngine3d{}
+		orig := pram()
+		e3d.next.Pram = append([]Polygon(nil), orig...)
+		e3d.sortPolys(test.ysort)
+		for i, idx := range test.order {
+			if e3d.next.Pram[i].vtx[0] != orig[idx].vtx[0] {
+				t.Errorf("ysort=%v: polygon %d is not #%d", test.ysort, i, idx)
+			}
+		}
+	}
+}
+
+func TestViewportPerPolygon(t *testing.T) {
+	for _, split := range []struct {
+		name   string
+		vp     [2]Primitive_SetViewport
+		x0, y0 [2]int32 // top-left corner of each polygon
+		w, h   int32    // size of each polygon
+	}{
+		{"left/right",
+			[2]Primitive_SetViewport{{0, 0, 127, 191}, {128, 0, 255, 191}},
+			[2]int32{0, 128}, [2]int32{0, 0}, 128, 192},
+		{"top/bottom",
+			[2]Primitive_SetViewport{{0, 96, 255, 191}, {0, 0, 255, 95}},
+			[2]int32{0, 0}, [2]int32{0, 96}, 256, 96},
+	} {
+		e3d := &HwEngine3d{}
+		e3d.next.Vram = make([]Vertex, 0, 16) // polygons point into it
+		tri := func(vp Primitive_SetViewport) {
+			e3d.viewport = vp
+			base := len(e3d.next.Vram)
+			for _, v := range [][2]int32{{-1, 1}, {1, 1}, {-1, -1}} {
+				e3d.cmdVertex(Primitive_Vertex{
+					X: emu.NewFixed12(v[0]), Y: emu.NewFixed12(v[1]), W: emu.NewFixed12(1),
+				})
+			}
+			e3d.cmdPolygon(Primitive_Polygon{
+				Attr: uint32(PFRenderFront | PFRenderBack),
+				Vtx:  [4]int{base, base + 1, base + 2},
+			})
+		}
+
+		// Both halves of the screen, within the same frame
+		tri(split.vp[0])
+		tri(split.vp[1])
+
+		if len(e3d.next.Pram) != 2 {
+			t.Fatalf("%s: invalid number of polygons: %d", split.name, len(e3d.next.Pram))
+		}
+		for i := range split.vp {
+			poly := &e3d.next.Pram[i]
+			x0, y0 := split.x0[i], split.y0[i]
+			if x, y := poly.vtx[0].x.TruncInt32(), poly.vtx[0].y.TruncInt32(); x != x0 || y != y0 {
+				t.Errorf("%s: polygon %d: top-left at (%d,%d), want (%d,%d)", split.name, i, x, y, x0, y0)
+			}
+			if x := poly.vtx[1].x.TruncInt32(); x != x0+split.w {
+				t.Errorf("%s: polygon %d: right edge at %d, want %d", split.name, i, x, x0+split.w)
+			}
+			if y := poly.vtx[2].y.TruncInt32(); y != y0+split.h {
+				t.Errorf("%s: polygon %d: bottom edge at %d, want %d", split.name, i, y, y0+split.h)
+			}
+		}
+	}
+}
+
+func TestViewportClipping(t *testing.T) {
+	// The NDS has no scissor registers: polygons are clipped against the
+	// view volume, which maps onto the viewport, so a polygon larger than
+	// the view volume never spills into the other half of a split screen.
+	e3d := &HwEngine3d{}
+	e3d.next.Vram = make([]Vertex, 0, 16) // polygons point into it
+	e3d.viewport = Primitive_SetViewport{0, 0, 127, 191}
+	for _, v := range [][2]int32{{-3, 3}, {3, 3}, {0, -3}} {
+		e3d.cmdVertex(Primitive_Vertex{
+			X: emu.NewFixed12(v[0]), Y: emu.NewFixed12(v[1]), W: emu.NewFixed12(1),
+		})
+	}
+	e3d.cmdPolygon(Primitive_Polygon{
+		Attr: uint32(PFRenderFront | PFRenderBack),
+		Vtx:  [4]int{0, 1, 2},
+	})
+
+	if len(e3d.next.Pram) == 0 {
+		t.Fatal("polygon not drawn")
+	}
+	for i := range e3d.next.Pram {
+		for _, v := range e3d.next.Pram[i].vtx {
+			if x, y := v.x.TruncInt32(), v.y.TruncInt32(); x < 0 || x > 128 || y < 0 || y > 192 {
+				t.Errorf("triangle %d: vertex (%d,%d) outside the viewport", i, x, y)
+			}
+		}
+	}
+}
+
+func TestQuadSplit(t *testing.T) {
+	e3d := &HwEngine3d{}
+	e3d.next.Vram = make([]Vertex, 0, 16) // polygons point into it
+	e3d.viewport = Primitive_SetViewport{0, 0, 255, 191}
+	quad := func(alpha PolygonFlags, y0, y1 int32) {
+		base := len(e3d.next.Vram)
+		for _, v := range [][2]int32{{0, y0}, {1, y0}, {1, y1}, {0, y1}} {
+			e3d.cmdVertex(Primitive_Vertex{
+				X: emu.NewFixed12(v[0]), Y: emu.Fixed12{V: v[1]}, W: emu.NewFixed12(1),
+			})
+		}
+		e3d.cmdPolygon(Primitive_Polygon{
+			Attr: uint32(PFQuad | PFRenderFront | PFRenderBack | alpha<<16),
+			Vtx:  [4]int{base, base + 1, base + 2, base + 3},
+		})
+	}
+
+	// Two translucent quads: the second one is higher on the screen, so it's
+	// sorted first.
+	quad(16, 0, -4096)
+	quad(16, 4096, 0)
+
+	if n := e3d.NumPolygons(); n != 2 {
+		t.Errorf("polygon RAM count: %d, want 2", n)
+	}
+	if len(e3d.next.Pram) != 4 {
+		t.Fatalf("invalid number of triangles: %d", len(e3d.next.Pram))
+	}
+
+	e3d.preparePolys()
+	e3d.sortPolys(true)
+	// The triangles of each quad are kept together
+	quadOf := func(poly *Polygon) int {
+		for i := range e3d.next.Vram {
+			if poly.vtx[0] == &e3d.next.Vram[i] {
+				return i / 4
+			}
+		}
+		return -1
+	}
+	for i, want := range []int{1, 1, 0, 0} {
+		if q := quadOf(&e3d.next.Pram[i]); q != want {
+			t.Errorf("triangle %d: quad %d, want %d", i, q, want)
+		}
+	}
+}
+
+func TestQuadDegenerate(t *testing.T) {
+	e3d := &HwEngine3d{}
+	e3d.next.Vram = make([]Vertex, 0, 16) // polygons point into it
+	e3d.viewport = Primitive_SetViewport{0, 0, 255, 191}
+	quad := func(vtx [4][2]int32) []Polygon {
+		base, first := len(e3d.next.Vram), len(e3d.next.Pram)
+		for _, v := range vtx {
+			e3d.cmdVertex(Primitive_Vertex{
+				X: emu.Fixed12{V: v[0] * 1024}, Y: emu.Fixed12{V: v[1] * 1024}, W: emu.NewFixed12(1),
+			})
+		}
+		e3d.cmdPolygon(Primitive_Polygon{
+			Attr: uint32(PFQuad | PFRenderFront | PFRenderBack | 31<<16),
+			Vtx:  [4]int{base, base + 1, base + 2, base + 3},
+		})
+		return e3d.next.Pram[first:]
+	}
+
+	// First three vertices aligned: the quad has an area, so the first
+	// triangle is dropped rather than drawn as a line, and its diagonal
+	// becomes an outer edge.
+	tris := quad([4][2]int32{{-2, 0}, {0, 0}, {2, 0}, {0, 2}})
+	if len(tris) != 1 {
+		t.Fatalf("aligned vertices: %d triangles, want 1", len(tris))
+	}
+	if tris[0].line || tris[0].inner != [2][2]*Vertex{} {
+		t.Errorf("aligned vertices: line=%v inner=%v", tris[0].line, tris[0].inner)
+	}
+
+	// Repeated vertex: same as above
+	tris = quad([4][2]int32{{-2, 0}, {2, 0}, {2, 0}, {0, 2}})
+	if len(tris) != 1 || tris[0].line {
+		t.Errorf("repeated vertex: %d triangles (line=%v), want 1", len(tris), len(tris) > 0 && tris[0].line)
+	}
+
+	// All vertices aligned: both triangles are drawn as lines
+	tris = quad([4][2]int32{{-2, 0}, {-1, 0}, {1, 0}, {2, 0}})
+	if len(tris) != 2 || !tris[0].line || !tris[1].line {
+		t.Errorf("line: %d triangles, want 2 lines", len(tris))
+	}
+}
+
+func TestSwitchRenderer(t *testing.T) {
+	e3d := NewHwEngine3d()
+
+	// The switch is applied only at the beginning of the next frame
+	e3d.SwitchRenderer("bogus")
+	if e3d.RendererName() != "soft" {
+		t.Errorf("renderer switched before the end of the frame")
+	}
+
+	// Unknown backends are ignored, and the current one is kept
+	e3d.BeginFrame()
+	if e3d.RendererName() != "soft" || e3d.renderer != nil {
+		t.Errorf("invalid renderer after failed switch: %q", e3d.RendererName())
+	}
+
+	// Only one switch can be pending
+	e3d.SwitchRenderer("soft")
+	e3d.SwitchRenderer("bogus")
+	e3d.BeginFrame()
+	if e3d.RendererName() != "soft" {
+		t.Errorf("invalid renderer: %q", e3d.RendererName())
+	}
+	select {
+	case name := <-e3d.switchCh:
+		t.Errorf("switch still pending: %q", name)
+	default:
+	}
+}
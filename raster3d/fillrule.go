@@ -0,0 +1,149 @@
+package raster3d
+
+// A FillRule defines which pixels along the edges of a polygon are drawn.
+// Vertically, both rules draw the lines from the top vertex (included) to
+// the bottom vertex (excluded), so that polygons sharing an edge never
+// overlap nor leave gaps between them.
+type FillRule int
+
+const (
+	// FillRuleNDS matches the hardware. On each line, every edge covers a
+	// run of pixels: a single pixel for Y-major edges (slope <= 1), or the
+	// whole horizontal distance covered by the edge within the line for
+	// X-major edges. The run is drawn for Y-major left edges and X-major
+	// right edges; otherwise, the span stops at the run.
+	//
+	// Translucent polygons, or frames with antialiasing or edge marking
+	// enabled, always draw the runs of both edges (except for vertical right
+	// edges).
+	FillRuleNDS FillRule = iota
+
+	// FillRuleTopLeft is the common rule of PC GPUs: a pixel is drawn if its
+	// center is within the polygon, or exactly on a left edge.
+	FillRuleTopLeft
+)
+
+// SetFillRule selects the rule used to decide which pixels on polygon edges
+// are drawn (FillRuleNDS by default).
+func (e3d *HwEngine3d) SetFillRule(rule FillRule) {
+	e3d.fillRule = rule
+}
+
+// Last line drawn for the polygon (bottom vertex excluded, unless the
+// polygon is just one line high)
+func (poly *Polygon) bottomLine() int32 {
+	top, bottom := poly.vtx[0].y.TruncInt32(), poly.vtx[2].y.TruncInt32()
+	if bottom > top {
+		bottom--
+	}
+	return bottom
+}
+
+func fixed22Floor(v int32) int32 { return v >> 22 }
+func fixed22Ceil(v int32) int32  { return (v + 1<<22 - 1) >> 22 }
+
+// Compute the run of pixels [x0,x1] covered by an edge on a line, given its
+// X coordinate at the top and at the bottom of the line. It also returns
+// true if the edge is X-major.
+func edgeRun(x, xnext int32) (x0, x1 int32, xmajor bool) {
+	xa, xb := x, xnext
+	if xb < xa {
+		xa, xb = xb, xa
+	}
+	if xb-xa <= 1<<22 {
+		x0 = fixed22Floor(x)
+		return x0, x0, false
+	}
+	x0, x1 = fixed22Floor(xa), fixed22Floor(xb)-1
+	if x1 < x0 {
+		x1 = x0
+	}
+	return x0, x1, true
+}
+
+// Compute the span [poly.x0, poly.x1) to be drawn on line y, according to
+// the fill rule. Interpolators must be positioned on line y.
+func (e3d *HwEngine3d) setSpan(poly *Polygon, y int) {
+	l, r := &poly.left[LerpX], &poly.right[LerpX]
+
+	var x0, x1 int32
+	switch {
+	case poly.line:
+		// Degenerate polygon: draw the pixels covered by the line, whatever
+		// the fill rule. Horizontal lines and single dots are drawn from the
+		// leftmost to the rightmost vertex (both included).
+		if poly.vtx[0].y.TruncInt32() == poly.vtx[2].y.TruncInt32() {
+			x0, x1 = 256, -1
+			for _, v := range poly.vtx {
+				x := v.x.TruncInt32()
+				if x > 255 {
+					x = 255
+				}
+				if x < x0 {
+					x0 = x
+				}
+				if x > x1 {
+					x1 = x
+				}
+			}
+			x1++
+			break
+		}
+		seg := 0
+		if int32(y) >= poly.hy {
+			seg = 1
+		}
+		l0, l1, _ := edgeRun(l.cur, l.cur+l.delta[seg])
+		r0, r1, _ := edgeRun(r.cur, r.cur+r.delta[seg])
+		x0, x1 = l0, l1+1
+		if r0 < x0 {
+			x0 = r0
+		}
+		if r1+1 > x1 {
+			x1 = r1 + 1
+		}
+		if x1 > 256 {
+			x1 = 256
+		}
+
+	case poly.vtx[0].y.TruncInt32() == poly.vtx[2].y.TruncInt32():
+		// Flat polygon: draw it as a single line
+		x0, x1 = l.Cur().NearInt32(), r.Cur().NearInt32()
+
+	case e3d.fillRule == FillRuleTopLeft:
+		seg := 0
+		if int32(y) >= poly.hy {
+			seg = 1
+		}
+		// Sample the edges at the vertical center of the line, and draw
+		// the pixels whose center is in [left, right).
+		const half = 1 << 21
+		x0 = fixed22Ceil(l.cur + l.delta[seg]/2 - half)
+		x1 = fixed22Ceil(r.cur + r.delta[seg]/2 - half)
+
+	default:
+		seg := 0
+		if int32(y) >= poly.hy {
+			seg = 1
+		}
+		l0, l1, lxmajor := edgeRun(l.cur, l.cur+l.delta[seg])
+		r0, r1, rxmajor := edgeRun(r.cur, r.cur+r.delta[seg])
+		fillAll := poly.Translucent() || e3d.cnt.AntiAliasing() || e3d.cnt.EdgeMarking()
+
+		x0 = l0
+		if lxmajor && !fillAll {
+			x0 = l1 + 1
+		}
+		// Vertical right edges are never drawn, as their run is just
+		// outside of the polygon.
+		x1 = r0
+		if rxmajor || (fillAll && r.delta[seg] != 0) {
+			x1 = r1 + 1
+		}
+	}
+
+	if x1 < x0 {
+		x1 = x0
+	}
+	poly.x0, poly.x1 = x0, x1
+}
@@ -0,0 +1,142 @@
+package raster3d
+
+import (
+	"ndsemu/emu"
+	"testing"
+)
+
+// Draw a mesh of adjacent triangles covering a rectangle: with any fill
+// rule, each pixel of the rectangle must be drawn exactly once. The only
+// exception is the NDS rule, where X-major edges converging on a vertex
+// can overlap on the line right above it (like on the hardware).
+func TestFillRuleSeams(t *testing.T) {
+	const nx, ny = 6, 5
+	const x0, y0, x1, y1 = 10, 7, 240, 183
+
+	// Grid of vertices, with interior ones moved around to get all kinds
+	// of slopes
+	var grid [ny + 1][nx + 1]Vertex
+	for j := 0; j <= ny; j++ {
+		for i := 0; i <= nx; i++ {
+			x := int32(x0 + (x1-x0)*i/nx)
+			y := int32(y0 + (y1-y0)*j/ny)
+			if i > 0 && i < nx && j > 0 && j < ny {
+				x += int32((i*7+j*13)%23 - 11)
+				y += int32((i*11+j*5)%17 - 8)
+			}
+			grid[j][i] = Vertex{x: emu.NewFixed12(x), y: emu.NewFixed12(y)}
+		}
+	}
+
+	for _, rule := range []FillRule{FillRuleNDS, FillRuleTopLeft} {
+		e3d := &HwEngine3d{fillRule: rule}
+		vram := grid
+		for j := 0; j < ny; j++ {
+			for i := 0; i < nx; i++ {
+				a, b := &vram[j][i], &vram[j][i+1]
+				c, d := &vram[j+1][i], &vram[j+1][i+1]
+				e3d.next.Pram = append(e3d.next.Pram,
+					Polygon{vtx: [3]*Vertex{a, b, d}, flags: 31 << 16},
+					Polygon{vtx: [3]*Vertex{a, d, c}, flags: 31 << 16})
+			}
+		}
+		e3d.preparePolys()
+
+		var cov [192][256]int
+		for idx := range e3d.next.Pram {
+			poly := &e3d.next.Pram[idx]
+			for i := range poly.left {
+				poly.left[i].Reset()
+				poly.right[i].Reset()
+			}
+			for y := poly.vtx[0].y.TruncInt32(); y <= poly.bottomLine(); y++ {
+				e3d.setSpan(poly, int(y))
+				for x := poly.x0; x < poly.x1; x++ {
+					cov[y][x]++
+				}
+				poly.nextLine(int(y))
+			}
+		}
+
+		nearVertex := func(x, y int) bool {
+			for j := range grid {
+				for i := range grid[j] {
+					vx, vy := int(grid[j][i].x.TruncInt32()), int(grid[j][i].y.TruncInt32())
+					if y == vy-1 && x >= vx-1 && x <= vx {
+						return true
+					}
+				}
+			}
+			return false
+		}
+
+		errors := 0
+		for y := 0; y < 192; y++ {
+			for x := 0; x < 256; x++ {
+				want := 0
+				if x >= x0 && x < x1 && y >= y0 && y < y1 {
+					want = 1
+				}
+				if rule == FillRuleNDS && cov[y][x] == 2 && nearVertex(x, y) {
+					continue
+				}
+				if cov[y][x] != want && errors < 10 {
+					t.Errorf("rule %d: pixel (%d,%d) drawn %d times", rule, x, y, cov[y][x])
+					errors++
+				}
+			}
+		}
+	}
+}
+
+func TestDegeneratePolygons(t *testing.T) {
+	vtx := func(x, y int32) *Vertex {
+		return &Vertex{x: emu.NewFixed12(x), y: emu.NewFixed12(y)}
+	}
+	tests := []struct {
+		vtx   [3]*Vertex
+		spans map[int32][2]int32 // line -> span [x0,x1)
+	}{
+		// Single dot
+		{[3]*Vertex{vtx(40, 20), vtx(40, 20), vtx(40, 20)},
+			map[int32][2]int32{20: {40, 41}}},
+		// Horizontal line
+		{[3]*Vertex{vtx(40, 20), vtx(50, 20), vtx(45, 20)},
+			map[int32][2]int32{20: {40, 51}}},
+		// Vertical line (one vertex repeated)
+		{[3]*Vertex{vtx(40, 20), vtx(40, 24), vtx(40, 24)},
+			map[int32][2]int32{20: {40, 41}, 21: {40, 41}, 22: {40, 41}, 23: {40, 41}}},
+		// X-major diagonal line, with the middle vertex on it
+		{[3]*Vertex{vtx(40, 20), vtx(52, 23), vtx(44, 21)},
+			map[int32][2]int32{20: {40, 44}, 21: {44, 48}, 22: {48, 52}}},
+	}
+
+	for i, test := range tests {
+		for _, rule := range []FillRule{FillRuleNDS, FillRuleTopLeft} {
+			e3d := &HwEngine3d{fillRule: rule}
+			if polyArea(test.vtx[:]) != 0 {
+				t.Errorf("test %d: polygon not detected as a line", i)
+				continue
+			}
+			e3d.next.Pram = []Polygon{{vtx: test.vtx, flags: 31 << 16, line: true}}
+			e3d.preparePolys()
+			poly := &e3d.next.Pram[0]
+			for i := range poly.left {
+				poly.left[i].Reset()
+				poly.right[i].Reset()
+			}
+
+			top := poly.vtx[0].y.TruncInt32()
+			if n := poly.bottomLine() - top + 1; int(n) != len(test.spans) {
+				t.Errorf("test %d: %d lines drawn, want %d", i, n, len(test.spans))
+			}
+			for y := top; y <= poly.bottomLine(); y++ {
+				e3d.setSpan(poly, int(y))
+				if got := [2]int32{poly.x0, poly.x1}; got != test.spans[y] {
+					t.Errorf("test %d, rule %d, line %d: span %v, want %v", i, rule, y, got, test.spans[y])
+				}
+				poly.nextLine(int(y))
+			}
+		}
+	}
+}
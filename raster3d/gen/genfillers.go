@@ -37,7 +37,7 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 		cfg.FillMode = fillerconfig.FillModeAlpha
 	}
 
-	fmt.Fprintf(g, "x0, x1 := poly.left[LerpX].Cur().NearInt32(), poly.right[LerpX].Cur().NearInt32()\n")
+	fmt.Fprintf(g, "x0, x1 := poly.x0, poly.x1\n")
 	fmt.Fprintf(g, "nx := x1-x0; if nx==0 {return}\n")
 	fmt.Fprintf(g, "z0, z1 := poly.left[LerpZ].Cur(), poly.right[LerpZ].Cur()\n")
 	fmt.Fprintf(g, "dz := z1.SubFixed(z0).Div(nx)\n")
@@ -65,8 +65,33 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	}
 	if cfg.FillMode == fillerconfig.FillModeAlpha {
 		fmt.Fprintf(g, "polyalpha := uint8(poly.flags.Alpha())<<1\n")
+		fmt.Fprintf(g, "alphablend := e3d.cnt.AlphaBlending()\n")
+		fmt.Fprintf(g, "alpharef := e3d.alphaRef\n")
+	}
+	fmt.Fprintf(g, "polyid := uint16(poly.flags.ID())\n")
+	fmt.Fprintf(g, "zequal := poly.flags.DepthEqual()\n")
+	if cfg.FillMode == fillerconfig.FillModeAlpha {
+		fmt.Fprintf(g, "transid := polyid<<8 | attrTransValid\n")
+		fmt.Fprintf(g, "transdepth := poly.flags.TransDepthUpdate()\n")
+	}
+	if cfg.FillMode == fillerconfig.FillModeWireframe {
+		// Wireframe: only the pixels belonging to the edges are drawn
+		fmt.Fprintf(g, "wl, wr := x0+poly.wireL, x1-poly.wireR\n")
 	}
 
+	// Clamp the span to the screen. Spans are not supposed to go out of the
+	// screen (in debug builds, drawLine panics before calling us), but in
+	// case of bugs we prefer clamping rather than corrupting memory.
+	fmt.Fprintf(g, "if x1 > 256 { x1 = 256 }\n")
+	fmt.Fprintf(g, "for ; x0 < 0; x0++ {\n")
+	fmt.Fprintf(g, "z0 = z0.AddFixed(dz)\n")
+	fmt.Fprintf(g, "c0 = c0.AddDelta(dc)\n")
+	if cfg.TexFormat > 0 {
+		fmt.Fprintf(g, "s0 = s0.AddFixed(ds)\n")
+		fmt.Fprintf(g, "t0 = t0.AddFixed(dt)\n")
+	}
+	fmt.Fprintf(g, "}\n")
+
 	// Pre pixel loop
 	switch cfg.TexFormat {
 	case Tex4:
@@ -88,6 +113,9 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	fmt.Fprintf(g, "var pxa uint8\n")
 	fmt.Fprintf(g, "pxa = 63\n")
 	fmt.Fprintf(g, "var px0 uint8\n")
+	if cfg.FillMode == fillerconfig.FillModeAlpha {
+		fmt.Fprintf(g, "var outa uint8\n")
+	}
 	if cfg.TexFormat > 0 {
 		fmt.Fprintf(g, "var s,t uint32\n")
 	}
@@ -100,11 +128,46 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	if cfg.FillMode == fillerconfig.FillModeAlpha {
 		fmt.Fprintf(g, "abuf.Add8(int(x0))\n")
 	}
+	fmt.Fprintf(g, "attr.Add16(int(x0))\n")
+
+	if cfg.ColorMode == fillerconfig.ColorModeShadow {
+		// Shadow polygons with ID 0 are shadow masks: they're not drawn, but
+		// set the stencil on pixels where the depth test *fails* (that is,
+		// pixels of other polygons that are within the shadow volume).
+		fmt.Fprintf(g, "if polyid == 0 {\n")
+		fmt.Fprintf(g, "for x:=x0; x<x1; x++ {\n")
+		fmt.Fprintf(g, "if z0.V >= int32(zbuf.Get32(0)) { attr.Set16(0, attr.Get16(0)|attrStencil) }\n")
+		fmt.Fprintf(g, "zbuf.Add32(1)\n")
+		fmt.Fprintf(g, "attr.Add16(1)\n")
+		fmt.Fprintf(g, "z0 = z0.AddFixed(dz)\n")
+		fmt.Fprintf(g, "}\n")
+		fmt.Fprintf(g, "return\n")
+		fmt.Fprintf(g, "}\n")
+	}
+
 	fmt.Fprintf(g, "for x:=x0; x<x1; x++ {\n")
 
-	// z-buffer check
+	if cfg.FillMode == fillerconfig.FillModeWireframe {
+		fmt.Fprintf(g, "// wireframe: skip pixels within the edges\n")
+		fmt.Fprintf(g, "if x >= wl && x < wr { goto next }\n")
+	}
+
+	if cfg.ColorMode == fillerconfig.ColorModeShadow {
+		// Other shadow polygons are only drawn where the stencil was set by
+		// the mask, and never over pixels of a polygon with the same ID
+		// (so that an object does not cast a shadow onto itself).
+		// The stencil is consumed in the process.
+		fmt.Fprintf(g, "// stencil check\n")
+		fmt.Fprintf(g, "if attr.Get16(0)&attrStencil == 0 { goto next }\n")
+		fmt.Fprintf(g, "attr.Set16(0, attr.Get16(0)&^attrStencil)\n")
+		fmt.Fprintf(g, "if attr.Get16(0)&attrPolyID == polyid { goto next }\n")
+	}
+
+	// z-buffer check: either less than, or equal within a margin
 	fmt.Fprintf(g, "// zbuffer check\n")
-	fmt.Fprintf(g, "if z0.V >= int32(zbuf.Get32(0)) { goto next }\n")
+	fmt.Fprintf(g, "if zequal {\n")
+	fmt.Fprintf(g, "if d := z0.V - int32(zbuf.Get32(0)); d < -kDepthEqualMargin || d > kDepthEqualMargin { goto next }\n")
+	fmt.Fprintf(g, "} else if z0.V >= int32(zbuf.Get32(0)) { goto next }\n")
 
 	if cfg.TexFormat > 0 {
 		// texture coords
@@ -227,27 +290,73 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 		}
 		fmt.Fprintf(g, "}\n")
 	case fillerconfig.ColorModeShadow:
-		fmt.Fprintf(g, "px = 0\n")
+		// Shadows are drawn with the vertex color, modulated by the texture
+		// if any (usually, they're untextured polygons).
+		fmt.Fprintf(g, "if true {\n")
+		if cfg.TexFormat > 0 {
+			fmt.Fprintf(g, "pxc := newColorFrom555U(px)\n")
+			fmt.Fprintf(g, "pxc = pxc.Modulate(c0)\n")
+			fmt.Fprintf(g, "px = pxc.To555U()\n")
+		} else {
+			fmt.Fprintf(g, "px = c0.To555U()\n")
+		}
 		if cfg.FillMode == fillerconfig.FillModeAlpha {
-			fmt.Fprintf(g, "pxa = polyalpha\n")
+			fmt.Fprintf(g, "pxa = uint8((int32(pxa+1)*int32(polyalpha+1)-1)>>6)\n")
 		}
+		fmt.Fprintf(g, "}\n")
 	}
 
 	fmt.Fprintf(g, "// alpha blending with background\n")
 	if cfg.FillMode == fillerconfig.FillModeAlpha {
 		fmt.Fprintf(g, "if pxa == 0 { goto next }\n")
+		// Alpha test (if enabled) is performed on the final 5-bit alpha,
+		// before blending. Solid polygons skip the test: their alpha is 31,
+		// which only fails with the maximum reference value (corner case
+		// that we ignore).
+		fmt.Fprintf(g, "if int(pxa>>1) <= alpharef { goto next }\n")
+		// A translucent pixel is not drawn over another translucent pixel
+		// of a polygon with the same ID (so that overlapping parts of the
+		// same translucent object are not blended twice).
+		fmt.Fprintf(g, "if pxa < 62 && attr.Get16(0)&(attrTransValid|attrTransID) == transid { goto next }\n")
 		fmt.Fprintf(g, "if true {\n")
-		fmt.Fprintf(g, "bkg := uint16(out.Get32(0))\n")
+		fmt.Fprintf(g, "bkg := out.Get32(0)\n")
 		fmt.Fprintf(g, "bkga := abuf.Get8(0)\n")
-		fmt.Fprintf(g, "if bkga != 0 { px = rgbAlphaMix(px, bkg, pxa>>1) }\n")
+		// Where nothing was drawn yet, the rear plane is transparent: the
+		// pixel is not blended here, but by the 2D engine with the layers
+		// below, using the alpha that we output.
+		fmt.Fprintf(g, "if bkga != 0 && alphablend && bkg&0x80000000 != 0 { px = rgbAlphaMix(px, uint16(bkg), pxa>>1) }\n")
 		fmt.Fprintf(g, "if pxa > bkga { abuf.Set8(0, pxa) }\n")
+		fmt.Fprintf(g, "outa = pxa>>1\n")
+		fmt.Fprintf(g, "if bkg&0x80000000 != 0 && uint8(bkg>>16)&0x1F > outa { outa = uint8(bkg>>16)&0x1F }\n")
 		fmt.Fprintf(g, "}\n")
 	}
 
 	// draw pixel
 	fmt.Fprintf(g, "// draw color and z\n")
-	fmt.Fprintf(g, "out.Set32(0, uint32(px)|0x80000000)\n")
-	fmt.Fprintf(g, "zbuf.Set32(0, uint32(z0.V))\n")
+	if cfg.FillMode == fillerconfig.FillModeAlpha {
+		fmt.Fprintf(g, "out.Set32(0, uint32(px)|uint32(outa)<<16|0x80000000)\n")
+	} else {
+		fmt.Fprintf(g, "out.Set32(0, uint32(px)|0x1F<<16|0x80000000)\n")
+	}
+	if cfg.FillMode == fillerconfig.FillModeAlpha {
+		// Translucent pixels update the depth buffer only if requested by
+		// the polygon attributes; pixels that end up being fully opaque
+		// are handled like those of opaque polygons.
+		fmt.Fprintf(g, "if pxa < 62 {\n")
+		fmt.Fprintf(g, "if transdepth { zbuf.Set32(0, uint32(z0.V)) }\n")
+		fmt.Fprintf(g, "attr.Set16(0, attr.Get16(0)&^attrTransID | transid)\n")
+		fmt.Fprintf(g, "} else {\n")
+		fmt.Fprintf(g, "zbuf.Set32(0, uint32(z0.V))\n")
+		if cfg.ColorMode != fillerconfig.ColorModeShadow {
+			fmt.Fprintf(g, "attr.Set16(0, attr.Get16(0)&^attrPolyID | polyid)\n")
+		}
+		fmt.Fprintf(g, "}\n")
+	} else {
+		fmt.Fprintf(g, "zbuf.Set32(0, uint32(z0.V))\n")
+		if cfg.ColorMode != fillerconfig.ColorModeShadow {
+			fmt.Fprintf(g, "attr.Set16(0, attr.Get16(0)&^attrPolyID | polyid)\n")
+		}
+	}
 
 	// Pixel loop footer
 	fmt.Fprintf(g, "next:\n")
@@ -256,6 +365,7 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	if cfg.FillMode == fillerconfig.FillModeAlpha {
 		fmt.Fprintf(g, "abuf.Add8(1)\n")
 	}
+	fmt.Fprintf(g, "attr.Add16(1)\n")
 	fmt.Fprintf(g, "z0 = z0.AddFixed(dz)\n")
 	fmt.Fprintf(g, "c0 = c0.AddDelta(dc)\n")
 	if cfg.TexFormat > 0 {
@@ -297,7 +407,7 @@ func (g *Generator) Run() {
 		} else {
 			dups[i] = i
 			digests[sum] = i
-			fmt.Fprintf(g.out, "func (e3d *HwEngine3d) filler_%03x(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {\n", i)
+			fmt.Fprintf(g.out, "func (e3d *HwEngine3d) filler_%03x(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line, attr gfx.Line) {\n", i)
 			fmt.Fprintf(g.out, "// %+v\n", cfg)
 			g.out.Write(buf.Bytes())
 			fmt.Fprintf(g.out, "}\n\n")
@@ -305,7 +415,7 @@ func (g *Generator) Run() {
 	}
 
 	g.Writer = g.out
-	fmt.Fprintf(g, "var polygonFillerTable = [%d]func(*HwEngine3d,*Polygon,gfx.Line,gfx.Line,gfx.Line) {\n",
+	fmt.Fprintf(g, "var polygonFillerTable = [%d]func(*HwEngine3d,*Polygon,gfx.Line,gfx.Line,gfx.Line,gfx.Line) {\n",
 		fillerconfig.FillerKeyMax)
 
 	for i := uint(0); i < fillerconfig.FillerKeyMax; i++ {
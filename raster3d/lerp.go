@@ -10,6 +10,12 @@ type lerp struct {
 	cur   int32
 	delta [2]int32
 	start int32
+
+	// Value at the line of the middle vertex, where the second delta
+	// begins. For edges that end at the middle vertex, this is the exact
+	// vertex value rather than the accumulated one, so that adjacent
+	// polygons sharing the next edge compute exactly the same values.
+	mid int32
 }
 
 func newLerp(start emu.Fixed22, d0 emu.Fixed22, d1 emu.Fixed22) lerp {
@@ -24,6 +30,18 @@ func newLerpFromInt(start int32, d0 int32, d1 int32) lerp {
 	return lerp{start: start, delta: [2]int32{d0, d1}}
 }
 
+// Compute the delta to step from a to b in n steps. Intermediate
+// calculations are done in 64-bit, so that the difference between two
+// large values can't overflow. If n is zero, the full difference is
+// returned.
+func lerpDelta(a, b int32, n int32) int32 {
+	d := int64(b) - int64(a)
+	if n > 0 {
+		d /= int64(n)
+	}
+	return int32(d)
+}
+
 func (l *lerp) Reset() {
 	l.cur = l.start
 }
@@ -44,6 +62,16 @@ func (l *lerp) Next(didx int) {
 	l.cur = l.cur + l.delta[didx]
 }
 
+// Advance moves the interpolator n steps forward, using the specified delta
+func (l *lerp) Advance(didx int, n int) {
+	l.cur += l.delta[didx] * int32(n)
+}
+
+// Mid moves the interpolator to the line of the middle vertex
+func (l *lerp) Mid() {
+	l.cur = l.mid
+}
+
 func (l lerp) String() string {
 	return fmt.Sprintf("lerp(%v (%v,%v) [%v])",
 		emu.Fixed22{V: l.cur}, emu.Fixed22{V: l.delta[0]}, emu.Fixed22{V: l.delta[1]}, emu.Fixed22{V: l.start})
@@ -2,17 +2,30 @@ package raster3d
 
 import "ndsemu/emu"
 
-// Swap buffers (marker of end-of-frame, with double-buffering)
+// Swap buffers (marker of end-of-frame, with double-buffering). The flags
+// are the SWAP_BUFFERS parameter, and apply to the frame being closed.
 type Primitive_SwapBuffers struct {
-	AlphaYSort bool
-	WBuffering bool
+	ManualSort bool // bit 0: don't Y-sort translucent polygons
+	WBuffering bool // bit 1: use W instead of Z for depth buffering
 }
 
-// New viewport, in pixel coordinates (0-255 / 0-191)
+// New viewport, as specified in the VIEWPORT command: (VX0,VY0) is the
+// bottom-left pixel and (VX1,VY1) is the top-right pixel, both inclusive,
+// with Y growing upward (0 is the bottom line of the screen).
 type Primitive_SetViewport struct {
 	VX0, VY0, VX1, VY1 int
 }
 
+// ScreenRect returns the viewport as a rectangle in screen coordinates (Y
+// growing downward): the top-left pixel and the size.
+func (vp Primitive_SetViewport) ScreenRect() (x, y, w, h int) {
+	x = vp.VX0
+	y = 191 - vp.VY1
+	w = (vp.VX1 - vp.VX0 + 1) & 0x1FF
+	h = (vp.VY1 - vp.VY0 + 1) & 0xFF
+	return
+}
+
 // New vertex to be pushed in Vertex RAM, with coordinates in
 // clip space (after model-view-proj)
 type Primitive_Vertex struct {
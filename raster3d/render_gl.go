@@ -0,0 +1,622 @@
+//go:build gl
+// +build gl
+
+package raster3d
+
+/*
+#cgo LDFLAGS: -lEGL -lGLESv2
+#include <EGL/egl.h>
+#include <EGL/eglext.h>
+#include <GLES2/gl2.h>
+#include <GLES2/gl2ext.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+// Create an offscreen OpenGL ES 2 context. Rendering always happens into a
+// framebuffer object, so a tiny pbuffer surface is enough to make the
+// context current.
+static const char *glrSetup(EGLDisplay *pdpy, EGLSurface *psurf, EGLContext *pctx) {
+	static const EGLint cfgattr[] = {
+		EGL_SURFACE_TYPE, EGL_PBUFFER_BIT,
+		EGL_RENDERABLE_TYPE, EGL_OPENGL_ES2_BIT,
+		EGL_RED_SIZE, 8, EGL_GREEN_SIZE, 8, EGL_BLUE_SIZE, 8, EGL_ALPHA_SIZE, 8,
+		EGL_NONE,
+	};
+	static const EGLint surfattr[] = { EGL_WIDTH, 16, EGL_HEIGHT, 16, EGL_NONE };
+	static const EGLint ctxattr[] = { EGL_CONTEXT_CLIENT_VERSION, 2, EGL_NONE };
+	EGLConfig cfg;
+	EGLint ncfg;
+
+	EGLDisplay dpy = eglGetDisplay(EGL_DEFAULT_DISPLAY);
+	if (dpy == EGL_NO_DISPLAY || !eglInitialize(dpy, NULL, NULL)) {
+		// No display server: try Mesa's surfaceless platform
+		PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+			(PFNEGLGETPLATFORMDISPLAYEXTPROC)eglGetProcAddress("eglGetPlatformDisplayEXT");
+		if (getPlatformDisplay)
+			dpy = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+		if (dpy == EGL_NO_DISPLAY || !eglInitialize(dpy, NULL, NULL))
+			return "cannot initialize EGL display";
+	}
+	if (!eglBindAPI(EGL_OPENGL_ES_API))
+		return "OpenGL ES not supported";
+	if (!eglChooseConfig(dpy, cfgattr, &cfg, 1, &ncfg) || ncfg == 0)
+		return "no suitable EGL config";
+	EGLSurface surf = eglCreatePbufferSurface(dpy, cfg, surfattr);
+	if (surf == EGL_NO_SURFACE)
+		return "cannot create EGL surface";
+	EGLContext ctx = eglCreateContext(dpy, cfg, EGL_NO_CONTEXT, ctxattr);
+	if (ctx == EGL_NO_CONTEXT)
+		return "cannot create OpenGL ES context";
+	if (!eglMakeCurrent(dpy, surf, surf, ctx))
+		return "cannot activate OpenGL ES context";
+	*pdpy = dpy; *psurf = surf; *pctx = ctx;
+	return NULL;
+}
+
+static void glrTeardown(EGLDisplay dpy, EGLSurface surf, EGLContext ctx) {
+	eglMakeCurrent(dpy, EGL_NO_SURFACE, EGL_NO_SURFACE, EGL_NO_CONTEXT);
+	eglDestroyContext(dpy, ctx);
+	eglDestroySurface(dpy, surf);
+	eglTerminate(dpy);
+}
+
+static void glrAttrib(GLuint idx, GLint size, GLsizei stride, uintptr_t off) {
+	glEnableVertexAttribArray(idx);
+	glVertexAttribPointer(idx, size, GL_FLOAT, GL_FALSE, stride, (const void *)off);
+}
+
+static GLuint glrCompile(GLenum type, const char *src) {
+	GLint ok;
+	GLuint sh = glCreateShader(type);
+	glShaderSource(sh, 1, &src, NULL);
+	glCompileShader(sh);
+	glGetShaderiv(sh, GL_COMPILE_STATUS, &ok);
+	if (!ok) {
+		glDeleteShader(sh);
+		return 0;
+	}
+	return sh;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"ndsemu/raster3d/fillerconfig"
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+const glrVertexShader = `
+attribute vec4 aPos;
+attribute vec4 aColor;
+attribute vec2 aTex;
+varying vec4 vColor;
+varying vec2 vTex;
+void main() {
+	// aPos.w is the clip-space W, used for perspective-correct interpolation
+	gl_Position = vec4(aPos.xyz * aPos.w, aPos.w);
+	vColor = aColor;
+	vTex = aTex;
+}
+`
+
+const glrFragmentShader = `
+precision mediump float;
+uniform sampler2D uTex;
+uniform sampler2D uToon;
+uniform int uTextured;
+uniform int uMode;
+uniform float uAlphaRef;
+varying vec4 vColor;
+varying vec2 vTex;
+void main() {
+	vec4 t = vec4(1.0);
+	if (uTextured != 0)
+		t = texture2D(uTex, vTex);
+	vec4 c;
+	if (uMode == 1) {
+		// decal
+		c = vec4(mix(vColor.rgb, t.rgb, t.a), vColor.a);
+	} else if (uMode == 2 || uMode == 4) {
+		// toon / highlight: vertex red selects the toon table entry
+		vec3 toon = texture2D(uToon, vec2(vColor.r * (31.0/32.0) + 1.0/64.0, 0.5)).rgb;
+		c = vec4(t.rgb * toon, t.a * vColor.a);
+		if (uMode == 4)
+			c.rgb = min(c.rgb + toon, 1.0);
+	} else {
+		// modulation
+		c = t * vColor;
+	}
+	if (c.a <= 0.0 || floor(c.a * 31.0 + 0.5) <= uAlphaRef)
+		discard;
+	gl_FragColor = c;
+}
+`
+
+// Number of floats per vertex: position (x,y,z,w), color (rgba), texture (s,t)
+const glrVertexSize = 10
+
+// Key identifying a texture (with its sampling parameters)
+type glrTexKey struct {
+	off, pal      uint32
+	width, height uint32
+	format        TexFormat
+	flags         TexFlags
+	transparency  bool
+}
+
+// An uploaded texture, kept across frames as long as it's used and its
+// VRAM slots are not modified (see texDeps)
+type glrTexture struct {
+	id    C.GLuint
+	deps  texDeps
+	frame uint32
+}
+
+// A batch of consecutive polygons that share the same rendering state
+type glrBatch struct {
+	mode      uint32 // GL_TRIANGLES or GL_LINES
+	first     int    // first vertex
+	count     int    // number of vertices
+	tex       C.GLuint
+	colorMode uint
+	blend     bool // translucent polygon (always drawn with blending enabled)
+	mix       bool // translucent polygon is blended with the background
+	depthMask bool
+	depthFunc C.GLenum
+}
+
+type glrRequest struct {
+	e3d   *HwEngine3d
+	polys []Polygon
+	out   *[192][256 * 4]byte
+}
+
+// glRenderer is an OpenGL ES 2 backend. As OpenGL contexts are bound to an
+// OS thread, all GL calls are made by a dedicated goroutine locked to its
+// thread; Render just hands it the frame and waits for the result.
+type glRenderer struct {
+	scale int
+	w, h  int
+
+	reqCh  chan glrRequest
+	doneCh chan struct{}
+
+	// The following fields are only accessed by the GL goroutine
+	dpy  C.EGLDisplay
+	surf C.EGLSurface
+	ctx  C.EGLContext
+
+	fbo, colorTex, depthRb C.GLuint
+	prog, vbo, toonTex     C.GLuint
+
+	uTextured, uMode, uAlphaRef C.GLint
+
+	verts    []float32
+	batches  []glrBatch
+	textures map[glrTexKey]*glrTexture
+	frame    uint32
+	pixels   []byte
+}
+
+func newGLRenderer(scale int) (renderer, error) {
+	r := &glRenderer{
+		scale:    scale,
+		w:        256 * scale,
+		h:        192 * scale,
+		reqCh:    make(chan glrRequest),
+		doneCh:   make(chan struct{}),
+		textures: make(map[glrTexKey]*glrTexture),
+	}
+
+	errCh := make(chan error)
+	go r.loop(errCh)
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *glRenderer) Render(e3d *HwEngine3d, polys []Polygon, out *[192][256 * 4]byte) {
+	r.reqCh <- glrRequest{e3d, polys, out}
+	<-r.doneCh
+}
+
+func (r *glRenderer) Close() {
+	close(r.reqCh)
+	<-r.doneCh
+}
+
+func (r *glRenderer) loop(errCh chan error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := r.init(); err != nil {
+		errCh <- err
+		return
+	}
+	errCh <- nil
+
+	for req := range r.reqCh {
+		r.render(req.e3d, req.polys)
+		r.readback(req.out)
+		r.doneCh <- struct{}{}
+	}
+
+	C.glDeleteFramebuffers(1, &r.fbo)
+	C.glDeleteTextures(1, &r.colorTex)
+	C.glDeleteRenderbuffers(1, &r.depthRb)
+	C.glDeleteTextures(1, &r.toonTex)
+	for _, t := range r.textures {
+		C.glDeleteTextures(1, &t.id)
+	}
+	C.glDeleteBuffers(1, &r.vbo)
+	C.glDeleteProgram(r.prog)
+	C.glrTeardown(r.dpy, r.surf, r.ctx)
+	r.doneCh <- struct{}{}
+}
+
+func (r *glRenderer) init() error {
+	if err := C.glrSetup(&r.dpy, &r.surf, &r.ctx); err != nil {
+		return errors.New("OpenGL renderer: " + C.GoString(err))
+	}
+
+	// Color target is a texture (RGBA8 renderbuffers are an extension in
+	// GLES2). Depth uses 24 bits if available, as the NDS does.
+	C.glGenTextures(1, &r.colorTex)
+	C.glBindTexture(C.GL_TEXTURE_2D, r.colorTex)
+	C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA, C.GLsizei(r.w), C.GLsizei(r.h), 0,
+		C.GL_RGBA, C.GL_UNSIGNED_BYTE, nil)
+	C.glGenFramebuffers(1, &r.fbo)
+	C.glBindFramebuffer(C.GL_FRAMEBUFFER, r.fbo)
+	C.glFramebufferTexture2D(C.GL_FRAMEBUFFER, C.GL_COLOR_ATTACHMENT0, C.GL_TEXTURE_2D, r.colorTex, 0)
+	C.glGenRenderbuffers(1, &r.depthRb)
+	C.glBindRenderbuffer(C.GL_RENDERBUFFER, r.depthRb)
+	for _, fmt := range []C.GLenum{C.GL_DEPTH_COMPONENT24_OES, C.GL_DEPTH_COMPONENT16} {
+		C.glRenderbufferStorage(C.GL_RENDERBUFFER, fmt, C.GLsizei(r.w), C.GLsizei(r.h))
+		C.glFramebufferRenderbuffer(C.GL_FRAMEBUFFER, C.GL_DEPTH_ATTACHMENT, C.GL_RENDERBUFFER, r.depthRb)
+		if C.glCheckFramebufferStatus(C.GL_FRAMEBUFFER) == C.GL_FRAMEBUFFER_COMPLETE {
+			break
+		}
+	}
+	if C.glCheckFramebufferStatus(C.GL_FRAMEBUFFER) != C.GL_FRAMEBUFFER_COMPLETE {
+		return errors.New("OpenGL renderer: cannot create framebuffer")
+	}
+
+	// Translucent pixels keep the maximum alpha, which needs min/max blending
+	ext := C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_EXTENSIONS))))
+	if !strings.Contains(" "+ext+" ", " GL_EXT_blend_minmax ") {
+		return errors.New("OpenGL renderer: GL_EXT_blend_minmax not supported")
+	}
+
+	vsrc, fsrc := C.CString(glrVertexShader), C.CString(glrFragmentShader)
+	defer C.free(unsafe.Pointer(vsrc))
+	defer C.free(unsafe.Pointer(fsrc))
+	vs := C.glrCompile(C.GL_VERTEX_SHADER, vsrc)
+	fs := C.glrCompile(C.GL_FRAGMENT_SHADER, fsrc)
+	if vs == 0 || fs == 0 {
+		return errors.New("OpenGL renderer: cannot compile shaders")
+	}
+	r.prog = C.glCreateProgram()
+	C.glAttachShader(r.prog, vs)
+	C.glAttachShader(r.prog, fs)
+	for i, name := range []string{"aPos", "aColor", "aTex"} {
+		cname := C.CString(name)
+		C.glBindAttribLocation(r.prog, C.GLuint(i), cname)
+		C.free(unsafe.Pointer(cname))
+	}
+	C.glLinkProgram(r.prog)
+	C.glDeleteShader(vs)
+	C.glDeleteShader(fs)
+	var ok C.GLint
+	C.glGetProgramiv(r.prog, C.GL_LINK_STATUS, &ok)
+	if ok == 0 {
+		return errors.New("OpenGL renderer: cannot link shaders")
+	}
+	C.glUseProgram(r.prog)
+
+	uniform := func(name string) C.GLint {
+		cname := C.CString(name)
+		defer C.free(unsafe.Pointer(cname))
+		return C.glGetUniformLocation(r.prog, cname)
+	}
+	r.uTextured = uniform("uTextured")
+	r.uMode = uniform("uMode")
+	r.uAlphaRef = uniform("uAlphaRef")
+	C.glUniform1i(uniform("uTex"), 0)
+	C.glUniform1i(uniform("uToon"), 1)
+
+	C.glGenBuffers(1, &r.vbo)
+	C.glBindBuffer(C.GL_ARRAY_BUFFER, r.vbo)
+	const stride = glrVertexSize * 4
+	C.glrAttrib(0, 4, stride, 0)
+	C.glrAttrib(1, 4, stride, 4*4)
+	C.glrAttrib(2, 2, stride, 8*4)
+
+	C.glGenTextures(1, &r.toonTex)
+	C.glActiveTexture(C.GL_TEXTURE1)
+	C.glBindTexture(C.GL_TEXTURE_2D, r.toonTex)
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_MIN_FILTER, C.GL_NEAREST)
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_MAG_FILTER, C.GL_NEAREST)
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_WRAP_S, C.GL_CLAMP_TO_EDGE)
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_WRAP_T, C.GL_CLAMP_TO_EDGE)
+	C.glActiveTexture(C.GL_TEXTURE0)
+
+	C.glViewport(0, 0, C.GLsizei(r.w), C.GLsizei(r.h))
+	r.pixels = make([]byte, r.w*r.h*4)
+
+	mod3d.Infof("OpenGL renderer: %s, %s (scale: %d)",
+		C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_RENDERER)))),
+		C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_VERSION)))), r.scale)
+	return nil
+}
+
+// Return the GL texture for the polygon, uploading it if it was never used
+// before, or if its VRAM slots were modified since it was uploaded.
+func (r *glRenderer) texture(e3d *HwEngine3d, tex *Texture) C.GLuint {
+	key := glrTexKey{tex.VramTexOffset, tex.VramPalOffset, tex.Width, tex.Height,
+		tex.Format, tex.Flags, tex.Transparency}
+	deps := e3d.texDeps(tex)
+	t := r.textures[key]
+	if t != nil && t.deps == deps {
+		t.frame = r.frame
+		return t.id
+	}
+	if t == nil {
+		t = &glrTexture{}
+		C.glGenTextures(1, &t.id)
+		r.textures[key] = t
+	}
+	t.deps, t.frame = deps, r.frame
+
+	wrap := func(repeat, flip TexFlags) C.GLint {
+		switch {
+		case tex.Flags&repeat == 0:
+			return C.GL_CLAMP_TO_EDGE
+		case tex.Flags&flip != 0:
+			return C.GL_MIRRORED_REPEAT
+		default:
+			return C.GL_REPEAT
+		}
+	}
+
+	data := e3d.decodeTexture(tex)
+	C.glBindTexture(C.GL_TEXTURE_2D, t.id)
+	C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA, C.GLsizei(tex.Width), C.GLsizei(tex.Height), 0,
+		C.GL_RGBA, C.GL_UNSIGNED_BYTE, unsafe.Pointer(&data[0]))
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_MIN_FILTER, C.GL_NEAREST)
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_MAG_FILTER, C.GL_NEAREST)
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_WRAP_S, wrap(TexSRepeat, TexSFlip))
+	C.glTexParameteri(C.GL_TEXTURE_2D, C.GL_TEXTURE_WRAP_T, wrap(TexTRepeat, TexTFlip))
+	return t.id
+}
+
+func (r *glRenderer) addVertex(v *Vertex, alpha float32, tex *Texture) {
+	w := float32(v.cw.V) / 4096
+	if w <= 0 {
+		w = 1
+	}
+	var s, t float32
+	if tex.Width != 0 && tex.Height != 0 {
+		s = float32(v.s.V) / 4096 / float32(tex.Width)
+		t = float32(v.t.V) / 4096 / float32(tex.Height)
+	}
+	r.verts = append(r.verts,
+		float32(v.x.V)/(128*4096)-1,
+		1-float32(v.y.V)/(96*4096),
+		float32(v.depth)/0xFFFFFF*2-1,
+		w,
+		float32(v.rgb.R())/63,
+		float32(v.rgb.G())/63,
+		float32(v.rgb.B())/63,
+		alpha,
+		s, t)
+}
+
+// Convert the polygon list into a draw list: a vertex buffer and a list of
+// batches sharing the same rendering state.
+func (r *glRenderer) buildDrawList(e3d *HwEngine3d, polys []Polygon) {
+	r.verts = r.verts[:0]
+	r.batches = r.batches[:0]
+
+	for idx := range polys {
+		poly := &polys[idx]
+		cmode := poly.flags.ColorMode()
+		if cmode == fillerconfig.ColorModeShadow && poly.flags.ID() == 0 {
+			// Shadow mask polygons only update the stencil buffer, which
+			// is not emulated: skip them, or they would cover the scene.
+			continue
+		}
+		if cmode == fillerconfig.ColorModeToon && e3d.cnt.Highlight() {
+			cmode = fillerconfig.ColorModeHighlight
+		}
+
+		b := glrBatch{
+			mode:      C.GL_TRIANGLES,
+			first:     len(r.verts) / glrVertexSize,
+			colorMode: cmode,
+			depthMask: true,
+			depthFunc: C.GL_LESS,
+		}
+		if e3d.cnt.TexMapping() && poly.tex.Format != TexNone {
+			b.tex = r.texture(e3d, &poly.tex)
+		}
+		if poly.flags.DepthEqual() {
+			b.depthFunc = C.GL_LEQUAL
+		}
+		if poly.Translucent() {
+			b.blend = true
+			b.mix = e3d.cnt.AlphaBlending()
+			b.depthMask = poly.flags.TransDepthUpdate()
+		}
+
+		alpha := float32(poly.flags.Alpha()) / 31
+		v := poly.vtx
+		if poly.line {
+			// Degenerate polygon: draw it as a line covering all vertices
+			b.mode = C.GL_LINES
+			if poly.flags.Alpha() == 0 {
+				alpha = 1
+			}
+			for _, e := range [3][2]int{{0, 1}, {1, 2}, {2, 0}} {
+				r.addVertex(v[e[0]], alpha, &poly.tex)
+				r.addVertex(v[e[1]], alpha, &poly.tex)
+			}
+		} else if poly.flags.Alpha() == 0 {
+			// Wireframe: only draw the edges of the original polygon
+			b.mode = C.GL_LINES
+			alpha = 1
+			for _, e := range [3][2]int{{0, 1}, {1, 2}, {2, 0}} {
+				if poly.outerEdge(v[e[0]], v[e[1]]) {
+					r.addVertex(v[e[0]], alpha, &poly.tex)
+					r.addVertex(v[e[1]], alpha, &poly.tex)
+				}
+			}
+		} else {
+			for _, vtx := range v {
+				r.addVertex(vtx, alpha, &poly.tex)
+			}
+		}
+		b.count = len(r.verts)/glrVertexSize - b.first
+		if b.count == 0 {
+			continue
+		}
+
+		// Merge with the previous batch if the state is the same
+		if n := len(r.batches); n > 0 {
+			last := &r.batches[n-1]
+			if last.first+last.count == b.first {
+				b2 := b
+				b2.first, b2.count = last.first, last.count
+				if b2 == *last {
+					last.count += b.count
+					continue
+				}
+			}
+		}
+		r.batches = append(r.batches, b)
+	}
+}
+
+func (r *glRenderer) render(e3d *HwEngine3d, polys []Polygon) {
+	r.buildDrawList(e3d, polys)
+
+	// Toon table, as a 32x1 texture
+	var toon [32 * 4]byte
+	for i := 0; i < 32; i++ {
+		c := uint16(e3d.ToonTable.Data[i*2]) | uint16(e3d.ToonTable.Data[i*2+1])<<8
+		rgb555ToRGBA(toon[i*4:], c)
+		toon[i*4+3] = 0xFF
+	}
+	C.glActiveTexture(C.GL_TEXTURE1)
+	C.glBindTexture(C.GL_TEXTURE_2D, r.toonTex)
+	C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA, 32, 1, 0, C.GL_RGBA, C.GL_UNSIGNED_BYTE, unsafe.Pointer(&toon[0]))
+	C.glActiveTexture(C.GL_TEXTURE0)
+
+	C.glBindFramebuffer(C.GL_FRAMEBUFFER, r.fbo)
+	C.glDepthMask(C.GL_TRUE)
+	C.glClearColor(0, 0, 0, 0)
+	C.glClearDepthf(1)
+	C.glClear(C.GL_COLOR_BUFFER_BIT | C.GL_DEPTH_BUFFER_BIT)
+	C.glEnable(C.GL_DEPTH_TEST)
+	C.glLineWidth(C.GLfloat(r.scale))
+
+	C.glUniform1f(r.uAlphaRef, C.GLfloat(e3d.alphaRef))
+	if len(r.verts) > 0 {
+		C.glBufferData(C.GL_ARRAY_BUFFER, C.GLsizeiptr(len(r.verts)*4), unsafe.Pointer(&r.verts[0]), C.GL_STREAM_DRAW)
+	}
+	for _, b := range r.batches {
+		if b.tex != 0 {
+			C.glBindTexture(C.GL_TEXTURE_2D, b.tex)
+			C.glUniform1i(r.uTextured, 1)
+		} else {
+			C.glUniform1i(r.uTextured, 0)
+		}
+		C.glUniform1i(r.uMode, C.GLint(b.colorMode))
+		if b.blend {
+			// Color is blended as usual, while alpha keeps the maximum of
+			// the source and destination alpha, like the NDS does. The
+			// color is thus premultiplied by the final alpha, so that
+			// translucent pixels drawn on the empty background can be
+			// recovered in readback (the software rasterizer doesn't blend
+			// them with the clear color).
+			C.glEnable(C.GL_BLEND)
+			if b.mix {
+				C.glBlendEquationSeparate(C.GL_FUNC_ADD, C.GL_MAX_EXT)
+				C.glBlendFunc(C.GL_SRC_ALPHA, C.GL_ONE_MINUS_SRC_ALPHA)
+			} else {
+				C.glBlendEquation(C.GL_FUNC_ADD)
+				C.glBlendFuncSeparate(C.GL_SRC_ALPHA, C.GL_ZERO, C.GL_ONE, C.GL_ZERO)
+			}
+		} else {
+			C.glDisable(C.GL_BLEND)
+		}
+		if b.depthMask {
+			C.glDepthMask(C.GL_TRUE)
+		} else {
+			C.glDepthMask(C.GL_FALSE)
+		}
+		C.glDepthFunc(b.depthFunc)
+		C.glDrawArrays(C.GLenum(b.mode), C.GLint(b.first), C.GLsizei(b.count))
+	}
+
+	// Release textures that were not used recently
+	for key, t := range r.textures {
+		if r.frame-t.frame > kTexCacheMaxAge {
+			C.glDeleteTextures(1, &t.id)
+			delete(r.textures, key)
+		}
+	}
+	r.frame++
+}
+
+// Read back the framebuffer, and downsample it to the native resolution
+func (r *glRenderer) readback(out *[192][256 * 4]byte) {
+	C.glReadPixels(0, 0, C.GLsizei(r.w), C.GLsizei(r.h), C.GL_RGBA, C.GL_UNSIGNED_BYTE, unsafe.Pointer(&r.pixels[0]))
+
+	scale := r.scale
+	for y := 0; y < 192; y++ {
+		line := out[y][:]
+		for x := 0; x < 256; x++ {
+			var rs, gs, bs, as, n uint32
+			for sy := 0; sy < scale; sy++ {
+				// OpenGL framebuffers are bottom-up
+				row := (r.h - 1 - (y*scale + sy)) * r.w
+				for sx := 0; sx < scale; sx++ {
+					p := r.pixels[(row+x*scale+sx)*4:]
+					if p[3] == 0 {
+						continue
+					}
+					// Undo the premultiplication of translucent pixels
+					a := uint32(p[3])
+					rs += uint32(p[0]) * 255 / a
+					gs += uint32(p[1]) * 255 / a
+					bs += uint32(p[2]) * 255 / a
+					as += a
+					n++
+				}
+			}
+
+			var pix uint32
+			if n > 0 {
+				rr, gg, bb, aa := rs/n, gs/n, bs/n, (as/n*31+127)/255
+				if rr > 255 {
+					rr = 255
+				}
+				if gg > 255 {
+					gg = 255
+				}
+				if bb > 255 {
+					bb = 255
+				}
+				pix = rr>>3 | (gg>>3)<<5 | (bb>>3)<<10 | aa<<16 | 0x80000000
+			}
+			line[x*4+0] = uint8(pix)
+			line[x*4+1] = uint8(pix >> 8)
+			line[x*4+2] = uint8(pix >> 16)
+			line[x*4+3] = uint8(pix >> 24)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+//go:build gl
+// +build gl
+
+package raster3d
+
+import (
+	"ndsemu/emu"
+	"ndsemu/emu/gfx"
+	"testing"
+)
+
+func renderScene(t *testing.T, sc *Scene, rend string) gfx.Buffer {
+	e3d := NewHwEngine3d()
+	if err := e3d.SetRenderer(rend, 2); err != nil {
+		t.Skip(err)
+	}
+	if err := e3d.LoadScene(sc); err != nil {
+		t.Fatal(err)
+	}
+
+	var lm gfx.LayerManager
+	lm.Cfg = gfx.LayerManagerConfig{
+		Width:     256,
+		Height:    192,
+		ScreenBpp: 4,
+		LayerBpp:  4,
+		Mixer:     func(pixels []uint32, ctx interface{}) uint32 { return pixels[0] },
+	}
+	lm.AddLayer(gfx.LayerFunc{Func: e3d.Draw3D})
+
+	screen := gfx.NewBufferMem(256, 192)
+	lm.BeginFrame()
+	for y := 0; y < 192; y++ {
+		lm.BeginLine(screen.Line(y))
+		lm.EndLine()
+	}
+	lm.EndFrame()
+	return screen
+}
+
+func TestGLRenderer(t *testing.T) {
+	vtx := func(x, y float64) Primitive_Vertex {
+		return Primitive_Vertex{
+			X: emu.Fixed12{V: int32(x * 4096)},
+			Y: emu.Fixed12{V: int32(y * 4096)},
+			W: emu.NewFixed12(1),
+			C: [3]uint8{31, 16, 0},
+		}
+	}
+
+	// Untextured triangle, drawn with vertex color (modulation mode)
+	sc := &Scene{
+		Disp3dCnt: 1 << 3,
+		ToonTable: make([]byte, 0x40),
+		Viewport:  Primitive_SetViewport{0, 0, 255, 191},
+		Cmds: []interface{}{
+			vtx(-0.5, -0.5), vtx(0.5, -0.5), vtx(0, 0.5),
+			Primitive_Polygon{
+				Vtx:  [4]int{0, 1, 2},
+				Attr: uint32(PFRenderBack | PFRenderFront | 31<<16),
+			},
+			Primitive_SwapBuffers{},
+		},
+	}
+
+	soft := renderScene(t, sc, "soft")
+	gl := renderScene(t, sc, "gl")
+
+	// Coverage must match, except for some pixels along the edges
+	diff := 0
+	for y := 0; y < 192; y++ {
+		for x := 0; x < 256; x++ {
+			if soft.Line(y).Get32(x)&0x80000000 != gl.Line(y).Get32(x)&0x80000000 {
+				diff++
+			}
+		}
+	}
+	if diff > 256 {
+		t.Errorf("too many different pixels: %d", diff)
+	}
+
+	if pix := gl.Line(96).Get32(128); pix != 0x80000000|0x1F<<16|16<<5|31 {
+		t.Errorf("invalid pixel at center: %08x", pix)
+	}
+	if pix := gl.Line(0).Get32(0); pix != 0 {
+		t.Errorf("invalid pixel at corner: %08x", pix)
+	}
+
+	// Same triangle, with a white vertex color and a blue 8x8 direct color
+	// texture
+	tex := make([]byte, 128*1024)
+	for i := 0; i < 64; i++ {
+		emu.Write16LE(tex[i*2:], 0x8000|31<<10)
+	}
+	sc.TexVram[0] = tex
+	sc.Disp3dCnt |= 1 << 0
+	for i := 0; i < 3; i++ {
+		v := sc.Cmds[i].(Primitive_Vertex)
+		v.C = [3]uint8{31, 31, 31}
+		v.S, v.T = emu.NewFixed12(int32(i*4)), emu.NewFixed12(int32(i*2))
+		sc.Cmds[i] = v
+	}
+	poly := sc.Cmds[3].(Primitive_Polygon)
+	poly.Tex = Texture{Width: 8, Height: 8, PitchShift: 3, Format: TexDirect,
+		Flags: TexSRepeat | TexTRepeat}
+	sc.Cmds[3] = poly
+
+	gl = renderScene(t, sc, "gl")
+	if pix := gl.Line(96).Get32(128); pix != 0x80000000|0x1F<<16|31<<10 {
+		t.Errorf("invalid textured pixel at center: %08x", pix)
+	}
+
+	// Translucent triangle: the alpha fed to the 2D mixer must be the same
+	// as the software renderer's
+	poly.Attr = uint32(PFRenderBack | PFRenderFront | 16<<16)
+	sc.Cmds[3] = poly
+	soft = renderScene(t, sc, "soft")
+	gl = renderScene(t, sc, "gl")
+	if ps, pg := soft.Line(96).Get32(128), gl.Line(96).Get32(128); ps != pg {
+		t.Errorf("translucent pixel at center: soft=%08x gl=%08x", ps, pg)
+	}
+}
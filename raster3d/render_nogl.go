@@ -0,0 +1,10 @@
+//go:build !gl
+// +build !gl
+
+package raster3d
+
+import "errors"
+
+func newGLRenderer(scale int) (renderer, error) {
+	return nil, errors.New("OpenGL renderer not available (rebuild with -tags gl)")
+}
@@ -0,0 +1,141 @@
+package raster3d
+
+import (
+	"fmt"
+	"ndsemu/emu/gfx"
+	"sync/atomic"
+)
+
+// A renderer is an alternative backend that rasterizes a whole frame at
+// once, instead of the software polyfillers. Geometry processing (clipping,
+// viewport transform, depth mode selection and polygon sorting) is shared by
+// all backends, and is done by HwEngine3d as primitives are received;
+// renderers get the final polygon list of the frame being drawn.
+type renderer interface {
+	// Render draws the polygons into the output lines, using the same pixel
+	// format of Line3D.
+	Render(e3d *HwEngine3d, polys []Polygon, out *[192][256 * 4]byte)
+
+	// Close releases all the resources held by the renderer.
+	Close()
+}
+
+// SetRenderer selects the backend used to draw 3D frames: "soft" (software
+// rasterizer, the default) or "gl" (OpenGL ES). scale is the internal
+// resolution multiplier used by the OpenGL renderer; the output is
+// downsampled to the native resolution, as the 2D engine composes the 3D
+// layer at 256x192.
+//
+// SetRenderer must not be called while a frame is being drawn; use
+// SwitchRenderer to change backend while the emulation is running.
+func (e3d *HwEngine3d) SetRenderer(name string, scale int) error {
+	var r renderer
+	switch name {
+	case "soft":
+	case "gl":
+		if scale < 1 || scale > 8 {
+			return fmt.Errorf("invalid 3D resolution scale: %d", scale)
+		}
+		var err error
+		if r, err = newGLRenderer(scale); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown 3D renderer: %q", name)
+	}
+
+	if e3d.renderer != nil {
+		e3d.renderer.Close()
+	}
+	e3d.renderer = r
+	e3d.rendererName = name
+	e3d.rendererScale = scale
+	return nil
+}
+
+// Names of the available backends, in the order in which SwitchRenderer
+// cycles through them.
+var rendererNames = []string{"soft", "gl"}
+
+// RendererName returns the name of the backend currently in use.
+func (e3d *HwEngine3d) RendererName() string {
+	if e3d.rendererName == "" {
+		return "soft"
+	}
+	return e3d.rendererName
+}
+
+// SwitchRenderer asks the engine to change backend, starting with the next
+// frame (the current one is completed by the old backend), so that the
+// output of different backends can be compared on the same scene. An empty
+// name selects the backend following the current one. The resolution scale
+// is the one passed to the last SetRenderer. It can be called from any
+// goroutine.
+func (e3d *HwEngine3d) SwitchRenderer(name string) {
+	select {
+	case e3d.switchCh <- name:
+	default:
+		mod3d.Warnf("3D renderer switch already pending, ignoring: %q", name)
+	}
+}
+
+// Called at the beginning of each frame: apply the pending SwitchRenderer
+// (if any). If the new backend cannot be created, the current one is kept.
+func (e3d *HwEngine3d) switchRenderer() {
+	var name string
+	select {
+	case name = <-e3d.switchCh:
+	default:
+		return
+	}
+
+	cur := e3d.RendererName()
+	if name == "" {
+		for i, n := range rendererNames {
+			if n == cur {
+				name = rendererNames[(i+1)%len(rendererNames)]
+				break
+			}
+		}
+	}
+	if name == cur {
+		return
+	}
+
+	scale := e3d.rendererScale
+	if scale == 0 {
+		scale = 1
+	}
+	if err := e3d.SetRenderer(name, scale); err != nil {
+		mod3d.WithField("renderer", cur).Warnf("cannot switch 3D renderer: %v", err)
+		return
+	}
+	mod3d.Warnf("3D renderer: %s", name)
+}
+
+// Draw the current frame with the selected renderer, and copy it into the
+// layer starting from line y.
+func (e3d *HwEngine3d) drawRenderer(ctx *gfx.LayerCtx, y int, out func(y int, dst gfx.Line)) {
+	// Compressed textures are decoded by the texture cache, like in the
+	// software rasterizer.
+	e3d.texCache.Update(e3d.cur.Pram, e3d)
+	polys := e3d.cur.Pram
+	if hidden := atomic.LoadUint32(&e3d.dbgHidden); hidden != 0 {
+		polys = nil
+		for i := range e3d.cur.Pram {
+			if !e3d.cur.Pram[i].debugHidden(hidden) {
+				polys = append(polys, e3d.cur.Pram[i])
+			}
+		}
+	}
+	e3d.renderer.Render(e3d, polys, &e3d.lineBuf)
+
+	for {
+		line := ctx.NextLine()
+		if line.IsNil() {
+			return
+		}
+		out(y, line)
+		y++
+	}
+}
@@ -0,0 +1,175 @@
+package raster3d
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// A Scene is a capture of all the inputs of the 3D engine for a single frame:
+// the primitives sent by the geometry engine (up to and including the
+// SwapBuffers that closes the frame), the rendering registers, and the
+// contents of the texture/palette VRAM.
+//
+// Scenes can be saved to disk while the emulator is running (see
+// RecordNextScene), and later replayed through a HwEngine3d without running
+// the game, to reproduce and regression-test rasterizer bugs.
+type Scene struct {
+	Disp3dCnt     uint32
+	Disp1DotDepth uint16
+	AlphaTestRef  uint16
+	ToonTable     []byte
+
+	// Texture and palette VRAM slots (nil if not mapped)
+	TexVram [4][]byte
+	PalVram [6][]byte
+
+	// Viewport active at the beginning of the scene
+	Viewport Primitive_SetViewport
+
+	// Primitives, in the order in which they were received
+	Cmds []interface{}
+}
+
+// The binary format is a magic string followed by a gob stream containing
+// the Scene. The magic includes a version number, to be bumped whenever
+// the format changes in an incompatible way.
+const cSceneMagic = "NDS3DSC1"
+
+func init() {
+	gob.Register(Primitive_SwapBuffers{})
+	gob.Register(Primitive_SetViewport{})
+	gob.Register(Primitive_Vertex{})
+	gob.Register(Primitive_Polygon{})
+}
+
+func (sc *Scene) Save(fn string) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	w.WriteString(cSceneMagic)
+	if err := gob.NewEncoder(w).Encode(sc); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func LoadScene(fn string) (*Scene, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(cSceneMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != cSceneMagic {
+		return nil, fmt.Errorf("%s: not a 3D scene file", fn)
+	}
+
+	sc := new(Scene)
+	if err := gob.NewDecoder(r).Decode(sc); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn, err)
+	}
+	return sc, nil
+}
+
+// RecordNextScene asks the engine to capture the next complete scene (that
+// is, starting with the next frame), and save it into the specified file.
+// It can be called from any goroutine.
+func (e3d *HwEngine3d) RecordNextScene(fn string) {
+	select {
+	case e3d.recCh <- fn:
+	default:
+		mod3d.Warnf("scene recording already pending, ignoring: %s", fn)
+	}
+}
+
+// Called at each SwapBuffers, before the scene is processed: save the
+// scene being recorded (if any), and check whether a new recording must
+// begin with next frame.
+func (e3d *HwEngine3d) recordSwap() {
+	if sc := e3d.recScene; sc != nil {
+		// Registers and VRAM are sampled at the end of the frame, as this
+		// is when the geometry is complete and ready to be drawn.
+		sc.Disp3dCnt = e3d.Disp3dCnt.Value
+		sc.Disp1DotDepth = e3d.Disp1DotDepth.Value
+		sc.AlphaTestRef = e3d.AlphaTestRef.Value
+		sc.ToonTable = append([]byte(nil), e3d.ToonTable.Data...)
+		for i, s := range e3d.texVram.Slots {
+			if s != nil {
+				sc.TexVram[i] = append([]byte(nil), s...)
+			}
+		}
+		for i, s := range e3d.palVram.Slots {
+			if s != nil {
+				sc.PalVram[i] = append([]byte(nil), s...)
+			}
+		}
+
+		if err := sc.Save(e3d.recFile); err != nil {
+			mod3d.Errorf("cannot save scene: %v", err)
+		} else {
+			mod3d.Infof("scene saved: %s (%d primitives)", e3d.recFile, len(sc.Cmds))
+		}
+		e3d.recScene = nil
+	}
+
+	select {
+	case fn := <-e3d.recCh:
+		e3d.recFile = fn
+		e3d.recScene = &Scene{Viewport: e3d.viewport}
+	default:
+	}
+}
+
+// LoadScene sets up the engine to render a previously recorded scene: the
+// registers and VRAM are restored, and the primitives are processed as
+// if they were sent by the geometry engine; the resulting frame becomes
+// the current one, so that it's drawn by the next Draw3D.
+//
+// This is meant for offline rendering, and must not be used on an engine
+// attached to a running emulator.
+func (e3d *HwEngine3d) LoadScene(sc *Scene) error {
+	if len(sc.Cmds) == 0 {
+		return errors.New("empty scene")
+	}
+	if _, ok := sc.Cmds[len(sc.Cmds)-1].(Primitive_SwapBuffers); !ok {
+		return errors.New("incomplete scene (missing SwapBuffers)")
+	}
+
+	e3d.Disp3dCnt.Value = sc.Disp3dCnt
+	e3d.Disp1DotDepth.Value = sc.Disp1DotDepth
+	e3d.AlphaTestRef.Value = sc.AlphaTestRef
+	copy(e3d.ToonTable.Data, sc.ToonTable)
+
+	var tex VramTextureBank
+	var pal VramTexturePaletteBank
+	copy(tex.Slots[:], sc.TexVram[:])
+	copy(pal.Slots[:], sc.PalVram[:])
+	e3d.SetVram(tex, pal)
+	e3d.BeginFrame()
+
+	e3d.CmdCh <- sc.Viewport
+	for _, cmd := range sc.Cmds {
+		e3d.CmdCh <- cmd
+	}
+
+	// Wait for the SwapBuffers to be processed, and make the scene current
+	next := <-e3d.nextCh
+	e3d.cur.Reset()
+	e3d.pool.Put(e3d.cur)
+	e3d.cur = next
+	return nil
+}
@@ -0,0 +1,84 @@
+package raster3d
+
+import (
+	"io/ioutil"
+	"ndsemu/emu"
+	"ndsemu/emu/gfx"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSceneReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scene3d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	vtx := func(x, y float64) Primitive_Vertex {
+		return Primitive_Vertex{
+			X: emu.Fixed12{V: int32(x * 4096)},
+			Y: emu.Fixed12{V: int32(y * 4096)},
+			W: emu.NewFixed12(1),
+		}
+	}
+
+	sc := &Scene{
+		ToonTable: make([]byte, 0x40),
+		Viewport:  Primitive_SetViewport{0, 0, 255, 191},
+		Cmds: []interface{}{
+			vtx(-0.5, -0.5), vtx(0.5, -0.5), vtx(0, 0.5),
+			Primitive_Polygon{
+				Vtx:  [4]int{0, 1, 2},
+				Attr: uint32(PFRenderBack | PFRenderFront | 31<<16),
+			},
+			Primitive_SwapBuffers{},
+		},
+	}
+
+	fn := filepath.Join(dir, "scene.bin")
+	if err := sc.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	sc2, err := LoadScene(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sc2.Cmds) != len(sc.Cmds) {
+		t.Fatalf("invalid number of primitives: %d", len(sc2.Cmds))
+	}
+
+	e3d := NewHwEngine3d()
+	if err := e3d.LoadScene(sc2); err != nil {
+		t.Fatal(err)
+	}
+	if len(e3d.cur.Pram) != 1 {
+		t.Fatalf("invalid number of polygons: %d", len(e3d.cur.Pram))
+	}
+
+	var lm gfx.LayerManager
+	lm.Cfg = gfx.LayerManagerConfig{
+		Width:     256,
+		Height:    192,
+		ScreenBpp: 4,
+		LayerBpp:  4,
+		Mixer:     func(pixels []uint32, ctx interface{}) uint32 { return pixels[0] },
+	}
+	lm.AddLayer(gfx.LayerFunc{Func: e3d.Draw3D})
+
+	screen := gfx.NewBufferMem(256, 192)
+	lm.BeginFrame()
+	for y := 0; y < 192; y++ {
+		lm.BeginLine(screen.Line(y))
+		lm.EndLine()
+	}
+	lm.EndFrame()
+
+	if pix := screen.Line(96).Get32(128); pix&0x80000000 == 0 {
+		t.Errorf("polygon not drawn at center: %08x", pix)
+	}
+	if pix := screen.Line(0).Get32(0); pix != 0 {
+		t.Errorf("invalid pixel at corner: %08x", pix)
+	}
+}
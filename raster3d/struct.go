@@ -15,6 +15,7 @@ const (
 	RVFClipNear
 	RVFClipFar
 	RVFTransformed // vertex has been already transformed to screen space
+	RVFInRam       // vertex has been stored in vertex RAM (and counted in RAM_COUNT)
 
 	RVFClipMask = (RVFClipLeft | RVFClipRight | RVFClipTop | RVFClipBottom | RVFClipNear | RVFClipFar)
 )
@@ -24,7 +25,15 @@ type Vertex struct {
 	cx, cy, cz, cw emu.Fixed12
 
 	// Screen coordinates (fractional part is always zero)
-	x, y, z emu.Fixed12
+	x, y emu.Fixed12
+
+	// Value used for depth buffering: 24-bit unsigned, either the screen Z
+	// (mapped from [-W,W] to [0,0xFFFFFF]) or W (with W-buffering).
+	depth int32
+
+	// 1/W, with kInvWFracBits fractional bits. This is linear in screen
+	// space, so it can be interpolated for perspective correction.
+	invw int32
 
 	// Texture coordinates
 	s, t emu.Fixed12
@@ -41,23 +50,59 @@ type Vertex struct {
 type PolygonFlags uint32
 
 const (
-	PFRenderBack  PolygonFlags = 1 << 6
-	PFRenderFront              = 1 << 7
-	PFQuad                     = 1 << 31
+	PFLightMask        PolygonFlags = 0xF
+	PFRenderBack                    = 1 << 6
+	PFRenderFront                   = 1 << 7
+	PFTransDepthUpdate              = 1 << 11 // translucent pixels update the depth buffer
+	PFFarPlaneRender                = 1 << 12 // polygons crossing the far plane are clipped (rather than hidden)
+	PFRender1Dot                    = 1 << 13
+	PFDepthEqual                    = 1 << 14 // depth test passes if depth is equal (rather than less)
+	PFFog                           = 1 << 15
+	PFQuad                          = 1 << 31
+)
+
+func (f PolygonFlags) Lights() uint           { return uint(f & PFLightMask) }
+func (f PolygonFlags) Alpha() int             { return int(f>>16) & 0x1F }
+func (f PolygonFlags) ColorMode() uint        { return uint(f>>4) & 3 }
+func (f PolygonFlags) ID() int                { return int(f>>24) & 0x3F }
+func (f PolygonFlags) TransDepthUpdate() bool { return f&PFTransDepthUpdate != 0 }
+func (f PolygonFlags) FarPlaneRender() bool   { return f&PFFarPlaneRender != 0 }
+func (f PolygonFlags) DepthEqual() bool       { return f&PFDepthEqual != 0 }
+func (f PolygonFlags) Fog() bool              { return f&PFFog != 0 }
+
+// Per-pixel attributes kept in the attribute buffer while rendering a line
+const (
+	attrPolyID     = 0x003F // polygon ID of the opaque pixel
+	attrStencil    = 0x0080 // stencil bit, set by shadow masks
+	attrTransID    = 0x3F00 // polygon ID of the translucent pixel
+	attrTransValid = 0x4000 // a translucent pixel was drawn (attrTransID is valid)
 )
 
-func (f PolygonFlags) Alpha() int      { return int(f>>16) & 0x1F }
-func (f PolygonFlags) ColorMode() uint { return uint(f>>4) & 3 }
+// With the depth-equal test, depth values are considered equal if they're
+// within this margin (0x200 in 24-bit depth units).
+const kDepthEqualMargin = 0x200 << kDepthFracBits
 
 const (
 	LerpX   = iota // coordinate on screen (X)
-	LerpZ          // depth on screen (Z or W)
+	LerpZ          // depth on screen (Z or W), with kDepthFracBits fractional bits
+	LerpW          // 1/W, with kInvWFracBits fractional bits
 	LerpT          // texture X coordinate (T)
 	LerpS          // texture Y coordinate (S)
 	LerpRGB        // vertex color (RGB)
 	NumLerps
 )
 
+const (
+	// Depth is interpolated with some fractional bits, to avoid accumulating
+	// errors on long edges and spans. 24-bit depth plus fractional bits must
+	// still leave room for the sign in deltas.
+	kDepthFracBits = 6
+
+	// 1/W is computed as (1<<30)/W (with W in 20.12), which fits in 32 bits
+	// for all W values between 1/4096 and the far plane.
+	kInvWFracBits = 18
+)
+
 //go:generate go run gen/genfillers.go -filename polyfillers.go
 
 type Polygon struct {
@@ -72,8 +117,30 @@ type Polygon struct {
 	left  [NumLerps]lerp
 	right [NumLerps]lerp
 
+	// true if the long edge (v0-v2) is on the left
+	longLeft bool
+
+	// true if all vertices of the original polygon are aligned on screen:
+	// the polygon has no area, and it's drawn as a line (or a single pixel)
+	line bool
+
+	// Edges created by splitting the original polygon into triangles
+	inner [2][2]*Vertex
+
+	// Polygons are split into triangles when they're stored, but the
+	// hardware sorts them as a whole: all the triangles share the top/bottom
+	// Y on screen of the original polygon.
+	sortTop, sortBottom int32
+
+	// Span of pixels [x0,x1) to draw on the current line (see setSpan)
+	x0, x1 int32
+
+	// Wireframe polygons: number of pixels to draw for the left and right
+	// edge on the current line
+	wireL, wireR int32
+
 	// polyfiller for this polygon
-	filler func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gfx.Line)
+	filler func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gfx.Line, gfx.Line)
 
 	// texture pointer
 	texptr []byte
@@ -7,27 +7,104 @@ import (
 	"image/png"
 	"ndsemu/emu"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 )
 
-// Cache holding decompressed textures. It is a locked dictionary,
-// indexed by the texture address in VRAM, and containing the texture
-// raw bits.
-// FIXME: possibile improvements:
-//   * As a key, use a fast hash of texture bits (eg: crc64); this would
-//     allow reusing the same texture across different frames. We need to
-//     benchmark whether it's a net win
-//   * Once we switch to the above, we could use a proper LRU cache to
-//     also decrease pressure on the GC; as things stand, texture buffers
-//     are allocated / deallocated 60 times per second.
+// Cache holding decompressed textures. Textures are decoded the first time
+// they're used, and kept across frames until the VRAM slots they're read
+// from (image and palette) are modified, or they're not used for a while.
+//
+// Polyfillers look up textures by their address in VRAM, so the cache also
+// keeps the list of textures used in the current frame, indexed by address.
 type texCache struct {
 	sync.Mutex
-	data map[uint32][]uint8
+	data    map[uint32][]uint8
+	entries map[texCacheKey]*texCacheEntry
+	frame   uint32
+
+	// Texture dumping (see HwEngine3d.DumpTextures)
+	dumpDir    string
+	dumpBudget *emu.FrameBudget
+}
+
+type texCacheKey struct {
+	off, pal      uint32
+	width, height uint32
+	format        TexFormat
+}
+
+type texCacheEntry struct {
+	buf    []uint8
+	deps   texDeps
+	frame  uint32 // last frame the texture was used
+	dumped bool
+}
+
+// Textures not used for this number of frames are evicted from the cache
+const kTexCacheMaxAge = 60
+
+// Estimated host time needed to save a texture as PNG; a texture is only
+// dumped if there's this much time left in the frame budget, otherwise it's
+// retried the next time it's used.
+const kTexDumpCost = 2 * time.Millisecond
+
+// texDeps records the generation of the VRAM slots that a texture is read
+// from (see VramTextureBank); slots that are not used are left at zero. If
+// the dependencies of a texture change, it must be decoded again.
+type texDeps struct {
+	tex [4]uint32
+	pal [6]uint32
+}
+
+// Bits per texel of each format
+var texBitsPerTexel = [...]uint32{
+	TexA3I5: 8, Tex4: 2, Tex16: 4, Tex256: 8, Tex4x4: 2, TexA5I3: 8, TexDirect: 16,
+}
+
+// Size of the palette used by each format, in bytes
+var texPaletteSize = [...]uint32{
+	TexA3I5:   32 * 2,
+	Tex4:      4 * 2,
+	Tex16:     16 * 2,
+	Tex256:    256 * 2,
+	Tex4x4:    0x4000 * 4, // as addressed by the per-block palette offset
+	TexA5I3:   8 * 2,
+	TexDirect: 0,
+}
+
+// Compute the dependencies of a texture
+func (e3d *HwEngine3d) texDeps(tex *Texture) (deps texDeps) {
+	if tex.Format == TexNone {
+		return
+	}
+	markTex := func(off, size uint32) {
+		for s := off >> 17; s <= (off+size-1)>>17; s++ {
+			deps.tex[s&3] = e3d.texVram.Gen[s&3]
+		}
+	}
+	size := tex.Width * tex.Height * texBitsPerTexel[tex.Format] / 8
+	markTex(tex.VramTexOffset, size)
+	if tex.Format == Tex4x4 {
+		// Per-block data is stored in slot 1 (see decompTex4x4)
+		markTex(128*1024, 128*1024)
+	}
+	if psize := texPaletteSize[tex.Format]; psize > 0 {
+		off := tex.VramPalOffset
+		for s := off >> 14; s <= (off+psize-1)>>14 && s < 6; s++ {
+			deps.pal[s] = e3d.palVram.Gen[s]
+		}
+	}
+	return
 }
 
 func (d *texCache) Reset() {
 	d.Lock()
 	d.data = make(map[uint32][]uint8)
+	if d.entries == nil {
+		d.entries = make(map[texCacheKey]*texCacheEntry)
+	}
 	d.Unlock()
 }
 
@@ -46,6 +123,7 @@ func (d *texCache) Put(pos uint32, tex []uint8) {
 
 func (cache *texCache) Update(polys []Polygon, e3d *HwEngine3d) {
 	cache.Reset()
+	cache.frame++
 
 	for idx := range polys {
 		poly := &polys[idx]
@@ -62,21 +140,40 @@ func (cache *texCache) Update(polys []Polygon, e3d *HwEngine3d) {
 			continue
 		}
 
-		out := decompFunc(cache, poly, e3d)
-
-		if false {
-			f, err := os.Create(fmt.Sprintf("tex-%x.png", poly.tex.VramTexOffset))
-			if err == nil {
-				png.Encode(f, &Image555{
-					buf: out,
-					w:   int(poly.tex.Width),
-					h:   int(poly.tex.Height),
-				})
-				f.Close()
-			}
+		key := texCacheKey{off, poly.tex.VramPalOffset, poly.tex.Width, poly.tex.Height, poly.tex.Format}
+		deps := e3d.texDeps(&poly.tex)
+		entry := cache.entries[key]
+		if entry == nil || entry.deps != deps {
+			out := decompFunc(cache, poly, e3d)
+			entry = &texCacheEntry{buf: out, deps: deps}
+			cache.entries[key] = entry
 		}
+		if cache.dumpDir != "" && !entry.dumped &&
+			(cache.dumpBudget == nil || cache.dumpBudget.Allow(kTexDumpCost)) {
+			cache.dump(key, entry)
+		}
+		entry.frame = cache.frame
+		cache.Put(off, entry.buf)
+	}
 
-		cache.Put(poly.tex.VramTexOffset, out)
+	for key, entry := range cache.entries {
+		if cache.frame-entry.frame > kTexCacheMaxAge {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+func (cache *texCache) dump(key texCacheKey, entry *texCacheEntry) {
+	entry.dumped = true
+	fn := filepath.Join(cache.dumpDir, fmt.Sprintf("tex-%05x-%05x-%dx%d.png", key.off, key.pal, key.width, key.height))
+	f, err := os.Create(fn)
+	if err != nil {
+		mod3d.Errorf("texture dump: %v", err)
+		return
+	}
+	defer f.Close()
+	if err := png.Encode(f, &Image555{buf: entry.buf, w: int(key.width), h: int(key.height)}); err != nil {
+		mod3d.Errorf("texture dump: %s: %v", fn, err)
 	}
 }
 
@@ -0,0 +1,75 @@
+package raster3d
+
+import (
+	"io/ioutil"
+	"ndsemu/emu"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTexCacheInvalidation(t *testing.T) {
+	e3d := &HwEngine3d{}
+	for i := range e3d.texVram.Slots {
+		e3d.texVram.Slots[i] = make([]byte, 128*1024)
+	}
+	for i := range e3d.palVram.Slots {
+		e3d.palVram.Slots[i] = make([]byte, 16*1024)
+	}
+	polys := []Polygon{{tex: Texture{Format: Tex4x4, Width: 8, Height: 8, PitchShift: 3}}}
+
+	e3d.texCache.Update(polys, e3d)
+	buf := e3d.texCache.Get(0)
+	if buf == nil {
+		t.Fatal("texture not decoded")
+	}
+
+	// Unrelated slots don't invalidate the texture
+	e3d.texVram.Gen[3]++
+	e3d.palVram.Gen[5]++
+	e3d.texCache.Update(polys, e3d)
+	if buf2 := e3d.texCache.Get(0); &buf2[0] != &buf[0] {
+		t.Error("texture decoded again, but VRAM was not modified")
+	}
+
+	// Per-block data is in slot 1
+	e3d.texVram.Gen[1]++
+	e3d.texCache.Update(polys, e3d)
+	if buf2 := e3d.texCache.Get(0); &buf2[0] == &buf[0] {
+		t.Error("texture not decoded again after VRAM was modified")
+	}
+}
+
+func TestTexCacheDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "texdump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e3d := &HwEngine3d{}
+	for i := range e3d.texVram.Slots {
+		e3d.texVram.Slots[i] = make([]byte, 128*1024)
+	}
+	for i := range e3d.palVram.Slots {
+		e3d.palVram.Slots[i] = make([]byte, 16*1024)
+	}
+	polys := []Polygon{{tex: Texture{Format: Tex4x4, Width: 8, Height: 8, PitchShift: 3}}}
+	fn := filepath.Join(dir, "tex-00000-00000-8x8.png")
+
+	// The budget was never started, so it's exhausted: the dump is postponed
+	var budget emu.FrameBudget
+	e3d.DumpTextures(dir, &budget)
+	e3d.texCache.Update(polys, e3d)
+	if _, err := os.Stat(fn); err == nil {
+		t.Error("texture dumped without budget")
+	}
+
+	// The texture is still cached, and it's dumped as soon as there's time
+	budget.Start(time.Hour)
+	e3d.texCache.Update(polys, e3d)
+	if _, err := os.Stat(fn); err != nil {
+		t.Errorf("texture not dumped: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package raster3d
+
+// Convert a RGB555 color into RGBA8 (alpha is set separately)
+func rgb555ToRGBA(dst []byte, c uint16) {
+	r, g, b := uint8(c&0x1F), uint8(c>>5)&0x1F, uint8(c>>10)&0x1F
+	dst[0] = r<<3 | r>>2
+	dst[1] = g<<3 | g>>2
+	dst[2] = b<<3 | b>>2
+}
+
+// decodeTexture decodes a whole texture into a linear RGBA8 buffer. This
+// is used by renderers that can't sample textures directly in the NDS
+// formats. Transparent texels (color 0 with color keying enabled) have
+// alpha set to zero; Tex4x4 textures must have been already decompressed
+// into the texture cache.
+func (e3d *HwEngine3d) decodeTexture(tex *Texture) []byte {
+	w, h := int(tex.Width), int(tex.Height)
+	out := make([]byte, w*h*4)
+
+	var pal VramTexturePalette
+	switch tex.Format {
+	case Tex4, Tex16, Tex256:
+		pal = e3d.palVram.Palette(int(tex.VramPalOffset))
+	}
+	var decomp []byte
+	if tex.Format == Tex4x4 {
+		if decomp = e3d.texCache.Get(tex.VramTexOffset); decomp == nil {
+			return out
+		}
+	}
+
+	off := tex.VramTexOffset
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst := out[(y*w+x)*4:]
+			alpha := uint8(31)
+			var px uint16
+			var idx uint8
+
+			switch tex.Format {
+			case Tex4:
+				idx = (e3d.texVram.Get8(off+uint32(y*w+x)/4) >> (2 * uint(x&3))) & 3
+				px = pal.Lookup(idx)
+			case Tex16:
+				idx = (e3d.texVram.Get8(off+uint32(y*w+x)/2) >> (4 * uint(x&1))) & 0xF
+				px = pal.Lookup(idx)
+			case Tex256:
+				idx = e3d.texVram.Get8(off + uint32(y*w+x))
+				px = pal.Lookup(idx)
+			case TexA3I5:
+				idx = e3d.texVram.Get8(off + uint32(y*w+x))
+				alpha = idx >> 5
+				alpha = alpha<<2 | alpha>>1
+				idx &= 0x1F
+				px = uint16(idx) | uint16(idx)<<5 | uint16(idx)<<10
+			case TexA5I3:
+				idx = e3d.texVram.Get8(off + uint32(y*w+x))
+				alpha = idx >> 3
+				idx = (idx & 7) << 2
+				px = uint16(idx) | uint16(idx)<<5 | uint16(idx)<<10
+			case TexDirect:
+				px = e3d.texVram.Get16(off + uint32(y*w+x)*2)
+				idx = 1
+				if px&0x8000 == 0 {
+					alpha = 0
+				}
+			case Tex4x4:
+				px = uint16(decomp[(y*w+x)*2]) | uint16(decomp[(y*w+x)*2+1])<<8
+				idx = 1
+				if px == 0 {
+					// Tex4x4 is always color-keyed
+					alpha = 0
+				}
+			}
+
+			if idx == 0 && tex.Transparency {
+				alpha = 0
+			}
+			rgb555ToRGBA(dst, px)
+			dst[3] = alpha<<3 | alpha>>2
+		}
+	}
+	return out
+}
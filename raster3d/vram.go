@@ -14,8 +14,13 @@ import (
 // across two different banks (this means that we can't find out
 // which bank a texture lies within, and then get a unsafe.Pointer
 // within that bank).
+//
+// Gen holds a generation counter for each slot, that the memory controller
+// increments whenever the contents of the slot might have changed; this
+// allows to keep decoded textures across frames.
 type VramTextureBank struct {
 	Slots [4][]byte
+	Gen   [4]uint32
 }
 
 func (vt *VramTextureBank) Get8(off uint32) uint8 {
@@ -37,8 +42,11 @@ func (vt *VramTextureBank) Get16(off uint32) uint16 {
 // optimized VramTexturePalette object (that is a wrapper of
 // an unsafe.Pointer), so that the rasterizer can access it
 // without much overhead.
+//
+// Like for VramTextureBank, Gen holds a generation counter for each slot.
 type VramTexturePaletteBank struct {
 	Slots [6][]byte
+	Gen   [6]uint32
 }
 
 func (vt *VramTexturePaletteBank) Palette(off int) VramTexturePalette {
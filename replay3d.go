@@ -0,0 +1,77 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"ndsemu/emu/gfx"
+	"ndsemu/raster3d"
+	"os"
+)
+
+// Replay3D renders a 3D scene recorded with the scene dump key, without
+// running the emulator, and saves the output into a PNG file. Pixels where
+// no polygon was drawn are left transparent. The scene is drawn with the
+// specified 3D renderer and fill rule (see -3drenderer and -3dfillrule), so
+// that the output can be compared against reference images.
+func Replay3D(scenefn string, pngfn string, threads int, rend string, scale int, rule raster3d.FillRule) error {
+	sc, err := raster3d.LoadScene(scenefn)
+	if err != nil {
+		return err
+	}
+
+	e3d := raster3d.NewHwEngine3d()
+	e3d.SetThreads(threads)
+	e3d.SetFillRule(rule)
+	if err := e3d.SetRenderer(rend, scale); err != nil {
+		return err
+	}
+	if err := e3d.LoadScene(sc); err != nil {
+		return err
+	}
+
+	var lm gfx.LayerManager
+	lm.Cfg = gfx.LayerManagerConfig{
+		Width:          256,
+		Height:         192,
+		ScreenBpp:      4,
+		LayerBpp:       4,
+		OverflowPixels: 8,
+		Mixer:          func(pixels []uint32, ctx interface{}) uint32 { return pixels[0] },
+	}
+	lm.AddLayer(gfx.LayerFunc{Func: e3d.Draw3D})
+
+	screen := gfx.NewBufferMem(256, 192)
+	lm.BeginFrame()
+	for y := 0; y < 192; y++ {
+		lm.BeginLine(screen.Line(y))
+		lm.EndLine()
+	}
+	lm.EndFrame()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 256, 192))
+	for y := 0; y < 192; y++ {
+		line := screen.Line(y)
+		for x := 0; x < 256; x++ {
+			pix := line.Get32(x)
+			if pix&0x80000000 == 0 {
+				continue
+			}
+			r, g, b := uint8(pix&0x1F), uint8(pix>>5)&0x1F, uint8(pix>>10)&0x1F
+			off := img.PixOffset(x, y)
+			img.Pix[off+0] = r<<3 | r>>2
+			img.Pix[off+1] = g<<3 | g>>2
+			img.Pix[off+2] = b<<3 | b>>2
+			img.Pix[off+3] = 0xFF
+		}
+	}
+
+	f, err := os.Create(pngfn)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -106,6 +106,9 @@ type HwRtc struct {
 		hour      byte
 		minOrFreq byte
 	}
+
+	// Source of the current date/time (defaults to the host clock)
+	clock func() time.Time
 }
 
 func NewHwRtc() *HwRtc {
@@ -113,10 +116,18 @@ func NewHwRtc() *HwRtc {
 	rtc.regStatus1 = 0x00 // 0x80: reset to defaults
 	rtc.regStatus2 = 0x00
 	rtc.HwSerial3W.dev = rtc
+	rtc.clock = time.Now
 	hwio.MustInitRegs(&rtc.HwSerial3W)
 	return rtc
 }
 
+// SetClock changes the source of the date/time reported by the RTC. This is
+// useful to make emulation reproducible, as the host clock is the only source
+// of non-determinism visible to the emulated software.
+func (rtc *HwRtc) SetClock(clock func() time.Time) {
+	rtc.clock = clock
+}
+
 func (rtc *HwRtc) ResetDefaults() {
 	rtc.regStatus1 = 0x80
 	rtc.regStatus2 = 0x00
@@ -248,7 +259,7 @@ func (rtc *HwRtc) WriteData(val uint8) {
 		rtc.buf = append(rtc.buf, rtc.regStatus2)
 
 	case RtcRegDatetime, RtcRegTime:
-		now := time.Now()
+		now := rtc.clock()
 
 		var hour uint8
 		if rtc.regStatus1&2 != 0 {
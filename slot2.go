@@ -12,6 +12,7 @@ var highz [16]byte = [...]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0
 type HwSlot2 struct {
 	Rom []byte
 	Ram [64 * 1024]byte
+	Irq *HwIrq // IRQ of the CPU that owns the slot (see EXMEMCNT)
 }
 
 func NewHwSlot2() *HwSlot2 {
@@ -85,3 +86,15 @@ func (slot *HwSlot2) HomebrewMapFatFile(fn string) error {
 func (slot *HwSlot2) UnmapCart() {
 	slot.Rom = highz[:]
 }
+
+// Eject simulates the removal of the cartridge from the GBA slot: from now
+// on, the slot reads as open bus, and the slot IRQ is raised on the CPU
+// that owns it. Some games poll for this, or use the IRQ to stop playing
+// when the cartridge they depend on is pulled out.
+func (slot *HwSlot2) Eject() {
+	slot.UnmapCart()
+	Emu.Hw.Mc.mapSlot2()
+	if slot.Irq != nil {
+		slot.Irq.Raise(IrqSlot2)
+	}
+}
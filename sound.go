@@ -60,6 +60,16 @@ type HwSound struct {
 	SndBias    hwio.Reg32 `hwio:"bank=1,offset=0x4,reset=0x200,rwmask=0x3FF"`
 	SndCap0Cnt hwio.Reg8  `hwio:"bank=1,offset=0x8,rwmask=0x8F"`
 	SndCap1Cnt hwio.Reg8  `hwio:"bank=1,offset=0x9,rwmask=0x8F"`
+
+	// Output configuration, for unusual speaker setups or recordings
+	Stereo StereoConfig
+}
+
+// StereoConfig adjusts the stereo image of the mixed output, before it is
+// sent to the host. It doesn't affect the emulated hardware.
+type StereoConfig struct {
+	Swap  bool // swap left and right channels
+	Width int  // stereo separation in percent: 0 is mono, 100 is normal (max 200)
 }
 
 func NewHwSound(bus emu.Bus) *HwSound {
@@ -71,6 +81,7 @@ func NewHwSound(bus emu.Bus) *HwSound {
 	snd := new(HwSound)
 	snd.Bus = bus
 	snd.cache = cache
+	snd.Stereo.Width = 100
 	for i := 0; i < 16; i++ {
 		hwio.MustInitRegs(&snd.Ch[i])
 		snd.Ch[i].snd = snd
@@ -250,9 +261,30 @@ func (snd *HwSound) RunOneFrame(buf []int16) {
 		r = r<<6 | r>>4
 
 		// Convert to signed
-		buf[i] = int16(l - 0x8000)
-		buf[i+1] = int16(r - 0x8000)
+		buf[i], buf[i+1] = snd.Stereo.apply(int16(l-0x8000), int16(r-0x8000))
+	}
+}
+
+func (sc *StereoConfig) apply(l, r int16) (int16, int16) {
+	if sc.Width != 100 {
+		// Scale the side (difference) component, keeping the mid one
+		mid := (int32(l) + int32(r)) / 2
+		side := (int32(l) - int32(r)) / 2 * int32(sc.Width) / 100
+		l, r = clamp16(mid+side), clamp16(mid-side)
+	}
+	if sc.Swap {
+		l, r = r, l
+	}
+	return l, r
+}
+
+func clamp16(s int32) int16 {
+	if s < -0x8000 {
+		return -0x8000
+	} else if s > 0x7FFF {
+		return 0x7FFF
 	}
+	return int16(s)
 }
 
 func mulvol64(s int64, vol int64) int64 {
@@ -0,0 +1,234 @@
+// framebisect finds the first frame where two builds of the emulator diverge
+// while playing the same input script.
+//
+// Despite the name, there is no binary search: each build is run (unthrottled,
+// with -framehash) at most twice.
+//
+//   - The coarse pass runs both builds for -frames frames. Output (video and
+//     audio) hashes are logged on every frame, so the first diverging output
+//     frame is reported directly. The full machine state is only hashed every
+//     -period frames, as it's slow, so this pass only finds the first
+//     checkpoint where the state differs.
+//   - The fine pass runs both builds again up to that checkpoint, hashing the
+//     state on every frame since the previous checkpoint, and reports the
+//     first frame (and the subsystems) where the state diverges.
+//
+// If the state never differs at a checkpoint (eg: only the output diverges,
+// or the state diverges and converges back between two checkpoints), the
+// fine pass is skipped.
+//
+// Usage:
+//
+//	framebisect -a ndsemu.good -b ndsemu.bad -input movie.txt -frames 3600 game.nds
+//
+// Any argument after the game file name (eg: a GBA slot ROM) is passed to
+// both builds after it. Since the emulator stops parsing flags at the game
+// file name, flags can't be passed this way.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	flagA      = flag.String("a", "", "path to first (good) build")
+	flagB      = flag.String("b", "", "path to second (bad) build")
+	flagInput  = flag.String("input", "", "input script to be played back by both builds")
+	flagFrames = flag.Int("frames", 3600, "number of frames to run")
+	flagPeriod = flag.Int("period", 60, "frames between full state checkpoints")
+	flagKeep   = flag.Bool("keep", false, "keep hash logs after exiting")
+)
+
+// Hashes of all subsystems for a frame
+type frameHashes map[string]string
+
+func run(exe string, logfn string, frames int, extra []string) error {
+	args := []string{
+		"-vsync=false",
+		"-frames", strconv.Itoa(frames),
+		"-framehash", logfn,
+		"-framehash-period", strconv.Itoa(*flagPeriod),
+	}
+	if *flagInput != "" {
+		args = append(args, "-playinput", *flagInput)
+	}
+	args = append(args, extra...)
+	args = append(args, flag.Args()...)
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %v", exe, err)
+	}
+	return nil
+}
+
+// Run both builds in parallel, and return their hash logs
+func runBoth(dir string, pass string, frames int, extra ...string) ([]frameHashes, []frameHashes, error) {
+	fna := filepath.Join(dir, pass+"-a.txt")
+	fnb := filepath.Join(dir, pass+"-b.txt")
+
+	fmt.Printf("%s: running both builds for %d frames...\n", pass, frames)
+	erra := make(chan error)
+	go func() { erra <- run(*flagA, fna, frames, extra) }()
+	errb := run(*flagB, fnb, frames, extra)
+	if err := <-erra; err != nil {
+		return nil, nil, err
+	}
+	if errb != nil {
+		return nil, nil, errb
+	}
+
+	a, err := loadLog(fna)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := loadLog(fnb)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+func loadLog(fn string) ([]frameHashes, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var frames []frameHashes
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		fields := strings.Fields(scan.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		nf, err := strconv.Atoi(fields[0])
+		if err != nil || nf != len(frames) {
+			return nil, fmt.Errorf("%s: invalid frame number: %q", fn, fields[0])
+		}
+		h := make(frameHashes)
+		for _, kv := range fields[1:] {
+			if idx := strings.IndexByte(kv, '='); idx > 0 {
+				h[kv[:idx]] = kv[idx+1:]
+			}
+		}
+		frames = append(frames, h)
+	}
+	return frames, scan.Err()
+}
+
+// Compare two logs, and return the first frame in which at least one of the
+// specified subsystems (or any, if none is specified) diverges, with the
+// list of diverging subsystems. It returns -1 if no divergence was found.
+func compare(a, b []frameHashes, subsys ...string) (int, []string) {
+	for nf := 0; nf < len(a) && nf < len(b); nf++ {
+		var diff []string
+		for k, ha := range a[nf] {
+			if len(subsys) > 0 && !contains(subsys, k) {
+				continue
+			}
+			if hb, found := b[nf][k]; found && ha != hb {
+				diff = append(diff, k)
+			}
+		}
+		if len(diff) > 0 {
+			sort.Strings(diff)
+			return nf, diff
+		}
+	}
+	return -1, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+var stateSubsys = []string{"cpu9", "cpu7", "ram", "wram", "vram"}
+
+func main() {
+	flag.Parse()
+	if *flagA == "" || *flagB == "" || len(flag.Args()) < 1 {
+		fmt.Fprintln(os.Stderr, "usage: framebisect -a BUILD -b BUILD [options] GAME [ARGS...]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	dir, err := ioutil.TempDir("", "framebisect")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *flagKeep {
+		fmt.Println("hash logs:", dir)
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	if err := bisect(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func bisect(dir string) error {
+	a, b, err := runBoth(dir, "coarse", *flagFrames)
+	if err != nil {
+		return err
+	}
+
+	fout, dout := compare(a, b, "video", "audio")
+	fstate, dstate := compare(a, b, stateSubsys...)
+	if fout < 0 && fstate < 0 {
+		fmt.Printf("no divergence found in %d frames\n", len(a))
+		return nil
+	}
+	if fout >= 0 {
+		fmt.Printf("output diverges at frame %d: %s\n", fout, strings.Join(dout, ", "))
+	}
+	if fstate < 0 {
+		fmt.Println("state never diverges at checkpoints")
+		return nil
+	}
+	fmt.Printf("state diverges at checkpoint frame %d: %s\n", fstate, strings.Join(dstate, ", "))
+
+	// Narrow down: state was still identical at the previous checkpoint,
+	// so rerun up to the diverging checkpoint, hashing every frame since
+	// the previous one.
+	first := fstate - *flagPeriod + 1
+	if first < 0 {
+		first = 0
+	}
+	if first < fstate {
+		win := fmt.Sprintf("%d:%d", first, fstate)
+		a, b, err = runBoth(dir, "fine", fstate+1, "-framehash-window", win)
+		if err != nil {
+			return err
+		}
+		fstate, dstate = compare(a, b, stateSubsys...)
+		if fstate < 0 {
+			// This means that emulation is not deterministic, and the
+			// second run diverged differently (or not at all)
+			return fmt.Errorf("divergence not reproducible in fine pass")
+		}
+	}
+
+	fmt.Printf("first divergence at frame %d: %s\n", fstate, strings.Join(dstate, ", "))
+	return nil
+}
@@ -0,0 +1,126 @@
+// verify-bios checks NDS BIOS and firmware dumps, reporting bad or
+// incomplete dumps before they're used with the emulator.
+//
+// Dumps can be specified either as a directory in split layout (containing
+// biosnds9.rom, biosnds7.rom and firmware.bin, like the emulator's "bios"
+// directory), or as a single combined dump (ARM9 BIOS, ARM7 BIOS and
+// firmware concatenated). A combined dump can be converted to split layout
+// with -split.
+//
+// Usage:
+//
+//	verify-bios bios/
+//	verify-bios -split bios/ combined.bin
+//
+// The exit status is 1 if any problem was found, and 2 if the dumps are
+// unusable.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"ndsemu/biosdump"
+	"os"
+	"path/filepath"
+)
+
+var (
+	flagSplit = flag.String("split", "", "write the images of a combined dump into the specified directory, in split layout")
+)
+
+type image struct {
+	fn    string
+	data  []byte
+	check func([]byte) biosdump.Report
+}
+
+func images(bios9, bios7, fw []byte) []image {
+	return []image{
+		{"biosnds9.rom", bios9, biosdump.CheckBios9},
+		{"biosnds7.rom", bios7, biosdump.CheckBios7},
+		{"firmware.bin", fw, biosdump.CheckFirmware},
+	}
+}
+
+func load(path string) ([]image, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		bios9, bios7, fw, err := biosdump.SplitCombined(data)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("%s: combined dump\n", path)
+		return images(bios9, bios7, fw), nil
+	}
+
+	if *flagSplit != "" {
+		return nil, fmt.Errorf("%s: -split requires a combined dump", path)
+	}
+	imgs := images(nil, nil, nil)
+	for i := range imgs {
+		if imgs[i].data, err = ioutil.ReadFile(filepath.Join(path, imgs[i].fn)); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Printf("%s: split dump\n", path)
+	return imgs, nil
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: verify-bios [-split DIR] <bios directory | combined dump>")
+		os.Exit(2)
+	}
+
+	imgs, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var rep biosdump.Report
+	for _, img := range imgs {
+		r := img.check(img.data)
+		if len(r) == 0 {
+			fmt.Printf("  %-14s OK\n", img.fn)
+		}
+		for _, p := range r {
+			fmt.Printf("  %-14s %v\n", img.fn, p.Msg)
+		}
+		rep = append(rep, r...)
+	}
+
+	if rep.Fatal() {
+		fmt.Println("dumps are unusable, please dump them again")
+		os.Exit(2)
+	}
+
+	if *flagSplit != "" {
+		if err := os.MkdirAll(*flagSplit, 0777); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		for _, img := range imgs {
+			fn := filepath.Join(*flagSplit, img.fn)
+			if err := ioutil.WriteFile(fn, img.data, 0666); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			fmt.Println("written", fn)
+		}
+	}
+
+	if len(rep) > 0 {
+		os.Exit(1)
+	}
+}
@@ -9,9 +9,28 @@ import (
 
 var modTsc = log.NewModule("tsc")
 
+// TouchFilter configures the filtering of the host pen input, which is
+// sampled once per frame. Taps that are shorter than a couple of frames, or
+// short lifts of the pen during a drag, can be misread by games; the filter
+// can extend short presses, ignore short releases, and smooth the position.
+// The zero value disables all filtering.
+type TouchFilter struct {
+	MinPress int // minimum length of a press, in frames
+	Release  int // frames the pen must stay up before the release is reported
+	Average  int // number of frames of the moving average of the position (max 8)
+}
+
 type HwTouchScreen struct {
+	Filter TouchFilter
+
 	penX, penY int
 	penDown    bool
+	pressed    int // frames since the (filtered) pen was pressed
+	released   int // frames since the pen was lifted, while still reported down
+
+	// Last raw positions, for the moving average
+	hist  [8][2]int
+	nhist int
 }
 
 func NewHwTouchScreen() *HwTouchScreen {
@@ -23,10 +42,103 @@ var tscChanNames = [8]string{
 	"touch_z2", "touch_x", "aux", "temp1",
 }
 
+// SetPen sets the state of the pen for the next frame, applying the filter.
 func (ff *HwTouchScreen) SetPen(down bool, x, y int) {
-	ff.penX = x
-	ff.penY = y
-	ff.penDown = down
+	f := &ff.Filter
+	if !down {
+		if !ff.penDown {
+			return
+		}
+		// Keep the pen down (in its last position) until the press is
+		// long enough, and the pen has been up for long enough
+		ff.released++
+		ff.pressed++
+		if ff.pressed >= f.MinPress && ff.released >= f.Release {
+			ff.penDown = false
+		}
+		return
+	}
+
+	// New press: restart the moving average
+	if !ff.penDown {
+		ff.pressed = 0
+		ff.nhist = 0
+	}
+	ff.penDown = true
+	ff.pressed++
+	ff.released = 0
+
+	n := f.Average
+	if n > len(ff.hist) {
+		n = len(ff.hist)
+	}
+	if n <= 1 {
+		ff.penX, ff.penY = x, y
+		return
+	}
+	copy(ff.hist[1:], ff.hist[:len(ff.hist)-1])
+	ff.hist[0] = [2]int{x, y}
+	if ff.nhist < n {
+		ff.nhist++
+	}
+	sx, sy := 0, 0
+	for _, p := range ff.hist[:ff.nhist] {
+		sx += p[0]
+		sy += p[1]
+	}
+	ff.penX, ff.penY = sx/ff.nhist, sy/ff.nhist
+}
+
+// PenDown returns whether the pen is down, after filtering.
+func (ff *HwTouchScreen) PenDown() bool {
+	return ff.penDown
+}
+
+// FIXME: this is surely wrong. It is reading the calibration values from the
+// RAM (where the firmware stores them) and performing a reverse mapping to
+// report the ADC values that correspond to the mouse position.
+// Surely the hardware is not aware of the calibration process and always
+// returns values in a certain fixed range (that might vary across different
+// units, but anyway).
+func (ff *HwTouchScreen) adcX() uint16 {
+	adcX1 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x58:])
+	scrX1 := Emu.Mem.Ram[0x3FFC80+0x5C]
+	adcX2 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x5E:])
+	scrX2 := Emu.Mem.Ram[0x3FFC80+0x62]
+	return uint16((ff.penX-int(scrX1)+1)*int(adcX2-adcX1)/int(scrX2-scrX1) + int(adcX1))
+}
+
+func (ff *HwTouchScreen) adcY() uint16 {
+	adcY1 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x5A:])
+	scrY1 := Emu.Mem.Ram[0x3FFC80+0x5D]
+	adcY2 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x60:])
+	scrY2 := Emu.Mem.Ram[0x3FFC80+0x63]
+	return uint16((ff.penY-int(scrY1)+1)*int(adcY2-adcY1)/int(scrY2-scrY1) + int(adcY1))
+}
+
+// Touch resistance reported by the pressure readings, relative to the
+// resistance of the X plate (scaled by 4096). Lower values mean a harder
+// press; the host pen has no pressure, so a medium press is reported.
+const cTscTouchRes = 1024
+
+// Return the pressure readings (Z1, Z2). Software computes the touch
+// resistance as Rx * X/4096 * (Z2/Z1 - 1), so Z2 is derived from the X
+// reading to report a constant resistance. When the pen is up, the plates
+// don't touch: Z1 reads 0 and Z2 full scale.
+func (ff *HwTouchScreen) pressure() (z1, z2 uint16) {
+	if !ff.penDown {
+		return 0, 0xFFF
+	}
+	x := int(ff.adcX())
+	if x < 1 {
+		x = 1
+	}
+	const zz1 = 0x400
+	zz2 := zz1 + zz1*cTscTouchRes/x
+	if zz2 > 0xFFF {
+		zz2 = 0xFFF
+	}
+	return zz1, uint16(zz2)
 }
 
 func (ff *HwTouchScreen) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
@@ -55,32 +167,17 @@ func (ff *HwTouchScreen) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 		output = 0x800
 	case 1: // Y coord
 		if ff.penDown {
-			// FIXME: this is surely wrong. It is reading the calibration
-			// values from the RAM (where the firmware stores them) and
-			// performing a reverse mapping to report the ADC values that
-			// correspond to the mouse position.
-			// Surely the hardware is not aware of the calibration process
-			// and always returns values in a certain fixed range (that might
-			// vary across different units, but anyway).
-			adcY1 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x5A:])
-			scrY1 := Emu.Mem.Ram[0x3FFC80+0x5D]
-			adcY2 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x60:])
-			scrY2 := Emu.Mem.Ram[0x3FFC80+0x63]
-			output = uint16((ff.penY-int(scrY1)+1)*int(adcY2-adcY1)/int(scrY2-scrY1) + int(adcY1))
-
-			// log.Infof("coord Y:%d -> Out:%x", ff.penY, output)
+			output = ff.adcY()
 		} else {
 			output = 0xFFF
 		}
+	case 3: // pressure (Z1)
+		output, _ = ff.pressure()
+	case 4: // pressure (Z2)
+		_, output = ff.pressure()
 	case 5: // X coord
 		if ff.penDown {
-			adcX1 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x58:])
-			scrX1 := Emu.Mem.Ram[0x3FFC80+0x5C]
-			adcX2 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x5E:])
-			scrX2 := Emu.Mem.Ram[0x3FFC80+0x62]
-			output = uint16((ff.penX-int(scrX1)+1)*int(adcX2-adcX1)/int(scrX2-scrX1) + int(adcX1))
-
-			// log.Infof("coord :%d -> Out:%x", ff.penC, output)
+			output = ff.adcX()
 		} else {
 			output = 0x0
 		}
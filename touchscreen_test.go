@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestTouchFilter(t *testing.T) {
+	type frame struct {
+		down     bool
+		x, y     int
+		wantDown bool
+		wantX    int
+	}
+	tests := []struct {
+		name   string
+		filter TouchFilter
+		frames []frame
+	}{
+		{"none", TouchFilter{}, []frame{
+			{true, 10, 10, true, 10},
+			{false, 0, 0, false, 10},
+			{true, 20, 10, true, 20},
+		}},
+		{"minpress", TouchFilter{MinPress: 3}, []frame{
+			{true, 10, 10, true, 10},
+			{false, 0, 0, true, 10},
+			{false, 0, 0, false, 10},
+		}},
+		{"release", TouchFilter{Release: 2}, []frame{
+			{true, 10, 10, true, 10},
+			{false, 0, 0, true, 10},
+			{true, 30, 10, true, 30},
+			{false, 0, 0, true, 30},
+			{false, 0, 0, false, 30},
+		}},
+		{"average", TouchFilter{Average: 2}, []frame{
+			{true, 10, 10, true, 10},
+			{true, 20, 10, true, 15},
+			{true, 40, 10, true, 30},
+			{false, 0, 0, false, 30},
+			{true, 100, 10, true, 100},
+		}},
+	}
+
+	for _, tt := range tests {
+		ts := NewHwTouchScreen()
+		ts.Filter = tt.filter
+		for i, f := range tt.frames {
+			ts.SetPen(f.down, f.x, f.y)
+			if ts.PenDown() != f.wantDown || ts.penX != f.wantX {
+				t.Errorf("%s: frame %d: got down=%v x=%d, want down=%v x=%d",
+					tt.name, i, ts.PenDown(), ts.penX, f.wantDown, f.wantX)
+			}
+		}
+	}
+}
+
+func TestTouchPressure(t *testing.T) {
+	// Calibration points in the firmware user settings
+	Emu = &NDSEmulator{Mem: new(NDSMemory)}
+	cal := Emu.Mem.Ram[0x3FFC80+0x58:]
+	binary.LittleEndian.PutUint16(cal[0:], 0x200) // X1
+	binary.LittleEndian.PutUint16(cal[2:], 0x200) // Y1
+	cal[4], cal[5] = 0x20, 0x20                   // screen X1,Y1
+	binary.LittleEndian.PutUint16(cal[6:], 0xE00) // X2
+	binary.LittleEndian.PutUint16(cal[8:], 0xE00) // Y2
+	cal[10], cal[11] = 0xE0, 0xA0                 // screen X2,Y2
+
+	read := func(ts *HwTouchScreen, ch byte) int {
+		out, _ := ts.SpiTransfer([]byte{0x80 | ch<<4})
+		return int(out[0])<<5 | int(out[1])>>3
+	}
+
+	ts := NewHwTouchScreen()
+	if z1, z2 := read(ts, 3), read(ts, 4); z1 != 0 || z2 != 0xFFF {
+		t.Errorf("pen up: z1=%x z2=%x", z1, z2)
+	}
+
+	// The touch resistance computed from the readings doesn't depend on
+	// the position
+	for _, x := range []int{40, 128, 250} {
+		ts.SetPen(true, x, 96)
+		adcx, z1, z2 := read(ts, 5), read(ts, 3), read(ts, 4)
+		if z1 == 0 {
+			t.Fatalf("x=%d: z1=0", x)
+		}
+		if res := adcx * (z2 - z1) / z1; res < cTscTouchRes-8 || res > cTscTouchRes+8 {
+			t.Errorf("x=%d: touch resistance %d (adc x=%x z1=%x z2=%x)", x, res, adcx, z1, z2)
+		}
+	}
+}
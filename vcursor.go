@@ -0,0 +1,101 @@
+package main
+
+import (
+	"ndsemu/emu/gfx"
+	"ndsemu/emu/hw"
+)
+
+const (
+	// Stick values within this range are considered noise and ignored
+	cVCursorDeadZone = 8000
+
+	// Vertical offset of the bottom screen in the output window
+	cBottomScreenY = 192 + 90
+
+	cVCursorMinSpeed = 0.5
+	cVCursorMaxSpeed = 16
+)
+
+// VirtualCursor emulates the touchscreen with a game controller: the left
+// analog stick moves a cursor over the bottom screen, and the A button (or
+// a press of the stick) touches the screen at the cursor position. The
+// shoulder buttons decrease/increase the cursor speed.
+type VirtualCursor struct {
+	pad   *hw.Pad
+	x, y  float64
+	speed float64 // pixels per frame at full stick deflection
+	down  bool
+
+	prevL, prevR bool
+}
+
+func NewVirtualCursor(pad *hw.Pad, speed float64) *VirtualCursor {
+	return &VirtualCursor{
+		pad:   pad,
+		x:     256 / 2,
+		y:     192 / 2,
+		speed: speed,
+	}
+}
+
+func vcursorAxis(v int) float64 {
+	if v > -cVCursorDeadZone && v < cVCursorDeadZone {
+		return 0
+	}
+	return float64(v) / 32768
+}
+
+// Update polls the controller and moves the cursor. It must be called once
+// per frame; it returns the pen status, in bottom screen coordinates.
+func (vc *VirtualCursor) Update() (down bool, x, y int) {
+	l := vc.pad.Button(hw.PadButtonLeftShoulder)
+	r := vc.pad.Button(hw.PadButtonRightShoulder)
+	if l && !vc.prevL && vc.speed/2 >= cVCursorMinSpeed {
+		vc.speed /= 2
+	}
+	if r && !vc.prevR && vc.speed*2 <= cVCursorMaxSpeed {
+		vc.speed *= 2
+	}
+	vc.prevL, vc.prevR = l, r
+
+	vc.x += vcursorAxis(vc.pad.Axis(hw.PadAxisLeftX)) * vc.speed
+	vc.y += vcursorAxis(vc.pad.Axis(hw.PadAxisLeftY)) * vc.speed
+	if vc.x < 0 {
+		vc.x = 0
+	} else if vc.x > 255 {
+		vc.x = 255
+	}
+	if vc.y < 0 {
+		vc.y = 0
+	} else if vc.y > 191 {
+		vc.y = 191
+	}
+
+	vc.down = vc.pad.Button(hw.PadButtonA) || vc.pad.Button(hw.PadButtonLeftStick)
+	return vc.down, int(vc.x), int(vc.y)
+}
+
+// Draw renders the cursor as a small cross on the bottom screen. The cross
+// is drawn by inverting the pixels, so that it is visible on any background;
+// it turns red while the screen is being touched.
+func (vc *VirtualCursor) Draw(screen gfx.Buffer) {
+	cx, cy := int(vc.x), int(vc.y)
+	for i := -3; i <= 3; i++ {
+		vc.plot(screen, cx+i, cy)
+		if i != 0 {
+			vc.plot(screen, cx, cy+i)
+		}
+	}
+}
+
+func (vc *VirtualCursor) plot(screen gfx.Buffer, x, y int) {
+	if x < 0 || x >= 256 || y < 0 || y >= 192 {
+		return
+	}
+	line := screen.Line(cBottomScreenY + y)
+	if vc.down {
+		line.Set32(x, 0xFF0000FF)
+	} else {
+		line.Set32(x, line.Get32(x)^0x00FFFFFF)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	log "ndsemu/emu/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Build information. These are meant to be set at build time, so that bug
+// reports can identify the exact build being run:
+//
+//	go build -ldflags "-X main.Version=v0.3 -X main.Commit=$(git rev-parse --short HEAD) -X main.BuildDate=$(date -u +%Y-%m-%d)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+const cReleasesUrl = "https://api.github.com/repos/knut0815/ndsemu/releases/latest"
+
+// VersionString returns a one-line description of the current build
+func VersionString() string {
+	s := "ndsemu " + Version
+	var extra []string
+	if Commit != "" {
+		extra = append(extra, "commit "+Commit)
+	}
+	if BuildDate != "" {
+		extra = append(extra, "built "+BuildDate)
+	}
+	if len(extra) > 0 {
+		s += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return s
+}
+
+// Compare two version strings like "v1.2.3" (the "v" is optional), returning
+// -1, 0 or +1. Missing or non-numeric components compare as zero.
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na < nb {
+			return -1
+		}
+		if na > nb {
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckUpdate queries GitHub for the latest release, and logs a notice if
+// it's newer than the running build. It's meant to be run in background, and
+// never fails: errors are just logged.
+func CheckUpdate() {
+	if Version == "dev" {
+		log.ModEmu.Infof("update check skipped: development build")
+		return
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(cReleasesUrl)
+	if err != nil {
+		log.ModEmu.Warnf("update check failed: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.ModEmu.Warnf("update check failed: %s", resp.Status)
+		return
+	}
+
+	var rel struct {
+		TagName string `json:"tag_name"`
+		HtmlUrl string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		log.ModEmu.Warnf("update check failed: %v", err)
+		return
+	}
+
+	if compareVersions(rel.TagName, Version) > 0 {
+		log.ModEmu.Infof("a new version is available: %s (running %s): %s", rel.TagName, Version, rel.HtmlUrl)
+	} else {
+		log.ModEmu.Infof("no updates available (latest release: %s)", rel.TagName)
+	}
+}
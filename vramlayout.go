@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// A VramRegion is an area of a VRAM address space (or a texture/extended
+// palette slot), and the bank that currently backs it.
+type VramRegion struct {
+	Space  string `json:"space"`  // address space or slot kind
+	Start  uint32 `json:"start"`  // first address (slot number, for slots)
+	End    uint32 `json:"end"`    // last address (same as Start, for slots)
+	Bank   string `json:"bank"`   // bank letter, or empty if unmapped
+	Offset int    `json:"offset"` // offset within the bank of the first byte
+}
+
+func (r VramRegion) String() string {
+	bank := "-"
+	if r.Bank != "" {
+		bank = fmt.Sprintf("%s+%05x", r.Bank, r.Offset)
+	}
+	if r.Start == r.End {
+		return fmt.Sprintf("%-12s slot %d             %s", r.Space, r.Start, bank)
+	}
+	return fmt.Sprintf("%-12s %08x-%08x  %s", r.Space, r.Start, r.End, bank)
+}
+
+// Return the bank (and the offset within it) that ptr points into; the bank
+// is empty if ptr doesn't point into VRAM (eg: unmapped areas, which are
+// backed by a zero-filled buffer).
+func (mc *HwMemoryController) vramBankOf(ptr []byte) (string, int) {
+	if len(ptr) == 0 {
+		return "", 0
+	}
+	p := uintptr(unsafe.Pointer(&ptr[0]))
+	for i, b := range mc.vram {
+		base := uintptr(unsafe.Pointer(&b[0]))
+		if p >= base && p < base+uintptr(len(b)) {
+			return string(rune('A' + i)), int(p - base)
+		}
+	}
+	return "", 0
+}
+
+// VramLayout describes which physical bank (and offset) backs each 16K
+// region of the VRAM address spaces of both CPUs, as well as the texture
+// and extended palette slots, as currently configured through VRAMCNT.
+// Consecutive regions backed by contiguous areas of the same bank are
+// merged together.
+func (mc *HwMemoryController) VramLayout() []VramRegion {
+	var res []VramRegion
+	add := func(r VramRegion) {
+		if n := len(res); n > 0 {
+			last := &res[n-1]
+			if last.Space == r.Space && last.End+1 == r.Start && last.Bank == r.Bank &&
+				(r.Bank == "" || last.Offset+int(r.Start-last.Start) == r.Offset) {
+				last.End = r.End
+				return
+			}
+		}
+		res = append(res, r)
+	}
+
+	spaces := []struct {
+		name       string
+		cpu7       bool
+		start, end uint32
+	}{
+		{"arm9 bg-a", false, 0x06000000, 0x0607FFFF},
+		{"arm9 bg-b", false, 0x06200000, 0x0621FFFF},
+		{"arm9 obj-a", false, 0x06400000, 0x0643FFFF},
+		{"arm9 obj-b", false, 0x06600000, 0x0661FFFF},
+		{"arm9 lcdc", false, 0x06800000, 0x068A3FFF},
+		{"arm7", true, 0x06000000, 0x0603FFFF},
+	}
+	for _, sp := range spaces {
+		for addr := sp.start; addr < sp.end; addr += 16 * 1024 {
+			var ptr []byte
+			if sp.cpu7 {
+				ptr = mc.Nds7.Bus.FetchPointer(addr)
+			} else {
+				ptr = mc.Nds9.Bus.FetchPointer(addr)
+			}
+			bank, off := mc.vramBankOf(ptr)
+			add(VramRegion{sp.name, addr, addr + 16*1024 - 1, bank, off})
+		}
+	}
+
+	slot := func(space string, idx int, ptr []byte) {
+		bank, off := mc.vramBankOf(ptr)
+		res = append(res, VramRegion{space, uint32(idx), uint32(idx), bank, off})
+	}
+	for i, ptr := range mc.Texture {
+		slot("texture", i, ptr)
+	}
+	for i, ptr := range mc.TexturePalette {
+		slot("texture-pal", i, ptr)
+	}
+	for e, name := range []string{"bgextpal-a", "bgextpal-b"} {
+		for i, ptr := range mc.BgExtPalette[e] {
+			slot(name, i, ptr)
+		}
+	}
+	for e, name := range []string{"objextpal-a", "objextpal-b"} {
+		slot(name, 0, mc.ObjExtPalette[e])
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestVramBankOf(t *testing.T) {
+	mc := newTestMemCtrl()
+	tests := []struct {
+		ptr  []byte
+		bank string
+		ofs  int
+	}{
+		{mc.vram[0], "A", 0},
+		{mc.vram[1][0x1234:], "B", 0x1234},
+		{mc.vram[8][16*1024-1:], "I", 16*1024 - 1},
+		{mc.zero[:], "", 0},
+		{nil, "", 0},
+	}
+	for i, tt := range tests {
+		if bank, ofs := mc.vramBankOf(tt.ptr); bank != tt.bank || ofs != tt.ofs {
+			t.Errorf("%d: got %q+%x, want %q+%x", i, bank, ofs, tt.bank, tt.ofs)
+		}
+	}
+}
+
+func TestVramLayout(t *testing.T) {
+	mc := newTestMemCtrl()
+	mc.WriteVRAMCNTA(0, 0x80|1)      // BG-A, offset 0
+	mc.WriteVRAMCNTB(0, 0x80|1<<3|1) // BG-A, offset 0x20000
+	mc.WriteVRAMCNTC(0, 0x80|4)      // BG-B
+	mc.WriteVRAMCNTD(0, 0x80|1<<3|3) // texture slot 1
+	mc.WriteVRAMCNTE(0, 0x80|4)      // BG-A extended palettes
+	mc.WriteVRAMCNTH(0, 0x80|0)      // LCDC
+	mc.WriteVRAMCNTI(0, 0x80|0)      // LCDC, right after H
+
+	// The 16K regions backed by contiguous areas of the same bank are
+	// merged together, but not across banks.
+	want := []VramRegion{
+		{"arm9 bg-a", 0x06000000, 0x0601FFFF, "A", 0},
+		{"arm9 bg-a", 0x06020000, 0x0603FFFF, "B", 0},
+		{"arm9 bg-a", 0x06040000, 0x0607FFFF, "", 0},
+		{"arm9 bg-b", 0x06200000, 0x0621FFFF, "C", 0},
+		{"arm9 obj-a", 0x06400000, 0x0643FFFF, "", 0},
+		{"arm9 obj-b", 0x06600000, 0x0661FFFF, "", 0},
+		{"arm9 lcdc", 0x06800000, 0x06897FFF, "", 0},
+		{"arm9 lcdc", 0x06898000, 0x0689FFFF, "H", 0},
+		{"arm9 lcdc", 0x068A0000, 0x068A3FFF, "I", 0},
+		{"arm7", 0x06000000, 0x0603FFFF, "", 0},
+	}
+	got := mc.VramLayout()
+	if len(got) < len(want) {
+		t.Fatalf("got %d regions, want at least %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("region %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// Slots are never merged
+	slots := map[VramRegion]bool{
+		{"texture", 0, 0, "", 0}:          true,
+		{"texture", 1, 1, "D", 0}:         true,
+		{"bgextpal-a", 0, 0, "E", 0}:      true,
+		{"bgextpal-a", 1, 1, "E", 0x2000}: true,
+		{"bgextpal-a", 3, 3, "E", 0x6000}: true,
+		{"objextpal-a", 0, 0, "", 0}:      true,
+	}
+	for _, r := range got[len(want):] {
+		delete(slots, r)
+	}
+	for r := range slots {
+		t.Errorf("missing slot: %v", r)
+	}
+}
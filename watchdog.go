@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"ndsemu/arm"
+	log "ndsemu/emu/logger"
+)
+
+// Watchdog detects when the emulated system is wedged: both CPUs are halted,
+// and no interrupt that could wake them up can ever be raised. This
+// usually happens because of an emulation bug (eg: an interrupt that is
+// never generated, or a wrong IPC handshake), and would otherwise show up
+// as a silent hang, with a frozen screen.
+//
+// The watchdog is checked once per frame; the condition must last for
+// Frames consecutive frames before it is reported. It is reported only once
+// (until the system recovers).
+type Watchdog struct {
+	Frames int  // number of frames the condition must last
+	Break  bool // break into the debugger when triggered
+
+	count    int
+	reported bool
+}
+
+// A source of interrupts that a halted CPU could be waiting for
+type wdCpu struct {
+	name  string
+	cpu   *arm.Cpu
+	irq   *HwIrq
+	lcd   *HwLcd
+	tms   *HwTimers
+	dma   [4]*HwDmaChannel
+	other CpuNum
+}
+
+func (emu *NDSEmulator) watchdogCpus() [2]wdCpu {
+	return [2]wdCpu{
+		{"arm9", nds9.Cpu, nds9.Irq, emu.Hw.Lcd9, nds9.Timers, nds9.Dma, CpuNds7},
+		{"arm7", nds7.Cpu, nds7.Irq, emu.Hw.Lcd7, nds7.Timers, nds7.Dma, CpuNds9},
+	}
+}
+
+// Return the mask of enabled interrupts that could still be raised for this
+// CPU, assuming that the other CPU is halted (so that no IPC interrupt can
+// arrive). IRQs that depend on events we can't easily predict (eg: RTC,
+// gamecard, geometry FIFO) are conservatively assumed to be possible.
+func (c *wdCpu) wakeSources() uint32 {
+	// HALT is released by enabled interrupts even if IME is clear
+	mask := c.irq.Ie.Value &^ uint32(IrqIpcSync|IrqIpcSendFifo|IrqIpcRecvFifo)
+	stat := c.lcd.DispStat.Value
+	if stat&cVBlankIrq == 0 {
+		mask &^= uint32(IrqVBlank)
+	}
+	if stat&cHBlankIrq == 0 {
+		mask &^= uint32(IrqHBlank)
+	}
+	if stat&cVMatchIrq == 0 {
+		mask &^= uint32(IrqVMatch)
+	}
+	for i := range c.tms.Timers {
+		if t := &c.tms.Timers[i]; !t.running() || !t.irq() {
+			mask &^= uint32(IrqTimer0) << uint(i)
+		}
+	}
+	for i, dma := range c.dma {
+		if !dma.enabled() || dma.DmaCntrl.Value&(1<<14) == 0 {
+			mask &^= uint32(IrqDma0) << uint(i)
+		}
+	}
+	return mask
+}
+
+func (c *wdCpu) wedged() bool {
+	return c.cpu.Halted() && c.wakeSources() == 0
+}
+
+// Check must be called at the end of each frame.
+func (wd *Watchdog) Check(emu *NDSEmulator) {
+	cpus := emu.watchdogCpus()
+	if !cpus[0].wedged() || !cpus[1].wedged() {
+		wd.count = 0
+		wd.reported = false
+		return
+	}
+
+	wd.count++
+	if wd.count < wd.Frames || wd.reported {
+		return
+	}
+	wd.reported = true
+
+	fields := log.Fields{"frame": emu.framecount}
+	for _, c := range cpus {
+		fields[c.name+"_pc"] = fmt.Sprintf("%08x", uint32(c.cpu.GetPC()))
+		fields[c.name+"_ime"] = c.irq.Ime.Value
+		fields[c.name+"_ie"] = fmt.Sprintf("%08x", c.irq.Ie.Value)
+		fields[c.name+"_if"] = fmt.Sprintf("%08x", c.irq.If.Value)
+	}
+	ipc := emu.Hw.Ipc
+	fields["ipcsync9"] = fmt.Sprintf("%04x", ipc.Ipc9Sync.Value)
+	fields["ipcsync7"] = fmt.Sprintf("%04x", ipc.Ipc7Sync.Value)
+	for i, ev := range ipc.lastTraffic() {
+		fields[fmt.Sprintf("ipc%d", i)] = ev.String()
+	}
+
+	msg := fmt.Sprintf("system wedged: both CPUs halted with no wakeup source for %d frames", wd.count)
+	log.ModEmu.WithFields(fields).Error(msg)
+	if wd.Break {
+		emu.DebugBreak(msg)
+	}
+}